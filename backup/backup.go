@@ -0,0 +1,424 @@
+// Package backup dumps every application table to a compressed archive and
+// loads it back, for self-hosters without DBA tooling.
+//
+// An archive is a gzipped tar file. Its first entry, manifest.json, says
+// when the backup was taken, at which schema version, and what each table's
+// columns are. An entry per table, in PostgreSQL's COPY text format,
+// follows, with tables before those whose foreign keys reference them.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FormatVersion is the version of the archive layout, bumped whenever
+// older servers couldn't restore newer archives.
+const FormatVersion = 1
+
+const manifestName = "manifest.json"
+
+// ErrIncompatible is returned when a backup's tables don't match the
+// database it is restored to.
+var ErrIncompatible = errors.New("backup doesn't match the database schema")
+
+// Manifest describes a backup.
+type Manifest struct {
+	FormatVersion int `json:"format_version"`
+	// SchemaVersion is the latest migration applied, read from goose's
+	// goose_db_version table, or 0 where there is none.
+	SchemaVersion int64 `json:"schema_version"`
+	// TakenAt is the moment of the snapshot all tables were read from.
+	TakenAt       time.Time `json:"taken_at"`
+	ServerVersion string    `json:"server_version"`
+	Tables        []Table   `json:"tables"`
+}
+
+// Rows is the number of rows across all tables.
+func (m Manifest) Rows() int64 {
+	var n int64
+	for _, t := range m.Tables {
+		n += t.Rows
+	}
+	return n
+}
+
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+	Rows    int64    `json:"rows"`
+}
+
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (t Table) copySQL(direction string) string {
+	cols := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		cols[i] = pgx.Identifier{c.Name}.Sanitize()
+	}
+	return fmt.Sprintf("COPY %s (%s) %s", pgx.Identifier{t.Name}.Sanitize(), strings.Join(cols, ", "), direction)
+}
+
+// Backup writes an archive of every table in conn's current schema to w.
+// The tables are read from a single snapshot, so the backup is consistent
+// even while the server keeps running.
+func Backup(ctx context.Context, conn *pgx.Conn, w io.Writer) (Manifest, error) {
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	m, _, err := readSchema(ctx, tx)
+	if err != nil {
+		return Manifest{}, err
+	}
+	// Each table is spooled to a file first: a tar header needs its size.
+	dir, err := os.MkdirTemp("", "assistant-backup-")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	files := make([]*os.File, len(m.Tables))
+	for i := range m.Tables {
+		t := &m.Tables[i]
+		if files[i], err = os.CreateTemp(dir, "table-"); err != nil {
+			return Manifest{}, err
+		}
+		defer func() { _ = files[i].Close() }()
+		tag, err := conn.PgConn().CopyTo(ctx, files[i], t.copySQL("TO STDOUT"))
+		if err != nil {
+			return Manifest{}, fmt.Errorf("copying %s: %w", t.Name, err)
+		}
+		t.Rows = tag.RowsAffected()
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := writeEntry(tw, manifestName, m.TakenAt, bytes.NewReader(manifest), int64(len(manifest))); err != nil {
+		return Manifest{}, err
+	}
+	for i, t := range m.Tables {
+		size, err := files[i].Seek(0, io.SeekCurrent)
+		if err != nil {
+			return Manifest{}, err
+		}
+		if _, err := files[i].Seek(0, io.SeekStart); err != nil {
+			return Manifest{}, err
+		}
+		if err := writeEntry(tw, t.Name+".copy", m.TakenAt, files[i], size); err != nil {
+			return Manifest{}, fmt.Errorf("archiving %s: %w", t.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return m, gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, r io.Reader, size int64) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: modTime, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// Restore replaces the contents of every table in conn's current schema
+// with the archive read from r. The database must be at the schema the
+// backup was taken at; it is checked before anything is changed, and the
+// restore happens in one transaction, so a failed one changes nothing.
+//
+// Triggers are held off while rows are loaded, as the rows already carry
+// what they would set, and row-level security is lifted so COPY may write
+// to tables that force it. Both need conn to be the tables' owner.
+func Restore(ctx context.Context, conn *pgx.Conn, r io.Reader) (Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("not a backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	m, err := readManifest(tr)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return m, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	target, forced, err := readSchema(ctx, tx)
+	if err != nil {
+		return m, err
+	}
+	if err := checkCompatible(m, target); err != nil {
+		return m, err
+	}
+
+	names := make([]string, len(m.Tables))
+	for i, t := range m.Tables {
+		names[i] = pgx.Identifier{t.Name}.Sanitize()
+		stmt := "ALTER TABLE " + names[i] + " DISABLE TRIGGER USER"
+		if forced[t.Name] {
+			stmt += ", NO FORCE ROW LEVEL SECURITY"
+		}
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return m, fmt.Errorf("preparing %s: %w", t.Name, err)
+		}
+	}
+	if len(names) > 0 {
+		if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(names, ", ")); err != nil {
+			return m, err
+		}
+	}
+	for i, t := range m.Tables {
+		hdr, err := tr.Next()
+		if err != nil || hdr.Name != t.Name+".copy" {
+			return m, fmt.Errorf("archive is missing the rows of %s", t.Name)
+		}
+		tag, err := conn.PgConn().CopyFrom(ctx, tr, t.copySQL("FROM STDIN"))
+		if err != nil {
+			return m, fmt.Errorf("loading %s: %w", t.Name, err)
+		}
+		if tag.RowsAffected() != t.Rows {
+			return m, fmt.Errorf("loading %s: archive has %d rows, manifest says %d", t.Name, tag.RowsAffected(), t.Rows)
+		}
+		stmt := "ALTER TABLE " + names[i] + " ENABLE TRIGGER USER"
+		if forced[t.Name] {
+			stmt += ", FORCE ROW LEVEL SECURITY"
+		}
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return m, fmt.Errorf("finishing %s: %w", t.Name, err)
+		}
+	}
+	return m, tx.Commit(ctx)
+}
+
+// readManifest reads the manifest, which must be the archive's first entry.
+func readManifest(tr *tar.Reader) (Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("not a backup archive: %w", err)
+	}
+	if hdr.Name != manifestName {
+		return Manifest{}, fmt.Errorf("not a backup archive: starts with %s, not %s", hdr.Name, manifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("reading %s: %w", manifestName, err)
+	}
+	if m.FormatVersion != FormatVersion {
+		return m, fmt.Errorf("archive format %d isn't supported; this server reads format %d", m.FormatVersion, FormatVersion)
+	}
+	return m, nil
+}
+
+// checkCompatible reports every way target's tables differ from the
+// backup's.
+func checkCompatible(backup, target Manifest) error {
+	var problems []string
+	if backup.SchemaVersion != 0 && target.SchemaVersion != 0 && backup.SchemaVersion != target.SchemaVersion {
+		problems = append(problems, fmt.Sprintf("backup is at schema version %d, the database at %d", backup.SchemaVersion, target.SchemaVersion))
+	}
+	tables := map[string]Table{}
+	for _, t := range target.Tables {
+		tables[t.Name] = t
+	}
+	for _, b := range backup.Tables {
+		t, ok := tables[b.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("table %s doesn't exist", b.Name))
+			continue
+		}
+		delete(tables, b.Name)
+		for _, bc := range b.Columns {
+			i := slices.IndexFunc(t.Columns, func(c Column) bool { return c.Name == bc.Name })
+			switch {
+			case i < 0:
+				problems = append(problems, fmt.Sprintf("column %s.%s doesn't exist", b.Name, bc.Name))
+			case t.Columns[i].Type != bc.Type:
+				problems = append(problems, fmt.Sprintf("column %s.%s is %s, not %s", b.Name, bc.Name, t.Columns[i].Type, bc.Type))
+			}
+		}
+		for _, c := range t.Columns {
+			if !slices.ContainsFunc(b.Columns, func(bc Column) bool { return bc.Name == c.Name }) {
+				problems = append(problems, fmt.Sprintf("column %s.%s isn't in the backup", b.Name, c.Name))
+			}
+		}
+	}
+	for _, t := range target.Tables {
+		if _, ok := tables[t.Name]; ok {
+			problems = append(problems, fmt.Sprintf("table %s isn't in the backup", t.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrIncompatible, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const (
+	snapshotQuery = `SELECT now(), current_setting('server_version'), to_regclass('goose_db_version') IS NOT NULL;`
+	// schemaVersionQuery follows goose: a version rolled back after it was
+	// applied doesn't count.
+	schemaVersionQuery = `SELECT COALESCE(max(version_id), 0) FROM goose_db_version g WHERE is_applied AND NOT EXISTS (
+		SELECT 1 FROM goose_db_version d WHERE d.version_id = g.version_id AND NOT d.is_applied AND d.id > g.id);`
+	tablesQuery = `SELECT c.relname, c.relforcerowsecurity FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND NOT c.relispartition AND c.relname <> 'goose_db_version';`
+	// Generated columns can't be copied in; they are computed again.
+	columnsQuery = `SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod) FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+		ORDER BY c.relname, a.attnum;`
+	referencesQuery = `SELECT child.relname, parent.relname FROM pg_constraint con
+		JOIN pg_class child ON child.oid = con.conrelid JOIN pg_class parent ON parent.oid = con.confrelid
+		JOIN pg_namespace n ON n.oid = child.relnamespace
+		WHERE con.contype = 'f' AND n.nspname = current_schema();`
+)
+
+// readSchema describes the tables of q's current schema in dependency
+// order, leaving out goose's own, and which of them force row-level
+// security.
+func readSchema(ctx context.Context, q querier) (Manifest, map[string]bool, error) {
+	m := Manifest{FormatVersion: FormatVersion}
+	var hasGoose bool
+	if err := q.QueryRow(ctx, snapshotQuery).Scan(&m.TakenAt, &m.ServerVersion, &hasGoose); err != nil {
+		return m, nil, err
+	}
+	if hasGoose {
+		if err := q.QueryRow(ctx, schemaVersionQuery).Scan(&m.SchemaVersion); err != nil {
+			return m, nil, fmt.Errorf("reading schema version: %w", err)
+		}
+	}
+
+	forced := map[string]bool{}
+	var names []string
+	if err := collect(ctx, q, tablesQuery, func(rows pgx.Rows) error {
+		var name string
+		var force bool
+		if err := rows.Scan(&name, &force); err != nil {
+			return err
+		}
+		names = append(names, name)
+		forced[name] = force
+		return nil
+	}); err != nil {
+		return m, nil, fmt.Errorf("listing tables: %w", err)
+	}
+	columns := map[string][]Column{}
+	if err := collect(ctx, q, columnsQuery, func(rows pgx.Rows) error {
+		var table string
+		var c Column
+		if err := rows.Scan(&table, &c.Name, &c.Type); err != nil {
+			return err
+		}
+		columns[table] = append(columns[table], c)
+		return nil
+	}); err != nil {
+		return m, nil, fmt.Errorf("listing columns: %w", err)
+	}
+	var refs [][2]string
+	if err := collect(ctx, q, referencesQuery, func(rows pgx.Rows) error {
+		var ref [2]string
+		if err := rows.Scan(&ref[0], &ref[1]); err != nil {
+			return err
+		}
+		refs = append(refs, ref)
+		return nil
+	}); err != nil {
+		return m, nil, fmt.Errorf("listing foreign keys: %w", err)
+	}
+
+	order, err := dependencyOrder(names, refs)
+	if err != nil {
+		return m, nil, err
+	}
+	for _, name := range order {
+		m.Tables = append(m.Tables, Table{Name: name, Columns: columns[name]})
+	}
+	return m, forced, nil
+}
+
+func collect(ctx context.Context, q querier, sql string, scan func(pgx.Rows) error) error {
+	rows, err := q.Query(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// dependencyOrder sorts tables so that each comes after the tables it
+// references, given as [table, referenced] pairs, and otherwise by name.
+// A table referencing itself doesn't constrain the order.
+func dependencyOrder(tables []string, refs [][2]string) ([]string, error) {
+	pending := map[string]map[string]bool{}
+	for _, t := range tables {
+		pending[t] = map[string]bool{}
+	}
+	for _, ref := range refs {
+		child, parent := ref[0], ref[1]
+		if _, ok := pending[parent]; !ok || child == parent {
+			continue
+		}
+		if deps, ok := pending[child]; ok {
+			deps[parent] = true
+		}
+	}
+	order := make([]string, 0, len(tables))
+	for len(pending) > 0 {
+		var ready []string
+		for t, deps := range pending {
+			if len(deps) == 0 {
+				ready = append(ready, t)
+			}
+		}
+		if len(ready) == 0 {
+			stuck := make([]string, 0, len(pending))
+			for t := range pending {
+				stuck = append(stuck, t)
+			}
+			slices.Sort(stuck)
+			return nil, fmt.Errorf("foreign keys between %s form a cycle", strings.Join(stuck, ", "))
+		}
+		slices.Sort(ready)
+		for _, t := range ready {
+			delete(pending, t)
+			for _, deps := range pending {
+				delete(deps, t)
+			}
+		}
+		order = append(order, ready...)
+	}
+	return order, nil
+}
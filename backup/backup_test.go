@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyOrder(t *testing.T) {
+	order, err := dependencyOrder(
+		[]string{"todos", "users", "households", "tenants", "todo_dependencies", "projects"},
+		[][2]string{
+			{"todos", "users"}, {"todos", "households"}, {"todos", "projects"}, {"projects", "households"},
+			{"users", "households"}, {"users", "tenants"}, {"households", "tenants"},
+			{"todo_dependencies", "todos"}, {"todos", "todos"}, {"todos", "goose_db_version"},
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenants", "households", "projects", "users", "todos", "todo_dependencies"}, order)
+
+	_, err = dependencyOrder([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "a"}})
+	assert.EqualError(t, err, "foreign keys between a, b form a cycle")
+}
+
+func TestCheckCompatible(t *testing.T) {
+	backup := Manifest{SchemaVersion: 20250913000000, Tables: []Table{
+		{Name: "households", Columns: []Column{{"uid", "uuid"}, {"name", "text"}}},
+		{Name: "todos", Columns: []Column{{"uid", "uuid"}, {"priority", "integer"}}},
+	}}
+	assert.NoError(t, checkCompatible(backup, backup))
+	// Column order doesn't matter, as COPY names the columns.
+	assert.NoError(t, checkCompatible(backup, Manifest{Tables: []Table{
+		{Name: "todos", Columns: []Column{{"priority", "integer"}, {"uid", "uuid"}}},
+		{Name: "households", Columns: []Column{{"name", "text"}, {"uid", "uuid"}}},
+	}}))
+
+	err := checkCompatible(backup, Manifest{SchemaVersion: 20250914000000, Tables: []Table{
+		{Name: "todos", Columns: []Column{{"uid", "uuid"}, {"priority", "text"}, {"status", "text"}}},
+		{Name: "projects", Columns: []Column{{"uid", "uuid"}}},
+	}})
+	assert.ErrorIs(t, err, ErrIncompatible)
+	for _, problem := range []string{
+		"backup is at schema version 20250913000000, the database at 20250914000000",
+		"table households doesn't exist",
+		"column todos.priority is text, not integer",
+		"column todos.status isn't in the backup",
+		"table projects isn't in the backup",
+	} {
+		assert.ErrorContains(t, err, problem)
+	}
+}
+
+func archive(t *testing.T, entries map[string]string, order ...string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range order {
+		require.NoError(t, writeEntry(tw, name, time.Now(), strings.NewReader(entries[name]), int64(len(entries[name]))))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return &buf
+}
+
+func TestReadManifest(t *testing.T) {
+	want := Manifest{FormatVersion: FormatVersion, SchemaVersion: 20250913000000, TakenAt: time.Date(2025, 9, 13, 10, 0, 0, 0, time.UTC),
+		Tables: []Table{{Name: "tenants", Columns: []Column{{"uid", "uuid"}}, Rows: 1}}}
+	manifest, err := json.Marshal(want)
+	require.NoError(t, err)
+	buf := archive(t, map[string]string{manifestName: string(manifest), "tenants.copy": "00000000-0000-0000-0000-000000000001\n"},
+		manifestName, "tenants.copy")
+
+	gz, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	got, err := readManifest(tar.NewReader(gz))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, int64(1), got.Rows())
+
+	future, err := json.Marshal(Manifest{FormatVersion: FormatVersion + 1})
+	require.NoError(t, err)
+	for name, buf := range map[string]*bytes.Buffer{
+		"archive format 2 isn't supported":            archive(t, map[string]string{manifestName: string(future)}, manifestName),
+		"starts with tenants.copy, not manifest.json": archive(t, map[string]string{"tenants.copy": ""}, "tenants.copy"),
+		"reading manifest.json":                       archive(t, map[string]string{manifestName: "{"}, manifestName),
+		"not a backup archive: gzip: invalid header":  bytes.NewBufferString("COPY todos"),
+	} {
+		// Restore reads the manifest before it touches the database.
+		_, err := Restore(t.Context(), nil, buf)
+		assert.ErrorContains(t, err, name)
+	}
+}
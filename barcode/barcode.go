@@ -0,0 +1,64 @@
+// Package barcode resolves the barcodes printed on groceries to the products
+// they identify, using an open product database such as Open Food Facts.
+package barcode
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Product is what a barcode identifies. Size is the pack size as printed,
+// e.g. "500 g"; Quantity and Unit are the same size as a number and unit
+// when the database gives one.
+type Product struct {
+	Barcode    string   `json:"barcode"`
+	Name       string   `json:"name"`
+	Brand      string   `json:"brand,omitempty"`
+	Size       string   `json:"size,omitempty"`
+	Quantity   *float64 `json:"quantity,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	ImageURL   string   `json:"image_url,omitempty"`
+}
+
+// Provider looks products up by barcode.
+type Provider interface {
+	// Lookup returns the product with code, a barcode from Normalize, or
+	// ErrNotFound.
+	Lookup(ctx context.Context, code string) (Product, error)
+}
+
+var (
+	ErrNotFound = errors.New("no product has this barcode")
+	ErrInvalid  = errors.New("barcode must be an EAN, UPC or GTIN of 8, 12, 13 or 14 digits")
+)
+
+// Normalize drops the spaces and dashes in code and checks it is an EAN-8,
+// UPC-A, EAN-13 or GTIN-14 with a correct check digit.
+func Normalize(code string) (string, error) {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrInvalid
+	}
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return "", ErrInvalid
+		}
+		digit := int(c - '0')
+		// From the right, the check digit counts once and the digits
+		// before it alternately three times and once.
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return "", ErrInvalid
+	}
+	return code, nil
+}
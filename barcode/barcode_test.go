@@ -0,0 +1,57 @@
+package barcode
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, code := range []string{"3017620422003", "036000291452", "96385074", "1 0036000 29145 9"} {
+		_, err := Normalize(code)
+		assert.NoError(t, err, code)
+	}
+	code, _ := Normalize("3017-6204 22003")
+	assert.Equal(t, "3017620422003", code)
+
+	for _, code := range []string{"", "3017620422004", "301762042200", "30176204220O3", "123456789"} {
+		_, err := Normalize(code)
+		assert.ErrorIs(t, err, ErrInvalid, code)
+	}
+}
+
+func TestOpenFoodFactsLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("User-Agent"), "assistant-server")
+		assert.Contains(t, r.URL.Query().Get("fields"), "product_name")
+		switch r.URL.Path {
+		case "/api/v2/product/3017620422003":
+			fmt.Fprint(w, `{"status": 1, "product": {"product_name": "Nutella", "brands": "Ferrero, Nutella",
+				"quantity": "400 g", "product_quantity": "400", "product_quantity_unit": "g",
+				"categories_tags": ["en:spreads", "en:hazelnut-spreads", "fr:pates-a-tartiner"]}}`)
+		case "/api/v2/product/96385074":
+			fmt.Fprint(w, `{"status": 0, "status_verbose": "product not found"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	off := NewOpenFoodFacts(srv.URL+"/", srv.Client())
+
+	p, err := off.Lookup(t.Context(), "3017620422003")
+	require.NoError(t, err)
+	q := 400.0
+	assert.Equal(t, Product{
+		Barcode: "3017620422003", Name: "Nutella", Brand: "Ferrero", Size: "400 g", Quantity: &q, Unit: "g",
+		Categories: []string{"spreads", "hazelnut spreads"},
+	}, p)
+
+	_, err = off.Lookup(t.Context(), "96385074")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = off.Lookup(t.Context(), "036000291452")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,97 @@
+package barcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OpenFoodFactsURL is the public Open Food Facts database.
+const OpenFoodFactsURL = "https://world.openfoodfacts.org"
+
+// userAgent identifies the server to Open Food Facts, which asks every app
+// to name itself.
+const userAgent = "assistant-server/1.0 (+https://github.com/pbdeuchler/assistant-server)"
+
+// offFields are the product fields read from Open Food Facts.
+const offFields = "product_name,brands,quantity,product_quantity,product_quantity_unit,categories_tags,image_front_url"
+
+// OpenFoodFacts looks products up in Open Food Facts, or a server with the
+// same API such as a self-hosted mirror.
+type OpenFoodFacts struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenFoodFacts calls baseURL + "/api/v2/product/{code}", e.g. with
+// baseURL OpenFoodFactsURL.
+func NewOpenFoodFacts(baseURL string, client *http.Client) *OpenFoodFacts {
+	return &OpenFoodFacts{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+type offResponse struct {
+	Status  int `json:"status"`
+	Product struct {
+		ProductName     string          `json:"product_name"`
+		Brands          string          `json:"brands"`
+		Quantity        string          `json:"quantity"`
+		ProductQuantity json.RawMessage `json:"product_quantity"`
+		QuantityUnit    string          `json:"product_quantity_unit"`
+		CategoriesTags  []string        `json:"categories_tags"`
+		ImageFrontURL   string          `json:"image_front_url"`
+	} `json:"product"`
+}
+
+func (o *OpenFoodFacts) Lookup(ctx context.Context, code string) (Product, error) {
+	u := fmt.Sprintf("%s/api/v2/product/%s?fields=%s", o.baseURL, url.PathEscape(code), offFields)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Product{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Product{}, fmt.Errorf("barcode: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Product{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Product{}, fmt.Errorf("barcode: open food facts answered %s", resp.Status)
+	}
+	var out offResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Product{}, fmt.Errorf("barcode: %w", err)
+	}
+	if out.Status != 1 || strings.TrimSpace(out.Product.ProductName) == "" {
+		return Product{}, ErrNotFound
+	}
+
+	p := Product{
+		Barcode:  code,
+		Name:     strings.TrimSpace(out.Product.ProductName),
+		Size:     strings.TrimSpace(out.Product.Quantity),
+		ImageURL: out.Product.ImageFrontURL,
+	}
+	// brands lists the brand first, then any owners.
+	p.Brand, _, _ = strings.Cut(out.Product.Brands, ",")
+	p.Brand = strings.TrimSpace(p.Brand)
+	// product_quantity comes as a number or a string, depending on who
+	// entered it.
+	if q, err := strconv.ParseFloat(strings.Trim(string(out.Product.ProductQuantity), `"`), 64); err == nil && q > 0 {
+		p.Quantity = &q
+		p.Unit = out.Product.QuantityUnit
+	}
+	for _, tag := range out.Product.CategoriesTags {
+		if name, ok := strings.CutPrefix(tag, "en:"); ok {
+			p.Categories = append(p.Categories, strings.ReplaceAll(name, "-", " "))
+		}
+	}
+	return p, nil
+}
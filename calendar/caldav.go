@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalDAVAccount is the value of a CALDAV credential. URL is the calendar
+// collection itself, e.g. https://caldav.fastmail.com/dav/calendars/user/
+// me@example.com/Default/; iCloud and Fastmail want an app password.
+type CalDAVAccount struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CalDAV is a calendar on a CalDAV server (RFC 4791).
+type CalDAV struct {
+	account CalDAVAccount
+	client  *http.Client
+}
+
+func NewCalDAV(account CalDAVAccount, client *http.Client) *CalDAV {
+	if !strings.HasSuffix(account.URL, "/") {
+		account.URL += "/"
+	}
+	return &CalDAV{account: account, client: client}
+}
+
+// calendarQuery asks for the events overlapping a time range, with
+// recurring events expanded by the server.
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:calendar-data><C:expand start="%[1]s" end="%[2]s"/></C:calendar-data>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%[1]s" end="%[2]s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+type multistatus struct {
+	Responses []struct {
+		Propstats []struct {
+			CalendarData string `xml:"prop>calendar-data"`
+			Status       string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func (c *CalDAV) Events(ctx context.Context, from, to time.Time) ([]Event, error) {
+	body := fmt.Sprintf(calendarQuery, from.UTC().Format(icalUTC), to.UTC().Format(icalUTC))
+	req, err := c.request(ctx, "REPORT", c.account.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, statusError("caldav REPORT", resp)
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("caldav REPORT: %w", err)
+	}
+	events := []Event{}
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if ps.CalendarData == "" || !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			parsed, err := parseICal(ps.CalendarData)
+			if err != nil {
+				return nil, fmt.Errorf("caldav REPORT: %w", err)
+			}
+			events = append(events, parsed...)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
+
+func (c *CalDAV) CreateEvent(ctx context.Context, e Event) (Event, error) {
+	if err := validate(e); err != nil {
+		return Event{}, err
+	}
+	e.ID = uuid.NewString()
+	req, err := c.request(ctx, http.MethodPut, c.account.URL+url.PathEscape(e.ID)+".ics", formatICal(e, time.Now()))
+	if err != nil {
+		return Event{}, err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	// Never overwrite an existing event.
+	req.Header.Set("If-None-Match", "*")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Event{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return Event{}, statusError("caldav PUT", resp)
+	}
+	return e, nil
+}
+
+func (c *CalDAV) request(ctx context.Context, method, target, body string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.account.Username, c.account.Password)
+	return req, nil
+}
+
+// statusError reports an unexpected response, with the start of its body.
+func statusError(op string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s: %s: %s", op, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,45 @@
+// Package calendar reads and writes a user's calendar, whichever service
+// hosts it: Google Calendar or any CalDAV server such as iCloud or Fastmail.
+package calendar
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Credential types, as stored in the credentials table.
+const (
+	GoogleCredential = "GOOGLE_CALENDAR"
+	CalDAVCredential = "CALDAV"
+)
+
+// Event is a calendar event. All-day events start and end at midnight UTC,
+// End being the day after the last day.
+type Event struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"all_day,omitempty"`
+}
+
+// Calendar is a single calendar a user has connected.
+type Calendar interface {
+	// Events returns the events overlapping from to to, ordered by start.
+	// Recurring events are expanded into their occurrences.
+	Events(ctx context.Context, from, to time.Time) ([]Event, error)
+	// CreateEvent adds e and returns it with its new ID.
+	CreateEvent(ctx context.Context, e Event) (Event, error)
+}
+
+var ErrInvalidEvent = errors.New("event needs a title and must end after it starts")
+
+func validate(e Event) error {
+	if e.Title == "" || !e.End.After(e.Start) {
+		return ErrInvalidEvent
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleICal = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\nTZID:Europe/London\r\nBEGIN:STANDARD\r\nDTSTART:19701025T020000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:dentist-1\r\n" +
+	"DTSTART;TZID=Europe/London:20250820T093000\r\n" +
+	"DURATION:PT45M\r\n" +
+	"SUMMARY:Dentist\\, Mia\r\n" +
+	"DESCRIPTION:Bring the form\\nand the card\r\n" +
+	"LOCATION:12 High St\r\n" +
+	"BEGIN:VALARM\r\nDESCRIPTION:Reminder\r\nTRIGGER:-PT1H\r\nEND:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday-1\r\n" +
+	"DTSTART;VALUE=DATE:20250825\r\n" +
+	"SUMMARY:Bank holi\r\n" +
+	" day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	events, err := parseICal(sampleICal)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	start := time.Date(2025, 8, 20, 9, 30, 0, 0, london)
+	assert.Equal(t, "dentist-1", events[0].ID)
+	assert.Equal(t, "Dentist, Mia", events[0].Title)
+	assert.Equal(t, "Bring the form\nand the card", events[0].Description)
+	assert.Equal(t, "12 High St", events[0].Location)
+	assert.True(t, start.Equal(events[0].Start))
+	assert.True(t, start.Add(45*time.Minute).Equal(events[0].End))
+
+	assert.Equal(t, Event{
+		ID:     "holiday-1",
+		Title:  "Bank holiday",
+		Start:  time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2025, 8, 26, 0, 0, 0, 0, time.UTC),
+		AllDay: true,
+	}, events[1])
+}
+
+func TestFormatICalRoundTrips(t *testing.T) {
+	e := Event{
+		ID:          "e1",
+		Title:       "Parents' evening; room 4, " + strings.Repeat("long ", 20),
+		Description: "Ask about maths\nand reading",
+		Start:       time.Date(2025, 9, 3, 17, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 9, 3, 18, 0, 0, 0, time.UTC),
+	}
+	out := formatICal(e, time.Now())
+	for _, line := range strings.Split(out, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	events, err := parseICal(out)
+	require.NoError(t, err)
+	assert.Equal(t, []Event{e}, events)
+}
+
+func TestParseICalDuration(t *testing.T) {
+	assert.Equal(t, 90*time.Minute, parseICalDuration("PT1H30M"))
+	assert.Equal(t, 8*24*time.Hour, parseICalDuration("P1W1D"))
+	assert.Equal(t, time.Duration(0), parseICalDuration("soon"))
+}
+
+func TestCalDAV(t *testing.T) {
+	var put string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if user != "me@example.com" || pass != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch r.Method {
+		case "REPORT":
+			assert.Equal(t, "/dav/cal/", r.URL.Path)
+			assert.Equal(t, "1", r.Header.Get("Depth"))
+			assert.Contains(t, string(body), `<C:time-range start="20250818T000000Z" end="20250901T000000Z"/>`)
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = io.WriteString(w, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response>
+    <d:href>/dav/cal/dentist-1.ics</d:href>
+    <d:propstat><d:prop><cal:calendar-data>`+sampleICal+`</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`)
+		case http.MethodPut:
+			assert.Equal(t, "*", r.Header.Get("If-None-Match"))
+			put = r.URL.Path + "\n" + string(body)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	cal := NewCalDAV(CalDAVAccount{URL: srv.URL + "/dav/cal", Username: "me@example.com", Password: "app-password"}, srv.Client())
+	events, err := cal.Events(t.Context(), time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Dentist, Mia", events[0].Title)
+
+	created, err := cal.CreateEvent(t.Context(), Event{
+		Title: "Swimming",
+		Start: time.Date(2025, 8, 23, 10, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 8, 23, 11, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Contains(t, put, "/dav/cal/"+created.ID+".ics\n")
+	assert.Contains(t, put, "SUMMARY:Swimming\r\n")
+	assert.Contains(t, put, "DTSTART:20250823T100000Z\r\n")
+
+	_, err = cal.CreateEvent(t.Context(), Event{Title: "Backwards", Start: created.End, End: created.Start})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+
+	bad := NewCalDAV(CalDAVAccount{URL: srv.URL + "/dav/cal/", Username: "me@example.com"}, srv.Client())
+	_, err = bad.Events(t.Context(), time.Now(), time.Now().Add(time.Hour))
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestGoogle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/calendars/primary/events", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "true", r.URL.Query().Get("singleEvents"))
+			_, _ = io.WriteString(w, `{"items": [
+				{"id": "g1", "summary": "Standup", "start": {"dateTime": "2025-08-20T09:00:00+01:00"}, "end": {"dateTime": "2025-08-20T09:15:00+01:00"}},
+				{"id": "g2", "summary": "Holiday", "start": {"date": "2025-08-25"}, "end": {"date": "2025-08-26"}}
+			]}`)
+		case http.MethodPost:
+			var in googleEvent
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			assert.Equal(t, "2025-08-30", in.Start.Date)
+			in.ID = "g3"
+			_ = json.NewEncoder(w).Encode(in)
+		}
+	}))
+	defer srv.Close()
+
+	cal := NewGoogle(srv.Client())
+	cal.baseURL = srv.URL
+	events, err := cal.Events(t.Context(), time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Standup", events[0].Title)
+	assert.Equal(t, 15*time.Minute, events[0].End.Sub(events[0].Start))
+	assert.True(t, events[1].AllDay)
+
+	created, err := cal.CreateEvent(t.Context(), Event{
+		Title:  "Camping",
+		Start:  time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		AllDay: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "g3", created.ID)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), created.End)
+}
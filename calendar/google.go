@@ -0,0 +1,129 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const googleCalendarAPI = "https://www.googleapis.com/calendar/v3"
+
+// Google is a user's primary Google calendar. The client must add the
+// user's OAuth token to requests, as oauth2.Config.Client does.
+type Google struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewGoogle(client *http.Client) *Google {
+	return &Google{client: client, baseURL: googleCalendarAPI}
+}
+
+// googleTime is an event's start or end: a date for all-day events,
+// otherwise a date-time.
+type googleTime struct {
+	Date     string     `json:"date,omitempty"`
+	DateTime *time.Time `json:"dateTime,omitempty"`
+}
+
+type googleEvent struct {
+	ID          string     `json:"id,omitempty"`
+	Summary     string     `json:"summary"`
+	Description string     `json:"description,omitempty"`
+	Location    string     `json:"location,omitempty"`
+	Start       googleTime `json:"start"`
+	End         googleTime `json:"end"`
+}
+
+func (g googleEvent) event() Event {
+	e := Event{ID: g.ID, Title: g.Summary, Description: g.Description, Location: g.Location}
+	if g.Start.DateTime != nil {
+		e.Start = *g.Start.DateTime
+	} else {
+		e.Start, _ = time.Parse(time.DateOnly, g.Start.Date)
+		e.AllDay = true
+	}
+	if g.End.DateTime != nil {
+		e.End = *g.End.DateTime
+	} else {
+		e.End, _ = time.Parse(time.DateOnly, g.End.Date)
+	}
+	return e
+}
+
+func toGoogleTime(t time.Time, allDay bool) googleTime {
+	if allDay {
+		return googleTime{Date: t.Format(time.DateOnly)}
+	}
+	return googleTime{DateTime: &t}
+}
+
+func (c *Google) Events(ctx context.Context, from, to time.Time) ([]Event, error) {
+	q := url.Values{
+		"timeMin":      {from.UTC().Format(time.RFC3339)},
+		"timeMax":      {to.UTC().Format(time.RFC3339)},
+		"singleEvents": {"true"},
+		"orderBy":      {"startTime"},
+		"maxResults":   {"250"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/calendars/primary/events?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("google calendar list", resp)
+	}
+	var out struct {
+		Items []googleEvent `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	events := make([]Event, len(out.Items))
+	for i, item := range out.Items {
+		events[i] = item.event()
+	}
+	return events, nil
+}
+
+func (c *Google) CreateEvent(ctx context.Context, e Event) (Event, error) {
+	if err := validate(e); err != nil {
+		return Event{}, err
+	}
+	body, err := json.Marshal(googleEvent{
+		Summary:     e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		Start:       toGoogleTime(e.Start, e.AllDay),
+		End:         toGoogleTime(e.End, e.AllDay),
+	})
+	if err != nil {
+		return Event{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/calendars/primary/events", bytes.NewReader(body))
+	if err != nil {
+		return Event{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Event{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Event{}, statusError("google calendar insert", resp)
+	}
+	var created googleEvent
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return Event{}, err
+	}
+	return created.event(), nil
+}
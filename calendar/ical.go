@@ -0,0 +1,206 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+	icalUTC      = "20060102T150405Z"
+)
+
+// icalProperty is one content line, e.g. DTSTART;TZID=Europe/London:2025...
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICal splits iCalendar text into content lines, joining the
+// continuation lines that start with a space or tab.
+func unfoldICal(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func parseICalLine(line string) icalProperty {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	p := icalProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: value}
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p
+}
+
+// parseICal returns the VEVENTs in an iCalendar object. Properties it
+// doesn't know are ignored.
+func parseICal(data string) ([]Event, error) {
+	var events []Event
+	var e *Event
+	var hasEnd bool
+	var duration time.Duration
+	nested := 0 // depth of components inside the event, such as VALARM
+	for _, line := range unfoldICal(data) {
+		p := parseICalLine(line)
+		switch {
+		case p.name == "BEGIN" && p.value == "VEVENT":
+			e, hasEnd, duration, nested = &Event{}, false, 0, 0
+		case e == nil:
+			continue
+		case p.name == "BEGIN":
+			nested++
+		case nested > 0:
+			if p.name == "END" {
+				nested--
+			}
+		case p.name == "END" && p.value == "VEVENT":
+			if !hasEnd {
+				e.End = e.Start.Add(duration)
+				if duration == 0 && e.AllDay {
+					e.End = e.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *e)
+			e = nil
+		case p.name == "UID":
+			e.ID = p.value
+		case p.name == "SUMMARY":
+			e.Title = unescapeICalText(p.value)
+		case p.name == "DESCRIPTION":
+			e.Description = unescapeICalText(p.value)
+		case p.name == "LOCATION":
+			e.Location = unescapeICalText(p.value)
+		case p.name == "DTSTART":
+			t, allDay, err := parseICalTime(p)
+			if err != nil {
+				return nil, err
+			}
+			e.Start, e.AllDay = t, allDay
+		case p.name == "DTEND":
+			t, _, err := parseICalTime(p)
+			if err != nil {
+				return nil, err
+			}
+			e.End, hasEnd = t, true
+		case p.name == "DURATION":
+			duration = parseICalDuration(p.value)
+		}
+	}
+	return events, nil
+}
+
+// parseICalTime reads a DATE, a UTC DATE-TIME, or a local DATE-TIME in its
+// TZID. Floating times, and zones Go doesn't know, are taken as UTC.
+func parseICalTime(p icalProperty) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len(icalDate) {
+		t, err := time.Parse(icalDate, p.value)
+		return t, true, err
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse(icalUTC, p.value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(icalDateTime, p.value, loc)
+	return t, false, err
+}
+
+// parseICalDuration reads the day, hour, minute and second parts of a
+// DURATION such as P1D or PT1H30M. Malformed durations count as zero.
+func parseICalDuration(s string) time.Duration {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "+"), "P")
+	var d time.Duration
+	n := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int(c-'0')
+			continue
+		case c == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H':
+			d += time.Duration(n) * time.Hour
+		case c == 'M':
+			d += time.Duration(n) * time.Minute
+		case c == 'S':
+			d += time.Duration(n) * time.Second
+		}
+		n = 0
+	}
+	return d
+}
+
+var (
+	icalEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func unescapeICalText(s string) string { return icalUnescaper.Replace(s) }
+
+// formatICal writes e as a VCALENDAR holding a single VEVENT.
+func formatICal(e Event, now time.Time) string {
+	var b strings.Builder
+	line := func(format string, args ...any) { b.WriteString(foldICal(fmt.Sprintf(format, args...))) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//assistant-server//calendar//EN")
+	line("BEGIN:VEVENT")
+	line("UID:%s", e.ID)
+	line("DTSTAMP:%s", now.UTC().Format(icalUTC))
+	if e.AllDay {
+		line("DTSTART;VALUE=DATE:%s", e.Start.Format(icalDate))
+		line("DTEND;VALUE=DATE:%s", e.End.Format(icalDate))
+	} else {
+		line("DTSTART:%s", e.Start.UTC().Format(icalUTC))
+		line("DTEND:%s", e.End.UTC().Format(icalUTC))
+	}
+	line("SUMMARY:%s", icalEscaper.Replace(e.Title))
+	if e.Description != "" {
+		line("DESCRIPTION:%s", icalEscaper.Replace(e.Description))
+	}
+	if e.Location != "" {
+		line("LOCATION:%s", icalEscaper.Replace(e.Location))
+	}
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// foldICal ends a content line with CRLF, first breaking it into lines of
+// at most 75 bytes without splitting a UTF-8 sequence.
+func foldICal(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		if n := utf8.RuneLen(r); width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += utf8.RuneLen(r)
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
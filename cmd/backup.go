@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pbdeuchler/assistant-server/backup"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// Backup writes an archive of the database at cfg.DatabaseURL to path, or
+// to stdout if path is "" or "-", and reports what it holds on stderr.
+func Backup(ctx context.Context, cfg Config, path string) (err error) {
+	// Copying a large table can take longer than any query the server
+	// runs. The archive holds every tenant's rows.
+	cfg.DBStatementTimeout = 0
+	ctx = postgres.WithAllTenants(ctx)
+	_, pool, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	var w io.Writer = os.Stdout
+	if path != "" && path != "-" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+			}
+		}()
+		w = f
+	}
+	m, err := backup.Backup(ctx, conn.Conn(), w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Backed up %d rows from %d tables at schema version %d, as of %s.\n",
+		m.Rows(), len(m.Tables), m.SchemaVersion, m.TakenAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
+
+// Restore replaces the contents of the database at cfg.DatabaseURL with the
+// archive at path, or on stdin if path is "" or "-".
+func Restore(ctx context.Context, cfg Config, path string) error {
+	var r io.Reader = os.Stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	cfg.DBStatementTimeout = 0
+	ctx = postgres.WithAllTenants(ctx)
+	_, pool, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	m, err := backup.Restore(ctx, conn.Conn(), r)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Restored %d rows to %d tables from the backup of %s.\n",
+		m.Rows(), len(m.Tables), m.TakenAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
@@ -1,18 +1,199 @@
 package cmd
 
-import "github.com/caarlos0/env/v11"
+import (
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+)
 
 type Config struct {
-	Port               string `env:"PORT" envDefault:"8080"`
-	DatabaseURL        string `env:"DATABASE_URL"`
-	GCloudClientID     string `env:"GCLOUD_CLIENT_ID"`
-	GCloudClientSecret string `env:"GCLOUD_CLIENT_SECRET"`
-	GCloudProjectID    string `env:"GCLOUD_PROJECT_ID"`
-	BaseURL            string `env:"BASE_URL" envDefault:"http://localhost:8080"`
+	Port string `env:"PORT" envDefault:"8080"`
+	// AdminPort serves /healthz and /metrics on a port of their own, which
+	// shouldn't be exposed to the internet; /debug/vars is then no longer
+	// served on Port. It is off when empty. With AdminToken set it also
+	// serves /debug/vars, /debug/runtime and /debug/pprof/ to callers
+	// presenting the token as a bearer token.
+	AdminPort          string        `env:"ADMIN_PORT"`
+	AdminToken         string        `env:"ADMIN_TOKEN"`
+	DatabaseURL        string        `env:"DATABASE_URL"`
+	GCloudClientID     string        `env:"GCLOUD_CLIENT_ID"`
+	GCloudClientSecret string        `env:"GCLOUD_CLIENT_SECRET"`
+	GCloudProjectID    string        `env:"GCLOUD_PROJECT_ID"`
+	BaseURL            string        `env:"BASE_URL" envDefault:"http://localhost:8080"`
+	MCPRequireAPIKey   bool          `env:"MCP_REQUIRE_API_KEY" envDefault:"false"`
+	MCPToolsPageSize   int           `env:"MCP_TOOLS_PAGE_SIZE" envDefault:"50"`
+	MCPSessionTTL      time.Duration `env:"MCP_SESSION_TTL" envDefault:"24h"`
+	MCPMaxSessions     int           `env:"MCP_MAX_SESSIONS" envDefault:"10000"`
+	// OperatorToken, presented as a bearer token, lets a caller act as an
+	// operator, e.g. to create API keys for any user. There are no
+	// operators when it is empty.
+	OperatorToken string `env:"OPERATOR_TOKEN"`
+	// MCPConfirmationPolicies overrides per-tool confirmation, e.g.
+	// "delete_note:never,delete_recipe:if_supported".
+	MCPConfirmationPolicies map[string]string `env:"MCP_CONFIRMATION_POLICIES"`
+	MCPElicitationTimeout   time.Duration     `env:"MCP_ELICITATION_TIMEOUT" envDefault:"5m"`
+	// MCPToolCacheTTL is how long the results of list_todos, get_recipe
+	// and get_preference are reused for identical calls; zero turns the
+	// cache off.
+	MCPToolCacheTTL time.Duration `env:"MCP_TOOL_CACHE_TTL" envDefault:"5s"`
+	// FeatureFlagTTL is how long the feature_flags table is cached; a flag
+	// changed through another server applies here within it.
+	FeatureFlagTTL time.Duration `env:"FEATURE_FLAG_TTL" envDefault:"30s"`
+	// APIUsageFlushInterval is how often the requests and tool calls counted
+	// per API key are written to the database.
+	APIUsageFlushInterval time.Duration `env:"API_USAGE_FLUSH_INTERVAL" envDefault:"1m"`
+	// ScratchpadTTL is how long an assistant's scratchpad entry is kept when
+	// it is set without a TTL of its own.
+	ScratchpadTTL time.Duration `env:"SCRATCHPAD_TTL" envDefault:"24h"`
+	// NoteShareSecret signs shareable note links; sharing is disabled when
+	// it is empty.
+	NoteShareSecret string        `env:"NOTE_SHARE_SECRET"`
+	NoteShareTTL    time.Duration `env:"NOTE_SHARE_TTL" envDefault:"168h"`
+	// BootstrapPromptBudget caps the bootstrap prompt in estimated tokens;
+	// zero disables the cap.
+	BootstrapPromptBudget int `env:"BOOTSTRAP_PROMPT_BUDGET" envDefault:"8000"`
+	// LogRedactKeys are extra case-insensitive regular expressions for
+	// attribute and JSON keys whose values are masked in logs.
+	LogRedactKeys []string `env:"LOG_REDACT_KEYS"`
+	// AuthzPolicyFile is a JSON role × tool/endpoint policy checked before
+	// MCP tool calls and REST handlers. AuthzDryRun only logs its denials.
+	AuthzPolicyFile string `env:"AUTHZ_POLICY_FILE"`
+	AuthzDryRun     bool   `env:"AUTHZ_DRY_RUN" envDefault:"false"`
+	// CompressResponses gzip- or deflate-encodes REST and bootstrap
+	// responses of at least CompressMinSize bytes.
+	CompressResponses bool `env:"COMPRESS_RESPONSES" envDefault:"true"`
+	CompressMinSize   int  `env:"COMPRESS_MIN_SIZE" envDefault:"1024"`
+	// CORSAllowedOrigins are the browser origins, or "*", allowed to call
+	// the REST and MCP endpoints; CORS is off when it is empty.
+	CORSAllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	CORSMaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`
+	// SMTPHost is the relay for email digests, e.g. an SES SMTP endpoint;
+	// digests are off when it is empty. DigestHour is the UTC hour they go
+	// out.
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+	DigestHour   int    `env:"DIGEST_HOUR" envDefault:"7"`
+	// APNsKeyFile is the .p8 key for Apple push notifications,
+	// FCMCredentialsFile a Firebase service account key and SlackBotToken
+	// a Slack app's bot token; reminders for due todos are sent when any is
+	// set. SlackSigningSecret verifies the Slack app's interaction requests,
+	// which the Done and Snooze buttons on its reminders send.
+	APNsKeyFile        string        `env:"APNS_KEY_FILE"`
+	APNsKeyID          string        `env:"APNS_KEY_ID"`
+	APNsTeamID         string        `env:"APNS_TEAM_ID"`
+	APNsTopic          string        `env:"APNS_TOPIC"`
+	APNsSandbox        bool          `env:"APNS_SANDBOX" envDefault:"false"`
+	FCMCredentialsFile string        `env:"FCM_CREDENTIALS_FILE"`
+	SlackBotToken      string        `env:"SLACK_BOT_TOKEN"`
+	SlackSigningSecret string        `env:"SLACK_SIGNING_SECRET"`
+	ReminderInterval   time.Duration `env:"REMINDER_INTERVAL" envDefault:"1m"`
+	// LLMURL is an OpenAI-compatible API, e.g. https://api.openai.com/v1.
+	// With it set, todos can be extracted from notes, and notes not updated
+	// for NoteSummaryAge are condensed into digest notes, unless
+	// NoteSummaryAge is 0.
+	LLMURL              string        `env:"LLM_URL"`
+	LLMAPIKey           string        `env:"LLM_API_KEY"`
+	LLMModel            string        `env:"LLM_MODEL"`
+	NoteSummaryAge      time.Duration `env:"NOTE_SUMMARY_AGE" envDefault:"2160h"`
+	NoteSummaryInterval time.Duration `env:"NOTE_SUMMARY_INTERVAL" envDefault:"24h"`
+	// AutoTagger suggests tags for notes and recipes saved without any:
+	// "keywords" matches AutoTagRules (tag:keyword|keyword) on top of the
+	// built-in rules, and "llm" asks the LLM. Suggestions are off when it
+	// is empty.
+	AutoTagger   string            `env:"AUTO_TAGGER"`
+	AutoTagRules map[string]string `env:"AUTO_TAG_RULES"`
+	// GroceryClassifier sorts pantry and shopping list items into aisles:
+	// "rules" matches GroceryRules (category:keyword|keyword) on top of the
+	// built-in rules, and "llm" also asks the LLM about items no rule
+	// places.
+	GroceryClassifier string            `env:"GROCERY_CLASSIFIER" envDefault:"rules"`
+	GroceryRules      map[string]string `env:"GROCERY_RULES"`
+	// BarcodeLookupURL is an Open Food Facts compatible API that GET
+	// /pantry/lookup resolves barcodes with, e.g.
+	// https://world.openfoodfacts.org. Lookups send barcodes to it, so they
+	// are off unless it is set.
+	BarcodeLookupURL string `env:"BARCODE_LOOKUP_URL"`
+	// RecipeRefresh enables the refresh_recipe MCP tool, which fetches a
+	// recipe's external_url from the server. Only public addresses are
+	// fetched, but it is still off unless asked for.
+	RecipeRefresh bool `env:"RECIPE_REFRESH" envDefault:"false"`
+	// WeeklyReviews turns on the scheduled weekly review for households
+	// that opted in, written on WeeklyReviewDay (0 is Sunday) at
+	// WeeklyReviewHour UTC.
+	WeeklyReviews    bool         `env:"WEEKLY_REVIEWS" envDefault:"false"`
+	WeeklyReviewDay  time.Weekday `env:"WEEKLY_REVIEW_DAY" envDefault:"0"`
+	WeeklyReviewHour int          `env:"WEEKLY_REVIEW_HOUR" envDefault:"18"`
+	// RetentionInterval is how often retention policies are enforced.
+	RetentionInterval time.Duration `env:"RETENTION_INTERVAL" envDefault:"24h"`
+	// JobWorkers is how many background job runs each server works on at
+	// once, checking the queue every JobPollInterval when it is empty.
+	// JobSchedules overrides when jobs run, e.g.
+	// "digests=30 6 * * 1-5;retention=@every 6h".
+	JobWorkers      int               `env:"JOB_WORKERS" envDefault:"2"`
+	JobPollInterval time.Duration     `env:"JOB_POLL_INTERVAL" envDefault:"5s"`
+	JobSchedules    map[string]string `env:"JOB_SCHEDULES" envSeparator:";" envKeyValSeparator:"="`
+	// UsageStats serves table sizes, index use and the features in use at
+	// /admin/usage, for operators planning capacity. It is opt-in.
+	UsageStats bool `env:"USAGE_STATS" envDefault:"false"`
+	// DBReadTimeout and DBWriteTimeout cap each attempt at a database read
+	// or write. Statements failing transiently, e.g. on a serialization
+	// failure, are retried up to DBMaxRetries times with a jittered backoff
+	// starting at DBRetryDelay.
+	DBReadTimeout  time.Duration `env:"DB_READ_TIMEOUT" envDefault:"5s"`
+	DBWriteTimeout time.Duration `env:"DB_WRITE_TIMEOUT" envDefault:"10s"`
+	DBMaxRetries   int           `env:"DB_MAX_RETRIES" envDefault:"3"`
+	DBRetryDelay   time.Duration `env:"DB_RETRY_DELAY" envDefault:"50ms"`
+	// DBStatementTimeout makes Postgres cancel any statement running
+	// longer, including those in transactions; zero leaves the server's
+	// setting. Statements slower than DBSlowQueryThreshold are logged with
+	// their SQL and argument types; zero turns that off.
+	DBStatementTimeout   time.Duration `env:"DB_STATEMENT_TIMEOUT" envDefault:"30s"`
+	DBSlowQueryThreshold time.Duration `env:"DB_SLOW_QUERY_THRESHOLD" envDefault:"500ms"`
+	// DBFaults injects failures into database calls so integration tests
+	// and staging can exercise error handling and retries: a statement
+	// fails with a retried serialization failure at DBFaultTransientRate
+	// and with a lasting error at DBFaultPermanentRate, and is delayed by
+	// DBFaultLatency plus up to DBFaultJitter. DBFaultStatements limits
+	// them to statements starting with those words, e.g. "SELECT,BEGIN".
+	// Never turn it on in production.
+	DBFaults             bool          `env:"DB_FAULTS" envDefault:"false"`
+	DBFaultTransientRate float64       `env:"DB_FAULT_TRANSIENT_RATE" envDefault:"0.05"`
+	DBFaultPermanentRate float64       `env:"DB_FAULT_PERMANENT_RATE" envDefault:"0.01"`
+	DBFaultLatency       time.Duration `env:"DB_FAULT_LATENCY" envDefault:"0s"`
+	DBFaultJitter        time.Duration `env:"DB_FAULT_JITTER" envDefault:"50ms"`
+	DBFaultStatements    []string      `env:"DB_FAULT_STATEMENTS"`
+	// OIDCProviderNames are OpenID Connect providers users log in with,
+	// e.g. "authentik,keycloak". Each is configured by
+	// OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and, optionally,
+	// _SCOPES; LoadConfig reads them into OIDCProviders.
+	OIDCProviderNames []string             `env:"OIDC_PROVIDERS"`
+	OIDCProviders     []OIDCProviderConfig `env:"-"`
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string   `env:"ISSUER"`
+	ClientID     string   `env:"CLIENT_ID"`
+	ClientSecret string   `env:"CLIENT_SECRET"`
+	Scopes       []string `env:"SCOPES"`
 }
 
 func LoadConfig() Config {
 	var c Config
 	_ = env.Parse(&c)
+	for _, name := range c.OIDCProviderNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		p := OIDCProviderConfig{Name: name}
+		_ = env.ParseWithOptions(&p, env.Options{Prefix: "OIDC_" + strings.ToUpper(name) + "_"})
+		c.OIDCProviders = append(c.OIDCProviders, p)
+	}
 	return c
 }
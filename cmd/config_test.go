@@ -65,4 +65,27 @@ func TestLoadConfig_PartialEnvironment(t *testing.T) {
 	if cfg.Port != "8080" {
 		t.Errorf("Expected default PORT '8080', got '%s'", cfg.Port)
 	}
-}
\ No newline at end of file
+}
+
+func TestLoadConfig_OIDCProviders(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "Authentik,keycloak")
+	t.Setenv("OIDC_AUTHENTIK_ISSUER", "https://auth.example.com/application/o/assistant/")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_ID", "assistant")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_KEYCLOAK_ISSUER", "https://sso.example.com/realms/home")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_ID", "assistant")
+	t.Setenv("OIDC_KEYCLOAK_SCOPES", "openid,email,groups")
+
+	cfg := LoadConfig()
+
+	if len(cfg.OIDCProviders) != 2 {
+		t.Fatalf("Expected 2 OIDC providers, got %d", len(cfg.OIDCProviders))
+	}
+	authentik, keycloak := cfg.OIDCProviders[0], cfg.OIDCProviders[1]
+	if authentik.Name != "authentik" || authentik.Issuer != "https://auth.example.com/application/o/assistant/" || authentik.ClientSecret != "secret" {
+		t.Errorf("Unexpected authentik provider %+v", authentik)
+	}
+	if keycloak.Name != "keycloak" || keycloak.ClientID != "assistant" || len(keycloak.Scopes) != 3 {
+		t.Errorf("Unexpected keycloak provider %+v", keycloak)
+	}
+}
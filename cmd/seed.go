@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+	"github.com/pbdeuchler/assistant-server/service"
+)
+
+// Seed creates the household in the fixture file at path, or the demo
+// household when path is empty, in the database at cfg.DatabaseURL, which
+// must already be migrated, and writes its users and their API keys to w.
+func Seed(ctx context.Context, cfg Config, path string, w io.Writer) error {
+	set := fixtures.Demo()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if set, err = fixtures.Parse(path, data); err != nil {
+			return err
+		}
+	}
+	// Seeded households belong to the default tenant.
+	ctx = postgres.WithTenant(ctx, postgres.DefaultTenantUID)
+	db, pool, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	res, err := service.Seed(ctx, db, set, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Created household %q (%s) with %d todos, %d notes, %d recipes and %d preferences.\n\n",
+		res.Household.Name, res.Household.UID, res.Todos, res.Notes, res.Recipes, res.Preferences)
+	for _, u := range res.Users {
+		fmt.Fprintf(w, "%s <%s>\n  user_uid: %s\n  API key:  %s\n\n", u.User.Name, u.User.Email, u.User.UID, u.APIKey)
+	}
+	fmt.Fprintf(w, "Point an MCP client at %s/mcp with \"Authorization: Bearer <API key>\".\n", cfg.BaseURL)
+	return nil
+}
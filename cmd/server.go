@@ -2,27 +2,115 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pbdeuchler/assistant-server/barcode"
 	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/llm"
+	"github.com/pbdeuchler/assistant-server/notify"
 	"github.com/pbdeuchler/assistant-server/service"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 )
 
 func Serve(ctx context.Context, cfg Config) error {
-	dbPool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	redactor, err := service.NewRedactor(append(service.DefaultSensitiveKeys, cfg.LogRedactKeys...))
+	if err != nil {
+		return fmt.Errorf("LOG_REDACT_KEYS: %w", err)
+	}
+	service.SetLogRedactor(redactor)
+	slog.SetDefault(slog.New(service.NewRedactingHandler(slog.NewJSONHandler(os.Stdout, nil))))
+
+	// Background work, such as jobs and the usage meter, spans every
+	// tenant. Requests are confined by service.Tenancy.
+	ctx = postgres.WithAllTenants(ctx)
+	db, _, err := openDB(ctx, cfg)
 	if err != nil {
 		return err
 	}
-	db, err := postgres.New(ctx, dbPool)
+
+	// Scheduled work runs as jobs, queued in the database and shown at
+	// /admin/jobs.
+	jobs := service.NewJobs(db, cfg.JobWorkers, cfg.JobPollInterval)
+
+	// Every email and push sent is recorded so failures can be replayed
+	// from /deliveries.
+	deliveries := service.NewDeliveryLog(db)
+	if cfg.SMTPHost != "" {
+		notifier, err := notify.NewSMTP(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			return err
+		}
+		jobs.Register(service.NewDigests(db, db, db, deliveries.Notifier(notifier), cfg.DigestHour).Job())
+	}
+
+	pushers, err := configurePushers(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	var households service.HouseholdNotifier
+	var memberships service.MembershipNotifier
+	if len(pushers) > 0 {
+		push := service.NewPushNotifier(db, db, db, deliveries.Pushers(pushers))
+		households, memberships = push, push
+		jobs.Register(service.NewTodoReminders(db, push, cfg.ReminderInterval).Job())
+		jobs.Register(service.NewNoteReminders(db, push, cfg.ReminderInterval).Job())
+	}
 
-	r := chi.NewRouter()
+	weeklyReviews := service.NewWeeklyReviews(db, households, cfg.WeeklyReviewDay, cfg.WeeklyReviewHour)
+	if cfg.WeeklyReviews {
+		jobs.Register(weeklyReviews.Job())
+	}
+
+	var chat *llm.Chat
+	var summaries *service.NoteSummaries
+	if cfg.LLMURL != "" {
+		chat = llm.NewChat(cfg.LLMURL, cfg.LLMAPIKey, cfg.LLMModel, &http.Client{Timeout: 2 * time.Minute})
+		summaries = service.NewNoteSummaries(db, chat, cfg.NoteSummaryAge, cfg.NoteSummaryInterval)
+		if cfg.NoteSummaryAge > 0 {
+			jobs.Register(summaries.Job())
+		}
+	}
+	jobs.Register(service.NewRetention(db, summaries, cfg.RetentionInterval).Job())
+	scratchpads := service.NewScratchpads(db, cfg.ScratchpadTTL)
+	jobs.Register(scratchpads.Job())
+	for name, spec := range cfg.JobSchedules {
+		schedule, err := service.ParseCron(spec)
+		if err != nil {
+			return fmt.Errorf("JOB_SCHEDULES: %s: %w", name, err)
+		}
+		if err := jobs.SetSchedule(name, schedule); err != nil {
+			return fmt.Errorf("JOB_SCHEDULES: %w", err)
+		}
+	}
+	go jobs.Run(ctx)
+
+	tagger, err := configureTagger(cfg, chat)
+	if err != nil {
+		return err
+	}
+	groceries, err := configureGroceryClassifier(cfg, chat)
+	if err != nil {
+		return err
+	}
+
+	r := newRouter(cfg)
 
 	// Auth endpoints (unprotected)
 	authConfig := service.AuthConfig{
@@ -31,23 +119,387 @@ func Serve(ctx context.Context, cfg Config) error {
 		GCloudProjectID:    cfg.GCloudProjectID,
 		BaseURL:            cfg.BaseURL,
 	}
-	r.Mount("/oauth", service.NewAuthHandlers(authConfig, db))
+	for _, p := range cfg.OIDCProviders {
+		if p.Issuer == "" || p.ClientID == "" || p.ClientSecret == "" {
+			name := strings.ToUpper(p.Name)
+			return fmt.Errorf("OIDC provider %s needs OIDC_%s_ISSUER, OIDC_%s_CLIENT_ID and OIDC_%s_CLIENT_SECRET", p.Name, name, name, name)
+		}
+		authConfig.OIDCProviders = append(authConfig.OIDCProviders, service.OIDCProvider{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			Scopes:       p.Scopes,
+		})
+	}
+	// Linking an OIDC account needs the API key of the user it is linked to.
+	r.With(service.APIKeyAuth(db, false)).Mount("/oauth", service.NewAuthHandlers(authConfig, db))
 
 	// API endpoints (can be protected with JWT middleware if needed)
 	// To protect routes, uncomment the following line:
 	// r.Use(service.JWTMiddleware([]byte(cfg.JWTSecret)))
 
-	r.Mount("/todos", service.NewTodos(db))
-	r.Mount("/preferences", service.NewPreferences(db))
-	r.Mount("/notes", service.NewNotes(db))
-	r.Mount("/recipes", service.NewRecipes(db))
-	r.Mount("/bootstrap", service.NewBootstrap(db))
-	r.Mount("/mcp", service.NewMCPRouter(db, db, db, db, db, db))
+	// Requests and tool calls are counted per API key, for /me/usage and
+	// /admin/api-usage.
+	meter := service.NewUsageMeter(db, cfg.APIUsageFlushInterval)
+	go meter.Run(ctx)
+	keys := meter.Keys(db)
+	var policy *service.Policy
+	if cfg.AuthzPolicyFile != "" {
+		if policy, err = service.LoadPolicy(cfg.AuthzPolicyFile); err != nil {
+			return fmt.Errorf("AUTHZ_POLICY_FILE: %w", err)
+		}
+		if cfg.AuthzDryRun {
+			policy.Mode = service.PolicyDryRun
+		}
+	}
+	api := restRouter(r, keys, policy)
+	if cfg.CompressResponses {
+		api = api.With(service.Compress(cfg.CompressMinSize))
+	}
+	// Writes through the REST API and MCP tools are pushed to household
+	// dashboards connected to /ws.
+	events := service.NewEventHub()
+	api = api.With(events.Track, service.Expand(db))
+	r.Handle("/ws", service.NewWebSocket(events, r, db))
+	// Slack signs its interaction requests, so they need no API key.
+	if cfg.SlackSigningSecret != "" {
+		r.Mount("/slack", service.NewSlackInteractions(db, cfg.SlackSigningSecret, events))
+	}
+	// Capabilities still being rolled out are gated per household; see
+	// /feature-flags.
+	flags := service.NewFeatureFlags(db, cfg.FeatureFlagTTL)
+
+	api.Mount("/todos", service.NewTodos(db))
+	api.Mount("/todo-templates", service.NewTodoTemplates(db))
+	api.Mount("/preferences", service.NewPreferences(db, service.WithPreferenceSchemas(db)))
+	api.Mount("/data-schemas", service.NewDataSchemas(db))
+	api.Mount("/notification-preferences", service.NewNotificationPreferences(db))
+	api.Mount("/weekly-reviews", service.NewWeeklyReviewHandler(weeklyReviews))
+	notesOpts := []service.NotesOption{service.WithNoteSchemas(db), service.WithNoteLocks(db)}
+	recipesOpts := []service.RecipesOption{service.WithRecipeUnits(db)}
+	if tagger != nil {
+		notesOpts = append(notesOpts, service.WithNoteTagger(tagger))
+		recipesOpts = append(recipesOpts, service.WithRecipeTagger(tagger))
+	}
+	if chat != nil {
+		notesOpts = append(notesOpts, service.WithNoteTodoExtraction(chat, db))
+	}
+	if cfg.NoteShareSecret != "" {
+		secret := []byte(cfg.NoteShareSecret)
+		notesOpts = append(notesOpts, service.WithNoteSharing(secret, cfg.BaseURL, cfg.NoteShareTTL))
+		r.Mount("/shared/notes", service.NewSharedNotes(db, secret))
+	}
+	// Public dashboards are opened by their token alone, like shared notes.
+	r.Mount("/public", service.NewPublicDashboard(db, db, db))
+	api.Mount("/notes", service.NewNotes(db, notesOpts...))
+	api.Mount("/recipes", service.NewRecipes(db, recipesOpts...))
+	api.With(flags.Require(service.FlagSearch)).Mount("/search", service.NewSearch(db))
+	pantryOpts := []service.PantryOption{service.WithPantryClassifier(groceries)}
+	if cfg.BarcodeLookupURL != "" {
+		pantryOpts = append(pantryOpts, service.WithBarcodeLookup(barcode.NewOpenFoodFacts(cfg.BarcodeLookupURL, &http.Client{Timeout: 10 * time.Second})))
+	}
+	api.Mount("/pantry", service.NewPantry(db, pantryOpts...))
+	api.Mount("/grocery-purchases", service.NewGroceryPurchases(db))
+	api.Mount("/grocery-stores", service.NewGroceryStores(db))
+	api.Mount("/unit-preferences", service.NewUnitPreferences(db))
+	api.Mount("/bootstrap", service.NewBootstrap(db, service.WithPromptBudget(cfg.BootstrapPromptBudget)))
+	api.Mount("/api-keys", service.NewAPIKeys(db))
+	api.Mount("/dashboard-tokens", service.NewDashboardTokens(db))
+	api.Mount("/devices", service.NewDevices(db))
+	api.Mount("/away", service.NewAway(db))
+	api.Mount("/my-day", service.NewMyDay(db, db))
+	// /me is whoever the API key belongs to, so it needs one.
+	api.With(service.APIKeyAuth(keys, true)).Mount("/me", service.NewMe(db, db, db, db, service.WithMeUsage(db)))
+	api.Mount("/users", service.NewUserAvatars(db))
+	api.Mount("/important-dates", service.NewImportantDates(db))
+	api.With(service.APIKeyAuth(keys, true)).Mount("/sync", service.NewSync(db, db, db))
+	api.Mount("/stats", service.NewStats(db))
+	api.Mount("/projects", service.NewProjects(db))
+	api.Mount("/backgrounds", service.NewBackgrounds(db))
+	api.Mount("/tool-policies", service.NewToolPolicies(db))
+	api.Mount("/tenants", service.NewTenants(db))
+	// Runtime and DAO retry counters, as expvar JSON. With an admin port
+	// they are served there instead.
+	if cfg.AdminPort == "" {
+		api.Handle("/debug/vars", expvar.Handler())
+	}
+	api.Mount("/retention-policies", service.NewRetentionPolicies(db))
+	api.Mount("/quotas", service.NewQuotas(db))
+	api.Mount("/feature-flags", service.NewFeatureFlagsAdmin(flags))
+	api.Mount("/join-requests", service.NewJoinRequests(db, memberships))
+	api.Mount("/deliveries", service.NewDeliveries(deliveries))
+	api.Mount("/admin/jobs", service.NewJobsAdmin(jobs))
+	api.Mount("/admin/api-usage", service.NewAPIUsageAdmin(db))
+	if cfg.UsageStats {
+		api.Mount("/admin/usage", service.NewUsage(db, map[string]bool{
+			"email_digests":       cfg.SMTPHost != "",
+			"push_reminders":      len(pushers) > 0,
+			"slack_interactions":  cfg.SlackSigningSecret != "",
+			"llm":                 chat != nil,
+			"note_summaries":      chat != nil && cfg.NoteSummaryAge > 0,
+			"weekly_reviews":      cfg.WeeklyReviews,
+			"auto_tagger":         cfg.AutoTagger != "",
+			"barcode_lookup":      cfg.BarcodeLookupURL != "",
+			"recipe_refresh":      cfg.RecipeRefresh,
+			"google_oauth":        cfg.GCloudClientID != "",
+			"oidc_login":          len(cfg.OIDCProviders) > 0,
+			"note_sharing":        cfg.NoteShareSecret != "",
+			"authz_policy":        cfg.AuthzPolicyFile != "",
+			"mcp_require_api_key": cfg.MCPRequireAPIKey,
+			"cors":                len(cfg.CORSAllowedOrigins) > 0,
+			"db_faults":           cfg.DBFaults,
+		}))
+	}
+	mcpOpts := []service.MCPOption{
+		service.WithAPIKeys(keys, cfg.MCPRequireAPIKey),
+		service.WithUsageMeter(meter),
+		service.WithLocks(db),
+		service.WithScratchpads(scratchpads),
+		service.WithBackgroundDAO(db),
+		service.WithTodoTemplates(db),
+		service.WithPantry(db),
+		service.WithGroceryPurchases(db),
+		service.WithAway(db),
+		service.WithHouseholdMembers(db),
+		service.WithImportantDates(db),
+		service.WithMyDay(db),
+		service.WithProjects(db),
+		service.WithDataSchemas(db),
+		service.WithTagger(tagger),
+		service.WithGroceryClassifier(groceries),
+		service.WithCalendars(db, &oauth2.Config{
+			ClientID:     cfg.GCloudClientID,
+			ClientSecret: cfg.GCloudClientSecret,
+			Endpoint:     google.Endpoint,
+		}),
+		service.WithToolsPageSize(cfg.MCPToolsPageSize),
+		service.WithSessionTTL(cfg.MCPSessionTTL),
+		service.WithMaxSessions(cfg.MCPMaxSessions),
+		service.WithElicitationTimeout(cfg.MCPElicitationTimeout),
+		service.WithAuthorizationPolicy(policy),
+		service.WithEvents(events),
+		service.WithToolCache(cfg.MCPToolCacheTTL),
+		service.WithFeatureFlags(flags),
+		service.WithExpansions(db),
+	}
+	if cfg.RecipeRefresh {
+		mcpOpts = append(mcpOpts, service.WithRecipeRefresh(service.NewPublicHTTPClient(30*time.Second)))
+	}
+	if chat != nil {
+		mcpOpts = append(mcpOpts, service.WithTodoExtraction(chat, db))
+	}
+	for tool, name := range cfg.MCPConfirmationPolicies {
+		policy, err := service.ParseConfirmationPolicy(name)
+		if err != nil {
+			return fmt.Errorf("MCP_CONFIRMATION_POLICIES: %s: %w", tool, err)
+		}
+		mcpOpts = append(mcpOpts, service.WithConfirmationPolicy(tool, policy))
+	}
+	r.Mount("/mcp", service.NewMCPRouter(db, db, db, db, db, db, mcpOpts...))
 
 	addr := fmt.Sprintf("0.0.0.0:%s", cfg.Port)
 	log.Printf("Starting server on %s", addr)
+	servers := []*http.Server{{Addr: addr, Handler: r}}
+	if cfg.AdminPort != "" {
+		if cfg.AdminPort == cfg.Port {
+			return errors.New("ADMIN_PORT must differ from PORT")
+		}
+		adminAddr := fmt.Sprintf("0.0.0.0:%s", cfg.AdminPort)
+		log.Printf("Serving admin endpoints on %s", adminAddr)
+		servers = append(servers, &http.Server{Addr: adminAddr, Handler: service.NewAdmin(db, cfg.AdminToken)})
+	}
+	return listenAndServe(ctx, servers...)
+}
+
+// listenAndServe runs servers until ctx is done or one of them fails,
+// then shuts them all down and returns the first error.
+// newRouter returns the root router. Callers presenting OPERATOR_TOKEN act
+// as operators, and only they see every tenant; everyone else is confined to
+// the default tenant until an API key says otherwise.
+func newRouter(cfg Config) *chi.Mux {
+	r := chi.NewRouter()
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		r.Use(service.CORS(service.CORSConfig{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}))
+	}
+	r.Use(service.OperatorAuth(cfg.OperatorToken), service.Tenancy)
+	return r
+}
+
+// apiKeys is what APIKeyAuth looks keys up in.
+type apiKeys interface {
+	CreateAPIKey(ctx context.Context, k postgres.APIKeys) (postgres.APIKeys, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (postgres.APIKeys, error)
+	ListAPIKeysByUserUID(ctx context.Context, userUID string) ([]postgres.APIKeys, error)
+	RevokeAPIKey(ctx context.Context, uid string) error
+	TouchAPIKey(ctx context.Context, uid string) error
+}
+
+// restRouter returns r as the REST API sees it. Every REST caller that sends
+// an API key is identified by it, which confines them to the key's tenant
+// and household; with a policy, they are then checked against it before any
+// handler runs.
+func restRouter(r chi.Router, keys apiKeys, policy *service.Policy) chi.Router {
+	api := r.With(service.APIKeyAuth(keys, false))
+	if policy != nil {
+		api = api.With(policy.Middleware())
+	}
+	return api
+}
+
+func listenAndServe(ctx context.Context, servers ...*http.Server) error {
+	errs := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() { errs <- srv.ListenAndServe() }()
+	}
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	for _, srv := range servers {
+		_ = srv.Shutdown(context.Background())
+	}
+	if err == nil {
+		err = <-errs
+	}
+	return err
+}
 
-	srv := &http.Server{Addr: addr, Handler: r}
-	go func() { <-ctx.Done(); _ = srv.Shutdown(context.Background()) }()
-	return srv.ListenAndServe()
+// openDB connects to DATABASE_URL and wraps the pool in a DAO with the
+// configured timeouts, retries and slow query log, and any DB_FAULTS.
+func openDB(ctx context.Context, cfg Config) (*postgres.DAO, *pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	postgres.ConfigureTenancy(poolConfig)
+	postgres.ConfigureStatementTimeout(poolConfig, cfg.DBStatementTimeout)
+	dbPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opts []postgres.Option
+	if cfg.DBFaults {
+		faults, err := configureFaults(cfg)
+		if err != nil {
+			dbPool.Close()
+			return nil, nil, err
+		}
+		// Beneath the retries, so transient faults are retried.
+		opts = append(opts, postgres.WithFaults(faults))
+	}
+	opts = append(opts, postgres.WithResilience(postgres.Resilience{
+		ReadTimeout:  cfg.DBReadTimeout,
+		WriteTimeout: cfg.DBWriteTimeout,
+		MaxRetries:   cfg.DBMaxRetries,
+		RetryDelay:   cfg.DBRetryDelay,
+	}), postgres.WithSlowQueryLog(cfg.DBSlowQueryThreshold))
+	db, err := postgres.New(ctx, dbPool, opts...)
+	if err != nil {
+		dbPool.Close()
+		return nil, nil, err
+	}
+	return db, dbPool, nil
+}
+
+// configureFaults returns the database faults DB_FAULTS injects, and warns
+// that they are on.
+func configureFaults(cfg Config) (postgres.Faults, error) {
+	f := postgres.Faults{
+		TransientRate: cfg.DBFaultTransientRate,
+		PermanentRate: cfg.DBFaultPermanentRate,
+		Latency:       cfg.DBFaultLatency,
+		Jitter:        cfg.DBFaultJitter,
+		Statements:    cfg.DBFaultStatements,
+	}
+	if f.TransientRate < 0 || f.PermanentRate < 0 || f.TransientRate+f.PermanentRate > 1 {
+		return f, errors.New("DB_FAULT_TRANSIENT_RATE and DB_FAULT_PERMANENT_RATE must be at least 0 and add up to at most 1")
+	}
+	if f.Latency < 0 || f.Jitter < 0 {
+		return f, errors.New("DB_FAULT_LATENCY and DB_FAULT_JITTER must not be negative")
+	}
+	slog.Warn("Injecting database faults; never do this in production",
+		"transient_rate", f.TransientRate, "permanent_rate", f.PermanentRate,
+		"latency", f.Latency, "jitter", f.Jitter, "statements", f.Statements)
+	return f, nil
+}
+
+// configureTagger returns the Tagger AUTO_TAGGER names, or nil when it is
+// empty. The "llm" tagger needs LLM_URL.
+func configureTagger(cfg Config, chat *llm.Chat) (service.Tagger, error) {
+	switch cfg.AutoTagger {
+	case "":
+		return nil, nil
+	case "keywords":
+		return service.NewKeywordTagger(cfg.AutoTagRules), nil
+	case "llm":
+		if chat == nil {
+			return nil, errors.New("AUTO_TAGGER=llm needs LLM_URL")
+		}
+		return chat, nil
+	default:
+		return nil, fmt.Errorf("AUTO_TAGGER: unknown tagger %q", cfg.AutoTagger)
+	}
+}
+
+// configureGroceryClassifier returns the GroceryClassifier
+// GROCERY_CLASSIFIER names. The "llm" classifier needs LLM_URL.
+func configureGroceryClassifier(cfg Config, chat *llm.Chat) (service.GroceryClassifier, error) {
+	rules, err := service.NewGroceryRules(cfg.GroceryRules)
+	if err != nil {
+		return nil, fmt.Errorf("GROCERY_RULES: %w", err)
+	}
+	switch cfg.GroceryClassifier {
+	case "rules":
+		return rules, nil
+	case "llm":
+		if chat == nil {
+			return nil, errors.New("GROCERY_CLASSIFIER=llm needs LLM_URL")
+		}
+		return service.NewGroceryClassifier(rules, chat), nil
+	default:
+		return nil, fmt.Errorf("GROCERY_CLASSIFIER: unknown classifier %q", cfg.GroceryClassifier)
+	}
+}
+
+// configurePushers connects to the push services that are configured.
+func configurePushers(ctx context.Context, cfg Config) (map[string]notify.Pusher, error) {
+	out := map[string]notify.Pusher{}
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("APNS_KEY_FILE: %w", err)
+		}
+		apns, err := notify.NewAPNs(notify.APNsConfig{
+			KeyID:      cfg.APNsKeyID,
+			TeamID:     cfg.APNsTeamID,
+			Topic:      cfg.APNsTopic,
+			PrivateKey: key,
+			Sandbox:    cfg.APNsSandbox,
+		}, &http.Client{Timeout: 30 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+		out[notify.PlatformAPNs] = apns
+	}
+	if cfg.FCMCredentialsFile != "" {
+		creds, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("FCM_CREDENTIALS_FILE: %w", err)
+		}
+		fcm, err := notify.NewFCM(ctx, creds)
+		if err != nil {
+			return nil, err
+		}
+		out[notify.PlatformFCM] = fcm
+	}
+	if cfg.SlackBotToken != "" {
+		out[notify.PlatformSlack] = notify.NewSlack(cfg.SlackBotToken, &http.Client{Timeout: 30 * time.Second})
+	}
+	return out, nil
 }
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/service"
+)
+
+func TestListenAndServe_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- listenAndServe(ctx, &http.Server{Addr: "127.0.0.1:0"}, &http.Server{Addr: "127.0.0.1:0"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Expected http.ErrServerClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("listenAndServe didn't return after cancel")
+	}
+}
+
+func TestListenAndServe_OneFailingStopsAll(t *testing.T) {
+	done := make(chan error)
+	go func() {
+		done <- listenAndServe(context.Background(), &http.Server{Addr: "127.0.0.1:0"}, &http.Server{Addr: "127.0.0.1:-1"})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Expected the admin listener's error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("listenAndServe didn't return when a server failed")
+	}
+}
+
+func TestConfigureFaults(t *testing.T) {
+	cfg := Config{DBFaultTransientRate: 0.2, DBFaultPermanentRate: 0.1, DBFaultJitter: 10 * time.Millisecond, DBFaultStatements: []string{"SELECT"}}
+	f, err := configureFaults(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.TransientRate != 0.2 || f.PermanentRate != 0.1 || f.Jitter != 10*time.Millisecond || len(f.Statements) != 1 {
+		t.Errorf("Unexpected faults %+v", f)
+	}
+
+	for _, bad := range []Config{
+		{DBFaultTransientRate: 0.8, DBFaultPermanentRate: 0.3},
+		{DBFaultTransientRate: -0.1},
+		{DBFaultLatency: -time.Second},
+	} {
+		if _, err := configureFaults(bad); err == nil {
+			t.Errorf("Expected an error for %+v", bad)
+		}
+	}
+}
+
+type fakeKeys struct{ byHash map[string]postgres.APIKeys }
+
+func (f fakeKeys) CreateAPIKey(ctx context.Context, k postgres.APIKeys) (postgres.APIKeys, error) {
+	return k, nil
+}
+
+func (f fakeKeys) GetAPIKeyByHash(ctx context.Context, keyHash string) (postgres.APIKeys, error) {
+	if k, ok := f.byHash[keyHash]; ok {
+		return k, nil
+	}
+	return postgres.APIKeys{}, errors.New("not found")
+}
+
+func (f fakeKeys) ListAPIKeysByUserUID(ctx context.Context, userUID string) ([]postgres.APIKeys, error) {
+	return nil, nil
+}
+
+func (f fakeKeys) RevokeAPIKey(ctx context.Context, uid string) error { return nil }
+
+func (f fakeKeys) TouchAPIKey(ctx context.Context, uid string) error { return nil }
+
+// fakeTenants records the tenant each call was confined to.
+type fakeTenants struct{ confined []string }
+
+func (f *fakeTenants) CreateTenant(ctx context.Context, t postgres.Tenant) (postgres.Tenant, error) {
+	return t, nil
+}
+
+func (f *fakeTenants) GetTenant(ctx context.Context, uid string) (postgres.Tenant, error) {
+	tenant, _ := postgres.TenantFromContext(ctx)
+	f.confined = append(f.confined, tenant)
+	return postgres.Tenant{UID: uid}, nil
+}
+
+func (f *fakeTenants) ListTenants(ctx context.Context) ([]postgres.Tenant, error) {
+	return nil, nil
+}
+
+func TestRESTRouter_ResolvesAPIKeysWithoutPolicy(t *testing.T) {
+	sum := sha256.Sum256([]byte("ak_tenantb"))
+	keys := fakeKeys{byHash: map[string]postgres.APIKeys{
+		hex.EncodeToString(sum[:]): {UID: "key-1", UserUID: "user-1", TenantUID: "tenant-b"},
+	}}
+	tenants := &fakeTenants{}
+	r := newRouter(Config{})
+	restRouter(r, keys, nil).Mount("/tenants", service.NewTenants(tenants))
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants", nil)
+	req.Header.Set("Authorization", "Bearer ak_tenantb")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var out []postgres.Tenant
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].UID != "tenant-b" {
+		t.Errorf("Expected only the key's tenant, got %+v", out)
+	}
+	if len(tenants.confined) != 1 || tenants.confined[0] != "tenant-b" {
+		t.Errorf("Expected the lookup to be confined to the key's tenant, got %v", tenants.confined)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants", nil)
+	req.Header.Set("Authorization", "Bearer ak_unknown")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown key, got %d", rec.Code)
+	}
+}
@@ -2,14 +2,32 @@ package postgres
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"expvar"
 	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Priority ranks a todo from PriorityLow to PriorityCritical. It is stored
+// as its number and appears in JSON as its label, e.g. "high"; either is
+// accepted when decoding.
 type Priority uint8
 
 const (
@@ -19,30 +37,468 @@ const (
 	PriorityCritical
 )
 
+// ErrInvalidPriority is returned for a priority that isn't one of the
+// Priority constants.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+var priorityLabels = [...]string{PriorityLow: "low", PriorityMedium: "medium", PriorityHigh: "high", PriorityCritical: "critical"}
+
+// ParsePriority reads a priority's label, in any case, or its number.
+func ParsePriority(s string) (Priority, error) {
+	for p, label := range priorityLabels {
+		if label != "" && (strings.EqualFold(s, label) || s == strconv.Itoa(p)) {
+			return Priority(p), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q: must be low, medium, high or critical (1-4)", ErrInvalidPriority, s)
+}
+
+func (p Priority) Valid() bool { return p >= PriorityLow && p <= PriorityCritical }
+
+func (p Priority) String() string {
+	if !p.Valid() {
+		return strconv.Itoa(int(p))
+	}
+	return priorityLabels[p]
+}
+
+// Value stores the number; without it pgx would send String.
+func (p Priority) Value() (driver.Value, error) { return int64(p), nil }
+
+// MarshalJSON writes the label, or null for the zero Priority.
+func (p Priority) MarshalJSON() ([]byte, error) {
+	if p == 0 {
+		return []byte("null"), nil
+	}
+	if !p.Valid() {
+		return nil, fmt.Errorf("%w %d", ErrInvalidPriority, p)
+	}
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(data, &s) != nil {
+		s = string(data)
+	}
+	parsed, err := ParsePriority(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// TodoStatus is where a todo is on the board, from TodoBacklog to TodoDone.
+// A todo is TodoDone exactly when MarkedComplete is set, so completing a
+// todo either way sets both.
+type TodoStatus string
+
+const (
+	TodoBacklog    TodoStatus = "backlog"
+	TodoPlanned    TodoStatus = "planned"
+	TodoInProgress TodoStatus = "in_progress"
+	TodoBlocked    TodoStatus = "blocked"
+	TodoDone       TodoStatus = "done"
+)
+
+// TodoStatuses are the statuses in board order.
+var TodoStatuses = []TodoStatus{TodoBacklog, TodoPlanned, TodoInProgress, TodoBlocked, TodoDone}
+
+// ErrInvalidStatus is returned for a status that isn't one of TodoStatuses.
+var ErrInvalidStatus = errors.New("invalid status")
+
+// ParseTodoStatus reads a status in any case, with spaces or hyphens for
+// underscores, so "In progress" is TodoInProgress.
+func ParseTodoStatus(s string) (TodoStatus, error) {
+	status := TodoStatus(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(s))))
+	if !status.Valid() {
+		return "", fmt.Errorf("%w %q: must be backlog, planned, in_progress, blocked or done", ErrInvalidStatus, s)
+	}
+	return status, nil
+}
+
+func (s TodoStatus) Valid() bool { return slices.Contains(TodoStatuses, s) }
+
+func (s *TodoStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, data)
+	}
+	if raw == "" {
+		*s = ""
+		return nil
+	}
+	parsed, err := ParseTodoStatus(raw)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
 type Todo struct {
-	UID            string     `json:"uid" db:"uid"`
-	Title          string     `json:"title" db:"title"`
-	Description    string     `json:"description" db:"description"`
-	Data           string     `json:"data" db:"data"`
-	Priority       Priority   `json:"priority" db:"priority"`
-	DueDate        *time.Time `json:"due_date" db:"due_date"`
-	RecursOn       string     `json:"recurs_on" db:"recurs_on"`
-	MarkedComplete *time.Time `json:"marked_complete" db:"marked_complete"`
-	ExternalURL    string     `json:"external_url" db:"external_url"`
-	UserUID        *string    `json:"user_uid" db:"user_uid"`
-	HouseholdUID   *string    `json:"household_uid" db:"household_uid"`
-	CompletedBy    string     `json:"completed_by" db:"completed_by"`
-	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	UID            string        `json:"uid" db:"uid"`
+	Title          string        `json:"title" db:"title"`
+	Description    string        `json:"description" db:"description"`
+	Data           string        `json:"data" db:"data"`
+	Priority       Priority      `json:"priority" db:"priority"`
+	Status         TodoStatus    `json:"status" db:"status"`
+	DueDate        *time.Time    `json:"due_date" db:"due_date"`
+	RecursOn       string        `json:"recurs_on" db:"recurs_on"`
+	MarkedComplete *time.Time    `json:"marked_complete" db:"marked_complete"`
+	ExternalURL    string        `json:"external_url" db:"external_url"`
+	UserUID        *string       `json:"user_uid" db:"user_uid"`
+	HouseholdUID   *string       `json:"household_uid" db:"household_uid"`
+	CompletedBy    *string       `json:"completed_by" db:"completed_by"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+	Location       *TodoLocation `json:"location,omitempty" db:"location"`
+	ProjectUID     *string       `json:"project_uid" db:"project_uid"`
+	Tags           []string      `json:"tags" db:"tags"`
+	// EstimateMinutes is how long the todo is expected to take, if anyone
+	// said; LoggedMinutes is the time logged against it so far.
+	EstimateMinutes *int `json:"estimate_minutes" db:"estimate_minutes"`
+	LoggedMinutes   int  `json:"logged_minutes" db:"logged_minutes"`
+	// Completer is the CompletedBy user, read along with the todo.
+	Completer *TodoCompleter `json:"completer,omitempty" db:"completer"`
+}
+
+// TodoCompleter is who completed a todo, so responses can say without
+// another lookup.
+type TodoCompleter struct {
+	UID   string `json:"uid"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Project groups a household's todos. DoneTodos and TotalTodos count its
+// completed todos and all of them, read along with it.
+type Project struct {
+	UID          string    `json:"uid" db:"uid"`
+	Name         string    `json:"name" db:"name"`
+	Description  string    `json:"description" db:"description"`
+	HouseholdUID string    `json:"household_uid" db:"household_uid"`
+	Archived     bool      `json:"archived" db:"archived"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	DoneTodos    int       `json:"done_todos" db:"done_todos"`
+	TotalTodos   int       `json:"total_todos" db:"total_todos"`
 }
 
-type Background struct {
-	Key       string    `json:"key" db:"key"`
-	Value     string    `json:"value" db:"value"`
+type UpdateProject struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Archived    *bool   `json:"archived"`
+}
+
+// ErrUnknownProject is returned when a todo is put in a project that
+// doesn't exist.
+var ErrUnknownProject = errors.New("project_uid is not a known project")
+
+// ErrProjectArchived is returned when todos are moved into an archived
+// project.
+var ErrProjectArchived = errors.New("project is archived")
+
+// ErrInvalidEstimate is returned for a negative EstimateMinutes.
+var ErrInvalidEstimate = errors.New("estimate_minutes must not be negative")
+
+// ErrUnknownCompleter is returned when a todo's CompletedBy isn't a user.
+var ErrUnknownCompleter = errors.New("completed_by is not a known user")
+
+// TodoLocation is where a todo can be done. RadiusM, in metres, widens the
+// spot into a geofence, e.g. a whole shopping centre rather than its
+// entrance.
+type TodoLocation struct {
+	Name    string  `json:"name,omitempty"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	RadiusM float64 `json:"radius_m,omitempty"`
+}
+
+// TodoTemplate is a reusable checklist, such as a packing list, that can be
+// turned into concrete todos in one go.
+type TodoTemplate struct {
+	UID          string         `json:"uid" db:"uid"`
+	Name         string         `json:"name" db:"name"`
+	Description  string         `json:"description" db:"description"`
+	Items        []TemplateItem `json:"items" db:"items"`
+	UserUID      *string        `json:"user_uid" db:"user_uid"`
+	HouseholdUID *string        `json:"household_uid" db:"household_uid"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateItem becomes one todo. Title and Description may contain {{param}}
+// placeholders; DueInDays, when set, is counted from the day the template is
+// applied.
+type TemplateItem struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Priority    Priority `json:"priority,omitempty"`
+	DueInDays   *int     `json:"due_in_days,omitempty"`
+}
+
+type UpdateTodoTemplate struct {
+	Name        *string        `json:"name"`
+	Description *string        `json:"description"`
+	Items       []TemplateItem `json:"items"`
+}
+
+// PantryItem is something a household has in stock. Quantity is nil for
+// items tracked only by presence; Unit is empty for a plain count. Category
+// is the grocery aisle it is shelved in, e.g. "produce".
+type PantryItem struct {
+	UID          string     `json:"uid" db:"uid"`
+	HouseholdUID string     `json:"household_uid" db:"household_uid"`
+	Item         string     `json:"item" db:"item"`
+	Quantity     *float64   `json:"quantity" db:"quantity"`
+	Unit         string     `json:"unit" db:"unit"`
+	Category     string     `json:"category" db:"category"`
+	ExpiresOn    *time.Time `json:"expires_on" db:"expires_on"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type UpdatePantryItem struct {
+	Item      *string    `json:"item"`
+	Quantity  *float64   `json:"quantity"`
+	Unit      *string    `json:"unit"`
+	Category  *string    `json:"category"`
+	ExpiresOn *time.Time `json:"expires_on"`
+}
+
+// GroceryPurchase is a shopping list item that was bought, with what it cost
+// and where. PriceCents covers the whole line, not a unit price.
+type GroceryPurchase struct {
+	UID          string    `json:"uid" db:"uid"`
+	HouseholdUID string    `json:"household_uid" db:"household_uid"`
+	Item         string    `json:"item" db:"item"`
+	Quantity     string    `json:"quantity" db:"quantity"`
+	Store        string    `json:"store" db:"store"`
+	PriceCents   int       `json:"price_cents" db:"price_cents"`
+	PurchasedOn  time.Time `json:"purchased_on" db:"purchased_on"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Device is a phone or tablet registered for push notifications. Platform
+// is "apns" or "fcm" and Token is that service's device token.
+type Device struct {
+	UID       string    `json:"uid" db:"uid"`
+	UserUID   string    `json:"user_uid" db:"user_uid"`
+	Platform  string    `json:"platform" db:"platform"`
+	Token     string    `json:"token" db:"token"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Delivery statuses.
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+)
+
+// Delivery is one message handed to an outbound channel: "email", "apns"
+// or "fcm". Recipient is the email address or device token, Payload what
+// was sent and PayloadHash its SHA-256 in hex. Attempts counts the first
+// send and every replay; LastError is why the latest one failed.
+type Delivery struct {
+	UID         string          `json:"uid" db:"uid"`
+	Channel     string          `json:"channel" db:"channel"`
+	Recipient   string          `json:"recipient" db:"recipient"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	PayloadHash string          `json:"payload_hash" db:"payload_hash"`
+	Status      string          `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	LastError   *string         `json:"last_error" db:"last_error"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Job run statuses. A failed attempt goes back to JobQueued until the run
+// has had its MaxAttempts.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// JobRun is one run of a background job, queued for RunAt. Scheduled runs
+// have a DedupeKey so they are only queued once. Attempts counts the times
+// it was started.
+type JobRun struct {
+	UID         string          `json:"uid" db:"uid"`
+	Name        string          `json:"name" db:"name"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	DedupeKey   *string         `json:"dedupe_key" db:"dedupe_key"`
+	Status      string          `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"`
+	StartedAt   *time.Time      `json:"started_at" db:"started_at"`
+	FinishedAt  *time.Time      `json:"finished_at" db:"finished_at"`
+	LastError   *string         `json:"last_error" db:"last_error"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// UsageStats describes the database's tables and indexes, from PostgreSQL's
+// cumulative statistics, without reading any rows. Scan counts are since
+// StatsReset, or since the statistics were created when it is nil.
+type UsageStats struct {
+	StatsReset *time.Time   `json:"stats_reset"`
+	Tables     []TableUsage `json:"tables"`
+	Indexes    []IndexUsage `json:"indexes"`
+}
+
+// TableUsage is a table's size and how it is read. Rows and DeadRows are
+// PostgreSQL's running estimates; DeadRows are those awaiting vacuum.
+type TableUsage struct {
+	Name       string `json:"name"`
+	Rows       int64  `json:"rows"`
+	DeadRows   int64  `json:"dead_rows"`
+	TotalBytes int64  `json:"total_bytes"`
+	IndexBytes int64  `json:"index_bytes"`
+	SeqScans   int64  `json:"seq_scans"`
+	IndexScans int64  `json:"index_scans"`
+}
+
+type IndexUsage struct {
+	Table string `json:"table"`
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+	Scans int64  `json:"scans"`
+}
+
+// SearchHit is a row matching a full-text search. Snippet is HTML: the
+// passages of the row's body around the matches, with each match in <mark>
+// and everything else escaped. Rank orders hits of the same entity; a
+// match in the title counts for more than one in the body.
+type SearchHit struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Snippet      string    `json:"snippet"`
+	Rank         float32   `json:"rank"`
+	UserUID      *string   `json:"user_uid"`
+	HouseholdUID *string   `json:"household_uid"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// AwayPeriod is a stretch of days, StartsOn to EndsOn inclusive, when a
+// user is away. UserName is filled in from the user when listing.
+type AwayPeriod struct {
+	UID       string    `json:"uid" db:"uid"`
+	UserUID   string    `json:"user_uid" db:"user_uid"`
+	UserName  string    `json:"user_name,omitempty" db:"-"`
+	StartsOn  time.Time `json:"starts_on" db:"starts_on"`
+	EndsOn    time.Time `json:"ends_on" db:"ends_on"`
+	Note      string    `json:"note" db:"note"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// ImportantDate is a date a household wants to be reminded of ahead of
+// time, such as a birthday or a renewal. Date is the first occurrence, at
+// midnight UTC; Recurrence says how it repeats.
+type ImportantDate struct {
+	UID             string    `json:"uid" db:"uid"`
+	HouseholdUID    string    `json:"household_uid" db:"household_uid"`
+	Title           string    `json:"title" db:"title"`
+	Kind            string    `json:"kind" db:"kind"`
+	Date            time.Time `json:"date" db:"date"`
+	Recurrence      string    `json:"recurrence" db:"recurrence"`
+	RemindDaysAhead int       `json:"remind_days_ahead" db:"remind_days_ahead"`
+	Notes           string    `json:"notes" db:"notes"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Important date kinds.
+const (
+	DateBirthday    = "birthday"
+	DateAnniversary = "anniversary"
+	DateRenewal     = "renewal"
+	DateOther       = "other"
+)
+
+// Important date recurrences.
+const (
+	RecursYearly  = "yearly"
+	RecursMonthly = "monthly"
+	RecursNever   = "none"
+)
+
+// RetentionPolicy caps how long one kind of record is kept, for one
+// household or, without a HouseholdUID, for every household. Records older
+// than MaxAgeDays are archived, deleted or summarized, as Action says.
+type RetentionPolicy struct {
+	UID          string    `json:"uid" db:"uid"`
+	Entity       string    `json:"entity" db:"entity"`
+	HouseholdUID *string   `json:"household_uid" db:"household_uid"`
+	MaxAgeDays   int       `json:"max_age_days" db:"max_age_days"`
+	Action       string    `json:"action" db:"action"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Entities a retention policy can cover, and what it can do with them.
+const (
+	RetentionEntityNotes            = "notes"
+	RetentionEntityTodos            = "todos"
+	RetentionEntityGroceryPurchases = "grocery_purchases"
+
+	RetentionArchive   = "archive"
+	RetentionDelete    = "delete"
+	RetentionSummarize = "summarize"
+)
+
+// DataSchema is a JSON Schema that the data of every note, or preference,
+// with Key must match.
+type DataSchema struct {
+	Entity    string          `json:"entity" db:"entity"`
+	Key       string          `json:"key" db:"key"`
+	Schema    json.RawMessage `json:"schema" db:"schema"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Entities whose data can have a schema.
+const (
+	DataSchemaNotes       = "notes"
+	DataSchemaPreferences = "preferences"
+)
+
+// GrocerySpend is what a household spent at one store in one month
+// ("2025-08").
+type GrocerySpend struct {
+	Month      string `json:"month"`
+	Store      string `json:"store"`
+	TotalCents int64  `json:"total_cents"`
+	Purchases  int64  `json:"purchases"`
+}
+
+// Background is a piece of context stored under a key. One with an
+// ExpiresAt, such as a scratchpad entry, is no longer read once it passes.
+type Background struct {
+	Key       string     `json:"key" db:"key"`
+	Value     string     `json:"value" db:"value"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// PreferenceKey identifies one preference.
+type PreferenceKey struct {
+	Key       string `json:"key"`
+	Specifier string `json:"specifier"`
+}
+
 type Preferences struct {
 	Key       string    `json:"key" db:"key"`
 	Specifier string    `json:"specifier" db:"specifier"`
@@ -59,10 +515,27 @@ type Notes struct {
 	HouseholdUID *string   `json:"household_uid" db:"household_uid"`
 	Data         string    `json:"data" db:"data"`
 	Tags         []string  `json:"tags" db:"tags"`
+	Visibility   string    `json:"visibility" db:"visibility"`
+	Pinned       bool      `json:"pinned" db:"pinned"`
+	SortOrder    int       `json:"sort_order" db:"sort_order"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	// ArchivedAt is when the note was condensed into a digest note.
+	ArchivedAt *time.Time `json:"archived_at" db:"archived_at"`
+	// RemindAt is when the note's owner is reminded of it, e.g. a month
+	// before the passport it records expires.
+	RemindAt *time.Time `json:"remind_at" db:"remind_at"`
 }
 
+// Note visibility levels. Private notes are only visible to their owner,
+// household notes to every member of the owner's household, and shared-link
+// notes additionally to anyone holding a signed share link.
+const (
+	NoteVisibilityPrivate    = "private"
+	NoteVisibilityHousehold  = "household"
+	NoteVisibilitySharedLink = "shared-link"
+)
+
 type Credentials struct {
 	ID             string          `json:"id" db:"id"`
 	UserUID        string          `json:"user_uid" db:"user_uid"`
@@ -87,13 +560,34 @@ type Users struct {
 	HouseholdUID *string   `json:"household_uid" db:"household_uid"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	Pronouns     string    `json:"pronouns" db:"pronouns"`
+	// Birthday is midnight UTC on the user's date of birth.
+	Birthday *time.Time `json:"birthday" db:"birthday"`
+	Phone    string     `json:"phone" db:"phone"`
+	// AvatarUpdatedAt is set while the user has an avatar.
+	AvatarUpdatedAt *time.Time `json:"avatar_updated_at" db:"avatar_updated_at"`
+}
+
+// UserAvatar is one rendition of a user's avatar: the upload itself
+// ("original") or a thumbnail.
+type UserAvatar struct {
+	UserUID     string    `json:"user_uid" db:"user_uid"`
+	Size        string    `json:"size" db:"size"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Width       int       `json:"width" db:"width"`
+	Height      int       `json:"height" db:"height"`
+	Data        []byte    `json:"-" db:"data"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
 type UpdateUser struct {
-	Name         *string `json:"name"`
-	Email        *string `json:"email"`
-	Description  *string `json:"description"`
-	HouseholdUID *string `json:"household_uid"`
+	Name         *string    `json:"name"`
+	Email        *string    `json:"email"`
+	Description  *string    `json:"description"`
+	HouseholdUID *string    `json:"household_uid"`
+	Pronouns     *string    `json:"pronouns"`
+	Birthday     *time.Time `json:"birthday"`
+	Phone        *string    `json:"phone"`
 }
 
 type UpdateHousehold struct {
@@ -109,24 +603,256 @@ type Households struct {
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// JoinRequest is a user asking to join a household. It stays pending until
+// a member approves it, which makes the user a member, or denies it.
+// HouseholdName and UserName are read along with it, where the caller can
+// see them.
+type JoinRequest struct {
+	UID           string     `json:"uid" db:"uid"`
+	HouseholdUID  string     `json:"household_uid" db:"household_uid"`
+	HouseholdName string     `json:"household_name,omitempty" db:"household_name"`
+	UserUID       string     `json:"user_uid" db:"user_uid"`
+	UserName      string     `json:"user_name,omitempty" db:"user_name"`
+	Status        string     `json:"status" db:"status"`
+	Message       string     `json:"message" db:"message"`
+	DecidedBy     *string    `json:"decided_by" db:"decided_by"`
+	DecidedAt     *time.Time `json:"decided_at" db:"decided_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Join request statuses.
+const (
+	JoinPending  = "pending"
+	JoinApproved = "approved"
+	JoinDenied   = "denied"
+)
+
+// ErrJoinRequestPending is returned when a user asks to join a household
+// they already have an open request for.
+var ErrJoinRequestPending = errors.New("a request to join this household is already pending")
+
+// ErrUnknownHousehold is returned when a user asks to join a household
+// that doesn't exist.
+var ErrUnknownHousehold = errors.New("household_uid is not a known household")
+
+type APIKeys struct {
+	UID          string     `json:"uid" db:"uid"`
+	UserUID      string     `json:"user_uid" db:"user_uid"`
+	HouseholdUID *string    `json:"household_uid" db:"household_uid"`
+	Name         string     `json:"name" db:"name"`
+	KeyHash      string     `json:"-" db:"key_hash"`
+	Scopes       []string   `json:"scopes" db:"scopes"`
+	LastUsedAt   *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	TenantUID    string     `json:"tenant_uid" db:"tenant_uid"`
+}
+
+// APIUsage is how many requests and MCP tool calls one API key made on one
+// UTC day. KeyName, UserName and HouseholdUID are read with it and ignored
+// when adding usage.
+type APIUsage struct {
+	APIKeyUID    string    `json:"api_key_uid" db:"api_key_uid"`
+	KeyName      string    `json:"key_name" db:"key_name"`
+	UserUID      string    `json:"user_uid" db:"user_uid"`
+	UserName     string    `json:"user_name" db:"user_name"`
+	HouseholdUID *string   `json:"household_uid" db:"household_uid"`
+	Day          time.Time `json:"day" db:"day"`
+	Requests     int64     `json:"requests" db:"requests"`
+	ToolCalls    int64     `json:"tool_calls" db:"tool_calls"`
+}
+
+// EntityLock is a short-lived claim on editing an entity, such as a note,
+// held by an API key until ExpiresAt. Holder and KeyName are the names of
+// the key's user and of the key, read with the lock; a lock taken without
+// a key, by an operator, has neither.
+type EntityLock struct {
+	Entity     string    `json:"entity" db:"entity"`
+	EntityID   string    `json:"entity_id" db:"entity_id"`
+	APIKeyUID  *string   `json:"api_key_uid" db:"api_key_uid"`
+	UserUID    *string   `json:"user_uid" db:"user_uid"`
+	Holder     string    `json:"holder" db:"holder"`
+	KeyName    string    `json:"key_name" db:"key_name"`
+	AcquiredAt time.Time `json:"acquired_at" db:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// DashboardToken grants read-only, unauthenticated access to the Sections
+// of a household's public dashboard. Only a hash of the token is stored.
+type DashboardToken struct {
+	UID          string     `json:"uid" db:"uid"`
+	HouseholdUID string     `json:"household_uid" db:"household_uid"`
+	Name         string     `json:"name" db:"name"`
+	TokenHash    string     `json:"-" db:"token_hash"`
+	Sections     []string   `json:"sections" db:"sections"`
+	LastUsedAt   *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	TenantUID    string     `json:"tenant_uid" db:"tenant_uid"`
+}
+
+// SyncServer is the writer that every change made other than through sync
+// counts under in a SyncVersion.
+const SyncServer = "server"
+
+// SyncVersion is a vector timestamp: how many changes each writer, a sync
+// client's ID or SyncServer, has made to an entity.
+type SyncVersion map[string]int64
+
+// Covers reports whether v has seen every change other has.
+func (v SyncVersion) Covers(other SyncVersion) bool {
+	for writer, n := range other {
+		if v[writer] < n {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge returns the version that has seen every change v and other have.
+func (v SyncVersion) Merge(other SyncVersion) SyncVersion {
+	out := SyncVersion{}
+	for writer, n := range v {
+		out[writer] = n
+	}
+	for writer, n := range other {
+		out[writer] = max(out[writer], n)
+	}
+	return out
+}
+
+// SyncState is where a synced todo or note stands in the sync feed. Seq
+// orders the feed; a deleted entity is kept as a tombstone. UpdatedAt is
+// when its latest change was made, which last-writer-wins compares.
+type SyncState struct {
+	Entity       string      `json:"entity" db:"entity"`
+	EntityID     string      `json:"id" db:"entity_id"`
+	HouseholdUID *string     `json:"household_uid" db:"household_uid"`
+	UserUID      *string     `json:"user_uid" db:"user_uid"`
+	Seq          int64       `json:"seq" db:"seq"`
+	Version      SyncVersion `json:"version" db:"version"`
+	Deleted      bool        `json:"deleted" db:"deleted"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// Quota caps how much one household, or without a HouseholdUID every
+// household, may store. A household's own quota replaces the default
+// entirely rather than adding to it. A nil cap is no cap.
+type Quota struct {
+	UID           string    `json:"uid" db:"uid"`
+	HouseholdUID  *string   `json:"household_uid" db:"household_uid"`
+	MaxTodos      *int      `json:"max_todos" db:"max_todos"`
+	MaxNotes      *int      `json:"max_notes" db:"max_notes"`
+	MaxRecipes    *int      `json:"max_recipes" db:"max_recipes"`
+	MaxPhotoBytes *int64    `json:"max_photo_bytes" db:"max_photo_bytes"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// QuotaUsage is how much a household stores, counting its members'
+// personal rows, against the quota that applies to it.
+type QuotaUsage struct {
+	HouseholdUID string `json:"household_uid"`
+	Todos        int64  `json:"todos"`
+	Notes        int64  `json:"notes"`
+	Recipes      int64  `json:"recipes"`
+	PhotoBytes   int64  `json:"photo_bytes"`
+	Quota        *Quota `json:"quota"`
+}
+
+// FeatureFlag turns a capability on for some households: all of them when
+// Enabled, those in HouseholdUIDs, and RolloutPercent of the rest, chosen
+// by a stable hash so a household stays in or out as the percentage grows.
+type FeatureFlag struct {
+	UID            string    `json:"uid" db:"uid"`
+	Name           string    `json:"name" db:"name"`
+	Description    string    `json:"description" db:"description"`
+	Enabled        bool      `json:"enabled" db:"enabled"`
+	RolloutPercent int       `json:"rollout_percent" db:"rollout_percent"`
+	HouseholdUIDs  []string  `json:"household_uids" db:"household_uids"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ErrQuotaExceeded is returned when a write would take a household over
+// its quota. The error's message says which cap and what to do about it.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// quotaErr turns the error the enforce_quota trigger raises into
+// ErrQuotaExceeded, keeping its message.
+func quotaErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "53400" {
+		return fmt.Errorf("%w: %s", ErrQuotaExceeded, pgErr.Message)
+	}
+	return err
+}
+
+// Tenant is an organization (a family or team) sharing this deployment.
+// Every other row belongs to exactly one tenant.
+type Tenant struct {
+	UID       string    `json:"uid" db:"uid"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ToolPolicy tailors the assistant tools for one user or one household. A
+// nil tool list inherits the next policy out: user, household, then the
+// server default.
+type ToolPolicy struct {
+	UID             string    `json:"uid" db:"uid"`
+	UserUID         *string   `json:"user_uid" db:"user_uid"`
+	HouseholdUID    *string   `json:"household_uid" db:"household_uid"`
+	AllowedTools    []string  `json:"allowed_tools" db:"allowed_tools"`
+	DisallowedTools []string  `json:"disallowed_tools" db:"disallowed_tools"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ErrInvalidRating is returned by RateRecipe for a rating outside 1-5.
+var ErrInvalidRating = errors.New("rating must be 1 to 5")
+
 type Recipes struct {
-	ID           string    `json:"id" db:"id"`
-	Title        string    `json:"title" db:"title"`
-	ExternalURL  *string   `json:"external_url" db:"external_url"`
-	Data         string    `json:"data" db:"data"`
-	Genre        *string   `json:"genre" db:"genre"`
-	GroceryList  *string   `json:"grocery_list" db:"grocery_list"`
-	PrepTime     *int      `json:"prep_time" db:"prep_time"`
-	CookTime     *int      `json:"cook_time" db:"cook_time"`
-	TotalTime    *int      `json:"total_time" db:"total_time"`
-	Servings     *int      `json:"servings" db:"servings"`
-	Difficulty   *string   `json:"difficulty" db:"difficulty"`
-	Rating       *int      `json:"rating" db:"rating"`
+	ID          string  `json:"id" db:"id"`
+	Title       string  `json:"title" db:"title"`
+	ExternalURL *string `json:"external_url" db:"external_url"`
+	Data        string  `json:"data" db:"data"`
+	Genre       *string `json:"genre" db:"genre"`
+	GroceryList *string `json:"grocery_list" db:"grocery_list"`
+	PrepTime    *int    `json:"prep_time" db:"prep_time"`
+	CookTime    *int    `json:"cook_time" db:"cook_time"`
+	TotalTime   *int    `json:"total_time" db:"total_time"`
+	Servings    *int    `json:"servings" db:"servings"`
+	Difficulty  *string `json:"difficulty" db:"difficulty"`
+	// Rating is the average of the household's RatingCount ratings and
+	// MyRating the caller's own. Saving a recipe leaves them alone; they
+	// are set through RateRecipe.
+	Rating       *float64  `json:"rating" db:"rating"`
+	RatingCount  int       `json:"rating_count" db:"rating_count"`
+	MyRating     *int      `json:"my_rating" db:"my_rating"`
 	Tags         []string  `json:"tags" db:"tags"`
 	UserUID      *string   `json:"user_uid" db:"user_uid"`
 	HouseholdUID *string   `json:"household_uid" db:"household_uid"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	// PhotoUpdatedAt is set while the recipe has a photo.
+	PhotoUpdatedAt *time.Time `json:"photo_updated_at" db:"photo_updated_at"`
+}
+
+// RecipePhoto is one rendition of a recipe's photo: the upload itself
+// ("original") or a thumbnail.
+type RecipePhoto struct {
+	RecipeID    string    `json:"recipe_id" db:"recipe_id"`
+	Size        string    `json:"size" db:"size"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Width       int       `json:"width" db:"width"`
+	Height      int       `json:"height" db:"height"`
+	Data        []byte    `json:"-" db:"data"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
 type ListOptions struct {
@@ -136,405 +862,2571 @@ type ListOptions struct {
 	SortDir     string
 	WhereClause string
 	WhereArgs   []any
+	// Fields limits which columns are selected; empty selects them all.
+	// Columns that aren't selected are left at their zero value.
+	Fields []string
 }
 
+// ErrUnknownField is returned when ListOptions.Fields names a column the
+// table doesn't have.
+var ErrUnknownField = errors.New("unknown field")
+
+// ErrInvalidSort is returned when ListOptions.SortBy isn't one of the
+// table's columns or SortDir isn't ASC or DESC.
+var ErrInvalidSort = errors.New("invalid sort")
+
 type queryer interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 type DAO struct{ pool queryer }
 
-func New(ctx context.Context, pool queryer) (*DAO, error) {
-	return &DAO{pool}, nil
-}
+// Option configures a DAO.
+type Option func(*DAO)
 
-func handleUIDRefs(userUID, householdUID *string) (*string, *string) {
-	var userUIDPtr *string
-	if userUID != nil && *userUID != "" {
-		userUIDPtr = userUID
+func New(ctx context.Context, pool queryer, opts ...Option) (*DAO, error) {
+	d := &DAO{scoped{pool}}
+	for _, opt := range opts {
+		opt(d)
 	}
+	return d, nil
+}
 
-	var householdUIDPtr *string
-	if householdUID != nil && *householdUID != "" {
-		householdUIDPtr = householdUID
-	}
+// DefaultTenantUID owns the rows that existed before tenants were added and
+// anything created without a tenant.
+const DefaultTenantUID = "00000000-0000-0000-0000-000000000001"
 
-	return userUIDPtr, householdUIDPtr
+type tenantKey struct{}
+
+// tenancy is the tenant the DAO calls made with a context act in, or whether
+// they may act in every tenant.
+type tenancy struct {
+	uid string
+	all bool
 }
 
-func (d *DAO) CreateTodo(ctx context.Context, t Todo) (Todo, error) {
-	userUID, householdUID := handleUIDRefs(t.UserUID, t.HouseholdUID)
+// WithTenant scopes the DAO calls made with ctx to a tenant. Row-level
+// security hides and rejects other tenants' rows.
+func WithTenant(ctx context.Context, tenantUID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenancy{uid: tenantUID})
+}
 
-	row := d.pool.QueryRow(ctx, insertTodo,
-		t.Title, t.Description, t.Data, t.Priority, t.DueDate,
-		t.RecursOn, t.MarkedComplete, t.ExternalURL, userUID, householdUID, t.CompletedBy,
-	)
-	return scanTodo(row)
+// WithAllTenants lets the DAO calls made with ctx see and write every
+// tenant's rows. It is for operators, background jobs and lookups that
+// find out a caller's tenant, such as of an API key by its hash; calls
+// made with neither it nor a tenant see no rows at all.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenancy{all: true})
 }
 
-func (d *DAO) GetTodo(ctx context.Context, uid string) (Todo, error) {
-	return scanTodo(d.pool.QueryRow(ctx, getTodo, uid))
+func TenantFromContext(ctx context.Context) (string, bool) {
+	t, _ := ctx.Value(tenantKey{}).(tenancy)
+	return t.uid, t.uid != ""
 }
 
-func (d *DAO) ListTodos(ctx context.Context, options ListOptions) ([]Todo, error) {
-	todoColumns := "uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at"
-	query := buildListQuery("todos", todoColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	out := []Todo{}
-	for rows.Next() {
-		t, err := scanTodo(rows)
-		if err != nil {
-			return nil, err
+// AllTenants reports whether ctx was made with WithAllTenants.
+func AllTenants(ctx context.Context) bool {
+	t, _ := ctx.Value(tenantKey{}).(tenancy)
+	return t.all
+}
+
+// ConfigureTenancy makes every connection acquired from the pool carry the
+// tenancy of the acquiring context, so WithTenant and WithAllTenants apply
+// to all queries. It must be applied before the pool is created.
+func ConfigureTenancy(config *pgxpool.Config) {
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		// Always set both, even to "", so a connection never keeps the
+		// previous caller's tenancy.
+		tenantUID, _ := TenantFromContext(ctx)
+		all := ""
+		if AllTenants(ctx) {
+			all = "on"
 		}
-		out = append(out, t)
+		_, err := conn.Exec(ctx, setTenant, tenantUID, all)
+		return err == nil
 	}
-	return out, rows.Err()
 }
 
-type UpdateTodo struct {
-	Title          *string    `json:"title"`
-	Description    *string    `json:"description"`
-	Data           *string    `json:"data"`
-	Priority       *int       `json:"priority"`
-	DueDate        *time.Time `json:"due_date"`
-	RecursOn       *string    `json:"recurs_on"`
-	ExternalURL    *string    `json:"external_url"`
-	CompletedBy    *string    `json:"completed_by"`
-	MarkedComplete *time.Time `json:"marked_complete"`
+type scopeKey struct{}
+
+// Scope is the user, and their household, a request acts for.
+type Scope struct {
+	UserUID      string
+	HouseholdUID string
 }
 
-func (d *DAO) UpdateTodo(ctx context.Context, uid string, t UpdateTodo) (Todo, error) {
-	row := d.pool.QueryRow(ctx, updateTodo, uid, t.Title, t.Description, t.Data,
-		t.Priority, t.DueDate, t.RecursOn, t.MarkedComplete, t.ExternalURL, t.CompletedBy,
-	)
-	return scanTodo(row)
+// WithScope confines the DAO calls made with ctx to the rows userUID and
+// their household may see. Row-level security then hides and rejects other
+// households' rows even when a query forgets to filter on them. Without a
+// scope, queries see the whole tenant.
+func WithScope(ctx context.Context, userUID, householdUID string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, Scope{UserUID: userUID, HouseholdUID: householdUID})
 }
 
-func (d *DAO) DeleteTodo(ctx context.Context, uid string) error {
-	_, err := d.pool.Exec(ctx, deleteTodo, uid)
-	return err
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(scopeKey{}).(Scope)
+	return s, ok && s.UserUID != ""
 }
 
-func (d *DAO) CreateBackground(ctx context.Context, b Background) (Background, error) {
-	row := d.pool.QueryRow(ctx, insertBackground, b.Key, b.Value)
-	return scanBackground(row)
+// scoped runs every call made with a Scope in its own transaction, which
+// sets app.user_uid and app.household_uid with SET LOCAL. Unlike the tenant,
+// the scope can't be left on a connection by accident: it ends with the
+// transaction. Unscoped calls go straight to the pool.
+type scoped struct{ queryer }
+
+// begin starts a transaction carrying ctx's scope. ok is false when ctx has
+// none.
+func (s scoped) begin(ctx context.Context) (tx pgx.Tx, ok bool, err error) {
+	scope, ok := ScopeFromContext(ctx)
+	if !ok {
+		return nil, false, nil
+	}
+	tx, err = s.queryer.Begin(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	if _, err := tx.Exec(ctx, setScope, scope.UserUID, scope.HouseholdUID); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, true, err
+	}
+	return tx, true, nil
 }
 
-func (d *DAO) GetBackground(ctx context.Context, key string) (Background, error) {
-	return scanBackground(d.pool.QueryRow(ctx, getBackground, key))
+// finish commits tx, or rolls it back if err is set.
+func finish(ctx context.Context, tx pgx.Tx, err error) error {
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (d *DAO) ListBackgrounds(ctx context.Context, options ListOptions) ([]Background, error) {
-	backgroundColumns := "*"
-	query := buildListQuery("backgrounds", backgroundColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
+func (s scoped) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, ok, err := s.begin(ctx)
+	if !ok {
+		return s.queryer.Begin(ctx)
+	}
+	return tx, err
+}
+
+func (s scoped) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx, ok, err := s.begin(ctx)
+	if !ok {
+		return s.queryer.Exec(ctx, sql, args...)
+	}
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := tx.Exec(ctx, sql, args...)
+	return tag, finish(ctx, tx, err)
+}
+
+func (s scoped) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	tx, ok, err := s.begin(ctx)
+	if !ok {
+		return s.queryer.QueryRow(ctx, sql, args...)
+	}
+	if err != nil {
+		return errRow{err}
+	}
+	return scopedRow{ctx: ctx, tx: tx, row: tx.QueryRow(ctx, sql, args...)}
+}
+
+func (s scoped) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	tx, ok, err := s.begin(ctx)
+	if !ok {
+		return s.queryer.Query(ctx, sql, args...)
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	var out []Background
-	for rows.Next() {
-		b, err := scanBackground(rows)
-		if err != nil {
-			return nil, err
-		}
-		out = append(out, b)
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
 	}
-	return out, rows.Err()
+	return &scopedRows{Rows: rows, ctx: ctx, tx: tx}, nil
 }
 
-func (d *DAO) UpdateBackground(ctx context.Context, key string, b Background) (Background, error) {
-	row := d.pool.QueryRow(ctx, updateBackground, key, b.Value)
-	return scanBackground(row)
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// scopedRow ends its transaction once it is scanned.
+type scopedRow struct {
+	ctx context.Context
+	tx  pgx.Tx
+	row pgx.Row
 }
 
-func (d *DAO) DeleteBackground(ctx context.Context, key string) error {
-	_, err := d.pool.Exec(ctx, deleteBackground, key)
-	return err
+func (r scopedRow) Scan(dest ...any) error {
+	return finish(r.ctx, r.tx, r.row.Scan(dest...))
 }
 
-func (d *DAO) CreatePreferences(ctx context.Context, p Preferences) (Preferences, error) {
-	row := d.pool.QueryRow(ctx, insertPreferences, p.Key, p.Specifier, p.Data, p.Tags)
-	return scanPreferences(row)
+// scopedRows ends its transaction once the rows are read or closed.
+type scopedRows struct {
+	pgx.Rows
+	ctx  context.Context
+	tx   pgx.Tx
+	done bool
+	err  error
 }
 
-func (d *DAO) GetPreferences(ctx context.Context, key, specifier string) (Preferences, error) {
-	return scanPreferences(d.pool.QueryRow(ctx, getPreferences, key, specifier))
+func (r *scopedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end()
+	return false
 }
 
-func (d *DAO) ListPreferences(ctx context.Context, options ListOptions) ([]Preferences, error) {
-	preferencesColumns := "key, specifier, data, created_at, updated_at, tags"
-	query := buildListQuery("preferences", preferencesColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
+func (r *scopedRows) Close() {
+	r.end()
+}
+
+func (r *scopedRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
 	}
-	defer rows.Close()
-	var out []Preferences
-	for rows.Next() {
-		p, err := scanPreferences(rows)
-		if err != nil {
-			return nil, err
+	return r.err
+}
+
+func (r *scopedRows) end() {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.Rows.Close()
+	r.err = finish(r.ctx, r.tx, r.Rows.Err())
+}
+
+// Resilience bounds how long each statement may take and retries the ones
+// that fail transiently, so a serialization failure or a dropped connection
+// doesn't reach the caller as an error.
+type Resilience struct {
+	// ReadTimeout caps each attempt at a SELECT and WriteTimeout each
+	// attempt at any other statement; zero means no cap. A statement that
+	// times out isn't retried.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// MaxRetries is how many times a statement is repeated after a
+	// transient failure. Before retry n the DAO waits a random time of up
+	// to RetryDelay×2ⁿ.
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+// WithResilience applies r to every call made through the DAO. Statements
+// run on a transaction from Begin are never retried on their own, as the
+// transaction is aborted by then; only beginning it is.
+func WithResilience(r Resilience) Option {
+	return func(d *DAO) { d.pool = resilient{queryer: d.pool, Resilience: r} }
+}
+
+// The retry counters are published with expvar: dao_retries by reason
+// (a SQLSTATE or "connection"), dao_retries_exhausted for statements that
+// still failed after MaxRetries, and dao_timeouts for timed out attempts.
+var (
+	retries          = expvar.NewMap("dao_retries")
+	retriesExhausted = expvar.NewInt("dao_retries_exhausted")
+	timeouts         = expvar.NewInt("dao_timeouts")
+)
+
+// resilient wraps the scoped queryer, so a retried scoped call repeats its
+// whole transaction.
+type resilient struct {
+	queryer
+	Resilience
+}
+
+func isRead(sql string) bool {
+	word, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	return strings.EqualFold(word, "SELECT")
+}
+
+// timeout returns the context for one attempt at sql.
+func (r resilient) timeout(ctx context.Context, sql string) (context.Context, context.CancelFunc) {
+	d := r.WriteTimeout
+	if isRead(sql) {
+		d = r.ReadTimeout
+	}
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// transient reports whether err is worth retrying, and why. Postgres rolls
+// back a statement that hits a serialization failure or deadlock, and one
+// that never reached the server can't have run, so both are safe to
+// repeat. A read is also repeated when its connection drops mid-flight; a
+// write isn't, as it may already have been applied.
+func transient(err error, read bool) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", "40P01":
+			return pgErr.Code, true
 		}
-		out = append(out, p)
+		return "", false
 	}
-	return out, rows.Err()
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.SafeToRetry(err) {
+		return "connection", true
+	}
+	var netErr net.Error
+	if read && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) && !netErr.Timeout()) {
+		return "connection", true
+	}
+	return "", false
 }
 
-func (d *DAO) UpdatePreferences(ctx context.Context, key, specifier string, p Preferences) (Preferences, error) {
-	row := d.pool.QueryRow(ctx, updatePreferences, key, specifier, p.Data, p.Tags)
-	return scanPreferences(row)
+// retry reports whether attempt n at sql, which failed with err, should be
+// repeated, after waiting out the backoff.
+func (r resilient) retry(ctx context.Context, sql string, n int, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		timeouts.Add(1)
+		return false
+	}
+	reason, ok := transient(err, isRead(sql))
+	if !ok {
+		return false
+	}
+	if n >= r.MaxRetries {
+		retriesExhausted.Add(1)
+		return false
+	}
+	retries.Add(reason, 1)
+	if delay := r.RetryDelay << n; delay > 0 {
+		t := time.NewTimer(rand.N(delay))
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return false
+		case <-t.C:
+		}
+	}
+	return true
+}
+
+func (r resilient) Begin(ctx context.Context) (pgx.Tx, error) {
+	for n := 0; ; n++ {
+		// Only beginning is timed: the transaction outlives the attempt.
+		actx, cancel := r.timeout(ctx, "BEGIN")
+		tx, err := r.queryer.Begin(actx)
+		cancel()
+		if !r.retry(ctx, "BEGIN", n, err) {
+			return tx, err
+		}
+	}
+}
+
+func (r resilient) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	for n := 0; ; n++ {
+		actx, cancel := r.timeout(ctx, sql)
+		tag, err := r.queryer.Exec(actx, sql, args...)
+		cancel()
+		if !r.retry(ctx, sql, n, err) {
+			return tag, err
+		}
+	}
+}
+
+// QueryRow defers running the statement to Scan, where its errors surface.
+func (r resilient) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return resilientRow{r: r, ctx: ctx, sql: sql, args: args}
+}
+
+// Query retries failures to start the statement. Errors reading the rows
+// are returned as they are.
+func (r resilient) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	for n := 0; ; n++ {
+		actx, cancel := r.timeout(ctx, sql)
+		rows, err := r.queryer.Query(actx, sql, args...)
+		if err == nil {
+			return &timedRows{Rows: rows, cancel: cancel}, nil
+		}
+		cancel()
+		if !r.retry(ctx, sql, n, err) {
+			return nil, err
+		}
+	}
+}
+
+type resilientRow struct {
+	r    resilient
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (row resilientRow) Scan(dest ...any) error {
+	for n := 0; ; n++ {
+		ctx, cancel := row.r.timeout(row.ctx, row.sql)
+		err := row.r.queryer.QueryRow(ctx, row.sql, row.args...).Scan(dest...)
+		cancel()
+		if !row.r.retry(row.ctx, row.sql, n, err) {
+			return err
+		}
+	}
+}
+
+// timedRows releases its attempt's timeout once the rows are read or
+// closed.
+type timedRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel()
+	return false
+}
+
+func (r *timedRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// ConfigureStatementTimeout makes Postgres cancel any statement on the
+// pool's connections that runs longer than d, including those inside
+// transactions, which the DAO's own timeouts don't cover. Zero leaves the
+// server's setting. It must be applied before the pool is created.
+func ConfigureStatementTimeout(config *pgxpool.Config, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// WithSlowQueryLog logs a warning for every statement that takes longer
+// than threshold, with its SQL and the shapes of its arguments but never
+// their values. A query is timed until its rows are read or closed, and
+// retries count towards it, so it should be applied after WithResilience.
+func WithSlowQueryLog(threshold time.Duration) Option {
+	return func(d *DAO) { d.pool = slowLog{queryer: d.pool, threshold: threshold} }
+}
+
+// slowQueries counts the statements WithSlowQueryLog logged, as expvar
+// dao_slow_queries.
+var slowQueries = expvar.NewInt("dao_slow_queries")
+
+type slowLog struct {
+	queryer
+	threshold time.Duration
+}
+
+// observe logs sql if it has been running since start for longer than the
+// threshold.
+func (s slowLog) observe(ctx context.Context, start time.Time, sql string, args []any) {
+	elapsed := time.Since(start)
+	if s.threshold <= 0 || elapsed < s.threshold {
+		return
+	}
+	slowQueries.Add(1)
+	slog.WarnContext(ctx, "Slow query", "duration", elapsed, "sql", sanitizeSQL(sql), "args", argShapes(args))
+}
+
+var (
+	sqlLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlSpace   = regexp.MustCompile(`\s+`)
+)
+
+// sanitizeSQL puts sql on one line and masks its string literals.
+func sanitizeSQL(sql string) string {
+	sql = sqlLiteral.ReplaceAllString(sql, "'?'")
+	return strings.TrimSpace(sqlSpace.ReplaceAllString(sql, " "))
+}
+
+// argShapes describes each argument by its type, and its length where it
+// has one, e.g. "string(36)" or "[]string(3)".
+func argShapes(args []any) []string {
+	shapes := make([]string, len(args))
+	for i, arg := range args {
+		shapes[i] = argShape(reflect.ValueOf(arg))
+	}
+	return shapes
+}
+
+func argShape(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v.Type().String() + "(nil)"
+		}
+		return "*" + argShape(v.Elem())
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%s(%d)", v.Type(), v.Len())
+	}
+	return v.Type().String()
+}
+
+func (s slowLog) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer s.observe(ctx, time.Now(), sql, args)
+	return s.queryer.Exec(ctx, sql, args...)
+}
+
+func (s slowLog) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return slowRow{s: s, ctx: ctx, start: time.Now(), sql: sql, args: args, row: s.queryer.QueryRow(ctx, sql, args...)}
+}
+
+func (s slowLog) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := s.queryer.Query(ctx, sql, args...)
+	if err != nil {
+		s.observe(ctx, start, sql, args)
+		return nil, err
+	}
+	return &slowRows{Rows: rows, s: s, ctx: ctx, start: start, sql: sql, args: args}, nil
+}
+
+// Begin times the statements run on the transaction too.
+func (s slowLog) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := s.queryer.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slowTx{Tx: tx, s: s}, nil
+}
+
+type slowTx struct {
+	pgx.Tx
+	s slowLog
+}
+
+func (t slowTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer t.s.observe(ctx, time.Now(), sql, args)
+	return t.Tx.Exec(ctx, sql, args...)
+}
+
+func (t slowTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return slowRow{s: t.s, ctx: ctx, start: time.Now(), sql: sql, args: args, row: t.Tx.QueryRow(ctx, sql, args...)}
+}
+
+func (t slowTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		t.s.observe(ctx, start, sql, args)
+		return nil, err
+	}
+	return &slowRows{Rows: rows, s: t.s, ctx: ctx, start: start, sql: sql, args: args}, nil
+}
+
+// slowRow is timed until Scan returns, as a row may run its statement
+// there.
+type slowRow struct {
+	s     slowLog
+	ctx   context.Context
+	start time.Time
+	sql   string
+	args  []any
+	row   pgx.Row
+}
+
+func (r slowRow) Scan(dest ...any) error {
+	defer r.s.observe(r.ctx, r.start, r.sql, r.args)
+	return r.row.Scan(dest...)
+}
+
+// slowRows is timed until the rows are read or closed.
+type slowRows struct {
+	pgx.Rows
+	s     slowLog
+	ctx   context.Context
+	start time.Time
+	sql   string
+	args  []any
+	done  bool
+}
+
+func (r *slowRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end()
+	return false
+}
+
+func (r *slowRows) Close() {
+	r.Rows.Close()
+	r.end()
+}
+
+func (r *slowRows) end() {
+	if !r.done {
+		r.done = true
+		r.s.observe(r.ctx, r.start, r.sql, r.args)
+	}
+}
+
+// Faults describes failures to inject into DAO calls, so error handling
+// and retries can be exercised in integration tests and staging. They
+// must never be injected in production.
+type Faults struct {
+	// TransientRate is the chance, from 0 to 1, that a statement fails
+	// with a serialization failure, which WithResilience retries.
+	TransientRate float64
+	// PermanentRate is the chance that a statement fails with an error
+	// that isn't retried and so reaches the handler.
+	PermanentRate float64
+	// Latency delays every statement, plus up to Jitter more at random.
+	Latency time.Duration
+	Jitter  time.Duration
+	// Statements limits the faults to statements starting with these
+	// words, e.g. "SELECT" or "BEGIN"; empty means every statement.
+	Statements []string
+}
+
+// WithFaults injects f into every call made through the DAO, including
+// statements on transactions from Begin. A failing statement is never
+// run. Applied before WithResilience, transient faults are retried like
+// real ones.
+func WithFaults(f Faults) Option {
+	return func(d *DAO) { d.pool = faulty{queryer: d.pool, Faults: f} }
+}
+
+// injectedFaults counts what WithFaults injected, as expvar
+// dao_injected_faults: "latency", "transient" and "permanent".
+var injectedFaults = expvar.NewMap("dao_injected_faults")
+
+type faulty struct {
+	queryer
+	Faults
+}
+
+// inject delays sql and returns the error it should fail with, if any.
+func (f Faults) inject(ctx context.Context, sql string) error {
+	word, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	if len(f.Statements) > 0 && !slices.ContainsFunc(f.Statements, func(s string) bool { return strings.EqualFold(s, word) }) {
+		return nil
+	}
+	delay := f.Latency
+	if f.Jitter > 0 {
+		delay += rand.N(f.Jitter)
+	}
+	if delay > 0 {
+		injectedFaults.Add("latency", 1)
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	switch p := rand.Float64(); {
+	case p < f.TransientRate:
+		injectedFaults.Add("transient", 1)
+		return &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "injected serialization failure"}
+	case p < f.TransientRate+f.PermanentRate:
+		injectedFaults.Add("permanent", 1)
+		return &pgconn.PgError{Severity: "ERROR", Code: "XX000", Message: "injected failure"}
+	}
+	return nil
+}
+
+func (f faulty) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if err := f.inject(ctx, sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return f.queryer.Exec(ctx, sql, args...)
+}
+
+// QueryRow decides on the fault before the statement runs, but it only
+// surfaces on Scan, as a real failure would.
+func (f faulty) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if err := f.inject(ctx, sql); err != nil {
+		return errRow{err}
+	}
+	return f.queryer.QueryRow(ctx, sql, args...)
+}
+
+func (f faulty) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if err := f.inject(ctx, sql); err != nil {
+		return nil, err
+	}
+	return f.queryer.Query(ctx, sql, args...)
+}
+
+func (f faulty) Begin(ctx context.Context) (pgx.Tx, error) {
+	if err := f.inject(ctx, "BEGIN"); err != nil {
+		return nil, err
+	}
+	tx, err := f.queryer.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return faultyTx{Tx: tx, f: f.Faults}, nil
+}
+
+type faultyTx struct {
+	pgx.Tx
+	f Faults
+}
+
+func (t faultyTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if err := t.f.inject(ctx, sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return t.Tx.Exec(ctx, sql, args...)
+}
+
+func (t faultyTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if err := t.f.inject(ctx, sql); err != nil {
+		return errRow{err}
+	}
+	return t.Tx.QueryRow(ctx, sql, args...)
+}
+
+func (t faultyTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if err := t.f.inject(ctx, sql); err != nil {
+		return nil, err
+	}
+	return t.Tx.Query(ctx, sql, args...)
+}
+
+func handleUIDRefs(userUID, householdUID *string) (*string, *string) {
+	var userUIDPtr *string
+	if userUID != nil && *userUID != "" {
+		userUIDPtr = userUID
+	}
+
+	var householdUIDPtr *string
+	if householdUID != nil && *householdUID != "" {
+		householdUIDPtr = householdUID
+	}
+
+	return userUIDPtr, householdUIDPtr
+}
+
+func (d *DAO) CreateTodo(ctx context.Context, t Todo) (Todo, error) {
+	args, err := todoInsertArgs(t)
+	if err != nil {
+		return Todo{}, err
+	}
+	created, err := scanTodo(d.pool.QueryRow(ctx, insertTodo, args...))
+	return created, quotaErr(todoRefErr(err))
+}
+
+// CreateTodos creates all of todos or, if any fails, none of them.
+func (d *DAO) CreateTodos(ctx context.Context, todos []Todo) ([]Todo, error) {
+	args := make([][]any, len(todos))
+	for i, t := range todos {
+		var err error
+		if args[i], err = todoInsertArgs(t); err != nil {
+			return nil, fmt.Errorf("todo %d: %w", i, err)
+		}
+	}
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	out := make([]Todo, 0, len(todos))
+	for _, a := range args {
+		created, err := scanTodo(tx.QueryRow(ctx, insertTodo, a...))
+		if err != nil {
+			return nil, quotaErr(todoRefErr(err))
+		}
+		out = append(out, created)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func todoInsertArgs(t Todo) ([]any, error) {
+	if !t.Priority.Valid() {
+		return nil, fmt.Errorf("%w %d", ErrInvalidPriority, t.Priority)
+	}
+	if t.EstimateMinutes != nil && *t.EstimateMinutes < 0 {
+		return nil, ErrInvalidEstimate
+	}
+	completedBy, markedComplete, err := completion(t.CompletedBy, t.MarkedComplete)
+	if err != nil {
+		return nil, err
+	}
+	status, markedComplete, err := todoStatus(t.Status, markedComplete)
+	if err != nil {
+		return nil, err
+	}
+	userUID, householdUID := handleUIDRefs(t.UserUID, t.HouseholdUID)
+	projectUID := t.ProjectUID
+	if projectUID != nil && *projectUID == "" {
+		projectUID = nil
+	}
+	return []any{
+		t.Title, t.Description, t.Data, t.Priority, t.DueDate,
+		t.RecursOn, markedComplete, t.ExternalURL, userUID, householdUID, completedBy, t.Location, status, t.EstimateMinutes,
+		projectUID, t.Tags,
+	}, nil
+}
+
+// todoStatus reconciles a new todo's status with its completion: a
+// completed todo is done, and a done one is completed now unless
+// markedComplete says when. The status defaults to backlog.
+func todoStatus(status TodoStatus, markedComplete *time.Time) (TodoStatus, *time.Time, error) {
+	switch {
+	case status == "" && markedComplete == nil:
+		return TodoBacklog, nil, nil
+	case status != "" && !status.Valid():
+		return "", nil, fmt.Errorf("%w %q", ErrInvalidStatus, status)
+	case markedComplete != nil:
+		if status != "" && status != TodoDone {
+			return "", nil, fmt.Errorf("%w: a completed todo is done, not %s", ErrInvalidStatus, status)
+		}
+		return TodoDone, markedComplete, nil
+	case status == TodoDone:
+		now := time.Now()
+		return TodoDone, &now, nil
+	}
+	return status, nil, nil
+}
+
+// completion checks who completed a todo. Naming a completer completes the
+// todo now unless markedComplete says when; an empty one is no completer.
+func completion(completedBy *string, markedComplete *time.Time) (*string, *time.Time, error) {
+	if completedBy == nil || *completedBy == "" {
+		return nil, markedComplete, nil
+	}
+	if uuid.Validate(*completedBy) != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownCompleter, *completedBy)
+	}
+	if markedComplete == nil {
+		now := time.Now()
+		markedComplete = &now
+	}
+	return completedBy, markedComplete, nil
+}
+
+// todoRefErr turns the foreign key violations for a completer that isn't a
+// user and a project that doesn't exist into ErrUnknownCompleter and
+// ErrUnknownProject.
+func todoRefErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.ConstraintName {
+		case "todos_completed_by_fkey":
+			return ErrUnknownCompleter
+		case "todos_project_uid_fkey":
+			return ErrUnknownProject
+		}
+	}
+	return err
+}
+
+func (d *DAO) GetTodo(ctx context.Context, uid string) (Todo, error) {
+	return scanTodo(d.pool.QueryRow(ctx, getTodo, uid))
+}
+
+func (d *DAO) ListTodos(ctx context.Context, options ListOptions) ([]Todo, error) {
+	return todoColumns.list(ctx, d.pool, "todos", options, []Todo{})
+}
+
+type UpdateTodo struct {
+	Title          *string       `json:"title"`
+	Description    *string       `json:"description"`
+	Data           *string       `json:"data"`
+	Priority       *Priority     `json:"priority"`
+	DueDate        *time.Time    `json:"due_date"`
+	RecursOn       *string       `json:"recurs_on"`
+	ExternalURL    *string       `json:"external_url"`
+	CompletedBy    *string       `json:"completed_by"`
+	MarkedComplete *time.Time    `json:"marked_complete"`
+	Location       *TodoLocation `json:"location"`
+	// EstimateMinutes replaces the estimate; there is no way to clear it.
+	EstimateMinutes *int `json:"estimate_minutes"`
+	// ProjectUID moves the todo into a project; RemoveTodosFromProject
+	// takes it out.
+	ProjectUID *string `json:"project_uid"`
+	// Tags replaces all of the todo's tags.
+	Tags *[]string `json:"tags"`
+}
+
+func (d *DAO) UpdateTodo(ctx context.Context, uid string, t UpdateTodo) (Todo, error) {
+	if t.Priority != nil && !t.Priority.Valid() {
+		return Todo{}, fmt.Errorf("%w %d", ErrInvalidPriority, *t.Priority)
+	}
+	if t.EstimateMinutes != nil && *t.EstimateMinutes < 0 {
+		return Todo{}, ErrInvalidEstimate
+	}
+	completedBy, markedComplete, err := completion(t.CompletedBy, t.MarkedComplete)
+	if err != nil {
+		return Todo{}, err
+	}
+	row := d.pool.QueryRow(ctx, updateTodo, uid, t.Title, t.Description, t.Data,
+		t.Priority, t.DueDate, t.RecursOn, markedComplete, t.ExternalURL, completedBy, t.Location, t.EstimateMinutes, t.ProjectUID, t.Tags,
+	)
+	updated, err := scanTodo(row)
+	return updated, todoRefErr(err)
+}
+
+// SetTodoStatus moves a todo to status. Moving it to TodoDone completes
+// it, by completedBy if given; moving it out reopens it, clearing
+// MarkedComplete and CompletedBy. A todo that doesn't exist or isn't
+// visible is pgx.ErrNoRows.
+func (d *DAO) SetTodoStatus(ctx context.Context, uid string, status TodoStatus, completedBy *string) (Todo, error) {
+	if !status.Valid() {
+		return Todo{}, fmt.Errorf("%w %q", ErrInvalidStatus, status)
+	}
+	completedBy, _, err := completion(completedBy, nil)
+	if err != nil {
+		return Todo{}, err
+	}
+	updated, err := scanTodo(d.pool.QueryRow(ctx, setTodoStatus, uid, status, completedBy))
+	return updated, todoRefErr(err)
+}
+
+func (d *DAO) DeleteTodo(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteTodo, uid)
+	return err
+}
+
+// TodoDependency records that TodoUID is blocked until BlockedByUID is
+// complete.
+type TodoDependency struct {
+	TodoUID      string    `json:"todo_uid"`
+	BlockedByUID string    `json:"blocked_by_uid"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ErrDependencyCycle is returned when a dependency would make a todo
+// (transitively) block itself.
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+// AddTodoDependency makes todoUID blocked by blockedByUID. Adding an existing
+// dependency is a no-op.
+func (d *DAO) AddTodoDependency(ctx context.Context, todoUID, blockedByUID string) error {
+	if todoUID == blockedByUID {
+		return ErrDependencyCycle
+	}
+	var cycle bool
+	if err := d.pool.QueryRow(ctx, todoDependencyCycle, todoUID, blockedByUID).Scan(&cycle); err != nil {
+		return err
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+	_, err := d.pool.Exec(ctx, insertTodoDependency, todoUID, blockedByUID)
+	return err
+}
+
+func (d *DAO) RemoveTodoDependency(ctx context.Context, todoUID, blockedByUID string) error {
+	_, err := d.pool.Exec(ctx, deleteTodoDependency, todoUID, blockedByUID)
+	return err
+}
+
+// ListTodoDependencies returns every dependency touching todoUID, in either
+// direction.
+func (d *DAO) ListTodoDependencies(ctx context.Context, todoUID string) ([]TodoDependency, error) {
+	rows, err := d.pool.Query(ctx, listTodoDependencies, todoUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []TodoDependency{}
+	for rows.Next() {
+		var dep TodoDependency
+		if err := rows.Scan(&dep.TodoUID, &dep.BlockedByUID, &dep.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, dep)
+	}
+	return out, rows.Err()
+}
+
+// AddToMyDay puts todoUID on userUID's My Day until expiresAt, or moves
+// the expiry of one already there. Entries that have lapsed are cleared
+// first. A todo that doesn't exist or isn't visible is pgx.ErrNoRows.
+func (d *DAO) AddToMyDay(ctx context.Context, userUID, todoUID string, expiresAt time.Time) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, clearMyDay, userUID); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, addToMyDay, userUID, todoUID, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+// RemoveFromMyDay takes todoUID off userUID's My Day. Removing a todo that
+// isn't there is a no-op.
+func (d *DAO) RemoveFromMyDay(ctx context.Context, userUID, todoUID string) error {
+	_, err := d.pool.Exec(ctx, removeFromMyDay, userUID, todoUID)
+	return err
+}
+
+// ListMyDay returns the todos on userUID's My Day that haven't lapsed, in
+// the order they were added.
+func (d *DAO) ListMyDay(ctx context.Context, userUID string) ([]Todo, error) {
+	rows, err := d.pool.Query(ctx, listMyDay, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// TodoTimeLog is time UserUID spent on a todo on LoggedOn.
+type TodoTimeLog struct {
+	UID       string    `json:"uid"`
+	TodoUID   string    `json:"todo_uid"`
+	UserUID   string    `json:"user_uid"`
+	Minutes   int       `json:"minutes"`
+	LoggedOn  time.Time `json:"logged_on"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TodoTimeSpent is the time a user logged on todos in the week starting
+// Week, a Monday as YYYY-MM-DD.
+type TodoTimeSpent struct {
+	Week    string `json:"week"`
+	UserUID string `json:"user_uid"`
+	Minutes int64  `json:"minutes"`
+	Logs    int64  `json:"logs"`
+}
+
+// ErrInvalidMinutes is returned for logging no time or negative time.
+var ErrInvalidMinutes = errors.New("minutes must be positive")
+
+// ErrUnknownTimeUser is returned when time is logged for someone who isn't
+// a user.
+var ErrUnknownTimeUser = errors.New("user_uid is not a known user")
+
+// LogTodoTime records time spent on a todo. A todo that doesn't exist or
+// isn't visible is pgx.ErrNoRows.
+func (d *DAO) LogTodoTime(ctx context.Context, l TodoTimeLog) (TodoTimeLog, error) {
+	if l.Minutes <= 0 {
+		return TodoTimeLog{}, ErrInvalidMinutes
+	}
+	if uuid.Validate(l.UserUID) != nil {
+		return TodoTimeLog{}, fmt.Errorf("%w: %s", ErrUnknownTimeUser, l.UserUID)
+	}
+	logged, err := scanTodoTimeLog(d.pool.QueryRow(ctx, insertTodoTimeLog, l.TodoUID, l.UserUID, l.Minutes, l.LoggedOn, l.Note))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "todo_time_logs_user_uid_fkey" {
+		return TodoTimeLog{}, ErrUnknownTimeUser
+	}
+	return logged, err
+}
+
+// ListTodoTimeLogs returns the time logged on a todo, earliest first.
+func (d *DAO) ListTodoTimeLogs(ctx context.Context, todoUID string) ([]TodoTimeLog, error) {
+	rows, err := d.pool.Query(ctx, listTodoTimeLogs, todoUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []TodoTimeLog{}
+	for rows.Next() {
+		l, err := scanTodoTimeLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// WeeklyTodoTime totals the time logged per week and user on the todos of
+// householdUID, by userUID, or both, for days on or after from and before
+// to. A non-empty title narrows it to todos whose title contains title.
+func (d *DAO) WeeklyTodoTime(ctx context.Context, householdUID, userUID string, from, to time.Time, title string) ([]TodoTimeSpent, error) {
+	rows, err := d.pool.Query(ctx, todoWeeklyTime, householdUID, userUID, from, to, title)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []TodoTimeSpent{}
+	for rows.Next() {
+		var s TodoTimeSpent
+		if err := rows.Scan(&s.Week, &s.UserUID, &s.Minutes, &s.Logs); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func scanTodoTimeLog(s scannable) (TodoTimeLog, error) {
+	var l TodoTimeLog
+	err := s.Scan(&l.UID, &l.TodoUID, &l.UserUID, &l.Minutes, &l.LoggedOn, &l.Note, &l.CreatedAt)
+	return l, err
+}
+
+func (d *DAO) CreateProject(ctx context.Context, p Project) (Project, error) {
+	return scanProject(d.pool.QueryRow(ctx, insertProject, p.Name, p.Description, p.HouseholdUID, p.Archived))
+}
+
+func (d *DAO) GetProject(ctx context.Context, uid string) (Project, error) {
+	return scanProject(d.pool.QueryRow(ctx, getProject, uid))
+}
+
+func (d *DAO) ListProjects(ctx context.Context, options ListOptions) ([]Project, error) {
+	return projectColumns.list(ctx, d.pool, "projects", options, []Project{})
+}
+
+func (d *DAO) UpdateProject(ctx context.Context, uid string, p UpdateProject) (Project, error) {
+	return scanProject(d.pool.QueryRow(ctx, updateProject, uid, p.Name, p.Description, p.Archived))
+}
+
+// DeleteProject deletes a project, keeping its todos.
+func (d *DAO) DeleteProject(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteProject, uid)
+	return err
+}
+
+// MoveTodosToProject puts todos in a project, out of any other, and returns
+// the ones it moved; todos that don't exist or aren't visible are skipped.
+// A project that doesn't exist is ErrUnknownProject, and an archived one
+// ErrProjectArchived.
+func (d *DAO) MoveTodosToProject(ctx context.Context, projectUID string, todoUIDs []string) ([]Todo, error) {
+	p, err := d.GetProject(ctx, projectUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProject, projectUID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Archived {
+		return nil, fmt.Errorf("%w: %s", ErrProjectArchived, p.Name)
+	}
+	return d.updateTodos(ctx, moveTodosToProject, projectUID, todoUIDs)
+}
+
+// RemoveTodosFromProject takes todos out of a project and returns the ones
+// that were in it.
+func (d *DAO) RemoveTodosFromProject(ctx context.Context, projectUID string, todoUIDs []string) ([]Todo, error) {
+	return d.updateTodos(ctx, removeTodosFromProject, projectUID, todoUIDs)
+}
+
+func (d *DAO) updateTodos(ctx context.Context, query string, args ...any) ([]Todo, error) {
+	rows, err := d.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, todoRefErr(err)
+	}
+	defer rows.Close()
+	out := []Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, todoRefErr(rows.Err())
+}
+
+func (d *DAO) CreateTodoTemplate(ctx context.Context, t TodoTemplate) (TodoTemplate, error) {
+	userUID, householdUID := handleUIDRefs(t.UserUID, t.HouseholdUID)
+	if t.Items == nil {
+		t.Items = []TemplateItem{}
+	}
+	row := d.pool.QueryRow(ctx, insertTodoTemplate, t.Name, t.Description, t.Items, userUID, householdUID)
+	return scanTodoTemplate(row)
+}
+
+func (d *DAO) GetTodoTemplate(ctx context.Context, uid string) (TodoTemplate, error) {
+	return scanTodoTemplate(d.pool.QueryRow(ctx, getTodoTemplate, uid))
+}
+
+func (d *DAO) ListTodoTemplates(ctx context.Context, options ListOptions) ([]TodoTemplate, error) {
+	return todoTemplateColumns.list(ctx, d.pool, "todo_templates", options, []TodoTemplate{})
+}
+
+func (d *DAO) UpdateTodoTemplate(ctx context.Context, uid string, t UpdateTodoTemplate) (TodoTemplate, error) {
+	row := d.pool.QueryRow(ctx, updateTodoTemplate, uid, t.Name, t.Description, t.Items)
+	return scanTodoTemplate(row)
+}
+
+func (d *DAO) DeleteTodoTemplate(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteTodoTemplate, uid)
+	return err
+}
+
+func (d *DAO) CreatePantryItem(ctx context.Context, p PantryItem) (PantryItem, error) {
+	row := d.pool.QueryRow(ctx, insertPantryItem, p.HouseholdUID, p.Item, p.Quantity, p.Unit, p.Category, p.ExpiresOn)
+	return scanPantryItem(row)
+}
+
+func (d *DAO) GetPantryItem(ctx context.Context, uid string) (PantryItem, error) {
+	return scanPantryItem(d.pool.QueryRow(ctx, getPantryItem, uid))
+}
+
+func (d *DAO) ListPantryItems(ctx context.Context, options ListOptions) ([]PantryItem, error) {
+	return pantryItemColumns.list(ctx, d.pool, "pantry_items", options, []PantryItem{})
+}
+
+func (d *DAO) UpdatePantryItem(ctx context.Context, uid string, p UpdatePantryItem) (PantryItem, error) {
+	row := d.pool.QueryRow(ctx, updatePantryItem, uid, p.Item, p.Quantity, p.Unit, p.Category, p.ExpiresOn)
+	return scanPantryItem(row)
+}
+
+func (d *DAO) DeletePantryItem(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deletePantryItem, uid)
+	return err
+}
+
+// CreateGroceryPurchase records a purchase. A zero PurchasedOn means today.
+func (d *DAO) CreateGroceryPurchase(ctx context.Context, p GroceryPurchase) (GroceryPurchase, error) {
+	var purchasedOn *time.Time
+	if !p.PurchasedOn.IsZero() {
+		purchasedOn = &p.PurchasedOn
+	}
+	row := d.pool.QueryRow(ctx, insertGroceryPurchase, p.HouseholdUID, p.Item, p.Quantity, p.Store, p.PriceCents, purchasedOn)
+	return scanGroceryPurchase(row)
+}
+
+func (d *DAO) GetGroceryPurchase(ctx context.Context, uid string) (GroceryPurchase, error) {
+	return scanGroceryPurchase(d.pool.QueryRow(ctx, getGroceryPurchase, uid))
+}
+
+func (d *DAO) ListGroceryPurchases(ctx context.Context, options ListOptions) ([]GroceryPurchase, error) {
+	return groceryPurchaseColumns.list(ctx, d.pool, "grocery_purchases", options, []GroceryPurchase{})
+}
+
+func (d *DAO) DeleteGroceryPurchase(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteGroceryPurchase, uid)
+	return err
+}
+
+// RegisterDevice adds a device, or moves an already registered token to
+// d.UserUID and renames it.
+func (d *DAO) RegisterDevice(ctx context.Context, dev Device) (Device, error) {
+	return scanDevice(d.pool.QueryRow(ctx, registerDevice, dev.UserUID, dev.Platform, dev.Token, dev.Name))
+}
+
+func (d *DAO) ListDevicesByUserUID(ctx context.Context, userUID string) ([]Device, error) {
+	return d.listDevices(ctx, listDevicesByUserUID, userUID)
+}
+
+// ListDevicesByHouseholdUID returns the devices of every member of a
+// household.
+func (d *DAO) ListDevicesByHouseholdUID(ctx context.Context, householdUID string) ([]Device, error) {
+	return d.listDevices(ctx, listDevicesByHouseholdUID, householdUID)
+}
+
+func (d *DAO) listDevices(ctx context.Context, query string, args ...any) ([]Device, error) {
+	rows, err := d.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Device{}
+	for rows.Next() {
+		dev, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dev)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) DeleteDevice(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteDevice, uid)
+	return err
+}
+
+func (d *DAO) CreateDelivery(ctx context.Context, dl Delivery) (Delivery, error) {
+	return scanDelivery(d.pool.QueryRow(ctx, insertDelivery, dl.Channel, dl.Recipient, dl.Payload, dl.PayloadHash, dl.Status, dl.LastError))
+}
+
+func (d *DAO) GetDelivery(ctx context.Context, uid string) (Delivery, error) {
+	return scanDelivery(d.pool.QueryRow(ctx, getDelivery, uid))
+}
+
+func (d *DAO) ListDeliveries(ctx context.Context, options ListOptions) ([]Delivery, error) {
+	return deliveryColumns.list(ctx, d.pool, "deliveries", options, []Delivery{})
+}
+
+// RecordDeliveryAttempt counts another attempt at a delivery, setting its
+// status and the error it failed with, if any.
+func (d *DAO) RecordDeliveryAttempt(ctx context.Context, uid, status string, lastError *string) (Delivery, error) {
+	return scanDelivery(d.pool.QueryRow(ctx, recordDeliveryAttempt, uid, status, lastError))
+}
+
+// EnqueueJobRun queues a run. A run whose DedupeKey was already queued
+// isn't queued again, and pgx.ErrNoRows is returned.
+func (d *DAO) EnqueueJobRun(ctx context.Context, r JobRun) (JobRun, error) {
+	payload := r.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage("{}")
+	}
+	return scanJobRun(d.pool.QueryRow(ctx, enqueueJobRun, r.Name, payload, r.DedupeKey, r.MaxAttempts, r.RunAt))
+}
+
+// ClaimJobRun starts the queued run that has been due longest, or one
+// still running since before staleBefore, whose worker is presumed dead.
+// With nothing to run it returns pgx.ErrNoRows.
+func (d *DAO) ClaimJobRun(ctx context.Context, staleBefore time.Time) (JobRun, error) {
+	return scanJobRun(d.pool.QueryRow(ctx, claimJobRun, staleBefore))
+}
+
+// FinishJobRun records how an attempt at a run went. A status of
+// JobQueued puts it back in the queue, for retryAt.
+func (d *DAO) FinishJobRun(ctx context.Context, uid, status string, lastError *string, retryAt *time.Time) (JobRun, error) {
+	return scanJobRun(d.pool.QueryRow(ctx, finishJobRun, uid, status, lastError, retryAt))
+}
+
+// PostponeJobRun queues a claimed run again for runAt without counting
+// the attempt, for a run that couldn't be started.
+func (d *DAO) PostponeJobRun(ctx context.Context, uid string, runAt time.Time) (JobRun, error) {
+	return scanJobRun(d.pool.QueryRow(ctx, postponeJobRun, uid, runAt))
+}
+
+// LockJob takes a lock on a job, held until unlock is called, so that its
+// runs never overlap, however many servers are running. ok is false, with
+// nothing to unlock, while another run holds it. The lock is a
+// transaction-level advisory lock: it keeps a connection from the pool
+// until it is released, and is released by Postgres if that connection
+// is lost.
+func (d *DAO) LockJob(ctx context.Context, name string) (unlock func(), ok bool, err error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.QueryRow(ctx, lockJob, name).Scan(&ok); err != nil || !ok {
+		_ = tx.Rollback(ctx)
+		return nil, false, err
+	}
+	return func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }, true, nil
+}
+
+func (d *DAO) GetJobRun(ctx context.Context, uid string) (JobRun, error) {
+	return scanJobRun(d.pool.QueryRow(ctx, getJobRun, uid))
+}
+
+func (d *DAO) ListJobRuns(ctx context.Context, options ListOptions) ([]JobRun, error) {
+	return jobRunColumns.list(ctx, d.pool, "job_runs", options, []JobRun{})
+}
+
+// Ping checks that the database answers.
+func (d *DAO) Ping(ctx context.Context) error {
+	_, err := d.pool.Exec(ctx, ping)
+	return err
+}
+
+// UsageStats reports the size and use of every table and index in the
+// current schema, largest first.
+func (d *DAO) UsageStats(ctx context.Context) (UsageStats, error) {
+	var out UsageStats
+	if err := d.pool.QueryRow(ctx, getStatsReset).Scan(&out.StatsReset); err != nil {
+		return out, err
+	}
+	var err error
+	if out.Tables, err = listUsage(ctx, d.pool, listTableUsage, func(rows pgx.Rows) (TableUsage, error) {
+		var t TableUsage
+		err := rows.Scan(&t.Name, &t.Rows, &t.DeadRows, &t.TotalBytes, &t.IndexBytes, &t.SeqScans, &t.IndexScans)
+		return t, err
+	}); err != nil {
+		return out, err
+	}
+	out.Indexes, err = listUsage(ctx, d.pool, listIndexUsage, func(rows pgx.Rows) (IndexUsage, error) {
+		var i IndexUsage
+		err := rows.Scan(&i.Table, &i.Name, &i.Bytes, &i.Scans)
+		return i, err
+	})
+	return out, err
+}
+
+func listUsage[T any](ctx context.Context, q queryer, sql string, scan func(pgx.Rows) (T, error)) ([]T, error) {
+	rows, err := q.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []T{}
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// searchTarget is an entity Search covers: its table and the columns
+// naming a row and holding its text. The table's search_vector is built
+// from the same columns.
+type searchTarget struct {
+	table, id, title, body string
+}
+
+var searchTargets = map[string]searchTarget{
+	"todos":   {table: "todos", id: "uid", title: "title", body: "coalesce(description, '')"},
+	"notes":   {table: "notes", id: "id", title: "key", body: "data"},
+	"recipes": {table: "recipes", id: "id", title: "title", body: "data"},
+}
+
+// SearchTargets are the entities Search accepts.
+var SearchTargets = []string{"todos", "notes", "recipes"}
+
+// ErrInvalidSearchTarget is returned by Search for an entity that isn't
+// one of SearchTargets.
+var ErrInvalidSearchTarget = errors.New("invalid search target")
+
+// ts_headline marks matches with private-use characters, which are then
+// replaced with <mark> once the snippet is escaped.
+const (
+	markStart       = "\ue000"
+	markStop        = "\ue001"
+	headlineOptions = "StartSel=" + markStart + ", StopSel=" + markStop + `, MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=" … "`
+)
+
+var snippetMarks = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// Search returns the rows of target, one of SearchTargets, that match
+// query, best first. query is a web-style search: words, "quoted phrases",
+// OR and -excluded words. options.WhereClause narrows the rows and
+// options.Limit caps them; the offset and sort are ignored.
+func (d *DAO) Search(ctx context.Context, target, query string, options ListOptions) ([]SearchHit, error) {
+	t, ok := searchTargets[target]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSearchTarget, target)
+	}
+	var conditions string
+	if where := strings.TrimPrefix(options.WhereClause, "WHERE "); where != "" {
+		conditions = " AND (" + where + ")"
+	}
+	n := len(options.WhereArgs)
+	sql := fmt.Sprintf(searchEntity, t.table, t.id, t.title, t.body, conditions, n+1, n+2, n+3)
+	args := append(slices.Clip(options.WhereArgs), query, headlineOptions, options.Limit)
+	rows, err := d.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []SearchHit{}
+	for rows.Next() {
+		var h SearchHit
+		if err := rows.Scan(&h.ID, &h.Title, &h.Snippet, &h.Rank, &h.UserUID, &h.HouseholdUID, &h.UpdatedAt); err != nil {
+			return nil, err
+		}
+		h.Snippet = snippetMarks.Replace(html.EscapeString(h.Snippet))
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) CreateAwayPeriod(ctx context.Context, a AwayPeriod) (AwayPeriod, error) {
+	return scanAwayPeriod(d.pool.QueryRow(ctx, insertAwayPeriod, a.UserUID, a.StartsOn, a.EndsOn, a.Note))
+}
+
+// ListAwayPeriodsByUserUID returns a user's away periods that end on or
+// after from, soonest first.
+func (d *DAO) ListAwayPeriodsByUserUID(ctx context.Context, userUID string, from time.Time) ([]AwayPeriod, error) {
+	return d.listAwayPeriods(ctx, listAwayPeriodsByUserUID, userUID, from)
+}
+
+// ListAwayPeriodsByHouseholdUID returns the away periods of every member of
+// a household that end on or after from, soonest first.
+func (d *DAO) ListAwayPeriodsByHouseholdUID(ctx context.Context, householdUID string, from time.Time) ([]AwayPeriod, error) {
+	return d.listAwayPeriods(ctx, listAwayPeriodsByHouseholdUID, householdUID, from)
+}
+
+func (d *DAO) listAwayPeriods(ctx context.Context, query string, args ...any) ([]AwayPeriod, error) {
+	rows, err := d.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []AwayPeriod{}
+	for rows.Next() {
+		a, err := scanAwayPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) DeleteAwayPeriod(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteAwayPeriod, uid)
+	return err
+}
+
+func (d *DAO) CreateImportantDate(ctx context.Context, i ImportantDate) (ImportantDate, error) {
+	return scanImportantDate(d.pool.QueryRow(ctx, insertImportantDate, i.HouseholdUID, i.Title, i.Kind, i.Date, i.Recurrence, i.RemindDaysAhead, i.Notes))
+}
+
+// ListImportantDates returns a household's important dates in calendar
+// order, ignoring the year.
+func (d *DAO) ListImportantDates(ctx context.Context, householdUID string) ([]ImportantDate, error) {
+	rows, err := d.pool.Query(ctx, listImportantDates, householdUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ImportantDate{}
+	for rows.Next() {
+		i, err := scanImportantDate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, i)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) DeleteImportantDate(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteImportantDate, uid)
+	return err
+}
+
+// CreateRetentionPolicy adds a policy, replacing any for the same entity
+// and household.
+func (d *DAO) CreateRetentionPolicy(ctx context.Context, p RetentionPolicy) (RetentionPolicy, error) {
+	return scanRetentionPolicy(d.pool.QueryRow(ctx, upsertRetentionPolicy, p.Entity, p.HouseholdUID, p.MaxAgeDays, p.Action))
+}
+
+func (d *DAO) ListRetentionPolicies(ctx context.Context) ([]RetentionPolicy, error) {
+	rows, err := d.pool.Query(ctx, listRetentionPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []RetentionPolicy{}
+	for rows.Next() {
+		p, err := scanRetentionPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) DeleteRetentionPolicy(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteRetentionPolicy, uid)
+	return err
+}
+
+// PutDataSchema registers the schema for an entity's key, replacing any it
+// had.
+func (d *DAO) PutDataSchema(ctx context.Context, s DataSchema) (DataSchema, error) {
+	return scanDataSchema(d.pool.QueryRow(ctx, upsertDataSchema, s.Entity, s.Key, string(s.Schema)))
+}
+
+// FindDataSchema returns the schema registered for an entity's key, or nil
+// when there is none.
+func (d *DAO) FindDataSchema(ctx context.Context, entity, key string) (*DataSchema, error) {
+	s, err := scanDataSchema(d.pool.QueryRow(ctx, getDataSchema, entity, key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (d *DAO) ListDataSchemas(ctx context.Context) ([]DataSchema, error) {
+	rows, err := d.pool.Query(ctx, listDataSchemas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []DataSchema{}
+	for rows.Next() {
+		s, err := scanDataSchema(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) DeleteDataSchema(ctx context.Context, entity, key string) error {
+	_, err := d.pool.Exec(ctx, deleteDataSchema, entity, key)
+	return err
+}
+
+// ArchiveNotesBefore archives the unpinned notes of a household, or of
+// every household when householdUID is empty, last updated before before.
+// It returns how many it archived.
+func (d *DAO) ArchiveNotesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error) {
+	return d.execCount(ctx, archiveNotesBefore, householdUID, before)
+}
+
+// DeleteNotesBefore deletes unpinned notes like ArchiveNotesBefore archives
+// them.
+func (d *DAO) DeleteNotesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error) {
+	return d.execCount(ctx, deleteNotesBefore, householdUID, before)
+}
+
+// DeleteCompletedTodosBefore deletes the todos of a household, or of every
+// household when householdUID is empty, completed before before.
+func (d *DAO) DeleteCompletedTodosBefore(ctx context.Context, householdUID string, before time.Time) (int64, error) {
+	return d.execCount(ctx, deleteCompletedTodosBefore, householdUID, before)
+}
+
+// DeleteGroceryPurchasesBefore deletes the purchases of a household, or of
+// every household when householdUID is empty, made before before.
+func (d *DAO) DeleteGroceryPurchasesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error) {
+	return d.execCount(ctx, deleteGroceryPurchasesBefore, householdUID, before)
+}
+
+func (d *DAO) execCount(ctx context.Context, query string, args ...any) (int64, error) {
+	tag, err := d.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GroceryMonthlySpend totals a household's purchases per month and store for
+// purchases on or after from and before to.
+func (d *DAO) GroceryMonthlySpend(ctx context.Context, householdUID string, from, to time.Time) ([]GrocerySpend, error) {
+	rows, err := d.pool.Query(ctx, groceryMonthlySpend, householdUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []GrocerySpend{}
+	for rows.Next() {
+		var s GrocerySpend
+		if err := rows.Scan(&s.Month, &s.Store, &s.TotalCents, &s.Purchases); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) CreateBackground(ctx context.Context, b Background) (Background, error) {
+	row := d.pool.QueryRow(ctx, insertBackground, b.Key, b.Value, b.ExpiresAt)
+	return scanBackground(row)
+}
+
+func (d *DAO) GetBackground(ctx context.Context, key string) (Background, error) {
+	return scanBackground(d.pool.QueryRow(ctx, getBackground, key))
+}
+
+func (d *DAO) ListBackgrounds(ctx context.Context, options ListOptions) ([]Background, error) {
+	return backgroundColumns.list(ctx, d.pool, "backgrounds", options, nil)
+}
+
+func (d *DAO) UpdateBackground(ctx context.Context, key string, b Background) (Background, error) {
+	row := d.pool.QueryRow(ctx, updateBackground, key, b.Value, b.ExpiresAt)
+	return scanBackground(row)
+}
+
+func (d *DAO) DeleteBackground(ctx context.Context, key string) error {
+	_, err := d.pool.Exec(ctx, deleteBackground, key)
+	return err
+}
+
+// PutBackground creates b or replaces the value and expiry of the
+// background under its key.
+func (d *DAO) PutBackground(ctx context.Context, b Background) (Background, error) {
+	return scanBackground(d.pool.QueryRow(ctx, upsertBackground, b.Key, b.Value, b.ExpiresAt))
+}
+
+// ListBackgroundsByPrefix returns the unexpired backgrounds whose keys
+// start with prefix, by key.
+func (d *DAO) ListBackgroundsByPrefix(ctx context.Context, prefix string) ([]Background, error) {
+	rows, err := d.pool.Query(ctx, listBackgroundsByPrefix, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Background{}
+	for rows.Next() {
+		b, err := scanBackground(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// DeleteExpiredBackgrounds deletes the backgrounds past their expiry and
+// returns how many there were.
+func (d *DAO) DeleteExpiredBackgrounds(ctx context.Context) (int64, error) {
+	tag, err := d.pool.Exec(ctx, deleteExpiredBackgrounds)
+	return tag.RowsAffected(), err
+}
+
+func (d *DAO) CreatePreferences(ctx context.Context, p Preferences) (Preferences, error) {
+	row := d.pool.QueryRow(ctx, insertPreferences, p.Key, p.Specifier, p.Data, p.Tags)
+	return scanPreferences(row)
+}
+
+func (d *DAO) GetPreferences(ctx context.Context, key, specifier string) (Preferences, error) {
+	return scanPreferences(d.pool.QueryRow(ctx, getPreferences, key, specifier))
+}
+
+func (d *DAO) ListPreferences(ctx context.Context, options ListOptions) ([]Preferences, error) {
+	return preferencesColumns.list(ctx, d.pool, "preferences", options, nil)
+}
+
+func (d *DAO) UpdatePreferences(ctx context.Context, key, specifier string, p Preferences) (Preferences, error) {
+	row := d.pool.QueryRow(ctx, updatePreferences, key, specifier, p.Data, p.Tags)
+	return scanPreferences(row)
+}
+
+func (d *DAO) DeletePreferences(ctx context.Context, key, specifier string) error {
+	_, err := d.pool.Exec(ctx, deletePreferences, key, specifier)
+	return err
+}
+
+// GetPreferencesBulk returns the preferences with the given keys in one
+// query, in the order asked for. Keys with no preference are left out.
+func (d *DAO) GetPreferencesBulk(ctx context.Context, keys []PreferenceKey) ([]Preferences, error) {
+	names := make([]string, len(keys))
+	specifiers := make([]string, len(keys))
+	for i, k := range keys {
+		names[i], specifiers[i] = k.Key, k.Specifier
+	}
+	rows, err := d.pool.Query(ctx, getPreferencesBulk, names, specifiers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Preferences{}
+	for rows.Next() {
+		p, err := scanPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// SetPreferencesBulk creates or replaces every preference in one
+// transaction, so either all of them are saved or none are.
+func (d *DAO) SetPreferencesBulk(ctx context.Context, prefs []Preferences) ([]Preferences, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	out := make([]Preferences, 0, len(prefs))
+	for _, p := range prefs {
+		saved, err := scanPreferences(tx.QueryRow(ctx, upsertPreferences, p.Key, p.Specifier, p.Data, p.Tags))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, saved)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (d *DAO) CreateNotes(ctx context.Context, n Notes) (Notes, error) {
+	userUID, householdUID := handleUIDRefs(n.UserUID, n.HouseholdUID)
+	visibility := n.Visibility
+	if visibility == "" {
+		visibility = NoteVisibilityHousehold
+	}
+	row := d.pool.QueryRow(ctx, insertNotes, n.Key, userUID, householdUID, n.Data, n.Tags, visibility, n.RemindAt)
+	out, err := scanNotes(row)
+	return out, quotaErr(err)
+}
+
+func (d *DAO) GetNotes(ctx context.Context, id string) (Notes, error) {
+	return scanNotes(d.pool.QueryRow(ctx, getNotes, id))
+}
+
+func (d *DAO) ListNotes(ctx context.Context, options ListOptions) ([]Notes, error) {
+	return notesColumns.list(ctx, d.pool, "notes", options, nil)
+}
+
+func (d *DAO) UpdateNotes(ctx context.Context, id string, n Notes) (Notes, error) {
+	row := d.pool.QueryRow(ctx, updateNotes, id, n.Key, n.UserUID, n.HouseholdUID, n.Data, n.Tags, n.Visibility, n.RemindAt)
+	return scanNotes(row)
+}
+
+// SetNoteReminder sets when a note's owner is reminded of it. A nil
+// remindAt cancels the reminder.
+func (d *DAO) SetNoteReminder(ctx context.Context, id string, remindAt *time.Time) (Notes, error) {
+	return scanNotes(d.pool.QueryRow(ctx, remindNotes, id, remindAt))
+}
+
+// SetNotePinned pins or unpins a note. Pinned notes are ordered by sortOrder,
+// lowest first.
+func (d *DAO) SetNotePinned(ctx context.Context, id string, pinned bool, sortOrder int) (Notes, error) {
+	row := d.pool.QueryRow(ctx, pinNotes, id, pinned, sortOrder)
+	return scanNotes(row)
+}
+
+func (d *DAO) DeleteNotes(ctx context.Context, id string) error {
+	_, err := d.pool.Exec(ctx, deleteNotes, id)
+	return err
+}
+
+// ArchiveNotes hides notes from bootstrap and list_notes without deleting
+// them.
+func (d *DAO) ArchiveNotes(ctx context.Context, ids []string) error {
+	_, err := d.pool.Exec(ctx, archiveNotes, ids)
+	return err
+}
+
+func (d *DAO) CreateCredentials(ctx context.Context, c Credentials) (Credentials, error) {
+	row := d.pool.QueryRow(ctx, insertCredentials, c.UserUID, c.CredentialType, c.Value)
+	return scanCredentials(row)
+}
+
+func (d *DAO) GetCredentials(ctx context.Context, id string) (Credentials, error) {
+	return scanCredentials(d.pool.QueryRow(ctx, getCredentials, id))
+}
+
+func (d *DAO) GetCredentialsByUserAndType(ctx context.Context, userID, credentialType string) (Credentials, error) {
+	return scanCredentials(d.pool.QueryRow(ctx, getCredentialsByUserAndType, userID, credentialType))
+}
+
+func (d *DAO) ListCredentials(ctx context.Context, options ListOptions) ([]Credentials, error) {
+	return credentialsColumns.list(ctx, d.pool, "credentials", options, nil)
+}
+
+func (d *DAO) UpdateCredentials(ctx context.Context, id string, c Credentials) (Credentials, error) {
+	row := d.pool.QueryRow(ctx, updateCredentials, id, c.UserUID, c.CredentialType, c.Value)
+	return scanCredentials(row)
+}
+
+func (d *DAO) DeleteCredentials(ctx context.Context, id string) error {
+	_, err := d.pool.Exec(ctx, deleteCredentials, id)
+	return err
+}
+
+func (d *DAO) GetSlackUser(ctx context.Context, slackUserUID string) (SlackUsers, error) {
+	return scanSlackUser(d.pool.QueryRow(ctx, getSlackUser, slackUserUID))
+}
+
+func (d *DAO) GetUserBySlackUserUID(ctx context.Context, slackUserUID string) (Users, error) {
+	return scanUser(d.pool.QueryRow(ctx, getUserBySlackUserUID, slackUserUID))
+}
+
+func (d *DAO) GetCredentialsByUserUID(ctx context.Context, userUID string) ([]Credentials, error) {
+	rows, err := d.pool.Query(ctx, getCredentialsByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Credentials
+	for rows.Next() {
+		c, err := scanCredentials(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) CreateUser(ctx context.Context, u Users) (Users, error) {
+	row := d.pool.QueryRow(ctx, insertUser, u.Name, u.Email, u.Description, u.HouseholdUID, u.Pronouns, u.Birthday, u.Phone)
+	return scanUser(row)
+}
+
+func (d *DAO) UpdateUser(ctx context.Context, uid string, u UpdateUser) (Users, error) {
+	row := d.pool.QueryRow(ctx, updateUser, uid, u.Name, u.Email, u.Description, u.HouseholdUID, u.Pronouns, u.Birthday, u.Phone)
+	return scanUser(row)
+}
+
+func (d *DAO) GetUser(ctx context.Context, uid string) (Users, error) {
+	return scanUser(d.pool.QueryRow(ctx, getUser, uid))
+}
+
+// ListHouseholdMembers returns the users in a household, by name.
+func (d *DAO) ListHouseholdMembers(ctx context.Context, householdUID string) ([]Users, error) {
+	rows, err := d.pool.Query(ctx, listHouseholdMembers, householdUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Users
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// SetUserAvatar replaces all renditions of a user's avatar with avatars.
+func (d *DAO) SetUserAvatar(ctx context.Context, userUID string, avatars []UserAvatar) (Users, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return Users{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, deleteUserAvatars, userUID); err != nil {
+		return Users{}, err
+	}
+	for _, a := range avatars {
+		if _, err := tx.Exec(ctx, insertUserAvatar, userUID, a.Size, a.ContentType, a.Width, a.Height, a.Data); err != nil {
+			return Users{}, err
+		}
+	}
+	u, err := scanUser(tx.QueryRow(ctx, setUserAvatarTime, userUID, time.Now()))
+	if err != nil {
+		return Users{}, err
+	}
+	return u, tx.Commit(ctx)
+}
+
+func (d *DAO) GetUserAvatar(ctx context.Context, userUID, size string) (UserAvatar, error) {
+	var a UserAvatar
+	err := d.pool.QueryRow(ctx, getUserAvatar, userUID, size).Scan(&a.UserUID, &a.Size, &a.ContentType, &a.Width, &a.Height, &a.Data, &a.CreatedAt)
+	return a, err
+}
+
+func (d *DAO) DeleteUserAvatar(ctx context.Context, userUID string) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, deleteUserAvatars, userUID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, setUserAvatarTime, userUID, nil); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (d *DAO) CreateHousehold(ctx context.Context, h Households) (Households, error) {
+	return scanHousehold(d.pool.QueryRow(ctx, insertHousehold, h.Name, h.Description))
+}
+
+func (d *DAO) GetHousehold(ctx context.Context, uid string) (Households, error) {
+	return scanHousehold(d.pool.QueryRow(ctx, getHousehold, uid))
+}
+
+// GetUsers returns the users among uids in one query, in no particular
+// order. UIDs that aren't users are skipped.
+func (d *DAO) GetUsers(ctx context.Context, uids []string) ([]Users, error) {
+	rows, err := d.pool.Query(ctx, getUsers, uids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Users
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// GetHouseholds is GetUsers for households.
+func (d *DAO) GetHouseholds(ctx context.Context, uids []string) ([]Households, error) {
+	rows, err := d.pool.Query(ctx, getHouseholds, uids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Households
+	for rows.Next() {
+		h, err := scanHousehold(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) UpdateHousehold(ctx context.Context, uid string, h UpdateHousehold) (Households, error) {
+	row := d.pool.QueryRow(ctx, updateHousehold, uid, h.Name, h.Description)
+	return scanHousehold(row)
+}
+
+func (d *DAO) GetTodosByUserUID(ctx context.Context, userUID string) ([]Todo, error) {
+	rows, err := d.pool.Query(ctx, getTodosByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Todo
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) GetNotesByUserUID(ctx context.Context, userUID string) ([]Notes, error) {
+	rows, err := d.pool.Query(ctx, getNotesByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Notes
+	for rows.Next() {
+		n, err := scanNotes(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) GetPreferencesByUserUID(ctx context.Context, userUID string) ([]Preferences, error) {
+	rows, err := d.pool.Query(ctx, getPreferencesByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Preferences
+	for rows.Next() {
+		p, err := scanPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) CreateRecipes(ctx context.Context, r Recipes) (Recipes, error) {
+	userUID, householdUID := handleUIDRefs(r.UserUID, r.HouseholdUID)
+	row := d.pool.QueryRow(ctx, insertRecipes, r.Title, r.ExternalURL, r.Data, r.Genre, r.GroceryList, r.PrepTime, r.CookTime, r.TotalTime, r.Servings, r.Difficulty, r.Tags, userUID, householdUID)
+	out, err := scanRecipes(row)
+	return out, quotaErr(err)
+}
+
+func (d *DAO) GetRecipes(ctx context.Context, id string) (Recipes, error) {
+	return scanRecipes(d.pool.QueryRow(ctx, getRecipes, id))
+}
+
+func (d *DAO) ListRecipes(ctx context.Context, options ListOptions) ([]Recipes, error) {
+	return recipesColumns.list(ctx, d.pool, "recipes", options, nil)
+}
+
+func (d *DAO) UpdateRecipes(ctx context.Context, id string, r Recipes) (Recipes, error) {
+	row := d.pool.QueryRow(ctx, updateRecipes, id, r.Title, r.ExternalURL, r.Data, r.Genre, r.GroceryList, r.PrepTime, r.CookTime, r.TotalTime, r.Servings, r.Difficulty, r.Tags, r.UserUID, r.HouseholdUID)
+	return scanRecipes(row)
+}
+
+// RateRecipe sets userUID's rating of a recipe, 1 to 5, replacing any they
+// gave before. It returns the recipe with its new average and, as
+// MyRating, the rating just given. A recipe that doesn't exist or isn't
+// visible is pgx.ErrNoRows.
+func (d *DAO) RateRecipe(ctx context.Context, recipeID, userUID string, rating int) (Recipes, error) {
+	if rating < 1 || rating > 5 {
+		return Recipes{}, ErrInvalidRating
+	}
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return Recipes{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, rateRecipe, recipeID, userUID, rating)
+	if err != nil {
+		return Recipes{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return Recipes{}, pgx.ErrNoRows
+	}
+	r, err := scanRecipes(tx.QueryRow(ctx, getRecipes, recipeID))
+	if err != nil {
+		return Recipes{}, err
+	}
+	r.MyRating = &rating
+	return r, tx.Commit(ctx)
+}
+
+func (d *DAO) DeleteRecipes(ctx context.Context, id string) error {
+	_, err := d.pool.Exec(ctx, deleteRecipes, id)
+	return err
+}
+
+// SetRecipePhoto replaces all renditions of a recipe's photo with photos.
+func (d *DAO) SetRecipePhoto(ctx context.Context, recipeID string, photos []RecipePhoto) (Recipes, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return Recipes{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, deleteRecipePhotos, recipeID); err != nil {
+		return Recipes{}, err
+	}
+	for _, p := range photos {
+		if _, err := tx.Exec(ctx, insertRecipePhoto, recipeID, p.Size, p.ContentType, p.Width, p.Height, p.Data); err != nil {
+			return Recipes{}, quotaErr(err)
+		}
+	}
+	r, err := scanRecipes(tx.QueryRow(ctx, setRecipePhotoTime, recipeID, time.Now()))
+	if err != nil {
+		return Recipes{}, err
+	}
+	return r, tx.Commit(ctx)
+}
+
+func (d *DAO) GetRecipePhoto(ctx context.Context, recipeID, size string) (RecipePhoto, error) {
+	var p RecipePhoto
+	err := d.pool.QueryRow(ctx, getRecipePhoto, recipeID, size).Scan(&p.RecipeID, &p.Size, &p.ContentType, &p.Width, &p.Height, &p.Data, &p.CreatedAt)
+	return p, err
+}
+
+func (d *DAO) DeleteRecipePhoto(ctx context.Context, recipeID string) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, deleteRecipePhotos, recipeID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, setRecipePhotoTime, recipeID, nil); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (d *DAO) GetRecipesByUserUID(ctx context.Context, userUID string) ([]Recipes, error) {
+	rows, err := d.pool.Query(ctx, getRecipesByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Recipes
+	for rows.Next() {
+		r, err := scanRecipes(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) CreateAPIKey(ctx context.Context, k APIKeys) (APIKeys, error) {
+	row := d.pool.QueryRow(ctx, insertAPIKey, k.UserUID, k.Name, k.KeyHash, k.Scopes)
+	return scanAPIKey(row)
+}
+
+// GetAPIKeyByHash returns the unrevoked key with the given hash, along with
+// the household of the user that owns it.
+func (d *DAO) GetAPIKeyByHash(ctx context.Context, keyHash string) (APIKeys, error) {
+	return scanAPIKey(d.pool.QueryRow(ctx, getAPIKeyByHash, keyHash))
+}
+
+func (d *DAO) ListAPIKeysByUserUID(ctx context.Context, userUID string) ([]APIKeys, error) {
+	rows, err := d.pool.Query(ctx, listAPIKeysByUserUID, userUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []APIKeys
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+func (d *DAO) RevokeAPIKey(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, revokeAPIKey, uid)
+	return err
 }
 
-func (d *DAO) DeletePreferences(ctx context.Context, key, specifier string) error {
-	_, err := d.pool.Exec(ctx, deletePreferences, key, specifier)
+func (d *DAO) TouchAPIKey(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, touchAPIKey, uid)
 	return err
 }
 
-func (d *DAO) CreateNotes(ctx context.Context, n Notes) (Notes, error) {
-	userUID, householdUID := handleUIDRefs(n.UserUID, n.HouseholdUID)
-	row := d.pool.QueryRow(ctx, insertNotes, n.Key, userUID, householdUID, n.Data, n.Tags)
-	return scanNotes(row)
+func (d *DAO) CreateDashboardToken(ctx context.Context, t DashboardToken) (DashboardToken, error) {
+	return scanDashboardToken(d.pool.QueryRow(ctx, insertDashboardToken, t.HouseholdUID, t.Name, t.TokenHash, t.Sections))
 }
 
-func (d *DAO) GetNotes(ctx context.Context, id string) (Notes, error) {
-	return scanNotes(d.pool.QueryRow(ctx, getNotes, id))
+// GetDashboardTokenByHash returns the unrevoked token with the given hash.
+func (d *DAO) GetDashboardTokenByHash(ctx context.Context, tokenHash string) (DashboardToken, error) {
+	return scanDashboardToken(d.pool.QueryRow(ctx, getDashboardTokenByHash, tokenHash))
 }
 
-func (d *DAO) ListNotes(ctx context.Context, options ListOptions) ([]Notes, error) {
-	notesColumns := "id, key, data, created_at, updated_at, user_uid, household_uid, tags"
-	query := buildListQuery("notes", notesColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
+func (d *DAO) ListDashboardTokens(ctx context.Context, householdUID string) ([]DashboardToken, error) {
+	rows, err := d.pool.Query(ctx, listDashboardTokens, householdUID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Notes
+	out := []DashboardToken{}
 	for rows.Next() {
-		n, err := scanNotes(rows)
+		t, err := scanDashboardToken(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, n)
+		out = append(out, t)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) UpdateNotes(ctx context.Context, id string, n Notes) (Notes, error) {
-	row := d.pool.QueryRow(ctx, updateNotes, id, n.Key, n.UserUID, n.HouseholdUID, n.Data, n.Tags)
-	return scanNotes(row)
-}
-
-func (d *DAO) DeleteNotes(ctx context.Context, id string) error {
-	_, err := d.pool.Exec(ctx, deleteNotes, id)
+func (d *DAO) RevokeDashboardToken(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, revokeDashboardToken, uid)
 	return err
 }
 
-func (d *DAO) CreateCredentials(ctx context.Context, c Credentials) (Credentials, error) {
-	row := d.pool.QueryRow(ctx, insertCredentials, c.UserUID, c.CredentialType, c.Value)
-	return scanCredentials(row)
-}
-
-func (d *DAO) GetCredentials(ctx context.Context, id string) (Credentials, error) {
-	return scanCredentials(d.pool.QueryRow(ctx, getCredentials, id))
-}
-
-func (d *DAO) GetCredentialsByUserAndType(ctx context.Context, userID, credentialType string) (Credentials, error) {
-	return scanCredentials(d.pool.QueryRow(ctx, getCredentialsByUserAndType, userID, credentialType))
+func (d *DAO) TouchDashboardToken(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, touchDashboardToken, uid)
+	return err
 }
 
-func (d *DAO) ListCredentials(ctx context.Context, options ListOptions) ([]Credentials, error) {
-	credentialsColumns := "*"
-	query := buildListQuery("credentials", credentialsColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
+// ListSyncChanges returns up to limit sync states changed after the since
+// seq, in feed order, for householdUID's entities and userUID's own.
+func (d *DAO) ListSyncChanges(ctx context.Context, householdUID, userUID string, since int64, limit int) ([]SyncState, error) {
+	rows, err := d.pool.Query(ctx, listSyncChanges, since, householdUID, userUID, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Credentials
+	out := []SyncState{}
 	for rows.Next() {
-		c, err := scanCredentials(rows)
+		s, err := scanSyncState(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, c)
+		out = append(out, s)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) UpdateCredentials(ctx context.Context, id string, c Credentials) (Credentials, error) {
-	row := d.pool.QueryRow(ctx, updateCredentials, id, c.UserUID, c.CredentialType, c.Value)
-	return scanCredentials(row)
-}
-
-func (d *DAO) DeleteCredentials(ctx context.Context, id string) error {
-	_, err := d.pool.Exec(ctx, deleteCredentials, id)
-	return err
+// GetSyncState returns pgx.ErrNoRows for an entity that was never synced.
+func (d *DAO) GetSyncState(ctx context.Context, entity, entityID string) (SyncState, error) {
+	return scanSyncState(d.pool.QueryRow(ctx, getSyncState, entity, entityID))
 }
 
-func (d *DAO) GetSlackUser(ctx context.Context, slackUserUID string) (SlackUsers, error) {
-	return scanSlackUser(d.pool.QueryRow(ctx, getSlackUser, slackUserUID))
+// SetSyncState records a change made through sync, replacing the version
+// the write itself counted as the server's. It moves the entity to the end
+// of the feed.
+func (d *DAO) SetSyncState(ctx context.Context, s SyncState) (SyncState, error) {
+	return scanSyncState(d.pool.QueryRow(ctx, upsertSyncState, s.Entity, s.EntityID, s.HouseholdUID, s.UserUID, s.Version, s.Deleted, s.UpdatedAt))
 }
 
-func (d *DAO) GetUserBySlackUserUID(ctx context.Context, slackUserUID string) (Users, error) {
-	return scanUser(d.pool.QueryRow(ctx, getUserBySlackUserUID, slackUserUID))
+// AddAPIUsage adds each entry's counts to what its key has on its day, in
+// one transaction, so a failed flush can be retried whole.
+func (d *DAO) AddAPIUsage(ctx context.Context, usage []APIUsage) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	for _, u := range usage {
+		if _, err := tx.Exec(ctx, addAPIUsage, u.APIKeyUID, u.Day, u.Requests, u.ToolCalls); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
-func (d *DAO) GetCredentialsByUserUID(ctx context.Context, userUID string) ([]Credentials, error) {
-	rows, err := d.pool.Query(ctx, getCredentialsByUserUID, userUID)
+// ListAPIUsage returns the usage of userUID's keys, or with an empty
+// userUID every key's, on the days from from to to inclusive.
+func (d *DAO) ListAPIUsage(ctx context.Context, userUID string, from, to time.Time) ([]APIUsage, error) {
+	rows, err := d.pool.Query(ctx, listAPIUsage, userUID, from, to)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Credentials
+	out := []APIUsage{}
 	for rows.Next() {
-		c, err := scanCredentials(rows)
-		if err != nil {
+		var u APIUsage
+		if err := rows.Scan(&u.APIKeyUID, &u.KeyName, &u.UserUID, &u.UserName, &u.HouseholdUID, &u.Day, &u.Requests, &u.ToolCalls); err != nil {
 			return nil, err
 		}
-		out = append(out, c)
+		out = append(out, u)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) CreateUser(ctx context.Context, u Users) (Users, error) {
-	row := d.pool.QueryRow(ctx, insertUser, u.Name, u.Email, u.Description, u.HouseholdUID)
-	return scanUser(row)
-}
-
-func (d *DAO) UpdateUser(ctx context.Context, uid string, u UpdateUser) (Users, error) {
-	row := d.pool.QueryRow(ctx, updateUser, uid, u.Name, u.Email, u.Description, u.HouseholdUID)
-	return scanUser(row)
+// AcquireEntityLock takes l's entity for l's key for ttl, or extends the
+// lock the key already holds, and returns the lock held afterwards: l's, or
+// another key's unexpired lock, which is left as it was.
+func (d *DAO) AcquireEntityLock(ctx context.Context, l EntityLock, ttl time.Duration) (EntityLock, error) {
+	return scanEntityLock(d.pool.QueryRow(ctx, acquireEntityLock, l.Entity, l.EntityID, l.APIKeyUID, l.UserUID, ttl.Seconds()))
 }
 
-func (d *DAO) GetUser(ctx context.Context, uid string) (Users, error) {
-	return scanUser(d.pool.QueryRow(ctx, getUser, uid))
+// GetEntityLock returns pgx.ErrNoRows when the entity isn't locked.
+func (d *DAO) GetEntityLock(ctx context.Context, entity, entityID string) (EntityLock, error) {
+	return scanEntityLock(d.pool.QueryRow(ctx, getEntityLock, entity, entityID))
 }
 
-func (d *DAO) GetHousehold(ctx context.Context, uid string) (Households, error) {
-	return scanHousehold(d.pool.QueryRow(ctx, getHousehold, uid))
+// ReleaseEntityLock removes the entity's lock if apiKeyUID holds it or it
+// has expired. Another key's lock is left in place.
+func (d *DAO) ReleaseEntityLock(ctx context.Context, entity, entityID string, apiKeyUID *string) error {
+	_, err := d.pool.Exec(ctx, releaseEntityLock, entity, entityID, apiKeyUID)
+	return err
 }
 
-func (d *DAO) UpdateHousehold(ctx context.Context, uid string, h UpdateHousehold) (Households, error) {
-	row := d.pool.QueryRow(ctx, updateHousehold, uid, h.Name, h.Description)
-	return scanHousehold(row)
+// PutQuota sets a household's quota or, without a HouseholdUID, the
+// default, replacing the one it had.
+func (d *DAO) PutQuota(ctx context.Context, q Quota) (Quota, error) {
+	return scanQuota(d.pool.QueryRow(ctx, upsertQuota, q.HouseholdUID, q.MaxTodos, q.MaxNotes, q.MaxRecipes, q.MaxPhotoBytes))
 }
 
-func (d *DAO) GetTodosByUserUID(ctx context.Context, userUID string) ([]Todo, error) {
-	rows, err := d.pool.Query(ctx, getTodosByUserUID, userUID)
+func (d *DAO) ListQuotas(ctx context.Context) ([]Quota, error) {
+	rows, err := d.pool.Query(ctx, listQuotas)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Todo
+	out := []Quota{}
 	for rows.Next() {
-		t, err := scanTodo(rows)
+		q, err := scanQuota(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, t)
+		out = append(out, q)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) GetNotesByUserUID(ctx context.Context, userUID string) ([]Notes, error) {
-	rows, err := d.pool.Query(ctx, getNotesByUserUID, userUID)
+func (d *DAO) DeleteQuota(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteQuota, uid)
+	return err
+}
+
+// PutFeatureFlag creates the flag f.Name or replaces its settings.
+func (d *DAO) PutFeatureFlag(ctx context.Context, f FeatureFlag) (FeatureFlag, error) {
+	if f.HouseholdUIDs == nil {
+		f.HouseholdUIDs = []string{}
+	}
+	return scanFeatureFlag(d.pool.QueryRow(ctx, upsertFeatureFlag, f.Name, f.Description, f.Enabled, f.RolloutPercent, f.HouseholdUIDs))
+}
+
+func (d *DAO) ListFeatureFlags(ctx context.Context) ([]FeatureFlag, error) {
+	rows, err := d.pool.Query(ctx, listFeatureFlags)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Notes
+	out := []FeatureFlag{}
 	for rows.Next() {
-		n, err := scanNotes(rows)
+		f, err := scanFeatureFlag(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, n)
+		out = append(out, f)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) GetPreferencesByUserUID(ctx context.Context, userUID string) ([]Preferences, error) {
-	rows, err := d.pool.Query(ctx, getPreferencesByUserUID, userUID)
+func (d *DAO) DeleteFeatureFlag(ctx context.Context, name string) error {
+	_, err := d.pool.Exec(ctx, deleteFeatureFlag, name)
+	return err
+}
+
+// GetQuotaUsage returns what householdUID stores and the quota that
+// applies to it, its own or the default; Quota is nil when there's none.
+func (d *DAO) GetQuotaUsage(ctx context.Context, householdUID string) (QuotaUsage, error) {
+	u := QuotaUsage{HouseholdUID: householdUID}
+	if err := d.pool.QueryRow(ctx, getQuotaUsage, householdUID).Scan(&u.Todos, &u.Notes, &u.Recipes, &u.PhotoBytes); err != nil {
+		return QuotaUsage{}, err
+	}
+	q, err := scanQuota(d.pool.QueryRow(ctx, getHouseholdQuota, householdUID))
+	switch {
+	case err == nil:
+		u.Quota = &q
+	case !errors.Is(err, pgx.ErrNoRows):
+		return QuotaUsage{}, err
+	}
+	return u, nil
+}
+
+// CreateJoinRequest records userUID asking to join householdUID.
+func (d *DAO) CreateJoinRequest(ctx context.Context, householdUID, userUID, message string) (JoinRequest, error) {
+	r, err := scanJoinRequest(d.pool.QueryRow(ctx, insertJoinRequest, householdUID, userUID, message))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.ConstraintName {
+		case "idx_household_join_requests_pending":
+			return JoinRequest{}, ErrJoinRequestPending
+		case "household_join_requests_household_uid_fkey":
+			return JoinRequest{}, ErrUnknownHousehold
+		}
+	}
+	return r, err
+}
+
+// GetJoinRequest returns pgx.ErrNoRows for a request that doesn't exist or
+// isn't visible.
+func (d *DAO) GetJoinRequest(ctx context.Context, uid string) (JoinRequest, error) {
+	return scanJoinRequest(d.pool.QueryRow(ctx, getJoinRequest, uid))
+}
+
+// ListJoinRequests returns the visible requests, newest first, to join
+// householdUID and with status when they aren't empty.
+func (d *DAO) ListJoinRequests(ctx context.Context, householdUID, status string) ([]JoinRequest, error) {
+	rows, err := d.pool.Query(ctx, listJoinRequests, householdUID, status)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Preferences
+	out := []JoinRequest{}
 	for rows.Next() {
-		p, err := scanPreferences(rows)
+		r, err := scanJoinRequest(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, p)
+		out = append(out, r)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) CreateRecipes(ctx context.Context, r Recipes) (Recipes, error) {
-	userUID, householdUID := handleUIDRefs(r.UserUID, r.HouseholdUID)
-	row := d.pool.QueryRow(ctx, insertRecipes, r.Title, r.ExternalURL, r.Data, r.Genre, r.GroceryList, r.PrepTime, r.CookTime, r.TotalTime, r.Servings, r.Difficulty, r.Rating, r.Tags, userUID, householdUID)
-	return scanRecipes(row)
+// DecideJoinRequest approves or denies a pending request, as status says,
+// on behalf of decidedBy if it isn't empty. Approving makes the requester
+// a member of the household, leaving any other. A request that isn't
+// pending, or isn't visible, is pgx.ErrNoRows.
+func (d *DAO) DecideJoinRequest(ctx context.Context, uid, status, decidedBy string) (JoinRequest, error) {
+	if status != JoinApproved && status != JoinDenied {
+		return JoinRequest{}, fmt.Errorf("%w %q", ErrInvalidStatus, status)
+	}
+	var by *string
+	if decidedBy != "" {
+		by = &decidedBy
+	}
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var householdUID, userUID string
+	if err := tx.QueryRow(ctx, lockJoinRequest, uid).Scan(&householdUID, &userUID); err != nil {
+		return JoinRequest{}, err
+	}
+	// The requester is only visible to the household while their request
+	// is pending, so they join before it is marked approved.
+	if status == JoinApproved {
+		tag, err := tx.Exec(ctx, joinHousehold, userUID, householdUID)
+		if err != nil {
+			return JoinRequest{}, err
+		}
+		if tag.RowsAffected() == 0 {
+			return JoinRequest{}, pgx.ErrNoRows
+		}
+	}
+	r, err := scanJoinRequest(tx.QueryRow(ctx, decideJoinRequest, uid, status, by))
+	if err != nil {
+		return JoinRequest{}, err
+	}
+	return r, tx.Commit(ctx)
 }
 
-func (d *DAO) GetRecipes(ctx context.Context, id string) (Recipes, error) {
-	return scanRecipes(d.pool.QueryRow(ctx, getRecipes, id))
+func (d *DAO) CreateToolPolicy(ctx context.Context, p ToolPolicy) (ToolPolicy, error) {
+	row := d.pool.QueryRow(ctx, insertToolPolicy, p.UserUID, p.HouseholdUID, p.AllowedTools, p.DisallowedTools)
+	return scanToolPolicy(row)
 }
 
-func (d *DAO) ListRecipes(ctx context.Context, options ListOptions) ([]Recipes, error) {
-	recipesColumns := "id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at"
-	query := buildListQuery("recipes", recipesColumns, options)
-	args := append(options.WhereArgs, options.Limit, options.Offset)
-	rows, err := d.pool.Query(ctx, query, args...)
+func (d *DAO) GetToolPolicy(ctx context.Context, uid string) (ToolPolicy, error) {
+	return scanToolPolicy(d.pool.QueryRow(ctx, getToolPolicy, uid))
+}
+
+func (d *DAO) ListToolPolicies(ctx context.Context, options ListOptions) ([]ToolPolicy, error) {
+	return toolPolicyColumns.list(ctx, d.pool, "tool_policies", options, nil)
+}
+
+// GetToolPoliciesForUser returns the policies that apply to a user: their
+// own and their household's, if any.
+func (d *DAO) GetToolPoliciesForUser(ctx context.Context, userUID string, householdUID *string) ([]ToolPolicy, error) {
+	rows, err := d.pool.Query(ctx, getToolPoliciesForUser, userUID, householdUID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Recipes
+	var out []ToolPolicy
 	for rows.Next() {
-		r, err := scanRecipes(rows)
+		p, err := scanToolPolicy(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, r)
+		out = append(out, p)
 	}
 	return out, rows.Err()
 }
 
-func (d *DAO) UpdateRecipes(ctx context.Context, id string, r Recipes) (Recipes, error) {
-	row := d.pool.QueryRow(ctx, updateRecipes, id, r.Title, r.ExternalURL, r.Data, r.Genre, r.GroceryList, r.PrepTime, r.CookTime, r.TotalTime, r.Servings, r.Difficulty, r.Rating, r.Tags, r.UserUID, r.HouseholdUID)
-	return scanRecipes(row)
+func (d *DAO) UpdateToolPolicy(ctx context.Context, uid string, p ToolPolicy) (ToolPolicy, error) {
+	row := d.pool.QueryRow(ctx, updateToolPolicy, uid, p.AllowedTools, p.DisallowedTools)
+	return scanToolPolicy(row)
 }
 
-func (d *DAO) DeleteRecipes(ctx context.Context, id string) error {
-	_, err := d.pool.Exec(ctx, deleteRecipes, id)
+func (d *DAO) DeleteToolPolicy(ctx context.Context, uid string) error {
+	_, err := d.pool.Exec(ctx, deleteToolPolicy, uid)
 	return err
 }
 
-func (d *DAO) GetRecipesByUserUID(ctx context.Context, userUID string) ([]Recipes, error) {
-	rows, err := d.pool.Query(ctx, getRecipesByUserUID, userUID)
+func (d *DAO) CreateTenant(ctx context.Context, t Tenant) (Tenant, error) {
+	return scanTenant(d.pool.QueryRow(ctx, insertTenant, t.Name))
+}
+
+func (d *DAO) GetTenant(ctx context.Context, uid string) (Tenant, error) {
+	return scanTenant(d.pool.QueryRow(ctx, getTenant, uid))
+}
+
+func (d *DAO) ListTenants(ctx context.Context) ([]Tenant, error) {
+	rows, err := d.pool.Query(ctx, listTenants)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []Recipes
+	var out []Tenant
 	for rows.Next() {
-		r, err := scanRecipes(rows)
+		t, err := scanTenant(rows)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, r)
+		out = append(out, t)
 	}
 	return out, rows.Err()
 }
@@ -543,36 +3435,127 @@ type scannable interface {
 	Scan(dest ...any) error
 }
 
+var todoColumns = columnSet[Todo]{
+	names: []string{"uid", "title", "description", "data", "priority", "status", "due_date", "recurs_on", "marked_complete", "external_url", "user_uid", "household_uid", "completed_by", "created_at", "updated_at", "location", "project_uid", "estimate_minutes", "tags", "logged_minutes", "completer"},
+	fields: func(t *Todo) []any {
+		return []any{&t.UID, &t.Title, &t.Description, &t.Data, &t.Priority, &t.Status, &t.DueDate, &t.RecursOn, &t.MarkedComplete, &t.ExternalURL, &t.UserUID, &t.HouseholdUID, &t.CompletedBy, &t.CreatedAt, &t.UpdatedAt, &t.Location, &t.ProjectUID, &t.EstimateMinutes, &t.Tags, &t.LoggedMinutes, &t.Completer}
+	},
+	exprs: map[string]string{"completer": todoCompleter, "logged_minutes": todoLoggedMinutes},
+}
+
 func scanTodo(s scannable) (Todo, error) {
-	var t Todo
-	err := s.Scan(&t.UID, &t.Title, &t.Description, &t.Data, &t.Priority,
-		&t.DueDate, &t.RecursOn, &t.MarkedComplete, &t.ExternalURL,
-		&t.UserUID, &t.HouseholdUID, &t.CompletedBy, &t.CreatedAt, &t.UpdatedAt)
-	return t, err
+	return todoColumns.scan(s, todoColumns.names)
+}
+
+var projectColumns = columnSet[Project]{
+	names: []string{"uid", "name", "description", "household_uid", "archived", "created_at", "updated_at", "done_todos", "total_todos"},
+	fields: func(p *Project) []any {
+		return []any{&p.UID, &p.Name, &p.Description, &p.HouseholdUID, &p.Archived, &p.CreatedAt, &p.UpdatedAt, &p.DoneTodos, &p.TotalTodos}
+	},
+	exprs: map[string]string{"done_todos": projectDoneTodos, "total_todos": projectTotalTodos},
+}
+
+func scanProject(s scannable) (Project, error) {
+	return projectColumns.scan(s, projectColumns.names)
+}
+
+var todoTemplateColumns = columnSet[TodoTemplate]{
+	names: []string{"uid", "name", "description", "items", "user_uid", "household_uid", "created_at", "updated_at"},
+	fields: func(t *TodoTemplate) []any {
+		return []any{&t.UID, &t.Name, &t.Description, &t.Items, &t.UserUID, &t.HouseholdUID, &t.CreatedAt, &t.UpdatedAt}
+	},
+}
+
+func scanTodoTemplate(s scannable) (TodoTemplate, error) {
+	return todoTemplateColumns.scan(s, todoTemplateColumns.names)
+}
+
+var pantryItemColumns = columnSet[PantryItem]{
+	names: []string{"uid", "household_uid", "item", "quantity", "unit", "category", "expires_on", "created_at", "updated_at"},
+	fields: func(p *PantryItem) []any {
+		return []any{&p.UID, &p.HouseholdUID, &p.Item, &p.Quantity, &p.Unit, &p.Category, &p.ExpiresOn, &p.CreatedAt, &p.UpdatedAt}
+	},
+}
+
+func scanPantryItem(s scannable) (PantryItem, error) {
+	return pantryItemColumns.scan(s, pantryItemColumns.names)
+}
+
+var groceryPurchaseColumns = columnSet[GroceryPurchase]{
+	names: []string{"uid", "household_uid", "item", "quantity", "store", "price_cents", "purchased_on", "created_at", "updated_at"},
+	fields: func(p *GroceryPurchase) []any {
+		return []any{&p.UID, &p.HouseholdUID, &p.Item, &p.Quantity, &p.Store, &p.PriceCents, &p.PurchasedOn, &p.CreatedAt, &p.UpdatedAt}
+	},
+}
+
+func scanGroceryPurchase(s scannable) (GroceryPurchase, error) {
+	return groceryPurchaseColumns.scan(s, groceryPurchaseColumns.names)
+}
+
+var deliveryColumns = columnSet[Delivery]{
+	names: []string{"uid", "channel", "recipient", "payload", "payload_hash", "status", "attempts", "last_error", "created_at", "updated_at"},
+	fields: func(d *Delivery) []any {
+		return []any{&d.UID, &d.Channel, &d.Recipient, &d.Payload, &d.PayloadHash, &d.Status, &d.Attempts, &d.LastError, &d.CreatedAt, &d.UpdatedAt}
+	},
+}
+
+func scanDelivery(s scannable) (Delivery, error) {
+	return deliveryColumns.scan(s, deliveryColumns.names)
+}
+
+var jobRunColumns = columnSet[JobRun]{
+	names: []string{"uid", "name", "payload", "dedupe_key", "status", "attempts", "max_attempts", "run_at", "started_at", "finished_at", "last_error", "created_at", "updated_at"},
+	fields: func(r *JobRun) []any {
+		return []any{&r.UID, &r.Name, &r.Payload, &r.DedupeKey, &r.Status, &r.Attempts, &r.MaxAttempts, &r.RunAt, &r.StartedAt, &r.FinishedAt, &r.LastError, &r.CreatedAt, &r.UpdatedAt}
+	},
+}
+
+func scanJobRun(s scannable) (JobRun, error) {
+	return jobRunColumns.scan(s, jobRunColumns.names)
+}
+
+var backgroundColumns = columnSet[Background]{
+	names: []string{"key", "value", "created_at", "updated_at", "expires_at"},
+	fields: func(b *Background) []any {
+		return []any{&b.Key, &b.Value, &b.CreatedAt, &b.UpdatedAt, &b.ExpiresAt}
+	},
 }
 
 func scanBackground(s scannable) (Background, error) {
-	var b Background
-	err := s.Scan(&b.Key, &b.Value, &b.CreatedAt, &b.UpdatedAt)
-	return b, err
+	return backgroundColumns.scan(s, backgroundColumns.names)
+}
+
+var preferencesColumns = columnSet[Preferences]{
+	names: []string{"key", "specifier", "data", "created_at", "updated_at", "tags"},
+	fields: func(p *Preferences) []any {
+		return []any{&p.Key, &p.Specifier, &p.Data, &p.CreatedAt, &p.UpdatedAt, &p.Tags}
+	},
 }
 
 func scanPreferences(s scannable) (Preferences, error) {
-	var p Preferences
-	err := s.Scan(&p.Key, &p.Specifier, &p.Data, &p.CreatedAt, &p.UpdatedAt, &p.Tags)
-	return p, err
+	return preferencesColumns.scan(s, preferencesColumns.names)
+}
+
+var notesColumns = columnSet[Notes]{
+	names: []string{"id", "key", "data", "created_at", "updated_at", "user_uid", "household_uid", "tags", "visibility", "pinned", "sort_order", "archived_at", "remind_at"},
+	fields: func(n *Notes) []any {
+		return []any{&n.ID, &n.Key, &n.Data, &n.CreatedAt, &n.UpdatedAt, &n.UserUID, &n.HouseholdUID, &n.Tags, &n.Visibility, &n.Pinned, &n.SortOrder, &n.ArchivedAt, &n.RemindAt}
+	},
 }
 
 func scanNotes(s scannable) (Notes, error) {
-	var n Notes
-	err := s.Scan(&n.ID, &n.Key, &n.Data, &n.CreatedAt, &n.UpdatedAt, &n.UserUID, &n.HouseholdUID, &n.Tags)
-	return n, err
+	return notesColumns.scan(s, notesColumns.names)
+}
+
+var credentialsColumns = columnSet[Credentials]{
+	names: []string{"id", "user_uid", "credential_type", "value", "created_at", "updated_at"},
+	fields: func(c *Credentials) []any {
+		return []any{&c.ID, &c.UserUID, &c.CredentialType, &c.Value, &c.CreatedAt, &c.UpdatedAt}
+	},
 }
 
 func scanCredentials(s scannable) (Credentials, error) {
-	var c Credentials
-	err := s.Scan(&c.ID, &c.UserUID, &c.CredentialType, &c.Value, &c.CreatedAt, &c.UpdatedAt)
-	return c, err
+	return credentialsColumns.scan(s, credentialsColumns.names)
 }
 
 func scanSlackUser(s scannable) (SlackUsers, error) {
@@ -583,7 +3566,7 @@ func scanSlackUser(s scannable) (SlackUsers, error) {
 
 func scanUser(s scannable) (Users, error) {
 	var u Users
-	err := s.Scan(&u.UID, &u.Name, &u.Email, &u.Description, &u.CreatedAt, &u.UpdatedAt, &u.HouseholdUID)
+	err := s.Scan(&u.UID, &u.Name, &u.Email, &u.Description, &u.CreatedAt, &u.UpdatedAt, &u.HouseholdUID, &u.Pronouns, &u.Birthday, &u.Phone, &u.AvatarUpdatedAt)
 	return u, err
 }
 
@@ -593,12 +3576,196 @@ func scanHousehold(s scannable) (Households, error) {
 	return h, err
 }
 
+var recipesColumns = columnSet[Recipes]{
+	names: []string{"id", "title", "external_url", "data", "genre", "grocery_list", "prep_time", "cook_time", "total_time", "servings", "difficulty", "rating", "rating_count", "tags", "user_uid", "household_uid", "created_at", "updated_at", "photo_updated_at", "my_rating"},
+	fields: func(r *Recipes) []any {
+		return []any{&r.ID, &r.Title, &r.ExternalURL, &r.Data, &r.Genre, &r.GroceryList, &r.PrepTime, &r.CookTime, &r.TotalTime, &r.Servings, &r.Difficulty, &r.Rating, &r.RatingCount, &r.Tags, &r.UserUID, &r.HouseholdUID, &r.CreatedAt, &r.UpdatedAt, &r.PhotoUpdatedAt, &r.MyRating}
+	},
+	exprs: map[string]string{"my_rating": recipeMyRating},
+}
+
 func scanRecipes(s scannable) (Recipes, error) {
-	var r Recipes
-	err := s.Scan(&r.ID, &r.Title, &r.ExternalURL, &r.Data, &r.Genre, &r.GroceryList, &r.PrepTime, &r.CookTime, &r.TotalTime, &r.Servings, &r.Difficulty, &r.Rating, &r.Tags, &r.UserUID, &r.HouseholdUID, &r.CreatedAt, &r.UpdatedAt)
+	return recipesColumns.scan(s, recipesColumns.names)
+}
+
+func scanAPIKey(s scannable) (APIKeys, error) {
+	var k APIKeys
+	err := s.Scan(&k.UID, &k.UserUID, &k.HouseholdUID, &k.Name, &k.KeyHash, &k.Scopes, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt, &k.UpdatedAt, &k.TenantUID)
+	return k, err
+}
+
+func scanDashboardToken(s scannable) (DashboardToken, error) {
+	var t DashboardToken
+	err := s.Scan(&t.UID, &t.HouseholdUID, &t.Name, &t.TokenHash, &t.Sections, &t.LastUsedAt, &t.RevokedAt, &t.CreatedAt, &t.UpdatedAt, &t.TenantUID)
+	return t, err
+}
+
+func scanImportantDate(s scannable) (ImportantDate, error) {
+	var i ImportantDate
+	err := s.Scan(&i.UID, &i.HouseholdUID, &i.Title, &i.Kind, &i.Date, &i.Recurrence, &i.RemindDaysAhead, &i.Notes, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+func scanJoinRequest(s scannable) (JoinRequest, error) {
+	var r JoinRequest
+	err := s.Scan(&r.UID, &r.HouseholdUID, &r.HouseholdName, &r.UserUID, &r.UserName, &r.Status, &r.Message, &r.DecidedBy, &r.DecidedAt, &r.CreatedAt, &r.UpdatedAt)
 	return r, err
 }
 
+func scanQuota(s scannable) (Quota, error) {
+	var q Quota
+	err := s.Scan(&q.UID, &q.HouseholdUID, &q.MaxTodos, &q.MaxNotes, &q.MaxRecipes, &q.MaxPhotoBytes, &q.CreatedAt, &q.UpdatedAt)
+	return q, err
+}
+
+func scanFeatureFlag(s scannable) (FeatureFlag, error) {
+	var f FeatureFlag
+	err := s.Scan(&f.UID, &f.Name, &f.Description, &f.Enabled, &f.RolloutPercent, &f.HouseholdUIDs, &f.CreatedAt, &f.UpdatedAt)
+	return f, err
+}
+
+func scanEntityLock(s scannable) (EntityLock, error) {
+	var l EntityLock
+	err := s.Scan(&l.Entity, &l.EntityID, &l.APIKeyUID, &l.UserUID, &l.Holder, &l.KeyName, &l.AcquiredAt, &l.ExpiresAt)
+	return l, err
+}
+
+func scanSyncState(s scannable) (SyncState, error) {
+	var st SyncState
+	err := s.Scan(&st.Entity, &st.EntityID, &st.HouseholdUID, &st.UserUID, &st.Seq, &st.Version, &st.Deleted, &st.UpdatedAt)
+	return st, err
+}
+
+func scanDevice(s scannable) (Device, error) {
+	var dev Device
+	err := s.Scan(&dev.UID, &dev.UserUID, &dev.Platform, &dev.Token, &dev.Name, &dev.CreatedAt, &dev.UpdatedAt)
+	return dev, err
+}
+
+func scanAwayPeriod(s scannable) (AwayPeriod, error) {
+	var a AwayPeriod
+	err := s.Scan(&a.UID, &a.UserUID, &a.UserName, &a.StartsOn, &a.EndsOn, &a.Note, &a.CreatedAt, &a.UpdatedAt)
+	return a, err
+}
+
+func scanRetentionPolicy(s scannable) (RetentionPolicy, error) {
+	var p RetentionPolicy
+	err := s.Scan(&p.UID, &p.Entity, &p.HouseholdUID, &p.MaxAgeDays, &p.Action, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+func scanDataSchema(s scannable) (DataSchema, error) {
+	var out DataSchema
+	var schema []byte
+	err := s.Scan(&out.Entity, &out.Key, &schema, &out.CreatedAt, &out.UpdatedAt)
+	out.Schema = schema
+	return out, err
+}
+
+func scanTenant(s scannable) (Tenant, error) {
+	var t Tenant
+	err := s.Scan(&t.UID, &t.Name, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+var toolPolicyColumns = columnSet[ToolPolicy]{
+	names: []string{"uid", "user_uid", "household_uid", "allowed_tools", "disallowed_tools", "created_at", "updated_at"},
+	fields: func(p *ToolPolicy) []any {
+		return []any{&p.UID, &p.UserUID, &p.HouseholdUID, &p.AllowedTools, &p.DisallowedTools, &p.CreatedAt, &p.UpdatedAt}
+	},
+}
+
+func scanToolPolicy(s scannable) (ToolPolicy, error) {
+	return toolPolicyColumns.scan(s, toolPolicyColumns.names)
+}
+
+// columnSet is a table's selectable columns, in SELECT order, and the struct
+// fields they scan into. names doubles as the whitelist for
+// ListOptions.Fields, so only known column names ever reach SQL.
+type columnSet[T any] struct {
+	names  []string
+	fields func(*T) []any
+	// exprs computes the names that aren't columns of the table.
+	exprs map[string]string
+}
+
+// sql writes the select list for columns.
+func (c columnSet[T]) sql(columns []string) string {
+	out := make([]string, len(columns))
+	for i, name := range columns {
+		out[i] = name
+		if expr, ok := c.exprs[name]; ok {
+			out[i] = expr + " AS " + name
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// selected returns the columns to select for fields, in table order.
+func (c columnSet[T]) selected(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return c.names, nil
+	}
+	for _, f := range fields {
+		if !slices.Contains(c.names, f) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, f)
+		}
+	}
+	var out []string
+	for _, name := range c.names {
+		if slices.Contains(fields, name) {
+			out = append(out, name)
+		}
+	}
+	return out, nil
+}
+
+// scan reads a row holding columns, a subset of c.names in the same order.
+func (c columnSet[T]) scan(s scannable, columns []string) (T, error) {
+	var v T
+	all := c.fields(&v)
+	dest := make([]any, 0, len(columns))
+	for i, name := range c.names {
+		if slices.Contains(columns, name) {
+			dest = append(dest, all[i])
+		}
+	}
+	err := s.Scan(dest...)
+	return v, err
+}
+
+// list runs a list query against table, appending the rows to out.
+func (c columnSet[T]) list(ctx context.Context, q queryer, table string, options ListOptions, out []T) ([]T, error) {
+	columns, err := c.selected(options.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(c.names, options.SortBy) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, options.SortBy)
+	}
+	if options.SortDir != "ASC" && options.SortDir != "DESC" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, options.SortDir)
+	}
+	query := buildListQuery(table, c.sql(columns), options)
+	args := append(options.WhereArgs, options.Limit, options.Offset)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		v, err := c.scan(rows, columns)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// buildListQuery writes a list query. Only the WHERE clause's arguments,
+// the limit and the offset are parameters, so callers must check the table,
+// columns and sort against their whitelists first.
 func buildListQuery(tableName string, columns string, options ListOptions) string {
 	query := fmt.Sprintf("SELECT %s FROM %s", columns, tableName)
 
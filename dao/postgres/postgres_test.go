@@ -1,13 +1,24 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"expvar"
+	"fmt"
+	"html"
+	"log/slog"
+	"net"
+	"reflect"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Mock queryer for testing
@@ -15,6 +26,7 @@ type mockQueryer struct {
 	queryFunc    func(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	queryRowFunc func(ctx context.Context, sql string, args ...any) pgx.Row
 	execFunc     func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	beginFunc    func(ctx context.Context) (pgx.Tx, error)
 }
 
 func (m *mockQueryer) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
@@ -38,6 +50,13 @@ func (m *mockQueryer) Exec(ctx context.Context, sql string, args ...any) (pgconn
 	return pgconn.CommandTag{}, errors.New("exec not implemented")
 }
 
+func (m *mockQueryer) Begin(ctx context.Context) (pgx.Tx, error) {
+	if m.beginFunc != nil {
+		return m.beginFunc(ctx)
+	}
+	return nil, errors.New("begin not implemented")
+}
+
 // Mock row for testing
 type mockRow struct {
 	scanFunc func(dest ...any) error
@@ -51,6 +70,23 @@ func (m *mockRow) Scan(dest ...any) error {
 	return m.err
 }
 
+func strPtr(s string) *string { return &s }
+
+// rowOf scans v into a full row of c's columns, so tests don't depend on
+// the column order.
+func rowOf[T any](c columnSet[T], v T) func(dest ...any) error {
+	return func(dest ...any) error {
+		src := c.fields(&v)
+		if len(dest) != len(src) {
+			return fmt.Errorf("scanned %d columns, want %d", len(dest), len(src))
+		}
+		for i := range dest {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(src[i]).Elem())
+		}
+		return nil
+	}
+}
+
 // Simplified mock for basic testing - avoid complex pgx.Rows interface
 
 func TestNew(t *testing.T) {
@@ -63,7 +99,7 @@ func TestNew(t *testing.T) {
 	if dao == nil {
 		t.Error("Expected DAO instance, got nil")
 	}
-	if dao.pool != mockPool {
+	if dao.pool != (scoped{mockPool}) {
 		t.Error("Expected DAO to use provided pool")
 	}
 }
@@ -74,26 +110,7 @@ func TestCreateTodo(t *testing.T) {
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 			if sql == insertTodo {
 				return &mockRow{
-					scanFunc: func(dest ...any) error {
-						// Simulate scanning a complete Todo
-						if len(dest) >= 14 {
-							*dest[0].(*string) = "test-uid"         // UID
-							*dest[1].(*string) = "Test Title"       // Title  
-							*dest[2].(*string) = "Test Description" // Description
-							*dest[3].(*string) = "{}"               // Data
-							*dest[4].(*Priority) = PriorityHigh     // Priority
-							// dest[5] is DueDate (*time.Time) - leave nil
-							*dest[6].(*string) = ""                 // RecursOn
-							// dest[7] is MarkedComplete (*time.Time) - leave nil
-							*dest[8].(*string) = ""                 // ExternalURL
-							*dest[9].(*string) = "user-123"        // UserUID
-							*dest[10].(*string) = "household-456"  // HouseholdUID
-							*dest[11].(*string) = ""               // CompletedBy
-							*dest[12].(*time.Time) = now           // CreatedAt
-							*dest[13].(*time.Time) = now           // UpdatedAt
-						}
-						return nil
-					},
+					scanFunc: rowOf(todoColumns, Todo{UID: "test-uid", Title: "Test Title", Description: "Test Description", Data: "{}", Priority: PriorityHigh, UserUID: strPtr("user-123"), HouseholdUID: strPtr("household-456"), CreatedAt: now, UpdatedAt: now}),
 				}
 			}
 			return &mockRow{err: errors.New("unexpected query")}
@@ -159,7 +176,7 @@ func TestListTodosQueryBuilding(t *testing.T) {
 		SortDir: "DESC",
 	}
 	
-	query := buildListQuery("todos", options)
+	query := buildListQuery("todos", "*", options)
 	expectedQuery := "SELECT * FROM todos ORDER BY created_at DESC LIMIT $1 OFFSET $2"
 	
 	if query != expectedQuery {
@@ -212,15 +229,7 @@ func TestCreatePreferences(t *testing.T) {
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 			if sql == insertPreferences {
 				return &mockRow{
-					scanFunc: func(dest ...any) error {
-						*dest[0].(*string) = "test-key"
-						*dest[1].(*string) = "test-specifier"
-						*dest[2].(*string) = "{\"theme\": \"dark\"}"
-						*dest[3].(*[]string) = []string{"theme", "ui"}
-						*dest[4].(*time.Time) = now
-						*dest[5].(*time.Time) = now
-						return nil
-					},
+					scanFunc: rowOf(preferencesColumns, Preferences{Key: "test-key", Specifier: "test-specifier", Data: "{\"theme\": \"dark\"}", Tags: []string{"theme", "ui"}, CreatedAt: now, UpdatedAt: now}),
 				}
 			}
 			return &mockRow{err: errors.New("unexpected query")}
@@ -295,7 +304,7 @@ func TestBuildListQuery(t *testing.T) {
 	
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result := buildListQuery(test.tableName, test.options)
+			result := buildListQuery(test.tableName, "*", test.options)
 			if result != test.expectedSQL {
 				t.Errorf("Expected SQL: %s\nGot: %s", test.expectedSQL, result)
 			}
@@ -384,7 +393,7 @@ func TestBuildListQueryEdgeCases(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result := buildListQuery(test.tableName, test.options)
+			result := buildListQuery(test.tableName, "*", test.options)
 			if result != test.expectedSQL {
 				t.Errorf("Expected SQL: %s\nGot: %s", test.expectedSQL, result)
 			}
@@ -453,17 +462,7 @@ func TestCreateNotes(t *testing.T) {
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 			if sql == insertNotes {
 				return &mockRow{
-					scanFunc: func(dest ...any) error {
-						*dest[0].(*string) = "test-id"                      // ID
-						*dest[1].(*string) = "Test Note"                    // Key
-						*dest[2].(*string) = "user123"                      // UserUID
-						*dest[3].(*string) = "household456"                 // HouseholdUID
-						*dest[4].(*string) = "This is the content of the note" // Data
-						*dest[5].(*[]string) = []string{"tag1", "tag2"}    // Tags
-						*dest[6].(*time.Time) = now                         // CreatedAt
-						*dest[7].(*time.Time) = now                         // UpdatedAt
-						return nil
-					},
+					scanFunc: rowOf(notesColumns, Notes{ID: "test-id", Key: "Test Note", UserUID: strPtr("user123"), HouseholdUID: strPtr("household456"), Data: "This is the content of the note", Tags: []string{"tag1", "tag2"}, CreatedAt: now, UpdatedAt: now}),
 				}
 			}
 			return &mockRow{err: errors.New("unexpected query")}
@@ -475,8 +474,8 @@ func TestCreateNotes(t *testing.T) {
 	note := Notes{
 		ID:          "test-id",
 		Key:         "Test Note",
-		UserUID:      "user123",
-		HouseholdUID: "household456",
+		UserUID:      strPtr("user123"),
+		HouseholdUID: strPtr("household456"),
 		Data:        "This is the content of the note",
 		Tags:        []string{"tag1", "tag2"},
 	}
@@ -492,11 +491,11 @@ func TestCreateNotes(t *testing.T) {
 	if result.Key != "Test Note" {
 		t.Errorf("Expected key 'Test Note', got '%s'", result.Key)
 	}
-	if result.UserUID != "user123" {
-		t.Errorf("Expected user_uid 'user123', got '%s'", result.UserUID)
+	if result.UserUID == nil || *result.UserUID != "user123" {
+		t.Errorf("Expected user_uid 'user123', got %v", result.UserUID)
 	}
-	if result.HouseholdUID != "household456" {
-		t.Errorf("Expected household_uid 'household456', got '%s'", result.HouseholdUID)
+	if result.HouseholdUID == nil || *result.HouseholdUID != "household456" {
+		t.Errorf("Expected household_uid 'household456', got %v", result.HouseholdUID)
 	}
 }
 
@@ -506,17 +505,7 @@ func TestGetNotes(t *testing.T) {
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 			if sql == getNotes && len(args) == 1 && args[0] == "test-id" {
 				return &mockRow{
-					scanFunc: func(dest ...any) error {
-						*dest[0].(*string) = "test-id"           // ID
-						*dest[1].(*string) = "Test Note"         // Key
-						*dest[2].(*string) = "user123"           // UserUID
-						*dest[3].(*string) = "household456"      // HouseholdUID
-						*dest[4].(*string) = "This is the content" // Data
-						*dest[5].(*[]string) = []string{"tag1"}   // Tags
-						*dest[6].(*time.Time) = now              // CreatedAt
-						*dest[7].(*time.Time) = now              // UpdatedAt
-						return nil
-					},
+					scanFunc: rowOf(notesColumns, Notes{ID: "test-id", Key: "Test Note", UserUID: strPtr("user123"), HouseholdUID: strPtr("household456"), Data: "This is the content", Tags: []string{"tag1", "tag2"}, CreatedAt: now, UpdatedAt: now}),
 				}
 			}
 			return &mockRow{err: errors.New("note not found")}
@@ -633,8 +622,8 @@ func TestCreateNotesError(t *testing.T) {
 	note := Notes{
 		ID:          "test-id",
 		Key:         "Test Note",
-		UserUID:      "user123",
-		HouseholdUID: "household456",
+		UserUID:      strPtr("user123"),
+		HouseholdUID: strPtr("household456"),
 		Data:        "This is the content of the note",
 		Tags:        []string{"tag1", "tag2"},
 	}
@@ -686,23 +675,7 @@ func TestUpdateBackgroundError(t *testing.T) {
 func TestScanTodo(t *testing.T) {
 	now := time.Now()
 	mockRow := &mockRow{
-		scanFunc: func(dest ...any) error {
-			*dest[0].(*string) = "test-uid"         // UID
-			*dest[1].(*string) = "Test Title"       // Title  
-			*dest[2].(*string) = "Test Description" // Description
-			*dest[3].(*string) = "{}"               // Data
-			*dest[4].(*Priority) = PriorityHigh     // Priority
-			// dest[5] is DueDate (*time.Time) - leave nil
-			*dest[6].(*string) = ""                 // RecursOn
-			// dest[7] is MarkedComplete (*time.Time) - leave nil
-			*dest[8].(*string) = ""                 // ExternalURL
-			*dest[9].(*string) = "user-123"        // UserUID
-			*dest[10].(*string) = "household-456"  // HouseholdUID
-			*dest[11].(*string) = ""               // CompletedBy
-			*dest[12].(*time.Time) = now           // CreatedAt
-			*dest[13].(*time.Time) = now           // UpdatedAt
-			return nil
-		},
+		scanFunc: rowOf(todoColumns, Todo{UID: "test-uid", Title: "Test Title", Description: "Test Description", Data: "{}", Priority: PriorityHigh, UserUID: strPtr("user-123"), HouseholdUID: strPtr("household-456"), CreatedAt: now, UpdatedAt: now}),
 	}
 
 	todo, err := scanTodo(mockRow)
@@ -773,15 +746,7 @@ func TestScanBackgroundError(t *testing.T) {
 func TestScanPreferences(t *testing.T) {
 	now := time.Now()
 	mockRow := &mockRow{
-		scanFunc: func(dest ...any) error {
-			*dest[0].(*string) = "test-key"
-			*dest[1].(*string) = "test-specifier"
-			*dest[2].(*string) = "{\"theme\": \"dark\"}"
-			*dest[3].(*[]string) = []string{"theme", "ui"}
-			*dest[4].(*time.Time) = now
-			*dest[5].(*time.Time) = now
-			return nil
-		},
+		scanFunc: rowOf(preferencesColumns, Preferences{Key: "test-key", Specifier: "test-specifier", Data: "{\"theme\": \"dark\"}", Tags: []string{"theme", "ui"}, CreatedAt: now, UpdatedAt: now}),
 	}
 
 	pref, err := scanPreferences(mockRow)
@@ -812,17 +777,7 @@ func TestScanPreferencesError(t *testing.T) {
 func TestScanNotes(t *testing.T) {
 	now := time.Now()
 	mockRow := &mockRow{
-		scanFunc: func(dest ...any) error {
-			*dest[0].(*string) = "test-id"
-			*dest[1].(*string) = "Test Note"
-			*dest[2].(*string) = "user123"
-			*dest[3].(*string) = "household456"
-			*dest[4].(*string) = "This is the content"
-			*dest[5].(*[]string) = []string{"tag1", "tag2"}
-			*dest[6].(*time.Time) = now
-			*dest[7].(*time.Time) = now
-			return nil
-		},
+		scanFunc: rowOf(notesColumns, Notes{ID: "test-id", Key: "Test Note", UserUID: strPtr("user123"), HouseholdUID: strPtr("household456"), Data: "This is the content", Tags: []string{"tag1", "tag2"}, CreatedAt: now, UpdatedAt: now}),
 	}
 
 	note, err := scanNotes(mockRow)
@@ -832,8 +787,8 @@ func TestScanNotes(t *testing.T) {
 	if note.ID != "test-id" {
 		t.Errorf("Expected ID 'test-id', got '%s'", note.ID)
 	}
-	if note.UserUID != "user123" {
-		t.Errorf("Expected UserUID 'user123', got '%s'", note.UserUID)
+	if note.UserUID == nil || *note.UserUID != "user123" {
+		t.Errorf("Expected UserUID 'user123', got %v", note.UserUID)
 	}
 	if len(note.Tags) != 2 {
 		t.Errorf("Expected 2 tags, got %d", len(note.Tags))
@@ -851,4 +806,838 @@ func TestScanNotesError(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
-}
\ No newline at end of file
+}
+// mockTx records how a scoped call used its transaction.
+type mockTx struct {
+	pgx.Tx
+	sql        []string
+	committed  bool
+	rolledBack bool
+	row        pgx.Row
+}
+
+func (m *mockTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	m.sql = append(m.sql, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (m *mockTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	m.sql = append(m.sql, sql)
+	return m.row
+}
+
+func (m *mockTx) Commit(ctx context.Context) error {
+	m.committed = true
+	return nil
+}
+
+func (m *mockTx) Rollback(ctx context.Context) error {
+	m.rolledBack = true
+	return nil
+}
+
+func TestScopedCallsRunInTransaction(t *testing.T) {
+	tx := &mockTx{row: &mockRow{}}
+	mockPool := &mockQueryer{
+		beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			t.Error("Expected scoped exec to run in the transaction")
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+	ctx := WithScope(context.Background(), "user123", "household456")
+
+	if _, err := dao.pool.Exec(ctx, deleteNotes, "note-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tx.sql) != 2 || tx.sql[0] != setScope || tx.sql[1] != deleteNotes {
+		t.Errorf("Expected the scope to be set before the statement, got %v", tx.sql)
+	}
+	if !tx.committed {
+		t.Error("Expected the transaction to be committed")
+	}
+
+	tx = &mockTx{row: &mockRow{err: pgx.ErrNoRows}}
+	if err := dao.pool.QueryRow(ctx, getNotes, "note-1").Scan(); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected ErrNoRows, got %v", err)
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Error("Expected a failed scan to roll the transaction back")
+	}
+}
+
+func TestUnscopedCallsSkipTransaction(t *testing.T) {
+	executed := false
+	mockPool := &mockQueryer{
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			executed = true
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	if _, err := dao.pool.Exec(WithScope(context.Background(), "", "household456"), deleteNotes, "note-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !executed {
+		t.Error("Expected a call without a user to go straight to the pool")
+	}
+}
+
+func TestListRejectsInvalidSort(t *testing.T) {
+	mockPool := &mockQueryer{
+		queryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			t.Errorf("Expected no query, got %s", sql)
+			return nil, errors.New("unexpected query")
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	for _, options := range []ListOptions{
+		{SortBy: "created_at; DROP TABLE todos", SortDir: "DESC"},
+		{SortBy: "(SELECT 1)", SortDir: "ASC"},
+		{SortBy: "created_at", SortDir: "DESC, uid"},
+		{SortBy: "created_at"},
+	} {
+		if _, err := dao.ListTodos(context.Background(), options); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("Expected ErrInvalidSort for %+v, got %v", options, err)
+		}
+	}
+}
+
+func TestResilientRetriesTransientErrors(t *testing.T) {
+	calls := 0
+	mockPool := &mockQueryer{
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			calls++
+			if calls < 3 {
+				return pgconn.CommandTag{}, &pgconn.PgError{Code: "40001"}
+			}
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	dao, _ := New(context.Background(), mockPool, WithResilience(Resilience{MaxRetries: 3, RetryDelay: time.Millisecond}))
+	before := counter(retries.Get("40001"))
+
+	if _, err := dao.pool.Exec(context.Background(), deleteNotes, "note-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+	if got := counter(retries.Get("40001")) - before; got != 2 {
+		t.Errorf("Expected 2 retries to be counted, got %d", got)
+	}
+
+	calls = 0
+	mockPool.execFunc = func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+		calls++
+		return pgconn.CommandTag{}, &pgconn.PgError{Code: "40P01"}
+	}
+	exhausted := retriesExhausted.Value()
+	if _, err := dao.pool.Exec(context.Background(), deleteNotes, "note-1"); err == nil {
+		t.Error("Expected the error once retries ran out")
+	}
+	if calls != 4 || retriesExhausted.Value() != exhausted+1 {
+		t.Errorf("Expected 4 attempts and an exhausted retry, got %d", calls)
+	}
+}
+
+func TestResilientRetriesOnlyReadsAfterConnectionReset(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	calls := 0
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			calls++
+			if calls == 1 {
+				return &mockRow{err: reset}
+			}
+			return &mockRow{}
+		},
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			calls++
+			return pgconn.CommandTag{}, reset
+		},
+	}
+	dao, _ := New(context.Background(), mockPool, WithResilience(Resilience{MaxRetries: 3}))
+
+	if err := dao.pool.QueryRow(context.Background(), getNotes, "note-1").Scan(); err != nil {
+		t.Fatalf("Expected the read to be retried, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts at the read, got %d", calls)
+	}
+
+	calls = 0
+	if _, err := dao.pool.Exec(context.Background(), deleteNotes, "note-1"); !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Expected the reset, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a write to be tried once, got %d", calls)
+	}
+}
+
+func TestResilientTimesOutAttempts(t *testing.T) {
+	calls := 0
+	mockPool := &mockQueryer{
+		queryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			calls++
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Expected the read to have a deadline")
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	dao, _ := New(context.Background(), mockPool, WithResilience(Resilience{ReadTimeout: time.Millisecond, MaxRetries: 3}))
+	before := timeouts.Value()
+
+	if _, err := dao.pool.Query(context.Background(), getNotes, "note-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if calls != 1 || timeouts.Value() != before+1 {
+		t.Errorf("Expected one counted, unretried timeout, got %d attempts", calls)
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	tx := &mockTx{row: &mockRow{}}
+	mockPool := &mockQueryer{
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			time.Sleep(5 * time.Millisecond)
+			return pgconn.CommandTag{}, nil
+		},
+		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row { return &mockRow{} },
+		beginFunc:    func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+	}
+	dao, _ := New(context.Background(), mockPool, WithSlowQueryLog(time.Millisecond))
+	before := slowQueries.Value()
+
+	status := TodoDone
+	if _, err := dao.pool.Exec(context.Background(), "UPDATE todos\n\tSET title = 'secret plans'\n\tWHERE uid = $1 AND status = $2 AND tags && $3",
+		"0d9c6f1e-5b1a-4c3e-9f7a-2b8d4e6f1a3c", &status, []string{"a", "b"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if slowQueries.Value() != before+1 {
+		t.Errorf("Expected the slow statement to be counted")
+	}
+	line := logs.String()
+	for _, want := range []string{
+		`msg="Slow query"`,
+		`sql="UPDATE todos SET title = '?' WHERE uid = $1 AND status = $2 AND tags && $3"`,
+		`args="[string(36) *postgres.TodoStatus(4) []string(2)]"`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected the log to contain %s, got %s", want, line)
+		}
+	}
+	if strings.Contains(line, "secret") || strings.Contains(line, "0d9c6f1e") {
+		t.Errorf("Expected no values in the log, got %s", line)
+	}
+
+	logs.Reset()
+	if err := dao.pool.QueryRow(context.Background(), getNotes, "note-1").Scan(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected a fast query not to be logged, got %s", logs.String())
+	}
+
+	// Statements on a transaction are timed too.
+	begun, err := dao.pool.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tx.row = slowScan{}
+	if err := begun.QueryRow(context.Background(), getNotes, nil).Scan(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), `args=[nil]`) || len(tx.sql) != 1 {
+		t.Errorf("Expected the transaction's query to be logged, got %s", logs.String())
+	}
+}
+
+type slowScan struct{}
+
+func (slowScan) Scan(dest ...any) error {
+	time.Sleep(5 * time.Millisecond)
+	return nil
+}
+
+func TestConfigureStatementTimeout(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgres://localhost/assistant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ConfigureStatementTimeout(config, 0)
+	if _, ok := config.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Error("Expected no statement_timeout for zero")
+	}
+	ConfigureStatementTimeout(config, 30*time.Second)
+	if got := config.ConnConfig.RuntimeParams["statement_timeout"]; got != "30000" {
+		t.Errorf("Expected statement_timeout 30000, got %q", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	mockPool := &mockQueryer{
+		queryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			gotSQL, gotArgs = sql, args
+			return nil, errors.New("stop")
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	whereArgs := []any{"user-1"}
+	_, _ = dao.Search(context.Background(), "notes", "taco -fish", ListOptions{Limit: 5, WhereClause: "WHERE user_uid = $1", WhereArgs: whereArgs})
+	for _, want := range []string{
+		"FROM notes WHERE search_vector @@ websearch_to_tsquery('english', $2) AND (user_uid = $1)",
+		"ts_headline('english', body, websearch_to_tsquery('english', $2), $3)",
+		"id::text AS id, key AS title, data AS body",
+		"LIMIT $4",
+	} {
+		if !strings.Contains(gotSQL, want) {
+			t.Errorf("Expected the query to contain %q, got %s", want, gotSQL)
+		}
+	}
+	if len(gotArgs) != 4 || gotArgs[0] != "user-1" || gotArgs[1] != "taco -fish" || gotArgs[2] != headlineOptions || gotArgs[3] != 5 {
+		t.Errorf("Unexpected args %v", gotArgs)
+	}
+	if len(whereArgs) != 1 {
+		t.Errorf("Expected the caller's args to be left alone, got %v", whereArgs)
+	}
+
+	_, _ = dao.Search(context.Background(), "todos", "plumber", ListOptions{Limit: 10})
+	if !strings.Contains(gotSQL, "FROM todos WHERE search_vector @@ websearch_to_tsquery('english', $1)\n") {
+		t.Errorf("Expected an unconditioned todos search, got %s", gotSQL)
+	}
+
+	if _, err := dao.Search(context.Background(), "users", "alex", ListOptions{}); !errors.Is(err, ErrInvalidSearchTarget) {
+		t.Errorf("Expected ErrInvalidSearchTarget, got %v", err)
+	}
+}
+
+func TestSearchSnippetsAreEscaped(t *testing.T) {
+	got := snippetMarks.Replace(html.EscapeString("Use <b>fresh</b> " + markStart + "tacos" + markStop + " & lime"))
+	if want := "Use &lt;b&gt;fresh&lt;/b&gt; <mark>tacos</mark> &amp; lime"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestSetNoteReminder(t *testing.T) {
+	remindAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	var got []any
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			if sql != remindNotes {
+				return &mockRow{err: errors.New("unexpected query")}
+			}
+			got = args
+			return &mockRow{scanFunc: func(dest ...any) error {
+				if len(dest) != len(notesColumns.names) {
+					return errors.New("wrong number of columns scanned")
+				}
+				*dest[0].(*string) = "note-1"
+				*dest[len(dest)-1].(**time.Time) = &remindAt
+				return nil
+			}}
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	note, err := dao.SetNoteReminder(context.Background(), "note-1", &remindAt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if note.RemindAt == nil || !note.RemindAt.Equal(remindAt) {
+		t.Errorf("Expected remind_at %v, got %v", remindAt, note.RemindAt)
+	}
+	if len(got) != 2 || got[0] != "note-1" || got[1] != &remindAt {
+		t.Errorf("Expected the note ID and time as arguments, got %v", got)
+	}
+}
+
+func counter(v expvar.Var) int64 {
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
+
+func TestSetPreferencesBulkIsAllOrNothing(t *testing.T) {
+	tx := &mockTx{row: &mockRow{err: errors.New("value too long")}}
+	mockPool := &mockQueryer{beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil }}
+	dao, _ := New(context.Background(), mockPool)
+
+	_, err := dao.SetPreferencesBulk(context.Background(), []Preferences{{Key: "diet", Specifier: "user-1", Data: "vegetarian"}})
+	if err == nil {
+		t.Fatal("Expected the failed upsert to be returned")
+	}
+	if len(tx.sql) != 1 || tx.sql[0] != upsertPreferences {
+		t.Errorf("Expected the upsert to run in the transaction, got %v", tx.sql)
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Error("Expected the transaction to be rolled back")
+	}
+}
+
+func TestPriorityJSON(t *testing.T) {
+	out, err := json.Marshal(TemplateItem{Title: "Vacuum", Priority: PriorityHigh})
+	if err != nil || string(out) != `{"title":"Vacuum","priority":"high"}` {
+		t.Errorf("Expected the label, got %s (%v)", out, err)
+	}
+
+	for in, want := range map[string]Priority{`"low"`: PriorityLow, `"Critical"`: PriorityCritical, `2`: PriorityMedium, `"3"`: PriorityHigh, `null`: 0} {
+		var p Priority
+		if err := json.Unmarshal([]byte(in), &p); err != nil || p != want {
+			t.Errorf("%s: expected %v, got %v (%v)", in, want, p, err)
+		}
+	}
+	for _, in := range []string{`"urgent"`, `0`, `5`, `2.5`, `true`} {
+		var p Priority
+		if err := json.Unmarshal([]byte(in), &p); !errors.Is(err, ErrInvalidPriority) {
+			t.Errorf("%s: expected ErrInvalidPriority, got %v", in, err)
+		}
+	}
+}
+
+func TestTodoWritesRejectInvalidPriority(t *testing.T) {
+	dao, _ := New(context.Background(), &mockQueryer{})
+	five := Priority(5)
+
+	if _, err := dao.CreateTodo(context.Background(), Todo{Title: "Renew passport"}); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("Expected a missing priority to be rejected, got %v", err)
+	}
+	if _, err := dao.CreateTodos(context.Background(), []Todo{{Priority: PriorityLow}, {Priority: five}}); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("Expected the batch to be rejected, got %v", err)
+	}
+	if _, err := dao.UpdateTodo(context.Background(), "todo-1", UpdateTodo{Priority: &five}); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("Expected the update to be rejected, got %v", err)
+	}
+}
+
+func TestTodoCompleter(t *testing.T) {
+	var args []any
+	mockPool := &mockQueryer{queryRowFunc: func(ctx context.Context, sql string, a ...any) pgx.Row {
+		args = a
+		return &mockRow{err: &pgconn.PgError{Code: "23503", ConstraintName: "todos_completed_by_fkey"}}
+	}}
+	dao, _ := New(context.Background(), mockPool)
+	alex := "Alex"
+	if _, err := dao.UpdateTodo(context.Background(), "todo-1", UpdateTodo{CompletedBy: &alex}); !errors.Is(err, ErrUnknownCompleter) {
+		t.Errorf("Expected a name to be rejected, got %v", err)
+	}
+	if args != nil {
+		t.Error("Expected the update not to run")
+	}
+
+	ghost := "5f0c6a8e-2d3b-4c47-9a52-1f7f3c8e9d10"
+	if _, err := dao.UpdateTodo(context.Background(), "todo-1", UpdateTodo{CompletedBy: &ghost}); !errors.Is(err, ErrUnknownCompleter) {
+		t.Errorf("Expected the foreign key violation to be ErrUnknownCompleter, got %v", err)
+	}
+	if markedComplete, _ := args[7].(*time.Time); markedComplete == nil {
+		t.Error("Expected naming a completer to complete the todo")
+	}
+
+	_, _ = dao.CreateTodo(context.Background(), Todo{Priority: PriorityLow, CompletedBy: new(string)})
+	if completedBy, _ := args[10].(*string); completedBy != nil {
+		t.Errorf("Expected an empty completer to be stored as none, got %q", *completedBy)
+	}
+}
+
+func TestRateRecipe(t *testing.T) {
+	tx := &mockTx{row: &mockRow{}}
+	mockPool := &mockQueryer{beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil }}
+	dao, _ := New(context.Background(), mockPool)
+
+	if _, err := dao.RateRecipe(context.Background(), "recipe-1", "user-1", 6); !errors.Is(err, ErrInvalidRating) {
+		t.Errorf("Expected ErrInvalidRating, got %v", err)
+	}
+	if len(tx.sql) != 0 {
+		t.Error("Expected an invalid rating not to be written")
+	}
+
+	// Nothing was inserted, so the recipe isn't there.
+	if _, err := dao.RateRecipe(context.Background(), "missing", "user-1", 4); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows, got %v", err)
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Error("Expected the transaction to be rolled back")
+	}
+}
+
+func TestAddToMyDay(t *testing.T) {
+	tx := &mockTx{row: &mockRow{}}
+	mockPool := &mockQueryer{beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil }}
+	dao, _ := New(context.Background(), mockPool)
+
+	// Nothing was inserted, so the todo isn't there.
+	if err := dao.AddToMyDay(context.Background(), "user-1", "missing", time.Now().Add(time.Hour)); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows, got %v", err)
+	}
+	if len(tx.sql) != 2 || tx.sql[0] != clearMyDay || tx.sql[1] != addToMyDay {
+		t.Errorf("Expected lapsed entries to be cleared before adding, got %v", tx.sql)
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Error("Expected the transaction to be rolled back")
+	}
+}
+
+func TestTodoStatus(t *testing.T) {
+	when := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+
+	if status, completed, err := todoStatus("", nil); status != TodoBacklog || completed != nil || err != nil {
+		t.Errorf("Expected a new todo in the backlog, got %q %v %v", status, completed, err)
+	}
+	if status, completed, _ := todoStatus("", &when); status != TodoDone || completed != &when {
+		t.Errorf("Expected a completed todo to be done, got %q %v", status, completed)
+	}
+	if _, completed, _ := todoStatus(TodoDone, nil); completed == nil {
+		t.Error("Expected a done todo to be completed")
+	}
+	if _, _, err := todoStatus(TodoPlanned, &when); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected a completed todo that isn't done to be ErrInvalidStatus, got %v", err)
+	}
+	if _, _, err := todoStatus("someday", nil); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestParseTodoStatus(t *testing.T) {
+	for in, want := range map[string]TodoStatus{"backlog": TodoBacklog, "In progress": TodoInProgress, "in-progress": TodoInProgress, " DONE ": TodoDone} {
+		if got, err := ParseTodoStatus(in); got != want || err != nil {
+			t.Errorf("ParseTodoStatus(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseTodoStatus("someday"); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestSetTodoStatus(t *testing.T) {
+	var sql string
+	var args []any
+	mockPool := &mockQueryer{queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+		sql, args = q, a
+		return &mockRow{err: pgx.ErrNoRows}
+	}}
+	dao, _ := New(context.Background(), mockPool)
+
+	if _, err := dao.SetTodoStatus(context.Background(), "todo-1", "someday", nil); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+	if sql != "" {
+		t.Error("Expected an invalid status not to be written")
+	}
+
+	by := "not-a-user"
+	if _, err := dao.SetTodoStatus(context.Background(), "todo-1", TodoDone, &by); !errors.Is(err, ErrUnknownCompleter) {
+		t.Errorf("Expected ErrUnknownCompleter, got %v", err)
+	}
+
+	if _, err := dao.SetTodoStatus(context.Background(), "missing", TodoInProgress, nil); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows, got %v", err)
+	}
+	if sql != setTodoStatus || args[1] != TodoInProgress {
+		t.Errorf("Expected setTodoStatus with the new status, got %q %v", sql, args)
+	}
+}
+
+func TestLogTodoTime(t *testing.T) {
+	var sql string
+	mockPool := &mockQueryer{queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+		sql = q
+		return &mockRow{err: &pgconn.PgError{Code: "23503", ConstraintName: "todo_time_logs_user_uid_fkey"}}
+	}}
+	dao, _ := New(context.Background(), mockPool)
+	userUID := "11111111-1111-1111-1111-111111111111"
+
+	if _, err := dao.LogTodoTime(context.Background(), TodoTimeLog{TodoUID: "todo-1", UserUID: userUID}); !errors.Is(err, ErrInvalidMinutes) {
+		t.Errorf("Expected ErrInvalidMinutes, got %v", err)
+	}
+	if _, err := dao.LogTodoTime(context.Background(), TodoTimeLog{TodoUID: "todo-1", UserUID: "someone", Minutes: 30}); !errors.Is(err, ErrUnknownTimeUser) {
+		t.Errorf("Expected ErrUnknownTimeUser, got %v", err)
+	}
+	if sql != "" {
+		t.Error("Expected invalid time not to be written")
+	}
+	if _, err := dao.LogTodoTime(context.Background(), TodoTimeLog{TodoUID: "todo-1", UserUID: userUID, Minutes: 30}); !errors.Is(err, ErrUnknownTimeUser) {
+		t.Errorf("Expected a missing user to be ErrUnknownTimeUser, got %v", err)
+	}
+	if sql != insertTodoTimeLog {
+		t.Errorf("Expected insertTodoTimeLog, got %q", sql)
+	}
+}
+
+func TestCreateTodoInvalidEstimate(t *testing.T) {
+	dao, _ := New(context.Background(), &mockQueryer{})
+	estimate := -5
+	if _, err := dao.CreateTodo(context.Background(), Todo{Title: "Mow", Priority: PriorityMedium, EstimateMinutes: &estimate}); !errors.Is(err, ErrInvalidEstimate) {
+		t.Errorf("Expected ErrInvalidEstimate, got %v", err)
+	}
+	if _, err := dao.UpdateTodo(context.Background(), "todo-1", UpdateTodo{EstimateMinutes: &estimate}); !errors.Is(err, ErrInvalidEstimate) {
+		t.Errorf("Expected ErrInvalidEstimate, got %v", err)
+	}
+}
+
+func TestWeeklyTodoTime(t *testing.T) {
+	var sql string
+	var args []any
+	mockPool := &mockQueryer{queryFunc: func(ctx context.Context, q string, a ...any) (pgx.Rows, error) {
+		sql, args = q, a
+		return nil, errors.New("boom")
+	}}
+	dao, _ := New(context.Background(), mockPool)
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := dao.WeeklyTodoTime(context.Background(), "house-1", "", from, to, "yard"); err == nil {
+		t.Error("Expected the query error")
+	}
+	if sql != todoWeeklyTime || len(args) != 5 || args[0] != "house-1" || args[2] != from || args[3] != to || args[4] != "yard" {
+		t.Errorf("Expected todoWeeklyTime with the household, range and title, got %q %v", sql, args)
+	}
+}
+
+func TestMoveTodosToProject(t *testing.T) {
+	var queried bool
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+			if a[0] == "missing" {
+				return &mockRow{err: pgx.ErrNoRows}
+			}
+			return &mockRow{scanFunc: func(dest ...any) error {
+				*dest[1].(*string) = "Old garage"
+				*dest[4].(*bool) = true
+				return nil
+			}}
+		},
+		queryFunc: func(ctx context.Context, q string, a ...any) (pgx.Rows, error) {
+			queried = true
+			return nil, errors.New("unexpected query")
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	if _, err := dao.MoveTodosToProject(context.Background(), "missing", []string{"todo-1"}); !errors.Is(err, ErrUnknownProject) {
+		t.Errorf("Expected ErrUnknownProject, got %v", err)
+	}
+	if _, err := dao.MoveTodosToProject(context.Background(), "archived", []string{"todo-1"}); !errors.Is(err, ErrProjectArchived) {
+		t.Errorf("Expected ErrProjectArchived, got %v", err)
+	}
+	if queried {
+		t.Error("Expected no todos to be moved")
+	}
+}
+
+func TestTodoRefErr(t *testing.T) {
+	if err := todoRefErr(&pgconn.PgError{ConstraintName: "todos_project_uid_fkey"}); !errors.Is(err, ErrUnknownProject) {
+		t.Errorf("Expected ErrUnknownProject, got %v", err)
+	}
+	if err := todoRefErr(&pgconn.PgError{ConstraintName: "todos_completed_by_fkey"}); !errors.Is(err, ErrUnknownCompleter) {
+		t.Errorf("Expected ErrUnknownCompleter, got %v", err)
+	}
+}
+
+func TestQuotaErr(t *testing.T) {
+	err := quotaErr(&pgconn.PgError{Code: "53400", Message: "this household has reached its limit of 5 todos"})
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "limit of 5 todos") {
+		t.Errorf("Expected ErrQuotaExceeded with the trigger's message, got %v", err)
+	}
+	if err := quotaErr(&pgconn.PgError{Code: "23505"}); errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected other errors to pass through, got %v", err)
+	}
+}
+
+func TestCreateJoinRequestErrors(t *testing.T) {
+	for constraint, want := range map[string]error{
+		"idx_household_join_requests_pending":        ErrJoinRequestPending,
+		"household_join_requests_household_uid_fkey": ErrUnknownHousehold,
+	} {
+		mockPool := &mockQueryer{
+			queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+				return &mockRow{err: &pgconn.PgError{Code: "23505", ConstraintName: constraint}}
+			},
+		}
+		dao, _ := New(context.Background(), mockPool)
+		if _, err := dao.CreateJoinRequest(context.Background(), "house-1", "user-1", ""); !errors.Is(err, want) {
+			t.Errorf("Expected %v for %s, got %v", want, constraint, err)
+		}
+	}
+}
+
+func TestRecordDeliveryAttempt(t *testing.T) {
+	var sql string
+	var args []any
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+			sql, args = q, a
+			return &mockRow{scanFunc: func(dest ...any) error {
+				*dest[6].(*int) = 2
+				return nil
+			}}
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	lastError := "timeout"
+	d, err := dao.RecordDeliveryAttempt(context.Background(), "d1", DeliveryFailed, &lastError)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sql != recordDeliveryAttempt || args[0] != "d1" || args[1] != DeliveryFailed || args[2] != &lastError {
+		t.Errorf("Expected recordDeliveryAttempt with the uid, status and error, got %q %v", sql, args)
+	}
+	if d.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", d.Attempts)
+	}
+}
+
+func TestEnqueueJobRun(t *testing.T) {
+	var sql string
+	var args []any
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+			sql, args = q, a
+			return &mockRow{err: pgx.ErrNoRows}
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	key := "digests@2025-08-15T07:00:00Z"
+	_, err := dao.EnqueueJobRun(context.Background(), JobRun{Name: "digests", DedupeKey: &key, MaxAttempts: 1})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows for an already queued run, got %v", err)
+	}
+	if sql != enqueueJobRun || args[0] != "digests" || string(args[1].(json.RawMessage)) != "{}" || args[2] != &key {
+		t.Errorf("Expected enqueueJobRun with an empty payload and the dedupe key, got %q %v", sql, args)
+	}
+}
+
+func TestLockJob(t *testing.T) {
+	for _, held := range []bool{false, true} {
+		tx := &mockTx{row: &mockRow{scanFunc: func(dest ...any) error {
+			*dest[0].(*bool) = !held
+			return nil
+		}}}
+		mockPool := &mockQueryer{beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil }}
+		dao, _ := New(context.Background(), mockPool)
+
+		unlock, ok, err := dao.LockJob(context.Background(), "digests")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(tx.sql) != 1 || tx.sql[0] != lockJob {
+			t.Errorf("Expected lockJob in the transaction, got %v", tx.sql)
+		}
+		if held {
+			if ok || unlock != nil || !tx.rolledBack {
+				t.Error("Expected a held lock not to be taken")
+			}
+			continue
+		}
+		if !ok || tx.rolledBack {
+			t.Fatal("Expected the lock to be taken and kept")
+		}
+		unlock()
+		if !tx.rolledBack {
+			t.Error("Expected unlock to end the transaction")
+		}
+	}
+}
+
+func TestUsageStats(t *testing.T) {
+	var queries []string
+	mockPool := &mockQueryer{
+		queryRowFunc: func(ctx context.Context, q string, a ...any) pgx.Row {
+			queries = append(queries, q)
+			return &mockRow{scanFunc: func(dest ...any) error { return nil }}
+		},
+		queryFunc: func(ctx context.Context, q string, a ...any) (pgx.Rows, error) {
+			queries = append(queries, q)
+			return nil, errors.New("permission denied")
+		},
+	}
+	dao, _ := New(context.Background(), mockPool)
+
+	if _, err := dao.UsageStats(context.Background()); err == nil {
+		t.Error("Expected the query error")
+	}
+	if len(queries) != 2 || queries[0] != getStatsReset || queries[1] != listTableUsage {
+		t.Errorf("Expected getStatsReset then listTableUsage, got %v", queries)
+	}
+}
+
+func TestFaultsFailBeforeTheStatementRuns(t *testing.T) {
+	calls := 0
+	mockPool := &mockQueryer{
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			calls++
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	dao, _ := New(context.Background(), mockPool, WithFaults(Faults{TransientRate: 1}), WithResilience(Resilience{MaxRetries: 2, RetryDelay: time.Millisecond}))
+	before := counter(injectedFaults.Get("transient"))
+
+	_, err := dao.pool.Exec(context.Background(), deleteNotes, "note-1")
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "40001" {
+		t.Fatalf("Expected an injected serialization failure, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected the statement never to run, ran %d times", calls)
+	}
+	if got := counter(injectedFaults.Get("transient")) - before; got != 3 {
+		t.Errorf("Expected every attempt to fail, got %d faults", got)
+	}
+
+	dao, _ = New(context.Background(), mockPool, WithFaults(Faults{PermanentRate: 1}))
+	if err := dao.pool.QueryRow(context.Background(), getNotes, "note-1").Scan(); !errors.As(err, &pgErr) || pgErr.Code != "XX000" {
+		t.Errorf("Expected an injected failure on Scan, got %v", err)
+	}
+	if _, err := dao.pool.Begin(context.Background()); err == nil {
+		t.Error("Expected Begin to fail")
+	}
+}
+
+func TestFaultsOnlyHitTheirStatements(t *testing.T) {
+	tx := &mockTx{}
+	mockPool := &mockQueryer{
+		execFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			return pgconn.CommandTag{}, nil
+		},
+		beginFunc: func(ctx context.Context) (pgx.Tx, error) { return tx, nil },
+	}
+	dao, _ := New(context.Background(), mockPool, WithFaults(Faults{PermanentRate: 1, Latency: 20 * time.Millisecond, Statements: []string{"update"}}))
+
+	start := time.Now()
+	if _, err := dao.pool.Exec(context.Background(), deleteNotes, "note-1"); err != nil {
+		t.Errorf("Expected a DELETE to be left alone, got %v", err)
+	}
+	if time.Since(start) >= 20*time.Millisecond {
+		t.Error("Expected a DELETE not to be delayed")
+	}
+
+	// Statements on a transaction are faulted too.
+	txn, err := dao.pool.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Expected BEGIN to be left alone, got %v", err)
+	}
+	start = time.Now()
+	if _, err := txn.Exec(context.Background(), "UPDATE todos SET title = $1", "x"); err == nil {
+		t.Error("Expected the UPDATE to fail")
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("Expected the UPDATE to be delayed")
+	}
+	if len(tx.sql) != 0 {
+		t.Errorf("Expected the UPDATE never to run, got %v", tx.sql)
+	}
+}
@@ -1,14 +1,39 @@
 package postgres
 
+// todoCompleter embeds the user who completed a todo as JSON.
+const todoCompleter = `(SELECT jsonb_build_object('uid', u.uid, 'name', u.name, 'email', u.email) FROM users u WHERE u.uid = todos.completed_by)`
+
+// todoLoggedMinutes is the time logged against a todo so far.
+const todoLoggedMinutes = `(SELECT COALESCE(SUM(l.minutes), 0) FROM todo_time_logs l WHERE l.todo_uid = todos.uid)`
+
+// projectDoneTodos and projectTotalTodos count a project's completed todos
+// and all of its todos.
+const (
+	projectDoneTodos  = `(SELECT COUNT(*) FROM todos t WHERE t.project_uid = projects.uid AND t.marked_complete IS NOT NULL)`
+	projectTotalTodos = `(SELECT COUNT(*) FROM todos t WHERE t.project_uid = projects.uid)`
+)
+
+// userColumns are a user's columns, in the order scanUser reads them.
+const userColumns = `uid, name, email, description, created_at, updated_at, household_uid, pronouns, birthday, phone, avatar_updated_at`
+
+// joinRequestColumns are a join request's columns, as r, with the names of
+// its household and user.
+const joinRequestColumns = `r.uid, r.household_uid, COALESCE((SELECT h.name FROM households h WHERE h.uid = r.household_uid), ''),
+	r.user_uid, COALESCE((SELECT u.name FROM users u WHERE u.uid = r.user_uid), ''),
+	r.status, r.message, r.decided_by, r.decided_at, r.created_at, r.updated_at`
+
+// recipeMyRating is the rating the transaction's user gave a recipe.
+const recipeMyRating = `(SELECT rr.rating FROM recipe_ratings rr WHERE rr.recipe_id = recipes.id AND rr.user_uid = current_app_user())`
+
 const (
 	insertTodo = `INSERT INTO todos
 	(uid,title,description,data,priority,due_date,recurs_on,marked_complete,
-	 external_url,user_uid,household_uid,completed_by,created_at,updated_at)
-	VALUES (gen_random_uuid()::uuid,$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW(),NOW()) 
-	RETURNING uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at;`
+	 external_url,user_uid,household_uid,completed_by,created_at,updated_at,location,status,estimate_minutes,project_uid,tags)
+	VALUES (gen_random_uuid()::uuid,$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW(),NOW(),$12,$13,$14,$15,COALESCE($16,'{}')) 
+	RETURNING uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer;`
 
-	getTodo    = `SELECT uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at FROM todos WHERE uid=$1;`
-	listTodos  = `SELECT uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at FROM todos ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
+	getTodo    = `SELECT uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer FROM todos WHERE uid=$1;`
+	listTodos  = `SELECT uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer FROM todos ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
 	updateTodo = `UPDATE todos SET 
 		title=COALESCE($2,title),
 		description=COALESCE($3,description),
@@ -17,20 +42,114 @@ const (
 		due_date=COALESCE($6,due_date),
 		recurs_on=COALESCE($7,recurs_on),
 		marked_complete=COALESCE($8,marked_complete),
+		status=CASE WHEN $8::timestamptz IS NULL THEN status ELSE 'done' END,
 		external_url=COALESCE($9,external_url),
 		completed_by=COALESCE($10,completed_by),
+		location=COALESCE($11,location),
+		estimate_minutes=COALESCE($12,estimate_minutes),
+		project_uid=COALESCE($13,project_uid),
+		tags=COALESCE($14,tags),
 		updated_at=NOW()
 		WHERE uid=$1 
-		RETURNING uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at;`
+		RETURNING uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer;`
 	deleteTodo = `DELETE FROM todos WHERE uid=$1;`
+	// setTodoStatus moves a todo to $2. Moving it to done completes it, by
+	// $3 if given; moving it out of done reopens it.
+	setTodoStatus = `UPDATE todos SET
+		status=$2,
+		marked_complete=CASE WHEN $2 = 'done' THEN COALESCE(marked_complete, NOW()) END,
+		completed_by=CASE WHEN $2 = 'done' THEN COALESCE($3, completed_by) END,
+		updated_at=NOW()
+		WHERE uid=$1
+		RETURNING uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer;`
+
+	// todoDependencyCycle reports whether $1 is already upstream of $2, in
+	// which case making $1 wait on $2 would close a loop.
+	todoDependencyCycle = `WITH RECURSIVE upstream(uid) AS (
+			SELECT blocked_by_uid FROM todo_dependencies WHERE todo_uid=$2
+			UNION
+			SELECT d.blocked_by_uid FROM todo_dependencies d JOIN upstream u ON d.todo_uid = u.uid
+		)
+		SELECT EXISTS (SELECT 1 FROM upstream WHERE uid=$1);`
+	insertTodoDependency = `INSERT INTO todo_dependencies (todo_uid, blocked_by_uid, tenant_uid, created_at)
+		SELECT t.uid, $2, t.tenant_uid, NOW() FROM todos t WHERE t.uid=$1
+		ON CONFLICT DO NOTHING;`
+	deleteTodoDependency = `DELETE FROM todo_dependencies WHERE todo_uid=$1 AND blocked_by_uid=$2;`
+	listTodoDependencies = `SELECT todo_uid, blocked_by_uid, created_at FROM todo_dependencies WHERE todo_uid=$1 OR blocked_by_uid=$1 ORDER BY created_at;`
 
-	insertBackground = `INSERT INTO backgrounds (key, value, created_at, updated_at)
-		VALUES ($1, $2, NOW(), NOW()) RETURNING *;`
-	getBackground    = `SELECT * FROM backgrounds WHERE key=$1;`
-	listBackgrounds  = `SELECT * FROM backgrounds ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
-	updateBackground = `UPDATE backgrounds SET value=$2, updated_at=NOW()
-		WHERE key=$1 RETURNING *;`
+	clearMyDay = `DELETE FROM my_day_todos WHERE user_uid=$1 AND expires_at <= NOW();`
+	addToMyDay = `INSERT INTO my_day_todos (user_uid, todo_uid, added_at, expires_at)
+		SELECT $1, uid, NOW(), $3 FROM todos WHERE uid=$2
+		ON CONFLICT (user_uid, todo_uid) DO UPDATE SET expires_at=EXCLUDED.expires_at;`
+	removeFromMyDay = `DELETE FROM my_day_todos WHERE user_uid=$1 AND todo_uid=$2;`
+	listMyDay       = `SELECT uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer FROM todos
+		WHERE uid IN (SELECT todo_uid FROM my_day_todos WHERE user_uid=$1 AND expires_at > NOW())
+		ORDER BY (SELECT m.added_at FROM my_day_todos m WHERE m.todo_uid = todos.uid AND m.user_uid=$1), uid;`
+
+	insertProject = `INSERT INTO projects (name, description, household_uid, archived, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,NOW(),NOW())
+		RETURNING uid, name, description, household_uid, archived, created_at, updated_at, ` + projectDoneTodos + ` AS done_todos, ` + projectTotalTodos + ` AS total_todos;`
+	getProject    = `SELECT uid, name, description, household_uid, archived, created_at, updated_at, ` + projectDoneTodos + ` AS done_todos, ` + projectTotalTodos + ` AS total_todos FROM projects WHERE uid=$1;`
+	updateProject = `UPDATE projects SET name=COALESCE($2,name), description=COALESCE($3,description), archived=COALESCE($4,archived), updated_at=NOW()
+		WHERE uid=$1
+		RETURNING uid, name, description, household_uid, archived, created_at, updated_at, ` + projectDoneTodos + ` AS done_todos, ` + projectTotalTodos + ` AS total_todos;`
+	deleteProject = `DELETE FROM projects WHERE uid=$1;`
+	// moveTodosToProject puts the todos $2 in project $1, taking them out of
+	// any other; removeTodosFromProject takes them out of $1 only.
+	moveTodosToProject = `UPDATE todos SET project_uid=$1, updated_at=NOW() WHERE uid = ANY($2::uuid[])
+		RETURNING uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer;`
+	removeTodosFromProject = `UPDATE todos SET project_uid=NULL, updated_at=NOW() WHERE project_uid=$1 AND uid = ANY($2::uuid[])
+		RETURNING uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer;`
+
+	insertTodoTimeLog = `INSERT INTO todo_time_logs (todo_uid, user_uid, minutes, logged_on, note, tenant_uid, created_at)
+		SELECT t.uid, $2, $3, $4, $5, t.tenant_uid, NOW() FROM todos t WHERE t.uid=$1
+		RETURNING uid, todo_uid, user_uid, minutes, logged_on, note, created_at;`
+	listTodoTimeLogs = `SELECT uid, todo_uid, user_uid, minutes, logged_on, note, created_at FROM todo_time_logs WHERE todo_uid=$1 ORDER BY logged_on, created_at;`
+	// todoWeeklyTime totals the time logged on todos of a household ($1),
+	// by a user ($2), or both, per week (starting Monday) and user, for days
+	// on or after $3 and before $4. $5 narrows it to todos whose title
+	// contains it.
+	todoWeeklyTime = `SELECT to_char(date_trunc('week', l.logged_on), 'YYYY-MM-DD') AS week, l.user_uid, SUM(l.minutes), COUNT(*)
+		FROM todo_time_logs l JOIN todos t ON t.uid = l.todo_uid
+		WHERE ($1 = '' OR t.household_uid::text = $1) AND ($2 = '' OR l.user_uid::text = $2)
+		AND l.logged_on >= $3 AND l.logged_on < $4 AND ($5 = '' OR t.title ILIKE '%' || $5 || '%')
+		GROUP BY week, l.user_uid ORDER BY week, l.user_uid;`
+
+	insertTodoTemplate = `INSERT INTO todo_templates (name, description, items, user_uid, household_uid, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING uid, name, description, items, user_uid, household_uid, created_at, updated_at;`
+	getTodoTemplate    = `SELECT uid, name, description, items, user_uid, household_uid, created_at, updated_at FROM todo_templates WHERE uid=$1;`
+	updateTodoTemplate = `UPDATE todo_templates SET name=COALESCE($2,name), description=COALESCE($3,description), items=COALESCE($4,items), updated_at=NOW()
+		WHERE uid=$1 RETURNING uid, name, description, items, user_uid, household_uid, created_at, updated_at;`
+	deleteTodoTemplate = `DELETE FROM todo_templates WHERE uid=$1;`
+
+	insertPantryItem = `INSERT INTO pantry_items (household_uid, item, quantity, unit, category, expires_on, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING uid, household_uid, item, quantity, unit, category, expires_on, created_at, updated_at;`
+	getPantryItem    = `SELECT uid, household_uid, item, quantity, unit, category, expires_on, created_at, updated_at FROM pantry_items WHERE uid=$1;`
+	updatePantryItem = `UPDATE pantry_items SET item=COALESCE($2,item), quantity=COALESCE($3,quantity), unit=COALESCE($4,unit), category=COALESCE($5,category), expires_on=COALESCE($6,expires_on), updated_at=NOW()
+		WHERE uid=$1 RETURNING uid, household_uid, item, quantity, unit, category, expires_on, created_at, updated_at;`
+	deletePantryItem = `DELETE FROM pantry_items WHERE uid=$1;`
+
+	insertGroceryPurchase = `INSERT INTO grocery_purchases (household_uid, item, quantity, store, price_cents, purchased_on, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, CURRENT_DATE), NOW(), NOW()) RETURNING uid, household_uid, item, quantity, store, price_cents, purchased_on, created_at, updated_at;`
+	getGroceryPurchase    = `SELECT uid, household_uid, item, quantity, store, price_cents, purchased_on, created_at, updated_at FROM grocery_purchases WHERE uid=$1;`
+	deleteGroceryPurchase = `DELETE FROM grocery_purchases WHERE uid=$1;`
+	groceryMonthlySpend   = `SELECT to_char(purchased_on, 'YYYY-MM') AS month, store, SUM(price_cents), COUNT(*) FROM grocery_purchases
+		WHERE household_uid=$1 AND purchased_on >= $2 AND purchased_on < $3 GROUP BY month, store ORDER BY month, store;`
+
+	insertBackground = `INSERT INTO backgrounds (key, value, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW()) RETURNING key, value, created_at, updated_at, expires_at;`
+	getBackground    = `SELECT key, value, created_at, updated_at, expires_at FROM backgrounds WHERE key=$1 AND (expires_at IS NULL OR expires_at > NOW());`
+	listBackgrounds  = `SELECT key, value, created_at, updated_at, expires_at FROM backgrounds ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
+	updateBackground = `UPDATE backgrounds SET value=$2, expires_at=$3, updated_at=NOW()
+		WHERE key=$1 AND (expires_at IS NULL OR expires_at > NOW()) RETURNING key, value, created_at, updated_at, expires_at;`
 	deleteBackground = `DELETE FROM backgrounds WHERE key=$1;`
+	upsertBackground = `INSERT INTO backgrounds (key, value, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (tenant_uid, key) DO UPDATE SET value=EXCLUDED.value, expires_at=EXCLUDED.expires_at, updated_at=NOW()
+		RETURNING key, value, created_at, updated_at, expires_at;`
+	listBackgroundsByPrefix = `SELECT key, value, created_at, updated_at, expires_at FROM backgrounds
+		WHERE starts_with(key, $1) AND (expires_at IS NULL OR expires_at > NOW()) ORDER BY key;`
+	deleteExpiredBackgrounds = `DELETE FROM backgrounds WHERE expires_at <= NOW();`
 
 	insertPreferences = `INSERT INTO preferences (key, specifier, data, tags, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING key, specifier, data, created_at, updated_at, tags;`
@@ -39,46 +158,278 @@ const (
 	updatePreferences = `UPDATE preferences SET data=$3, tags=$4, updated_at=NOW()
 		WHERE key=$1 AND specifier=$2 RETURNING key, specifier, data, created_at, updated_at, tags;`
 	deletePreferences = `DELETE FROM preferences WHERE key=$1 AND specifier=$2;`
+	upsertPreferences = `INSERT INTO preferences (key, specifier, data, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (tenant_uid, key, specifier) DO UPDATE SET data=EXCLUDED.data, tags=EXCLUDED.tags, updated_at=NOW()
+		RETURNING key, specifier, data, created_at, updated_at, tags;`
+	getPreferencesBulk = `SELECT p.key, p.specifier, p.data, p.created_at, p.updated_at, p.tags
+		FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS k(key, specifier, n)
+		JOIN preferences p ON p.key = k.key AND p.specifier = k.specifier ORDER BY k.n;`
 
-	insertNotes = `INSERT INTO notes (key, user_uid, household_uid, data, tags, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags;`
-	getNotes    = `SELECT id, key, data, created_at, updated_at, user_uid, household_uid, tags FROM notes WHERE id=$1;`
-	listNotes   = `SELECT * FROM notes ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
-	updateNotes = `UPDATE notes SET key=$2, user_uid=$3, household_uid=$4, data=$5, tags=$6, updated_at=NOW()
-		WHERE id=$1 RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags;`
-	deleteNotes = `DELETE FROM notes WHERE id=$1;`
+	insertNotes = `INSERT INTO notes (key, user_uid, household_uid, data, tags, visibility, remind_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at;`
+	getNotes    = `SELECT id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at FROM notes WHERE id=$1;`
+	listNotes   = `SELECT id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at FROM notes ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
+	updateNotes = `UPDATE notes SET key=$2, user_uid=$3, household_uid=$4, data=$5, tags=$6,
+		visibility=COALESCE(NULLIF($7, ''), visibility), remind_at=$8, updated_at=NOW()
+		WHERE id=$1 RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at;`
+	pinNotes = `UPDATE notes SET pinned=$2, sort_order=$3, updated_at=NOW()
+		WHERE id=$1 RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at;`
+	remindNotes = `UPDATE notes SET remind_at=$2, updated_at=NOW()
+		WHERE id=$1 RETURNING id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at;`
+	deleteNotes  = `DELETE FROM notes WHERE id=$1;`
+	archiveNotes = `UPDATE notes SET archived_at=NOW() WHERE id = ANY($1) AND archived_at IS NULL;`
 
 	insertCredentials = `INSERT INTO credentials (user_uid, credential_type, value, created_at, updated_at)
-		VALUES ($1, $2, $3, NOW(), NOW()) RETURNING *;`
-	getCredentials              = `SELECT * FROM credentials WHERE id=$1;`
-	getCredentialsByUserAndType = `SELECT * FROM credentials WHERE user_uid=$1 AND credential_type=$2;`
-	listCredentials             = `SELECT * FROM credentials ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
+		VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id, user_uid, credential_type, value, created_at, updated_at;`
+	getCredentials              = `SELECT id, user_uid, credential_type, value, created_at, updated_at FROM credentials WHERE id=$1;`
+	getCredentialsByUserAndType = `SELECT id, user_uid, credential_type, value, created_at, updated_at FROM credentials WHERE user_uid=$1 AND credential_type=$2;`
+	listCredentials             = `SELECT id, user_uid, credential_type, value, created_at, updated_at FROM credentials ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
 	updateCredentials           = `UPDATE credentials SET user_uid=$2, credential_type=$3, value=$4, updated_at=NOW()
-		WHERE id=$1 RETURNING *;`
+		WHERE id=$1 RETURNING id, user_uid, credential_type, value, created_at, updated_at;`
 	deleteCredentials = `DELETE FROM credentials WHERE id=$1;`
 
-	insertRecipes = `INSERT INTO recipes (title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at)
-		VALUES ($2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW()) RETURNING id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at;`
-	getRecipes    = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at FROM recipes WHERE id=$1;`
-	listRecipes   = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at FROM recipes ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
-	updateRecipes = `UPDATE recipes SET title=$2, external_url=$3, data=$4, genre=$5, grocery_list=$6, prep_time=$7, cook_time=$8, total_time=$9, servings=$10, difficulty=$11, rating=$12, tags=$13, user_uid=$14, household_uid=$15, updated_at=NOW()
-		WHERE id=$1 RETURNING id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at;`
+	insertRecipes = `INSERT INTO recipes (title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, tags, user_uid, household_uid, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW()) RETURNING id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating;`
+	getRecipes    = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating FROM recipes WHERE id=$1;`
+	listRecipes   = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating FROM recipes ORDER BY created_at DESC LIMIT $1 OFFSET $2;`
+	updateRecipes = `UPDATE recipes SET title=$2, external_url=$3, data=$4, genre=$5, grocery_list=$6, prep_time=$7, cook_time=$8, total_time=$9, servings=$10, difficulty=$11, tags=$12, user_uid=$13, household_uid=$14, updated_at=NOW()
+		WHERE id=$1 RETURNING id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating;`
 	deleteRecipes = `DELETE FROM recipes WHERE id=$1;`
+	rateRecipe    = `INSERT INTO recipe_ratings (recipe_id, user_uid, rating, created_at, updated_at)
+		SELECT id, $2, $3, NOW(), NOW() FROM recipes WHERE id=$1
+		ON CONFLICT (recipe_id, user_uid) DO UPDATE SET rating=EXCLUDED.rating, updated_at=NOW();`
+
+	insertRecipePhoto = `INSERT INTO recipe_photos (recipe_id, size, content_type, width, height, data, tenant_uid, created_at)
+		SELECT r.id, $2, $3, $4, $5, $6, r.tenant_uid, NOW() FROM recipes r WHERE r.id=$1;`
+	getRecipePhoto     = `SELECT recipe_id, size, content_type, width, height, data, created_at FROM recipe_photos WHERE recipe_id=$1 AND size=$2;`
+	deleteRecipePhotos = `DELETE FROM recipe_photos WHERE recipe_id=$1;`
+	setRecipePhotoTime = `UPDATE recipes SET photo_updated_at=$2 WHERE id=$1
+		RETURNING id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating;`
+
+	registerDevice = `INSERT INTO devices (user_uid, platform, token, name, created_at, updated_at) VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (platform, token) DO UPDATE SET user_uid=EXCLUDED.user_uid, name=EXCLUDED.name, updated_at=NOW()
+		RETURNING uid, user_uid, platform, token, name, created_at, updated_at;`
+	listDevicesByUserUID      = `SELECT uid, user_uid, platform, token, name, created_at, updated_at FROM devices WHERE user_uid=$1 ORDER BY created_at;`
+	listDevicesByHouseholdUID = `SELECT d.uid, d.user_uid, d.platform, d.token, d.name, d.created_at, d.updated_at
+		FROM devices d JOIN users u ON u.uid = d.user_uid WHERE u.household_uid=$1 ORDER BY d.created_at;`
+	deleteDevice = `DELETE FROM devices WHERE uid=$1;`
+
+	insertDelivery = `INSERT INTO deliveries (channel, recipient, payload, payload_hash, status, last_error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING uid, channel, recipient, payload, payload_hash, status, attempts, last_error, created_at, updated_at;`
+	getDelivery           = `SELECT uid, channel, recipient, payload, payload_hash, status, attempts, last_error, created_at, updated_at FROM deliveries WHERE uid=$1;`
+	recordDeliveryAttempt = `UPDATE deliveries SET status=$2, last_error=$3, attempts=attempts+1, updated_at=NOW() WHERE uid=$1
+		RETURNING uid, channel, recipient, payload, payload_hash, status, attempts, last_error, created_at, updated_at;`
+
+	jobRunColumnList = `uid, name, payload, dedupe_key, status, attempts, max_attempts, run_at, started_at, finished_at, last_error, created_at, updated_at`
+	enqueueJobRun    = `INSERT INTO job_runs (name, payload, dedupe_key, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) ON CONFLICT (dedupe_key) DO NOTHING
+		RETURNING ` + jobRunColumnList + `;`
+	// claimJobRun skips runs other workers hold, so each is claimed once.
+	claimJobRun = `UPDATE job_runs SET status='running', attempts=attempts+1, started_at=NOW(), finished_at=NULL, updated_at=NOW()
+		WHERE uid = (SELECT uid FROM job_runs WHERE (status='queued' AND run_at <= NOW()) OR (status='running' AND started_at < $1)
+			ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING ` + jobRunColumnList + `;`
+	finishJobRun = `UPDATE job_runs SET status=$2::text, last_error=$3, run_at=COALESCE($4, run_at),
+		finished_at=CASE WHEN $2::text = 'queued' THEN NULL ELSE NOW() END, updated_at=NOW() WHERE uid=$1
+		RETURNING ` + jobRunColumnList + `;`
+	getJobRun = `SELECT ` + jobRunColumnList + ` FROM job_runs WHERE uid=$1;`
+	// postponeJobRun puts back a run that was claimed but not started, so
+	// the claim doesn't count as an attempt.
+	postponeJobRun = `UPDATE job_runs SET status='queued', attempts=attempts-1, run_at=$2, started_at=NULL, updated_at=NOW() WHERE uid=$1
+		RETURNING ` + jobRunColumnList + `;`
+	lockJob = `SELECT pg_try_advisory_xact_lock(hashtextextended('job_runs:' || $1::text, 0));`
+
+	ping = `SELECT 1;`
+
+	getStatsReset  = `SELECT stats_reset FROM pg_stat_database WHERE datname = current_database();`
+	listTableUsage = `SELECT relname, n_live_tup, n_dead_tup, pg_total_relation_size(relid), pg_indexes_size(relid), COALESCE(seq_scan, 0), COALESCE(idx_scan, 0)
+		FROM pg_stat_user_tables WHERE schemaname = current_schema() ORDER BY pg_total_relation_size(relid) DESC, relname;`
+	listIndexUsage = `SELECT relname, indexrelname, pg_relation_size(indexrelid), COALESCE(idx_scan, 0)
+		FROM pg_stat_user_indexes WHERE schemaname = current_schema() ORDER BY pg_relation_size(indexrelid) DESC, relname, indexrelname;`
+
+	// searchEntity is formatted with a search target's table, its ID, title
+	// and body columns, any further conditions, and the numbers of the
+	// parameters holding the query, ts_headline's options and the limit.
+	// Only the hits kept get a headline, as it reads their whole body.
+	searchEntity = `SELECT id, title, ts_headline('english', body, websearch_to_tsquery('english', $%[6]d), $%[7]d), rank, user_uid, household_uid, updated_at
+		FROM (SELECT %[2]s::text AS id, %[3]s AS title, %[4]s AS body, ts_rank(search_vector, websearch_to_tsquery('english', $%[6]d)) AS rank, user_uid, household_uid, updated_at
+			FROM %[1]s WHERE search_vector @@ websearch_to_tsquery('english', $%[6]d)%[5]s
+			ORDER BY rank DESC, updated_at DESC LIMIT $%[8]d) hits
+		ORDER BY rank DESC, updated_at DESC;`
+
+	insertAwayPeriod = `WITH a AS (
+		INSERT INTO away_periods (user_uid, starts_on, ends_on, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING *)
+		SELECT a.uid, a.user_uid, u.name, a.starts_on, a.ends_on, a.note, a.created_at, a.updated_at
+		FROM a JOIN users u ON u.uid = a.user_uid;`
+	listAwayPeriodsByUserUID = `SELECT a.uid, a.user_uid, u.name, a.starts_on, a.ends_on, a.note, a.created_at, a.updated_at
+		FROM away_periods a JOIN users u ON u.uid = a.user_uid WHERE a.user_uid=$1 AND a.ends_on >= $2::date ORDER BY a.starts_on;`
+	listAwayPeriodsByHouseholdUID = `SELECT a.uid, a.user_uid, u.name, a.starts_on, a.ends_on, a.note, a.created_at, a.updated_at
+		FROM away_periods a JOIN users u ON u.uid = a.user_uid WHERE u.household_uid=$1 AND a.ends_on >= $2::date ORDER BY a.starts_on;`
+	deleteAwayPeriod = `DELETE FROM away_periods WHERE uid=$1;`
+
+	insertImportantDate = `INSERT INTO important_dates (household_uid, title, kind, date, recurrence, remind_days_ahead, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING uid, household_uid, title, kind, date, recurrence, remind_days_ahead, notes, created_at, updated_at;`
+	listImportantDates = `SELECT uid, household_uid, title, kind, date, recurrence, remind_days_ahead, notes, created_at, updated_at
+		FROM important_dates WHERE household_uid=$1 ORDER BY EXTRACT(MONTH FROM date), EXTRACT(DAY FROM date), title;`
+	deleteImportantDate = `DELETE FROM important_dates WHERE uid=$1;`
+
+	upsertRetentionPolicy = `INSERT INTO retention_policies (entity, household_uid, max_age_days, action, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (entity, (COALESCE(household_uid::text, ''))) DO UPDATE SET max_age_days=EXCLUDED.max_age_days, action=EXCLUDED.action, updated_at=NOW()
+		RETURNING uid, entity, household_uid, max_age_days, action, created_at, updated_at;`
+	listRetentionPolicies = `SELECT uid, entity, household_uid, max_age_days, action, created_at, updated_at FROM retention_policies ORDER BY entity, household_uid NULLS FIRST;`
+	deleteRetentionPolicy = `DELETE FROM retention_policies WHERE uid=$1;`
+	archiveNotesBefore    = `UPDATE notes SET archived_at=NOW()
+		WHERE ($1 = '' OR household_uid::text = $1) AND updated_at < $2 AND NOT pinned AND archived_at IS NULL;`
+	deleteNotesBefore            = `DELETE FROM notes WHERE ($1 = '' OR household_uid::text = $1) AND updated_at < $2 AND NOT pinned;`
+	deleteCompletedTodosBefore   = `DELETE FROM todos WHERE ($1 = '' OR household_uid::text = $1) AND marked_complete < $2;`
+	deleteGroceryPurchasesBefore = `DELETE FROM grocery_purchases WHERE ($1 = '' OR household_uid::text = $1) AND purchased_on < $2::date;`
+
+	upsertDataSchema = `INSERT INTO data_schemas (entity, key, schema, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (tenant_uid, entity, key) DO UPDATE SET schema=EXCLUDED.schema, updated_at=NOW()
+		RETURNING entity, key, schema, created_at, updated_at;`
+	getDataSchema    = `SELECT entity, key, schema, created_at, updated_at FROM data_schemas WHERE entity=$1 AND key=$2;`
+	listDataSchemas  = `SELECT entity, key, schema, created_at, updated_at FROM data_schemas ORDER BY entity, key;`
+	deleteDataSchema = `DELETE FROM data_schemas WHERE entity=$1 AND key=$2;`
+
+	insertAPIKey = `WITH k AS (
+		INSERT INTO api_keys (user_uid, name, key_hash, scopes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING *)
+		SELECT k.uid, k.user_uid, u.household_uid, k.name, k.key_hash, k.scopes, k.last_used_at, k.revoked_at, k.created_at, k.updated_at, k.tenant_uid
+		FROM k JOIN users u ON u.uid = k.user_uid;`
+	getAPIKeyByHash = `SELECT k.uid, k.user_uid, u.household_uid, k.name, k.key_hash, k.scopes, k.last_used_at, k.revoked_at, k.created_at, k.updated_at, k.tenant_uid
+		FROM api_keys k JOIN users u ON u.uid = k.user_uid WHERE k.key_hash=$1 AND k.revoked_at IS NULL;`
+	listAPIKeysByUserUID = `SELECT k.uid, k.user_uid, u.household_uid, k.name, k.key_hash, k.scopes, k.last_used_at, k.revoked_at, k.created_at, k.updated_at, k.tenant_uid
+		FROM api_keys k JOIN users u ON u.uid = k.user_uid WHERE k.user_uid=$1 ORDER BY k.created_at DESC;`
+	revokeAPIKey = `UPDATE api_keys SET revoked_at=NOW(), updated_at=NOW() WHERE uid=$1 AND revoked_at IS NULL;`
+	touchAPIKey  = `UPDATE api_keys SET last_used_at=NOW() WHERE uid=$1;`
+
+	addAPIUsage = `INSERT INTO api_usage (api_key_uid, user_uid, day, requests, tool_calls)
+		SELECT uid, user_uid, $2, $3, $4 FROM api_keys WHERE uid=$1
+		ON CONFLICT (api_key_uid, day) DO UPDATE SET requests=api_usage.requests+EXCLUDED.requests, tool_calls=api_usage.tool_calls+EXCLUDED.tool_calls;`
+	listAPIUsage = `SELECT a.api_key_uid, k.name, a.user_uid, u.name, u.household_uid, a.day, a.requests, a.tool_calls
+		FROM api_usage a JOIN api_keys k ON k.uid = a.api_key_uid JOIN users u ON u.uid = a.user_uid
+		WHERE ($1 = '' OR a.user_uid::text = $1) AND a.day BETWEEN $2::date AND $3::date ORDER BY a.day, a.api_key_uid;`
+
+	entityLockColumns = `l.entity, l.entity_id, l.api_key_uid, l.user_uid, COALESCE(u.name, ''), COALESCE(k.name, ''), l.acquired_at, l.expires_at`
+	// acquireEntityLock takes the lock when it is free or expired, or
+	// extends it for the key already holding it, and returns whichever lock
+	// is held afterwards.
+	acquireEntityLock = `WITH taken AS (
+			INSERT INTO entity_locks (entity, entity_id, api_key_uid, user_uid, acquired_at, expires_at)
+			VALUES ($1, $2, $3, $4, NOW(), NOW() + make_interval(secs => $5))
+			ON CONFLICT (tenant_uid, entity, entity_id) DO UPDATE SET api_key_uid=EXCLUDED.api_key_uid, user_uid=EXCLUDED.user_uid, expires_at=EXCLUDED.expires_at,
+				acquired_at=CASE WHEN entity_locks.expires_at > NOW() THEN entity_locks.acquired_at ELSE NOW() END
+			WHERE entity_locks.expires_at <= NOW() OR entity_locks.api_key_uid IS NOT DISTINCT FROM EXCLUDED.api_key_uid
+			RETURNING *
+		), held AS (
+			SELECT entity, entity_id, api_key_uid, user_uid, acquired_at, expires_at FROM taken
+			UNION ALL
+			SELECT entity, entity_id, api_key_uid, user_uid, acquired_at, expires_at FROM entity_locks
+			WHERE entity=$1 AND entity_id=$2 AND NOT EXISTS (SELECT 1 FROM taken)
+		)
+		SELECT ` + entityLockColumns + ` FROM held l LEFT JOIN users u ON u.uid = l.user_uid LEFT JOIN api_keys k ON k.uid = l.api_key_uid;`
+	getEntityLock = `SELECT ` + entityLockColumns + ` FROM entity_locks l LEFT JOIN users u ON u.uid = l.user_uid LEFT JOIN api_keys k ON k.uid = l.api_key_uid
+		WHERE l.entity=$1 AND l.entity_id=$2 AND l.expires_at > NOW();`
+	releaseEntityLock = `DELETE FROM entity_locks WHERE entity=$1 AND entity_id=$2 AND (api_key_uid IS NOT DISTINCT FROM $3 OR expires_at <= NOW());`
+
+	insertDashboardToken = `INSERT INTO dashboard_tokens (household_uid, name, token_hash, sections, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING uid, household_uid, name, token_hash, sections, last_used_at, revoked_at, created_at, updated_at, tenant_uid;`
+	getDashboardTokenByHash = `SELECT uid, household_uid, name, token_hash, sections, last_used_at, revoked_at, created_at, updated_at, tenant_uid
+		FROM dashboard_tokens WHERE token_hash=$1 AND revoked_at IS NULL;`
+	listDashboardTokens = `SELECT uid, household_uid, name, token_hash, sections, last_used_at, revoked_at, created_at, updated_at, tenant_uid
+		FROM dashboard_tokens WHERE household_uid=$1 ORDER BY created_at DESC;`
+	revokeDashboardToken = `UPDATE dashboard_tokens SET revoked_at=NOW(), updated_at=NOW() WHERE uid=$1 AND revoked_at IS NULL;`
+	touchDashboardToken  = `UPDATE dashboard_tokens SET last_used_at=NOW() WHERE uid=$1;`
+
+	listSyncChanges = `SELECT entity, entity_id, household_uid, user_uid, seq, version, deleted, updated_at FROM sync_states
+		WHERE seq > $1 AND (household_uid = NULLIF($2, '')::uuid OR user_uid = $3) ORDER BY seq LIMIT $4;`
+	getSyncState = `SELECT entity, entity_id, household_uid, user_uid, seq, version, deleted, updated_at FROM sync_states
+		WHERE entity=$1 AND entity_id=$2;`
+	upsertSyncState = `INSERT INTO sync_states (entity, entity_id, household_uid, user_uid, version, deleted, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (entity, entity_id) DO UPDATE SET seq=nextval('sync_seq'), household_uid=EXCLUDED.household_uid,
+			user_uid=EXCLUDED.user_uid, version=EXCLUDED.version, deleted=EXCLUDED.deleted, updated_at=EXCLUDED.updated_at
+		RETURNING entity, entity_id, household_uid, user_uid, seq, version, deleted, updated_at;`
+
+	upsertQuota = `INSERT INTO household_quotas (household_uid, max_todos, max_notes, max_recipes, max_photo_bytes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (tenant_uid, (COALESCE(household_uid::text, ''))) DO UPDATE SET max_todos=EXCLUDED.max_todos, max_notes=EXCLUDED.max_notes,
+			max_recipes=EXCLUDED.max_recipes, max_photo_bytes=EXCLUDED.max_photo_bytes, updated_at=NOW()
+		RETURNING uid, household_uid, max_todos, max_notes, max_recipes, max_photo_bytes, created_at, updated_at;`
+	listQuotas = `SELECT uid, household_uid, max_todos, max_notes, max_recipes, max_photo_bytes, created_at, updated_at
+		FROM household_quotas ORDER BY household_uid NULLS FIRST;`
+	deleteQuota       = `DELETE FROM household_quotas WHERE uid=$1;`
+	getHouseholdQuota = `SELECT uid, household_uid, max_todos, max_notes, max_recipes, max_photo_bytes, created_at, updated_at
+		FROM household_quotas WHERE household_uid=$1 OR household_uid IS NULL ORDER BY household_uid NULLS LAST LIMIT 1;`
+	getQuotaUsage = `SELECT household_usage($1, 'todos'), household_usage($1, 'notes'), household_usage($1, 'recipes'), household_usage($1, 'photo_bytes');`
+
+	upsertFeatureFlag = `INSERT INTO feature_flags (name, description, enabled, rollout_percent, household_uids, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5::uuid[], NOW(), NOW())
+		ON CONFLICT (tenant_uid, name) DO UPDATE SET description=EXCLUDED.description, enabled=EXCLUDED.enabled,
+			rollout_percent=EXCLUDED.rollout_percent, household_uids=EXCLUDED.household_uids, updated_at=NOW()
+		RETURNING uid, name, description, enabled, rollout_percent, household_uids::text[], created_at, updated_at;`
+	listFeatureFlags = `SELECT uid, name, description, enabled, rollout_percent, household_uids::text[], created_at, updated_at
+		FROM feature_flags ORDER BY name;`
+	deleteFeatureFlag = `DELETE FROM feature_flags WHERE name=$1;`
+
+	insertJoinRequest = `WITH r AS (INSERT INTO household_join_requests (household_uid, user_uid, message, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW()) RETURNING *)
+		SELECT ` + joinRequestColumns + ` FROM r;`
+	getJoinRequest   = `SELECT ` + joinRequestColumns + ` FROM household_join_requests r WHERE r.uid=$1;`
+	listJoinRequests = `SELECT ` + joinRequestColumns + ` FROM household_join_requests r
+		WHERE ($1 = '' OR r.household_uid::text = $1) AND ($2 = '' OR r.status = $2) ORDER BY r.created_at DESC;`
+	lockJoinRequest   = `SELECT household_uid, user_uid FROM household_join_requests WHERE uid=$1 AND status='pending' FOR UPDATE;`
+	joinHousehold     = `UPDATE users SET household_uid=$2, updated_at=NOW() WHERE uid=$1;`
+	decideJoinRequest = `WITH r AS (UPDATE household_join_requests SET status=$2, decided_by=$3, decided_at=NOW(), updated_at=NOW()
+		WHERE uid=$1 RETURNING *)
+		SELECT ` + joinRequestColumns + ` FROM r;`
+
+	insertToolPolicy = `INSERT INTO tool_policies (user_uid, household_uid, allowed_tools, disallowed_tools, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING uid, user_uid, household_uid, allowed_tools, disallowed_tools, created_at, updated_at;`
+	getToolPolicy          = `SELECT uid, user_uid, household_uid, allowed_tools, disallowed_tools, created_at, updated_at FROM tool_policies WHERE uid=$1;`
+	getToolPoliciesForUser = `SELECT uid, user_uid, household_uid, allowed_tools, disallowed_tools, created_at, updated_at FROM tool_policies WHERE user_uid=$1 OR household_uid=$2;`
+	updateToolPolicy       = `UPDATE tool_policies SET allowed_tools=$2, disallowed_tools=$3, updated_at=NOW()
+		WHERE uid=$1 RETURNING uid, user_uid, household_uid, allowed_tools, disallowed_tools, created_at, updated_at;`
+	deleteToolPolicy = `DELETE FROM tool_policies WHERE uid=$1;`
+
+	insertTenant = `INSERT INTO tenants (name, created_at, updated_at) VALUES ($1, NOW(), NOW()) RETURNING uid, name, created_at, updated_at;`
+	getTenant    = `SELECT uid, name, created_at, updated_at FROM tenants WHERE uid=$1;`
+	listTenants  = `SELECT uid, name, created_at, updated_at FROM tenants ORDER BY created_at;`
+	setTenant    = `SELECT set_config('app.tenant_uid', $1, false), set_config('app.all_tenants', $2, false);`
+	setScope     = `SELECT set_config('app.user_uid', $1, true), set_config('app.household_uid', $2, true);`
 
-	insertUser = `INSERT INTO users (uid, name, email, description, household_uid, created_at, updated_at)
-		VALUES (gen_random_uuid()::uuid, $1, $2, $3, $4, NOW(), NOW()) RETURNING uid, name, email, description, created_at, updated_at, household_uid;`
-	updateUser = `UPDATE users SET name=COALESCE($2,name), email=COALESCE($3,email), description=COALESCE($4,description), household_uid=COALESCE($5,household_uid), updated_at=NOW()
-		WHERE uid=$1 RETURNING uid, name, email, description, created_at, updated_at, household_uid;`
+	insertUser = `INSERT INTO users (uid, name, email, description, household_uid, pronouns, birthday, phone, created_at, updated_at)
+		VALUES (gen_random_uuid()::uuid, $1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) RETURNING ` + userColumns + `;`
+	updateUser = `UPDATE users SET name=COALESCE($2,name), email=COALESCE($3,email), description=COALESCE($4,description), household_uid=COALESCE($5,household_uid),
+		pronouns=COALESCE($6,pronouns), birthday=COALESCE($7,birthday), phone=COALESCE($8,phone), updated_at=NOW()
+		WHERE uid=$1 RETURNING ` + userColumns + `;`
+	listHouseholdMembers = `SELECT ` + userColumns + ` FROM users WHERE household_uid=$1 ORDER BY name;`
+	setUserAvatarTime    = `UPDATE users SET avatar_updated_at=$2 WHERE uid=$1 RETURNING ` + userColumns + `;`
+	insertUserAvatar     = `INSERT INTO user_avatars (user_uid, size, content_type, width, height, data, tenant_uid, created_at)
+		SELECT u.uid, $2, $3, $4, $5, $6, u.tenant_uid, NOW() FROM users u WHERE u.uid=$1;`
+	getUserAvatar     = `SELECT user_uid, size, content_type, width, height, data, created_at FROM user_avatars WHERE user_uid=$1 AND size=$2;`
+	deleteUserAvatars = `DELETE FROM user_avatars WHERE user_uid=$1;`
 
 	getSlackUser            = `SELECT slack_user_uid, user_uid, created_at, updated_at FROM slack_users WHERE slack_user_uid=$1;`
-	getUserBySlackUserUID   = `SELECT u.uid, u.name, u.email, u.description, u.created_at, u.updated_at, u.household_uid FROM users u JOIN slack_users su ON u.uid = su.user_uid WHERE su.slack_user_uid=$1;`
+	getUserBySlackUserUID   = `SELECT u.uid, u.name, u.email, u.description, u.created_at, u.updated_at, u.household_uid, u.pronouns, u.birthday, u.phone, u.avatar_updated_at FROM users u JOIN slack_users su ON u.uid = su.user_uid WHERE su.slack_user_uid=$1;`
 	getCredentialsByUserUID = `SELECT id, user_uid, credential_type, value, created_at, updated_at FROM credentials WHERE user_uid=$1;`
-	getUser                 = `SELECT uid, name, email, description, created_at, updated_at, household_uid FROM users WHERE uid=$1;`
-	getHousehold            = `SELECT * FROM households WHERE uid=$1;`
-	updateHousehold         = `UPDATE households SET name=COALESCE($2,name), description=COALESCE($3,description), updated_at=NOW()
-		WHERE uid=$1 RETURNING *;`
-	getTodosByUserUID       = `SELECT uid, title, description, data, priority, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at FROM todos WHERE user_uid=$1;`
-	getNotesByUserUID       = `SELECT id, key, data, created_at, updated_at, user_uid, household_uid, tags FROM notes WHERE user_uid=$1;`
-	getRecipesByUserUID     = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, tags, user_uid, household_uid, created_at, updated_at FROM recipes WHERE user_uid=$1;`
+	getUser                 = `SELECT ` + userColumns + ` FROM users WHERE uid=$1;`
+	getHousehold            = `SELECT uid, name, description, created_at, updated_at FROM households WHERE uid=$1;`
+	insertHousehold         = `INSERT INTO households (uid, name, description, created_at, updated_at)
+		VALUES (gen_random_uuid()::uuid, $1, $2, NOW(), NOW()) RETURNING uid, name, description, created_at, updated_at;`
+	getUsers        = `SELECT ` + userColumns + ` FROM users WHERE uid = ANY($1::uuid[]);`
+	getHouseholds   = `SELECT uid, name, description, created_at, updated_at FROM households WHERE uid = ANY($1::uuid[]);`
+	updateHousehold = `UPDATE households SET name=COALESCE($2,name), description=COALESCE($3,description), updated_at=NOW()
+		WHERE uid=$1 RETURNING uid, name, description, created_at, updated_at;`
+	getTodosByUserUID       = `SELECT uid, title, description, data, priority, status, due_date, recurs_on, marked_complete, external_url, user_uid, household_uid, completed_by, created_at, updated_at, location, project_uid, estimate_minutes, tags, ` + todoLoggedMinutes + ` AS logged_minutes, ` + todoCompleter + ` AS completer FROM todos WHERE user_uid=$1;`
+	getNotesByUserUID       = `SELECT id, key, data, created_at, updated_at, user_uid, household_uid, tags, visibility, pinned, sort_order, archived_at, remind_at FROM notes WHERE user_uid=$1 AND archived_at IS NULL ORDER BY pinned DESC, sort_order, created_at DESC;`
+	getRecipesByUserUID     = `SELECT id, title, external_url, data, genre, grocery_list, prep_time, cook_time, total_time, servings, difficulty, rating, rating_count, tags, user_uid, household_uid, created_at, updated_at, photo_updated_at, ` + recipeMyRating + ` AS my_rating FROM recipes WHERE user_uid=$1;`
 	getPreferencesByUserUID = `SELECT key, specifier, data, created_at, updated_at, tags FROM preferences WHERE specifier=$1;`
 )
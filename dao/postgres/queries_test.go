@@ -19,12 +19,12 @@ func TestQueryConstants(t *testing.T) {
 		{
 			name:    "getTodo selects by uid",
 			query:   getTodo,
-			wantSQL: "SELECT * FROM todos WHERE uid=$1",
+			wantSQL: "FROM todos WHERE uid=$1",
 		},
 		{
 			name:    "listTodos orders and limits",
 			query:   listTodos,
-			wantSQL: "SELECT * FROM todos ORDER BY created_at DESC LIMIT $1 OFFSET $2",
+			wantSQL: "FROM todos ORDER BY created_at DESC LIMIT $1 OFFSET $2",
 		},
 		{
 			name:    "updateTodo updates by uid",
@@ -44,7 +44,7 @@ func TestQueryConstants(t *testing.T) {
 		{
 			name:    "getBackground selects by key",
 			query:   getBackground,
-			wantSQL: "SELECT * FROM backgrounds WHERE key=$1",
+			wantSQL: "FROM backgrounds WHERE key=$1",
 		},
 		{
 			name:    "insertPreferences has key, specifier, and data",
@@ -54,7 +54,7 @@ func TestQueryConstants(t *testing.T) {
 		{
 			name:    "getPreferences selects by key and specifier",
 			query:   getPreferences,
-			wantSQL: "SELECT * FROM preferences WHERE key=$1 AND specifier=$2",
+			wantSQL: "FROM preferences WHERE key=$1 AND specifier=$2",
 		},
 	}
 	
@@ -70,15 +70,15 @@ func TestQueryConstants(t *testing.T) {
 func TestTodoQueries(t *testing.T) {
 	// Test that insertTodo has the correct number of parameters
 	paramCount := strings.Count(insertTodo, "$")
-	expectedParams := 12 // Based on the Todo struct fields being inserted (added user_uid and household_uid)
+	expectedParams := 16 // Based on the Todo struct fields being inserted
 	
 	if paramCount != expectedParams {
 		t.Errorf("insertTodo should have %d parameters, found %d", expectedParams, paramCount)
 	}
 	
-	// Test that insertTodo returns all fields
-	if !strings.Contains(insertTodo, "RETURNING *") {
-		t.Error("insertTodo should return all fields with RETURNING *")
+	// Test that insertTodo returns the saved row
+	if !strings.Contains(insertTodo, "RETURNING ") {
+		t.Error("insertTodo should return the saved row with RETURNING")
 	}
 	
 	// Test that updateTodo has updated_at=NOW()
@@ -90,7 +90,7 @@ func TestTodoQueries(t *testing.T) {
 func TestBackgroundQueries(t *testing.T) {
 	// Test insertBackground parameters
 	paramCount := strings.Count(insertBackground, "$")
-	expectedParams := 2 // key, value
+	expectedParams := 3 // key, value, expires_at
 	
 	if paramCount != expectedParams {
 		t.Errorf("insertBackground should have %d parameters, found %d", expectedParams, paramCount)
@@ -146,8 +146,8 @@ func TestQueryConsistency(t *testing.T) {
 		if !strings.Contains(iq.query, "NOW()") {
 			t.Errorf("%s should set timestamps to NOW()", iq.name)
 		}
-		if !strings.Contains(iq.query, "RETURNING *") {
-			t.Errorf("%s should return all fields with RETURNING *", iq.name)
+		if !strings.Contains(iq.query, "RETURNING ") {
+			t.Errorf("%s should return the saved row with RETURNING", iq.name)
 		}
 	}
 	
@@ -165,8 +165,8 @@ func TestQueryConsistency(t *testing.T) {
 		if !strings.Contains(uq.query, "updated_at=NOW()") {
 			t.Errorf("%s should update updated_at to NOW()", uq.name)
 		}
-		if !strings.Contains(uq.query, "RETURNING *") {
-			t.Errorf("%s should return all fields with RETURNING *", uq.name)
+		if !strings.Contains(uq.query, "RETURNING ") {
+			t.Errorf("%s should return the saved row with RETURNING", uq.name)
 		}
 	}
 	
@@ -0,0 +1,88 @@
+// Package fixtures makes households, users and their data for tests and
+// local development.
+//
+// The factories, such as Todo, return a record with sensible defaults that
+// the functions passed to them may change, for tests that need one record
+// and care about a field or two of it. A Set is a whole household, read
+// from a YAML or JSON fixture file with Load or Parse, and Insert writes it
+// to the database, as the seed command does with Demo.
+package fixtures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+func apply[T any](v T, overrides []func(*T)) T {
+	for _, o := range overrides {
+		o(&v)
+	}
+	return v
+}
+
+// Household returns a household named "Test Household".
+func Household(overrides ...func(*dao.Households)) dao.Households {
+	return apply(dao.Households{Name: "Test Household", Description: "Test household"}, overrides)
+}
+
+// User returns a user named "Test User" in no household, with an email
+// address of its own, as no two users may share one.
+func User(overrides ...func(*dao.Users)) dao.Users {
+	return apply(dao.Users{
+		Name:        "Test User",
+		Email:       "user-" + uuid.NewString()[:8] + "@example.com",
+		Description: "Test user",
+	}, overrides)
+}
+
+// Todo returns a high-priority todo due a day from now.
+func Todo(overrides ...func(*dao.Todo)) dao.Todo {
+	due := time.Now().Add(24 * time.Hour)
+	return apply(dao.Todo{
+		Title:       "Test Todo",
+		Description: "Test todo",
+		Data:        `{"test": true}`,
+		Priority:    dao.PriorityHigh,
+		DueDate:     &due,
+	}, overrides)
+}
+
+// Note returns a note keyed "test-key".
+func Note(overrides ...func(*dao.Notes)) dao.Notes {
+	return apply(dao.Notes{
+		Key:  "test-key",
+		Data: `{"content": "Test note content", "test": true}`,
+		Tags: []string{"test"},
+	}, overrides)
+}
+
+// Recipe returns an Italian recipe with every optional detail filled in.
+func Recipe(overrides ...func(*dao.Recipes)) dao.Recipes {
+	return apply(dao.Recipes{
+		Title:       "Test Recipe",
+		Data:        `{"instructions": ["Step 1", "Step 2"], "test": true}`,
+		Genre:       ptrTo("italian"),
+		GroceryList: ptrTo(`["pasta", "tomatoes", "cheese"]`),
+		PrepTime:    ptrTo(15),
+		CookTime:    ptrTo(30),
+		TotalTime:   ptrTo(45),
+		Servings:    ptrTo(4),
+		Difficulty:  ptrTo("medium"),
+		Tags:        []string{"test", "pasta", "italian"},
+	}, overrides)
+}
+
+// Preference returns a preference keyed "test-key" for a specifier of its
+// own.
+func Preference(overrides ...func(*dao.Preferences)) dao.Preferences {
+	return apply(dao.Preferences{
+		Key:       "test-key",
+		Specifier: uuid.NewString(),
+		Data:      `{"theme": "dark", "test": true}`,
+		Tags:      []string{"test"},
+	}, overrides)
+}
+
+func ptrTo[T any](v T) *T { return &v }
@@ -0,0 +1,158 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactories(t *testing.T) {
+	a, b := User(), User(func(u *dao.Users) { u.Name = "Sam" })
+	assert.NotEqual(t, a.Email, b.Email)
+	assert.Equal(t, "Test User", a.Name)
+	assert.Equal(t, "Sam", b.Name)
+
+	todo := Todo(func(td *dao.Todo) { td.Priority = dao.PriorityLow }, func(td *dao.Todo) { td.Title = "Later" })
+	assert.Equal(t, dao.PriorityLow, todo.Priority)
+	assert.Equal(t, "Later", todo.Title)
+	assert.NotNil(t, todo.DueDate)
+}
+
+// store records what Insert creates, giving each record a UID, and fails
+// once failAt calls have been made, if that is set.
+type store struct {
+	calls, failAt int
+	todos         []dao.Todo
+	notes         []dao.Notes
+	prefs         []dao.Preferences
+}
+
+func (s *store) call() (string, error) {
+	s.calls++
+	if s.calls == s.failAt {
+		return "", errors.New("connection refused")
+	}
+	return fmt.Sprintf("0d9c6f1e-%d", s.calls), nil
+}
+
+func (s *store) CreateHousehold(_ context.Context, h dao.Households) (dao.Households, error) {
+	uid, err := s.call()
+	h.UID = uid
+	return h, err
+}
+
+func (s *store) CreateUser(_ context.Context, u dao.Users) (dao.Users, error) {
+	uid, err := s.call()
+	u.UID = uid
+	return u, err
+}
+
+func (s *store) CreateTodos(_ context.Context, todos []dao.Todo) ([]dao.Todo, error) {
+	_, err := s.call()
+	s.todos = todos
+	return todos, err
+}
+
+func (s *store) CreateNotes(_ context.Context, n dao.Notes) (dao.Notes, error) {
+	_, err := s.call()
+	s.notes = append(s.notes, n)
+	return n, err
+}
+
+func (s *store) CreateRecipes(_ context.Context, r dao.Recipes) (dao.Recipes, error) {
+	_, err := s.call()
+	return r, err
+}
+
+func (s *store) CreatePreferences(_ context.Context, p dao.Preferences) (dao.Preferences, error) {
+	_, err := s.call()
+	s.prefs = append(s.prefs, p)
+	return p, err
+}
+
+func TestInsertDemo(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+	s := &store{}
+	out, err := Insert(t.Context(), s, Demo(), now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "The Riveras", out.Household.Name)
+	require.Len(t, out.Users, 2)
+	alex, sam := out.Users[0], out.Users[1]
+	assert.Equal(t, "alex+0d9c6f1e@example.com", alex.Email)
+	assert.Equal(t, out.Household.UID, *alex.HouseholdUID)
+
+	require.Len(t, out.Todos, 6)
+	assert.Equal(t, dao.PriorityHigh, s.todos[0].Priority)
+	assert.Equal(t, dao.TodoPlanned, s.todos[0].Status)
+	assert.Equal(t, now.AddDate(0, 0, 3), *s.todos[0].DueDate)
+	assert.Equal(t, sam.UID, *s.todos[0].UserUID)
+	assert.Nil(t, s.todos[0].HouseholdUID)
+	assert.Equal(t, alex.UID, *s.todos[5].CompletedBy)
+	for _, todo := range s.todos {
+		assert.Equal(t, "{}", todo.Data)
+	}
+
+	assert.Len(t, out.Notes, 3)
+	assert.Equal(t, "Mia: swimming Tuesdays and Thursdays at 4pm.\nLeo: football Saturdays at 9am.", s.notes[0].Data)
+	assert.Equal(t, dao.NoteVisibilityPrivate, s.notes[2].Visibility)
+	assert.Len(t, out.Recipes, 2)
+	assert.Equal(t, 40, *out.Recipes[1].TotalTime)
+	require.Len(t, out.Preferences, 3)
+	assert.Equal(t, alex.UID, s.prefs[0].Specifier)
+	assert.Equal(t, `"metric"`, s.prefs[1].Data)
+}
+
+func TestInsertStopsAtFirstFailure(t *testing.T) {
+	out, err := Insert(t.Context(), &store{failAt: 3}, Demo(), time.Now())
+	assert.EqualError(t, err, "creating user sam: connection refused")
+	assert.Len(t, out.Users, 1)
+	assert.Empty(t, out.Todos)
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"small.json": {Data: []byte(`{
+			"household": {"name": "The Okafors"},
+			"users": [{"ref": "ada", "name": "Ada Okafor"}],
+			"todos": [{"title": "Call the plumber", "priority": "critical", "user": "ada"}],
+			"preferences": [{"key": "units", "specifier": "household-wide", "data": "\"metric\""}]
+		}`)},
+		"typo.yaml":    {Data: []byte("household: {name: X}\ntodos: [{title: Y, prioirty: high}]\n")},
+		"unknown.yaml": {Data: []byte("household: {name: X}\nnotes: [{key: k, user: bob}]\n")},
+		"nameless.yml": {Data: []byte("users: [{ref: a}]\n")},
+		"twice.yaml":   {Data: []byte("household: {name: X}\nusers: [{ref: a}, {ref: a}]\n")},
+		"notes.txt":    {Data: []byte("household: {name: X}\n")},
+	}
+
+	set, err := Load(fsys, "small.json")
+	require.NoError(t, err)
+	assert.Equal(t, "The Okafors", set.Household.Name)
+	assert.Equal(t, dao.PriorityCritical, set.Todos[0].Priority)
+	assert.Equal(t, "ada", set.Todos[0].User)
+
+	s := &store{}
+	out, err := Insert(t.Context(), s, set, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "ada+0d9c6f1e@example.com", out.Users[0].Email)
+	assert.Equal(t, "household-wide", s.prefs[0].Specifier)
+
+	for name, want := range map[string]string{
+		"typo.yaml":    `unknown field "prioirty"`,
+		"unknown.yaml": `notes[0].user "bob" isn't one of the users' refs`,
+		"nameless.yml": "household.name is required",
+		"twice.yaml":   `users[1].ref "a" is used twice`,
+		"notes.txt":    "must be .yaml, .yml or .json",
+		"missing.yaml": "file does not exist",
+	} {
+		_, err := Load(fsys, name)
+		assert.ErrorContains(t, err, want, name)
+	}
+}
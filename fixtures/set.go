@@ -0,0 +1,292 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed demo.yaml
+var demo []byte
+
+// Set is a household, its users and their todos, notes, recipes and
+// preferences: the contents of a fixture file. Records are written as the
+// API returns them, with snake_case fields and priorities by name; their
+// UIDs and timestamps are ignored, as the database assigns them.
+type Set struct {
+	Household   dao.Households    `json:"household"`
+	Users       []UserEntry       `json:"users"`
+	Todos       []TodoEntry       `json:"todos"`
+	Notes       []NoteEntry       `json:"notes"`
+	Recipes     []RecipeEntry     `json:"recipes"`
+	Preferences []PreferenceEntry `json:"preferences"`
+}
+
+// UserEntry is a member of the set's household. Other entries refer to
+// them by Ref.
+type UserEntry struct {
+	dao.Users
+	Ref string `json:"ref"`
+}
+
+// Owner says whose an entry is: the user with the ref User, the household,
+// or both.
+type Owner struct {
+	User      string `json:"user"`
+	Household bool   `json:"household"`
+}
+
+// TodoEntry is a todo of the Owner's.
+type TodoEntry struct {
+	dao.Todo
+	Owner
+	// CompletedBy is a user's ref.
+	CompletedBy string `json:"completed_by"`
+	// DueInDays sets the due date this many days from when the set is
+	// inserted.
+	DueInDays *int `json:"due_in_days"`
+}
+
+// NoteEntry is a note of the Owner's.
+type NoteEntry struct {
+	dao.Notes
+	Owner
+}
+
+// RecipeEntry is a recipe of the Owner's.
+type RecipeEntry struct {
+	dao.Recipes
+	Owner
+}
+
+// PreferenceEntry is a preference of the user with the ref User, or, when
+// that is empty, of whatever its Specifier is.
+type PreferenceEntry struct {
+	dao.Preferences
+	User string `json:"user"`
+}
+
+// Demo returns the demo household the seed command creates: two users
+// with a representative set of todos, notes, recipes and preferences.
+func Demo() Set {
+	set, err := Parse("demo.yaml", demo)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// Load reads the fixture file name from fsys.
+func Load(fsys fs.FS, name string) (Set, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Set{}, err
+	}
+	return Parse(name, data)
+}
+
+// Parse reads a fixture file, in YAML or JSON according to the extension of
+// name, and checks that it names a household and that every ref it uses
+// is one of its users'. Unknown fields are an error, to catch typos.
+func Parse(name string, data []byte) (Set, error) {
+	switch path.Ext(name) {
+	case ".json":
+	case ".yaml", ".yml":
+		// Going through JSON lets the records' json tags and
+		// unmarshalers, like Priority's, apply to YAML too.
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return Set{}, fmt.Errorf("%s: %w", name, err)
+		}
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return Set{}, fmt.Errorf("%s: %w", name, err)
+		}
+	default:
+		return Set{}, fmt.Errorf("%s: fixture files must be .yaml, .yml or .json", name)
+	}
+	var set Set
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&set); err != nil {
+		return Set{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := set.validate(); err != nil {
+		return Set{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return set, nil
+}
+
+func (s Set) validate() error {
+	if s.Household.Name == "" {
+		return errors.New("household.name is required")
+	}
+	refs := map[string]bool{}
+	for i, u := range s.Users {
+		switch {
+		case u.Ref == "":
+			return fmt.Errorf("users[%d].ref is required", i)
+		case refs[u.Ref]:
+			return fmt.Errorf("users[%d].ref %q is used twice", i, u.Ref)
+		}
+		refs[u.Ref] = true
+	}
+	ref := func(field, r string) error {
+		if r != "" && !refs[r] {
+			return fmt.Errorf("%s %q isn't one of the users' refs", field, r)
+		}
+		return nil
+	}
+	for i, t := range s.Todos {
+		if err := ref(fmt.Sprintf("todos[%d].user", i), t.User); err != nil {
+			return err
+		}
+		if err := ref(fmt.Sprintf("todos[%d].completed_by", i), t.CompletedBy); err != nil {
+			return err
+		}
+	}
+	for i, n := range s.Notes {
+		if err := ref(fmt.Sprintf("notes[%d].user", i), n.User); err != nil {
+			return err
+		}
+	}
+	for i, r := range s.Recipes {
+		if err := ref(fmt.Sprintf("recipes[%d].user", i), r.User); err != nil {
+			return err
+		}
+	}
+	for i, p := range s.Preferences {
+		if p.User == "" && p.Specifier == "" {
+			return fmt.Errorf("preferences[%d] needs a user or a specifier", i)
+		}
+		if err := ref(fmt.Sprintf("preferences[%d].user", i), p.User); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store is what Insert writes with, which *dao.DAO is.
+type Store interface {
+	CreateHousehold(ctx context.Context, h dao.Households) (dao.Households, error)
+	CreateUser(ctx context.Context, u dao.Users) (dao.Users, error)
+	CreateTodos(ctx context.Context, todos []dao.Todo) ([]dao.Todo, error)
+	CreateNotes(ctx context.Context, n dao.Notes) (dao.Notes, error)
+	CreateRecipes(ctx context.Context, r dao.Recipes) (dao.Recipes, error)
+	CreatePreferences(ctx context.Context, p dao.Preferences) (dao.Preferences, error)
+}
+
+// Inserted is what Insert created, in the set's order.
+type Inserted struct {
+	Household   dao.Households
+	Users       []dao.Users
+	Todos       []dao.Todo
+	Notes       []dao.Notes
+	Recipes     []dao.Recipes
+	Preferences []dao.Preferences
+}
+
+// Insert creates set's household, its users as members of it, and then
+// their data, with relative due dates counted from now. It stops at the
+// first failure, returning what it created so far.
+//
+// Every call creates a new household, so a set may be inserted again: the
+// users' emails gain a "+tag" from the household's UID so they don't clash,
+// and users without one get ref@example.com first.
+func Insert(ctx context.Context, d Store, set Set, now time.Time) (Inserted, error) {
+	household, err := d.CreateHousehold(ctx, set.Household)
+	if err != nil {
+		return Inserted{}, fmt.Errorf("creating household: %w", err)
+	}
+	out := Inserted{Household: household}
+	home := &household.UID
+	tag := household.UID
+	if len(tag) > 8 {
+		tag = tag[:8]
+	}
+
+	uids := map[string]*string{}
+	for _, u := range set.Users {
+		user := u.Users
+		local, domain, ok := strings.Cut(user.Email, "@")
+		if !ok {
+			local, domain = u.Ref, "example.com"
+		}
+		user.Email = fmt.Sprintf("%s+%s@%s", local, tag, domain)
+		user.HouseholdUID = home
+		created, err := d.CreateUser(ctx, user)
+		if err != nil {
+			return out, fmt.Errorf("creating user %s: %w", u.Ref, err)
+		}
+		out.Users = append(out.Users, created)
+		uids[u.Ref] = &created.UID
+	}
+	owner := func(o Owner) (user, household *string) {
+		if o.Household {
+			household = home
+		}
+		return uids[o.User], household
+	}
+
+	if len(set.Todos) > 0 {
+		todos := make([]dao.Todo, len(set.Todos))
+		for i, t := range set.Todos {
+			todos[i] = t.Todo
+			todos[i].UserUID, todos[i].HouseholdUID = owner(t.Owner)
+			todos[i].CompletedBy = uids[t.CompletedBy]
+			if t.DueInDays != nil {
+				due := now.AddDate(0, 0, *t.DueInDays)
+				todos[i].DueDate = &due
+			}
+			if todos[i].Data == "" {
+				todos[i].Data = "{}"
+			}
+		}
+		if out.Todos, err = d.CreateTodos(ctx, todos); err != nil {
+			return out, fmt.Errorf("creating todos: %w", err)
+		}
+	}
+
+	for _, n := range set.Notes {
+		note := n.Notes
+		note.UserUID, note.HouseholdUID = owner(n.Owner)
+		created, err := d.CreateNotes(ctx, note)
+		if err != nil {
+			return out, fmt.Errorf("creating note %s: %w", note.Key, err)
+		}
+		out.Notes = append(out.Notes, created)
+	}
+
+	for _, r := range set.Recipes {
+		recipe := r.Recipes
+		recipe.UserUID, recipe.HouseholdUID = owner(r.Owner)
+		created, err := d.CreateRecipes(ctx, recipe)
+		if err != nil {
+			return out, fmt.Errorf("creating recipe %s: %w", recipe.Title, err)
+		}
+		out.Recipes = append(out.Recipes, created)
+	}
+
+	for _, p := range set.Preferences {
+		pref := p.Preferences
+		if p.User != "" {
+			pref.Specifier = *uids[p.User]
+		}
+		created, err := d.CreatePreferences(ctx, pref)
+		if err != nil {
+			return out, fmt.Errorf("creating preference %s: %w", pref.Key, err)
+		}
+		out.Preferences = append(out.Preferences, created)
+	}
+	return out, nil
+}
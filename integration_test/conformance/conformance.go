@@ -0,0 +1,758 @@
+// Package conformance checks a running assistant-server against the MCP
+// contract its clients depend on: initialize negotiation and sessions,
+// well-formed tool schemas in tools/list, and a happy and an error path
+// for every tool it lists, with results in the {"status": ...} shape.
+//
+// The suite writes through the tools, so point it at a test or staging
+// server, with an API key that has the mcp:write scope and belongs to a
+// user with a household.
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Client speaks JSON-RPC to an MCP endpoint over plain HTTP POSTs. It
+// never asks for event streams, so every response is a single JSON body.
+type Client struct {
+	URL    string
+	APIKey string
+	HTTP   *http.Client
+
+	protocolVersion string
+	sessionID       string
+	nextID          int
+}
+
+func NewClient(url, apiKey string) *Client {
+	return &Client{URL: strings.TrimSuffix(url, "/") + "/", APIKey: apiKey, HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// fork returns a client for the same server without a session.
+func (c *Client) fork() *Client {
+	return &Client{URL: c.URL, APIKey: c.APIKey, HTTP: c.HTTP}
+}
+
+type rpcError struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      any             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+func (c *Client) newRequest(t *testing.T, method string, body []byte) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, c.URL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	if c.protocolVersion != "" {
+		req.Header.Set("MCP-Protocol-Version", c.protocolVersion)
+	}
+	if c.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", c.sessionID)
+	}
+	return req
+}
+
+// Request sends method and returns the server's response, which must be a
+// JSON-RPC 2.0 response to this request.
+func (c *Client) Request(t *testing.T, method string, params any) (rpcResponse, http.Header) {
+	t.Helper()
+	c.nextID++
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	require.NoError(t, err)
+	resp, err := c.HTTP.Do(c.newRequest(t, http.MethodPost, body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "%s: HTTP status", method)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"), "%s: Content-Type", method)
+
+	var out rpcResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out), "%s: response is not JSON", method)
+	assert.Equal(t, "2.0", out.JSONRPC, "%s: jsonrpc", method)
+	assert.EqualValues(t, c.nextID, out.ID, "%s: response id", method)
+	assert.True(t, (out.Error == nil) != (out.Result == nil), "%s: a response has exactly one of result and error", method)
+	return out, resp.Header
+}
+
+// Initialize negotiates version and, when the server agrees, uses it and
+// the session it creates for every later request.
+func (c *Client) Initialize(t *testing.T, version string) rpcResponse {
+	t.Helper()
+	c.protocolVersion, c.sessionID = "", ""
+	resp, header := c.Request(t, "initialize", map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "mcp-conformance", "version": "1.0.0"},
+	})
+	if resp.Error == nil {
+		c.protocolVersion = version
+		c.sessionID = header.Get("Mcp-Session-Id")
+	}
+	return resp
+}
+
+// ListTools returns every page of tools/list, checking that pages don't
+// repeat tools.
+func (c *Client) ListTools(t *testing.T) []Tool {
+	t.Helper()
+	var tools []Tool
+	seen := map[string]bool{}
+	cursor := ""
+	for page := 0; ; page++ {
+		require.Less(t, page, 100, "tools/list keeps returning a nextCursor")
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		resp, _ := c.Request(t, "tools/list", params)
+		require.Nil(t, resp.Error, "tools/list")
+		var result struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		for _, tool := range result.Tools {
+			assert.False(t, seen[tool.Name], "%s is listed twice", tool.Name)
+			seen[tool.Name] = true
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// ToolResult is a tools/call result, decoded from its text content.
+type ToolResult struct {
+	IsError bool
+	Body    map[string]any
+}
+
+// Status is "ok" or "error".
+func (r ToolResult) Status() string {
+	s, _ := r.Body["status"].(string)
+	return s
+}
+
+// CallTool calls a tool and checks its result has the shape every tool
+// result must, whether it succeeded or not.
+func (c *Client) CallTool(t *testing.T, name string, arguments map[string]any) ToolResult {
+	t.Helper()
+	resp, _ := c.Request(t, "tools/call", map[string]any{"name": name, "arguments": arguments})
+	require.Nil(t, resp.Error, "tools/call %s: tool failures belong in the result, not a JSON-RPC error", name)
+
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError           bool           `json:"isError"`
+		StructuredContent map[string]any `json:"structuredContent"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	require.Len(t, result.Content, 1, "%s: content", name)
+	require.Equal(t, "text", result.Content[0].Type, "%s: content type", name)
+	out := ToolResult{IsError: result.IsError}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out.Body), "%s: text content is not a JSON object", name)
+
+	switch out.Status() {
+	case "ok":
+		assert.False(t, out.IsError, "%s: isError on an ok result", name)
+		assert.NotEmpty(t, out.Body["summary"], "%s: ok result without a summary", name)
+	case "error":
+		assert.True(t, out.IsError, "%s: isError not set on an error result", name)
+		assert.NotEmpty(t, out.Body["error"], "%s: error result without an error", name)
+	default:
+		t.Errorf("%s: status is %q, want ok or error", name, out.Body["status"])
+	}
+	if c.protocolVersion >= service.ProtocolVersion20250618 {
+		assert.Equal(t, out.Body, result.StructuredContent, "%s: structuredContent must match the text content", name)
+	} else {
+		assert.Nil(t, result.StructuredContent, "%s: structuredContent before %s", name, service.ProtocolVersion20250618)
+	}
+	return out
+}
+
+// Tool is an entry of tools/list.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema InputSchema     `json:"inputSchema"`
+	Annotations json.RawMessage `json:"annotations"`
+}
+
+type InputSchema struct {
+	Type       string                    `json:"type"`
+	Properties map[string]map[string]any `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+// Run checks the server c points at. It initializes its own sessions, so
+// c need only have a URL and API key.
+func Run(t *testing.T, c *Client) {
+	t.Run("initialize", func(t *testing.T) { testInitialize(t, c.fork()) })
+	t.Run("sessions", func(t *testing.T) { testSessions(t, c.fork()) })
+	t.Run("tools/list", func(t *testing.T) { testToolsList(t, c.fork()) })
+	t.Run("tools/call", func(t *testing.T) { testToolsCall(t, c.fork()) })
+}
+
+func testInitialize(t *testing.T, c *Client) {
+	for _, version := range service.SupportedProtocolVersions {
+		t.Run(version, func(t *testing.T) {
+			resp := c.Initialize(t, version)
+			require.Nil(t, resp.Error)
+			var result struct {
+				ProtocolVersion string         `json:"protocolVersion"`
+				Capabilities    map[string]any `json:"capabilities"`
+				ServerInfo      struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+				} `json:"serverInfo"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Result, &result))
+			assert.Equal(t, version, result.ProtocolVersion)
+			assert.Contains(t, result.Capabilities, "tools")
+			assert.NotEmpty(t, result.ServerInfo.Name)
+			assert.NotEmpty(t, result.ServerInfo.Version)
+			assert.NotEmpty(t, c.sessionID, "initialize must return an Mcp-Session-Id")
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		resp := c.Initialize(t, "1999-01-01")
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, -32602, resp.Error.Code)
+		assert.Equal(t, "1999-01-01", resp.Error.Data["requested"])
+		assert.NotEmpty(t, resp.Error.Data["supported"])
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		require.Nil(t, c.Initialize(t, service.LatestProtocolVersion).Error)
+		resp, _ := c.Request(t, "conformance/unknown", nil)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, -32601, resp.Error.Code)
+	})
+
+	t.Run("unknown tool", func(t *testing.T) {
+		require.Nil(t, c.Initialize(t, service.LatestProtocolVersion).Error)
+		assert.Equal(t, "error", c.CallTool(t, "conformance_unknown_tool", nil).Status())
+	})
+}
+
+func testSessions(t *testing.T, c *Client) {
+	require.Nil(t, c.Initialize(t, service.LatestProtocolVersion).Error)
+	resp, _ := c.Request(t, "initialized", nil)
+	assert.Nil(t, resp.Error)
+
+	// The session carries the negotiated version for requests that omit
+	// the header.
+	c.protocolVersion = ""
+	resp, _ = c.Request(t, "tools/list", nil)
+	assert.Nil(t, resp.Error)
+
+	do := func(method string) int {
+		resp, err := c.HTTP.Do(c.newRequest(t, method, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost), "a deleted session must not be usable")
+
+	c.sessionID = ""
+	c.protocolVersion = "1999-01-01"
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost), "an unsupported MCP-Protocol-Version header must be refused")
+}
+
+var (
+	toolNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	schemaTypes     = []string{"string", "number", "integer", "boolean", "array", "object"}
+)
+
+func testToolsList(t *testing.T, c *Client) {
+	for _, version := range service.SupportedProtocolVersions {
+		t.Run(version, func(t *testing.T) {
+			require.Nil(t, c.Initialize(t, version).Error)
+			tools := c.ListTools(t)
+			require.NotEmpty(t, tools)
+			for _, tool := range tools {
+				checkTool(t, version, tool)
+			}
+		})
+	}
+}
+
+func checkTool(t *testing.T, version string, tool Tool) {
+	t.Helper()
+	assert.Regexp(t, toolNamePattern, tool.Name)
+	assert.NotEmpty(t, tool.Description, "%s: description", tool.Name)
+
+	schema := tool.InputSchema
+	assert.Equal(t, "object", schema.Type, "%s: inputSchema type", tool.Name)
+	for name, prop := range schema.Properties {
+		typ, _ := prop["type"].(string)
+		assert.Contains(t, schemaTypes, typ, "%s.%s: type", tool.Name, name)
+		if typ == "array" {
+			assert.Contains(t, prop, "items", "%s.%s: arrays must describe their items", tool.Name, name)
+		}
+		if enum, ok := prop["enum"]; ok {
+			assert.NotEmpty(t, enum, "%s.%s: enum", tool.Name, name)
+		}
+	}
+	for _, name := range schema.Required {
+		assert.Contains(t, schema.Properties, name, "%s: required argument %s is not a property", tool.Name, name)
+	}
+
+	if version < service.ProtocolVersion20250326 {
+		assert.Nil(t, tool.Annotations, "%s: annotations before %s", tool.Name, service.ProtocolVersion20250326)
+		return
+	}
+	if tool.Annotations == nil {
+		return
+	}
+	var annotations map[string]any
+	require.NoError(t, json.Unmarshal(tool.Annotations, &annotations), "%s: annotations", tool.Name)
+	for key, value := range annotations {
+		if strings.HasSuffix(key, "Hint") {
+			assert.IsType(t, true, value, "%s: annotations.%s", tool.Name, key)
+		}
+	}
+}
+
+func testToolsCall(t *testing.T, c *Client) {
+	require.Nil(t, c.Initialize(t, service.LatestProtocolVersion).Error)
+	listed := map[string]bool{}
+	for _, tool := range c.ListTools(t) {
+		listed[tool.Name] = true
+	}
+	covered := map[string]bool{}
+	for _, tc := range toolCases {
+		covered[tc.Tool] = true
+	}
+	for _, name := range sortedKeys(listed) {
+		assert.True(t, covered[name], "%s has no conformance case; add one to toolCases", name)
+	}
+
+	s := state{}
+	for _, tc := range toolCases {
+		if !listed[tc.Tool] {
+			continue
+		}
+		t.Run(tc.Tool, func(t *testing.T) { tc.run(t, c, s) })
+	}
+
+	// Every revision gets the same result body; only 2025-06-18 and later
+	// repeat it as structuredContent.
+	for _, version := range service.SupportedProtocolVersions {
+		t.Run("result/"+version, func(t *testing.T) {
+			c := c.fork()
+			require.Nil(t, c.Initialize(t, version).Error)
+			assert.Equal(t, "ok", c.CallTool(t, "list_todos", map[string]any{}).Status())
+		})
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// state holds the IDs of what earlier cases created, for later ones.
+type state map[string]string
+
+// toolCase exercises one tool. Cases run in the order of toolCases, so a
+// case may use what an earlier one saved.
+type toolCase struct {
+	Tool string
+	// OK returns arguments the tool must accept.
+	OK func(s state) map[string]any
+	// Save, when set, records what the OK call created.
+	Save func(s state, body map[string]any)
+	// Fail returns arguments the tool must reject.
+	Fail func(s state) map[string]any
+	// External tools depend on something outside the server, such as a
+	// connected calendar or an LLM, so their OK call need only return a
+	// well-formed result.
+	External bool
+}
+
+func (tc toolCase) run(t *testing.T, c *Client, s state) {
+	ok := c.CallTool(t, tc.Tool, tc.OK(s))
+	if !tc.External {
+		require.Equal(t, "ok", ok.Status(), "%s: %v", tc.Tool, ok.Body["error"])
+		if tc.Save != nil {
+			tc.Save(s, ok.Body)
+		}
+	}
+	failed := c.CallTool(t, tc.Tool, tc.Fail(s))
+	assert.Equal(t, "error", failed.Status(), "%s accepted arguments it should reject", tc.Tool)
+}
+
+// save records the ID of the object under key in a result as s[as].
+func save(key, id, as string) func(s state, body map[string]any) {
+	return func(s state, body map[string]any) {
+		obj, _ := body[key].(map[string]any)
+		if v, ok := obj[id]; ok && v != nil {
+			s[as] = fmt.Sprint(v)
+		}
+	}
+}
+
+func args(kv ...any) func(s state) map[string]any {
+	return func(state) map[string]any {
+		m := map[string]any{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			m[kv[i].(string)] = kv[i+1]
+		}
+		return m
+	}
+}
+
+// Owner IDs no API key can belong to.
+const (
+	foreignHousehold = "00000000-0000-0000-0000-0000000000ff"
+	foreignUser      = "00000000-0000-0000-0000-0000000000fe"
+)
+
+var toolCases = []toolCase{
+	// Todos
+	{
+		Tool: "create_todo",
+		OK:   args("title", "Conformance todo", "description", "Created by the MCP conformance suite"),
+		Save: save("todo", "uid", "todo"),
+		Fail: args("description", "No title"),
+	},
+	{
+		Tool: "create_todo",
+		OK:   args("title", "Conformance blocker"),
+		Save: save("todo", "uid", "blocker"),
+		Fail: args("title", "Foreign", "household_uid", foreignHousehold),
+	},
+	{
+		Tool: "list_todos",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "set_todo_status",
+		OK:   func(s state) map[string]any { return map[string]any{"todo_id": s["todo"], "status": "in_progress"} },
+		Fail: func(s state) map[string]any { return map[string]any{"todo_id": s["todo"], "status": "someday"} },
+	},
+	{
+		Tool: "link_todos",
+		OK: func(s state) map[string]any {
+			return map[string]any{"todo_id": s["todo"], "blocked_by_id": s["blocker"]}
+		},
+		Fail: func(s state) map[string]any { return map[string]any{"todo_id": s["todo"]} },
+	},
+	{
+		Tool: "log_time",
+		OK:   func(s state) map[string]any { return map[string]any{"todo_id": s["todo"], "minutes": 15} },
+		Fail: func(s state) map[string]any { return map[string]any{"todo_id": s["todo"], "minutes": 0} },
+	},
+	{
+		Tool: "time_report",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "add_to_my_day",
+		OK:   func(s state) map[string]any { return map[string]any{"todo_id": s["todo"]} },
+		Fail: args(),
+	},
+	{
+		Tool: "get_my_day",
+		OK:   args(),
+		Fail: args("user_uid", foreignUser),
+	},
+	{
+		Tool: "remove_from_my_day",
+		OK:   func(s state) map[string]any { return map[string]any{"todo_id": s["todo"]} },
+		Fail: args(),
+	},
+	{
+		Tool: "create_project",
+		OK:   args("name", "Conformance project"),
+		Save: save("project", "uid", "project"),
+		Fail: args("name", "Foreign", "household_uid", foreignHousehold),
+	},
+	{
+		Tool: "list_projects",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "move_todos_to_project",
+		OK: func(s state) map[string]any {
+			return map[string]any{"project_id": s["project"], "todo_ids": []any{s["todo"]}}
+		},
+		Fail: func(s state) map[string]any { return map[string]any{"project_id": s["project"]} },
+	},
+	{
+		Tool: "complete_todo",
+		OK:   func(s state) map[string]any { return map[string]any{"todo_id": s["blocker"]} },
+		Fail: args(),
+	},
+
+	// Notes
+	{
+		Tool: "save_note",
+		OK:   args("key", "conformance", "data", "Buy milk and call the plumber"),
+		Save: save("note", "id", "note"),
+		Fail: args("key", "conformance"),
+	},
+	{
+		Tool: "list_notes",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "recall_note",
+		OK:   func(s state) map[string]any { return map[string]any{"note_id": s["note"]} },
+		Fail: args(),
+	},
+	{
+		Tool: "pin_note",
+		OK:   func(s state) map[string]any { return map[string]any{"note_id": s["note"]} },
+		Fail: args(),
+	},
+	{
+		Tool: "remind_me",
+		OK: func(s state) map[string]any {
+			return map[string]any{"note_id": s["note"], "remind_at": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)}
+		},
+		Fail: func(s state) map[string]any { return map[string]any{"note_id": s["note"]} },
+	},
+	{
+		Tool:     "extract_todos",
+		OK:       func(s state) map[string]any { return map[string]any{"note_id": s["note"]} },
+		Fail:     args(),
+		External: true,
+	},
+	{
+		Tool: "get_data_schema",
+		OK:   args("entity", "notes", "key", "conformance"),
+		Fail: args("entity", "recipes", "key", "conformance"),
+	},
+	{
+		Tool: "delete_note",
+		OK:   func(s state) map[string]any { return map[string]any{"note_id": s["note"], "confirm": true} },
+		Fail: args("confirm", true),
+	},
+
+	// Preferences
+	{
+		Tool: "set_preference",
+		OK:   args("key", "conformance", "specifier", "single", "data", "on"),
+		Fail: args("key", "conformance", "specifier", "single"),
+	},
+	{
+		Tool: "get_preference",
+		OK:   args("key", "conformance", "specifier", "single"),
+		Fail: args("key", "conformance"),
+	},
+	{
+		Tool: "set_preferences_bulk",
+		OK:   args("preferences", []any{map[string]any{"key": "conformance", "specifier": "bulk", "data": "on"}}),
+		Fail: args("preferences", []any{}),
+	},
+	{
+		Tool: "get_preferences_bulk",
+		OK:   args("preferences", []any{map[string]any{"key": "conformance", "specifier": "bulk"}}),
+		Fail: args("preferences", []any{map[string]any{"key": "conformance"}}),
+	},
+	{
+		Tool: "set_notification_preference",
+		OK:   args("quiet_hours", "22:00-07:00"),
+		Fail: args("quiet_hours", "late"),
+	},
+
+	// Recipes and shopping
+	{
+		Tool: "save_recipe",
+		OK:   args("title", "Conformance soup", "data", "Simmer everything for an hour", "grocery_list", "2 carrots\n1 onion"),
+		Save: save("recipe", "id", "recipe"),
+		Fail: args("title", "Conformance soup"),
+	},
+	{
+		Tool: "find_recipes",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "get_recipe",
+		OK:   func(s state) map[string]any { return map[string]any{"recipe_id": s["recipe"]} },
+		Fail: args(),
+	},
+	{
+		Tool: "rate_recipe",
+		OK:   func(s state) map[string]any { return map[string]any{"recipe_id": s["recipe"], "rating": 4} },
+		Fail: func(s state) map[string]any { return map[string]any{"recipe_id": s["recipe"], "rating": 9} },
+	},
+	{
+		Tool: "duplicate_recipe",
+		OK: func(s state) map[string]any {
+			return map[string]any{"recipe_id": s["recipe"], "title": "Conformance soup, again"}
+		},
+		Save: save("recipe", "id", "copy"),
+		Fail: args(),
+	},
+	{
+		Tool:     "refresh_recipe",
+		OK:       func(s state) map[string]any { return map[string]any{"recipe_id": s["recipe"]} },
+		Fail:     args(),
+		External: true,
+	},
+	{
+		Tool: "convert_units",
+		OK:   args("quantity", "2 cups", "to", "ml"),
+		Fail: args("quantity", "2 cups", "to", "furlongs"),
+	},
+	{
+		Tool: "build_shopping_list",
+		OK:   func(s state) map[string]any { return map[string]any{"recipe_ids": s["recipe"]} },
+		Fail: func(s state) map[string]any {
+			return map[string]any{"recipe_ids": s["recipe"], "household_uid": foreignHousehold}
+		},
+	},
+	{
+		Tool: "set_grocery_stores",
+		OK:   args("stores", "Corner Shop, Market"),
+		Fail: args(),
+	},
+	{
+		Tool: "set_preferred_store",
+		OK:   args("item", "carrots", "store", "Market"),
+		Fail: args("store", "Market"),
+	},
+	{
+		Tool: "split_shopping_list",
+		OK:   func(s state) map[string]any { return map[string]any{"recipe_ids": s["recipe"]} },
+		Fail: func(s state) map[string]any {
+			return map[string]any{"recipe_ids": s["recipe"], "household_uid": foreignHousehold}
+		},
+	},
+	{
+		Tool: "delete_recipe",
+		OK:   func(s state) map[string]any { return map[string]any{"recipe_id": s["copy"], "confirm": true} },
+		Fail: args("confirm", true),
+	},
+
+	// Pantry and grocery spend
+	{
+		Tool: "add_pantry_item",
+		OK:   args("item", "rice", "quantity", 1, "unit", "kg"),
+		Save: save("pantry_item", "uid", "pantry_item"),
+		Fail: args("item", "rice", "expires_on", "next week"),
+	},
+	{
+		Tool: "list_pantry",
+		OK:   args(),
+		Fail: args("household_uid", foreignHousehold),
+	},
+	{
+		Tool: "update_pantry_item",
+		OK:   func(s state) map[string]any { return map[string]any{"pantry_item_id": s["pantry_item"], "quantity": 2} },
+		Fail: args("quantity", 2),
+	},
+	{
+		Tool: "remove_pantry_item",
+		OK: func(s state) map[string]any {
+			return map[string]any{"pantry_item_id": s["pantry_item"], "confirm": true}
+		},
+		Fail: args("confirm", true),
+	},
+	{
+		Tool: "record_grocery_purchase",
+		OK:   args("item", "rice", "price", 2.49, "store", "Market"),
+		Fail: args("item", "rice"),
+	},
+	{
+		Tool: "grocery_spend_report",
+		OK:   args(),
+		Fail: args("from", "last month"),
+	},
+
+	// Users and households
+	{
+		Tool: "update_user_description",
+		OK:   args("description", "Runs the MCP conformance suite"),
+		Fail: args(),
+	},
+	{
+		Tool: "update_household_description",
+		OK:   args("description", "A household the MCP conformance suite writes to"),
+		Fail: args(),
+	},
+	{
+		Tool: "set_background",
+		OK:   args("key", "conformance", "value", "Written by the MCP conformance suite"),
+		Fail: args("key", "conformance"),
+	},
+	{
+		Tool: "get_background",
+		OK:   args("key", "conformance"),
+		Fail: args(),
+	},
+	{
+		Tool: "get_briefing",
+		OK:   args(),
+		Fail: args("user_uid", foreignUser),
+	},
+	{
+		Tool: "set_away",
+		OK:   args("starts_on", "2099-01-01", "ends_on", "2099-01-02", "note", "Conformance"),
+		Fail: args("ends_on", "soon"),
+	},
+	{
+		Tool: "add_important_date",
+		OK:   args("title", "Conformance day", "date", "2099-01-01", "recurrence", "never"),
+		Fail: args("title", "Conformance day", "date", "tomorrow"),
+	},
+	{
+		Tool:     "apply_template",
+		OK:       args("template", "conformance"),
+		Fail:     args(),
+		External: true,
+	},
+	{
+		Tool:     "list_calendar_events",
+		OK:       args(),
+		Fail:     args("user_uid", foreignUser),
+		External: true,
+	},
+	{
+		Tool:     "create_calendar_event",
+		OK:       args("title", "Conformance event", "start", "2099-01-01"),
+		Fail:     args("title", "Conformance event", "start", "2099-01-01", "user_uid", foreignUser),
+		External: true,
+	},
+}
@@ -0,0 +1,50 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+	"github.com/pbdeuchler/assistant-server/integration_test/testutil"
+	"github.com/pbdeuchler/assistant-server/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodosAPI_UnderFaults(t *testing.T) {
+	t.Parallel()
+	db := testutil.SetupTestDatabase(t)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
+	todo, _ := json.Marshal(map[string]any{"title": "Survive faults", "user_uid": user.UID, "household_uid": household.UID})
+
+	// Transient faults are retried away.
+	flaky, err := dao.New(context.Background(), db.Pool,
+		dao.WithFaults(dao.Faults{TransientRate: 0.3, Jitter: 5 * time.Millisecond}),
+		dao.WithResilience(dao.Resilience{MaxRetries: 10, RetryDelay: time.Millisecond}))
+	require.NoError(t, err)
+	server := httptest.NewServer(service.NewTodos(flaky))
+	defer server.Close()
+	for range 10 {
+		resp, err := http.Post(server.URL+"/", "application/json", bytes.NewReader(todo))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// Lasting ones reach the client as a server error.
+	broken, err := dao.New(context.Background(), db.Pool, dao.WithFaults(dao.Faults{PermanentRate: 1}))
+	require.NoError(t, err)
+	server = httptest.NewServer(service.NewTodos(broken))
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/", "application/json", bytes.NewReader(todo))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
 	"github.com/pbdeuchler/assistant-server/integration_test/testutil"
 	"github.com/pbdeuchler/assistant-server/service"
 	"github.com/stretchr/testify/assert"
@@ -33,12 +34,13 @@ func setupTestServer(t *testing.T, db *testutil.TestDatabase) *httptest.Server {
 }
 
 func TestTodosAPI_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	t.Run("Create Todo", func(t *testing.T) {
 		dueDate := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
@@ -109,7 +111,10 @@ func TestTodosAPI_Integration(t *testing.T) {
 			
 			assert.Equal(t, "Updated Todo Title", updatedTodo.Title)
 			assert.NotNil(t, updatedTodo.MarkedComplete)
-			assert.Equal(t, user.UID, updatedTodo.CompletedBy)
+			assert.Equal(t, &user.UID, updatedTodo.CompletedBy)
+			if assert.NotNil(t, updatedTodo.Completer) {
+				assert.Equal(t, user.Name, updatedTodo.Completer.Name)
+			}
 		})
 		
 		// Test List Todos
@@ -157,12 +162,13 @@ func TestTodosAPI_Integration(t *testing.T) {
 }
 
 func TestNotesAPI_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	t.Run("Create and Manage Notes", func(t *testing.T) {
 		createReq := map[string]any{
@@ -234,12 +240,13 @@ func TestNotesAPI_Integration(t *testing.T) {
 }
 
 func TestRecipesAPI_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	t.Run("Create and Search Recipes", func(t *testing.T) {
 		createReq := map[string]any{
@@ -272,7 +279,8 @@ func TestRecipesAPI_Integration(t *testing.T) {
 		assert.Equal(t, user.UID, recipe.UserUID)
 		assert.Equal(t, household.UID, recipe.HouseholdUID)
 		assert.Equal(t, "italian", *recipe.Genre)
-		assert.Equal(t, 5, *recipe.Rating)
+		assert.Equal(t, 5.0, *recipe.Rating)
+		assert.Equal(t, 1, recipe.RatingCount)
 		assert.ElementsMatch(t, []string{"pasta", "italian", "dinner", "comfort-food"}, recipe.Tags)
 		assert.NotEmpty(t, recipe.ID)
 		
@@ -346,6 +354,7 @@ func TestRecipesAPI_Integration(t *testing.T) {
 }
 
 func TestPreferencesAPI_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
@@ -421,17 +430,18 @@ func TestPreferencesAPI_Integration(t *testing.T) {
 }
 
 func TestBootstrapAPI_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	// Create some test data for the user
-	testutil.CreateTestTodo(t, db, user.UID, household.UID)
-	testutil.CreateTestNote(t, db, user.UID, household.UID)
-	testutil.CreateTestRecipe(t, db, user.UID, household.UID)
+	testutil.Create(t, db.DAO.CreateTodo, fixtures.Todo(func(td *dao.Todo) { td.UserUID, td.HouseholdUID = &user.UID, &household.UID }))
+	testutil.Create(t, db.DAO.CreateNotes, fixtures.Note(func(n *dao.Notes) { n.UserUID, n.HouseholdUID = &user.UID, &household.UID }))
+	testutil.Create(t, db.DAO.CreateRecipes, fixtures.Recipe(func(r *dao.Recipes) { r.UserUID, r.HouseholdUID = &user.UID, &household.UID }))
 	
 	t.Run("Bootstrap User Data", func(t *testing.T) {
 		resp, err := http.Get(server.URL + "/bootstrap/" + user.UID)
@@ -471,6 +481,7 @@ func TestBootstrapAPI_Integration(t *testing.T) {
 }
 
 func TestAPIErrors_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupTestServer(t, db)
 	defer server.Close()
@@ -492,7 +503,7 @@ func TestAPIErrors_Integration(t *testing.T) {
 	t.Run("Invalid Priority", func(t *testing.T) {
 		createReq := map[string]any{
 			"title":    "Test Todo",
-			"priority": 10, // Invalid priority (should be 1-5)
+			"priority": 10, // Invalid priority (should be 1-4)
 		}
 		
 		body, _ := json.Marshal(createReq)
@@ -0,0 +1,61 @@
+package integration_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+	"github.com/pbdeuchler/assistant-server/integration_test/conformance"
+	"github.com/pbdeuchler/assistant-server/integration_test/testutil"
+	"github.com/pbdeuchler/assistant-server/service"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMCPConformance runs the conformance suite against the server at
+// MCP_CONFORMANCE_URL, authenticating with MCP_CONFORMANCE_API_KEY, or,
+// when that isn't set, against an MCP router on the test database.
+func TestMCPConformance(t *testing.T) {
+	t.Parallel()
+	if url := os.Getenv("MCP_CONFORMANCE_URL"); url != "" {
+		conformance.Run(t, conformance.NewClient(url, os.Getenv("MCP_CONFORMANCE_API_KEY")))
+		return
+	}
+
+	db := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User(func(u *dao.Users) { u.HouseholdUID = &household.UID }))
+	const key = "ak_conformance"
+	sum := sha256.Sum256([]byte(key))
+	_, err := db.DAO.CreateAPIKey(ctx, dao.APIKeys{
+		UserUID: user.UID,
+		Name:    "conformance",
+		KeyHash: hex.EncodeToString(sum[:]),
+		Scopes:  []string{service.ScopeMCPWrite},
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(service.NewMCPRouter(db.DAO, db.DAO, db.DAO, db.DAO, db.DAO, db.DAO,
+		service.WithAPIKeys(db.DAO, true),
+		service.WithBackgroundDAO(db.DAO),
+		service.WithTodoTemplates(db.DAO),
+		service.WithPantry(db.DAO),
+		service.WithGroceryPurchases(db.DAO),
+		service.WithAway(db.DAO),
+		service.WithHouseholdMembers(db.DAO),
+		service.WithImportantDates(db.DAO),
+		service.WithMyDay(db.DAO),
+		service.WithProjects(db.DAO),
+		service.WithDataSchemas(db.DAO),
+		service.WithExpansions(db.DAO),
+		service.WithRecipeRefresh(http.DefaultClient),
+	))
+	defer server.Close()
+	conformance.Run(t, conformance.NewClient(server.URL, key))
+}
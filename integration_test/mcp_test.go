@@ -9,6 +9,7 @@ import (
 	"time"
 
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
 	"github.com/pbdeuchler/assistant-server/integration_test/testutil"
 	"github.com/pbdeuchler/assistant-server/service"
 	"github.com/stretchr/testify/assert"
@@ -42,7 +43,12 @@ func sendMCPRequest(t *testing.T, server *httptest.Server, req JSONRPCRequest) J
 	body, err := json.Marshal(req)
 	require.NoError(t, err)
 	
-	resp, err := http.Post(server.URL+"/", "application/json", bytes.NewReader(body))
+	httpReq, err := http.NewRequest("POST", server.URL+"/", bytes.NewReader(body))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("MCP-Protocol-Version", service.LatestProtocolVersion)
+
+	resp, err := http.DefaultClient.Do(httpReq)
 	require.NoError(t, err)
 	defer resp.Body.Close()
 	
@@ -57,6 +63,7 @@ func sendMCPRequest(t *testing.T, server *httptest.Server, req JSONRPCRequest) J
 }
 
 func TestMCP_Initialize_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
@@ -126,6 +133,7 @@ func TestMCP_Initialize_Integration(t *testing.T) {
 }
 
 func TestMCP_ToolsList_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
@@ -172,12 +180,13 @@ func TestMCP_ToolsList_Integration(t *testing.T) {
 }
 
 func TestMCP_TodoTools_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	var todoID string
 	
@@ -215,21 +224,16 @@ func TestMCP_TodoTools_Integration(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, "text", textContent["type"])
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "Todo created successfully")
-		assert.Contains(t, text, "ID:")
-		
-		// Extract the todo ID from the response text
-		parts := []string{}
-		for _, part := range []string{text} {
-			if len(part) > 0 {
-				parts = append(parts, part)
-			}
+		var body struct {
+			Status string   `json:"status"`
+			Todo   dao.Todo `json:"todo"`
 		}
-		// The response should be like "Todo created successfully with ID: <id>"
-		assert.Contains(t, text, "ID:")
-		start := len("Todo created successfully with ID: ")
-		todoID = text[start:]
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", body.Status)
+		assert.Equal(t, result["structuredContent"].(map[string]any)["todo"].(map[string]any)["uid"], body.Todo.UID)
+		
+		todoID = body.Todo.UID
 		assert.NotEmpty(t, todoID)
 	})
 	
@@ -262,9 +266,12 @@ func TestMCP_TodoTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var todos []dao.Todo
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &todos)
+		var body struct {
+			Todos []dao.Todo `json:"todos"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		todos := body.Todos
 		
 		assert.GreaterOrEqual(t, len(todos), 1)
 		
@@ -273,7 +280,7 @@ func TestMCP_TodoTools_Integration(t *testing.T) {
 			if todo.UID == todoID {
 				found = true
 				assert.Equal(t, "MCP Integration Todo", todo.Title)
-				assert.Equal(t, user.UID, todo.UserUID)
+				assert.Equal(t, user.UID, *todo.UserUID)
 				break
 			}
 		}
@@ -309,19 +316,24 @@ func TestMCP_TodoTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "marked as completed")
-		assert.Contains(t, text, todoID)
+		var body struct {
+			Todo dao.Todo `json:"todo"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
+		require.NoError(t, err)
+		assert.Equal(t, todoID, body.Todo.UID)
+		assert.NotNil(t, body.Todo.MarkedComplete)
 	})
 }
 
 func TestMCP_NotesTools_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	var noteID string
 	
@@ -357,13 +369,13 @@ func TestMCP_NotesTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "Note saved successfully")
-		assert.Contains(t, text, "ID:")
+		var body struct {
+			Note dao.Notes `json:"note"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
+		require.NoError(t, err)
 		
-		// Extract the note ID
-		start := len("Note saved successfully with ID: ")
-		noteID = text[start:]
+		noteID = body.Note.ID
 		assert.NotEmpty(t, noteID)
 	})
 	
@@ -395,13 +407,16 @@ func TestMCP_NotesTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var note dao.Notes
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &note)
+		var body struct {
+			Note dao.Notes `json:"note"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		note := body.Note
 		
 		assert.Equal(t, noteID, note.ID)
 		assert.Equal(t, "mcp-test-note", note.Key)
-		assert.Equal(t, user.UID, note.UserUID)
+		assert.Equal(t, user.UID, *note.UserUID)
 		assert.Contains(t, note.Data, "MCP integration")
 		assert.Contains(t, note.Tags, "meeting")
 		assert.Contains(t, note.Tags, "mcp")
@@ -437,9 +452,12 @@ func TestMCP_NotesTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var notes []dao.Notes
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &notes)
+		var body struct {
+			Notes []dao.Notes `json:"notes"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		notes := body.Notes
 		
 		assert.GreaterOrEqual(t, len(notes), 1)
 		
@@ -457,12 +475,13 @@ func TestMCP_NotesTools_Integration(t *testing.T) {
 }
 
 func TestMCP_RecipeTools_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	var recipeID string
 	
@@ -505,13 +524,13 @@ func TestMCP_RecipeTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "Recipe saved successfully")
-		assert.Contains(t, text, "ID:")
+		var body struct {
+			Recipe dao.Recipes `json:"recipe"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
+		require.NoError(t, err)
 		
-		// Extract the recipe ID
-		start := len("Recipe saved successfully with ID: ")
-		recipeID = text[start:]
+		recipeID = body.Recipe.ID
 		assert.NotEmpty(t, recipeID)
 	})
 	
@@ -543,15 +562,18 @@ func TestMCP_RecipeTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var recipe dao.Recipes
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &recipe)
+		var body struct {
+			Recipe dao.Recipes `json:"recipe"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		recipe := body.Recipe
 		
 		assert.Equal(t, recipeID, recipe.ID)
 		assert.Equal(t, "MCP Pasta Recipe", recipe.Title)
-		assert.Equal(t, user.UID, recipe.UserUID)
+		assert.Equal(t, user.UID, *recipe.UserUID)
 		assert.Equal(t, "italian", *recipe.Genre)
-		assert.Equal(t, 4, *recipe.Rating)
+		assert.Equal(t, 4.0, *recipe.Rating)
 		assert.Contains(t, recipe.Tags, "pasta")
 		assert.Contains(t, recipe.Tags, "mcp")
 	})
@@ -588,9 +610,12 @@ func TestMCP_RecipeTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var recipes []dao.Recipes
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &recipes)
+		var body struct {
+			Recipes []dao.Recipes `json:"recipes"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		recipes := body.Recipes
 		
 		assert.GreaterOrEqual(t, len(recipes), 1)
 		
@@ -609,6 +634,7 @@ func TestMCP_RecipeTools_Integration(t *testing.T) {
 }
 
 func TestMCP_PreferenceTools_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
@@ -645,8 +671,15 @@ func TestMCP_PreferenceTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "Preference created: mcp-settings/test-user-123")
+		var created struct {
+			Summary    string          `json:"summary"`
+			Preference dao.Preferences `json:"preference"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &created)
+		require.NoError(t, err)
+		assert.Equal(t, "Preference created", created.Summary)
+		assert.Equal(t, "mcp-settings", created.Preference.Key)
+		assert.Equal(t, "test-user-123", created.Preference.Specifier)
 		
 		// Get preference
 		getReq := JSONRPCRequest{
@@ -677,9 +710,12 @@ func TestMCP_PreferenceTools_Integration(t *testing.T) {
 		textContent, ok = content[0].(map[string]any)
 		require.True(t, ok)
 		
-		var pref dao.Preferences
-		err := json.Unmarshal([]byte(textContent["text"].(string)), &pref)
+		var body struct {
+			Preference dao.Preferences `json:"preference"`
+		}
+		err = json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		pref := body.Preference
 		
 		assert.Equal(t, "mcp-settings", pref.Key)
 		assert.Equal(t, "test-user-123", pref.Specifier)
@@ -689,12 +725,13 @@ func TestMCP_PreferenceTools_Integration(t *testing.T) {
 }
 
 func TestMCP_UserAndHouseholdTools_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
 	
-	user := testutil.CreateTestUser(t, db)
-	household := testutil.CreateTestHousehold(t, db)
+	user := testutil.Create(t, db.DAO.CreateUser, fixtures.User())
+	household := testutil.Create(t, db.DAO.CreateHousehold, fixtures.Household())
 	
 	t.Run("Update User Description via MCP", func(t *testing.T) {
 		req := JSONRPCRequest{
@@ -725,16 +762,12 @@ func TestMCP_UserAndHouseholdTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "User description updated successfully")
-		
-		// Parse the returned user JSON to verify the update
-		start := len("User description updated successfully: ")
-		userJSON := text[start:]
-		
-		var updatedUser dao.Users
-		err := json.Unmarshal([]byte(userJSON), &updatedUser)
+		var body struct {
+			User dao.Users `json:"user"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		updatedUser := body.User
 		
 		assert.Equal(t, user.UID, updatedUser.UID)
 		assert.Contains(t, updatedUser.Description, "Updated via MCP")
@@ -770,16 +803,12 @@ func TestMCP_UserAndHouseholdTools_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "Household description updated successfully")
-		
-		// Parse the returned household JSON to verify the update
-		start := len("Household description updated successfully: ")
-		householdJSON := text[start:]
-		
-		var updatedHousehold dao.Households
-		err := json.Unmarshal([]byte(householdJSON), &updatedHousehold)
+		var body struct {
+			Household dao.Households `json:"household"`
+		}
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
 		require.NoError(t, err)
+		updatedHousehold := body.Household
 		
 		assert.Equal(t, household.UID, updatedHousehold.UID)
 		assert.Contains(t, updatedHousehold.Description, "Updated via MCP")
@@ -788,6 +817,7 @@ func TestMCP_UserAndHouseholdTools_Integration(t *testing.T) {
 }
 
 func TestMCP_ErrorHandling_Integration(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	server := setupMCPServer(t, db)
 	defer server.Close()
@@ -876,7 +906,10 @@ func TestMCP_ErrorHandling_Integration(t *testing.T) {
 		textContent, ok := content[0].(map[string]any)
 		require.True(t, ok)
 		
-		text := textContent["text"].(string)
-		assert.Contains(t, text, "title is required")
+		var body map[string]any
+		err := json.Unmarshal([]byte(textContent["text"].(string)), &body)
+		require.NoError(t, err)
+		assert.Equal(t, "error", body["status"])
+		assert.Equal(t, "title is required", body["error"])
 	})
 }
\ No newline at end of file
@@ -10,6 +10,7 @@ import (
 )
 
 func TestMigrations(t *testing.T) {
+	t.Parallel()
 	db := testutil.SetupTestDatabase(t)
 	ctx := context.Background()
 	
@@ -50,9 +51,9 @@ func TestMigrations(t *testing.T) {
 		
 		// Check expected columns exist
 		expectedColumns := []string{
-			"uid", "title", "description", "data", "priority", 
+			"uid", "title", "description", "data", "priority", "status",
 			"due_date", "recurs_on", "marked_complete", "external_url",
-			"user_uid", "household_uid", "completed_by", "created_at", "updated_at",
+			"user_uid", "household_uid", "completed_by", "created_at", "updated_at", "estimate_minutes", "project_uid", "tags",
 		}
 		
 		for _, col := range expectedColumns {
@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const summarizePrompt = "You condense a household assistant's old notes. " +
+	"Write one plain-text note that keeps every fact still worth remembering " +
+	"(names, numbers, dates, decisions, preferences) and drops chatter and " +
+	"anything superseded by a later note. Do not add anything that is not in the notes."
+
+const suggestTagsPrompt = "You file a household assistant's notes and recipes. " +
+	"Reply with up to 3 short, lowercase, single-word tags for the text, " +
+	"separated by commas, and nothing else."
+
+const extractTodosPrompt = "You find the actionable tasks in a household assistant's note. " +
+	"Reply with only a JSON array, empty if there are none, of objects with " +
+	"\"title\" (a short imperative), optional \"description\" and optional " +
+	"\"due_date\" (YYYY-MM-DD, only when the note gives or implies a date). " +
+	"Today is %s."
+
+const categorizeGroceriesPrompt = "You sort a household's shopping list into supermarket aisles. " +
+	"Reply with only a JSON object mapping each item, exactly as given, to one of " +
+	"these categories: %s."
+
+// Chat talks to an OpenAI-compatible chat completions API, which most
+// hosted and local model servers offer.
+type Chat struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewChat calls baseURL + "/chat/completions", e.g. with baseURL
+// "https://api.openai.com/v1". apiKey may be empty for local servers.
+func NewChat(baseURL, apiKey, model string, client *http.Client) *Chat {
+	return &Chat{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, model: model, client: client}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Complete sends one system and one user message and returns the model's
+// reply, trimmed.
+func (c *Chat) Complete(ctx context.Context, system, prompt string) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": c.model,
+		"messages": []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("llm: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	var out struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if len(out.Choices) == 0 || strings.TrimSpace(out.Choices[0].Message.Content) == "" {
+		return "", errors.New("llm: empty response")
+	}
+	return strings.TrimSpace(out.Choices[0].Message.Content), nil
+}
+
+func (c *Chat) Summarize(ctx context.Context, docs []Document) (string, error) {
+	var prompt strings.Builder
+	for i, d := range docs {
+		if d.Title != "" {
+			fmt.Fprintf(&prompt, "Note %d: %s\n", i+1, d.Title)
+		} else {
+			fmt.Fprintf(&prompt, "Note %d\n", i+1)
+		}
+		prompt.WriteString(d.Text)
+		prompt.WriteString("\n\n")
+	}
+	return c.Complete(ctx, summarizePrompt, prompt.String())
+}
+
+// SuggestTags asks the model for a few tags for text.
+func (c *Chat) SuggestTags(ctx context.Context, text string) ([]string, error) {
+	reply, err := c.Complete(ctx, suggestTagsPrompt, text)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, tag := range strings.Split(reply, ",") {
+		tag = strings.ToLower(strings.Trim(strings.TrimSpace(tag), `."'#`))
+		if tag != "" && !strings.ContainsAny(tag, " \n") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// ExtractTodos asks the model for the todos in text. Proposals without a
+// title are dropped.
+func (c *Chat) ExtractTodos(ctx context.Context, text string, now time.Time) ([]TodoProposal, error) {
+	reply, err := c.Complete(ctx, fmt.Sprintf(extractTodosPrompt, now.Format("Monday 2006-01-02")), text)
+	if err != nil {
+		return nil, err
+	}
+	var proposed []TodoProposal
+	if err := json.Unmarshal([]byte(trimCodeFence(reply)), &proposed); err != nil {
+		return nil, fmt.Errorf("llm: todos are not a JSON array: %w", err)
+	}
+	out := []TodoProposal{}
+	for _, p := range proposed {
+		p.Title = strings.TrimSpace(p.Title)
+		if p.Title == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, p.DueDate); err != nil {
+			p.DueDate = ""
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// CategorizeGroceries asks the model which of categories each of items
+// belongs in. Items given a category not in categories are left out.
+func (c *Chat) CategorizeGroceries(ctx context.Context, items, categories []string) (map[string]string, error) {
+	reply, err := c.Complete(ctx, fmt.Sprintf(categorizeGroceriesPrompt, strings.Join(categories, ", ")), strings.Join(items, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	var suggested map[string]string
+	if err := json.Unmarshal([]byte(trimCodeFence(reply)), &suggested); err != nil {
+		return nil, fmt.Errorf("llm: categories are not a JSON object: %w", err)
+	}
+	out := map[string]string{}
+	for _, item := range items {
+		if category := strings.ToLower(strings.TrimSpace(suggested[item])); slices.Contains(categories, category) {
+			out[item] = category
+		}
+	}
+	return out, nil
+}
+
+// trimCodeFence strips the Markdown code fence models like to wrap JSON in.
+func trimCodeFence(reply string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reply, "```json"), "```"), "```"))
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatSummarize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var in struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, "small-model", in.Model)
+		require.Len(t, in.Messages, 2)
+		assert.Equal(t, "Note 1: wifi\nPassword is hunter2\n\nNote 2\nBins go out on Tuesday\n\n", in.Messages[1].Content)
+		_, _ = io.WriteString(w, `{"choices": [{"message": {"role": "assistant", "content": " Wi-Fi password hunter2; bins out Tuesdays.\n"}}]}`)
+	}))
+	defer srv.Close()
+
+	c := NewChat(srv.URL+"/v1/", "secret", "small-model", srv.Client())
+	out, err := c.Summarize(t.Context(), []Document{{Title: "wifi", Text: "Password is hunter2"}, {Text: "Bins go out on Tuesday"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Wi-Fi password hunter2; bins out Tuesdays.", out)
+}
+
+func TestChatSummarizeErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			_, _ = io.WriteString(w, `{"choices": []}`)
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error": "slow down"}`)
+	}))
+	defer srv.Close()
+
+	_, err := NewChat(srv.URL, "key", "m", srv.Client()).Summarize(t.Context(), []Document{{Text: "x"}})
+	assert.ErrorContains(t, err, "429")
+	_, err = NewChat(srv.URL, "", "m", srv.Client()).Summarize(t.Context(), []Document{{Text: "x"}})
+	assert.ErrorContains(t, err, "empty response")
+}
+
+func TestChatSuggestTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"choices": [{"message": {"role": "assistant", "content": "Health, #dentist., not a tag, kids"}}]}`)
+	}))
+	defer srv.Close()
+
+	tags, err := NewChat(srv.URL, "", "m", srv.Client()).SuggestTags(t.Context(), "Dentist for Leo on Friday")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"health", "dentist", "kids"}, tags)
+}
+
+func TestChatExtractTodos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Contains(t, in.Messages[0].Content, "Today is Monday 2025-08-18.")
+		reply := "```json\n[{\"title\": \"Book dentist\", \"due_date\": \"2025-08-22\"}, {\"title\": \" \"}, {\"title\": \"Renew passport\", \"due_date\": \"soon\"}]\n```"
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []any{map[string]any{"message": chatMessage{Role: "assistant", Content: reply}}}})
+	}))
+	defer srv.Close()
+
+	todos, err := NewChat(srv.URL, "", "m", srv.Client()).ExtractTodos(t.Context(), "Dentist by Friday, passport", time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []TodoProposal{{Title: "Book dentist", DueDate: "2025-08-22"}, {Title: "Renew passport"}}, todos)
+}
+
+func TestChatCategorizeGroceries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Contains(t, in.Messages[0].Content, "categories: produce, dairy, other.")
+		assert.Equal(t, "tahini\nquark\nsomething", in.Messages[1].Content)
+		reply := "```json\n{\"tahini\": \"Pantry\", \"quark\": \"dairy\", \"something\": \"aisle 9\"}\n```"
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []any{map[string]any{"message": chatMessage{Role: "assistant", Content: reply}}}})
+	}))
+	defer srv.Close()
+
+	categories, err := NewChat(srv.URL, "", "m", srv.Client()).CategorizeGroceries(t.Context(),
+		[]string{"tahini", "quark", "something"}, []string{"produce", "dairy", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"quark": "dairy"}, categories)
+}
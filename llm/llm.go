@@ -0,0 +1,35 @@
+// Package llm asks a language model for small text tasks: condensing old
+// notes into a digest, suggesting tags, picking todos out of a note and
+// sorting groceries into aisles.
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+// Document is one piece of text to summarize. Title may be empty.
+type Document struct {
+	Title string
+	Text  string
+}
+
+// Summarizer condenses documents into one shorter text that keeps the facts
+// worth remembering.
+type Summarizer interface {
+	Summarize(ctx context.Context, docs []Document) (string, error)
+}
+
+// TodoProposal is a todo the model found in a note. DueDate is YYYY-MM-DD,
+// or empty when the note gives no date.
+type TodoProposal struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+// TodoExtractor proposes the actionable todos in a note. Relative dates
+// ("next Friday") are resolved against now.
+type TodoExtractor interface {
+	ExtractTodos(ctx context.Context, text string, now time.Time) ([]TodoProposal, error)
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -12,5 +14,28 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	cfg := cmd.LoadConfig()
-	_ = cmd.Serve(ctx, cfg)
+	var err error
+	switch arg(1) {
+	case "seed":
+		err = cmd.Seed(ctx, cfg, arg(2), os.Stdout)
+	case "backup":
+		err = cmd.Backup(ctx, cfg, arg(2))
+	case "restore":
+		err = cmd.Restore(ctx, cfg, arg(2))
+	default:
+		_ = cmd.Serve(ctx, cfg)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", arg(1), err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func arg(i int) string {
+	if i < len(os.Args) {
+		return os.Args[i]
+	}
+	return ""
 }
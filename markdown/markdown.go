@@ -0,0 +1,397 @@
+// Package markdown renders the markdown in note data as HTML that is safe to
+// embed in email and web pages as is.
+//
+// Only common markdown is understood: ATX headings, paragraphs, bulleted,
+// numbered and task lists (nested by indenting), block quotes, fenced code
+// blocks and horizontal rules, and inline code, bold, italics,
+// strikethrough, links and bare URLs. Line breaks within a paragraph are
+// kept, as notes are usually written line by line.
+//
+// Nothing in the source is passed through: all text is escaped and the only
+// tags in the output are the ones written here, so raw HTML shows as text.
+// Links are kept only when they are http, https or mailto; images are shown
+// as links to the image rather than loaded.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	headingRe  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	ruleRe     = regexp.MustCompile(`^ {0,3}(?:-[ \t]*){3,}$|^ {0,3}(?:\*[ \t]*){3,}$|^ {0,3}(?:_[ \t]*){3,}$`)
+	listItemRe = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])(?:[ \t]+(.*))?$`)
+	fenceRe    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`]*)$")
+	quoteRe    = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	langRe     = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+)
+
+// Render converts markdown to an HTML fragment.
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"))
+	return b.String()
+}
+
+// startsBlock reports whether line begins something other than a paragraph.
+func startsBlock(line string) bool {
+	return headingRe.MatchString(line) || ruleRe.MatchString(line) || fenceRe.MatchString(line) ||
+		quoteRe.MatchString(line) || listItemRe.MatchString(line)
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fenceRe.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			level := string('0' + rune(len(m[1])))
+			b.WriteString("<h" + level + ">")
+			renderInline(b, m[2])
+			b.WriteString("</h" + level + ">\n")
+			i++
+		case ruleRe.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case quoteRe.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quoteRe.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteRe.FindStringSubmatch(lines[i])[1])
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+		case listItemRe.MatchString(line):
+			i = renderList(b, lines, i)
+		default:
+			start := i
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines[i]); i++ {
+			}
+			b.WriteString("<p>")
+			renderLines(b, lines[start:i])
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// renderLines writes the lines of a paragraph, keeping the breaks between
+// them.
+func renderLines(b *strings.Builder, lines []string) {
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("<br>\n")
+		}
+		renderInline(b, strings.TrimSpace(line))
+	}
+}
+
+// renderFence writes the fenced code block opening at lines[i] and returns
+// the index of the line after it. An unclosed fence runs to the end.
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceRe.FindStringSubmatch(lines[i])
+	fence, lang := m[1], strings.TrimSpace(m[2])
+	if f := strings.Fields(lang); len(f) > 0 {
+		lang = f[0]
+	}
+	b.WriteString("<pre><code")
+	if langRe.MatchString(lang) {
+		b.WriteString(` class="language-` + lang + `"`)
+	}
+	b.WriteString(">")
+	for i++; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+			i++
+			break
+		}
+		b.WriteString(html.EscapeString(lines[i]) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList writes the list starting at lines[i] and returns the index of
+// the line after it. Lines indented past an item's marker belong to the
+// item, so a nested list is one indented under its parent item.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	m := listItemRe.FindStringSubmatch(lines[i])
+	indent, ordered := len(m[1]), !strings.ContainsAny(m[2], "-*+")
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if start, _ := strconv.Atoi(strings.TrimRight(m[2], ".)")); start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+	for i < len(lines) {
+		m := listItemRe.FindStringSubmatch(lines[i])
+		if m == nil || ruleRe.MatchString(lines[i]) || len(m[1]) != indent || ordered == strings.ContainsAny(m[2], "-*+") {
+			break
+		}
+		body := []string{m[3]}
+		content := indent + len(m[2]) + 1
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line only continues the item when more of it follows.
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= content {
+					body = append(body, "")
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) > indent {
+				body = append(body, strings.TrimPrefix(line, strings.Repeat(" ", min(leadingSpaces(line), content))))
+				continue
+			}
+			if startsBlock(line) {
+				break
+			}
+			// A lazy continuation of the item's text.
+			body = append(body, line)
+		}
+		renderItem(b, body)
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) && listItemRe.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderItem writes one list item: its text, then whatever blocks are
+// nested under it.
+func renderItem(b *strings.Builder, body []string) {
+	text := 1
+	for ; text < len(body) && strings.TrimSpace(body[text]) != "" && !startsBlock(body[text]); text++ {
+	}
+	first := body[0]
+	b.WriteString("<li>")
+	switch {
+	case strings.HasPrefix(first, "[ ] "):
+		b.WriteString(`<input type="checkbox" disabled> `)
+		first = first[4:]
+	case strings.HasPrefix(first, "[x] "), strings.HasPrefix(first, "[X] "):
+		b.WriteString(`<input type="checkbox" checked disabled> `)
+		first = first[4:]
+	}
+	renderLines(b, append([]string{first}, body[1:text]...))
+	if text < len(body) {
+		b.WriteString("\n")
+		renderBlocks(b, body[text:])
+	}
+	b.WriteString("</li>\n")
+}
+
+func leadingSpaces(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " "))
+}
+
+// renderInline writes the inline markdown in s.
+func renderInline(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			ticks := s[i : i+n]
+			end := strings.Index(s[i+n:], ticks)
+			if end < 0 {
+				b.WriteString(ticks)
+				i += n
+				continue
+			}
+			b.WriteString("<code>" + html.EscapeString(strings.TrimSpace(s[i+n:i+n+end])) + "</code>")
+			i += n + end + n
+		case c == '*' || c == '_' || c == '~':
+			n, ok := renderEmphasis(b, s, i)
+			if !ok {
+				b.WriteByte(c)
+				n = 1
+			}
+			i += n
+		case c == '[' || (c == '!' && strings.HasPrefix(s[i+1:], "[")):
+			n, ok := renderLink(b, s, i)
+			if !ok {
+				b.WriteByte(c)
+				n = 1
+			}
+			i += n
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				if href := s[i+1 : i+end]; safeURL(href) && !strings.ContainsAny(href, " <") {
+					writeLink(b, href, html.EscapeString(href))
+					i += end + 1
+					continue
+				}
+			}
+			b.WriteString("&lt;")
+			i++
+		case (c == 'h' || c == 'H') && (i == 0 || !isWordByte(s[i-1])) && bareURLAt(s[i:]) > 0:
+			n := bareURLAt(s[i:])
+			writeLink(b, s[i:i+n], html.EscapeString(s[i:i+n]))
+			i += n
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			b.WriteString(html.EscapeString(s[i : i+size]))
+			i += size
+		}
+	}
+}
+
+// renderEmphasis writes the bold, italic or struck-through span opening at
+// s[i], returning how much of s it used. ok is false when the delimiter
+// isn't closed, or is an underscore inside a word such as snake_case.
+func renderEmphasis(b *strings.Builder, s string, i int) (int, bool) {
+	c := s[i]
+	n := 1
+	if i+1 < len(s) && s[i+1] == c {
+		n = 2
+	}
+	if c == '~' && n == 1 {
+		return 0, false
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0, false
+	}
+	delim := s[i : i+n]
+	rest := s[i+n:]
+	if rest == "" || rest[0] == ' ' {
+		return 0, false
+	}
+	for from := 0; ; {
+		end := strings.Index(rest[from:], delim)
+		if end < 0 {
+			return 0, false
+		}
+		end += from
+		after := end + n
+		// A single * or _ mustn't close on half of a double one, nor _ inside a word.
+		if end > 0 && rest[end-1] != ' ' && (n == 2 || after >= len(rest) || rest[after] != c) &&
+			(c != '_' || after >= len(rest) || !isWordByte(rest[after])) {
+			tag := map[string]string{"*": "em", "_": "em", "**": "strong", "__": "strong", "~~": "del"}[delim]
+			b.WriteString("<" + tag + ">")
+			renderInline(b, rest[:end])
+			b.WriteString("</" + tag + ">")
+			return n + after, true
+		}
+		from = end + n
+	}
+}
+
+// renderLink writes the [text](url) link, or ![alt](url) image, opening at
+// s[i], returning how much of s it used. A link to an unsafe URL is written
+// as its text alone.
+func renderLink(b *strings.Builder, s string, i int) (int, bool) {
+	start := i
+	if s[i] == '!' {
+		i++
+	}
+	depth, close := 0, -1
+	for j := i; j < len(s) && close < 0; j++ {
+		switch s[j] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				close = j
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return 0, false
+	}
+	// The URL may have balanced parentheses of its own.
+	end := -1
+	for j, depth := close+2, 1; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				end = j - close - 2
+			}
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+	target := strings.TrimSpace(s[close+2 : close+2+end])
+	if f := strings.Fields(target); len(f) > 0 {
+		target = strings.Trim(f[0], "<>")
+	}
+	var text strings.Builder
+	renderInline(&text, s[i+1:close])
+	if text.Len() == 0 {
+		text.WriteString(html.EscapeString(target))
+	}
+	if safeURL(target) {
+		writeLink(b, target, text.String())
+	} else {
+		b.WriteString(text.String())
+	}
+	return close + 2 + end + 1 - start, true
+}
+
+func writeLink(b *strings.Builder, href, text string) {
+	b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">` + text + "</a>")
+}
+
+// safeURL reports whether a link may point at u.
+func safeURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return parsed.Opaque != ""
+	default:
+		return false
+	}
+}
+
+// bareURLAt is the length of the http or https URL s starts with, or 0.
+// Punctuation ending a sentence isn't taken as part of the URL.
+func bareURLAt(s string) int {
+	lower := strings.ToLower(s[:min(len(s), 8)])
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return 0
+	}
+	n := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '<' })
+	if n < 0 {
+		n = len(s)
+	}
+	n = len(strings.TrimRight(s[:n], `.,:;!?'")*_~`))
+	if !safeURL(s[:n]) {
+		return 0
+	}
+	return n
+}
+
+func isPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("`^<>|~=+$", c) >= 0
+}
+
+func isWordByte(c byte) bool {
+	return c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
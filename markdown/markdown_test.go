@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	for src, want := range map[string]string{
+		"# Trip\n\nPack **boots** and *socks*.\nCall Sam.": "<h1>Trip</h1>\n<p>Pack <strong>boots</strong> and <em>socks</em>.<br>\nCall Sam.</p>\n",
+		"- [ ] passports\n- [x] tickets\n  - train\n- snacks": "<ul>\n<li><input type=\"checkbox\" disabled> passports</li>\n" +
+			"<li><input type=\"checkbox\" checked disabled> tickets\n<ul>\n<li>train</li>\n</ul>\n</li>\n<li>snacks</li>\n</ul>\n",
+		"3. three\n\n4. four":                   "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n",
+		"> quoted\n\n---\n\nend":                "<blockquote>\n<p>quoted</p>\n</blockquote>\n<hr>\n<p>end</p>\n",
+		"```go\nfmt.Println(\"<b>\")\n```":      "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;b&gt;&#34;)\n</code></pre>\n",
+		"`a <b>` and \\*not em\\* and ~~gone~~": "<p><code>a &lt;b&gt;</code> and *not em* and <del>gone</del></p>\n",
+		"my_var_name and __init__":              "<p>my_var_name and <strong>init</strong></p>\n",
+		"see https://example.com/a. or <mailto:sam@example.com>": `<p>see <a href="https://example.com/a" rel="nofollow noopener noreferrer">https://example.com/a</a>.` +
+			` or <a href="mailto:sam@example.com" rel="nofollow noopener noreferrer">mailto:sam@example.com</a></p>` + "\n",
+	} {
+		assert.Equal(t, want, Render(src), src)
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	for src, want := range map[string]string{
+		"<script>alert(1)</script>":         "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		`<img src=x onerror="alert(1)">`:    "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n",
+		"[click](javascript:alert(1))":      "<p>click</p>\n",
+		"[click](JavaScript:alert(1))":      "<p>click</p>\n",
+		"[<b>x</b>](data:text/html,hi)":     "<p>&lt;b&gt;x&lt;/b&gt;</p>\n",
+		`[x](https://a.com/"onclick="bad)`:  `<p><a href="https://a.com/&#34;onclick=&#34;bad" rel="nofollow noopener noreferrer">x</a></p>` + "\n",
+		"![cat](https://example.com/c.png)": `<p><a href="https://example.com/c.png" rel="nofollow noopener noreferrer">cat</a></p>` + "\n",
+		"```\"><script>\nx\n```":            "<pre><code>x\n</code></pre>\n",
+	} {
+		assert.Equal(t, want, Render(src), src)
+	}
+}
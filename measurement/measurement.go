@@ -0,0 +1,336 @@
+// Package measurement parses and converts cooking quantities such as
+// "1 1/2 cups" or "250 g", for scaling recipes and adding up grocery lists.
+//
+// Volumes and masses convert freely within their dimension. Converting
+// between volume and mass needs the ingredient's density, which is known for
+// common pantry staples (see Density).
+package measurement
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type Dimension string
+
+const (
+	Volume Dimension = "volume"
+	Mass   Dimension = "mass"
+	Count  Dimension = "count"
+)
+
+type System string
+
+const (
+	Metric System = "metric"
+	// Imperial covers the US customary units American recipes use: cups,
+	// tablespoons, fluid ounces, ounces and pounds.
+	Imperial System = "imperial"
+)
+
+// Unit is a unit of measure. PerBase is how many millilitres (volume),
+// grams (mass) or items (count) one of the unit holds.
+type Unit struct {
+	Name      string
+	Dimension Dimension
+	System    System
+	PerBase   float64
+}
+
+var (
+	Millilitre = Unit{"ml", Volume, Metric, 1}
+	Litre      = Unit{"l", Volume, Metric, 1000}
+	Teaspoon   = Unit{"tsp", Volume, Imperial, 4.92892}
+	Tablespoon = Unit{"tbsp", Volume, Imperial, 14.7868}
+	FluidOunce = Unit{"fl oz", Volume, Imperial, 29.5735}
+	Cup        = Unit{"cup", Volume, Imperial, 236.588}
+	Pint       = Unit{"pint", Volume, Imperial, 473.176}
+	Quart      = Unit{"quart", Volume, Imperial, 946.353}
+	Gallon     = Unit{"gallon", Volume, Imperial, 3785.41}
+	Gram       = Unit{"g", Mass, Metric, 1}
+	Kilogram   = Unit{"kg", Mass, Metric, 1000}
+	Ounce      = Unit{"oz", Mass, Imperial, 28.3495}
+	Pound      = Unit{"lb", Mass, Imperial, 453.592}
+	Each       = Unit{"each", Count, "", 1}
+	Dozen      = Unit{"dozen", Count, "", 12}
+)
+
+var unitAliases = map[string]Unit{}
+
+func init() {
+	for unit, aliases := range map[Unit][]string{
+		Millilitre: {"ml", "milliliter", "millilitre", "milliliters", "millilitres", "mls"},
+		Litre:      {"l", "liter", "litre", "liters", "litres"},
+		Teaspoon:   {"tsp", "teaspoon", "teaspoons", "tsps", "t"},
+		Tablespoon: {"tbsp", "tablespoon", "tablespoons", "tbsps", "tbs", "tbl", "T"},
+		FluidOunce: {"fl oz", "floz", "fl. oz", "fluid ounce", "fluid ounces"},
+		Cup:        {"cup", "cups", "c"},
+		Pint:       {"pint", "pints", "pt", "pts"},
+		Quart:      {"quart", "quarts", "qt", "qts"},
+		Gallon:     {"gallon", "gallons", "gal", "gals"},
+		Gram:       {"g", "gram", "grams", "gs", "gr"},
+		Kilogram:   {"kg", "kilogram", "kilograms", "kilo", "kilos", "kgs"},
+		Ounce:      {"oz", "ounce", "ounces", "ozs"},
+		Pound:      {"lb", "pound", "pounds", "lbs"},
+		Each:       {"", "each", "ea", "piece", "pieces", "pc", "pcs", "item", "items", "whole"},
+		Dozen:      {"dozen", "doz"},
+	} {
+		for _, alias := range aliases {
+			unitAliases[alias] = unit
+		}
+	}
+}
+
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// ParseUnit looks up a unit by name, symbol or plural. Matching ignores case
+// except for "t" (teaspoon) and "T" (tablespoon).
+func ParseUnit(s string) (Unit, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "."))
+	if u, ok := unitAliases[s]; ok {
+		return u, nil
+	}
+	if u, ok := unitAliases[strings.ToLower(s)]; ok {
+		return u, nil
+	}
+	return Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+}
+
+// Quantity is an amount of a unit.
+type Quantity struct {
+	Amount float64
+	Unit   Unit
+}
+
+var unicodeFractions = map[rune]float64{
+	'¼': 0.25, '½': 0.5, '¾': 0.75, '⅓': 1.0 / 3, '⅔': 2.0 / 3,
+	'⅛': 0.125, '⅜': 0.375, '⅝': 0.625, '⅞': 0.875,
+}
+
+// Parse reads a quantity such as "2 cups", "1 1/2 tbsp", "½ tsp", "250g" or
+// "3". A bare number is a count.
+func Parse(s string) (Quantity, error) {
+	amount, rest, err := parseAmount(s)
+	if err != nil {
+		return Quantity{}, err
+	}
+	unit, err := ParseUnit(strings.Join(rest, " "))
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Amount: amount, Unit: unit}, nil
+}
+
+// Ingredient is one line of a grocery list. Quantity is nil when the line
+// gives no amount, as in "salt".
+type Ingredient struct {
+	Name     string
+	Quantity *Quantity
+}
+
+// ParseIngredient splits a grocery list line such as "1 1/2 cups rolled
+// oats", "250g butter" or "3 eggs" into its amount and name. An amount
+// without a known unit counts the ingredient.
+func ParseIngredient(line string) Ingredient {
+	line = strings.TrimSpace(line)
+	amount, rest, err := parseAmount(line)
+	if err != nil {
+		return Ingredient{Name: line}
+	}
+	q := Quantity{Amount: amount, Unit: Each}
+	// Two words first, for "fl oz".
+	for n := min(2, len(rest)); n > 0; n-- {
+		if u, err := ParseUnit(strings.Join(rest[:n], " ")); err == nil {
+			q.Unit, rest = u, rest[n:]
+			break
+		}
+	}
+	if len(rest) > 0 && rest[0] == "of" {
+		rest = rest[1:]
+	}
+	return Ingredient{Name: strings.Join(rest, " "), Quantity: &q}
+}
+
+// parseAmount reads the leading amount of s, adding up whole numbers and
+// fractions ("1 1/2", "1½"), and returns the words after it.
+func parseAmount(s string) (float64, []string, error) {
+	// Split unicode fractions from whatever they are glued to: "1½cups".
+	var b strings.Builder
+	for _, r := range s {
+		if f, ok := unicodeFractions[r]; ok {
+			fmt.Fprintf(&b, " %g ", f)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	fields := strings.Fields(b.String())
+
+	var amount float64
+	found := false
+	unitStart := len(fields)
+	for i, field := range fields {
+		// "250g" has its unit glued on.
+		num, rest := splitNumber(field)
+		if num == "" {
+			unitStart = i
+			break
+		}
+		v, err := parseNumber(num)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid amount %q: %w", field, err)
+		}
+		amount += v
+		found = true
+		if rest != "" {
+			fields[i] = rest
+			unitStart = i
+			break
+		}
+	}
+	if !found {
+		return 0, nil, fmt.Errorf("no amount in %q", strings.TrimSpace(s))
+	}
+	return amount, fields[unitStart:], nil
+}
+
+// splitNumber splits a leading number, including fractions like "1/2", from
+// the rest of s.
+func splitNumber(s string) (num, rest string) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '/'
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+func parseNumber(s string) (float64, error) {
+	if n, d, ok := strings.Cut(s, "/"); ok {
+		num, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, err
+		}
+		den, err := strconv.ParseFloat(d, 64)
+		if err != nil {
+			return 0, err
+		}
+		if den == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return num / den, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// densities are grams per millilitre of common ingredients as measured in a
+// kitchen: spooned and levelled for dry goods, packed for brown sugar.
+var densities = map[string]float64{
+	"water":          1.0,
+	"milk":           1.03,
+	"cream":          1.01,
+	"flour":          0.53,
+	"sugar":          0.85,
+	"brown sugar":    0.93,
+	"powdered sugar": 0.51,
+	"butter":         0.96,
+	"rice":           0.78,
+	"oats":           0.38,
+	"honey":          1.42,
+	"maple syrup":    1.32,
+	"oil":            0.92,
+	"salt":           1.2,
+	"cocoa":          0.42,
+	"yogurt":         1.03,
+}
+
+// Density returns grams per millilitre for ingredient. Qualified names match
+// their base ingredient: "all-purpose flour" uses flour and "olive oil" uses
+// oil. The longest matching name wins, so "brown sugar" is not plain sugar.
+func Density(ingredient string) (float64, bool) {
+	name := strings.ToLower(strings.TrimSpace(ingredient))
+	best, density := "", 0.0
+	for known, d := range densities {
+		if (name == known || strings.HasSuffix(name, " "+known) || strings.HasSuffix(name, "-"+known)) && len(known) > len(best) {
+			best, density = known, d
+		}
+	}
+	return density, best != ""
+}
+
+var ErrIncompatible = errors.New("incompatible units")
+
+// Convert expresses q in unit to. Converting between volume and mass uses
+// the density of ingredient and fails if it is not known.
+func (q Quantity) Convert(to Unit, ingredient string) (Quantity, error) {
+	base := q.Amount * q.Unit.PerBase
+	if q.Unit.Dimension != to.Dimension {
+		density, ok := Density(ingredient)
+		switch {
+		case !ok || q.Unit.Dimension == Count || to.Dimension == Count:
+			return Quantity{}, fmt.Errorf("%w: cannot convert %s to %s for %q", ErrIncompatible, q.Unit.Dimension, to.Dimension, ingredient)
+		case q.Unit.Dimension == Volume:
+			base *= density
+		default:
+			base /= density
+		}
+	}
+	return Quantity{Amount: base / to.PerBase, Unit: to}, nil
+}
+
+// ToSystem converts q to the most readable unit of the same dimension in
+// sys, e.g. 750 ml becomes 3.17 cups and 1.5 cups becomes 354.88 ml. Counts are
+// returned unchanged.
+func (q Quantity) ToSystem(sys System) Quantity {
+	if q.Unit.Dimension == Count {
+		return q
+	}
+	base := q.Amount * q.Unit.PerBase
+	var ladder []Unit
+	switch {
+	case q.Unit.Dimension == Volume && sys == Metric:
+		ladder = []Unit{Millilitre, Litre}
+	case q.Unit.Dimension == Volume:
+		ladder = []Unit{Teaspoon, Tablespoon, Cup, Quart, Gallon}
+	case sys == Metric:
+		ladder = []Unit{Gram, Kilogram}
+	default:
+		ladder = []Unit{Ounce, Pound}
+	}
+	// The largest unit that still gives at least one whole.
+	unit := ladder[0]
+	for _, u := range ladder[1:] {
+		if base >= u.PerBase {
+			unit = u
+		}
+	}
+	return Quantity{Amount: base / unit.PerBase, Unit: unit}
+}
+
+// Scale multiplies the amount, e.g. to double a recipe.
+func (q Quantity) Scale(factor float64) Quantity {
+	return Quantity{Amount: q.Amount * factor, Unit: q.Unit}
+}
+
+// Add returns q plus other, in q's unit, converting other first; adding
+// "1 cup" of flour to "200 g" of flour works.
+func (q Quantity) Add(other Quantity, ingredient string) (Quantity, error) {
+	converted, err := other.Convert(q.Unit, ingredient)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Amount: q.Amount + converted.Amount, Unit: q.Unit}, nil
+}
+
+// String formats q with at most two decimals, e.g. "1.5 cup" or "3".
+func (q Quantity) String() string {
+	amount := strconv.FormatFloat(math.Round(q.Amount*100)/100, 'f', -1, 64)
+	if q.Unit == Each {
+		return amount
+	}
+	return amount + " " + q.Unit.Name
+}
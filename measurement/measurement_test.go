@@ -0,0 +1,115 @@
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		amount float64
+		unit   Unit
+	}{
+		{"2 cups", 2, Cup},
+		{"1 1/2 tbsp", 1.5, Tablespoon},
+		{"½ tsp", 0.5, Teaspoon},
+		{"1½ cups", 1.5, Cup},
+		{"250g", 250, Gram},
+		{"1.5 KG", 1.5, Kilogram},
+		{"8 fl oz", 8, FluidOunce},
+		{"2 T", 2, Tablespoon},
+		{"2 t", 2, Teaspoon},
+		{"3", 3, Each},
+		{"1 dozen", 1, Dozen},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.InDelta(t, tt.amount, q.Amount, 1e-9, tt.in)
+		assert.Equal(t, tt.unit, q.Unit, tt.in)
+	}
+
+	for _, in := range []string{"", "cups", "2 handfuls", "1/0 cup"} {
+		_, err := Parse(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	q, err := Quantity{2, Cup}.Convert(Millilitre, "")
+	assert.NoError(t, err)
+	assert.InDelta(t, 473.18, q.Amount, 0.01)
+
+	q, err = Quantity{1, Pound}.Convert(Gram, "")
+	assert.NoError(t, err)
+	assert.InDelta(t, 453.59, q.Amount, 0.01)
+
+	// A cup of flour is much lighter than a cup of water.
+	q, err = Quantity{1, Cup}.Convert(Gram, "all-purpose flour")
+	assert.NoError(t, err)
+	assert.InDelta(t, 125.4, q.Amount, 0.1)
+
+	q, err = Quantity{100, Gram}.Convert(Cup, "brown sugar")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.45, q.Amount, 0.01)
+
+	_, err = Quantity{1, Cup}.Convert(Gram, "kale")
+	assert.ErrorIs(t, err, ErrIncompatible)
+	_, err = Quantity{3, Each}.Convert(Gram, "water")
+	assert.ErrorIs(t, err, ErrIncompatible)
+}
+
+func TestDensity(t *testing.T) {
+	d, ok := Density("Brown Sugar")
+	assert.True(t, ok)
+	assert.Equal(t, 0.93, d)
+	d, ok = Density("olive oil")
+	assert.True(t, ok)
+	assert.Equal(t, 0.92, d)
+	_, ok = Density("sugar snap peas")
+	assert.False(t, ok)
+}
+
+func TestToSystem(t *testing.T) {
+	assert.Equal(t, "3.17 cup", Quantity{750, Millilitre}.ToSystem(Imperial).String())
+	assert.Equal(t, "354.88 ml", Quantity{1.5, Cup}.ToSystem(Metric).String())
+	assert.Equal(t, "1.1 kg", Quantity{1100, Gram}.ToSystem(Metric).String())
+	assert.Equal(t, "2.2 lb", Quantity{1, Kilogram}.ToSystem(Imperial).String())
+	assert.Equal(t, "2 tsp", Quantity{2, Teaspoon}.ToSystem(Imperial).String())
+	assert.Equal(t, "4", Quantity{4, Each}.ToSystem(Metric).String())
+}
+
+func TestScaleAndAdd(t *testing.T) {
+	assert.Equal(t, Quantity{3, Cup}, Quantity{1.5, Cup}.Scale(2))
+
+	sum, err := Quantity{200, Gram}.Add(Quantity{1, Cup}, "flour")
+	assert.NoError(t, err)
+	assert.Equal(t, Gram, sum.Unit)
+	assert.InDelta(t, 325.4, sum.Amount, 0.1)
+
+	_, err = Quantity{2, Each}.Add(Quantity{1, Cup}, "milk")
+	assert.ErrorIs(t, err, ErrIncompatible)
+}
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		in   string
+		name string
+		want *Quantity
+	}{
+		{"1 1/2 cups rolled oats", "rolled oats", &Quantity{1.5, Cup}},
+		{"250g butter", "butter", &Quantity{250, Gram}},
+		{"8 fl oz milk", "milk", &Quantity{8, FluidOunce}},
+		{"2 cups of flour", "flour", &Quantity{2, Cup}},
+		{"3 eggs", "eggs", &Quantity{3, Each}},
+		{"1 dozen eggs", "eggs", &Quantity{1, Dozen}},
+		{"parmesan cheese", "parmesan cheese", nil},
+	}
+	for _, tt := range tests {
+		got := ParseIngredient(tt.in)
+		assert.Equal(t, tt.name, got.Name, tt.in)
+		assert.Equal(t, tt.want, got.Quantity, tt.in)
+	}
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS api_keys (
+	uid           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_uid      uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	name          text NOT NULL,
+	key_hash      text NOT NULL UNIQUE,
+	scopes        text[] NOT NULL DEFAULT '{}',
+	last_used_at  timestamptz,
+	revoked_at    timestamptz,
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_uid ON api_keys (user_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_user_uid;
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
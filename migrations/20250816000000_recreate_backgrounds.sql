@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS backgrounds (
+	key         varchar(128) PRIMARY KEY,
+	value       text NOT NULL,
+	created_at  timestamptz NOT NULL DEFAULT now(),
+	updated_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_backgrounds_created_at ON backgrounds (created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backgrounds_created_at;
+DROP TABLE IF EXISTS backgrounds;
+-- +goose StatementEnd
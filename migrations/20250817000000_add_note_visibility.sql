@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS visibility text NOT NULL DEFAULT 'household'
+	CHECK (visibility IN ('private', 'household', 'shared-link'));
+
+CREATE INDEX IF NOT EXISTS idx_notes_visibility ON notes (visibility);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_visibility;
+ALTER TABLE notes DROP COLUMN IF EXISTS visibility;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS pinned boolean NOT NULL DEFAULT false;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS sort_order integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_notes_pinned ON notes (sort_order) WHERE pinned;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_pinned;
+ALTER TABLE notes DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE notes DROP COLUMN IF EXISTS pinned;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A policy belongs to exactly one user or one household. A NULL tool list
+-- inherits from the household (for user policies) or the server default.
+CREATE TABLE IF NOT EXISTS tool_policies (
+	uid               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_uid          uuid UNIQUE REFERENCES users(uid) ON DELETE CASCADE,
+	household_uid     uuid UNIQUE REFERENCES households(uid) ON DELETE CASCADE,
+	allowed_tools     text[],
+	disallowed_tools  text[],
+	created_at        timestamptz NOT NULL DEFAULT now(),
+	updated_at        timestamptz NOT NULL DEFAULT now(),
+	CHECK ((user_uid IS NULL) <> (household_uid IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tool_policies;
+-- +goose StatementEnd
@@ -0,0 +1,91 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tenants (
+	uid         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	name        text NOT NULL,
+	created_at  timestamptz NOT NULL DEFAULT now(),
+	updated_at  timestamptz NOT NULL DEFAULT now()
+);
+
+-- Everything that exists today belongs to the default tenant.
+INSERT INTO tenants (uid, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default') ON CONFLICT DO NOTHING;
+
+-- current_tenant is the tenant the application set on this connection
+-- (app.tenant_uid), or NULL for unscoped connections.
+CREATE OR REPLACE FUNCTION current_tenant() RETURNS uuid LANGUAGE sql STABLE AS $$
+	SELECT NULLIF(current_setting('app.tenant_uid', true), '')::uuid
+$$;
+
+-- stamp_tenant sets tenant_uid on new rows: the connection's tenant when it
+-- has one, otherwise the tenant of the row's user or household.
+CREATE OR REPLACE FUNCTION stamp_tenant() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	owner jsonb := to_jsonb(NEW);
+	t uuid := current_tenant();
+BEGIN
+	IF t IS NULL AND owner->>'user_uid' IS NOT NULL THEN
+		SELECT tenant_uid INTO t FROM users WHERE uid = (owner->>'user_uid')::uuid;
+	END IF;
+	IF t IS NULL AND owner->>'household_uid' IS NOT NULL THEN
+		SELECT tenant_uid INTO t FROM households WHERE uid = (owner->>'household_uid')::uuid;
+	END IF;
+	NEW.tenant_uid := COALESCE(t, NEW.tenant_uid);
+	RETURN NEW;
+END
+$$;
+
+-- Stamp every table with its tenant and only let a tenant-scoped connection
+-- see and write its own rows.
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOREACH tbl IN ARRAY ARRAY['households', 'users', 'slack_users', 'credentials', 'todos', 'notes', 'preferences', 'recipes', 'backgrounds', 'api_keys', 'tool_policies'] LOOP
+		EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_uid uuid NOT NULL DEFAULT %L REFERENCES tenants(uid)', tbl, '00000000-0000-0000-0000-000000000001');
+		EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (tenant_uid)', 'idx_' || tbl || '_tenant_uid', tbl);
+		EXECUTE format('DROP TRIGGER IF EXISTS stamp_tenant ON %I', tbl);
+		EXECUTE format('CREATE TRIGGER stamp_tenant BEFORE INSERT ON %I FOR EACH ROW EXECUTE FUNCTION stamp_tenant()', tbl);
+		EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+		EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tbl);
+		EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+		EXECUTE format('CREATE POLICY tenant_isolation ON %I USING (current_tenant() IS NULL OR tenant_uid = current_tenant())', tbl);
+	END LOOP;
+END
+$$;
+
+-- Natural keys are only unique within a tenant.
+ALTER TABLE backgrounds DROP CONSTRAINT IF EXISTS backgrounds_pkey;
+ALTER TABLE backgrounds ADD PRIMARY KEY (tenant_uid, key);
+ALTER TABLE preferences DROP CONSTRAINT IF EXISTS preferences_pkey;
+ALTER TABLE preferences ADD PRIMARY KEY (tenant_uid, key, specifier);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_tenant_email_key UNIQUE (tenant_uid, email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_email_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE preferences DROP CONSTRAINT IF EXISTS preferences_pkey;
+ALTER TABLE preferences ADD PRIMARY KEY (key, specifier);
+ALTER TABLE backgrounds DROP CONSTRAINT IF EXISTS backgrounds_pkey;
+ALTER TABLE backgrounds ADD PRIMARY KEY (key);
+
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOREACH tbl IN ARRAY ARRAY['households', 'users', 'slack_users', 'credentials', 'todos', 'notes', 'preferences', 'recipes', 'backgrounds', 'api_keys', 'tool_policies'] LOOP
+		EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+		EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', tbl);
+		EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', tbl);
+		EXECUTE format('DROP TRIGGER IF EXISTS stamp_tenant ON %I', tbl);
+		EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_uid', tbl);
+	END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS stamp_tenant();
+DROP FUNCTION IF EXISTS current_tenant();
+DROP TABLE IF EXISTS tenants;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A row means todo_uid cannot be done until blocked_by_uid is.
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+	todo_uid        uuid NOT NULL REFERENCES todos(uid) ON DELETE CASCADE,
+	blocked_by_uid  uuid NOT NULL REFERENCES todos(uid) ON DELETE CASCADE,
+	tenant_uid      uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at      timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (todo_uid, blocked_by_uid),
+	CHECK (todo_uid <> blocked_by_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_blocked_by_uid ON todo_dependencies (blocked_by_uid);
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_tenant_uid ON todo_dependencies (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON todo_dependencies FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE todo_dependencies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_dependencies FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON todo_dependencies USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_dependencies;
+-- +goose StatementEnd
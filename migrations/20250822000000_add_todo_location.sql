@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- location holds {"name", "lat", "lon", "radius_m"}; NULL when the todo can
+-- be done anywhere.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS location jsonb;
+
+-- distance_m is the great-circle (haversine) distance in metres between two
+-- points given in degrees.
+CREATE OR REPLACE FUNCTION distance_m(lat1 float8, lon1 float8, lat2 float8, lon2 float8) RETURNS float8
+LANGUAGE sql IMMUTABLE AS $$
+	SELECT 2 * 6371000 * asin(sqrt(
+		power(sin(radians(lat2 - lat1) / 2), 2) +
+		cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lon2 - lon1) / 2), 2)
+	))
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS distance_m(float8, float8, float8, float8);
+ALTER TABLE todos DROP COLUMN IF EXISTS location;
+-- +goose StatementEnd
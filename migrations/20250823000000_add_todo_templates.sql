@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- items is a JSON array of {"title", "description", "priority", "due_in_days"};
+-- titles and descriptions may contain {{param}} placeholders filled in when
+-- the template is applied.
+CREATE TABLE IF NOT EXISTS todo_templates (
+	uid            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	name           text NOT NULL,
+	description    text NOT NULL DEFAULT '',
+	items          jsonb NOT NULL DEFAULT '[]',
+	user_uid       uuid REFERENCES users(uid) ON DELETE CASCADE,
+	household_uid  uuid REFERENCES households(uid) ON DELETE CASCADE,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at     timestamptz NOT NULL DEFAULT now(),
+	updated_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_templates_name ON todo_templates (name);
+CREATE INDEX IF NOT EXISTS idx_todo_templates_tenant_uid ON todo_templates (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON todo_templates FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE todo_templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_templates FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON todo_templates USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_templates;
+-- +goose StatementEnd
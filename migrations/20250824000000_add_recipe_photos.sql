@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- photo_updated_at is set while a recipe has a photo and doubles as a cache
+-- buster in photo URLs.
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS photo_updated_at timestamptz;
+
+-- One row per rendition of a recipe's photo: the upload as "original" plus
+-- its generated thumbnails.
+CREATE TABLE IF NOT EXISTS recipe_photos (
+	recipe_id     uuid NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+	size          text NOT NULL,
+	content_type  text NOT NULL,
+	width         integer NOT NULL,
+	height        integer NOT NULL,
+	data          bytea NOT NULL,
+	tenant_uid    uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (recipe_id, size)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_photos_tenant_uid ON recipe_photos (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON recipe_photos FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE recipe_photos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE recipe_photos FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON recipe_photos USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recipe_photos;
+ALTER TABLE recipes DROP COLUMN IF EXISTS photo_updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- quantity is NULL for staples tracked only by presence ("some salt"); unit
+-- is any name the measurement package understands, or '' for a count.
+CREATE TABLE IF NOT EXISTS pantry_items (
+	uid            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid  uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	item           text NOT NULL,
+	quantity       double precision CHECK (quantity >= 0),
+	unit           text NOT NULL DEFAULT '',
+	expires_on     date,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at     timestamptz NOT NULL DEFAULT now(),
+	updated_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pantry_items_household_uid ON pantry_items (household_uid, item);
+CREATE INDEX IF NOT EXISTS idx_pantry_items_expires_on ON pantry_items (expires_on) WHERE expires_on IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pantry_items_tenant_uid ON pantry_items (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON pantry_items FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE pantry_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pantry_items FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON pantry_items USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pantry_items;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per shopping list item bought. price_cents is what was paid for
+-- the whole line; quantity is free text such as "2 l".
+CREATE TABLE IF NOT EXISTS grocery_purchases (
+	uid            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid  uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	item           text NOT NULL,
+	quantity       text NOT NULL DEFAULT '',
+	store          text NOT NULL DEFAULT '',
+	price_cents    integer NOT NULL CHECK (price_cents >= 0),
+	purchased_on   date NOT NULL DEFAULT CURRENT_DATE,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at     timestamptz NOT NULL DEFAULT now(),
+	updated_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_grocery_purchases_household_uid ON grocery_purchases (household_uid, purchased_on);
+CREATE INDEX IF NOT EXISTS idx_grocery_purchases_tenant_uid ON grocery_purchases (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON grocery_purchases FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE grocery_purchases ENABLE ROW LEVEL SECURITY;
+ALTER TABLE grocery_purchases FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON grocery_purchases USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS grocery_purchases;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mobile devices registered for push notifications. A token belongs to one
+-- device, so registering it again moves it to the new user.
+CREATE TABLE IF NOT EXISTS devices (
+	uid         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	platform    text NOT NULL CHECK (platform IN ('apns', 'fcm')),
+	token       text NOT NULL,
+	name        text NOT NULL DEFAULT '',
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at  timestamptz NOT NULL DEFAULT now(),
+	updated_at  timestamptz NOT NULL DEFAULT now(),
+	UNIQUE (platform, token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_devices_user_uid ON devices (user_uid);
+CREATE INDEX IF NOT EXISTS idx_devices_tenant_uid ON devices (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON devices FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE devices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE devices FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON devices USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS devices;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- email_digest preferences become the digest_frequency of each user's
+-- notifications preference.
+INSERT INTO preferences (tenant_uid, key, specifier, data, tags, created_at, updated_at)
+SELECT tenant_uid, 'notifications', specifier,
+	jsonb_build_object('digest_frequency', CASE WHEN lower(data #>> '{}') IN ('daily', 'weekly') THEN lower(data #>> '{}') ELSE 'off' END),
+	'{}', NOW(), NOW()
+FROM preferences WHERE key = 'email_digest'
+ON CONFLICT DO NOTHING;
+
+DELETE FROM preferences WHERE key = 'email_digest';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+INSERT INTO preferences (tenant_uid, key, specifier, data, tags, created_at, updated_at)
+SELECT tenant_uid, 'email_digest', specifier, data->'digest_frequency', '{}', NOW(), NOW()
+FROM preferences WHERE key = 'notifications' AND data->>'digest_frequency' IN ('daily', 'weekly')
+ON CONFLICT DO NOTHING;
+
+DELETE FROM preferences WHERE key = 'notifications';
+-- +goose StatementEnd
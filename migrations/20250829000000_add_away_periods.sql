@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Dates a user is away, e.g. on holiday. Both ends are included.
+CREATE TABLE IF NOT EXISTS away_periods (
+	uid         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	starts_on   date NOT NULL,
+	ends_on     date NOT NULL,
+	note        text NOT NULL DEFAULT '',
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at  timestamptz NOT NULL DEFAULT now(),
+	updated_at  timestamptz NOT NULL DEFAULT now(),
+	CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_away_periods_user_uid ON away_periods (user_uid, ends_on);
+CREATE INDEX IF NOT EXISTS idx_away_periods_tenant_uid ON away_periods (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON away_periods FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE away_periods ENABLE ROW LEVEL SECURITY;
+ALTER TABLE away_periods FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON away_periods USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS away_periods;
+-- +goose StatementEnd
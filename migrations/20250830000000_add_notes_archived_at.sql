@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notes condensed into a digest note are archived rather than deleted.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS archived_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_notes_unarchived_updated_at ON notes (updated_at) WHERE archived_at IS NULL AND NOT pinned;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_unarchived_updated_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS archived_at;
+-- +goose StatementEnd
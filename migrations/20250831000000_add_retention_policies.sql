@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How long each kind of record is kept, per household or, with no
+-- household, for all of them. Older records are archived, deleted or
+-- summarized.
+CREATE TABLE IF NOT EXISTS retention_policies (
+	uid           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	entity        text NOT NULL CHECK (entity IN ('notes', 'todos', 'grocery_purchases')),
+	household_uid uuid REFERENCES households(uid) ON DELETE CASCADE,
+	max_age_days  integer NOT NULL CHECK (max_age_days > 0),
+	action        text NOT NULL CHECK (action IN ('archive', 'delete', 'summarize')),
+	tenant_uid    uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_policies_entity_household ON retention_policies (entity, (COALESCE(household_uid::text, '')));
+CREATE INDEX IF NOT EXISTS idx_retention_policies_tenant_uid ON retention_policies (tenant_uid);
+CREATE INDEX IF NOT EXISTS idx_todos_marked_complete ON todos (marked_complete) WHERE marked_complete IS NOT NULL;
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON retention_policies FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE retention_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE retention_policies FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON retention_policies USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_todos_marked_complete;
+DROP TABLE IF EXISTS retention_policies;
+-- +goose StatementEnd
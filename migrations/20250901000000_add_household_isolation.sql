@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+-- current_app_user and current_household are the user and household the
+-- application set for this transaction (app.user_uid, app.household_uid), or
+-- NULL for unscoped transactions.
+CREATE OR REPLACE FUNCTION current_app_user() RETURNS uuid LANGUAGE sql STABLE AS $$
+	SELECT NULLIF(current_setting('app.user_uid', true), '')::uuid
+$$;
+
+CREATE OR REPLACE FUNCTION current_household() RETURNS uuid LANGUAGE sql STABLE AS $$
+	SELECT NULLIF(current_setting('app.household_uid', true), '')::uuid
+$$;
+
+-- household_visible reports whether a row owned by household and owner may
+-- be seen in this transaction: always when it is unscoped, otherwise when
+-- the row is the household's, or has no household and belongs to nobody, the
+-- user or one of their housemates.
+CREATE OR REPLACE FUNCTION household_visible(household uuid, owner uuid) RETURNS boolean LANGUAGE sql STABLE AS $$
+	SELECT current_app_user() IS NULL
+		OR household = current_household()
+		OR (household IS NULL AND (owner IS NULL OR owner = current_app_user()
+			OR owner IN (SELECT uid FROM users WHERE household_uid = current_household())))
+$$;
+
+-- household_isolation is restrictive, so it narrows tenant_isolation rather
+-- than widening it. Credentials and API keys stay private to their user.
+DO $$
+DECLARE
+	rule text[];
+BEGIN
+	FOREACH rule SLICE 1 IN ARRAY ARRAY[
+		['households', 'current_app_user() IS NULL OR uid = current_household()'],
+		['users', 'current_app_user() IS NULL OR uid = current_app_user() OR household_uid = current_household()'],
+		['todos', 'household_visible(household_uid, user_uid)'],
+		['notes', 'household_visible(household_uid, user_uid)'],
+		['recipes', 'household_visible(household_uid, user_uid)'],
+		['todo_templates', 'household_visible(household_uid, user_uid)'],
+		['tool_policies', 'household_visible(household_uid, user_uid)'],
+		['pantry_items', 'household_visible(household_uid, NULL)'],
+		['grocery_purchases', 'household_visible(household_uid, NULL)'],
+		['retention_policies', 'household_visible(household_uid, NULL)'],
+		['slack_users', 'household_visible(NULL, user_uid)'],
+		['credentials', 'current_app_user() IS NULL OR user_uid = current_app_user()'],
+		['api_keys', 'current_app_user() IS NULL OR user_uid = current_app_user()'],
+		['devices', 'household_visible(NULL, user_uid)'],
+		['away_periods', 'household_visible(NULL, user_uid)']
+	] LOOP
+		EXECUTE format('DROP POLICY IF EXISTS household_isolation ON %I', rule[1]);
+		EXECUTE format('CREATE POLICY household_isolation ON %I AS RESTRICTIVE USING (%s)', rule[1], rule[2]);
+	END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOREACH tbl IN ARRAY ARRAY['households', 'users', 'todos', 'notes', 'recipes', 'todo_templates', 'tool_policies', 'pantry_items', 'grocery_purchases', 'retention_policies', 'slack_users', 'credentials', 'api_keys', 'devices', 'away_periods'] LOOP
+		EXECUTE format('DROP POLICY IF EXISTS household_isolation ON %I', tbl);
+	END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS household_visible(uuid, uuid);
+DROP FUNCTION IF EXISTS current_household();
+DROP FUNCTION IF EXISTS current_app_user();
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- JSON Schemas that the data of notes or preferences with a given key must
+-- match, so data written by assistants keeps its shape.
+CREATE TABLE IF NOT EXISTS data_schemas (
+	entity     text NOT NULL CHECK (entity IN ('notes', 'preferences')),
+	key        text NOT NULL,
+	schema     jsonb NOT NULL,
+	tenant_uid uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at timestamptz NOT NULL DEFAULT now(),
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (tenant_uid, entity, key)
+);
+
+-- Schemas apply to every household in the tenant, so tenant isolation is
+-- all they need.
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON data_schemas FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE data_schemas ENABLE ROW LEVEL SECURITY;
+ALTER TABLE data_schemas FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON data_schemas USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_schemas;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Priorities were written on a 1-5 scale; they are now low (1), medium (2),
+-- high (3) and critical (4). 1 and 2 become low, 3 medium, 4 high and 5
+-- critical. Missing or out-of-range ones become medium.
+UPDATE todos SET priority = CASE priority
+	WHEN 1 THEN 1
+	WHEN 2 THEN 1
+	WHEN 3 THEN 2
+	WHEN 4 THEN 3
+	WHEN 5 THEN 4
+	ELSE 2
+END;
+ALTER TABLE todos ALTER COLUMN priority SET NOT NULL;
+ALTER TABLE todos ADD CONSTRAINT todos_priority_check CHECK (priority BETWEEN 1 AND 4);
+
+-- Template items store priorities as JSON, now as labels. Items without a
+-- valid one lose it and so default to medium when applied.
+UPDATE todo_templates SET items = (
+	SELECT jsonb_agg(CASE WHEN p.label IS NULL THEN e.item - 'priority' ELSE jsonb_set(e.item, '{priority}', to_jsonb(p.label)) END ORDER BY e.n)
+	FROM jsonb_array_elements(items) WITH ORDINALITY AS e(item, n),
+	LATERAL (SELECT CASE e.item->>'priority'
+		WHEN '1' THEN 'low'
+		WHEN '2' THEN 'low'
+		WHEN '3' THEN 'medium'
+		WHEN '4' THEN 'high'
+		WHEN '5' THEN 'critical'
+	END AS label) p
+)
+WHERE jsonb_path_exists(items, '$[*].priority');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_priority_check;
+ALTER TABLE todos ALTER COLUMN priority DROP NOT NULL;
+UPDATE todos SET priority = CASE priority WHEN 1 THEN 1 WHEN 2 THEN 3 WHEN 3 THEN 4 WHEN 4 THEN 5 END;
+
+UPDATE todo_templates SET items = (
+	SELECT jsonb_agg(CASE WHEN p.n IS NULL THEN e.item - 'priority' ELSE jsonb_set(e.item, '{priority}', to_jsonb(p.n)) END ORDER BY e.i)
+	FROM jsonb_array_elements(items) WITH ORDINALITY AS e(item, i),
+	LATERAL (SELECT CASE e.item->>'priority'
+		WHEN 'low' THEN 1
+		WHEN 'medium' THEN 3
+		WHEN 'high' THEN 4
+		WHEN 'critical' THEN 5
+	END AS n) p
+)
+WHERE jsonb_path_exists(items, '$[*].priority');
+-- +goose StatementEnd
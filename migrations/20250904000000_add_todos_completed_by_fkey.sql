@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- completed_by was free text: a user's UID, name or email, or ''. A todo
+-- with a completer counts as completed, so keep it marked complete before
+-- resolving the completer to the one user it names, if any.
+UPDATE todos SET marked_complete = COALESCE(marked_complete, updated_at) WHERE completed_by <> '';
+
+UPDATE todos t SET completed_by = (
+	SELECT min(u.uid::text) FROM users u
+	WHERE u.uid::text = lower(t.completed_by)
+		OR u.household_uid IS NOT DISTINCT FROM t.household_uid
+			AND (lower(u.email) = lower(t.completed_by) OR lower(u.name) = lower(t.completed_by))
+	HAVING count(*) = 1
+)
+WHERE completed_by IS NOT NULL;
+
+ALTER TABLE todos ALTER COLUMN completed_by TYPE uuid USING completed_by::uuid;
+ALTER TABLE todos ADD CONSTRAINT todos_completed_by_fkey FOREIGN KEY (completed_by) REFERENCES users(uid) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_todos_completed_by ON todos (completed_by);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_todos_completed_by;
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_completed_by_fkey;
+ALTER TABLE todos ALTER COLUMN completed_by TYPE text USING completed_by::text;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- save_recipe looks up the household's recipe from a page before saving a
+-- new one.
+CREATE INDEX IF NOT EXISTS idx_recipes_household_external_url ON recipes (household_uid, external_url) WHERE external_url IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_recipes_household_external_url;
+-- +goose StatementEnd
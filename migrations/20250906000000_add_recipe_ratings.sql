@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each user rates a recipe once. recipes.rating becomes the average of its
+-- ratings, kept up to date with rating_count by recipe_rating_changed.
+CREATE TABLE IF NOT EXISTS recipe_ratings (
+	recipe_id   uuid NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	rating      integer NOT NULL CHECK (rating BETWEEN 1 AND 5),
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at  timestamptz NOT NULL DEFAULT now(),
+	updated_at  timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (recipe_id, user_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ratings_user_uid ON recipe_ratings (user_uid);
+CREATE INDEX IF NOT EXISTS idx_recipe_ratings_tenant_uid ON recipe_ratings (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON recipe_ratings FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE recipe_ratings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE recipe_ratings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON recipe_ratings USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON recipe_ratings AS RESTRICTIVE USING (household_visible(NULL, user_uid));
+
+-- The single rating so far was set by whoever saved the recipe; it becomes
+-- the creator's.
+INSERT INTO recipe_ratings (recipe_id, user_uid, rating, tenant_uid, created_at, updated_at)
+SELECT id, user_uid, rating, tenant_uid, updated_at, updated_at FROM recipes
+WHERE user_uid IS NOT NULL AND rating BETWEEN 1 AND 5
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE recipes ALTER COLUMN rating TYPE double precision;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS rating_count integer NOT NULL DEFAULT 0;
+UPDATE recipes SET rating = NULL;
+UPDATE recipes r SET rating = s.average, rating_count = s.n
+FROM (SELECT recipe_id, avg(rating) AS average, count(*) AS n FROM recipe_ratings GROUP BY recipe_id) s
+WHERE r.id = s.recipe_id;
+
+CREATE OR REPLACE FUNCTION recipe_rating_changed() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	recipe uuid := CASE WHEN TG_OP = 'DELETE' THEN OLD.recipe_id ELSE NEW.recipe_id END;
+BEGIN
+	UPDATE recipes SET
+		rating = (SELECT avg(rating) FROM recipe_ratings WHERE recipe_id = recipe),
+		rating_count = (SELECT count(*) FROM recipe_ratings WHERE recipe_id = recipe)
+	WHERE id = recipe;
+	RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER recipe_rating_changed AFTER INSERT OR UPDATE OR DELETE ON recipe_ratings
+	FOR EACH ROW EXECUTE FUNCTION recipe_rating_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The creator's rating, if any, goes back on the recipe.
+ALTER TABLE recipes ALTER COLUMN rating TYPE integer USING NULL;
+UPDATE recipes r SET rating = rr.rating FROM recipe_ratings rr WHERE rr.recipe_id = r.id AND rr.user_uid = r.user_uid;
+ALTER TABLE recipes DROP COLUMN IF EXISTS rating_count;
+DROP TABLE IF EXISTS recipe_ratings;
+DROP FUNCTION IF EXISTS recipe_rating_changed();
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The grocery aisle a pantry item is shelved in, such as produce or dairy.
+-- Empty until the item is next saved.
+ALTER TABLE pantry_items ADD COLUMN IF NOT EXISTS category text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_pantry_items_household_category ON pantry_items (household_uid, category);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pantry_items_household_category;
+ALTER TABLE pantry_items DROP COLUMN IF EXISTS category;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A user's My Day: the todos they mean to focus on today, whatever their
+-- due dates. Each entry lapses at expires_at, the user's next local
+-- midnight when it was added, so the list starts empty every day.
+CREATE TABLE IF NOT EXISTS my_day_todos (
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	todo_uid    uuid NOT NULL REFERENCES todos(uid) ON DELETE CASCADE,
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	added_at    timestamptz NOT NULL DEFAULT now(),
+	expires_at  timestamptz NOT NULL,
+	PRIMARY KEY (user_uid, todo_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_my_day_todos_todo_uid ON my_day_todos (todo_uid);
+CREATE INDEX IF NOT EXISTS idx_my_day_todos_tenant_uid ON my_day_todos (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON my_day_todos FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE my_day_todos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE my_day_todos FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON my_day_todos USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON my_day_todos AS RESTRICTIVE USING (household_visible(NULL, user_uid));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS my_day_todos;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Where a todo is on the board. A todo is done exactly when marked_complete
+-- is set, so completed todos start out done and the rest in the backlog.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'backlog'
+	CHECK (status IN ('backlog', 'planned', 'in_progress', 'blocked', 'done'));
+UPDATE todos SET status = 'done' WHERE marked_complete IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_todos_household_status ON todos (household_uid, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_todos_household_status;
+ALTER TABLE todos DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
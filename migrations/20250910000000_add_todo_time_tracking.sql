@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How long a todo is expected to take, and the time actually spent on it,
+-- logged by whoever did the work on the day they did it.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS estimate_minutes integer CHECK (estimate_minutes >= 0);
+
+CREATE TABLE IF NOT EXISTS todo_time_logs (
+	uid         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	todo_uid    uuid NOT NULL REFERENCES todos(uid) ON DELETE CASCADE,
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	minutes     integer NOT NULL CHECK (minutes > 0),
+	logged_on   date NOT NULL DEFAULT CURRENT_DATE,
+	note        text NOT NULL DEFAULT '',
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_time_logs_todo_uid ON todo_time_logs (todo_uid);
+CREATE INDEX IF NOT EXISTS idx_todo_time_logs_user_logged_on ON todo_time_logs (user_uid, logged_on);
+CREATE INDEX IF NOT EXISTS idx_todo_time_logs_tenant_uid ON todo_time_logs (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON todo_time_logs FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE todo_time_logs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_time_logs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON todo_time_logs USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON todo_time_logs AS RESTRICTIVE USING (household_visible(NULL, user_uid));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_time_logs;
+ALTER TABLE todos DROP COLUMN IF EXISTS estimate_minutes;
+-- +goose StatementEnd
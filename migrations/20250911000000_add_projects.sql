@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A project groups a household's todos, e.g. "Repaint the garage". Archived
+-- projects are kept with their todos but left out of everyday lists.
+CREATE TABLE IF NOT EXISTS projects (
+	uid            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	name           text NOT NULL,
+	description    text NOT NULL DEFAULT '',
+	household_uid  uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	archived       boolean NOT NULL DEFAULT false,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at     timestamptz NOT NULL DEFAULT now(),
+	updated_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_household_uid ON projects (household_uid, archived);
+CREATE INDEX IF NOT EXISTS idx_projects_tenant_uid ON projects (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON projects FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+ALTER TABLE projects FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON projects USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON projects AS RESTRICTIVE USING (household_visible(household_uid, NULL));
+
+-- Deleting a project keeps its todos.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS project_uid uuid REFERENCES projects(uid) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_todos_project_uid ON todos (project_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos DROP COLUMN IF EXISTS project_uid;
+DROP TABLE IF EXISTS projects;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per message handed to an outbound channel (email, apns or fcm),
+-- kept so failed deliveries can be inspected and replayed. payload is what
+-- was sent and payload_hash its SHA-256, so repeats of a message can be
+-- found; attempts counts the first send and every replay.
+CREATE TABLE IF NOT EXISTS deliveries (
+	uid           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	channel       text NOT NULL CHECK (channel IN ('email', 'apns', 'fcm')),
+	recipient     text NOT NULL,
+	payload       jsonb NOT NULL,
+	payload_hash  text NOT NULL,
+	status        text NOT NULL CHECK (status IN ('sent', 'failed')),
+	attempts      integer NOT NULL DEFAULT 1,
+	last_error    text,
+	tenant_uid    uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_status ON deliveries (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_deliveries_payload_hash ON deliveries (payload_hash);
+CREATE INDEX IF NOT EXISTS idx_deliveries_tenant_uid ON deliveries (tenant_uid);
+
+-- Deliveries are only read by operators replaying them, and have no
+-- household, so tenant isolation is all they need.
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON deliveries FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON deliveries USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS deliveries;
+-- +goose StatementEnd
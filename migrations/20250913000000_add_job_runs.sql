@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The background job queue, kept as a history of runs. Workers claim
+-- queued runs that are due; a failed run is queued again until it has had
+-- max_attempts. Scheduled runs carry a dedupe_key naming the job and the
+-- time it was scheduled for, so each is queued once however many servers
+-- are running. Jobs run for the whole server, so runs aren't tenant-scoped.
+CREATE TABLE IF NOT EXISTS job_runs (
+	uid           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	name          text NOT NULL,
+	payload       jsonb NOT NULL DEFAULT '{}',
+	dedupe_key    text UNIQUE,
+	status        text NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+	attempts      integer NOT NULL DEFAULT 0,
+	max_attempts  integer NOT NULL DEFAULT 1 CHECK (max_attempts > 0),
+	run_at        timestamptz NOT NULL DEFAULT now(),
+	started_at    timestamptz,
+	finished_at   timestamptz,
+	last_error    text,
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_due ON job_runs (status, run_at);
+CREATE INDEX IF NOT EXISTS idx_job_runs_name ON job_runs (name, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS job_runs;
+-- +goose StatementEnd
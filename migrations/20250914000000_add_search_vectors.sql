@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text search vectors, kept up to date by Postgres. Titles weigh more
+-- than bodies, so a match in the title ranks higher.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('english', coalesce(key, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(data, '')), 'B')
+) STORED;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(genre, '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(data, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_todos_search_vector ON todos USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_recipes_search_vector ON recipes USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE recipes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE notes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE todos DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- When a note's owner is reminded of it. The reminders job looks for notes
+-- falling due in each interval, so only notes with a reminder are indexed.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS remind_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_notes_remind_at ON notes (remind_at) WHERE remind_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_remind_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS remind_at;
+-- +goose StatementEnd
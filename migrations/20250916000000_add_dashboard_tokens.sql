@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Share tokens for a household's read-only public dashboard, e.g. on a
+-- tablet on the fridge. Like API keys, only a hash of each token is kept.
+-- sections lists what the dashboard shows; a revoked token shows nothing.
+CREATE TABLE IF NOT EXISTS dashboard_tokens (
+	uid            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid  uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	name           text NOT NULL,
+	token_hash     text NOT NULL UNIQUE,
+	sections       text[] NOT NULL DEFAULT '{}',
+	last_used_at   timestamptz,
+	revoked_at     timestamptz,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at     timestamptz NOT NULL DEFAULT now(),
+	updated_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_tokens_household_uid ON dashboard_tokens (household_uid);
+CREATE INDEX IF NOT EXISTS idx_dashboard_tokens_tenant_uid ON dashboard_tokens (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON dashboard_tokens FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE dashboard_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE dashboard_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON dashboard_tokens USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON dashboard_tokens AS RESTRICTIVE USING (household_visible(household_uid, NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS dashboard_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The sync feed for offline clients: one row per synced todo or note, kept
+-- after it is deleted as a tombstone. seq orders the feed; every change
+-- takes a new one, so a client's cursor is the last seq it has seen.
+-- version is a vector timestamp, how many changes each writer has made:
+-- clients count under their own ID and every other write under "server".
+-- updated_at is when the winning change was made, for last-writer-wins.
+CREATE SEQUENCE IF NOT EXISTS sync_seq;
+
+CREATE TABLE IF NOT EXISTS sync_states (
+	entity         text NOT NULL,
+	entity_id      uuid NOT NULL,
+	household_uid  uuid,
+	user_uid       uuid,
+	seq            bigint NOT NULL DEFAULT nextval('sync_seq'),
+	version        jsonb NOT NULL DEFAULT '{}',
+	deleted        boolean NOT NULL DEFAULT false,
+	tenant_uid     uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	updated_at     timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (entity, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_states_seq ON sync_states (seq);
+CREATE INDEX IF NOT EXISTS idx_sync_states_household_uid ON sync_states (household_uid);
+CREATE INDEX IF NOT EXISTS idx_sync_states_tenant_uid ON sync_states (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON sync_states FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE sync_states ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sync_states FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sync_states USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON sync_states AS RESTRICTIVE USING (household_visible(household_uid, user_uid));
+
+-- sync_changed counts a write to a synced table as the server's. The first
+-- argument names the table's key column. Sync writes its clients' versions
+-- over this afterwards.
+CREATE OR REPLACE FUNCTION sync_changed() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	r jsonb;
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		r := to_jsonb(OLD);
+	ELSE
+		r := to_jsonb(NEW);
+	END IF;
+	INSERT INTO sync_states (entity, entity_id, household_uid, user_uid, version, deleted, tenant_uid)
+	VALUES (TG_TABLE_NAME, (r->>TG_ARGV[0])::uuid, (r->>'household_uid')::uuid, (r->>'user_uid')::uuid,
+		'{"server": 1}', TG_OP = 'DELETE', (r->>'tenant_uid')::uuid)
+	ON CONFLICT (entity, entity_id) DO UPDATE SET
+		seq = nextval('sync_seq'),
+		version = sync_states.version || jsonb_build_object('server', coalesce((sync_states.version->>'server')::bigint, 0) + 1),
+		deleted = EXCLUDED.deleted,
+		household_uid = EXCLUDED.household_uid,
+		user_uid = EXCLUDED.user_uid,
+		updated_at = now();
+	RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER sync_changed AFTER INSERT OR UPDATE OR DELETE ON todos
+	FOR EACH ROW EXECUTE FUNCTION sync_changed('uid');
+CREATE TRIGGER sync_changed AFTER INSERT OR UPDATE OR DELETE ON notes
+	FOR EACH ROW EXECUTE FUNCTION sync_changed('id');
+
+-- Everything already stored starts in the feed as the server's.
+INSERT INTO sync_states (entity, entity_id, household_uid, user_uid, version, tenant_uid, updated_at)
+SELECT 'todos', uid, household_uid, user_uid, '{"server": 1}', tenant_uid, updated_at FROM todos
+UNION ALL
+SELECT 'notes', id, household_uid, user_uid, '{"server": 1}', tenant_uid, updated_at FROM notes
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS sync_changed ON todos;
+DROP TRIGGER IF EXISTS sync_changed ON notes;
+DROP FUNCTION IF EXISTS sync_changed();
+DROP TABLE IF EXISTS sync_states;
+DROP SEQUENCE IF EXISTS sync_seq;
+-- +goose StatementEnd
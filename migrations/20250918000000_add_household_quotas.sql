@@ -0,0 +1,114 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Caps on how much a household can store, so a runaway assistant can't
+-- fill a shared deployment. The row with no household is the default; a
+-- household's own row replaces it entirely, which is how an operator
+-- raises or lifts one household's caps. A NULL cap is no cap.
+CREATE TABLE IF NOT EXISTS household_quotas (
+	uid             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid   uuid REFERENCES households(uid) ON DELETE CASCADE,
+	max_todos       integer CHECK (max_todos >= 0),
+	max_notes       integer CHECK (max_notes >= 0),
+	max_recipes     integer CHECK (max_recipes >= 0),
+	max_photo_bytes bigint CHECK (max_photo_bytes >= 0),
+	tenant_uid      uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at      timestamptz NOT NULL DEFAULT now(),
+	updated_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_household_quotas_household ON household_quotas (tenant_uid, (COALESCE(household_uid::text, '')));
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON household_quotas FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE household_quotas ENABLE ROW LEVEL SECURITY;
+ALTER TABLE household_quotas FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON household_quotas USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+
+-- household_usage is how much of entity ('todos', 'notes', 'recipes' or
+-- 'photo_bytes') a household holds, counting its members' personal rows.
+CREATE OR REPLACE FUNCTION household_usage(household uuid, entity text) RETURNS bigint LANGUAGE plpgsql STABLE AS $$
+DECLARE
+	n bigint;
+BEGIN
+	IF entity = 'photo_bytes' THEN
+		SELECT coalesce(sum(octet_length(p.data)), 0) INTO n FROM recipe_photos p JOIN recipes r ON r.id = p.recipe_id
+		WHERE r.household_uid = household
+			OR (r.household_uid IS NULL AND r.user_uid IN (SELECT uid FROM users WHERE household_uid = household));
+	ELSE
+		EXECUTE format('SELECT count(*) FROM %I WHERE household_uid = $1
+			OR (household_uid IS NULL AND user_uid IN (SELECT uid FROM users WHERE household_uid = $1))', entity)
+		INTO n USING household;
+	END IF;
+	RETURN n;
+END
+$$;
+
+-- enforce_quota refuses a new todo, note, recipe or photo that would take
+-- its household over a cap. Rows owned by no household aren't capped.
+CREATE OR REPLACE FUNCTION enforce_quota() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	household uuid;
+	owner uuid;
+	q household_quotas;
+	cap bigint;
+	used bigint;
+	what text;
+BEGIN
+	IF TG_TABLE_NAME = 'recipe_photos' THEN
+		SELECT r.household_uid, r.user_uid INTO household, owner FROM recipes r WHERE r.id = NEW.recipe_id;
+	ELSE
+		household := NEW.household_uid;
+		owner := NEW.user_uid;
+	END IF;
+	IF household IS NULL AND owner IS NOT NULL THEN
+		SELECT u.household_uid INTO household FROM users u WHERE u.uid = owner;
+	END IF;
+	IF household IS NULL THEN
+		RETURN NEW;
+	END IF;
+	SELECT * INTO q FROM household_quotas
+	WHERE household_uid = household OR household_uid IS NULL
+	ORDER BY household_uid NULLS LAST LIMIT 1;
+	IF NOT FOUND THEN
+		RETURN NEW;
+	END IF;
+
+	CASE TG_TABLE_NAME
+	WHEN 'todos' THEN cap := q.max_todos; what := 'todos';
+	WHEN 'notes' THEN cap := q.max_notes; what := 'notes';
+	WHEN 'recipes' THEN cap := q.max_recipes; what := 'recipes';
+	ELSE cap := q.max_photo_bytes; what := 'photo_bytes';
+	END CASE;
+	IF cap IS NULL THEN
+		RETURN NEW;
+	END IF;
+
+	used := household_usage(household, what);
+	IF what = 'photo_bytes' THEN
+		IF used + octet_length(NEW.data) > cap THEN
+			RAISE EXCEPTION 'this household''s recipe photos would take more than its limit of % bytes (% used); remove some photos or ask an operator to raise the limit', cap, used
+				USING ERRCODE = 'configuration_limit_exceeded';
+		END IF;
+	ELSIF used >= cap THEN
+		RAISE EXCEPTION 'this household has reached its limit of % %; delete some or ask an operator to raise the limit', cap, what
+			USING ERRCODE = 'configuration_limit_exceeded';
+	END IF;
+	RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER enforce_quota BEFORE INSERT ON todos FOR EACH ROW EXECUTE FUNCTION enforce_quota();
+CREATE TRIGGER enforce_quota BEFORE INSERT ON notes FOR EACH ROW EXECUTE FUNCTION enforce_quota();
+CREATE TRIGGER enforce_quota BEFORE INSERT ON recipes FOR EACH ROW EXECUTE FUNCTION enforce_quota();
+CREATE TRIGGER enforce_quota BEFORE INSERT ON recipe_photos FOR EACH ROW EXECUTE FUNCTION enforce_quota();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS enforce_quota ON todos;
+DROP TRIGGER IF EXISTS enforce_quota ON notes;
+DROP TRIGGER IF EXISTS enforce_quota ON recipes;
+DROP TRIGGER IF EXISTS enforce_quota ON recipe_photos;
+DROP FUNCTION IF EXISTS enforce_quota();
+DROP FUNCTION IF EXISTS household_usage(uuid, text);
+DROP TABLE IF EXISTS household_quotas;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A user asking to join a household. They become a member only once
+-- someone already in the household approves.
+CREATE TABLE IF NOT EXISTS household_join_requests (
+	uid           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	user_uid      uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	status        text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+	message       text NOT NULL DEFAULT '',
+	decided_by    uuid REFERENCES users(uid) ON DELETE SET NULL,
+	decided_at    timestamptz,
+	tenant_uid    uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+-- A user has at most one open request per household.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_household_join_requests_pending ON household_join_requests (household_uid, user_uid) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_household_join_requests_user_uid ON household_join_requests (user_uid);
+CREATE INDEX IF NOT EXISTS idx_household_join_requests_tenant_uid ON household_join_requests (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON household_join_requests FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE household_join_requests ENABLE ROW LEVEL SECURITY;
+ALTER TABLE household_join_requests FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON household_join_requests USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- Requests are seen by the requester and by the household they ask to join.
+CREATE POLICY household_isolation ON household_join_requests AS RESTRICTIVE
+	USING (current_app_user() IS NULL OR user_uid = current_app_user() OR household_uid = current_household());
+
+-- Members also see the users asking to join, so they can approve them.
+DROP POLICY IF EXISTS household_isolation ON users;
+CREATE POLICY household_isolation ON users AS RESTRICTIVE
+	USING (current_app_user() IS NULL OR uid = current_app_user() OR household_uid = current_household()
+		OR uid IN (SELECT r.user_uid FROM household_join_requests r WHERE r.household_uid = current_household() AND r.status = 'pending'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY IF EXISTS household_isolation ON users;
+CREATE POLICY household_isolation ON users AS RESTRICTIVE
+	USING (current_app_user() IS NULL OR uid = current_app_user() OR household_uid = current_household());
+DROP TABLE IF EXISTS household_join_requests;
+-- +goose StatementEnd
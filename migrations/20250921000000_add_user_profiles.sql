@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Profile details for personalizing briefings. avatar_updated_at is set
+-- while a user has an avatar and doubles as a cache buster in its URLs.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pronouns text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birthday date;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_updated_at timestamptz;
+
+-- One row per rendition of a user's avatar: the upload as "original" plus
+-- its generated thumbnails.
+CREATE TABLE IF NOT EXISTS user_avatars (
+	user_uid      uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	size          text NOT NULL,
+	content_type  text NOT NULL,
+	width         integer NOT NULL,
+	height        integer NOT NULL,
+	data          bytea NOT NULL,
+	tenant_uid    uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at    timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (user_uid, size)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_avatars_tenant_uid ON user_avatars (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON user_avatars FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE user_avatars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_avatars FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_avatars USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON user_avatars AS RESTRICTIVE USING (household_visible(NULL, user_uid));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_avatars;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_updated_at;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users DROP COLUMN IF EXISTS birthday;
+ALTER TABLE users DROP COLUMN IF EXISTS pronouns;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Dates a household wants to be reminded of ahead of time: birthdays,
+-- anniversaries, renewals. They recur yearly, monthly or not at all, and
+-- show in the briefing remind_days_ahead days before they come round.
+CREATE TABLE IF NOT EXISTS important_dates (
+	uid               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	household_uid     uuid NOT NULL REFERENCES households(uid) ON DELETE CASCADE,
+	title             text NOT NULL,
+	kind              text NOT NULL DEFAULT 'other' CHECK (kind IN ('birthday', 'anniversary', 'renewal', 'other')),
+	date              date NOT NULL,
+	recurrence        text NOT NULL DEFAULT 'yearly' CHECK (recurrence IN ('yearly', 'monthly', 'none')),
+	remind_days_ahead integer NOT NULL DEFAULT 7 CHECK (remind_days_ahead BETWEEN 0 AND 365),
+	notes             text NOT NULL DEFAULT '',
+	tenant_uid        uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at        timestamptz NOT NULL DEFAULT now(),
+	updated_at        timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_important_dates_household_uid ON important_dates (household_uid);
+CREATE INDEX IF NOT EXISTS idx_important_dates_tenant_uid ON important_dates (tenant_uid);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON important_dates FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE important_dates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE important_dates FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON important_dates USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON important_dates AS RESTRICTIVE USING (household_visible(household_uid, NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS important_dates;
+-- +goose StatementEnd
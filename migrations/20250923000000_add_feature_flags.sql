@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Switches for rolling out new capabilities gradually. A flag is on for a
+-- household when it is enabled outright, when the household is listed, or
+-- when the household falls in the first rollout_percent of 100 buckets
+-- hashed from the flag's name and the household's UID. The server caches
+-- the table briefly, so a change takes effect within FEATURE_FLAG_TTL.
+CREATE TABLE IF NOT EXISTS feature_flags (
+	uid             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	name            text NOT NULL,
+	description     text NOT NULL DEFAULT '',
+	enabled         boolean NOT NULL DEFAULT false,
+	rollout_percent integer NOT NULL DEFAULT 0 CHECK (rollout_percent BETWEEN 0 AND 100),
+	household_uids  uuid[] NOT NULL DEFAULT '{}',
+	tenant_uid      uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	created_at      timestamptz NOT NULL DEFAULT now(),
+	updated_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feature_flags_name ON feature_flags (tenant_uid, name);
+
+-- Flags are set by operators for the whole tenant and name the households
+-- they cover, so tenant isolation is all they need.
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON feature_flags FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE feature_flags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE feature_flags FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON feature_flags USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feature_flags;
+-- +goose StatementEnd
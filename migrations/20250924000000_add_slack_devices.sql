@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A Slack "device" is a user's direct messages with the app, with their
+-- Slack user ID as its token, so reminders can go to Slack like pushes.
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_platform_check;
+ALTER TABLE devices ADD CONSTRAINT devices_platform_check CHECK (platform IN ('apns', 'fcm', 'slack'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM devices WHERE platform = 'slack';
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_platform_check;
+ALTER TABLE devices ADD CONSTRAINT devices_platform_check CHECK (platform IN ('apns', 'fcm'));
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Requests and MCP tool calls made with each API key, per UTC day. Servers
+-- count in memory and add their counts here every API_USAGE_FLUSH_INTERVAL,
+-- so the current day lags by up to that long. Like the keys themselves,
+-- a user's usage is private to them.
+CREATE TABLE IF NOT EXISTS api_usage (
+	api_key_uid uuid NOT NULL REFERENCES api_keys(uid) ON DELETE CASCADE,
+	user_uid    uuid NOT NULL REFERENCES users(uid) ON DELETE CASCADE,
+	day         date NOT NULL,
+	requests    bigint NOT NULL DEFAULT 0,
+	tool_calls  bigint NOT NULL DEFAULT 0,
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	PRIMARY KEY (api_key_uid, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_user_day ON api_usage (user_uid, day);
+CREATE INDEX IF NOT EXISTS idx_api_usage_day ON api_usage (day);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON api_usage FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE api_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_usage FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_usage USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+CREATE POLICY household_isolation ON api_usage AS RESTRICTIVE USING (current_app_user() IS NULL OR user_uid = current_app_user());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_usage;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Short-lived advisory locks on editing an entity, such as a note, so a
+-- person editing in a dashboard and the assistant don't overwrite each
+-- other. A lock is held by an API key (or, without one, by an operator)
+-- until expires_at; an expired row is as good as no row, and is replaced
+-- by the next lock taken. Writes by other keys are refused while it is
+-- held; the lock itself changes nothing in the database.
+CREATE TABLE IF NOT EXISTS entity_locks (
+	entity      text NOT NULL,
+	entity_id   text NOT NULL,
+	api_key_uid uuid REFERENCES api_keys(uid) ON DELETE CASCADE,
+	user_uid    uuid REFERENCES users(uid) ON DELETE CASCADE,
+	acquired_at timestamptz NOT NULL DEFAULT now(),
+	expires_at  timestamptz NOT NULL,
+	tenant_uid  uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(uid),
+	PRIMARY KEY (entity, entity_id)
+);
+
+CREATE TRIGGER stamp_tenant BEFORE INSERT ON entity_locks FOR EACH ROW EXECUTE FUNCTION stamp_tenant();
+ALTER TABLE entity_locks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE entity_locks FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON entity_locks USING (current_tenant() IS NULL OR tenant_uid = current_tenant());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS entity_locks;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Backgrounds can lapse: the assistant's per-conversation scratchpads are
+-- kept as backgrounds under scratchpad:<owner>:<conversation>:<key> with an
+-- expires_at, after which they are no longer read and the
+-- scratchpad_cleanup job deletes them. Durable backgrounds have none.
+-- Namespaced keys need more room than 128 characters.
+ALTER TABLE backgrounds ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+ALTER TABLE backgrounds ALTER COLUMN key TYPE varchar(255);
+
+CREATE INDEX IF NOT EXISTS idx_backgrounds_expires_at ON backgrounds (expires_at) WHERE expires_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM backgrounds WHERE expires_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_backgrounds_expires_at;
+ALTER TABLE backgrounds DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE backgrounds ALTER COLUMN key TYPE varchar(128);
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- all_tenants reports whether the application asked to see every tenant on
+-- this connection (app.all_tenants), as it does for operators and background
+-- jobs only. Connections with neither a tenant nor this used to see every
+-- tenant's rows; now they see none. Migrations that touch rows must run as
+-- a role that bypasses row-level security, such as the database owner with
+-- BYPASSRLS.
+CREATE OR REPLACE FUNCTION all_tenants() RETURNS boolean LANGUAGE sql STABLE AS $$
+	SELECT COALESCE(current_setting('app.all_tenants', true), '') = 'on'
+$$;
+
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOR tbl IN SELECT tablename FROM pg_policies WHERE schemaname = current_schema() AND policyname = 'tenant_isolation' LOOP
+		EXECUTE format('ALTER POLICY tenant_isolation ON %I USING (all_tenants() OR tenant_uid = current_tenant())', tbl);
+	END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOR tbl IN SELECT tablename FROM pg_policies WHERE schemaname = current_schema() AND policyname = 'tenant_isolation' LOOP
+		EXECUTE format('ALTER POLICY tenant_isolation ON %I USING (current_tenant() IS NULL OR tenant_uid = current_tenant())', tbl);
+	END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS all_tenants();
+-- +goose StatementEnd
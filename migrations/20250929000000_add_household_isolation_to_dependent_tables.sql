@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tables whose rows belong to another row rather than to a household are
+-- visible through that row: a recipe's photos with the recipe, a todo's
+-- dependencies with both todos and a note's lock with the note. Quotas are
+-- the household's own or, without a household, the default every household
+-- falls back on.
+--
+-- data_schemas, feature_flags and deliveries have no household; they are
+-- configuration and outbound history for the whole tenant, managed by
+-- operators, so tenant isolation is all they need.
+DO $$
+DECLARE
+	rule text[];
+BEGIN
+	FOREACH rule SLICE 1 IN ARRAY ARRAY[
+		['recipe_photos', 'current_app_user() IS NULL OR EXISTS (SELECT 1 FROM recipes r WHERE r.id = recipe_id AND household_visible(r.household_uid, r.user_uid))'],
+		['todo_dependencies', 'current_app_user() IS NULL OR (EXISTS (SELECT 1 FROM todos t WHERE t.uid = todo_uid AND household_visible(t.household_uid, t.user_uid))
+			AND EXISTS (SELECT 1 FROM todos t WHERE t.uid = blocked_by_uid AND household_visible(t.household_uid, t.user_uid)))'],
+		['household_quotas', 'household_visible(household_uid, NULL)'],
+		['entity_locks', 'current_app_user() IS NULL OR entity <> ''notes'' OR EXISTS (SELECT 1 FROM notes n WHERE n.id::text = entity_id AND household_visible(n.household_uid, n.user_uid))']
+	] LOOP
+		EXECUTE format('DROP POLICY IF EXISTS household_isolation ON %I', rule[1]);
+		EXECUTE format('CREATE POLICY household_isolation ON %I AS RESTRICTIVE USING (%s)', rule[1], rule[2]);
+	END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+	tbl text;
+BEGIN
+	FOREACH tbl IN ARRAY ARRAY['recipe_photos', 'todo_dependencies', 'household_quotas', 'entity_locks'] LOOP
+		EXECUTE format('DROP POLICY IF EXISTS household_isolation ON %I', tbl);
+	END LOOP;
+END
+$$;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A lock is only unique within its tenant. Keyed on the entity alone, a
+-- lock in one tenant, which row-level security hides from every other, made
+-- their inserts fail on a row they could neither see nor resolve with ON
+-- CONFLICT.
+ALTER TABLE entity_locks DROP CONSTRAINT IF EXISTS entity_locks_pkey;
+ALTER TABLE entity_locks ADD PRIMARY KEY (tenant_uid, entity, entity_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entity_locks DROP CONSTRAINT IF EXISTS entity_locks_pkey;
+ALTER TABLE entity_locks ADD PRIMARY KEY (entity, entity_id);
+-- +goose StatementEnd
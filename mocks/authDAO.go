@@ -38,6 +38,72 @@ func (_m *MockauthDAO) EXPECT() *MockauthDAO_Expecter {
 	return &MockauthDAO_Expecter{mock: &_m.Mock}
 }
 
+// CreateAPIKey provides a mock function for the type MockauthDAO
+func (_mock *MockauthDAO) CreateAPIKey(ctx context.Context, k postgres.APIKeys) (postgres.APIKeys, error) {
+	ret := _mock.Called(ctx, k)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 postgres.APIKeys
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.APIKeys) (postgres.APIKeys, error)); ok {
+		return returnFunc(ctx, k)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.APIKeys) postgres.APIKeys); ok {
+		r0 = returnFunc(ctx, k)
+	} else {
+		r0 = ret.Get(0).(postgres.APIKeys)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, postgres.APIKeys) error); ok {
+		r1 = returnFunc(ctx, k)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockauthDAO_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type MockauthDAO_CreateAPIKey_Call struct {
+	*mock.Call
+}
+
+// CreateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - k postgres.APIKeys
+func (_e *MockauthDAO_Expecter) CreateAPIKey(ctx interface{}, k interface{}) *MockauthDAO_CreateAPIKey_Call {
+	return &MockauthDAO_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, k)}
+}
+
+func (_c *MockauthDAO_CreateAPIKey_Call) Run(run func(ctx context.Context, k postgres.APIKeys)) *MockauthDAO_CreateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 postgres.APIKeys
+		if args[1] != nil {
+			arg1 = args[1].(postgres.APIKeys)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockauthDAO_CreateAPIKey_Call) Return(aPIKeys postgres.APIKeys, err error) *MockauthDAO_CreateAPIKey_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockauthDAO_CreateAPIKey_Call) RunAndReturn(run func(ctx context.Context, k postgres.APIKeys) (postgres.APIKeys, error)) *MockauthDAO_CreateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateCredentials provides a mock function for the type MockauthDAO
 func (_mock *MockauthDAO) CreateCredentials(ctx context.Context, c postgres.Credentials) (postgres.Credentials, error) {
 	ret := _mock.Called(ctx, c)
@@ -105,8 +171,8 @@ func (_c *MockauthDAO_CreateCredentials_Call) RunAndReturn(run func(ctx context.
 }
 
 // GetCredentialsByUserAndType provides a mock function for the type MockauthDAO
-func (_mock *MockauthDAO) GetCredentialsByUserAndType(ctx context.Context, userID string, credentialType string) (postgres.Credentials, error) {
-	ret := _mock.Called(ctx, userID, credentialType)
+func (_mock *MockauthDAO) GetCredentialsByUserAndType(ctx context.Context, userUID string, credentialType string) (postgres.Credentials, error) {
+	ret := _mock.Called(ctx, userUID, credentialType)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCredentialsByUserAndType")
@@ -115,15 +181,15 @@ func (_mock *MockauthDAO) GetCredentialsByUserAndType(ctx context.Context, userI
 	var r0 postgres.Credentials
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (postgres.Credentials, error)); ok {
-		return returnFunc(ctx, userID, credentialType)
+		return returnFunc(ctx, userUID, credentialType)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) postgres.Credentials); ok {
-		r0 = returnFunc(ctx, userID, credentialType)
+		r0 = returnFunc(ctx, userUID, credentialType)
 	} else {
 		r0 = ret.Get(0).(postgres.Credentials)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = returnFunc(ctx, userID, credentialType)
+		r1 = returnFunc(ctx, userUID, credentialType)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -137,13 +203,13 @@ type MockauthDAO_GetCredentialsByUserAndType_Call struct {
 
 // GetCredentialsByUserAndType is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
+//   - userUID string
 //   - credentialType string
-func (_e *MockauthDAO_Expecter) GetCredentialsByUserAndType(ctx interface{}, userID interface{}, credentialType interface{}) *MockauthDAO_GetCredentialsByUserAndType_Call {
-	return &MockauthDAO_GetCredentialsByUserAndType_Call{Call: _e.mock.On("GetCredentialsByUserAndType", ctx, userID, credentialType)}
+func (_e *MockauthDAO_Expecter) GetCredentialsByUserAndType(ctx interface{}, userUID interface{}, credentialType interface{}) *MockauthDAO_GetCredentialsByUserAndType_Call {
+	return &MockauthDAO_GetCredentialsByUserAndType_Call{Call: _e.mock.On("GetCredentialsByUserAndType", ctx, userUID, credentialType)}
 }
 
-func (_c *MockauthDAO_GetCredentialsByUserAndType_Call) Run(run func(ctx context.Context, userID string, credentialType string)) *MockauthDAO_GetCredentialsByUserAndType_Call {
+func (_c *MockauthDAO_GetCredentialsByUserAndType_Call) Run(run func(ctx context.Context, userUID string, credentialType string)) *MockauthDAO_GetCredentialsByUserAndType_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -171,7 +237,75 @@ func (_c *MockauthDAO_GetCredentialsByUserAndType_Call) Return(credentials postg
 	return _c
 }
 
-func (_c *MockauthDAO_GetCredentialsByUserAndType_Call) RunAndReturn(run func(ctx context.Context, userID string, credentialType string) (postgres.Credentials, error)) *MockauthDAO_GetCredentialsByUserAndType_Call {
+func (_c *MockauthDAO_GetCredentialsByUserAndType_Call) RunAndReturn(run func(ctx context.Context, userUID string, credentialType string) (postgres.Credentials, error)) *MockauthDAO_GetCredentialsByUserAndType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCredentials provides a mock function for the type MockauthDAO
+func (_mock *MockauthDAO) ListCredentials(ctx context.Context, options postgres.ListOptions) ([]postgres.Credentials, error) {
+	ret := _mock.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCredentials")
+	}
+
+	var r0 []postgres.Credentials
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.ListOptions) ([]postgres.Credentials, error)); ok {
+		return returnFunc(ctx, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.ListOptions) []postgres.Credentials); ok {
+		r0 = returnFunc(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.Credentials)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, postgres.ListOptions) error); ok {
+		r1 = returnFunc(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockauthDAO_ListCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCredentials'
+type MockauthDAO_ListCredentials_Call struct {
+	*mock.Call
+}
+
+// ListCredentials is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options postgres.ListOptions
+func (_e *MockauthDAO_Expecter) ListCredentials(ctx interface{}, options interface{}) *MockauthDAO_ListCredentials_Call {
+	return &MockauthDAO_ListCredentials_Call{Call: _e.mock.On("ListCredentials", ctx, options)}
+}
+
+func (_c *MockauthDAO_ListCredentials_Call) Run(run func(ctx context.Context, options postgres.ListOptions)) *MockauthDAO_ListCredentials_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 postgres.ListOptions
+		if args[1] != nil {
+			arg1 = args[1].(postgres.ListOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockauthDAO_ListCredentials_Call) Return(credentialss []postgres.Credentials, err error) *MockauthDAO_ListCredentials_Call {
+	_c.Call.Return(credentialss, err)
+	return _c
+}
+
+func (_c *MockauthDAO_ListCredentials_Call) RunAndReturn(run func(ctx context.Context, options postgres.ListOptions) ([]postgres.Credentials, error)) *MockauthDAO_ListCredentials_Call {
 	_c.Call.Return(run)
 	return _c
 }
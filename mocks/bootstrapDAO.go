@@ -38,47 +38,47 @@ func (_m *MockbootstrapDAO) EXPECT() *MockbootstrapDAO_Expecter {
 	return &MockbootstrapDAO_Expecter{mock: &_m.Mock}
 }
 
-// GetCredentialsByUserID provides a mock function for the type MockbootstrapDAO
-func (_mock *MockbootstrapDAO) GetCredentialsByUserID(ctx context.Context, userID string) ([]postgres.Credentials, error) {
-	ret := _mock.Called(ctx, userID)
+// GetCredentialsByUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetCredentialsByUserUID(ctx context.Context, userUID string) ([]postgres.Credentials, error) {
+	ret := _mock.Called(ctx, userUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCredentialsByUserID")
+		panic("no return value specified for GetCredentialsByUserUID")
 	}
 
 	var r0 []postgres.Credentials
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.Credentials, error)); ok {
-		return returnFunc(ctx, userID)
+		return returnFunc(ctx, userUID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.Credentials); ok {
-		r0 = returnFunc(ctx, userID)
+		r0 = returnFunc(ctx, userUID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]postgres.Credentials)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, userUID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockbootstrapDAO_GetCredentialsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCredentialsByUserID'
-type MockbootstrapDAO_GetCredentialsByUserID_Call struct {
+// MockbootstrapDAO_GetCredentialsByUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCredentialsByUserUID'
+type MockbootstrapDAO_GetCredentialsByUserUID_Call struct {
 	*mock.Call
 }
 
-// GetCredentialsByUserID is a helper method to define mock.On call
+// GetCredentialsByUserUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
-func (_e *MockbootstrapDAO_Expecter) GetCredentialsByUserID(ctx interface{}, userID interface{}) *MockbootstrapDAO_GetCredentialsByUserID_Call {
-	return &MockbootstrapDAO_GetCredentialsByUserID_Call{Call: _e.mock.On("GetCredentialsByUserID", ctx, userID)}
+//   - userUID string
+func (_e *MockbootstrapDAO_Expecter) GetCredentialsByUserUID(ctx interface{}, userUID interface{}) *MockbootstrapDAO_GetCredentialsByUserUID_Call {
+	return &MockbootstrapDAO_GetCredentialsByUserUID_Call{Call: _e.mock.On("GetCredentialsByUserUID", ctx, userUID)}
 }
 
-func (_c *MockbootstrapDAO_GetCredentialsByUserID_Call) Run(run func(ctx context.Context, userID string)) *MockbootstrapDAO_GetCredentialsByUserID_Call {
+func (_c *MockbootstrapDAO_GetCredentialsByUserUID_Call) Run(run func(ctx context.Context, userUID string)) *MockbootstrapDAO_GetCredentialsByUserUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -96,12 +96,12 @@ func (_c *MockbootstrapDAO_GetCredentialsByUserID_Call) Run(run func(ctx context
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetCredentialsByUserID_Call) Return(credentialss []postgres.Credentials, err error) *MockbootstrapDAO_GetCredentialsByUserID_Call {
+func (_c *MockbootstrapDAO_GetCredentialsByUserUID_Call) Return(credentialss []postgres.Credentials, err error) *MockbootstrapDAO_GetCredentialsByUserUID_Call {
 	_c.Call.Return(credentialss, err)
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetCredentialsByUserID_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]postgres.Credentials, error)) *MockbootstrapDAO_GetCredentialsByUserID_Call {
+func (_c *MockbootstrapDAO_GetCredentialsByUserUID_Call) RunAndReturn(run func(ctx context.Context, userUID string) ([]postgres.Credentials, error)) *MockbootstrapDAO_GetCredentialsByUserUID_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -172,47 +172,47 @@ func (_c *MockbootstrapDAO_GetHousehold_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
-// GetNotesByUserID provides a mock function for the type MockbootstrapDAO
-func (_mock *MockbootstrapDAO) GetNotesByUserID(ctx context.Context, userID string) ([]postgres.Notes, error) {
-	ret := _mock.Called(ctx, userID)
+// GetNotesByUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetNotesByUserUID(ctx context.Context, userUID string) ([]postgres.Notes, error) {
+	ret := _mock.Called(ctx, userUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetNotesByUserID")
+		panic("no return value specified for GetNotesByUserUID")
 	}
 
 	var r0 []postgres.Notes
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.Notes, error)); ok {
-		return returnFunc(ctx, userID)
+		return returnFunc(ctx, userUID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.Notes); ok {
-		r0 = returnFunc(ctx, userID)
+		r0 = returnFunc(ctx, userUID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]postgres.Notes)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, userUID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockbootstrapDAO_GetNotesByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotesByUserID'
-type MockbootstrapDAO_GetNotesByUserID_Call struct {
+// MockbootstrapDAO_GetNotesByUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotesByUserUID'
+type MockbootstrapDAO_GetNotesByUserUID_Call struct {
 	*mock.Call
 }
 
-// GetNotesByUserID is a helper method to define mock.On call
+// GetNotesByUserUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
-func (_e *MockbootstrapDAO_Expecter) GetNotesByUserID(ctx interface{}, userID interface{}) *MockbootstrapDAO_GetNotesByUserID_Call {
-	return &MockbootstrapDAO_GetNotesByUserID_Call{Call: _e.mock.On("GetNotesByUserID", ctx, userID)}
+//   - userUID string
+func (_e *MockbootstrapDAO_Expecter) GetNotesByUserUID(ctx interface{}, userUID interface{}) *MockbootstrapDAO_GetNotesByUserUID_Call {
+	return &MockbootstrapDAO_GetNotesByUserUID_Call{Call: _e.mock.On("GetNotesByUserUID", ctx, userUID)}
 }
 
-func (_c *MockbootstrapDAO_GetNotesByUserID_Call) Run(run func(ctx context.Context, userID string)) *MockbootstrapDAO_GetNotesByUserID_Call {
+func (_c *MockbootstrapDAO_GetNotesByUserUID_Call) Run(run func(ctx context.Context, userUID string)) *MockbootstrapDAO_GetNotesByUserUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -230,57 +230,57 @@ func (_c *MockbootstrapDAO_GetNotesByUserID_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetNotesByUserID_Call) Return(notess []postgres.Notes, err error) *MockbootstrapDAO_GetNotesByUserID_Call {
+func (_c *MockbootstrapDAO_GetNotesByUserUID_Call) Return(notess []postgres.Notes, err error) *MockbootstrapDAO_GetNotesByUserUID_Call {
 	_c.Call.Return(notess, err)
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetNotesByUserID_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]postgres.Notes, error)) *MockbootstrapDAO_GetNotesByUserID_Call {
+func (_c *MockbootstrapDAO_GetNotesByUserUID_Call) RunAndReturn(run func(ctx context.Context, userUID string) ([]postgres.Notes, error)) *MockbootstrapDAO_GetNotesByUserUID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPreferencesByUserID provides a mock function for the type MockbootstrapDAO
-func (_mock *MockbootstrapDAO) GetPreferencesByUserID(ctx context.Context, userID string) ([]postgres.Preferences, error) {
-	ret := _mock.Called(ctx, userID)
+// GetPreferencesByUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetPreferencesByUserUID(ctx context.Context, userUID string) ([]postgres.Preferences, error) {
+	ret := _mock.Called(ctx, userUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPreferencesByUserID")
+		panic("no return value specified for GetPreferencesByUserUID")
 	}
 
 	var r0 []postgres.Preferences
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.Preferences, error)); ok {
-		return returnFunc(ctx, userID)
+		return returnFunc(ctx, userUID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.Preferences); ok {
-		r0 = returnFunc(ctx, userID)
+		r0 = returnFunc(ctx, userUID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]postgres.Preferences)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, userUID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockbootstrapDAO_GetPreferencesByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPreferencesByUserID'
-type MockbootstrapDAO_GetPreferencesByUserID_Call struct {
+// MockbootstrapDAO_GetPreferencesByUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPreferencesByUserUID'
+type MockbootstrapDAO_GetPreferencesByUserUID_Call struct {
 	*mock.Call
 }
 
-// GetPreferencesByUserID is a helper method to define mock.On call
+// GetPreferencesByUserUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
-func (_e *MockbootstrapDAO_Expecter) GetPreferencesByUserID(ctx interface{}, userID interface{}) *MockbootstrapDAO_GetPreferencesByUserID_Call {
-	return &MockbootstrapDAO_GetPreferencesByUserID_Call{Call: _e.mock.On("GetPreferencesByUserID", ctx, userID)}
+//   - userUID string
+func (_e *MockbootstrapDAO_Expecter) GetPreferencesByUserUID(ctx interface{}, userUID interface{}) *MockbootstrapDAO_GetPreferencesByUserUID_Call {
+	return &MockbootstrapDAO_GetPreferencesByUserUID_Call{Call: _e.mock.On("GetPreferencesByUserUID", ctx, userUID)}
 }
 
-func (_c *MockbootstrapDAO_GetPreferencesByUserID_Call) Run(run func(ctx context.Context, userID string)) *MockbootstrapDAO_GetPreferencesByUserID_Call {
+func (_c *MockbootstrapDAO_GetPreferencesByUserUID_Call) Run(run func(ctx context.Context, userUID string)) *MockbootstrapDAO_GetPreferencesByUserUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -298,57 +298,125 @@ func (_c *MockbootstrapDAO_GetPreferencesByUserID_Call) Run(run func(ctx context
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetPreferencesByUserID_Call) Return(preferencess []postgres.Preferences, err error) *MockbootstrapDAO_GetPreferencesByUserID_Call {
+func (_c *MockbootstrapDAO_GetPreferencesByUserUID_Call) Return(preferencess []postgres.Preferences, err error) *MockbootstrapDAO_GetPreferencesByUserUID_Call {
 	_c.Call.Return(preferencess, err)
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetPreferencesByUserID_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]postgres.Preferences, error)) *MockbootstrapDAO_GetPreferencesByUserID_Call {
+func (_c *MockbootstrapDAO_GetPreferencesByUserUID_Call) RunAndReturn(run func(ctx context.Context, userUID string) ([]postgres.Preferences, error)) *MockbootstrapDAO_GetPreferencesByUserUID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTodosByUserID provides a mock function for the type MockbootstrapDAO
-func (_mock *MockbootstrapDAO) GetTodosByUserID(ctx context.Context, userID string) ([]postgres.Todo, error) {
-	ret := _mock.Called(ctx, userID)
+// GetRecipesByUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetRecipesByUserUID(ctx context.Context, userUID string) ([]postgres.Recipes, error) {
+	ret := _mock.Called(ctx, userUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTodosByUserID")
+		panic("no return value specified for GetRecipesByUserUID")
+	}
+
+	var r0 []postgres.Recipes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.Recipes, error)); ok {
+		return returnFunc(ctx, userUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.Recipes); ok {
+		r0 = returnFunc(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.Recipes)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockbootstrapDAO_GetRecipesByUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipesByUserUID'
+type MockbootstrapDAO_GetRecipesByUserUID_Call struct {
+	*mock.Call
+}
+
+// GetRecipesByUserUID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userUID string
+func (_e *MockbootstrapDAO_Expecter) GetRecipesByUserUID(ctx interface{}, userUID interface{}) *MockbootstrapDAO_GetRecipesByUserUID_Call {
+	return &MockbootstrapDAO_GetRecipesByUserUID_Call{Call: _e.mock.On("GetRecipesByUserUID", ctx, userUID)}
+}
+
+func (_c *MockbootstrapDAO_GetRecipesByUserUID_Call) Run(run func(ctx context.Context, userUID string)) *MockbootstrapDAO_GetRecipesByUserUID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetRecipesByUserUID_Call) Return(recipess []postgres.Recipes, err error) *MockbootstrapDAO_GetRecipesByUserUID_Call {
+	_c.Call.Return(recipess, err)
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetRecipesByUserUID_Call) RunAndReturn(run func(ctx context.Context, userUID string) ([]postgres.Recipes, error)) *MockbootstrapDAO_GetRecipesByUserUID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTodosByUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetTodosByUserUID(ctx context.Context, userUID string) ([]postgres.Todo, error) {
+	ret := _mock.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTodosByUserUID")
 	}
 
 	var r0 []postgres.Todo
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.Todo, error)); ok {
-		return returnFunc(ctx, userID)
+		return returnFunc(ctx, userUID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.Todo); ok {
-		r0 = returnFunc(ctx, userID)
+		r0 = returnFunc(ctx, userUID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]postgres.Todo)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, userUID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockbootstrapDAO_GetTodosByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTodosByUserID'
-type MockbootstrapDAO_GetTodosByUserID_Call struct {
+// MockbootstrapDAO_GetTodosByUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTodosByUserUID'
+type MockbootstrapDAO_GetTodosByUserUID_Call struct {
 	*mock.Call
 }
 
-// GetTodosByUserID is a helper method to define mock.On call
+// GetTodosByUserUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
-func (_e *MockbootstrapDAO_Expecter) GetTodosByUserID(ctx interface{}, userID interface{}) *MockbootstrapDAO_GetTodosByUserID_Call {
-	return &MockbootstrapDAO_GetTodosByUserID_Call{Call: _e.mock.On("GetTodosByUserID", ctx, userID)}
+//   - userUID string
+func (_e *MockbootstrapDAO_Expecter) GetTodosByUserUID(ctx interface{}, userUID interface{}) *MockbootstrapDAO_GetTodosByUserUID_Call {
+	return &MockbootstrapDAO_GetTodosByUserUID_Call{Call: _e.mock.On("GetTodosByUserUID", ctx, userUID)}
 }
 
-func (_c *MockbootstrapDAO_GetTodosByUserID_Call) Run(run func(ctx context.Context, userID string)) *MockbootstrapDAO_GetTodosByUserID_Call {
+func (_c *MockbootstrapDAO_GetTodosByUserUID_Call) Run(run func(ctx context.Context, userUID string)) *MockbootstrapDAO_GetTodosByUserUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -366,55 +434,195 @@ func (_c *MockbootstrapDAO_GetTodosByUserID_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetTodosByUserID_Call) Return(todos []postgres.Todo, err error) *MockbootstrapDAO_GetTodosByUserID_Call {
+func (_c *MockbootstrapDAO_GetTodosByUserUID_Call) Return(todos []postgres.Todo, err error) *MockbootstrapDAO_GetTodosByUserUID_Call {
 	_c.Call.Return(todos, err)
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetTodosByUserID_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]postgres.Todo, error)) *MockbootstrapDAO_GetTodosByUserID_Call {
+func (_c *MockbootstrapDAO_GetTodosByUserUID_Call) RunAndReturn(run func(ctx context.Context, userUID string) ([]postgres.Todo, error)) *MockbootstrapDAO_GetTodosByUserUID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetToolPoliciesForUser provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetToolPoliciesForUser(ctx context.Context, userUID string, householdUID *string) ([]postgres.ToolPolicy, error) {
+	ret := _mock.Called(ctx, userUID, householdUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetToolPoliciesForUser")
+	}
+
+	var r0 []postgres.ToolPolicy
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *string) ([]postgres.ToolPolicy, error)); ok {
+		return returnFunc(ctx, userUID, householdUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *string) []postgres.ToolPolicy); ok {
+		r0 = returnFunc(ctx, userUID, householdUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.ToolPolicy)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *string) error); ok {
+		r1 = returnFunc(ctx, userUID, householdUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockbootstrapDAO_GetToolPoliciesForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetToolPoliciesForUser'
+type MockbootstrapDAO_GetToolPoliciesForUser_Call struct {
+	*mock.Call
+}
+
+// GetToolPoliciesForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userUID string
+//   - householdUID *string
+func (_e *MockbootstrapDAO_Expecter) GetToolPoliciesForUser(ctx interface{}, userUID interface{}, householdUID interface{}) *MockbootstrapDAO_GetToolPoliciesForUser_Call {
+	return &MockbootstrapDAO_GetToolPoliciesForUser_Call{Call: _e.mock.On("GetToolPoliciesForUser", ctx, userUID, householdUID)}
+}
+
+func (_c *MockbootstrapDAO_GetToolPoliciesForUser_Call) Run(run func(ctx context.Context, userUID string, householdUID *string)) *MockbootstrapDAO_GetToolPoliciesForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *string
+		if args[2] != nil {
+			arg2 = args[2].(*string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetToolPoliciesForUser_Call) Return(toolPolicys []postgres.ToolPolicy, err error) *MockbootstrapDAO_GetToolPoliciesForUser_Call {
+	_c.Call.Return(toolPolicys, err)
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetToolPoliciesForUser_Call) RunAndReturn(run func(ctx context.Context, userUID string, householdUID *string) ([]postgres.ToolPolicy, error)) *MockbootstrapDAO_GetToolPoliciesForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUser provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetUser(ctx context.Context, uid string) (postgres.Users, error) {
+	ret := _mock.Called(ctx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 postgres.Users
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (postgres.Users, error)); ok {
+		return returnFunc(ctx, uid)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) postgres.Users); ok {
+		r0 = returnFunc(ctx, uid)
+	} else {
+		r0 = ret.Get(0).(postgres.Users)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockbootstrapDAO_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockbootstrapDAO_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - uid string
+func (_e *MockbootstrapDAO_Expecter) GetUser(ctx interface{}, uid interface{}) *MockbootstrapDAO_GetUser_Call {
+	return &MockbootstrapDAO_GetUser_Call{Call: _e.mock.On("GetUser", ctx, uid)}
+}
+
+func (_c *MockbootstrapDAO_GetUser_Call) Run(run func(ctx context.Context, uid string)) *MockbootstrapDAO_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetUser_Call) Return(users postgres.Users, err error) *MockbootstrapDAO_GetUser_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockbootstrapDAO_GetUser_Call) RunAndReturn(run func(ctx context.Context, uid string) (postgres.Users, error)) *MockbootstrapDAO_GetUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserBySlackUserID provides a mock function for the type MockbootstrapDAO
-func (_mock *MockbootstrapDAO) GetUserBySlackUserID(ctx context.Context, slackUserID string) (postgres.Users, error) {
-	ret := _mock.Called(ctx, slackUserID)
+// GetUserBySlackUserUID provides a mock function for the type MockbootstrapDAO
+func (_mock *MockbootstrapDAO) GetUserBySlackUserUID(ctx context.Context, slackUserUID string) (postgres.Users, error) {
+	ret := _mock.Called(ctx, slackUserUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserBySlackUserID")
+		panic("no return value specified for GetUserBySlackUserUID")
 	}
 
 	var r0 postgres.Users
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (postgres.Users, error)); ok {
-		return returnFunc(ctx, slackUserID)
+		return returnFunc(ctx, slackUserUID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) postgres.Users); ok {
-		r0 = returnFunc(ctx, slackUserID)
+		r0 = returnFunc(ctx, slackUserUID)
 	} else {
 		r0 = ret.Get(0).(postgres.Users)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, slackUserID)
+		r1 = returnFunc(ctx, slackUserUID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockbootstrapDAO_GetUserBySlackUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBySlackUserID'
-type MockbootstrapDAO_GetUserBySlackUserID_Call struct {
+// MockbootstrapDAO_GetUserBySlackUserUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserBySlackUserUID'
+type MockbootstrapDAO_GetUserBySlackUserUID_Call struct {
 	*mock.Call
 }
 
-// GetUserBySlackUserID is a helper method to define mock.On call
+// GetUserBySlackUserUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - slackUserID string
-func (_e *MockbootstrapDAO_Expecter) GetUserBySlackUserID(ctx interface{}, slackUserID interface{}) *MockbootstrapDAO_GetUserBySlackUserID_Call {
-	return &MockbootstrapDAO_GetUserBySlackUserID_Call{Call: _e.mock.On("GetUserBySlackUserID", ctx, slackUserID)}
+//   - slackUserUID string
+func (_e *MockbootstrapDAO_Expecter) GetUserBySlackUserUID(ctx interface{}, slackUserUID interface{}) *MockbootstrapDAO_GetUserBySlackUserUID_Call {
+	return &MockbootstrapDAO_GetUserBySlackUserUID_Call{Call: _e.mock.On("GetUserBySlackUserUID", ctx, slackUserUID)}
 }
 
-func (_c *MockbootstrapDAO_GetUserBySlackUserID_Call) Run(run func(ctx context.Context, slackUserID string)) *MockbootstrapDAO_GetUserBySlackUserID_Call {
+func (_c *MockbootstrapDAO_GetUserBySlackUserUID_Call) Run(run func(ctx context.Context, slackUserUID string)) *MockbootstrapDAO_GetUserBySlackUserUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -432,12 +640,12 @@ func (_c *MockbootstrapDAO_GetUserBySlackUserID_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetUserBySlackUserID_Call) Return(users postgres.Users, err error) *MockbootstrapDAO_GetUserBySlackUserID_Call {
+func (_c *MockbootstrapDAO_GetUserBySlackUserUID_Call) Return(users postgres.Users, err error) *MockbootstrapDAO_GetUserBySlackUserUID_Call {
 	_c.Call.Return(users, err)
 	return _c
 }
 
-func (_c *MockbootstrapDAO_GetUserBySlackUserID_Call) RunAndReturn(run func(ctx context.Context, slackUserID string) (postgres.Users, error)) *MockbootstrapDAO_GetUserBySlackUserID_Call {
+func (_c *MockbootstrapDAO_GetUserBySlackUserUID_Call) RunAndReturn(run func(ctx context.Context, slackUserUID string) (postgres.Users, error)) *MockbootstrapDAO_GetUserBySlackUserUID_Call {
 	_c.Call.Return(run)
 	return _c
 }
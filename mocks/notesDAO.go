@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/pbdeuchler/assistant-server/dao/postgres"
 	mock "github.com/stretchr/testify/mock"
@@ -295,6 +296,156 @@ func (_c *MocknotesDAO_ListNotes_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// SetNotePinned provides a mock function for the type MocknotesDAO
+func (_mock *MocknotesDAO) SetNotePinned(ctx context.Context, id string, pinned bool, sortOrder int) (postgres.Notes, error) {
+	ret := _mock.Called(ctx, id, pinned, sortOrder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNotePinned")
+	}
+
+	var r0 postgres.Notes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool, int) (postgres.Notes, error)); ok {
+		return returnFunc(ctx, id, pinned, sortOrder)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool, int) postgres.Notes); ok {
+		r0 = returnFunc(ctx, id, pinned, sortOrder)
+	} else {
+		r0 = ret.Get(0).(postgres.Notes)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, bool, int) error); ok {
+		r1 = returnFunc(ctx, id, pinned, sortOrder)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocknotesDAO_SetNotePinned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNotePinned'
+type MocknotesDAO_SetNotePinned_Call struct {
+	*mock.Call
+}
+
+// SetNotePinned is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - pinned bool
+//   - sortOrder int
+func (_e *MocknotesDAO_Expecter) SetNotePinned(ctx interface{}, id interface{}, pinned interface{}, sortOrder interface{}) *MocknotesDAO_SetNotePinned_Call {
+	return &MocknotesDAO_SetNotePinned_Call{Call: _e.mock.On("SetNotePinned", ctx, id, pinned, sortOrder)}
+}
+
+func (_c *MocknotesDAO_SetNotePinned_Call) Run(run func(ctx context.Context, id string, pinned bool, sortOrder int)) *MocknotesDAO_SetNotePinned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MocknotesDAO_SetNotePinned_Call) Return(notes postgres.Notes, err error) *MocknotesDAO_SetNotePinned_Call {
+	_c.Call.Return(notes, err)
+	return _c
+}
+
+func (_c *MocknotesDAO_SetNotePinned_Call) RunAndReturn(run func(ctx context.Context, id string, pinned bool, sortOrder int) (postgres.Notes, error)) *MocknotesDAO_SetNotePinned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetNoteReminder provides a mock function for the type MocknotesDAO
+func (_mock *MocknotesDAO) SetNoteReminder(ctx context.Context, id string, remindAt *time.Time) (postgres.Notes, error) {
+	ret := _mock.Called(ctx, id, remindAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNoteReminder")
+	}
+
+	var r0 postgres.Notes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *time.Time) (postgres.Notes, error)); ok {
+		return returnFunc(ctx, id, remindAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *time.Time) postgres.Notes); ok {
+		r0 = returnFunc(ctx, id, remindAt)
+	} else {
+		r0 = ret.Get(0).(postgres.Notes)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *time.Time) error); ok {
+		r1 = returnFunc(ctx, id, remindAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocknotesDAO_SetNoteReminder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNoteReminder'
+type MocknotesDAO_SetNoteReminder_Call struct {
+	*mock.Call
+}
+
+// SetNoteReminder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - remindAt *time.Time
+func (_e *MocknotesDAO_Expecter) SetNoteReminder(ctx interface{}, id interface{}, remindAt interface{}) *MocknotesDAO_SetNoteReminder_Call {
+	return &MocknotesDAO_SetNoteReminder_Call{Call: _e.mock.On("SetNoteReminder", ctx, id, remindAt)}
+}
+
+func (_c *MocknotesDAO_SetNoteReminder_Call) Run(run func(ctx context.Context, id string, remindAt *time.Time)) *MocknotesDAO_SetNoteReminder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MocknotesDAO_SetNoteReminder_Call) Return(notes postgres.Notes, err error) *MocknotesDAO_SetNoteReminder_Call {
+	_c.Call.Return(notes, err)
+	return _c
+}
+
+func (_c *MocknotesDAO_SetNoteReminder_Call) RunAndReturn(run func(ctx context.Context, id string, remindAt *time.Time) (postgres.Notes, error)) *MocknotesDAO_SetNoteReminder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateNotes provides a mock function for the type MocknotesDAO
 func (_mock *MocknotesDAO) UpdateNotes(ctx context.Context, id string, n postgres.Notes) (postgres.Notes, error) {
 	ret := _mock.Called(ctx, id, n)
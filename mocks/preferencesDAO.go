@@ -239,6 +239,74 @@ func (_c *MockpreferencesDAO_GetPreferences_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// GetPreferencesBulk provides a mock function for the type MockpreferencesDAO
+func (_mock *MockpreferencesDAO) GetPreferencesBulk(ctx context.Context, keys []postgres.PreferenceKey) ([]postgres.Preferences, error) {
+	ret := _mock.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreferencesBulk")
+	}
+
+	var r0 []postgres.Preferences
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []postgres.PreferenceKey) ([]postgres.Preferences, error)); ok {
+		return returnFunc(ctx, keys)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []postgres.PreferenceKey) []postgres.Preferences); ok {
+		r0 = returnFunc(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.Preferences)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []postgres.PreferenceKey) error); ok {
+		r1 = returnFunc(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockpreferencesDAO_GetPreferencesBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPreferencesBulk'
+type MockpreferencesDAO_GetPreferencesBulk_Call struct {
+	*mock.Call
+}
+
+// GetPreferencesBulk is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []postgres.PreferenceKey
+func (_e *MockpreferencesDAO_Expecter) GetPreferencesBulk(ctx interface{}, keys interface{}) *MockpreferencesDAO_GetPreferencesBulk_Call {
+	return &MockpreferencesDAO_GetPreferencesBulk_Call{Call: _e.mock.On("GetPreferencesBulk", ctx, keys)}
+}
+
+func (_c *MockpreferencesDAO_GetPreferencesBulk_Call) Run(run func(ctx context.Context, keys []postgres.PreferenceKey)) *MockpreferencesDAO_GetPreferencesBulk_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []postgres.PreferenceKey
+		if args[1] != nil {
+			arg1 = args[1].([]postgres.PreferenceKey)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockpreferencesDAO_GetPreferencesBulk_Call) Return(preferencess []postgres.Preferences, err error) *MockpreferencesDAO_GetPreferencesBulk_Call {
+	_c.Call.Return(preferencess, err)
+	return _c
+}
+
+func (_c *MockpreferencesDAO_GetPreferencesBulk_Call) RunAndReturn(run func(ctx context.Context, keys []postgres.PreferenceKey) ([]postgres.Preferences, error)) *MockpreferencesDAO_GetPreferencesBulk_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListPreferences provides a mock function for the type MockpreferencesDAO
 func (_mock *MockpreferencesDAO) ListPreferences(ctx context.Context, options postgres.ListOptions) ([]postgres.Preferences, error) {
 	ret := _mock.Called(ctx, options)
@@ -307,6 +375,74 @@ func (_c *MockpreferencesDAO_ListPreferences_Call) RunAndReturn(run func(ctx con
 	return _c
 }
 
+// SetPreferencesBulk provides a mock function for the type MockpreferencesDAO
+func (_mock *MockpreferencesDAO) SetPreferencesBulk(ctx context.Context, prefs []postgres.Preferences) ([]postgres.Preferences, error) {
+	ret := _mock.Called(ctx, prefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPreferencesBulk")
+	}
+
+	var r0 []postgres.Preferences
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []postgres.Preferences) ([]postgres.Preferences, error)); ok {
+		return returnFunc(ctx, prefs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []postgres.Preferences) []postgres.Preferences); ok {
+		r0 = returnFunc(ctx, prefs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.Preferences)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []postgres.Preferences) error); ok {
+		r1 = returnFunc(ctx, prefs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockpreferencesDAO_SetPreferencesBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPreferencesBulk'
+type MockpreferencesDAO_SetPreferencesBulk_Call struct {
+	*mock.Call
+}
+
+// SetPreferencesBulk is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefs []postgres.Preferences
+func (_e *MockpreferencesDAO_Expecter) SetPreferencesBulk(ctx interface{}, prefs interface{}) *MockpreferencesDAO_SetPreferencesBulk_Call {
+	return &MockpreferencesDAO_SetPreferencesBulk_Call{Call: _e.mock.On("SetPreferencesBulk", ctx, prefs)}
+}
+
+func (_c *MockpreferencesDAO_SetPreferencesBulk_Call) Run(run func(ctx context.Context, prefs []postgres.Preferences)) *MockpreferencesDAO_SetPreferencesBulk_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []postgres.Preferences
+		if args[1] != nil {
+			arg1 = args[1].([]postgres.Preferences)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockpreferencesDAO_SetPreferencesBulk_Call) Return(preferencess []postgres.Preferences, err error) *MockpreferencesDAO_SetPreferencesBulk_Call {
+	_c.Call.Return(preferencess, err)
+	return _c
+}
+
+func (_c *MockpreferencesDAO_SetPreferencesBulk_Call) RunAndReturn(run func(ctx context.Context, prefs []postgres.Preferences) ([]postgres.Preferences, error)) *MockpreferencesDAO_SetPreferencesBulk_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdatePreferences provides a mock function for the type MockpreferencesDAO
 func (_mock *MockpreferencesDAO) UpdatePreferences(ctx context.Context, key string, specifier string, p postgres.Preferences) (postgres.Preferences, error) {
 	ret := _mock.Called(ctx, key, specifier, p)
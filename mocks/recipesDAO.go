@@ -104,6 +104,63 @@ func (_c *MockrecipesDAO_CreateRecipes_Call) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
+// DeleteRecipePhoto provides a mock function for the type MockrecipesDAO
+func (_mock *MockrecipesDAO) DeleteRecipePhoto(ctx context.Context, recipeID string) error {
+	ret := _mock.Called(ctx, recipeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRecipePhoto")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, recipeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockrecipesDAO_DeleteRecipePhoto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRecipePhoto'
+type MockrecipesDAO_DeleteRecipePhoto_Call struct {
+	*mock.Call
+}
+
+// DeleteRecipePhoto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID string
+func (_e *MockrecipesDAO_Expecter) DeleteRecipePhoto(ctx interface{}, recipeID interface{}) *MockrecipesDAO_DeleteRecipePhoto_Call {
+	return &MockrecipesDAO_DeleteRecipePhoto_Call{Call: _e.mock.On("DeleteRecipePhoto", ctx, recipeID)}
+}
+
+func (_c *MockrecipesDAO_DeleteRecipePhoto_Call) Run(run func(ctx context.Context, recipeID string)) *MockrecipesDAO_DeleteRecipePhoto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockrecipesDAO_DeleteRecipePhoto_Call) Return(err error) *MockrecipesDAO_DeleteRecipePhoto_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockrecipesDAO_DeleteRecipePhoto_Call) RunAndReturn(run func(ctx context.Context, recipeID string) error) *MockrecipesDAO_DeleteRecipePhoto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteRecipes provides a mock function for the type MockrecipesDAO
 func (_mock *MockrecipesDAO) DeleteRecipes(ctx context.Context, id string) error {
 	ret := _mock.Called(ctx, id)
@@ -161,6 +218,78 @@ func (_c *MockrecipesDAO_DeleteRecipes_Call) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
+// GetRecipePhoto provides a mock function for the type MockrecipesDAO
+func (_mock *MockrecipesDAO) GetRecipePhoto(ctx context.Context, recipeID string, size string) (postgres.RecipePhoto, error) {
+	ret := _mock.Called(ctx, recipeID, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecipePhoto")
+	}
+
+	var r0 postgres.RecipePhoto
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (postgres.RecipePhoto, error)); ok {
+		return returnFunc(ctx, recipeID, size)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) postgres.RecipePhoto); ok {
+		r0 = returnFunc(ctx, recipeID, size)
+	} else {
+		r0 = ret.Get(0).(postgres.RecipePhoto)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, recipeID, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockrecipesDAO_GetRecipePhoto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipePhoto'
+type MockrecipesDAO_GetRecipePhoto_Call struct {
+	*mock.Call
+}
+
+// GetRecipePhoto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID string
+//   - size string
+func (_e *MockrecipesDAO_Expecter) GetRecipePhoto(ctx interface{}, recipeID interface{}, size interface{}) *MockrecipesDAO_GetRecipePhoto_Call {
+	return &MockrecipesDAO_GetRecipePhoto_Call{Call: _e.mock.On("GetRecipePhoto", ctx, recipeID, size)}
+}
+
+func (_c *MockrecipesDAO_GetRecipePhoto_Call) Run(run func(ctx context.Context, recipeID string, size string)) *MockrecipesDAO_GetRecipePhoto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockrecipesDAO_GetRecipePhoto_Call) Return(recipePhoto postgres.RecipePhoto, err error) *MockrecipesDAO_GetRecipePhoto_Call {
+	_c.Call.Return(recipePhoto, err)
+	return _c
+}
+
+func (_c *MockrecipesDAO_GetRecipePhoto_Call) RunAndReturn(run func(ctx context.Context, recipeID string, size string) (postgres.RecipePhoto, error)) *MockrecipesDAO_GetRecipePhoto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetRecipes provides a mock function for the type MockrecipesDAO
 func (_mock *MockrecipesDAO) GetRecipes(ctx context.Context, id string) (postgres.Recipes, error) {
 	ret := _mock.Called(ctx, id)
@@ -295,6 +424,156 @@ func (_c *MockrecipesDAO_ListRecipes_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// RateRecipe provides a mock function for the type MockrecipesDAO
+func (_mock *MockrecipesDAO) RateRecipe(ctx context.Context, recipeID string, userUID string, rating int) (postgres.Recipes, error) {
+	ret := _mock.Called(ctx, recipeID, userUID, rating)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RateRecipe")
+	}
+
+	var r0 postgres.Recipes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) (postgres.Recipes, error)); ok {
+		return returnFunc(ctx, recipeID, userUID, rating)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) postgres.Recipes); ok {
+		r0 = returnFunc(ctx, recipeID, userUID, rating)
+	} else {
+		r0 = ret.Get(0).(postgres.Recipes)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = returnFunc(ctx, recipeID, userUID, rating)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockrecipesDAO_RateRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RateRecipe'
+type MockrecipesDAO_RateRecipe_Call struct {
+	*mock.Call
+}
+
+// RateRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID string
+//   - userUID string
+//   - rating int
+func (_e *MockrecipesDAO_Expecter) RateRecipe(ctx interface{}, recipeID interface{}, userUID interface{}, rating interface{}) *MockrecipesDAO_RateRecipe_Call {
+	return &MockrecipesDAO_RateRecipe_Call{Call: _e.mock.On("RateRecipe", ctx, recipeID, userUID, rating)}
+}
+
+func (_c *MockrecipesDAO_RateRecipe_Call) Run(run func(ctx context.Context, recipeID string, userUID string, rating int)) *MockrecipesDAO_RateRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockrecipesDAO_RateRecipe_Call) Return(recipes postgres.Recipes, err error) *MockrecipesDAO_RateRecipe_Call {
+	_c.Call.Return(recipes, err)
+	return _c
+}
+
+func (_c *MockrecipesDAO_RateRecipe_Call) RunAndReturn(run func(ctx context.Context, recipeID string, userUID string, rating int) (postgres.Recipes, error)) *MockrecipesDAO_RateRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRecipePhoto provides a mock function for the type MockrecipesDAO
+func (_mock *MockrecipesDAO) SetRecipePhoto(ctx context.Context, recipeID string, photos []postgres.RecipePhoto) (postgres.Recipes, error) {
+	ret := _mock.Called(ctx, recipeID, photos)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRecipePhoto")
+	}
+
+	var r0 postgres.Recipes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []postgres.RecipePhoto) (postgres.Recipes, error)); ok {
+		return returnFunc(ctx, recipeID, photos)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []postgres.RecipePhoto) postgres.Recipes); ok {
+		r0 = returnFunc(ctx, recipeID, photos)
+	} else {
+		r0 = ret.Get(0).(postgres.Recipes)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []postgres.RecipePhoto) error); ok {
+		r1 = returnFunc(ctx, recipeID, photos)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockrecipesDAO_SetRecipePhoto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRecipePhoto'
+type MockrecipesDAO_SetRecipePhoto_Call struct {
+	*mock.Call
+}
+
+// SetRecipePhoto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID string
+//   - photos []postgres.RecipePhoto
+func (_e *MockrecipesDAO_Expecter) SetRecipePhoto(ctx interface{}, recipeID interface{}, photos interface{}) *MockrecipesDAO_SetRecipePhoto_Call {
+	return &MockrecipesDAO_SetRecipePhoto_Call{Call: _e.mock.On("SetRecipePhoto", ctx, recipeID, photos)}
+}
+
+func (_c *MockrecipesDAO_SetRecipePhoto_Call) Run(run func(ctx context.Context, recipeID string, photos []postgres.RecipePhoto)) *MockrecipesDAO_SetRecipePhoto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []postgres.RecipePhoto
+		if args[2] != nil {
+			arg2 = args[2].([]postgres.RecipePhoto)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockrecipesDAO_SetRecipePhoto_Call) Return(recipes postgres.Recipes, err error) *MockrecipesDAO_SetRecipePhoto_Call {
+	_c.Call.Return(recipes, err)
+	return _c
+}
+
+func (_c *MockrecipesDAO_SetRecipePhoto_Call) RunAndReturn(run func(ctx context.Context, recipeID string, photos []postgres.RecipePhoto) (postgres.Recipes, error)) *MockrecipesDAO_SetRecipePhoto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateRecipes provides a mock function for the type MockrecipesDAO
 func (_mock *MockrecipesDAO) UpdateRecipes(ctx context.Context, id string, r postgres.Recipes) (postgres.Recipes, error) {
 	ret := _mock.Called(ctx, id, r)
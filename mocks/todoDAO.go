@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/pbdeuchler/assistant-server/dao/postgres"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,69 @@ func (_m *MocktodoDAO) EXPECT() *MocktodoDAO_Expecter {
 	return &MocktodoDAO_Expecter{mock: &_m.Mock}
 }
 
+// AddTodoDependency provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) AddTodoDependency(ctx context.Context, todoUID string, blockedByUID string) error {
+	ret := _mock.Called(ctx, todoUID, blockedByUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTodoDependency")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, todoUID, blockedByUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MocktodoDAO_AddTodoDependency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddTodoDependency'
+type MocktodoDAO_AddTodoDependency_Call struct {
+	*mock.Call
+}
+
+// AddTodoDependency is a helper method to define mock.On call
+//   - ctx context.Context
+//   - todoUID string
+//   - blockedByUID string
+func (_e *MocktodoDAO_Expecter) AddTodoDependency(ctx interface{}, todoUID interface{}, blockedByUID interface{}) *MocktodoDAO_AddTodoDependency_Call {
+	return &MocktodoDAO_AddTodoDependency_Call{Call: _e.mock.On("AddTodoDependency", ctx, todoUID, blockedByUID)}
+}
+
+func (_c *MocktodoDAO_AddTodoDependency_Call) Run(run func(ctx context.Context, todoUID string, blockedByUID string)) *MocktodoDAO_AddTodoDependency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_AddTodoDependency_Call) Return(err error) *MocktodoDAO_AddTodoDependency_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MocktodoDAO_AddTodoDependency_Call) RunAndReturn(run func(ctx context.Context, todoUID string, blockedByUID string) error) *MocktodoDAO_AddTodoDependency_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateTodo provides a mock function for the type MocktodoDAO
 func (_mock *MocktodoDAO) CreateTodo(ctx context.Context, t postgres.Todo) (postgres.Todo, error) {
 	ret := _mock.Called(ctx, t)
@@ -227,6 +291,142 @@ func (_c *MocktodoDAO_GetTodo_Call) RunAndReturn(run func(ctx context.Context, u
 	return _c
 }
 
+// ListTodoDependencies provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) ListTodoDependencies(ctx context.Context, todoUID string) ([]postgres.TodoDependency, error) {
+	ret := _mock.Called(ctx, todoUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTodoDependencies")
+	}
+
+	var r0 []postgres.TodoDependency
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.TodoDependency, error)); ok {
+		return returnFunc(ctx, todoUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.TodoDependency); ok {
+		r0 = returnFunc(ctx, todoUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.TodoDependency)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, todoUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocktodoDAO_ListTodoDependencies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTodoDependencies'
+type MocktodoDAO_ListTodoDependencies_Call struct {
+	*mock.Call
+}
+
+// ListTodoDependencies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - todoUID string
+func (_e *MocktodoDAO_Expecter) ListTodoDependencies(ctx interface{}, todoUID interface{}) *MocktodoDAO_ListTodoDependencies_Call {
+	return &MocktodoDAO_ListTodoDependencies_Call{Call: _e.mock.On("ListTodoDependencies", ctx, todoUID)}
+}
+
+func (_c *MocktodoDAO_ListTodoDependencies_Call) Run(run func(ctx context.Context, todoUID string)) *MocktodoDAO_ListTodoDependencies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_ListTodoDependencies_Call) Return(todoDependencys []postgres.TodoDependency, err error) *MocktodoDAO_ListTodoDependencies_Call {
+	_c.Call.Return(todoDependencys, err)
+	return _c
+}
+
+func (_c *MocktodoDAO_ListTodoDependencies_Call) RunAndReturn(run func(ctx context.Context, todoUID string) ([]postgres.TodoDependency, error)) *MocktodoDAO_ListTodoDependencies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTodoTimeLogs provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) ListTodoTimeLogs(ctx context.Context, todoUID string) ([]postgres.TodoTimeLog, error) {
+	ret := _mock.Called(ctx, todoUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTodoTimeLogs")
+	}
+
+	var r0 []postgres.TodoTimeLog
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]postgres.TodoTimeLog, error)); ok {
+		return returnFunc(ctx, todoUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []postgres.TodoTimeLog); ok {
+		r0 = returnFunc(ctx, todoUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.TodoTimeLog)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, todoUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocktodoDAO_ListTodoTimeLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTodoTimeLogs'
+type MocktodoDAO_ListTodoTimeLogs_Call struct {
+	*mock.Call
+}
+
+// ListTodoTimeLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - todoUID string
+func (_e *MocktodoDAO_Expecter) ListTodoTimeLogs(ctx interface{}, todoUID interface{}) *MocktodoDAO_ListTodoTimeLogs_Call {
+	return &MocktodoDAO_ListTodoTimeLogs_Call{Call: _e.mock.On("ListTodoTimeLogs", ctx, todoUID)}
+}
+
+func (_c *MocktodoDAO_ListTodoTimeLogs_Call) Run(run func(ctx context.Context, todoUID string)) *MocktodoDAO_ListTodoTimeLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_ListTodoTimeLogs_Call) Return(todoTimeLogs []postgres.TodoTimeLog, err error) *MocktodoDAO_ListTodoTimeLogs_Call {
+	_c.Call.Return(todoTimeLogs, err)
+	return _c
+}
+
+func (_c *MocktodoDAO_ListTodoTimeLogs_Call) RunAndReturn(run func(ctx context.Context, todoUID string) ([]postgres.TodoTimeLog, error)) *MocktodoDAO_ListTodoTimeLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListTodos provides a mock function for the type MocktodoDAO
 func (_mock *MocktodoDAO) ListTodos(ctx context.Context, options postgres.ListOptions) ([]postgres.Todo, error) {
 	ret := _mock.Called(ctx, options)
@@ -295,6 +495,213 @@ func (_c *MocktodoDAO_ListTodos_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// LogTodoTime provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) LogTodoTime(ctx context.Context, l postgres.TodoTimeLog) (postgres.TodoTimeLog, error) {
+	ret := _mock.Called(ctx, l)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogTodoTime")
+	}
+
+	var r0 postgres.TodoTimeLog
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.TodoTimeLog) (postgres.TodoTimeLog, error)); ok {
+		return returnFunc(ctx, l)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, postgres.TodoTimeLog) postgres.TodoTimeLog); ok {
+		r0 = returnFunc(ctx, l)
+	} else {
+		r0 = ret.Get(0).(postgres.TodoTimeLog)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, postgres.TodoTimeLog) error); ok {
+		r1 = returnFunc(ctx, l)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocktodoDAO_LogTodoTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogTodoTime'
+type MocktodoDAO_LogTodoTime_Call struct {
+	*mock.Call
+}
+
+// LogTodoTime is a helper method to define mock.On call
+//   - ctx context.Context
+//   - l postgres.TodoTimeLog
+func (_e *MocktodoDAO_Expecter) LogTodoTime(ctx interface{}, l interface{}) *MocktodoDAO_LogTodoTime_Call {
+	return &MocktodoDAO_LogTodoTime_Call{Call: _e.mock.On("LogTodoTime", ctx, l)}
+}
+
+func (_c *MocktodoDAO_LogTodoTime_Call) Run(run func(ctx context.Context, l postgres.TodoTimeLog)) *MocktodoDAO_LogTodoTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 postgres.TodoTimeLog
+		if args[1] != nil {
+			arg1 = args[1].(postgres.TodoTimeLog)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_LogTodoTime_Call) Return(todoTimeLog postgres.TodoTimeLog, err error) *MocktodoDAO_LogTodoTime_Call {
+	_c.Call.Return(todoTimeLog, err)
+	return _c
+}
+
+func (_c *MocktodoDAO_LogTodoTime_Call) RunAndReturn(run func(ctx context.Context, l postgres.TodoTimeLog) (postgres.TodoTimeLog, error)) *MocktodoDAO_LogTodoTime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveTodoDependency provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) RemoveTodoDependency(ctx context.Context, todoUID string, blockedByUID string) error {
+	ret := _mock.Called(ctx, todoUID, blockedByUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTodoDependency")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, todoUID, blockedByUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MocktodoDAO_RemoveTodoDependency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveTodoDependency'
+type MocktodoDAO_RemoveTodoDependency_Call struct {
+	*mock.Call
+}
+
+// RemoveTodoDependency is a helper method to define mock.On call
+//   - ctx context.Context
+//   - todoUID string
+//   - blockedByUID string
+func (_e *MocktodoDAO_Expecter) RemoveTodoDependency(ctx interface{}, todoUID interface{}, blockedByUID interface{}) *MocktodoDAO_RemoveTodoDependency_Call {
+	return &MocktodoDAO_RemoveTodoDependency_Call{Call: _e.mock.On("RemoveTodoDependency", ctx, todoUID, blockedByUID)}
+}
+
+func (_c *MocktodoDAO_RemoveTodoDependency_Call) Run(run func(ctx context.Context, todoUID string, blockedByUID string)) *MocktodoDAO_RemoveTodoDependency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_RemoveTodoDependency_Call) Return(err error) *MocktodoDAO_RemoveTodoDependency_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MocktodoDAO_RemoveTodoDependency_Call) RunAndReturn(run func(ctx context.Context, todoUID string, blockedByUID string) error) *MocktodoDAO_RemoveTodoDependency_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTodoStatus provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) SetTodoStatus(ctx context.Context, uid string, status postgres.TodoStatus, completedBy *string) (postgres.Todo, error) {
+	ret := _mock.Called(ctx, uid, status, completedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTodoStatus")
+	}
+
+	var r0 postgres.Todo
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, postgres.TodoStatus, *string) (postgres.Todo, error)); ok {
+		return returnFunc(ctx, uid, status, completedBy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, postgres.TodoStatus, *string) postgres.Todo); ok {
+		r0 = returnFunc(ctx, uid, status, completedBy)
+	} else {
+		r0 = ret.Get(0).(postgres.Todo)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, postgres.TodoStatus, *string) error); ok {
+		r1 = returnFunc(ctx, uid, status, completedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocktodoDAO_SetTodoStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTodoStatus'
+type MocktodoDAO_SetTodoStatus_Call struct {
+	*mock.Call
+}
+
+// SetTodoStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - uid string
+//   - status postgres.TodoStatus
+//   - completedBy *string
+func (_e *MocktodoDAO_Expecter) SetTodoStatus(ctx interface{}, uid interface{}, status interface{}, completedBy interface{}) *MocktodoDAO_SetTodoStatus_Call {
+	return &MocktodoDAO_SetTodoStatus_Call{Call: _e.mock.On("SetTodoStatus", ctx, uid, status, completedBy)}
+}
+
+func (_c *MocktodoDAO_SetTodoStatus_Call) Run(run func(ctx context.Context, uid string, status postgres.TodoStatus, completedBy *string)) *MocktodoDAO_SetTodoStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 postgres.TodoStatus
+		if args[2] != nil {
+			arg2 = args[2].(postgres.TodoStatus)
+		}
+		var arg3 *string
+		if args[3] != nil {
+			arg3 = args[3].(*string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_SetTodoStatus_Call) Return(todo postgres.Todo, err error) *MocktodoDAO_SetTodoStatus_Call {
+	_c.Call.Return(todo, err)
+	return _c
+}
+
+func (_c *MocktodoDAO_SetTodoStatus_Call) RunAndReturn(run func(ctx context.Context, uid string, status postgres.TodoStatus, completedBy *string) (postgres.Todo, error)) *MocktodoDAO_SetTodoStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateTodo provides a mock function for the type MocktodoDAO
 func (_mock *MocktodoDAO) UpdateTodo(ctx context.Context, uid string, t postgres.UpdateTodo) (postgres.Todo, error) {
 	ret := _mock.Called(ctx, uid, t)
@@ -366,3 +773,95 @@ func (_c *MocktodoDAO_UpdateTodo_Call) RunAndReturn(run func(ctx context.Context
 	_c.Call.Return(run)
 	return _c
 }
+
+// WeeklyTodoTime provides a mock function for the type MocktodoDAO
+func (_mock *MocktodoDAO) WeeklyTodoTime(ctx context.Context, householdUID string, userUID string, from time.Time, to time.Time, title string) ([]postgres.TodoTimeSpent, error) {
+	ret := _mock.Called(ctx, householdUID, userUID, from, to, title)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WeeklyTodoTime")
+	}
+
+	var r0 []postgres.TodoTimeSpent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time, string) ([]postgres.TodoTimeSpent, error)); ok {
+		return returnFunc(ctx, householdUID, userUID, from, to, title)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time, string) []postgres.TodoTimeSpent); ok {
+		r0 = returnFunc(ctx, householdUID, userUID, from, to, title)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgres.TodoTimeSpent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time, string) error); ok {
+		r1 = returnFunc(ctx, householdUID, userUID, from, to, title)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MocktodoDAO_WeeklyTodoTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WeeklyTodoTime'
+type MocktodoDAO_WeeklyTodoTime_Call struct {
+	*mock.Call
+}
+
+// WeeklyTodoTime is a helper method to define mock.On call
+//   - ctx context.Context
+//   - householdUID string
+//   - userUID string
+//   - from time.Time
+//   - to time.Time
+//   - title string
+func (_e *MocktodoDAO_Expecter) WeeklyTodoTime(ctx interface{}, householdUID interface{}, userUID interface{}, from interface{}, to interface{}, title interface{}) *MocktodoDAO_WeeklyTodoTime_Call {
+	return &MocktodoDAO_WeeklyTodoTime_Call{Call: _e.mock.On("WeeklyTodoTime", ctx, householdUID, userUID, from, to, title)}
+}
+
+func (_c *MocktodoDAO_WeeklyTodoTime_Call) Run(run func(ctx context.Context, householdUID string, userUID string, from time.Time, to time.Time, title string)) *MocktodoDAO_WeeklyTodoTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		var arg4 time.Time
+		if args[4] != nil {
+			arg4 = args[4].(time.Time)
+		}
+		var arg5 string
+		if args[5] != nil {
+			arg5 = args[5].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MocktodoDAO_WeeklyTodoTime_Call) Return(todoTimeSpents []postgres.TodoTimeSpent, err error) *MocktodoDAO_WeeklyTodoTime_Call {
+	_c.Call.Return(todoTimeSpents, err)
+	return _c
+}
+
+func (_c *MocktodoDAO_WeeklyTodoTime_Call) RunAndReturn(run func(ctx context.Context, householdUID string, userUID string, from time.Time, to time.Time, title string) ([]postgres.TodoTimeSpent, error)) *MocktodoDAO_WeeklyTodoTime_Call {
+	_c.Call.Return(run)
+	return _c
+}
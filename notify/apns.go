@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProduction = "https://api.push.apple.com"
+	apnsSandbox    = "https://api.sandbox.push.apple.com"
+	// apnsTokenTTL is how long a provider token is reused. Apple rejects
+	// tokens older than an hour and refreshes more often than every 20
+	// minutes.
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNsConfig is an APNs token-based (.p8 key) connection.
+type APNsConfig struct {
+	KeyID  string
+	TeamID string
+	// Topic is the app's bundle ID.
+	Topic string
+	// PrivateKey is the PEM-encoded .p8 signing key.
+	PrivateKey []byte
+	Sandbox    bool
+}
+
+// APNs sends notifications through Apple's HTTP/2 provider API.
+type APNs struct {
+	cfg     APNsConfig
+	key     *ecdsa.PrivateKey
+	client  *http.Client
+	baseURL string
+
+	mu       sync.Mutex
+	token    string
+	tokenAge time.Time
+}
+
+func NewAPNs(cfg APNsConfig, client *http.Client) (*APNs, error) {
+	block, _ := pem.Decode(cfg.PrivateKey)
+	if block == nil {
+		return nil, errors.New("apns: private key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("apns: private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("apns: private key is not an ECDSA key")
+	}
+	baseURL := apnsProduction
+	if cfg.Sandbox {
+		baseURL = apnsSandbox
+	}
+	return &APNs{cfg: cfg, key: key, client: client, baseURL: baseURL}, nil
+}
+
+func (a *APNs) Push(ctx context.Context, p Push) error {
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": p.Title, "body": p.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range p.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	token, err := a.providerToken(time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/3/device/"+p.Token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", a.cfg.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var reason struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&reason)
+	if resp.StatusCode == http.StatusGone || reason.Reason == "BadDeviceToken" || reason.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+	return fmt.Errorf("apns: %s: %s", resp.Status, reason.Reason)
+}
+
+// providerToken returns the ES256 JWT that authenticates requests, signing
+// a new one once the current one is apnsTokenTTL old.
+func (a *APNs) providerToken(now time.Time) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && now.Sub(a.tokenAge) < apnsTokenTTL {
+		return a.token, nil
+	}
+	enc := base64.RawURLEncoding
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": a.cfg.KeyID})
+	claims, _ := json.Marshal(map[string]any{"iss": a.cfg.TeamID, "iat": now.Unix()})
+	signing := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signing))
+	r, s, err := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("apns: sign token: %w", err)
+	}
+	// JWS wants the fixed-size r || s encoding, not ASN.1.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	a.token, a.tokenAge = signing+"."+enc.EncodeToString(sig), now
+	return a.token, nil
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmAPI   = "https://fcm.googleapis.com/v1"
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCM sends notifications through the Firebase Cloud Messaging HTTP v1 API.
+type FCM struct {
+	projectID string
+	client    *http.Client
+	baseURL   string
+}
+
+// NewFCM authenticates with a Firebase service account key file's JSON.
+func NewFCM(ctx context.Context, serviceAccountJSON []byte) (*FCM, error) {
+	creds, err := google.CredentialsFromJSON(ctx, serviceAccountJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: credentials: %w", err)
+	}
+	if creds.ProjectID == "" {
+		return nil, errors.New("fcm: credentials have no project_id")
+	}
+	return &FCM{projectID: creds.ProjectID, client: oauth2.NewClient(ctx, creds.TokenSource), baseURL: fcmAPI}, nil
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (f *FCM) Push(ctx context.Context, p Push) error {
+	body, err := json.Marshal(map[string]fcmMessage{"message": {
+		Token:        p.Token,
+		Notification: fcmNotification{Title: p.Title, Body: p.Body},
+		Data:         p.Data,
+	}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/projects/"+f.projectID+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var out struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&out)
+	if resp.StatusCode == http.StatusNotFound || out.Error.Status == "NOT_FOUND" || out.Error.Status == "UNREGISTERED" {
+		return ErrUnregistered
+	}
+	return fmt.Errorf("fcm: %s: %s", resp.Status, out.Error.Message)
+}
@@ -0,0 +1,17 @@
+// Package notify delivers messages to users outside of an assistant
+// session, such as the email digest.
+package notify
+
+import "context"
+
+// Message is a plain-text message to one recipient.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier sends messages.
+type Notifier interface {
+	Send(ctx context.Context, m Message) error
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Push platforms, as stored on a registered device.
+const (
+	PlatformAPNs = "apns"
+	PlatformFCM  = "fcm"
+)
+
+// ErrUnregistered is returned when the push service says a device token is
+// no longer valid, e.g. because the app was uninstalled.
+var ErrUnregistered = errors.New("push: device token is no longer registered")
+
+// Push is a notification to one device. Data is passed to the app with it.
+type Push struct {
+	Token string            `json:"token"`
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data,omitempty"`
+}
+
+// Pusher sends push notifications through one platform's service.
+type Pusher interface {
+	Push(ctx context.Context, p Push) error
+}
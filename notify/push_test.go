@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPNsPush(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "com.example.assistant", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		var claims map[string]any
+		claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+		assert.Equal(t, "TEAM123", claims["iss"])
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])))
+
+		switch r.URL.Path {
+		case "/3/device/good":
+			var payload map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "t1", payload["todo_uid"])
+			assert.Equal(t, map[string]any{"title": "Bins", "body": "Due now"}, payload["aps"].(map[string]any)["alert"])
+		case "/3/device/gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = io.WriteString(w, `{"reason": "Unregistered"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"reason": "TopicDisallowed"}`)
+		}
+	}))
+	defer srv.Close()
+
+	a, err := NewAPNs(APNsConfig{
+		KeyID:      "KEY123",
+		TeamID:     "TEAM123",
+		Topic:      "com.example.assistant",
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+	}, srv.Client())
+	require.NoError(t, err)
+	a.baseURL = srv.URL
+
+	push := Push{Title: "Bins", Body: "Due now", Data: map[string]string{"todo_uid": "t1"}}
+	push.Token = "good"
+	require.NoError(t, a.Push(t.Context(), push))
+	push.Token = "gone"
+	assert.ErrorIs(t, a.Push(t.Context(), push), ErrUnregistered)
+	push.Token = "other"
+	assert.ErrorContains(t, a.Push(t.Context(), push), "TopicDisallowed")
+
+	_, err = NewAPNs(APNsConfig{PrivateKey: []byte("not a key")}, srv.Client())
+	assert.Error(t, err)
+}
+
+func TestAPNsProviderTokenIsReused(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	a := &APNs{key: key}
+	now := time.Now()
+	first, err := a.providerToken(now)
+	require.NoError(t, err)
+	again, _ := a.providerToken(now.Add(10 * time.Minute))
+	assert.Equal(t, first, again)
+	later, _ := a.providerToken(now.Add(apnsTokenTTL))
+	assert.NotEqual(t, first, later)
+}
+
+func TestFCMPush(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/assistant-app/messages:send", r.URL.Path)
+		var body struct {
+			Message fcmMessage `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Message.Token == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": {"status": "NOT_FOUND", "message": "Requested entity was not found."}}`)
+			return
+		}
+		assert.Equal(t, fcmNotification{Title: "Bins", Body: "Due now"}, body.Message.Notification)
+		assert.Equal(t, map[string]string{"todo_uid": "t1"}, body.Message.Data)
+		_, _ = io.WriteString(w, `{"name": "projects/assistant-app/messages/1"}`)
+	}))
+	defer srv.Close()
+
+	f := &FCM{projectID: "assistant-app", client: srv.Client(), baseURL: srv.URL}
+	push := Push{Token: "device", Title: "Bins", Body: "Due now", Data: map[string]string{"todo_uid": "t1"}}
+	require.NoError(t, f.Push(t.Context(), push))
+	push.Token = "gone"
+	assert.ErrorIs(t, f.Push(t.Context(), push), ErrUnregistered)
+}
+
+func TestSlackPush(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat.postMessage", r.URL.Path)
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+		var body struct {
+			Channel string           `json:"channel"`
+			Text    string           `json:"text"`
+			Blocks  []map[string]any `json:"blocks"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch body.Channel {
+		case "U123":
+			assert.Equal(t, "Bins: Due now", body.Text)
+			require.Len(t, body.Blocks, 2)
+			buttons := body.Blocks[1]["elements"].([]any)
+			require.Len(t, buttons, 2)
+			assert.Equal(t, SlackActionTodoDone, buttons[0].(map[string]any)["action_id"])
+			assert.Equal(t, "t1", buttons[0].(map[string]any)["value"])
+			assert.Equal(t, SlackActionTodoSnooze, buttons[1].(map[string]any)["action_id"])
+			_, _ = io.WriteString(w, `{"ok": true}`)
+		case "U456":
+			assert.Len(t, body.Blocks, 1)
+			_, _ = io.WriteString(w, `{"ok": true}`)
+		case "UGONE":
+			_, _ = io.WriteString(w, `{"ok": false, "error": "user_not_found"}`)
+		default:
+			_, _ = io.WriteString(w, `{"ok": false, "error": "ratelimited"}`)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewSlack("xoxb-test", srv.Client())
+	s.baseURL = srv.URL
+	push := Push{Token: "U123", Title: "Bins", Body: "Due now", Data: map[string]string{"todo_uid": "t1"}}
+	require.NoError(t, s.Push(t.Context(), push))
+	require.NoError(t, s.Push(t.Context(), Push{Token: "U456", Title: "Passport", Body: "Renew by June", Data: map[string]string{"note_id": "n1"}}))
+	push.Token = "UGONE"
+	assert.ErrorIs(t, s.Push(t.Context(), push), ErrUnregistered)
+	push.Token = "U789"
+	assert.EqualError(t, s.Push(t.Context(), push), "slack: ratelimited")
+}
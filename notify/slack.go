@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PlatformSlack is the platform of a Slack "device": a user's direct
+// messages with the app, whose token is their Slack user ID.
+const PlatformSlack = "slack"
+
+// The action IDs of the buttons on a todo's reminder in Slack, as they come
+// back in an interaction payload. The button's value is the todo's UID.
+const (
+	SlackActionTodoDone   = "todo_done"
+	SlackActionTodoSnooze = "todo_snooze"
+)
+
+const slackAPI = "https://slack.com/api"
+
+// Slack sends notifications as direct messages from a Slack app's bot.
+// Todo reminders, which carry a todo_uid, get Done and Snooze 1h buttons.
+type Slack struct {
+	botToken string
+	client   *http.Client
+	baseURL  string
+}
+
+// NewSlack sends as the bot with botToken, an xoxb- token with the
+// chat:write scope.
+func NewSlack(botToken string, client *http.Client) *Slack {
+	return &Slack{botToken: botToken, client: client, baseURL: slackAPI}
+}
+
+// slackGone are the chat.postMessage errors meaning the user can't be
+// messaged again, like an uninstalled app's device token.
+var slackGone = map[string]bool{
+	"channel_not_found": true,
+	"user_not_found":    true,
+	"account_inactive":  true,
+	"user_disabled":     true,
+}
+
+func (s *Slack) Push(ctx context.Context, p Push) error {
+	body, err := json.Marshal(map[string]any{
+		"channel": p.Token,
+		"text":    p.Title + ": " + p.Body,
+		"blocks":  slackBlocks(p),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.botToken)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&out)
+	switch {
+	case resp.StatusCode == http.StatusOK && out.OK:
+		return nil
+	case slackGone[out.Error]:
+		return ErrUnregistered
+	case out.Error != "":
+		return fmt.Errorf("slack: %s", out.Error)
+	}
+	return fmt.Errorf("slack: %s", resp.Status)
+}
+
+// slackBlocks lays p out as Block Kit blocks: its title in bold over its
+// body and, for a todo, the buttons that complete or snooze it.
+func slackBlocks(p Push) []any {
+	blocks := []any{map[string]any{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": "*" + p.Title + "*\n" + p.Body},
+	}}
+	todo := p.Data["todo_uid"]
+	if todo == "" {
+		return blocks
+	}
+	button := func(action, label, style string) map[string]any {
+		b := map[string]any{
+			"type":      "button",
+			"action_id": action,
+			"value":     todo,
+			"text":      map[string]string{"type": "plain_text", "text": label},
+		}
+		if style != "" {
+			b["style"] = style
+		}
+		return b
+	}
+	return append(blocks, map[string]any{
+		"type":     "actions",
+		"block_id": "todo_reminder",
+		"elements": []any{
+			button(SlackActionTodoDone, "Done", "primary"),
+			button(SlackActionTodoSnooze, "Snooze 1h", ""),
+		},
+	})
+}
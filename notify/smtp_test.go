@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP accepts one message without auth or TLS and returns the
+// commands it saw and the DATA it received.
+func fakeSMTP(t *testing.T) (port int, done <-chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	out := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+		var seen []string
+		reply("220 fake")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				out <- seen
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			seen = append(seen, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 fake")
+			case line == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, _ := r.ReadString('\n')
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				seen = append(seen, data.String())
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				out <- seen
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return l.Addr().(*net.TCPAddr).Port, out
+}
+
+func TestSMTPSend(t *testing.T) {
+	port, done := fakeSMTP(t)
+	s, err := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: port, From: "Assistant <assistant@example.com>"})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(t.Context(), Message{To: "mia@example.com", Subject: "Your daily digest", Body: "Overdue:\n- Bins"}))
+	seen := <-done
+	assert.Contains(t, seen, "MAIL FROM:<assistant@example.com>")
+	assert.Contains(t, seen, "RCPT TO:<mia@example.com>")
+	data := seen[len(seen)-2]
+	assert.Contains(t, data, "Subject: Your daily digest\r\n")
+	assert.Contains(t, data, "\r\n\r\nOverdue:\r\n- Bins")
+
+	assert.Error(t, s.Send(t.Context(), Message{To: "not an address"}))
+	_, err = NewSMTP(SMTPConfig{Host: "127.0.0.1", From: "nobody"})
+	assert.Error(t, err)
+}
+
+func TestFormatMessage(t *testing.T) {
+	from := &mail.Address{Name: "Assistant", Address: "assistant@example.com"}
+	to := &mail.Address{Address: "mia@example.com"}
+	body := "Café at 10 — " + strings.Repeat("x", 100)
+	msg, err := formatMessage(from, to, Message{Subject: "Café", Body: body}, time.Date(2025, 8, 18, 7, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Café", subject)
+	assert.Equal(t, "Mon, 18 Aug 2025 07:00:00 +0000", parsed.Header.Get("Date"))
+	decoded, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+	for _, line := range strings.Split(string(msg), "\r\n") {
+		assert.LessOrEqual(t, len(line), 78)
+	}
+}
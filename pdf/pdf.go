@@ -0,0 +1,344 @@
+// Package pdf writes simple printable documents: a title, headings,
+// paragraphs, bulleted and numbered lists and a JPEG photo, flowing onto as
+// many US Letter pages as they need.
+//
+// Text is set in the standard Helvetica fonts every PDF reader has, so
+// nothing is embedded. Those fonts only cover Windows-1252: other
+// characters print as "?". Line breaking measures text with the fonts'
+// published widths.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry, in points.
+const (
+	pageWidth  = 612
+	pageHeight = 792
+	margin     = 54
+	textWidth  = pageWidth - 2*margin
+
+	titleSize   = 20
+	headingSize = 14
+	bodySize    = 11
+	// leading is the line height as a multiple of the font size.
+	leading = 1.35
+	// listIndent is how far list items hang in from their markers.
+	listIndent = 18
+)
+
+// Font resource names.
+const (
+	regular = "F1"
+	bold    = "F2"
+)
+
+type jpegImage struct {
+	data          []byte
+	width, height int
+	colorSpace    string
+}
+
+// Document is a document being written. Add content in reading order and
+// call Bytes for the file.
+type Document struct {
+	title  string
+	pages  []*bytes.Buffer
+	y      float64
+	images []jpegImage
+}
+
+// New starts a document whose metadata title is title. The title isn't
+// printed; add it with Title.
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer { return d.pages[len(d.pages)-1] }
+
+// space moves down by height, starting a new page first when it wouldn't
+// fit on this one.
+func (d *Document) space(height float64) {
+	if d.y-height < margin && d.y < pageHeight-margin {
+		d.newPage()
+	}
+	d.y -= height
+}
+
+// Title prints the document's title in large bold type.
+func (d *Document) Title(text string) {
+	d.lines(text, bold, titleSize, margin, textWidth)
+	d.gap(bodySize * 0.5)
+}
+
+// Heading prints a section heading.
+func (d *Document) Heading(text string) {
+	d.gap(bodySize * 0.5)
+	d.lines(text, bold, headingSize, margin, textWidth)
+	d.gap(bodySize * 0.25)
+}
+
+// Paragraph prints text wrapped to the page. Line breaks in text are kept.
+func (d *Document) Paragraph(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		d.lines(line, regular, bodySize, margin, textWidth)
+	}
+	d.gap(bodySize * 0.5)
+}
+
+// Bullets prints items as a bulleted list.
+func (d *Document) Bullets(items []string) {
+	d.list(items, func(int) string { return "•" })
+}
+
+// Numbered prints items as a list numbered from 1.
+func (d *Document) Numbered(items []string) {
+	d.list(items, func(i int) string { return fmt.Sprintf("%d.", i+1) })
+}
+
+func (d *Document) list(items []string, marker func(int) string) {
+	for i, item := range items {
+		d.markedLines(marker(i), item, regular, bodySize, margin+listIndent, textWidth-listIndent)
+		d.gap(bodySize * 0.25)
+	}
+	d.gap(bodySize * 0.25)
+}
+
+// Image prints a JPEG scaled to fit within maxWidth by maxHeight points,
+// keeping its proportions.
+func (d *Document) Image(data []byte, maxWidth, maxHeight float64) error {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if format != "jpeg" {
+		return fmt.Errorf("pdf: images must be JPEG, not %s", format)
+	}
+	colorSpace := "DeviceRGB"
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "DeviceCMYK"
+	}
+	d.images = append(d.images, jpegImage{data: data, width: cfg.Width, height: cfg.Height, colorSpace: colorSpace})
+
+	maxWidth, maxHeight = min(maxWidth, textWidth), min(maxHeight, pageHeight-2*margin)
+	scale := min(maxWidth/float64(cfg.Width), maxHeight/float64(cfg.Height))
+	w, h := float64(cfg.Width)*scale, float64(cfg.Height)*scale
+	d.space(h)
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, float64(margin), d.y, len(d.images))
+	d.gap(bodySize)
+	return nil
+}
+
+func (d *Document) gap(height float64) {
+	d.y -= height
+}
+
+// lines prints text in font and size, wrapped within width from x.
+func (d *Document) lines(text, font string, size, x, width float64) {
+	d.markedLines("", text, font, size, x, width)
+}
+
+// markedLines is lines with marker, e.g. a bullet, at the margin of the
+// first line.
+func (d *Document) markedLines(marker, text, font string, size, x, width float64) {
+	for i, line := range wrap(text, font, size, width) {
+		d.space(size * leading)
+		baseline := d.y + size*(leading-1)
+		if i == 0 && marker != "" {
+			d.text(marker, font, size, margin, baseline)
+		}
+		d.text(line, font, size, x, baseline)
+	}
+}
+
+func (d *Document) text(s, font string, size, x, y float64) {
+	fmt.Fprintf(d.page(), "BT /%s %g Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(encode(s)))
+}
+
+// wrap breaks text into lines no wider than width, between words where it
+// can. An empty text is one empty line.
+func wrap(text, font string, size, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	line := ""
+	for _, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line == "" || measure(candidate, font, size) <= width {
+			line = candidate
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+		// A word wider than the line is split where it overflows.
+		for measure(line, font, size) > width && utf8.RuneCountInString(line) > 1 {
+			runes := []rune(line)
+			n := len(runes) - 1
+			for n > 1 && measure(string(runes[:n]), font, size) > width {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			line = string(runes[n:])
+		}
+	}
+	return append(lines, line)
+}
+
+// measure is the width of s in points.
+func measure(s, font string, size float64) float64 {
+	widths := &helveticaWidths
+	if font == bold {
+		widths = &helveticaBoldWidths
+	}
+	var total int
+	for _, c := range encode(s) {
+		if c >= ' ' && c <= '~' {
+			total += widths[c-' ']
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// cp1252 maps the characters Windows-1252 puts in 0x80-0x9F.
+var cp1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89,
+	'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts s to Windows-1252, the WinAnsiEncoding the fonts use.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case cp1252[r] != 0:
+			out = append(out, cp1252[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// escape makes b a PDF literal string's contents.
+func escape(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			out = append(out, '\\', c)
+		case '\r', '\n', '\t':
+			out = append(out, ' ')
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Bytes returns the PDF file.
+func (d *Document) Bytes() []byte {
+	var b bytes.Buffer
+	var offsets []int
+	// object starts the next object, numbered from 1 in the order written.
+	object := func() int {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n", len(offsets))
+		return len(offsets)
+	}
+	stream := func(dict string, data []byte) {
+		object()
+		fmt.Fprintf(&b, "<< %s /Length %d >>\nstream\n", dict, len(data))
+		b.Write(data)
+		b.WriteString("\nendstream\nendobj\n")
+	}
+
+	// Objects 1 to 4 are the catalog, the page tree, the fonts and the
+	// metadata; images follow, then each page and its contents.
+	const pagesObj, imagesStart = 2, 6
+	pagesStart := imagesStart + len(d.images)
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	object()
+	fmt.Fprintf(&b, "<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pagesObj)
+	object()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pagesStart+2*i)
+	}
+	fmt.Fprintf(&b, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+	for _, name := range []string{"Helvetica", "Helvetica-Bold"} {
+		object()
+		fmt.Fprintf(&b, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", name)
+	}
+	object()
+	fmt.Fprintf(&b, "<< /Title (%s) /Producer (assistant-server) >>\nendobj\n", escape(encode(d.title)))
+
+	var xobjects strings.Builder
+	for i, img := range d.images {
+		fmt.Fprintf(&xobjects, " /Im%d %d 0 R", i+1, imagesStart+i)
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode",
+			img.width, img.height, img.colorSpace), img.data)
+	}
+	for _, content := range d.pages {
+		page := object()
+		fmt.Fprintf(&b, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> /XObject <<%s >> >> /Contents %d 0 R >>\nendobj\n",
+			pagesObj, pageWidth, pageHeight, regular, bold, xobjects.String(), page+1)
+		stream("", content.Bytes())
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.Bytes()
+}
+
+// Advance widths of ' ' to '~', in thousandths of the font size, from the
+// fonts' Adobe metrics.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkXref checks that every xref entry points at its object.
+func checkXref(t *testing.T, file []byte) {
+	start := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(file)
+	require.NotNil(t, start)
+	xref, err := strconv.Atoi(string(start[1]))
+	require.NoError(t, err)
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(file[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(file[off:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	var photo bytes.Buffer
+	require.NoError(t, jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil))
+
+	d := New("Pancakes (fluffy)")
+	d.Title("Pancakes")
+	require.NoError(t, d.Image(photo.Bytes(), 300, 200))
+	d.Heading("Ingredients")
+	d.Bullets([]string{"1½ cups flour", "2 eggs → beaten"})
+	d.Heading("Steps")
+	d.Numbered([]string{"Whisk.", "Fry."})
+	file := d.Bytes()
+
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF-1.4\n")))
+	checkXref(t, file)
+	assert.Contains(t, string(file), "/Title (Pancakes \\(fluffy\\))")
+	assert.Contains(t, string(file), "(Pancakes) Tj")
+	assert.Contains(t, string(file), "(\x95) Tj")
+	assert.Contains(t, string(file), "(1\xbd cups flour) Tj")
+	assert.Contains(t, string(file), "(2 eggs ? beaten) Tj")
+	assert.Contains(t, string(file), "(2.) Tj")
+	assert.Contains(t, string(file), "/Width 40 /Height 20 /ColorSpace /DeviceRGB")
+	// The 40x20 photo fills the 300 points of width it was given.
+	assert.Contains(t, string(file), "q 300.00 0 0 150.00 54.00")
+	assert.Contains(t, string(file), "/Count 1")
+
+	assert.Error(t, New("x").Image([]byte("GIF89a"), 100, 100))
+}
+
+func TestDocumentFlowsOntoPages(t *testing.T) {
+	d := New("Long")
+	for range 120 {
+		d.Paragraph("A step long enough to need wrapping onto a second line, since it goes on about stirring for a while.")
+	}
+	file := d.Bytes()
+	checkXref(t, file)
+	count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(file)
+	require.NotNil(t, count)
+	pages, _ := strconv.Atoi(string(count[1]))
+	assert.Greater(t, pages, 2)
+	assert.Equal(t, pages, strings.Count(string(file), "/Type /Page "))
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap("The quick brown fox jumps over the lazy dog", regular, bodySize, 100)
+	assert.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, measure(line, regular, bodySize), 100.0, line)
+	}
+	assert.Equal(t, "The quick brown fox jumps over the lazy dog", strings.Join(lines, " "))
+
+	long := wrap(strings.Repeat("m", 50), regular, bodySize, 100)
+	assert.Greater(t, len(long), 1)
+	assert.Equal(t, strings.Repeat("m", 50), strings.Join(long, ""))
+	assert.Equal(t, []string{""}, wrap("  ", regular, bodySize, 100))
+}
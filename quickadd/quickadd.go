@@ -0,0 +1,130 @@
+// Package quickadd reads the shorthand people type to add a todo in one
+// line, e.g. "buy milk !3 #errands @sat", into its title, priority, tags
+// and due date. It is shared by every surface todos can be added from, so
+// the shorthand means the same thing everywhere.
+//
+// The markers are:
+//
+//	!1 to !4          priority, low to critical
+//	#errands          a tag; tags start with a letter and are lowercased
+//	@today, @tomorrow due today or tomorrow (also @tod, @tom, @tmrw)
+//	@sat, @saturday   due on the next Saturday, today included; any three
+//	                  or more letters of a weekday will do
+//	@2025-09-20       due on that date
+//	🔺 ⏫ 🔼 🔽       priority as Obsidian Tasks writes it: critical, high,
+//	                  medium and low
+//	📅 2025-09-20     due on that date, as Obsidian Tasks writes it
+//
+// Words that only look like markers, such as "#1", "!9" or "@home", stay
+// in the title. When a priority or due date is given twice, the last one
+// wins.
+package quickadd
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Todo is what a quick-add line says. Priority is 1 (low) to 4 (critical),
+// or 0 when the line doesn't give one. Due is midnight UTC on the due date,
+// the way due dates are stored.
+type Todo struct {
+	Title    string
+	Priority int
+	Tags     []string
+	Due      *time.Time
+}
+
+// emojiPriorities are the priority markers of Obsidian Tasks.
+var emojiPriorities = map[string]int{"🔽": 1, "🔼": 2, "⏫": 3, "🔺": 4}
+
+const dueEmoji = "📅"
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// Parse reads the markers out of text. Relative dates such as @sat are
+// counted from now's date in now's location.
+func Parse(text string, now time.Time) Todo {
+	var out Todo
+	var title []string
+	words := strings.Fields(text)
+	for i := 0; i < len(words); i++ {
+		// Emoji may come with a variation selector.
+		word := strings.TrimSuffix(words[i], "\ufe0f")
+		if p, ok := emojiPriorities[word]; ok {
+			out.Priority = p
+			continue
+		}
+		if rest, ok := strings.CutPrefix(word, dueEmoji); ok {
+			next := i
+			if rest == "" && i+1 < len(words) {
+				next, rest = i+1, words[i+1]
+			}
+			if due, ok := parseDate(rest); ok {
+				out.Due, i = &due, next
+				continue
+			}
+		}
+		switch {
+		case len(word) == 2 && word[0] == '!' && word[1] >= '1' && word[1] <= '4':
+			out.Priority = int(word[1] - '0')
+			continue
+		case strings.HasPrefix(word, "#"):
+			if tag, ok := parseTag(word[1:]); ok {
+				if !slices.Contains(out.Tags, tag) {
+					out.Tags = append(out.Tags, tag)
+				}
+				continue
+			}
+		case strings.HasPrefix(word, "@"):
+			if due, ok := parseDue(word[1:], now); ok {
+				out.Due = &due
+				continue
+			}
+		}
+		title = append(title, words[i])
+	}
+	out.Title = strings.Join(title, " ")
+	return out
+}
+
+func parseTag(s string) (string, bool) {
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '_'):
+		default:
+			return "", false
+		}
+	}
+	return strings.ToLower(s), s != ""
+}
+
+// parseDue reads the date after an @: a day relative to now, or an ISO
+// date.
+func parseDue(s string, now time.Time) (time.Time, bool) {
+	s = strings.ToLower(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch s {
+	case "today", "tod":
+		return today, true
+	case "tomorrow", "tom", "tmrw":
+		return today.AddDate(0, 0, 1), true
+	}
+	if len(s) >= 3 {
+		for day, name := range weekdays {
+			if strings.HasPrefix(name, s) {
+				ahead := (day - int(now.Weekday()) + 7) % 7
+				return today.AddDate(0, 0, ahead), true
+			}
+		}
+	}
+	return parseDate(s)
+}
+
+func parseDate(s string) (time.Time, bool) {
+	t, err := time.Parse(time.DateOnly, s)
+	return t, err == nil
+}
@@ -0,0 +1,36 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) *time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	// A Wednesday, late in the evening in New York, when it is already
+	// Thursday in UTC.
+	ny, _ := time.LoadLocation("America/New_York")
+	now := time.Date(2025, 9, 17, 22, 30, 0, 0, ny)
+	for text, want := range map[string]Todo{
+		"buy milk !3 #errands @sat":           {Title: "buy milk", Priority: 3, Tags: []string{"errands"}, Due: date("2025-09-20")},
+		"call mum @today":                     {Title: "call mum", Due: date("2025-09-17")},
+		"bins out @tmrw #Chores #chores":      {Title: "bins out", Tags: []string{"chores"}, Due: date("2025-09-18")},
+		"standup @wed":                        {Title: "standup", Due: date("2025-09-17")},
+		"pay rent @2025-10-01 !4":             {Title: "pay rent", Priority: 4, Due: date("2025-10-01")},
+		"renew passport ⏫ 📅 2025-11-03":       {Title: "renew passport", Priority: 3, Due: date("2025-11-03")},
+		"water plants 🔽️ 📅2025-09-19":         {Title: "water plants", Priority: 1, Due: date("2025-09-19")},
+		"fix bug #1 !9 @home":                 {Title: "fix bug #1 !9 @home"},
+		"move !1 this !2":                     {Title: "move this", Priority: 2},
+		"pack  for\ttrip @thurs #travel-2025": {Title: "pack for trip", Tags: []string{"travel-2025"}, Due: date("2025-09-18")},
+		"📅 someday":                           {Title: "📅 someday"},
+		"#errands !2":                         {Priority: 2, Tags: []string{"errands"}},
+	} {
+		assert.Equal(t, want, Parse(text, now), text)
+	}
+}
@@ -0,0 +1,428 @@
+// Package schema validates JSON documents against a JSON Schema, so the
+// free-form data of notes and preferences keeps a consistent shape.
+//
+// Only a subset of JSON Schema is understood: type, enum, const,
+// properties, required, additionalProperties, items, minItems, maxItems,
+// uniqueItems, minLength, maxLength, pattern, format (date, date-time,
+// email and uri), minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// anyOf, allOf and not, plus annotations such as title and description. A
+// schema using any other keyword is rejected rather than half-enforced.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema. The zero value accepts everything.
+type Schema struct {
+	reject bool // the false schema
+
+	types       []string
+	enum        []any
+	constant    any
+	hasConstant bool
+
+	properties   map[string]*Schema
+	required     []string
+	additional   *Schema
+	items        *Schema
+	minItems     *int
+	maxItems     *int
+	uniqueItems  bool
+	minLength    *int
+	maxLength    *int
+	pattern      *regexp.Regexp
+	format       string
+	minimum      *float64
+	maximum      *float64
+	exclusiveMin *float64
+	exclusiveMax *float64
+
+	anyOf []*Schema
+	allOf []*Schema
+	not   *Schema
+}
+
+// FieldError is one way a document fails its schema. Path is a JSON
+// Pointer to the offending value, "" for the document itself.
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+var types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+var formats = map[string]func(string) bool{
+	"date": func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"email": func(s string) bool {
+		a, err := mail.ParseAddress(s)
+		return err == nil && a.Address == s
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+}
+
+// annotations are keywords that describe a schema without constraining it.
+var annotations = []string{"$schema", "$id", "$comment", "title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly"}
+
+// Parse reads a JSON Schema, which must be an object or a boolean.
+func Parse(raw []byte) (*Schema, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("schema isn't JSON: %w", err)
+	}
+	return parse(v, "")
+}
+
+func parse(v any, path string) (*Schema, error) {
+	switch v := v.(type) {
+	case bool:
+		return &Schema{reject: !v}, nil
+	case map[string]any:
+		s := &Schema{}
+		for _, k := range sortedKeys(v) {
+			if err := s.set(k, v[k], path); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("%s: a schema must be an object or a boolean", at(path))
+}
+
+// set applies one keyword of the schema at path.
+func (s *Schema) set(keyword string, v any, schemaPath string) error {
+	path := schemaPath + "/" + escape(keyword)
+	var err error
+	switch keyword {
+	case "type":
+		s.types, err = parseTypes(v, path)
+	case "enum":
+		values, ok := v.([]any)
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("%s: must be a non-empty array", path)
+		}
+		s.enum = values
+	case "const":
+		s.constant, s.hasConstant = v, true
+	case "properties":
+		props, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: must be an object", path)
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, p := range props {
+			if s.properties[name], err = parse(p, path+"/"+escape(name)); err != nil {
+				return err
+			}
+		}
+	case "required":
+		s.required, err = parseStrings(v, path)
+	case "additionalProperties":
+		s.additional, err = parse(v, path)
+	case "items":
+		s.items, err = parse(v, path)
+	case "minItems":
+		s.minItems, err = parseCount(v, path)
+	case "maxItems":
+		s.maxItems, err = parseCount(v, path)
+	case "uniqueItems":
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%s: must be a boolean", path)
+		}
+		s.uniqueItems = b
+	case "minLength":
+		s.minLength, err = parseCount(v, path)
+	case "maxLength":
+		s.maxLength, err = parseCount(v, path)
+	case "pattern":
+		p, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: must be a string", path)
+		}
+		if s.pattern, err = regexp.Compile(p); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case "format":
+		f, ok := v.(string)
+		if _, known := formats[f]; !ok || !known {
+			return fmt.Errorf("%s: unsupported format %v", path, v)
+		}
+		s.format = f
+	case "minimum":
+		s.minimum, err = parseNumber(v, path)
+	case "maximum":
+		s.maximum, err = parseNumber(v, path)
+	case "exclusiveMinimum":
+		s.exclusiveMin, err = parseNumber(v, path)
+	case "exclusiveMaximum":
+		s.exclusiveMax, err = parseNumber(v, path)
+	case "anyOf":
+		s.anyOf, err = parseAll(v, path)
+	case "allOf":
+		s.allOf, err = parseAll(v, path)
+	case "not":
+		s.not, err = parse(v, path)
+	default:
+		if !slices.Contains(annotations, keyword) {
+			return fmt.Errorf("%s: unsupported keyword %q", at(schemaPath), keyword)
+		}
+	}
+	return err
+}
+
+func parseTypes(v any, path string) ([]string, error) {
+	names := []string{}
+	switch v := v.(type) {
+	case string:
+		names = append(names, v)
+	case []any:
+		for _, t := range v {
+			name, ok := t.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string or an array of strings", path)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", path)
+	}
+	for _, name := range names {
+		if !slices.Contains(types, name) {
+			return nil, fmt.Errorf("%s: unknown type %q", path, name)
+		}
+	}
+	return names, nil
+}
+
+func parseStrings(v any, path string) ([]string, error) {
+	values, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an array of strings", path)
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", path)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func parseNumber(v any, path string) (*float64, error) {
+	n, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", path)
+	}
+	return &n, nil
+}
+
+func parseCount(v any, path string) (*int, error) {
+	n, ok := v.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", path)
+	}
+	c := int(n)
+	return &c, nil
+}
+
+func parseAll(v any, path string) ([]*Schema, error) {
+	values, ok := v.([]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array of schemas", path)
+	}
+	out := make([]*Schema, len(values))
+	for i, value := range values {
+		var err error
+		if out[i], err = parse(value, path+"/"+strconv.Itoa(i)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Validate checks a JSON document against s and returns every way it
+// fails, or nil when it matches.
+func (s *Schema) Validate(doc []byte) []FieldError {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return []FieldError{{Message: "must be valid JSON"}}
+	}
+	return s.validate(v, "")
+}
+
+func (s *Schema) validate(v any, path string) []FieldError {
+	if s.reject {
+		return []FieldError{{path, "is not allowed"}}
+	}
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return isType(v, t) }) {
+		return []FieldError{{path, "must be " + strings.Join(s.types, " or ")}}
+	}
+	var errs []FieldError
+	fail := func(format string, args ...any) {
+		errs = append(errs, FieldError{path, fmt.Sprintf(format, args...)})
+	}
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		fail("must be one of %s", list(s.enum))
+	}
+	if s.hasConstant && !reflect.DeepEqual(s.constant, v) {
+		fail("must be %s", list([]any{s.constant}))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, FieldError{path + "/" + escape(name), "is required"})
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			child := path + "/" + escape(name)
+			if p, ok := s.properties[name]; ok {
+				errs = append(errs, p.validate(v[name], child)...)
+			} else if s.additional != nil {
+				errs = append(errs, s.additional.validate(v[name], child)...)
+			}
+		}
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.uniqueItems {
+			for i := range v {
+				if slices.ContainsFunc(v[:i], func(e any) bool { return reflect.DeepEqual(e, v[i]) }) {
+					fail("must not repeat items")
+					break
+				}
+			}
+		}
+		if s.items != nil {
+			for i, item := range v {
+				errs = append(errs, s.items.validate(item, path+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.minLength != nil && n < *s.minLength {
+			fail("must be at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("must be at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match %s", s.pattern)
+		}
+		if s.format != "" && !formats[s.format](v) {
+			fail("must be a valid %s", s.format)
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			fail("must be at least %v", *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			fail("must be at most %v", *s.maximum)
+		}
+		if s.exclusiveMin != nil && v <= *s.exclusiveMin {
+			fail("must be greater than %v", *s.exclusiveMin)
+		}
+		if s.exclusiveMax != nil && v >= *s.exclusiveMax {
+			fail("must be less than %v", *s.exclusiveMax)
+		}
+	}
+
+	for _, sub := range s.allOf {
+		errs = append(errs, sub.validate(v, path)...)
+	}
+	if len(s.anyOf) > 0 && !slices.ContainsFunc(s.anyOf, func(sub *Schema) bool { return sub.validate(v, path) == nil }) {
+		fail("must match at least one of the allowed schemas")
+	}
+	if s.not != nil && s.not.validate(v, path) == nil {
+		fail("must not match the disallowed schema")
+	}
+	return errs
+}
+
+func isType(v any, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case map[string]any:
+		return t == "object"
+	case []any:
+		return t == "array"
+	case float64:
+		return t == "number" || t == "integer" && v == math.Trunc(v)
+	case string:
+		return t == "string"
+	}
+	return false
+}
+
+// list renders values as JSON, for messages.
+func list(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		b, _ := json.Marshal(v)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escape makes name a JSON Pointer reference token.
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func at(path string) string {
+	if path == "" {
+		return "schema"
+	}
+	return path
+}
@@ -0,0 +1,99 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shoppingSchema = `{
+	"title": "Weekly shop",
+	"type": "object",
+	"required": ["store", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"store": {"type": "string", "minLength": 1},
+		"day": {"enum": ["saturday", "sunday"]},
+		"budget": {"type": "number", "minimum": 0, "exclusiveMaximum": 1000},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"uniqueItems": true,
+			"items": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "pattern": "^[a-z ]+$"},
+					"count": {"type": "integer"},
+					"by": {"type": "string", "format": "date"}
+				}
+			}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(shoppingSchema))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, s.Validate([]byte(`{"store": "Co-op", "day": "sunday", "budget": 80, "items": [{"name": "milk", "count": 2, "by": "2025-09-06"}]}`)))
+
+	assert.Equal(t, []FieldError{
+		{"/items", "is required"},
+		{"/budget", "must be less than 1000"},
+		{"/day", `must be one of "saturday", "sunday"`},
+		{"/note", "is not allowed"},
+		{"/store", "must be string"},
+	}, s.Validate([]byte(`{"store": 3, "day": "monday", "budget": 1000, "note": "x"}`)))
+
+	assert.Equal(t, []FieldError{
+		{"/items", "must not repeat items"},
+		{"/items/0/count", "must be integer"},
+		{"/items/0/name", "must match ^[a-z ]+$"},
+		{"/items/1/name", "is required"},
+		{"/items/1/by", "must be a valid date"},
+	}, s.Validate([]byte(`{"store": "Co-op", "items": [{"name": "Milk!", "count": 1.5}, {"by": "Friday"}, {"name": "eggs"}, {"name": "eggs"}]}`)))
+
+	assert.Equal(t, []FieldError{{"", "must be object"}}, s.Validate([]byte(`["milk"]`)))
+	assert.Equal(t, []FieldError{{"", "must be valid JSON"}}, s.Validate([]byte(`buy milk`)))
+}
+
+func TestValidateCombinators(t *testing.T) {
+	s, err := Parse([]byte(`{"anyOf": [{"type": "string", "format": "email"}, {"type": "string", "format": "uri"}], "not": {"const": "https://example.com"}}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, s.Validate([]byte(`"alex@example.com"`)))
+	assert.Nil(t, s.Validate([]byte(`"https://example.org/recipes"`)))
+	assert.Equal(t, []FieldError{{"", "must match at least one of the allowed schemas"}}, s.Validate([]byte(`"call mum"`)))
+	assert.Equal(t, []FieldError{{"", "must not match the disallowed schema"}}, s.Validate([]byte(`"https://example.com"`)))
+
+	reject, err := Parse([]byte(`false`))
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldError{{"", "is not allowed"}}, reject.Validate([]byte(`{}`)))
+}
+
+func TestParseRejectsUnsupportedSchemas(t *testing.T) {
+	for in, msg := range map[string]string{
+		`{"type": "object", "$ref": "#/$defs/x"}`:     `schema: unsupported keyword "$ref"`,
+		`{"properties": {"a": {"oneOf": [true]}}}`:    `/properties/a: unsupported keyword "oneOf"`,
+		`{"type": "decimal"}`:                         `/type: unknown type "decimal"`,
+		`{"format": "phone"}`:                         `/format: unsupported format phone`,
+		`{"minLength": -1}`:                           `/minLength: must be a non-negative integer`,
+		`{"pattern": "("}`:                            "/pattern: error parsing regexp: missing closing ): `(`",
+		`{"items": 3}`:                                `/items: a schema must be an object or a boolean`,
+		`{"required": "name"}`:                        `/required: must be an array of strings`,
+		`"object"`:                                    `schema: a schema must be an object or a boolean`,
+		`{"properties": {"a/b": {"enum": []}}}`:       `/properties/a~1b/enum: must be a non-empty array`,
+		`{"allOf": [{"type": "string"}, {"if": {}}]}`: `/allOf/1: unsupported keyword "if"`,
+	} {
+		_, err := Parse([]byte(in))
+		assert.EqualError(t, err, msg, in)
+	}
+
+	_, err := Parse([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+// Load test for the endpoints an assistant waits on: bootstrap when it
+// starts, and tools/call (list_todos and get_briefing) during its turns.
+//
+//   BASE_URL=http://localhost:8080 API_KEY=ak_... SLACK_ID=U123 k6 run scripts/loadtest/k6.js
+//
+// API_KEY needs the mcp:write scope; `go run main.go seed` prints one.
+// SLACK_ID is a user's Slack ID, for bootstrap, which is skipped without
+// it. RATE sets requests per second for each scenario (default 20) and
+// DURATION how long they run (default 1m).
+//
+// The thresholds are the latency budgets README.md documents; k6 exits
+// non-zero when one is exceeded.
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const baseURL = (__ENV.BASE_URL || 'http://localhost:8080').replace(/\/$/, '');
+const rate = Number(__ENV.RATE || 20);
+const duration = __ENV.DURATION || '1m';
+const auth = __ENV.API_KEY ? { Authorization: `Bearer ${__ENV.API_KEY}` } : {};
+
+function scenario(exec) {
+  return {
+    executor: 'constant-arrival-rate',
+    exec,
+    rate,
+    timeUnit: '1s',
+    duration,
+    preAllocatedVUs: Math.max(5, rate),
+  };
+}
+
+const scenarios = {
+  list_todos: scenario('listTodos'),
+  get_briefing: scenario('getBriefing'),
+};
+if (__ENV.SLACK_ID) {
+  scenarios.bootstrap = scenario('bootstrap');
+}
+
+export const options = {
+  scenarios,
+  thresholds: {
+    'http_req_failed': ['rate<0.01'],
+    'http_req_duration{endpoint:bootstrap}': ['p(95)<300', 'p(99)<600'],
+    'http_req_duration{endpoint:list_todos}': ['p(95)<150', 'p(99)<300'],
+    'http_req_duration{endpoint:get_briefing}': ['p(95)<250', 'p(99)<500'],
+  },
+};
+
+// setup opens the MCP session every tools/call is made in.
+export function setup() {
+  const res = http.post(`${baseURL}/mcp`, JSON.stringify({
+    jsonrpc: '2.0',
+    id: 1,
+    method: 'initialize',
+    params: {
+      protocolVersion: '2025-06-18',
+      capabilities: {},
+      clientInfo: { name: 'k6', version: '1.0.0' },
+    },
+  }), { headers: Object.assign({ 'Content-Type': 'application/json' }, auth) });
+  if (res.status !== 200) {
+    fail(`initialize returned ${res.status}: ${res.body}`);
+  }
+  return { session: res.headers['Mcp-Session-Id'] };
+}
+
+function callTool(data, name, args) {
+  const res = http.post(`${baseURL}/mcp`, JSON.stringify({
+    jsonrpc: '2.0',
+    id: 2,
+    method: 'tools/call',
+    params: { name, arguments: args },
+  }), {
+    headers: Object.assign({ 'Content-Type': 'application/json', 'Mcp-Session-Id': data.session }, auth),
+    tags: { endpoint: name },
+  });
+  check(res, {
+    [`${name} is 200`]: (r) => r.status === 200,
+    [`${name} succeeds`]: (r) => r.status === 200 && r.json('result.isError') !== true,
+  });
+}
+
+export function listTodos(data) {
+  callTool(data, 'list_todos', { limit: 20 });
+}
+
+export function getBriefing(data) {
+  callTool(data, 'get_briefing', {});
+}
+
+export function bootstrap() {
+  const res = http.get(`${baseURL}/bootstrap?slack_id=${encodeURIComponent(__ENV.SLACK_ID)}`, {
+    headers: auth,
+    tags: { endpoint: 'bootstrap' },
+  });
+  check(res, { 'bootstrap is 200': (r) => r.status === 200 });
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// healthTimeout caps how long /healthz waits on the database.
+const healthTimeout = 2 * time.Second
+
+// NewAdmin serves the operational endpoints: /healthz for container
+// health checks and /metrics in the Prometheus text format, which anyone
+// who can reach the port may call, and, when token is set, /debug/vars,
+// /debug/runtime and /debug/pprof/ for callers presenting it as a bearer
+// token. The port belongs where only operators and orchestrators can
+// reach it. Requests aren't logged, as health checks and scrapes would
+// drown out the rest.
+func NewAdmin(db pinger, token string) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/healthz", healthz(db))
+	r.Get("/metrics", metrics)
+	if token == "" {
+		return r
+	}
+	r.Route("/debug", func(r chi.Router) {
+		r.Use(adminToken(token))
+		r.Handle("/vars", expvar.Handler())
+		r.Get("/runtime", runtimeStats)
+		r.HandleFunc("/pprof/cmdline", pprof.Cmdline)
+		r.HandleFunc("/pprof/profile", pprof.Profile)
+		r.HandleFunc("/pprof/symbol", pprof.Symbol)
+		r.HandleFunc("/pprof/trace", pprof.Trace)
+		r.HandleFunc("/pprof/*", pprof.Index)
+	})
+	return r
+}
+
+// adminToken turns away requests without "Authorization: Bearer <token>".
+func adminToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// healthz answers 200 while the database does, and 503 when it doesn't.
+func healthz(db pinger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+		defer cancel()
+		w.Header().Set("Content-Type", "application/json")
+		if err := db.Ping(ctx); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "unavailable", "error": err.Error()})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}
+}
+
+// metrics writes the Go runtime's statistics and every numeric expvar,
+// such as the DAO's retry counters, for Prometheus to scrape. A map's
+// entries become one series each, labelled by key.
+func metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	stats := readRuntimeStats()
+	writeMetric(w, "go_goroutines", "gauge", "", float64(stats.Goroutines))
+	writeMetric(w, "go_memstats_alloc_bytes", "gauge", "", float64(stats.HeapAllocBytes))
+	writeMetric(w, "go_memstats_heap_inuse_bytes", "gauge", "", float64(stats.HeapInuseBytes))
+	writeMetric(w, "go_memstats_heap_objects", "gauge", "", float64(stats.HeapObjects))
+	writeMetric(w, "go_memstats_sys_bytes", "gauge", "", float64(stats.SysBytes))
+	writeMetric(w, "go_gc_cycles_total", "counter", "", float64(stats.GCCycles))
+	expvar.Do(func(kv expvar.KeyValue) {
+		name := metricName(kv.Key)
+		if m, ok := kv.Value.(*expvar.Map); ok {
+			fmt.Fprintf(w, "# TYPE %s untyped\n", name)
+			m.Do(func(entry expvar.KeyValue) {
+				if value, ok := expvarNumber(entry.Value); ok {
+					writeMetric(w, name, "", fmt.Sprintf("{key=%q}", entry.Key), value)
+				}
+			})
+		} else if value, ok := expvarNumber(kv.Value); ok {
+			writeMetric(w, name, "untyped", "", value)
+		}
+	})
+}
+
+// RuntimeStats is a snapshot of the process's goroutines, heap and
+// garbage collector, for telling whether memory keeps growing and why.
+type RuntimeStats struct {
+	Goroutines int `json:"goroutines"`
+	// EventSubscribers counts open dashboard WebSockets and MCP resource
+	// streams, each of which holds a goroutine and a buffer.
+	EventSubscribers int64  `json:"event_subscribers"`
+	HeapAllocBytes   uint64 `json:"heap_alloc_bytes"`
+	HeapInuseBytes   uint64 `json:"heap_inuse_bytes"`
+	HeapObjects      uint64 `json:"heap_objects"`
+	SysBytes         uint64 `json:"sys_bytes"`
+	// NextGCBytes is the heap size the next collection starts at.
+	NextGCBytes   uint64     `json:"next_gc_bytes"`
+	GCCycles      uint32     `json:"gc_cycles"`
+	GCPauseTotal  string     `json:"gc_pause_total"`
+	GCCPUFraction float64    `json:"gc_cpu_fraction"`
+	LastGC        *time.Time `json:"last_gc,omitempty"`
+}
+
+func readRuntimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	out := RuntimeStats{
+		Goroutines:       runtime.NumGoroutine(),
+		EventSubscribers: eventSubscribers.Value(),
+		HeapAllocBytes:   mem.HeapAlloc,
+		HeapInuseBytes:   mem.HeapInuse,
+		HeapObjects:      mem.HeapObjects,
+		SysBytes:         mem.Sys,
+		NextGCBytes:      mem.NextGC,
+		GCCycles:         mem.NumGC,
+		GCPauseTotal:     time.Duration(mem.PauseTotalNs).String(),
+		GCCPUFraction:    mem.GCCPUFraction,
+	}
+	if mem.LastGC > 0 {
+		last := time.Unix(0, int64(mem.LastGC)).UTC()
+		out.LastGC = &last
+	}
+	return out
+}
+
+// runtimeStats serves GET /debug/runtime. ?gc=true collects garbage
+// first, so what remains is what is still referenced.
+func runtimeStats(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("gc") == "true" {
+		runtime.GC()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(readRuntimeStats())
+}
+
+func expvarNumber(v expvar.Var) (float64, bool) {
+	switch v := v.(type) {
+	case *expvar.Int:
+		return float64(v.Value()), true
+	case *expvar.Float:
+		return v.Value(), true
+	}
+	return 0, false
+}
+
+// writeMetric writes one sample, preceded by its TYPE line unless typ is
+// empty.
+func writeMetric(w io.Writer, name, typ, labels string, value float64) {
+	if typ != "" {
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+	}
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+func metricName(key string) string {
+	return invalidMetricChars.ReplaceAllString(key, "_")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePinger struct{ err error }
+
+func (f fakePinger) Ping(ctx context.Context) error { return f.err }
+
+func TestAdminHealthz(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NewAdmin(fakePinger{}, "").ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	NewAdmin(fakePinger{errors.New("connection refused")}, "").ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status": "unavailable", "error": "connection refused"}`, rr.Body.String())
+}
+
+func TestAdminMetrics(t *testing.T) {
+	expvar.NewInt("admin_test.count").Set(3)
+	m := expvar.NewMap("admin_test_by_reason")
+	m.Add("40001", 2)
+	m.Add("connection", 1)
+
+	rr := httptest.NewRecorder()
+	NewAdmin(fakePinger{}, "").ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.Contains(t, body, "# TYPE go_goroutines gauge\ngo_goroutines ")
+	assert.Contains(t, body, "# TYPE admin_test_count untyped\nadmin_test_count 3\n")
+	assert.Contains(t, body, "# TYPE admin_test_by_reason untyped\nadmin_test_by_reason{key=\"40001\"} 2\nadmin_test_by_reason{key=\"connection\"} 1\n")
+	assert.NotContains(t, body, "cmdline")
+}
+
+func TestAdminDebug(t *testing.T) {
+	admin := NewAdmin(fakePinger{}, "s3cret")
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		admin.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for _, path := range []string{"/debug/vars", "/debug/runtime", "/debug/pprof/", "/debug/pprof/goroutine?debug=1"} {
+		assert.Equal(t, http.StatusOK, get(path, "s3cret").Code, path)
+		assert.Equal(t, http.StatusUnauthorized, get(path, "").Code, path)
+		assert.Equal(t, http.StatusUnauthorized, get(path, "guess").Code, path)
+	}
+	// Health checks and scrapes need no token.
+	assert.Equal(t, http.StatusOK, get("/healthz", "").Code)
+	assert.Equal(t, http.StatusOK, get("/metrics", "").Code)
+
+	// Without a token there are no debug endpoints at all.
+	rr := httptest.NewRecorder()
+	NewAdmin(fakePinger{}, "").ServeHTTP(rr, httptest.NewRequest("GET", "/debug/pprof/", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAdminRuntimeStats(t *testing.T) {
+	hub := NewEventHub()
+	before := eventSubscribers.Value()
+	_, unsubscribe := hub.Subscribe("house-1")
+
+	req := httptest.NewRequest("GET", "/debug/runtime?gc=true", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rr := httptest.NewRecorder()
+	NewAdmin(fakePinger{}, "s3cret").ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var stats RuntimeStats
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Positive(t, stats.Goroutines)
+	assert.Positive(t, stats.HeapAllocBytes)
+	assert.Positive(t, stats.GCCycles)
+	assert.NotNil(t, stats.LastGC)
+	assert.Equal(t, before+1, stats.EventSubscribers)
+
+	unsubscribe()
+	unsubscribe()
+	assert.Equal(t, before, eventSubscribers.Value())
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// API key scopes. mcp:read keys only see read-only MCP tools, mcp:write keys
+// see every tool, and mcp:tool:<name> grants a single tool by name.
+// role:<name> scopes (ScopeRolePrefix) assign roles for the authorization
+// Policy rather than granting tools.
+const (
+	ScopeAll        = "*"
+	ScopeMCPRead    = "mcp:read"
+	ScopeMCPWrite   = "mcp:write"
+	ScopeToolPrefix = "mcp:tool:"
+)
+
+const apiKeyPrefix = "ak_"
+
+type apiKeyDAO interface {
+	CreateAPIKey(ctx context.Context, k dao.APIKeys) (dao.APIKeys, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (dao.APIKeys, error)
+	ListAPIKeysByUserUID(ctx context.Context, userUID string) ([]dao.APIKeys, error)
+	RevokeAPIKey(ctx context.Context, uid string) error
+	TouchAPIKey(ctx context.Context, uid string) error
+}
+
+type APIKeyHandlers struct{ dao apiKeyDAO }
+
+type CreateAPIKeyRequest struct {
+	UserUID string   `json:"user_uid"`
+	Name    string   `json:"name"`
+	Scopes  []string `json:"scopes"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key. It is only ever returned
+// once; the server stores a hash.
+type CreateAPIKeyResponse struct {
+	Key    string      `json:"key"`
+	APIKey dao.APIKeys `json:"api_key"`
+}
+
+// NewAPIKeys manages API keys. Callers with an API key manage their own
+// user's keys, and can only grant scopes their key holds; operators manage
+// anyone's. Callers with neither are refused, so keys can't be minted for
+// other users.
+func NewAPIKeys(dao apiKeyDAO) http.Handler {
+	h := &APIKeyHandlers{dao}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Delete("/{uid}", h.revoke)
+	return r
+}
+
+// keyOwner returns the user whose keys the caller may manage: requested,
+// which operators must give, or else the caller's own user. It writes the
+// response and returns false when the caller may not.
+func keyOwner(w http.ResponseWriter, r *http.Request, requested string) (string, bool) {
+	if IsOperator(r.Context()) {
+		if requested == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return "", false
+		}
+		return requested, true
+	}
+	id, ok := IdentityFromContext(r.Context())
+	if !ok || id.UserUID == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return "", false
+	}
+	if requested != "" && requested != id.UserUID {
+		w.WriteHeader(http.StatusForbidden)
+		return "", false
+	}
+	return id.UserUID, true
+}
+
+func (h *APIKeyHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if json.NewDecoder(r.Body).Decode(&req) != nil || req.Name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if len(req.Scopes) == 0 {
+		req.Scopes = []string{ScopeMCPWrite}
+	}
+	for _, scope := range req.Scopes {
+		if !validScope(scope) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unknown scope: " + scope})
+			return
+		}
+	}
+	userUID, ok := keyOwner(w, r, req.UserUID)
+	if !ok {
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok && !IsOperator(r.Context()) {
+		for _, scope := range req.Scopes {
+			if !grantsScope(id, scope) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "your API key doesn't hold scope: " + scope})
+				return
+			}
+		}
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	out, err := h.dao.CreateAPIKey(r.Context(), dao.APIKeys{
+		UserUID: userUID,
+		Name:    req.Name,
+		KeyHash: hashAPIKey(key),
+		Scopes:  req.Scopes,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(CreateAPIKeyResponse{Key: key, APIKey: out})
+}
+
+func (h *APIKeyHandlers) list(w http.ResponseWriter, r *http.Request) {
+	userUID, ok := keyOwner(w, r, r.URL.Query().Get("user_uid"))
+	if !ok {
+		return
+	}
+	out, err := h.dao.ListAPIKeysByUserUID(r.Context(), userUID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// revoke revokes a key. Callers with an API key can only revoke their own
+// user's keys; other keys are not found.
+func (h *APIKeyHandlers) revoke(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	if !IsOperator(r.Context()) {
+		userUID, ok := keyOwner(w, r, "")
+		if !ok {
+			return
+		}
+		owned, err := h.dao.ListAPIKeysByUserUID(r.Context(), userUID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !slices.ContainsFunc(owned, func(k dao.APIKeys) bool { return k.UID == uid }) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+	if h.dao.RevokeAPIKey(r.Context(), uid) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validScope(scope string) bool {
+	switch scope {
+	case ScopeAll, ScopeMCPRead, ScopeMCPWrite:
+		return true
+	}
+	for _, prefix := range []string{ScopeToolPrefix, ScopeRolePrefix} {
+		if strings.HasPrefix(scope, prefix) && len(scope) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// grantsScope reports whether a key with id's scopes may create a key with
+// scope: one it holds itself, or an MCP scope narrower than its mcp:write.
+func grantsScope(id Identity, scope string) bool {
+	if id.HasScope(scope) {
+		return true
+	}
+	narrower := scope == ScopeMCPRead || strings.HasPrefix(scope, ScopeToolPrefix)
+	return narrower && id.HasScope(ScopeMCPWrite)
+}
+
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Identity is the caller resolved from an API key.
+type Identity struct {
+	APIKeyUID    string
+	UserUID      string
+	HouseholdUID string
+	TenantUID    string
+	Scopes       []string
+}
+
+// HasScope reports whether the identity was granted scope, either directly or
+// through the wildcard scope.
+func (i Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, ScopeAll) || slices.Contains(i.Scopes, scope)
+}
+
+type identityKey struct{}
+type operatorKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// WithOperator marks ctx as an operator's: a caller who presented the
+// operator token rather than an API key, and may act for anyone.
+func WithOperator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operatorKey{}, true)
+}
+
+func IsOperator(ctx context.Context) bool {
+	operator, _ := ctx.Value(operatorKey{}).(bool)
+	return operator
+}
+
+// OperatorAuth marks requests presenting token as "Authorization: Bearer
+// <token>" as operators'. Other requests are passed through untouched, and
+// with no token set there are no operators.
+func OperatorAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token != "" && ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+				r = r.WithContext(WithOperator(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerAPIKey extracts an API key from the Authorization or X-API-Key header.
+func bearerAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// APIKeyAuth resolves the request's API key into an Identity on the request
+// context. Requests without a key are passed through untouched unless
+// required is set; requests with an unknown or revoked key are rejected.
+// Requests already carrying an Identity, and operators' requests, are not
+// looked up.
+func APIKeyAuth(keys apiKeyDAO, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := IdentityFromContext(r.Context()); ok || IsOperator(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := bearerAPIKey(r)
+			if key == "" {
+				if required {
+					http.Error(w, "Missing API key", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The key's tenant isn't known until it is found.
+			lookup := dao.WithAllTenants(r.Context())
+			k, err := keys.GetAPIKeyByHash(lookup, hashAPIKey(key))
+			if err != nil {
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+			if err := keys.TouchAPIKey(lookup, k.UID); err != nil {
+				slog.Warn("Failed to record API key use", slog.String("api_key_uid", k.UID), slog.String("error", err.Error()))
+			}
+
+			id := Identity{APIKeyUID: k.UID, UserUID: k.UserUID, TenantUID: k.TenantUID, Scopes: k.Scopes}
+			if k.HouseholdUID != nil {
+				id.HouseholdUID = *k.HouseholdUID
+			}
+			// Everything the key does from here on is confined to its tenant,
+			// and to its user's household.
+			ctx := dao.WithTenant(WithIdentity(r.Context(), id), id.TenantUID)
+			ctx = dao.WithScope(ctx, id.UserUID, id.HouseholdUID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeysCreate(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+
+	var storedHash string
+	mockDAO.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(k postgres.APIKeys) bool {
+		storedHash = k.KeyHash
+		return k.UserUID == "user-123" && k.Name == "laptop" && len(k.Scopes) == 1 && k.Scopes[0] == ScopeMCPRead
+	})).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-123", Name: "laptop", Scopes: []string{ScopeMCPRead}}, nil)
+
+	handler := NewAPIKeys(mockDAO)
+	req := asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"user_uid":"user-123","name":"laptop","scopes":["mcp:read"]}`)))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp CreateAPIKeyResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.True(t, strings.HasPrefix(resp.Key, apiKeyPrefix))
+	assert.Equal(t, hashAPIKey(resp.Key), storedHash)
+	assert.Equal(t, "key-1", resp.APIKey.UID)
+	assert.NotContains(t, rr.Body.String(), "key_hash")
+}
+
+func TestAPIKeysCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing user", body: `{"name":"laptop"}`},
+		{name: "missing name", body: `{"user_uid":"user-123"}`},
+		{name: "unknown scope", body: `{"user_uid":"user-123","name":"laptop","scopes":["admin"]}`},
+		{name: "empty tool scope", body: `{"user_uid":"user-123","name":"laptop","scopes":["mcp:tool:"]}`},
+		{name: "invalid json", body: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAPIKeys(mocks.NewMockapiKeyDAO(t))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(tt.body))))
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestAPIKeysCreate_DefaultsToWriteScope(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	mockDAO.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(k postgres.APIKeys) bool {
+		return len(k.Scopes) == 1 && k.Scopes[0] == ScopeMCPWrite
+	})).Return(postgres.APIKeys{UID: "key-1"}, nil)
+
+	rr := httptest.NewRecorder()
+	NewAPIKeys(mockDAO).ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"user_uid":"user-123","name":"laptop"}`))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAPIKeysList(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	mockDAO.On("ListAPIKeysByUserUID", mock.Anything, "user-123").Return([]postgres.APIKeys{{UID: "key-1"}, {UID: "key-2"}}, nil)
+
+	rr := httptest.NewRecorder()
+	NewAPIKeys(mockDAO).ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/?user_uid=user-123", nil)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var keys []postgres.APIKeys
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &keys))
+	assert.Len(t, keys, 2)
+
+	rr = httptest.NewRecorder()
+	NewAPIKeys(mockDAO).ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAPIKeysRevoke(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	mockDAO.On("RevokeAPIKey", mock.Anything, "key-1").Return(nil)
+
+	rr := httptest.NewRecorder()
+	NewAPIKeys(mockDAO).ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/key-1", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func asOperator(r *http.Request) *http.Request {
+	return r.WithContext(WithOperator(r.Context()))
+}
+
+func TestAPIKeys_CallerManagesOwnKeys(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	handler := NewAPIKeys(mockDAO)
+	serve := func(ctx context.Context, method, target, body string) int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx))
+		return rr.Code
+	}
+	writer := WithIdentity(context.Background(), Identity{APIKeyUID: "key-1", UserUID: "user-1", Scopes: []string{ScopeMCPWrite}})
+
+	// Without a key or the operator token, nothing is allowed.
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "POST", "/", `{"user_uid":"user-2","name":"laptop","scopes":["*"]}`))
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "GET", "/?user_uid=user-2", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "DELETE", "/key-9", ""))
+
+	// A key works for its own user, with scopes it holds or narrower ones.
+	mockDAO.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(k postgres.APIKeys) bool {
+		return k.UserUID == "user-1" && k.Scopes[0] == ScopeMCPRead
+	})).Return(postgres.APIKeys{UID: "key-3"}, nil).Once()
+	assert.Equal(t, http.StatusOK, serve(writer, "POST", "/", `{"name":"phone","scopes":["mcp:read"]}`))
+	assert.Equal(t, http.StatusForbidden, serve(writer, "POST", "/", `{"name":"phone","scopes":["*"]}`))
+	assert.Equal(t, http.StatusForbidden, serve(writer, "POST", "/", `{"name":"phone","scopes":["role:parent"]}`))
+	assert.Equal(t, http.StatusForbidden, serve(writer, "POST", "/", `{"user_uid":"user-2","name":"phone"}`))
+
+	mockDAO.On("ListAPIKeysByUserUID", mock.Anything, "user-1").Return([]postgres.APIKeys{{UID: "key-1"}, {UID: "key-3"}}, nil)
+	assert.Equal(t, http.StatusOK, serve(writer, "GET", "/", ""))
+	assert.Equal(t, http.StatusForbidden, serve(writer, "GET", "/?user_uid=user-2", ""))
+
+	mockDAO.On("RevokeAPIKey", mock.Anything, "key-3").Return(nil).Once()
+	assert.Equal(t, http.StatusNoContent, serve(writer, "DELETE", "/key-3", ""))
+	assert.Equal(t, http.StatusNotFound, serve(writer, "DELETE", "/key-9", ""))
+}
+
+func TestOperatorAuth(t *testing.T) {
+	var operator bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { operator = IsOperator(r.Context()) })
+	for _, c := range []struct {
+		token, auth string
+		want        bool
+	}{
+		{"op-secret", "Bearer op-secret", true},
+		{"op-secret", "Bearer ak_other", false},
+		{"op-secret", "", false},
+		{"", "Bearer ", false},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", c.auth)
+		OperatorAuth(c.token)(next).ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, c.want, operator, c.auth)
+	}
+
+	// The operator token isn't looked up as an API key.
+	req := asOperator(httptest.NewRequest("GET", "/", nil))
+	req.Header.Set("Authorization", "Bearer op-secret")
+	rr := httptest.NewRecorder()
+	APIKeyAuth(mocks.NewMockapiKeyDAO(t), true)(next).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	household := "household-1"
+	var seen *Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := IdentityFromContext(r.Context()); ok {
+			seen = &id
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("valid bearer key", func(t *testing.T) {
+		seen = nil
+		mockDAO := mocks.NewMockapiKeyDAO(t)
+		mockDAO.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_good")).
+			Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", HouseholdUID: &household, Scopes: []string{ScopeMCPRead}}, nil)
+		mockDAO.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Authorization", "Bearer ak_good")
+		rr := httptest.NewRecorder()
+		APIKeyAuth(mockDAO, true)(next).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		if assert.NotNil(t, seen) {
+			assert.Equal(t, Identity{APIKeyUID: "key-1", UserUID: "user-1", HouseholdUID: "household-1", Scopes: []string{ScopeMCPRead}}, *seen)
+		}
+	})
+
+	t.Run("X-API-Key header", func(t *testing.T) {
+		seen = nil
+		mockDAO := mocks.NewMockapiKeyDAO(t)
+		mockDAO.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_good")).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1"}, nil)
+		mockDAO.On("TouchAPIKey", mock.Anything, "key-1").Return(errors.New("db down"))
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("X-API-Key", "ak_good")
+		rr := httptest.NewRecorder()
+		APIKeyAuth(mockDAO, false)(next).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotNil(t, seen)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		mockDAO := mocks.NewMockapiKeyDAO(t)
+		mockDAO.On("GetAPIKeyByHash", mock.Anything, mock.Anything).Return(postgres.APIKeys{}, errors.New("no rows"))
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Authorization", "Bearer ak_bad")
+		rr := httptest.NewRecorder()
+		APIKeyAuth(mockDAO, false)(next).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		seen = nil
+		rr := httptest.NewRecorder()
+		APIKeyAuth(mocks.NewMockapiKeyDAO(t), false)(next).ServeHTTP(rr, httptest.NewRequest("POST", "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, seen)
+
+		rr = httptest.NewRecorder()
+		APIKeyAuth(mocks.NewMockapiKeyDAO(t), true)(next).ServeHTTP(rr, httptest.NewRequest("POST", "/", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type apiUsageDAO interface {
+	AddAPIUsage(ctx context.Context, usage []dao.APIUsage) error
+	ListAPIUsage(ctx context.Context, userUID string, from, to time.Time) ([]dao.APIUsage, error)
+}
+
+const (
+	// defaultUsageDays is how many days, up to today, a usage report covers
+	// when it isn't given from.
+	defaultUsageDays = 30
+	// maxUsageDays bounds the days one usage report covers.
+	maxUsageDays = 366
+	// usageFlushTimeout bounds the final flush when the server stops.
+	usageFlushTimeout = 10 * time.Second
+)
+
+type usageKey struct {
+	apiKeyUID string
+	day       time.Time
+}
+
+type usageCount struct{ requests, toolCalls int64 }
+
+// UsageMeter counts the requests and MCP tool calls made with each API key
+// in memory, and adds them to the api_usage table every interval, so
+// counting costs a request no database write. Counts not yet flushed when
+// a server dies are lost. A nil *UsageMeter counts nothing.
+type UsageMeter struct {
+	dao      apiUsageDAO
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	counts map[usageKey]usageCount
+}
+
+func NewUsageMeter(dao apiUsageDAO, interval time.Duration) *UsageMeter {
+	return &UsageMeter{dao: dao, interval: interval, now: time.Now, counts: map[usageKey]usageCount{}}
+}
+
+func (m *UsageMeter) add(apiKeyUID string, c usageCount) {
+	if m == nil || apiKeyUID == "" {
+		return
+	}
+	y, mo, d := m.now().UTC().Date()
+	k := usageKey{apiKeyUID, time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)}
+	m.mu.Lock()
+	sum := m.counts[k]
+	sum.requests += c.requests
+	sum.toolCalls += c.toolCalls
+	m.counts[k] = sum
+	m.mu.Unlock()
+}
+
+// countToolCall counts a tool call by the API key in ctx, if any.
+func (m *UsageMeter) countToolCall(ctx context.Context) {
+	if id, ok := IdentityFromContext(ctx); ok {
+		m.add(id.APIKeyUID, usageCount{toolCalls: 1})
+	}
+}
+
+// Keys returns keys with each use of a key, which APIKeyAuth records on
+// every request it authenticates, also counted as a request.
+func (m *UsageMeter) Keys(keys apiKeyDAO) apiKeyDAO {
+	return &meteredKeys{apiKeyDAO: keys, meter: m}
+}
+
+type meteredKeys struct {
+	apiKeyDAO
+	meter *UsageMeter
+}
+
+func (k *meteredKeys) TouchAPIKey(ctx context.Context, uid string) error {
+	k.meter.add(uid, usageCount{requests: 1})
+	return k.apiKeyDAO.TouchAPIKey(ctx, uid)
+}
+
+// Flush adds the counts made since the last flush to the database. If that
+// fails they are kept, to be added by the next flush.
+func (m *UsageMeter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = map[usageKey]usageCount{}
+	m.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+	usage := make([]dao.APIUsage, 0, len(counts))
+	for k, c := range counts {
+		usage = append(usage, dao.APIUsage{APIKeyUID: k.apiKeyUID, Day: k.day, Requests: c.requests, ToolCalls: c.toolCalls})
+	}
+	err := m.dao.AddAPIUsage(ctx, usage)
+	if err != nil {
+		m.mu.Lock()
+		for k, c := range counts {
+			sum := m.counts[k]
+			sum.requests += c.requests
+			sum.toolCalls += c.toolCalls
+			m.counts[k] = sum
+		}
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// Run flushes every interval until ctx is done, and then once more.
+func (m *UsageMeter) Run(ctx context.Context) {
+	tick := time.NewTicker(m.interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if err := m.Flush(ctx); err != nil {
+				slog.Warn("Failed to record API usage", "error", err)
+			}
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageFlushTimeout)
+			defer cancel()
+			if err := m.Flush(flushCtx); err != nil {
+				slog.Error("Failed to record API usage on shutdown", "error", err)
+			}
+			return
+		}
+	}
+}
+
+// WithUsageMeter counts every MCP tool call made with an API key.
+func WithUsageMeter(m *UsageMeter) MCPOption {
+	return func(h *MCPHandlers) {
+		h.usage = m
+	}
+}
+
+// APIUsageReport totals the requests and tool calls made with API keys
+// from From to To, by key, by day and, for operators, by user, heaviest
+// first.
+type APIUsageReport struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Requests  int64           `json:"requests"`
+	ToolCalls int64           `json:"tool_calls"`
+	Users     []APIUsageTotal `json:"users,omitempty"`
+	Keys      []APIUsageTotal `json:"keys"`
+	Days      []APIUsageDay   `json:"days"`
+}
+
+// APIUsageTotal is one key's or, without an APIKeyUID, one user's usage.
+type APIUsageTotal struct {
+	APIKeyUID    string  `json:"api_key_uid,omitempty"`
+	KeyName      string  `json:"key_name,omitempty"`
+	UserUID      string  `json:"user_uid"`
+	UserName     string  `json:"user_name"`
+	HouseholdUID *string `json:"household_uid"`
+	Requests     int64   `json:"requests"`
+	ToolCalls    int64   `json:"tool_calls"`
+}
+
+type APIUsageDay struct {
+	Day       string `json:"day"`
+	Requests  int64  `json:"requests"`
+	ToolCalls int64  `json:"tool_calls"`
+}
+
+// summarizeAPIUsage totals rows, by user too when byUser is set.
+func summarizeAPIUsage(rows []dao.APIUsage, from, to time.Time, byUser bool) APIUsageReport {
+	out := APIUsageReport{From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Keys: []APIUsageTotal{}, Days: []APIUsageDay{}}
+	keys, users := map[string]*APIUsageTotal{}, map[string]*APIUsageTotal{}
+	days := map[string]*APIUsageDay{}
+	for _, row := range rows {
+		out.Requests += row.Requests
+		out.ToolCalls += row.ToolCalls
+		k, ok := keys[row.APIKeyUID]
+		if !ok {
+			k = &APIUsageTotal{APIKeyUID: row.APIKeyUID, KeyName: row.KeyName, UserUID: row.UserUID, UserName: row.UserName, HouseholdUID: row.HouseholdUID}
+			keys[row.APIKeyUID] = k
+		}
+		k.Requests += row.Requests
+		k.ToolCalls += row.ToolCalls
+		if byUser {
+			u, ok := users[row.UserUID]
+			if !ok {
+				u = &APIUsageTotal{UserUID: row.UserUID, UserName: row.UserName, HouseholdUID: row.HouseholdUID}
+				users[row.UserUID] = u
+			}
+			u.Requests += row.Requests
+			u.ToolCalls += row.ToolCalls
+		}
+		day := row.Day.Format(time.DateOnly)
+		d, ok := days[day]
+		if !ok {
+			d = &APIUsageDay{Day: day}
+			days[day] = d
+		}
+		d.Requests += row.Requests
+		d.ToolCalls += row.ToolCalls
+	}
+	heaviest := func(totals map[string]*APIUsageTotal) []APIUsageTotal {
+		out := make([]APIUsageTotal, 0, len(totals))
+		for _, t := range totals {
+			out = append(out, *t)
+		}
+		slices.SortFunc(out, func(a, b APIUsageTotal) int {
+			return cmp.Or(cmp.Compare(b.Requests+b.ToolCalls, a.Requests+a.ToolCalls), cmp.Compare(a.APIKeyUID+a.UserUID, b.APIKeyUID+b.UserUID))
+		})
+		return out
+	}
+	out.Keys = heaviest(keys)
+	if byUser {
+		out.Users = heaviest(users)
+	}
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		out.Days = append(out.Days, *days[day])
+	}
+	return out
+}
+
+// usageRange reads a report's ?from= and ?to=, as YYYY-MM-DD, defaulting
+// to the defaultUsageDays up to today. It writes a 400 and returns false
+// when they are invalid.
+func usageRange(w http.ResponseWriter, r *http.Request, now time.Time) (from, to time.Time, ok bool) {
+	y, m, d := now.UTC().Date()
+	to = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	fail := func(msg string) (time.Time, time.Time, bool) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+		return time.Time{}, time.Time{}, false
+	}
+	var err error
+	if s := r.URL.Query().Get("to"); s != "" {
+		if to, err = time.Parse(time.DateOnly, s); err != nil {
+			return fail("to must be a date like 2025-09-30")
+		}
+	}
+	from = to.AddDate(0, 0, 1-defaultUsageDays)
+	if s := r.URL.Query().Get("from"); s != "" {
+		if from, err = time.Parse(time.DateOnly, s); err != nil {
+			return fail("from must be a date like 2025-09-01")
+		}
+	}
+	switch {
+	case from.After(to):
+		return fail("from must not be after to")
+	case to.Sub(from) >= maxUsageDays*24*time.Hour:
+		return fail("a report covers at most 366 days")
+	}
+	return from, to, true
+}
+
+// WithMeUsage serves GET /usage: the caller's usage, by API key and day.
+func WithMeUsage(d apiUsageDAO) MeOption {
+	return func(h *MeHandlers) {
+		h.usage = d
+	}
+}
+
+func (h *MeHandlers) myUsage(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := usageRange(w, r, time.Now())
+	if !ok {
+		return
+	}
+	id, _ := IdentityFromContext(r.Context())
+	rows, err := h.usage.ListAPIUsage(r.Context(), id.UserUID, from, to)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(summarizeAPIUsage(rows, from, to, false))
+}
+
+type APIUsageHandlers struct{ dao apiUsageDAO }
+
+// NewAPIUsageAdmin reports API usage across users and keys, so operators
+// can see which integrations are heavy. Callers with an API key are turned
+// away; they have GET /me/usage.
+func NewAPIUsageAdmin(dao apiUsageDAO) http.Handler {
+	h := &APIUsageHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger(), operatorsOnly)
+	r.Get("/", h.report)
+	return r
+}
+
+// report totals usage from ?from= to ?to=, for ?user_uid= or everyone.
+func (h *APIUsageHandlers) report(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := usageRange(w, r, time.Now())
+	if !ok {
+		return
+	}
+	rows, err := h.dao.ListAPIUsage(r.Context(), r.URL.Query().Get("user_uid"), from, to)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(summarizeAPIUsage(rows, from, to, true))
+}
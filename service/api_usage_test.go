@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageMeterFlush(t *testing.T) {
+	d := mocks.NewMockapiUsageDAO(t)
+	meter := NewUsageMeter(d, time.Minute)
+	now := time.Date(2025, 9, 25, 23, 59, 0, 0, time.UTC)
+	meter.now = func() time.Time { return now }
+	today := time.Date(2025, 9, 25, 0, 0, 0, 0, time.UTC)
+
+	keys := mocks.NewMockapiKeyDAO(t)
+	keys.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+	metered := meter.Keys(keys)
+	require.NoError(t, metered.TouchAPIKey(t.Context(), "key-1"))
+	require.NoError(t, metered.TouchAPIKey(t.Context(), "key-1"))
+	meter.countToolCall(identityContext("user-1", "house-1"))
+	// Operators have no key to count against.
+	meter.countToolCall(context.Background())
+
+	// A failed flush keeps its counts for the next.
+	d.On("AddAPIUsage", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+	assert.Error(t, meter.Flush(t.Context()))
+
+	now = now.Add(2 * time.Minute)
+	meter.countToolCall(identityContext("user-1", "house-1"))
+	var added []postgres.APIUsage
+	d.On("AddAPIUsage", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		added = args.Get(1).([]postgres.APIUsage)
+	}).Return(nil).Once()
+	require.NoError(t, meter.Flush(t.Context()))
+	assert.ElementsMatch(t, []postgres.APIUsage{
+		{APIKeyUID: "key-1", Day: today, Requests: 2, ToolCalls: 1},
+		{APIKeyUID: "key-1", Day: today.AddDate(0, 0, 1), ToolCalls: 1},
+	}, added)
+
+	// Nothing counted, nothing written.
+	require.NoError(t, meter.Flush(t.Context()))
+
+	var off *UsageMeter
+	off.countToolCall(identityContext("user-1", "house-1"))
+}
+
+func TestSummarizeAPIUsage(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	rows := []postgres.APIUsage{
+		{APIKeyUID: "key-1", KeyName: "Claude", UserUID: "user-1", UserName: "Ada", Day: day(1), Requests: 10, ToolCalls: 40},
+		{APIKeyUID: "key-2", KeyName: "Dashboard", UserUID: "user-1", UserName: "Ada", Day: day(1), Requests: 300},
+		{APIKeyUID: "key-1", KeyName: "Claude", UserUID: "user-1", UserName: "Ada", Day: day(2), Requests: 5, ToolCalls: 20},
+		{APIKeyUID: "key-3", KeyName: "Shortcuts", UserUID: "user-2", UserName: "Sam", Day: day(2), Requests: 2},
+	}
+	report := summarizeAPIUsage(rows, day(1), day(30), true)
+
+	assert.Equal(t, "2025-09-01", report.From)
+	assert.Equal(t, int64(317), report.Requests)
+	assert.Equal(t, int64(60), report.ToolCalls)
+	require.Len(t, report.Keys, 3)
+	assert.Equal(t, "key-2", report.Keys[0].APIKeyUID)
+	assert.Equal(t, APIUsageTotal{APIKeyUID: "key-1", KeyName: "Claude", UserUID: "user-1", UserName: "Ada", Requests: 15, ToolCalls: 60}, report.Keys[1])
+	require.Len(t, report.Users, 2)
+	assert.Equal(t, APIUsageTotal{UserUID: "user-1", UserName: "Ada", Requests: 315, ToolCalls: 60}, report.Users[0])
+	assert.Equal(t, []APIUsageDay{{Day: "2025-09-01", Requests: 310, ToolCalls: 40}, {Day: "2025-09-02", Requests: 7, ToolCalls: 20}}, report.Days)
+
+	assert.Nil(t, summarizeAPIUsage(rows, day(1), day(30), false).Users)
+}
+
+func TestMeUsage(t *testing.T) {
+	d := mocks.NewMockapiUsageDAO(t)
+	d.On("ListAPIUsage", mock.Anything, "user-1", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC)).
+		Return([]postgres.APIUsage{{APIKeyUID: "key-1", UserUID: "user-1", Day: time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC), Requests: 4, ToolCalls: 9}}, nil)
+	handler := NewMe(&MockUserDAO{}, &MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, WithMeUsage(d))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/usage?from=2025-09-01&to=2025-09-07", nil).WithContext(identityContext("user-1", "house-1")))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var report APIUsageReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	assert.Equal(t, int64(9), report.ToolCalls)
+	assert.Equal(t, "key-1", report.Keys[0].APIKeyUID)
+	assert.Empty(t, report.Users)
+
+	for target, want := range map[string]string{
+		"/usage?from=September":                "from must be a date",
+		"/usage?from=2025-09-08&to=2025-09-07": "from must not be after to",
+		"/usage?from=2024-01-01&to=2025-09-07": "at most 366 days",
+		"/usage?to=2025-13-01":                 "to must be a date",
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil).WithContext(identityContext("user-1", "house-1")))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), want, target)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/usage", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAPIUsageAdmin(t *testing.T) {
+	d := mocks.NewMockapiUsageDAO(t)
+	d.On("ListAPIUsage", mock.Anything, "", mock.Anything, mock.Anything).Return([]postgres.APIUsage{
+		{APIKeyUID: "key-1", UserUID: "user-1", Requests: 4},
+		{APIKeyUID: "key-2", UserUID: "user-2", Requests: 40},
+	}, nil)
+	handler := NewAPIUsageAdmin(d)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/", nil)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var report APIUsageReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	assert.Equal(t, int64(44), report.Requests)
+	require.Len(t, report.Users, 2)
+	assert.Equal(t, "user-2", report.Users[0].UserUID)
+	to, _ := time.Parse(time.DateOnly, report.To)
+	from, _ := time.Parse(time.DateOnly, report.From)
+	assert.Equal(t, defaultUsageDays-1, int(to.Sub(from).Hours()/24))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil).WithContext(identityContext("user-1", "house-1")))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
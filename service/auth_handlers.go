@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/pbdeuchler/assistant-server/calendar"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -21,18 +22,24 @@ type AuthConfig struct {
 	GCloudClientSecret string
 	GCloudProjectID    string
 	BaseURL            string
+	// OIDCProviders log users in, and link their accounts, at
+	// /oauth/oidc/{name}.
+	OIDCProviders []OIDCProvider
 }
 
 type authDAO interface {
 	CreateCredentials(ctx context.Context, c dao.Credentials) (dao.Credentials, error)
 	GetCredentialsByUserAndType(ctx context.Context, userUID, credentialType string) (dao.Credentials, error)
 	UpdateCredentials(ctx context.Context, id string, c dao.Credentials) (dao.Credentials, error)
+	ListCredentials(ctx context.Context, options dao.ListOptions) ([]dao.Credentials, error)
+	CreateAPIKey(ctx context.Context, k dao.APIKeys) (dao.APIKeys, error)
 }
 
 type AuthHandlers struct {
 	oauth2Config *oauth2.Config
 	jwtSecret    []byte
 	dao          authDAO
+	oidc         map[string]*oidcClient
 }
 
 type GoogleUserInfo struct {
@@ -78,12 +85,20 @@ func NewAuthHandlers(cfg AuthConfig, dao authDAO) http.Handler {
 	h := &AuthHandlers{
 		oauth2Config: oauth2Config,
 		dao:          dao,
+		oidc:         map[string]*oidcClient{},
+	}
+	for _, p := range cfg.OIDCProviders {
+		h.oidc[p.Name] = newOIDCClient(p, cfg.BaseURL)
 	}
 
 	r := chi.NewRouter()
 	r.Use(httpLogger())
 	r.Get("/google", h.googleAuth)
 	r.Get("/google/callback", h.googleCallback)
+	r.Post("/caldav", h.caldavConnect)
+	r.Get("/oidc/{provider}", h.oidcAuth)
+	r.Post("/oidc/{provider}/link", h.oidcLinkURL)
+	r.Get("/oidc/{provider}/callback", h.oidcCallback)
 	return r
 }
 
@@ -219,6 +234,59 @@ func (h *AuthHandlers) googleCallback(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+type caldavConnectRequest struct {
+	UserID string `json:"user_id"`
+	calendar.CalDAVAccount
+}
+
+// caldavConnect stores a CalDAV calendar (iCloud, Fastmail, ...) for a user
+// once a query against it succeeds.
+func (h *AuthHandlers) caldavConnect(w http.ResponseWriter, r *http.Request) {
+	var req caldavConnectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == "" || req.URL == "" || req.Username == "" || req.Password == "" {
+		http.Error(w, "user_id, url, username and password are required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	if _, err := calendar.NewCalDAV(req.CalDAVAccount, calendarHTTPClient).Events(ctx, now, now.Add(24*time.Hour)); err != nil {
+		http.Error(w, "Failed to read calendar: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accountJSON, err := json.Marshal(req.CalDAVAccount)
+	if err != nil {
+		http.Error(w, "Failed to marshal account: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	credential := dao.Credentials{
+		ID:             uuid.NewString(),
+		UserUID:        req.UserID,
+		CredentialType: calendar.CalDAVCredential,
+		Value:          accountJSON,
+	}
+	if existingCred, err := h.dao.GetCredentialsByUserAndType(ctx, req.UserID, calendar.CalDAVCredential); err == nil {
+		_, err = h.dao.UpdateCredentials(ctx, existingCred.ID, credential)
+		if err != nil {
+			http.Error(w, "Failed to update credential: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else if _, err := h.dao.CreateCredentials(ctx, credential); err != nil {
+		http.Error(w, "Failed to create credential: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("CalDAV credential saved", "user_id", req.UserID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
+
 func (h *AuthHandlers) getUserInfo(ctx context.Context, token *oauth2.Token) (*GoogleUserInfo, error) {
 	client := h.oauth2Config.Client(ctx, token)
 	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Roles are granted to API keys as "role:<name>" scopes. Keys without one
+// act as RoleMember, and requests without a key as RoleAnonymous.
+const (
+	ScopeRolePrefix = "role:"
+	RoleMember      = "member"
+	RoleAnonymous   = "anonymous"
+)
+
+type PolicyEffect string
+
+const (
+	PolicyAllow PolicyEffect = "allow"
+	PolicyDeny  PolicyEffect = "deny"
+)
+
+type PolicyMode string
+
+const (
+	// PolicyEnforce refuses denied requests.
+	PolicyEnforce PolicyMode = "enforce"
+	// PolicyDryRun only logs what would have been denied, so a policy can be
+	// checked against real traffic before it is enforced.
+	PolicyDryRun PolicyMode = "dry-run"
+)
+
+// PolicyRule matches callers holding any of Roles ("*" for everyone) and
+// requests for any of its Tools (MCP tool names) or Endpoints ("METHOD
+// /path"). Names and paths are path.Match globs; a path ending in "/**" also
+// matches everything below it.
+type PolicyRule struct {
+	Roles     []string     `json:"roles"`
+	Tools     []string     `json:"tools,omitempty"`
+	Endpoints []string     `json:"endpoints,omitempty"`
+	Effect    PolicyEffect `json:"effect"`
+}
+
+// Policy is a role × tool/endpoint matrix. Rules are checked in order and
+// the first match decides; Default applies when none match.
+type Policy struct {
+	Mode    PolicyMode   `json:"mode"`
+	Default PolicyEffect `json:"default"`
+	Rules   []PolicyRule `json:"rules"`
+}
+
+// ParsePolicy parses and validates a JSON policy. Mode defaults to enforce
+// and Default to allow.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if p.Mode == "" {
+		p.Mode = PolicyEnforce
+	}
+	if p.Default == "" {
+		p.Default = PolicyAllow
+	}
+	if p.Mode != PolicyEnforce && p.Mode != PolicyDryRun {
+		return nil, fmt.Errorf("invalid policy mode %q", p.Mode)
+	}
+	if !validEffect(p.Default) {
+		return nil, fmt.Errorf("invalid policy default %q", p.Default)
+	}
+	for i, rule := range p.Rules {
+		if !validEffect(rule.Effect) {
+			return nil, fmt.Errorf("rule %d: invalid effect %q", i, rule.Effect)
+		}
+		if len(rule.Roles) == 0 {
+			return nil, fmt.Errorf("rule %d: roles are required", i)
+		}
+		for _, pattern := range rule.Tools {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid tool pattern %q", i, pattern)
+			}
+		}
+		for _, endpoint := range rule.Endpoints {
+			method, pattern, ok := strings.Cut(endpoint, " ")
+			if !ok || method == "" || !strings.HasPrefix(pattern, "/") {
+				return nil, fmt.Errorf("rule %d: endpoint %q must look like \"GET /path\"", i, endpoint)
+			}
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid endpoint pattern %q", i, endpoint)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// LoadPolicy reads a policy file for ParsePolicy.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+func validEffect(e PolicyEffect) bool {
+	return e == PolicyAllow || e == PolicyDeny
+}
+
+// Roles returns the roles granted by the identity's scopes.
+func (i Identity) Roles() []string {
+	var roles []string
+	for _, scope := range i.Scopes {
+		if role, ok := strings.CutPrefix(scope, ScopeRolePrefix); ok {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		roles = []string{RoleMember}
+	}
+	return roles
+}
+
+func callerRoles(ctx context.Context) []string {
+	if id, ok := IdentityFromContext(ctx); ok {
+		return id.Roles()
+	}
+	return []string{RoleAnonymous}
+}
+
+func (r PolicyRule) appliesTo(roles []string) bool {
+	return slices.Contains(r.Roles, "*") || slices.ContainsFunc(r.Roles, func(role string) bool {
+		return slices.Contains(roles, role)
+	})
+}
+
+func (r PolicyRule) matchesTool(name string) bool {
+	return slices.ContainsFunc(r.Tools, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
+
+func (r PolicyRule) matchesEndpoint(method, urlPath string) bool {
+	return slices.ContainsFunc(r.Endpoints, func(endpoint string) bool {
+		m, pattern, _ := strings.Cut(endpoint, " ")
+		if m != "*" && !strings.EqualFold(m, method) {
+			return false
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if urlPath == prefix || strings.HasPrefix(urlPath, prefix+"/") {
+				return true
+			}
+			pattern = prefix
+		}
+		ok, _ := path.Match(pattern, urlPath)
+		return ok
+	})
+}
+
+// decide returns the effect for roles and the index of the deciding rule,
+// or -1 when the default applied.
+func (p *Policy) decide(roles []string, match func(PolicyRule) bool) (PolicyEffect, int) {
+	for i, rule := range p.Rules {
+		if rule.appliesTo(roles) && match(rule) {
+			return rule.Effect, i
+		}
+	}
+	return p.Default, -1
+}
+
+// check evaluates the policy for the caller in ctx and records denials. It
+// reports whether the request may proceed, which in dry-run mode is always.
+// A nil policy allows everything.
+func (p *Policy) check(ctx context.Context, kind, resource string, match func(PolicyRule) bool) bool {
+	if p == nil {
+		return true
+	}
+	roles := callerRoles(ctx)
+	effect, rule := p.decide(roles, match)
+	if effect == PolicyAllow {
+		return true
+	}
+
+	attrs := []any{
+		slog.String("kind", kind),
+		slog.String("resource", resource),
+		slog.Any("roles", roles),
+		slog.Int("rule", rule),
+		slog.String("mode", string(p.Mode)),
+	}
+	if id, ok := IdentityFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("user_uid", id.UserUID), slog.String("api_key_uid", id.APIKeyUID))
+	}
+	if p.Mode == PolicyDryRun {
+		slog.Info("Authorization policy would deny request", attrs...)
+		return true
+	}
+	slog.Warn("Authorization policy denied request", attrs...)
+	return false
+}
+
+// permitsTool reports whether the caller may use an MCP tool, without
+// recording anything. It is used to filter tools/list.
+func (p *Policy) permitsTool(ctx context.Context, name string) bool {
+	if p == nil || p.Mode == PolicyDryRun {
+		return true
+	}
+	effect, _ := p.decide(callerRoles(ctx), func(r PolicyRule) bool { return r.matchesTool(name) })
+	return effect == PolicyAllow
+}
+
+// AllowTool evaluates the policy for a call to the named MCP tool.
+func (p *Policy) AllowTool(ctx context.Context, name string) bool {
+	return p.check(ctx, "tool", name, func(r PolicyRule) bool { return r.matchesTool(name) })
+}
+
+// Middleware evaluates the policy for each request and answers 403 when it
+// is denied. It must run after APIKeyAuth so the caller's roles are known.
+func (p *Policy) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resource := r.Method + " " + r.URL.Path
+			if !p.check(r.Context(), "endpoint", resource, func(rule PolicyRule) bool {
+				return rule.matchesEndpoint(r.Method, r.URL.Path)
+			}) {
+				http.Error(w, "Forbidden by authorization policy", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithAuthorizationPolicy checks every MCP tool call against p, after the
+// API key's scopes, and hides denied tools from tools/list.
+func WithAuthorizationPolicy(p *Policy) MCPOption {
+	return func(h *MCPHandlers) {
+		h.policy = p
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testPolicy = `{
+	"rules": [
+		{"roles": ["child"], "tools": ["delete_*"], "effect": "deny"},
+		{"roles": ["child"], "endpoints": ["DELETE /notes/**", "* /api-keys/**"], "effect": "deny"},
+		{"roles": ["anonymous"], "endpoints": ["* /bootstrap"], "effect": "deny"}
+	]
+}`
+
+func roleRequest(roles ...string) *http.Request {
+	var scopes []string
+	for _, role := range roles {
+		scopes = append(scopes, ScopeRolePrefix+role)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	return req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1", HouseholdUID: "house-1", Scopes: append(scopes, ScopeAll)}))
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyEnforce, p.Mode)
+	assert.Equal(t, PolicyAllow, p.Default)
+
+	for _, bad := range []string{
+		`{"mode": "audit"}`,
+		`{"default": "maybe"}`,
+		`{"rules": [{"roles": ["child"], "tools": ["x"], "effect": "block"}]}`,
+		`{"rules": [{"tools": ["x"], "effect": "deny"}]}`,
+		`{"rules": [{"roles": ["child"], "endpoints": ["/notes"], "effect": "deny"}]}`,
+		`{"rules": [{"roles": ["child"], "tools": ["["], "effect": "deny"}]}`,
+	} {
+		_, err := ParsePolicy([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPolicyDecide(t *testing.T) {
+	p, _ := ParsePolicy([]byte(`{
+		"default": "deny",
+		"rules": [
+			{"roles": ["admin"], "tools": ["*"], "effect": "allow"},
+			{"roles": ["*"], "tools": ["list_*", "recall_note"], "effect": "allow"}
+		]
+	}`))
+	tool := func(name string) func(PolicyRule) bool {
+		return func(r PolicyRule) bool { return r.matchesTool(name) }
+	}
+
+	effect, rule := p.decide([]string{"admin"}, tool("delete_note"))
+	assert.Equal(t, PolicyAllow, effect)
+	assert.Equal(t, 0, rule)
+
+	effect, rule = p.decide([]string{RoleMember}, tool("list_todos"))
+	assert.Equal(t, PolicyAllow, effect)
+	assert.Equal(t, 1, rule)
+
+	effect, rule = p.decide([]string{RoleMember}, tool("delete_note"))
+	assert.Equal(t, PolicyDeny, effect)
+	assert.Equal(t, -1, rule)
+}
+
+func TestIdentityRoles(t *testing.T) {
+	assert.Equal(t, []string{RoleMember}, Identity{Scopes: []string{ScopeMCPWrite}}.Roles())
+	assert.Equal(t, []string{"child", "guest"}, Identity{Scopes: []string{"role:child", ScopeMCPRead, "role:guest"}}.Roles())
+	assert.Equal(t, []string{RoleAnonymous}, callerRoles(t.Context()))
+	assert.True(t, validScope("role:child"))
+	assert.False(t, validScope("role:"))
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	p, _ := ParsePolicy([]byte(testPolicy))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	serve := func(p *Policy, method, path string, req *http.Request) int {
+		req.Method, req.URL.Path = method, path
+		rr := httptest.NewRecorder()
+		p.Middleware()(ok).ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve(p, "DELETE", "/notes/note-1", roleRequest("child")))
+	assert.Equal(t, http.StatusForbidden, serve(p, "GET", "/api-keys", roleRequest("child")))
+	assert.Equal(t, http.StatusNoContent, serve(p, "GET", "/notes/note-1", roleRequest("child")))
+	assert.Equal(t, http.StatusNoContent, serve(p, "DELETE", "/notes/note-1", roleRequest("parent")))
+	assert.Equal(t, http.StatusForbidden, serve(p, "GET", "/bootstrap", httptest.NewRequest("GET", "/", nil)))
+
+	dryRun := *p
+	dryRun.Mode = PolicyDryRun
+	assert.Equal(t, http.StatusNoContent, serve(&dryRun, "DELETE", "/notes/note-1", roleRequest("child")))
+
+	var none *Policy
+	assert.Equal(t, http.StatusNoContent, serve(none, "GET", "/bootstrap", httptest.NewRequest("GET", "/", nil)))
+}
+
+func TestMCPHandlers_AuthorizationPolicy(t *testing.T) {
+	p, _ := ParsePolicy([]byte(testPolicy))
+	mockNotesDAO := &MockNotesDAO{}
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithAuthorizationPolicy(p))
+
+	child := roleRequest("child").Context()
+	var body map[string]any
+	decodeToolResult(t, h.callTool(child, "delete_note", map[string]any{"note_id": "note-1", "confirm": true}), &body)
+	assert.Equal(t, "Tool delete_note is not permitted by the authorization policy", body["error"])
+	mockNotesDAO.AssertNotCalled(t, "DeleteNotes", mock.Anything, mock.Anything)
+
+	result, err := h.listTools(child, "")
+	assert.NoError(t, err)
+	names := make([]string, len(result.Tools))
+	for i, tool := range result.Tools {
+		names[i] = tool.Name
+	}
+	assert.NotContains(t, names, "delete_note")
+	assert.NotContains(t, names, "delete_recipe")
+	assert.Contains(t, names, "save_note")
+
+	result, err = h.listTools(roleRequest("parent").Context(), "")
+	assert.NoError(t, err)
+	assert.Len(t, result.Tools, 32)
+}
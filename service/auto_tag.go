@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// Tagger suggests tags for a note or recipe saved without any.
+type Tagger interface {
+	SuggestTags(ctx context.Context, text string) ([]string, error)
+}
+
+// KeywordTagger suggests each tag whose keywords the text mentions. Keywords
+// are matched case-insensitively as whole words or phrases.
+type KeywordTagger map[string][]string
+
+// DefaultTagKeywords are the rules NewKeywordTagger starts from.
+var DefaultTagKeywords = KeywordTagger{
+	"groceries":  {"grocery", "groceries", "supermarket", "shopping list", "milk", "bread", "eggs"},
+	"health":     {"doctor", "dentist", "gp", "prescription", "vaccine", "allergy", "hospital"},
+	"school":     {"school", "teacher", "homework", "term", "nursery", "pta"},
+	"finance":    {"bank", "bill", "invoice", "tax", "mortgage", "insurance", "budget"},
+	"travel":     {"flight", "hotel", "passport", "holiday", "booking", "airport"},
+	"home":       {"plumber", "electrician", "boiler", "wifi", "bins", "repair"},
+	"car":        {"car", "mot", "tyres", "garage", "parking"},
+	"quick":      {"15 minutes", "20 minutes", "quick", "easy"},
+	"vegetarian": {"vegetarian", "vegan", "tofu", "lentils", "chickpeas"},
+}
+
+// NewKeywordTagger adds rules, as "keyword|keyword" per tag, to
+// DefaultTagKeywords.
+func NewKeywordTagger(rules map[string]string) KeywordTagger {
+	k := KeywordTagger{}
+	for tag, keywords := range DefaultTagKeywords {
+		k[tag] = slices.Clone(keywords)
+	}
+	for tag, keywords := range rules {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		for _, kw := range strings.Split(keywords, "|") {
+			if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+				k[tag] = append(k[tag], kw)
+			}
+		}
+	}
+	return k
+}
+
+func (k KeywordTagger) SuggestTags(_ context.Context, text string) ([]string, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	padded := " " + strings.Join(words, " ") + " "
+	var tags []string
+	for tag, keywords := range k {
+		for _, kw := range keywords {
+			if strings.Contains(padded, " "+kw+" ") {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// maxSuggestedTags caps how many suggestions are returned or applied.
+const maxSuggestedTags = 5
+
+// suggestTags returns tagger's tags for text when tags is empty, or nil when
+// there are tags already or no tagger. A failing tagger only costs the
+// suggestions.
+func suggestTags(ctx context.Context, tagger Tagger, tags []string, text string) []string {
+	if tagger == nil || len(tags) > 0 {
+		return nil
+	}
+	suggested, err := tagger.SuggestTags(ctx, text)
+	if err != nil {
+		slog.Warn("Failed to suggest tags", "error", err)
+		return nil
+	}
+	if len(suggested) > maxSuggestedTags {
+		suggested = suggested[:maxSuggestedTags]
+	}
+	return suggested
+}
+
+// autoTagRequested reports whether a create request asked for suggested
+// tags to be applied with ?auto_tag=true.
+func autoTagRequested(r *http.Request) bool {
+	return r.URL.Query().Get("auto_tag") == "true"
+}
+
+// WithNoteTagger suggests tags for notes created without any.
+func WithNoteTagger(t Tagger) NotesOption {
+	return func(h *NotesHandlers) { h.tagger = t }
+}
+
+// WithRecipeTagger suggests tags for recipes created without any.
+func WithRecipeTagger(t Tagger) RecipesOption {
+	return func(h *RecipesHandlers) { h.tagger = t }
+}
+
+// recipeTagText is what a recipe's tags are suggested from.
+func recipeTagText(r dao.Recipes) string {
+	text := r.Title + "\n" + r.Data
+	if r.Genre != nil {
+		text = *r.Genre + "\n" + text
+	}
+	return text
+}
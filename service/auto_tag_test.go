@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeTagger struct {
+	tags []string
+	err  error
+}
+
+func (f fakeTagger) SuggestTags(context.Context, string) ([]string, error) { return f.tags, f.err }
+
+func TestKeywordTagger(t *testing.T) {
+	k := NewKeywordTagger(map[string]string{"Kids": "leo| mia ", "health": "physio"})
+
+	tags, err := k.SuggestTags(context.Background(), "Physio for Mia; then pick up the SHOPPING list!")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"groceries", "health", "kids"}, tags)
+
+	// Keywords only match whole words.
+	tags, _ = k.SuggestTags(context.Background(), "Caravan carpet")
+	assert.Empty(t, tags)
+	assert.NotContains(t, DefaultTagKeywords["health"], "physio")
+}
+
+func TestSuggestTags(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, suggestTags(ctx, nil, nil, "dentist"))
+	assert.Nil(t, suggestTags(ctx, fakeTagger{tags: []string{"health"}}, []string{"mine"}, "dentist"))
+	assert.Nil(t, suggestTags(ctx, fakeTagger{err: errors.New("down")}, nil, "dentist"))
+	assert.Len(t, suggestTags(ctx, fakeTagger{tags: []string{"a", "b", "c", "d", "e", "f"}}, nil, "x"), maxSuggestedTags)
+}
+
+func TestNotesCreateSuggestsTags(t *testing.T) {
+	mockDAO := &MockNotesDAO{}
+	mockDAO.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool { return len(n.Tags) == 0 })).
+		Return(postgres.Notes{ID: "n1"}, nil).Once()
+	mockDAO.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool { return assert.ObjectsAreEqual([]string{"health"}, n.Tags) })).
+		Return(postgres.Notes{ID: "n2", Tags: []string{"health"}}, nil).Once()
+	handler := NewNotes(mockDAO, WithNoteTagger(NewKeywordTagger(nil)))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "dentist", "data": "Tuesday 3pm"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"suggested_tags":["health"]`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/?auto_tag=true", strings.NewReader(`{"key": "dentist", "data": "Tuesday 3pm"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"n2"`)
+	mockDAO.AssertExpectations(t)
+}
+
+func TestRecipesCreateSuggestsTags(t *testing.T) {
+	mockDAO := &MockRecipesDAO{}
+	mockDAO.On("CreateRecipes", mock.Anything, mock.MatchedBy(func(r postgres.Recipes) bool { return assert.ObjectsAreEqual([]string{"vegetarian"}, r.Tags) })).
+		Return(postgres.Recipes{ID: "r1", Tags: []string{"vegetarian"}}, nil)
+	handler := NewRecipes(mockDAO, WithRecipeTagger(NewKeywordTagger(nil)))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/?auto_tag=true", strings.NewReader(`{"title": "Lentil soup", "genre": "Vegetarian", "data": "Simmer"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"suggested_tags":["vegetarian"]`)
+	mockDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_SaveNoteAutoTag(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool { return assert.ObjectsAreEqual([]string{"car"}, n.Tags) })).
+		Return(postgres.Notes{ID: "n1", Tags: []string{"car"}}, nil)
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithTagger(NewKeywordTagger(nil)))
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "save_note", map[string]any{"key": "MOT due", "data": "Book at the garage", "auto_tag": true}), &body)
+	assert.Equal(t, []any{"car"}, body["suggested_tags"])
+	mockNotesDAO.AssertExpectations(t)
+}
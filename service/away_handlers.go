@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type awayDAO interface {
+	CreateAwayPeriod(ctx context.Context, a dao.AwayPeriod) (dao.AwayPeriod, error)
+	ListAwayPeriodsByUserUID(ctx context.Context, userUID string, from time.Time) ([]dao.AwayPeriod, error)
+	ListAwayPeriodsByHouseholdUID(ctx context.Context, householdUID string, from time.Time) ([]dao.AwayPeriod, error)
+	DeleteAwayPeriod(ctx context.Context, uid string) error
+}
+
+type AwayHandlers struct{ dao awayDAO }
+
+func NewAway(dao awayDAO) http.Handler {
+	h := &AwayHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Delete("/{uid}", h.delete)
+	return r
+}
+
+func (h *AwayHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		UserUID  string `json:"user_uid"`
+		StartsOn string `json:"starts_on"`
+		EndsOn   string `json:"ends_on"`
+		Note     string `json:"note"`
+	}
+	if json.NewDecoder(r.Body).Decode(&in) != nil || in.UserUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a, err := newAwayPeriod(in.UserUID, in.StartsOn, in.EndsOn, in.Note, time.Now())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateAwayPeriod(r.Context(), a)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// list returns the current and upcoming away periods of a user or of a
+// whole household.
+func (h *AwayHandlers) list(w http.ResponseWriter, r *http.Request) {
+	today := awayDay(time.Now())
+	var out []dao.AwayPeriod
+	var err error
+	switch q := r.URL.Query(); {
+	case q.Get("user_uid") != "":
+		out, err = h.dao.ListAwayPeriodsByUserUID(r.Context(), q.Get("user_uid"), today)
+	case q.Get("household_uid") != "":
+		out, err = h.dao.ListAwayPeriodsByHouseholdUID(r.Context(), q.Get("household_uid"), today)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *AwayHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteAwayPeriod(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newAwayPeriod checks an away period's dates, given as YYYY-MM-DD. The
+// start defaults to today, and the period can't end before it starts or in
+// the past.
+func newAwayPeriod(userUID, startsOn, endsOn, note string, now time.Time) (dao.AwayPeriod, error) {
+	today := awayDay(now)
+	start := today
+	if startsOn != "" {
+		var err error
+		if start, err = time.Parse(time.DateOnly, startsOn); err != nil {
+			return dao.AwayPeriod{}, errors.New("starts_on must be a date like 2025-08-30")
+		}
+	}
+	end, err := time.Parse(time.DateOnly, endsOn)
+	if err != nil {
+		return dao.AwayPeriod{}, errors.New("ends_on must be a date like 2025-08-30")
+	}
+	if end.Before(start) {
+		return dao.AwayPeriod{}, errors.New("ends_on is before starts_on")
+	}
+	if end.Before(today) {
+		return dao.AwayPeriod{}, errors.New("ends_on is in the past")
+	}
+	return dao.AwayPeriod{UserUID: userUID, StartsOn: start, EndsOn: end, Note: strings.TrimSpace(note)}, nil
+}
+
+// awayDay is the UTC date of t, which away periods are compared against.
+func awayDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// awayOn returns the periods that cover t's date.
+func awayOn(periods []dao.AwayPeriod, t time.Time) []dao.AwayPeriod {
+	day := awayDay(t)
+	out := []dao.AwayPeriod{}
+	for _, p := range periods {
+		if !day.Before(p.StartsOn) && !day.After(p.EndsOn) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAwayPeriod(t *testing.T) {
+	now := time.Date(2025, 8, 18, 21, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	a, err := newAwayPeriod("user-1", "", "2025-08-24", " Camping ", now)
+	require.NoError(t, err)
+	assert.Equal(t, postgres.AwayPeriod{UserUID: "user-1", StartsOn: today, EndsOn: today.AddDate(0, 0, 6), Note: "Camping"}, a)
+
+	for _, dates := range [][2]string{
+		{"", ""},
+		{"", "next week"},
+		{"2025-08-24", "2025-08-20"},
+		{"2025-08-01", "2025-08-17"},
+	} {
+		_, err := newAwayPeriod("user-1", dates[0], dates[1], "", now)
+		assert.Error(t, err, dates)
+	}
+}
+
+func TestAwayOn(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	periods := []postgres.AwayPeriod{{UID: "a1", StartsOn: day(18), EndsOn: day(20)}, {UID: "a2", StartsOn: day(25), EndsOn: day(25)}}
+
+	assert.Len(t, awayOn(periods, day(17).Add(23*time.Hour)), 0)
+	assert.Len(t, awayOn(periods, day(20).Add(23*time.Hour)), 1)
+	assert.Equal(t, "a2", awayOn(periods, day(25).Add(time.Hour))[0].UID)
+}
+
+func TestAwayHandlers(t *testing.T) {
+	mockDAO := mocks.NewMockawayDAO(t)
+	mockDAO.On("CreateAwayPeriod", mock.Anything, mock.MatchedBy(func(a postgres.AwayPeriod) bool {
+		return a.UserUID == "user-1" && a.EndsOn.Format(time.DateOnly) == "2099-01-02"
+	})).Return(postgres.AwayPeriod{UID: "a1", UserUID: "user-1"}, nil)
+	mockDAO.On("ListAwayPeriodsByHouseholdUID", mock.Anything, "house-1", awayDay(time.Now())).Return([]postgres.AwayPeriod{{UID: "a1"}}, nil)
+	mockDAO.On("DeleteAwayPeriod", mock.Anything, "a1").Return(nil)
+	handler := NewAway(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"user_uid": "user-1", "starts_on": "2099-01-01", "ends_on": "2099-01-02"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"a1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"user_uid": "user-1", "ends_on": "soon"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "ends_on must be a date")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?household_uid=house-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"a1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/a1", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestMCPHandlers_SetAway(t *testing.T) {
+	today := awayDay(time.Now())
+	mockDAO := mocks.NewMockawayDAO(t)
+	mockDAO.On("CreateAwayPeriod", mock.Anything, postgres.AwayPeriod{UserUID: "user-1", StartsOn: today, EndsOn: today.AddDate(0, 0, 3), Note: "Lisbon"}).
+		Return(postgres.AwayPeriod{UID: "a1", UserUID: "user-1", StartsOn: today, EndsOn: today.AddDate(0, 0, 3)}, nil)
+	mockDAO.On("ListAwayPeriodsByUserUID", mock.Anything, "user-1", today).Return([]postgres.AwayPeriod{{UID: "a1"}, {UID: "a2"}}, nil)
+	mockDAO.On("DeleteAwayPeriod", mock.Anything, "a1").Return(nil)
+	mockDAO.On("DeleteAwayPeriod", mock.Anything, "a2").Return(nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithAway(mockDAO))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "set_away", map[string]any{"ends_on": today.AddDate(0, 0, 3).Format(time.DateOnly), "note": "Lisbon"}), &body)
+	assert.Equal(t, "a1", body["away_period"].(map[string]any)["uid"])
+
+	decodeToolResult(t, h.callTool(ctx, "set_away", map[string]any{"back": true}), &body)
+	assert.Len(t, body["ended"], 2)
+
+	assert.True(t, h.callTool(ctx, "set_away", map[string]any{}).IsError)
+
+	withoutAway := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutAway.findTool("set_away")
+	assert.False(t, ok)
+}
+
+func TestMCPHandlers_GetBriefingAway(t *testing.T) {
+	today := awayDay(time.Now())
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "user-1").Return(postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}, nil)
+	mockHouseholdDAO := &MockHouseholdDAO{}
+	mockHouseholdDAO.On("GetHousehold", mock.Anything, "house-1").Return(postgres.Households{UID: "house-1"}, nil)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	mockAwayDAO := mocks.NewMockawayDAO(t)
+	mockAwayDAO.On("ListAwayPeriodsByHouseholdUID", mock.Anything, "house-1", today).Return([]postgres.AwayPeriod{
+		{UID: "a1", UserUID: "user-2", UserName: "Mia", StartsOn: today.AddDate(0, 0, -1), EndsOn: today.AddDate(0, 0, 2)},
+		{UID: "a2", UserUID: "user-3", UserName: "Leo", StartsOn: today.AddDate(0, 0, 5), EndsOn: today.AddDate(0, 0, 9)},
+	}, nil)
+
+	h := NewMCP(mockTodoDAO, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, mockHouseholdDAO, WithAway(mockAwayDAO))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_briefing", map[string]any{}), &body)
+	require.Len(t, body["away"], 1)
+	assert.Equal(t, "Mia", body["away"].([]any)[0].(map[string]any)["user_name"])
+}
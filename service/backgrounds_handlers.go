@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type backgroundDAO interface {
+	CreateBackground(ctx context.Context, b dao.Background) (dao.Background, error)
+	GetBackground(ctx context.Context, key string) (dao.Background, error)
+	ListBackgrounds(ctx context.Context, options dao.ListOptions) ([]dao.Background, error)
+	UpdateBackground(ctx context.Context, key string, b dao.Background) (dao.Background, error)
+	DeleteBackground(ctx context.Context, key string) error
+}
+
+type BackgroundHandlers struct{ dao backgroundDAO }
+
+// NewBackgrounds manages backgrounds. Scratchpad entries are backgrounds
+// too, but belong to their conversations: keys starting with scratchpad:
+// can't be created, read, changed or deleted here, and aren't listed.
+func NewBackgrounds(dao backgroundDAO) http.Handler {
+	h := &BackgroundHandlers{dao}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/{key}", h.get)
+	r.Put("/{key}", h.update)
+	r.Delete("/{key}", h.delete)
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *BackgroundHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var b dao.Background
+	if json.NewDecoder(r.Body).Decode(&b) != nil || b.Key == "" || isScratchpadKey(b.Key) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.CreateBackground(r.Context(), b)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *BackgroundHandlers) get(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if isScratchpadKey(key) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	where, args := visibleBackgrounds("WHERE key = $1", []any{key})
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Background, error) { return h.dao.GetBackground(ctx, key) },
+		h.dao.ListBackgrounds, where, args...)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *BackgroundHandlers) update(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if isScratchpadKey(key) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var b dao.Background
+	if json.NewDecoder(r.Body).Decode(&b) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.UpdateBackground(r.Context(), key, b)
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *BackgroundHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if isScratchpadKey(key) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if h.dao.DeleteBackground(r.Context(), key) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BackgroundHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, BackgroundsFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, BackgroundsFilters.Filters)
+	if !ok {
+		return
+	}
+	whereClause, whereArgs = visibleBackgrounds(whereClause, whereArgs)
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListBackgrounds(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func isScratchpadKey(key string) bool {
+	return strings.HasPrefix(key, scratchpadKeyPrefix)
+}
+
+// visibleBackgrounds narrows a list query built by BuildWhereClause to the
+// backgrounds REST callers see: unexpired, and not scratchpad entries.
+func visibleBackgrounds(whereClause string, whereArgs []any) (string, []any) {
+	whereArgs = append(whereArgs, scratchpadKeyPrefix)
+	cond := fmt.Sprintf("NOT starts_with(key, $%d) AND (expires_at IS NULL OR expires_at > NOW())", len(whereArgs))
+	if whereClause == "" {
+		return "WHERE " + cond, whereArgs
+	}
+	return whereClause + " AND " + cond, whereArgs
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBackgroundsCreate(t *testing.T) {
+	mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+	mockBackgroundDAO.On("CreateBackground", mock.Anything, postgres.Background{Key: "family", Value: "Two kids"}).
+		Return(postgres.Background{Key: "family", Value: "Two kids"}, nil)
+
+	handler := NewBackgrounds(mockBackgroundDAO)
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "family", "value": "Two kids"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var out postgres.Background
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, "Two kids", out.Value)
+}
+
+func TestBackgroundsCreateRequiresKey(t *testing.T) {
+	handler := NewBackgrounds(mocks.NewMockbackgroundDAO(t))
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"value": "Two kids"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBackgroundsGet(t *testing.T) {
+	mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+	mockBackgroundDAO.On("GetBackground", mock.Anything, "family").Return(postgres.Background{Key: "family", Value: "Two kids"}, nil)
+	mockBackgroundDAO.On("GetBackground", mock.Anything, "missing").Return(postgres.Background{}, errors.New("no rows"))
+
+	handler := NewBackgrounds(mockBackgroundDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/family", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBackgroundsUpdateAndDelete(t *testing.T) {
+	mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+	mockBackgroundDAO.On("UpdateBackground", mock.Anything, "family", postgres.Background{Value: "Three kids"}).
+		Return(postgres.Background{Key: "family", Value: "Three kids"}, nil)
+	mockBackgroundDAO.On("DeleteBackground", mock.Anything, "family").Return(nil)
+
+	handler := NewBackgrounds(mockBackgroundDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/family", strings.NewReader(`{"value": "Three kids"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/family", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestBackgroundsList(t *testing.T) {
+	mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+	mockBackgroundDAO.On("ListBackgrounds", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE key = $1 AND NOT starts_with(key, $2) AND (expires_at IS NULL OR expires_at > NOW())" &&
+			assert.ObjectsAreEqual([]any{"family", "scratchpad:"}, o.WhereArgs)
+	})).Return([]postgres.Background{{Key: "family", Value: "Two kids"}}, nil)
+
+	handler := NewBackgrounds(mockBackgroundDAO)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?key=family", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var out []postgres.Background
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Len(t, out, 1)
+}
+
+func TestBackgroundsHideScratchpads(t *testing.T) {
+	mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+	mockBackgroundDAO.On("ListBackgrounds", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE NOT starts_with(key, $1) AND (expires_at IS NULL OR expires_at > NOW())" &&
+			assert.ObjectsAreEqual([]any{"scratchpad:"}, o.WhereArgs)
+	})).Return([]postgres.Background{{Key: "family", Value: "Two kids"}}, nil)
+	mockBackgroundDAO.On("UpdateBackground", mock.Anything, "expired", postgres.Background{Value: "Three kids"}).
+		Return(postgres.Background{}, pgx.ErrNoRows)
+	handler := NewBackgrounds(mockBackgroundDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Another conversation's entries can't be reached by key, nor made
+	// permanent by an update.
+	entry := "/scratchpad:user-2:conv-1:plan"
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", entry, nil),
+		httptest.NewRequest("GET", entry+"?fields=value", nil),
+		httptest.NewRequest("PUT", entry, strings.NewReader(`{"value": "mine now"}`)),
+		httptest.NewRequest("DELETE", entry, nil),
+	} {
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code, req.Method+" "+req.URL.String())
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "scratchpad:user-2:conv-1:plan", "value": "mine now"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Nor can an expired background be brought back.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/expired", strings.NewReader(`{"value": "Three kids"}`)))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMCPHandlers_BackgroundTools(t *testing.T) {
+	t.Run("not registered without a DAO", func(t *testing.T) {
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+		_, ok := h.findTool("set_background")
+		assert.False(t, ok)
+		assert.True(t, h.callTool(t.Context(), "get_background", map[string]any{"key": "family"}).IsError)
+	})
+
+	t.Run("set creates then updates", func(t *testing.T) {
+		mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+		mockBackgroundDAO.On("GetBackground", mock.Anything, "family").Return(postgres.Background{}, errors.New("no rows")).Once()
+		mockBackgroundDAO.On("CreateBackground", mock.Anything, postgres.Background{Key: "family", Value: "Two kids"}).
+			Return(postgres.Background{Key: "family", Value: "Two kids"}, nil)
+		mockBackgroundDAO.On("GetBackground", mock.Anything, "family").Return(postgres.Background{Key: "family", Value: "Two kids"}, nil).Once()
+		mockBackgroundDAO.On("UpdateBackground", mock.Anything, "family", postgres.Background{Key: "family", Value: "Three kids"}).
+			Return(postgres.Background{Key: "family", Value: "Three kids"}, nil)
+
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+			WithBackgroundDAO(mockBackgroundDAO))
+
+		var body map[string]any
+		decodeToolResult(t, h.callTool(t.Context(), "set_background", map[string]any{"key": "family", "value": "Two kids"}), &body)
+		assert.Equal(t, "Background saved", body["summary"])
+
+		body = nil
+		decodeToolResult(t, h.callTool(t.Context(), "set_background", map[string]any{"key": "family", "value": "Three kids"}), &body)
+		assert.Equal(t, "Background updated", body["summary"])
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mockBackgroundDAO := mocks.NewMockbackgroundDAO(t)
+		mockBackgroundDAO.On("GetBackground", mock.Anything, "family").Return(postgres.Background{Key: "family", Value: "Two kids"}, nil)
+
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+			WithBackgroundDAO(mockBackgroundDAO))
+		tool, ok := h.findTool("get_background")
+		if assert.True(t, ok) {
+			assert.True(t, *tool.Annotations.ReadOnlyHint)
+		}
+
+		var body map[string]any
+		decodeToolResult(t, h.callTool(t.Context(), "get_background", map[string]any{"key": "family"}), &body)
+		assert.Equal(t, "Two kids", body["background"].(map[string]any)["value"])
+
+		body = nil
+		decodeToolResult(t, h.callTool(t.Context(), "get_background", map[string]any{}), &body)
+		assert.Equal(t, "key is required", body["error"])
+	})
+}
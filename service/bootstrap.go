@@ -7,7 +7,6 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -24,13 +23,20 @@ type bootstrapDAO interface {
 	GetPreferencesByUserUID(ctx context.Context, userUID string) ([]dao.Preferences, error)
 	GetRecipesByUserUID(ctx context.Context, userUID string) ([]dao.Recipes, error)
 	GetHousehold(ctx context.Context, uid string) (dao.Households, error)
+	GetToolPoliciesForUser(ctx context.Context, userUID string, householdUID *string) ([]dao.ToolPolicy, error)
 	UpdateCredentials(ctx context.Context, id string, c dao.Credentials) (dao.Credentials, error)
 }
 
-type bootstrapHandlers struct{ dao bootstrapDAO }
+type bootstrapHandlers struct {
+	dao    bootstrapDAO
+	prompt PromptCompiler
+}
 
-func NewBootstrap(dao bootstrapDAO) http.Handler {
-	h := &bootstrapHandlers{dao}
+func NewBootstrap(dao bootstrapDAO, opts ...BootstrapOption) http.Handler {
+	h := &bootstrapHandlers{dao: dao, prompt: MarkdownPrompt{Budget: defaultPromptBudget}}
+	for _, opt := range opts {
+		opt(h)
+	}
 	r := chi.NewRouter()
 	r.Use(httpLogger())
 	r.Get("/", h.bootstrap)
@@ -137,8 +143,23 @@ func (h *bootstrapHandlers) bootstrap(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Resolve which assistant tools this user may invoke
+	policies, err := h.dao.GetToolPoliciesForUser(ctx, user.UID, user.HouseholdUID)
+	if err != nil {
+		slog.Error("Failed to get tool policies", "user_id", user.UID, "error", err)
+		policies = []dao.ToolPolicy{}
+	}
+	allowedTools, disallowedTools := resolveToolPolicy(policies, user.UID)
+
 	// Compile structured prompt for LLM
-	prompt := h.compileLLMPrompt(user, household, todos, notes, preferences)
+	prompt := h.prompt.CompilePrompt(PromptInput{
+		User:        user,
+		Household:   household,
+		Todos:       todos,
+		Notes:       notes,
+		Preferences: preferences,
+		Now:         time.Now(),
+	})
 
 	response := BootstrapResponse{
 		User:               user,
@@ -146,8 +167,8 @@ func (h *bootstrapHandlers) bootstrap(w http.ResponseWriter, r *http.Request) {
 		Notes:              notes,
 		Preferences:        preferences,
 		AppendSystemPrompt: prompt,
-		AllowedTools:       []string{"mcp__assistant-mcp"},
-		DisallowedTools:    []string{"TodoWrite"},
+		AllowedTools:       allowedTools,
+		DisallowedTools:    disallowedTools,
 		Env:                env,
 	}
 
@@ -211,56 +232,3 @@ func (h *bootstrapHandlers) validateAndRefreshCredential(ctx context.Context, cr
 	env["GOOGLE_API_ACCESS_TOKEN"] = token.AccessToken
 	return env, nil
 }
-
-func (h *bootstrapHandlers) compileLLMPrompt(user dao.Users, household *dao.Households, todos []dao.Todo, notes []dao.Notes, preferences []dao.Preferences) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("# User Context\n\n")
-	prompt.WriteString(fmt.Sprintf("**User:** \n %s | %s | user_uid=%s\n", user.Name, user.Email, user.UID))
-	if user.Description != "" {
-		prompt.WriteString(fmt.Sprintf("**Description:** %s\n", user.Description))
-	}
-	prompt.WriteString("\n")
-
-	if household != nil {
-		prompt.WriteString("# Household Context\n\n")
-		prompt.WriteString(fmt.Sprintf("**Household:** %s (uid=%s)\n", household.Name, household.UID))
-		if household.Description != "" {
-			prompt.WriteString(fmt.Sprintf("**Description:** %s\n", household.Description))
-		}
-		prompt.WriteString("\n")
-	}
-
-	if len(todos) > 0 {
-		prompt.WriteString("# Todos\n\n")
-		for _, todo := range todos {
-			prompt.WriteString(fmt.Sprintf("- **%s**", todo.Title))
-			if todo.Description != "" {
-				prompt.WriteString(fmt.Sprintf(" - %s", todo.Description))
-			}
-			if todo.DueDate != nil {
-				prompt.WriteString(fmt.Sprintf(" (Due: %s)", todo.DueDate.Format("2006-01-02")))
-			}
-			prompt.WriteString("\n")
-		}
-		prompt.WriteString("\n")
-	}
-
-	if len(notes) > 0 {
-		prompt.WriteString("# Notes\n\n")
-		for _, note := range notes {
-			prompt.WriteString(fmt.Sprintf("- **%s**: %s\n", note.Key, note.Data))
-		}
-		prompt.WriteString("\n")
-	}
-
-	if len(preferences) > 0 {
-		prompt.WriteString("# Preferences\n\n")
-		for _, pref := range preferences {
-			prompt.WriteString(fmt.Sprintf("- **%s** (%s): %s\n", pref.Key, pref.Specifier, pref.Data))
-		}
-		prompt.WriteString("\n")
-	}
-
-	return prompt.String()
-}
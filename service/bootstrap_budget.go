@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// defaultPromptBudget is the bootstrap prompt budget, in estimated tokens,
+// used when none is configured.
+const defaultPromptBudget = 8000
+
+// BootstrapOption configures the bootstrap handler.
+type BootstrapOption func(*bootstrapHandlers)
+
+// WithPromptBudget caps the compiled bootstrap prompt at roughly tokens
+// tokens. Zero or less disables the cap. It replaces a compiler set by an
+// earlier WithPromptCompiler with a MarkdownPrompt.
+func WithPromptBudget(tokens int) BootstrapOption {
+	return func(h *bootstrapHandlers) { h.prompt = MarkdownPrompt{Budget: tokens} }
+}
+
+// WithPromptCompiler compiles bootstrap prompts with c instead of a
+// MarkdownPrompt.
+func WithPromptCompiler(c PromptCompiler) BootstrapOption {
+	return func(h *bootstrapHandlers) { h.prompt = c }
+}
+
+// estimateTokens approximates the token count of s at four characters per
+// token, which is close enough for English prose and JSON-ish notes.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// promptBuilder writes prompt sections while keeping track of the remaining
+// token budget. A budget of zero or less means unlimited.
+type promptBuilder struct {
+	strings.Builder
+	remaining int
+	limited   bool
+}
+
+func newPromptBuilder(budget int) *promptBuilder {
+	return &promptBuilder{remaining: budget, limited: budget > 0}
+}
+
+// fits reports whether s fits in the remaining budget and, if so, charges
+// it against the budget.
+func (p *promptBuilder) fits(s string) bool {
+	if !p.limited {
+		return true
+	}
+	cost := estimateTokens(s)
+	if cost > p.remaining {
+		return false
+	}
+	p.remaining -= cost
+	return true
+}
+
+// always writes s regardless of the budget, still charging it, for context
+// the assistant cannot work without.
+func (p *promptBuilder) always(s string) {
+	p.remaining -= estimateTokens(s)
+	p.WriteString(s)
+}
+
+// section writes a heading followed by as many lines as fit, in order. The
+// first line that does not fit ends the section so lower priority items are
+// never shown ahead of higher priority ones, and a trailing line records how
+// many were left out.
+func (p *promptBuilder) section(heading, noun string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	header := "# " + heading + "\n\n"
+	if !p.fits(header) {
+		return
+	}
+	p.WriteString(header)
+	shown := 0
+	for _, line := range lines {
+		if !p.fits(line) {
+			break
+		}
+		p.WriteString(line)
+		shown++
+	}
+	if omitted := len(lines) - shown; omitted > 0 {
+		p.WriteString(fmt.Sprintf("- _%d more %s omitted_\n", omitted, noun))
+	}
+	p.WriteString("\n")
+}
+
+// prioritizeTodos returns todos with overdue ones first, oldest due date
+// first, followed by the rest in their original order.
+func prioritizeTodos(todos []dao.Todo, now time.Time) []dao.Todo {
+	out := make([]dao.Todo, len(todos))
+	copy(out, todos)
+	overdue := func(t dao.Todo) bool { return t.DueDate != nil && t.DueDate.Before(now) }
+	sort.SliceStable(out, func(i, j int) bool {
+		oi, oj := overdue(out[i]), overdue(out[j])
+		if oi && oj {
+			return out[i].DueDate.Before(*out[j].DueDate)
+		}
+		return oi && !oj
+	})
+	return out
+}
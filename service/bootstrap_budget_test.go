@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrioritizeTodos(t *testing.T) {
+	now := time.Date(2025, 8, 19, 12, 0, 0, 0, time.UTC)
+	day := func(offset int) *time.Time {
+		d := now.AddDate(0, 0, offset)
+		return &d
+	}
+	todos := []postgres.Todo{
+		{UID: "upcoming", DueDate: day(3)},
+		{UID: "undated"},
+		{UID: "overdue-recent", DueDate: day(-1)},
+		{UID: "overdue-old", DueDate: day(-10)},
+	}
+
+	var ids []string
+	for _, todo := range prioritizeTodos(todos, now) {
+		ids = append(ids, todo.UID)
+	}
+	assert.Equal(t, []string{"overdue-old", "overdue-recent", "upcoming", "undated"}, ids)
+	assert.Equal(t, "upcoming", todos[0].UID, "input must not be reordered")
+}
+
+func TestMarkdownPromptUnlimited(t *testing.T) {
+	var notes []postgres.Notes
+	for i := range 50 {
+		notes = append(notes, postgres.Notes{ID: fmt.Sprint(i), Key: fmt.Sprintf("note-%d", i), Data: strings.Repeat("x", 200)})
+	}
+
+	prompt := MarkdownPrompt{}.CompilePrompt(PromptInput{User: postgres.Users{UID: "user-1", Name: "Sam"}, Notes: notes, Now: time.Now()})
+	assert.Equal(t, 50, strings.Count(prompt, "- **note-"))
+	assert.NotContains(t, prompt, "omitted")
+}
+
+func TestMarkdownPromptBudget(t *testing.T) {
+	overdue := time.Now().AddDate(0, 0, -2)
+	var todos []postgres.Todo
+	for i := range 20 {
+		todos = append(todos, postgres.Todo{Title: fmt.Sprintf("todo-%d", i), Description: strings.Repeat("y", 100)})
+	}
+	todos = append(todos, postgres.Todo{Title: "pay rent", DueDate: &overdue})
+	var notes []postgres.Notes
+	for i := range 20 {
+		notes = append(notes, postgres.Notes{ID: fmt.Sprint(i), Key: fmt.Sprintf("note-%d", i), Data: strings.Repeat("x", 100)})
+	}
+	notes = append(notes, postgres.Notes{ID: "wifi", Key: "wifi", Data: "hunter2", Pinned: true})
+	preferences := []postgres.Preferences{{Key: "units", Specifier: "global", Data: "metric"}}
+	user := postgres.Users{UID: "user-1", Name: "Sam", Description: "Likes lists"}
+
+	prompt := MarkdownPrompt{Budget: 300}.CompilePrompt(PromptInput{User: user, Todos: todos, Notes: notes, Preferences: preferences, Now: time.Now()})
+
+	assert.Contains(t, prompt, "Likes lists")
+	assert.Contains(t, prompt, "**wifi**: hunter2")
+	assert.Contains(t, prompt, "**pay rent** (Overdue: ")
+	assert.Less(t, strings.Index(prompt, "**pay rent**"), strings.Index(prompt, "**todo-0**"))
+	assert.Regexp(t, `- _\d+ more todos omitted_`, prompt)
+	assert.LessOrEqual(t, estimateTokens(prompt), 300+20, "only omission markers may exceed the budget")
+}
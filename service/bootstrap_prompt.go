@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// PromptCompiler writes the system prompt that bootstrap hands the
+// assistant, from what is known about the user.
+type PromptCompiler interface {
+	CompilePrompt(in PromptInput) string
+}
+
+// PromptInput is what a bootstrap prompt is compiled from. Household is
+// nil for users without one, and Now is what todos are overdue against.
+type PromptInput struct {
+	User        dao.Users
+	Household   *dao.Households
+	Todos       []dao.Todo
+	Notes       []dao.Notes
+	Preferences []dao.Preferences
+	Now         time.Time
+}
+
+// MarkdownPrompt is the default PromptCompiler. It writes the user and
+// their household in full, then as many pinned notes, todos, other notes
+// and preferences as fit in Budget estimated tokens, or all of them when
+// Budget is zero or less. The golden files in testdata/prompts show what
+// it writes; changes to them change what every assistant is told.
+type MarkdownPrompt struct {
+	Budget int
+}
+
+func (m MarkdownPrompt) CompilePrompt(in PromptInput) string {
+	prompt := newPromptBuilder(m.Budget)
+	user, household := in.User, in.Household
+
+	var about strings.Builder
+	about.WriteString("# User Context\n\n")
+	about.WriteString(fmt.Sprintf("**User:** \n %s | %s | user_uid=%s\n", user.Name, user.Email, user.UID))
+	if user.Description != "" {
+		about.WriteString(fmt.Sprintf("**Description:** %s\n", user.Description))
+	}
+	about.WriteString("\n")
+
+	if household != nil {
+		about.WriteString("# Household Context\n\n")
+		about.WriteString(fmt.Sprintf("**Household:** %s (uid=%s)\n", household.Name, household.UID))
+		if household.Description != "" {
+			about.WriteString(fmt.Sprintf("**Description:** %s\n", household.Description))
+		}
+		about.WriteString("\n")
+	}
+	prompt.always(about.String())
+
+	// Sections are written in priority order and each takes what is left of
+	// the budget: pinned notes, then todos (overdue first), then the other
+	// notes, then preferences.
+	pinned := budgetPinnedNotes(in.Notes, defaultPinnedNotesBudget)
+	shown := make(map[string]bool, len(pinned))
+	var lines []string
+	for _, note := range pinned {
+		shown[note.ID] = true
+		lines = append(lines, fmt.Sprintf("- **%s**: %s\n", note.Key, note.Data))
+	}
+	prompt.section("Pinned Notes", "pinned notes", lines)
+
+	now := in.Now
+	lines = nil
+	for _, todo := range prioritizeTodos(in.Todos, now) {
+		line := fmt.Sprintf("- **%s**", todo.Title)
+		if todo.Description != "" {
+			line += fmt.Sprintf(" - %s", todo.Description)
+		}
+		if todo.DueDate != nil {
+			if todo.DueDate.Before(now) {
+				line += fmt.Sprintf(" (Overdue: %s)", todo.DueDate.Format("2006-01-02"))
+			} else {
+				line += fmt.Sprintf(" (Due: %s)", todo.DueDate.Format("2006-01-02"))
+			}
+		}
+		lines = append(lines, line+"\n")
+	}
+	prompt.section("Todos", "todos", lines)
+
+	lines = nil
+	for _, note := range in.Notes {
+		if shown[note.ID] {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- **%s**: %s\n", note.Key, note.Data))
+	}
+	prompt.section("Notes", "notes", lines)
+
+	lines = nil
+	for _, pref := range in.Preferences {
+		lines = append(lines, fmt.Sprintf("- **%s** (%s): %s\n", pref.Key, pref.Specifier, pref.Data))
+	}
+	prompt.section("Preferences", "preferences", lines)
+
+	return prompt.String()
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata with what the code writes now")
+
+// TestMarkdownPromptGolden compares compiled prompts with the files in
+// testdata/prompts. After changing the prompt on purpose, run
+//
+//	go test ./service -run TestMarkdownPromptGolden -update
+//
+// and review the diff of the golden files along with the code.
+func TestMarkdownPromptGolden(t *testing.T) {
+	now := time.Date(2025, 8, 19, 12, 0, 0, 0, time.UTC)
+	day := func(offset int) *time.Time {
+		d := now.AddDate(0, 0, offset)
+		return &d
+	}
+	alex := postgres.Users{UID: "user-1", Name: "Alex Rivera", Email: "alex@example.com", Description: "Works from home; does most of the cooking"}
+	riveras := &postgres.Households{UID: "house-1", Name: "The Riveras", Description: "Two adults, two kids and a cat"}
+
+	var manyTodos []postgres.Todo
+	for i := range 60 {
+		todo := postgres.Todo{Title: fmt.Sprintf("Chore %02d", i)}
+		switch i % 3 {
+		case 0:
+			todo.DueDate = day(i/3 - 5)
+		case 1:
+			todo.Description = "Whenever there's time"
+		}
+		manyTodos = append(manyTodos, todo)
+	}
+
+	paragraph := "Mia swims on Tuesdays and Thursdays at four, Leo has football on Saturday mornings, and both need packed lunches on school trips. "
+	var longNotes []postgres.Notes
+	for i := range 12 {
+		longNotes = append(longNotes, postgres.Notes{ID: fmt.Sprint(i), Key: fmt.Sprintf("kids-term-%d", i+1), Data: strings.TrimSpace(strings.Repeat(paragraph, 4))})
+	}
+	longNotes = append(longNotes,
+		postgres.Notes{ID: "wifi", Key: "wifi", Data: "Network riveras-5g, password hunter2", Pinned: true},
+		postgres.Notes{ID: "manual", Key: "boiler-manual", Data: strings.TrimSpace(strings.Repeat(paragraph, 20)), Pinned: true},
+	)
+
+	for name, c := range map[string]struct {
+		in     PromptInput
+		budget int
+	}{
+		"no_household": {in: PromptInput{User: postgres.Users{UID: "user-2", Name: "Sam", Email: "sam@example.com"}}},
+		"household": {in: PromptInput{
+			User:      alex,
+			Household: riveras,
+			Todos: []postgres.Todo{
+				{Title: "Renew car registration", DueDate: day(3)},
+				{Title: "Fix the dripping kitchen tap", Description: "Washer is under the sink"},
+				{Title: "Pay the water bill", DueDate: day(-2)},
+			},
+			Notes: []postgres.Notes{
+				{ID: "1", Key: "gift-ideas", Data: "Mia: art supplies. Leo: the next book in his series."},
+				{ID: "2", Key: "wifi", Data: "hunter2", Pinned: true},
+			},
+			Preferences: []postgres.Preferences{
+				{Key: "units", Specifier: "user-1", Data: `"metric"`},
+				{Key: "diet", Specifier: "house-1", Data: `{"style": "vegetarian on weekdays"}`},
+			},
+		}},
+		"many_todos":  {in: PromptInput{User: alex, Household: riveras, Todos: manyTodos}, budget: 400},
+		"long_notes":  {in: PromptInput{User: alex, Household: riveras, Notes: longNotes}, budget: 1500},
+		"empty_lists": {in: PromptInput{User: alex, Todos: []postgres.Todo{}, Notes: []postgres.Notes{}, Preferences: []postgres.Preferences{}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c.in.Now = now
+			got := MarkdownPrompt{Budget: c.budget}.CompilePrompt(c.in)
+			path := filepath.Join("testdata", "prompts", name+".md")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "run with -update to create it")
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
+
+type cannedPrompt string
+
+func (p cannedPrompt) CompilePrompt(PromptInput) string { return string(p) }
+
+func TestBootstrapUsesPromptCompiler(t *testing.T) {
+	user := postgres.Users{UID: "user-1", Name: "Sam"}
+	mockBootstrapDAO := mocks.NewMockbootstrapDAO(t)
+	mockBootstrapDAO.On("GetUserBySlackUserUID", mock.Anything, "U123").Return(user, nil)
+	mockBootstrapDAO.On("GetCredentialsByUserUID", mock.Anything, "user-1").Return([]postgres.Credentials{}, nil)
+	mockBootstrapDAO.On("GetTodosByUserUID", mock.Anything, "user-1").Return([]postgres.Todo{}, nil)
+	mockBootstrapDAO.On("GetNotesByUserUID", mock.Anything, "user-1").Return([]postgres.Notes{}, nil)
+	mockBootstrapDAO.On("GetPreferencesByUserUID", mock.Anything, "user-1").Return([]postgres.Preferences{}, nil)
+	mockBootstrapDAO.On("GetToolPoliciesForUser", mock.Anything, "user-1", (*string)(nil)).Return([]postgres.ToolPolicy{}, nil)
+
+	rr := httptest.NewRecorder()
+	NewBootstrap(mockBootstrapDAO, WithPromptCompiler(cannedPrompt("Be brief."))).ServeHTTP(rr, httptest.NewRequest("GET", "/?slack_id=U123", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var out BootstrapResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, "Be brief.", out.AppendSystemPrompt)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/calendar"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"golang.org/x/oauth2"
+)
+
+// calendarHTTPClient talks to CalDAV servers.
+var calendarHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+var errNoCalendar = errors.New("no calendar connected; connect Google at /oauth/google or a CalDAV calendar at /oauth/caldav")
+
+type calendarCredentialDAO interface {
+	GetCredentialsByUserUID(ctx context.Context, userUID string) ([]dao.Credentials, error)
+}
+
+// WithCalendars enables the calendar tools. Each user's calendar is the
+// Google or CalDAV calendar they connected; google refreshes expired Google
+// tokens.
+func WithCalendars(credentials calendarCredentialDAO, google *oauth2.Config) MCPOption {
+	return func(h *MCPHandlers) {
+		h.calendarCreds = credentials
+		h.googleOAuth = google
+	}
+}
+
+// calendarFor opens the calendar userUID connected, preferring Google when
+// they connected both.
+func (h *MCPHandlers) calendarFor(ctx context.Context, userUID string) (calendar.Calendar, error) {
+	creds, err := h.calendarCreds.GetCredentialsByUserUID(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, credentialType := range []string{calendar.GoogleCredential, calendar.CalDAVCredential} {
+		for _, cred := range creds {
+			if cred.CredentialType == credentialType {
+				return h.openCalendar(ctx, cred)
+			}
+		}
+	}
+	return nil, errNoCalendar
+}
+
+func (h *MCPHandlers) openCalendar(ctx context.Context, cred dao.Credentials) (calendar.Calendar, error) {
+	switch cred.CredentialType {
+	case calendar.GoogleCredential:
+		var token oauth2.Token
+		if err := json.Unmarshal(cred.Value, &token); err != nil {
+			return nil, fmt.Errorf("google credential: %w", err)
+		}
+		if h.googleOAuth == nil {
+			return calendar.NewGoogle(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&token))), nil
+		}
+		return calendar.NewGoogle(h.googleOAuth.Client(ctx, &token)), nil
+	case calendar.CalDAVCredential:
+		var account calendar.CalDAVAccount
+		if err := json.Unmarshal(cred.Value, &account); err != nil {
+			return nil, fmt.Errorf("caldav credential: %w", err)
+		}
+		return calendar.NewCalDAV(account, calendarHTTPClient), nil
+	}
+	return nil, errNoCalendar
+}
+
+// parseEventTime reads a tool's start or end: a date (YYYY-MM-DD) for an
+// all-day event, or an RFC 3339 date-time. Date-times without an offset
+// are taken as UTC.
+func parseEventTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("2006-01-02T15:04", s); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a date like 2025-08-30 or a time like 2025-08-30T15:00:00Z", s)
+}
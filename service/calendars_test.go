@@ -0,0 +1,123 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pbdeuchler/assistant-server/calendar"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// caldavServer is a one-event CalDAV calendar at /cal/ that records PUTs.
+func caldavServer(t *testing.T, puts *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, _ := r.BasicAuth(); pass != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch r.Method {
+		case "REPORT":
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = io.WriteString(w, `<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:response><d:propstat><d:prop><c:calendar-data>`+
+				"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:swim\r\nDTSTART:20250823T100000Z\r\nDTEND:20250823T110000Z\r\nSUMMARY:Swimming\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"+
+				`</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`)
+		case http.MethodPut:
+			*puts = append(*puts, string(body))
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestParseEventTime(t *testing.T) {
+	d, allDay, err := parseEventTime("2025-08-30")
+	require.NoError(t, err)
+	assert.True(t, allDay)
+	assert.Equal(t, time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC), d)
+
+	dt, allDay, err := parseEventTime("2025-08-30T15:00:00+01:00")
+	require.NoError(t, err)
+	assert.False(t, allDay)
+	assert.True(t, time.Date(2025, 8, 30, 14, 0, 0, 0, time.UTC).Equal(dt))
+
+	_, _, err = parseEventTime("saturday")
+	assert.Error(t, err)
+}
+
+func TestMCPHandlers_CalendarToolsCalDAV(t *testing.T) {
+	var puts []string
+	srv := caldavServer(t, &puts)
+	account, _ := json.Marshal(calendar.CalDAVAccount{URL: srv.URL + "/cal", Username: "me", Password: "app-password"})
+
+	creds := mocks.NewMockcalendarCredentialDAO(t)
+	creds.On("GetCredentialsByUserUID", mock.Anything, "user-1").
+		Return([]postgres.Credentials{{CredentialType: calendar.CalDAVCredential, Value: account}}, nil)
+	creds.On("GetCredentialsByUserUID", mock.Anything, "user-2").Return([]postgres.Credentials{}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithCalendars(creds, nil))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "list_calendar_events", map[string]any{"from": "2025-08-18", "days": float64(14)}), &body)
+	assert.Equal(t, "Found 1 events from 2025-08-18 to 2025-08-31", body["summary"])
+
+	decodeToolResult(t, h.callTool(ctx, "create_calendar_event", map[string]any{
+		"title": "Camping", "start": "2025-08-30", "end": "2025-08-31",
+	}), &body)
+	assert.Equal(t, "Added Camping on 2025-08-30", body["summary"])
+	require.Len(t, puts, 1)
+	assert.Contains(t, puts[0], "DTSTART;VALUE=DATE:20250830\r\n")
+	assert.Contains(t, puts[0], "DTEND;VALUE=DATE:20250901\r\n")
+
+	assert.True(t, h.callTool(ctx, "create_calendar_event", map[string]any{"title": "Dinner", "start": "tonight"}).IsError)
+	assert.True(t, h.callTool(ctx, "create_calendar_event", map[string]any{
+		"title": "Dinner", "start": "2025-08-30T19:00:00Z", "end": "2025-08-30",
+	}).IsError)
+
+	result := h.callTool(identityContext("user-2", "house-1"), "list_calendar_events", map[string]any{})
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no calendar connected")
+}
+
+func TestCalDAVConnect(t *testing.T) {
+	var puts []string
+	srv := caldavServer(t, &puts)
+
+	authDAO := mocks.NewMockauthDAO(t)
+	authDAO.On("GetCredentialsByUserAndType", mock.Anything, "user-1", calendar.CalDAVCredential).
+		Return(postgres.Credentials{}, assert.AnError)
+	authDAO.On("CreateCredentials", mock.Anything, mock.MatchedBy(func(c postgres.Credentials) bool {
+		return c.UserUID == "user-1" && c.CredentialType == calendar.CalDAVCredential &&
+			strings.Contains(string(c.Value), `"password":"app-password"`)
+	})).Return(postgres.Credentials{}, nil)
+	h := NewAuthHandlers(AuthConfig{}, authDAO)
+
+	connect := func(password string) *httptest.ResponseRecorder {
+		body := `{"user_id": "user-1", "url": "` + srv.URL + `/cal/", "username": "me", "password": "` + password + `"}`
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/caldav", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := connect("wrong")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "401")
+
+	rec = connect("app-password")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"success": true}`, rec.Body.String())
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// formatCompact asks for trimmed payloads, either as ?format=compact on REST
+// get and list endpoints or as the format argument of compactTools. Voice
+// and low-context clients use it to save tokens.
+const formatCompact = "compact"
+
+// compactTools are the MCP tools that take a format argument.
+var compactTools = []string{"list_todos", "list_notes", "find_recipes", "get_recipe"}
+
+// CompactTodo, CompactNote and CompactRecipe are the compact projections of
+// todos, notes and recipes: enough to name an item and refer back to it.
+type CompactTodo struct {
+	UID     string     `json:"uid"`
+	Title   string     `json:"title"`
+	DueDate *time.Time `json:"due_date,omitempty"`
+}
+
+type CompactNote struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type CompactRecipe struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func compactTodo(t dao.Todo) CompactTodo {
+	return CompactTodo{UID: t.UID, Title: t.Title, DueDate: t.DueDate}
+}
+
+func compactNote(n dao.Notes) CompactNote {
+	return CompactNote{ID: n.ID, Key: n.Key}
+}
+
+func compactRecipe(r dao.Recipes) CompactRecipe {
+	return CompactRecipe{ID: r.ID, Title: r.Title}
+}
+
+func compactRecipeResponse(r recipeResponse) CompactRecipe {
+	return compactRecipe(r.Recipes)
+}
+
+func compactAll[T, C any](in []T, project func(T) C) []C {
+	out := make([]C, len(in))
+	for i, v := range in {
+		out[i] = project(v)
+	}
+	return out
+}
+
+// compactView returns the compact projection of a todo, note or recipe, or
+// a slice of them. Anything else is returned unchanged.
+func compactView(v any) any {
+	switch v := v.(type) {
+	case dao.Todo:
+		return compactTodo(v)
+	case []dao.Todo:
+		return compactAll(v, compactTodo)
+	case dao.Notes:
+		return compactNote(v)
+	case []dao.Notes:
+		return compactAll(v, compactNote)
+	case dao.Recipes:
+		return compactRecipe(v)
+	case []dao.Recipes:
+		return compactAll(v, compactRecipe)
+	case recipeResponse:
+		return compactRecipeResponse(v)
+	case []recipeResponse:
+		return compactAll(v, compactRecipeResponse)
+	}
+	return v
+}
+
+// encodeResponse writes v as JSON, in its compact form when the request asks
+// for ?format=compact, trimmed to the requested ?fields= and with the related
+// objects named by ?expand=. Responses carry an ETag so polling clients can
+// make conditional GETs, except for lists that are streamed.
+func encodeResponse(w http.ResponseWriter, r *http.Request, v any) {
+	if r.URL.Query().Get("format") == formatCompact {
+		v = compactView(v)
+	}
+	if fields := parseFields(r); len(fields) > 0 {
+		v = selectFields(v, fields)
+	}
+	v, ok := expandResponse(w, r, v)
+	if !ok || writeStreamed(w, r, v) {
+		return
+	}
+	writeETagged(w, r, v)
+}
+
+// addFormatArgument gives each of compactTools a format argument.
+func addFormatArgument(tools []mcp.Tool) {
+	for i := range tools {
+		if !slices.Contains(compactTools, tools[i].Name) {
+			continue
+		}
+		tools[i].InputSchema.Properties["format"] = map[string]any{
+			"type":        "string",
+			"enum":        []string{"full", formatCompact},
+			"description": "Use 'compact' for only IDs, titles and due dates",
+		}
+	}
+}
+
+// compactToolResult projects the payload of a successful tool result. The
+// payload values are still the typed values handed to toolOK.
+func compactToolResult(result mcp.CallToolResult) mcp.CallToolResult {
+	body, ok := result.StructuredContent.(map[string]any)
+	if !ok || result.IsError {
+		return result
+	}
+	for k, v := range body {
+		body[k] = compactView(v)
+	}
+	return toolJSON(body, false)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCompactView(t *testing.T) {
+	due := time.Date(2025, 8, 22, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []CompactTodo{{UID: "t1", Title: "Call plumber", DueDate: &due}},
+		compactView([]postgres.Todo{{UID: "t1", Title: "Call plumber", Description: "Leaky tap", DueDate: &due}}))
+	assert.Equal(t, CompactNote{ID: "n1", Key: "wifi"}, compactView(postgres.Notes{ID: "n1", Key: "wifi", Data: "hunter2"}))
+	assert.Equal(t, []CompactRecipe{{ID: "r1", Title: "Carbonara"}},
+		compactView(withPhotoURLsList([]postgres.Recipes{{ID: "r1", Title: "Carbonara", Data: "long method"}})))
+	// Anything else passes through untouched.
+	assert.Equal(t, 3, compactView(3))
+}
+
+func TestTodoListCompact(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).
+		Return([]postgres.Todo{{UID: "t1", Title: "Call plumber", Description: "Leaky tap", Data: "{}"}}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?format=compact", nil))
+	assert.JSONEq(t, `[{"uid": "t1", "title": "Call plumber"}]`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Contains(t, rr.Body.String(), "Leaky tap")
+}
+
+func TestMCPHandlers_CompactFormat(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).
+		Return([]postgres.Todo{{UID: "t1", Title: "Call plumber", Description: "Leaky tap"}}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	tool, _ := h.findTool("list_todos")
+	assert.Contains(t, tool.InputSchema.Properties, "format")
+	tool, _ = h.findTool("create_todo")
+	assert.NotContains(t, tool.InputSchema.Properties, "format")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "list_todos", map[string]any{"format": "compact"}), &body)
+	assert.Equal(t, []any{map[string]any{"uid": "t1", "title": "Call plumber"}}, body["todos"])
+	assert.Equal(t, "Found 1 todos", body["summary"])
+	assert.Equal(t, float64(1), body["count"])
+
+	decodeToolResult(t, h.callTool(t.Context(), "list_todos", map[string]any{}), &body)
+	assert.Equal(t, "Leaky tap", body["todos"].([]any)[0].(map[string]any)["description"])
+}
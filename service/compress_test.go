@@ -0,0 +1,87 @@
+package service
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                       "",
+		"gzip":                   "gzip",
+		"deflate, gzip":          "gzip",
+		"gzip;q=0.5, deflate":    "deflate",
+		"gzip;q=0, deflate;q=0":  "",
+		"*":                      "gzip",
+		"br, *;q=0.1, gzip;q=0":  "deflate",
+		"identity":               "",
+		"GZIP; q=1.0, deflate;q": "gzip",
+	} {
+		assert.Equal(t, want, negotiateEncoding(header), header)
+	}
+}
+
+func serveCompressed(handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	rr := httptest.NewRecorder()
+	Compress(100)(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCompress(t *testing.T) {
+	large := `{"notes": "` + strings.Repeat("milk eggs bread ", 20) + `"}`
+	writeJSON := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = io.WriteString(w, body)
+		}
+	}
+
+	rr := serveCompressed(writeJSON(large), "gzip")
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Equal(t, `W/"v1"`, rr.Header().Get("ETag"))
+	assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+	zr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(zr)
+	assert.Equal(t, large, string(body))
+
+	rr = serveCompressed(writeJSON(large), "deflate")
+	assert.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+	fr, err := zlib.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(fr)
+	assert.Equal(t, large, string(body))
+
+	// Below the threshold, or without Accept-Encoding, the body is untouched.
+	for _, rr := range []*httptest.ResponseRecorder{serveCompressed(writeJSON(`{"ok": true}`), "gzip"), serveCompressed(writeJSON(large), "")} {
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, `"v1"`, rr.Header().Get("ETag"))
+	}
+	assert.Equal(t, `{"ok": true}`, serveCompressed(writeJSON(`{"ok": true}`), "gzip").Body.String())
+
+	// Photos are already compressed.
+	rr = serveCompressed(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(make([]byte, 500))
+	}, "gzip")
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Len(t, rr.Body.Bytes(), 500)
+
+	// Status codes survive being held back.
+	rr = serveCompressed(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}, "gzip")
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.Bytes())
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPHandlers_ConvertUnits(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	ctx := t.Context()
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "convert_units", map[string]any{"quantity": "2 cups", "to": "grams", "ingredient": "flour"}), &body)
+	assert.Equal(t, "2 cup = 250.78 g", body["summary"])
+	assert.Equal(t, 250.78, body["amount"])
+	assert.Equal(t, "g", body["unit"])
+
+	decodeToolResult(t, h.callTool(ctx, "convert_units", map[string]any{"quantity": "1.5 kg", "to": "imperial"}), &body)
+	assert.Equal(t, "3.31 lb", body["quantity"])
+
+	result := h.callTool(ctx, "convert_units", map[string]any{"quantity": "1 cup", "to": "g", "ingredient": "spinach"})
+	assert.True(t, result.IsError)
+	decodeToolResult(t, result, &body)
+	assert.Contains(t, body["error"], "Cannot convert 1 cup to g")
+
+	assert.True(t, h.callTool(ctx, "convert_units", map[string]any{"quantity": "a pinch", "to": "g"}).IsError)
+	assert.True(t, h.callTool(ctx, "convert_units", map[string]any{"quantity": "1 cup", "to": "smidgen"}).IsError)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func corsRequest(handler http.Handler, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/todos", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCORS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://dashboard.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(ok)
+
+	rr := corsRequest(handler, "GET", "https://dashboard.example.com", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "https://dashboard.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "ETag, Mcp-Session-Id", rr.Header().Get("Access-Control-Expose-Headers"))
+
+	rr = corsRequest(handler, "OPTIONS", "https://dashboard.example.com", map[string]string{
+		"Access-Control-Request-Method":  "DELETE",
+		"Access-Control-Request-Headers": "Authorization, Content-Type",
+	})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Contains(t, rr.Header().Get("Access-Control-Allow-Methods"), "DELETE")
+	assert.Equal(t, "Authorization, Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+
+	// Other origins, and requests that aren't cross-origin, get no CORS headers.
+	for _, origin := range []string{"https://evil.example.com", ""} {
+		rr = corsRequest(handler, "GET", origin, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"), origin)
+	}
+	rr = corsRequest(handler, "OPTIONS", "https://evil.example.com", map[string]string{"Access-Control-Request-Method": "DELETE"})
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rr := corsRequest(CORS(CORSConfig{AllowedOrigins: []string{"*"}})(ok), "GET", "https://a.example.com", nil)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+
+	// Browsers refuse "*" with credentials, so the origin is echoed instead.
+	rr = corsRequest(CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})(ok), "GET", "https://a.example.com", nil)
+	assert.Equal(t, "https://a.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+}
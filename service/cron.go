@@ -0,0 +1,194 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule says when a recurring job runs next. Times are in UTC.
+type Schedule interface {
+	Next(after time.Time) time.Time
+	String() string
+}
+
+type every time.Duration
+
+// Every runs a job at each multiple of d since the zero time, so every
+// server agrees on when runs are due. Like time.NewTicker, it panics if d
+// isn't positive.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("non-positive interval for Every")
+	}
+	return every(d)
+}
+
+func (e every) Next(after time.Time) time.Time {
+	d := time.Duration(e)
+	return after.UTC().Truncate(d).Add(d)
+}
+
+func (e every) String() string { return "@every " + time.Duration(e).String() }
+
+// cronSchedule is a parsed five-field cron expression. Each field is a set
+// of the values it matches.
+type cronSchedule struct {
+	spec                          string
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny are set for a "*" day of month or week. When both
+	// are restricted, a day matching either runs, as in cron.
+	domAny, dowAny bool
+}
+
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron parses a cron expression, "minute hour day-of-month month
+// day-of-week" in UTC, e.g. "30 7 * * 1-5". Fields take "*", numbers,
+// ranges, lists and steps ("*/15"); Sunday is 0 or 7. "@hourly", "@daily",
+// "@weekly", "@monthly" and "@every 10m" are also accepted.
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", d)
+		}
+		return Every(interval), nil
+	}
+	expr := spec
+	if e, ok := cronDescriptors[spec]; ok {
+		expr = e
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+	s := &cronSchedule{spec: spec, domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := [5]*map[int]bool{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", spec, err)
+		}
+		*sets[i] = set
+	}
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	return s, nil
+}
+
+// dailyAt runs a job at hour o'clock UTC on weekdays, or on every day if
+// none are given. An hour outside 0-23 never comes.
+func dailyAt(hour int, weekdays ...time.Weekday) Schedule {
+	s := &cronSchedule{
+		minute: map[int]bool{0: true},
+		hour:   map[int]bool{hour: true},
+		dom:    cronRange(1, 31),
+		month:  cronRange(1, 12),
+		dow:    cronRange(0, 6),
+		domAny: true,
+	}
+	days := "*"
+	if len(weekdays) > 0 {
+		s.dow = map[int]bool{}
+		names := make([]string, len(weekdays))
+		for i, d := range weekdays {
+			s.dow[int(d)] = true
+			names[i] = strconv.Itoa(int(d))
+		}
+		days = strings.Join(names, ",")
+	}
+	s.spec = fmt.Sprintf("0 %d * * %s", hour, days)
+	return s
+}
+
+func cronRange(lo, hi int) map[int]bool {
+	set := map[int]bool{}
+	for v := lo; v <= hi; v++ {
+		set[v] = true
+	}
+	return set
+}
+
+func parseCronField(field string, lo, hi int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (s *cronSchedule) String() string { return s.spec }
+
+// Next returns the first minute after after that s matches, or the zero
+// time if none does within five years, e.g. for "0 0 31 2 *".
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.month[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.hour[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	// Friday 15 August 2025, 10:20.
+	now := time.Date(2025, 8, 15, 10, 20, 30, 0, time.UTC)
+	for spec, want := range map[string]time.Time{
+		"*/15 * * * *":  time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC),
+		"30 7 * * 1-5":  time.Date(2025, 8, 18, 7, 30, 0, 0, time.UTC),
+		"0 9 * * 7":     time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC),
+		"0 0 1,15 * *":  time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 31 * 5":    time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC),
+		"0 12 29 2 *":   time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		"@daily":        time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC),
+		"@every 45m":    time.Date(2025, 8, 15, 10, 30, 0, 0, time.UTC),
+		" 20 10 * * * ": time.Date(2025, 8, 16, 10, 20, 0, 0, time.UTC),
+	} {
+		s, err := ParseCron(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, s.Next(now), spec)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every -1m", "@yearly"} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+
+	never, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(now).IsZero())
+}
+
+func TestDailyAt(t *testing.T) {
+	sunday := time.Date(2025, 8, 17, 18, 0, 0, 0, time.UTC)
+	weekly := dailyAt(18, time.Sunday)
+	assert.Equal(t, "0 18 * * 0", weekly.String())
+	assert.Equal(t, sunday, weekly.Next(sunday.Add(-time.Minute)))
+	assert.Equal(t, sunday.AddDate(0, 0, 7), weekly.Next(sunday))
+	assert.Equal(t, sunday.AddDate(0, 0, 1), dailyAt(18).Next(sunday))
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// Public dashboard sections. Meals is the household's latest meal_plan
+// note; todos are its open todos, soonest due first.
+const (
+	DashboardMeals = "meals"
+	DashboardTodos = "todos"
+)
+
+var dashboardSections = []string{DashboardMeals, DashboardTodos}
+
+const (
+	dashboardTokenPrefix = "dt_"
+	// mealPlanNoteKey is the key of the notes a household plans its meals
+	// in.
+	mealPlanNoteKey = "meal_plan"
+	// dashboardTodoLimit caps the todos a dashboard shows.
+	dashboardTodoLimit = 50
+)
+
+type dashboardTokenDAO interface {
+	CreateDashboardToken(ctx context.Context, t dao.DashboardToken) (dao.DashboardToken, error)
+	GetDashboardTokenByHash(ctx context.Context, tokenHash string) (dao.DashboardToken, error)
+	ListDashboardTokens(ctx context.Context, householdUID string) ([]dao.DashboardToken, error)
+	RevokeDashboardToken(ctx context.Context, uid string) error
+	TouchDashboardToken(ctx context.Context, uid string) error
+}
+
+type DashboardTokenHandlers struct{ dao dashboardTokenDAO }
+
+type CreateDashboardTokenRequest struct {
+	HouseholdUID string `json:"household_uid"`
+	Name         string `json:"name"`
+	// Sections default to all of them.
+	Sections []string `json:"sections"`
+}
+
+// CreateDashboardTokenResponse carries the plaintext token and the public
+// URL path it opens. They are only ever returned once; the server stores a
+// hash.
+type CreateDashboardTokenResponse struct {
+	Token          string             `json:"token"`
+	Path           string             `json:"path"`
+	DashboardToken dao.DashboardToken `json:"dashboard_token"`
+}
+
+// NewDashboardTokens manages the share tokens of households' public
+// dashboards. Callers with an API key manage their own household's tokens;
+// operators name the household.
+func NewDashboardTokens(dao dashboardTokenDAO) http.Handler {
+	h := &DashboardTokenHandlers{dao}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Delete("/{uid}", h.revoke)
+	return r
+}
+
+// tokenHousehold returns the household whose tokens the caller manages:
+// requested, for operators, and otherwise the household of the caller's API
+// key, which requested must match when given. It writes the error and
+// returns false when there is none.
+func tokenHousehold(w http.ResponseWriter, r *http.Request, requested string) (string, bool) {
+	if IsOperator(r.Context()) {
+		if requested == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return "", false
+		}
+		return requested, true
+	}
+	id, ok := IdentityFromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return "", false
+	}
+	if id.HouseholdUID == "" || requested != "" && requested != id.HouseholdUID {
+		w.WriteHeader(http.StatusForbidden)
+		return "", false
+	}
+	return id.HouseholdUID, true
+}
+
+func (h *DashboardTokenHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateDashboardTokenRequest
+	if json.NewDecoder(r.Body).Decode(&req) != nil || req.Name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	household, ok := tokenHousehold(w, r, req.HouseholdUID)
+	if !ok {
+		return
+	}
+	if len(req.Sections) == 0 {
+		req.Sections = dashboardSections
+	}
+	for _, section := range req.Sections {
+		if !slices.Contains(dashboardSections, section) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unknown section " + section + ": sections are " + strings.Join(dashboardSections, ", ")})
+			return
+		}
+	}
+
+	token, err := generateDashboardToken()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	out, err := h.dao.CreateDashboardToken(r.Context(), dao.DashboardToken{
+		HouseholdUID: household,
+		Name:         req.Name,
+		TokenHash:    hashAPIKey(token),
+		Sections:     req.Sections,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(CreateDashboardTokenResponse{Token: token, Path: "/public/" + token, DashboardToken: out})
+}
+
+func (h *DashboardTokenHandlers) list(w http.ResponseWriter, r *http.Request) {
+	household, ok := tokenHousehold(w, r, r.URL.Query().Get("household_uid"))
+	if !ok {
+		return
+	}
+	out, err := h.dao.ListDashboardTokens(r.Context(), household)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *DashboardTokenHandlers) revoke(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	if !IsOperator(r.Context()) {
+		household, ok := tokenHousehold(w, r, "")
+		if !ok {
+			return
+		}
+		owned, err := h.dao.ListDashboardTokens(r.Context(), household)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !slices.ContainsFunc(owned, func(t dao.DashboardToken) bool { return t.UID == uid }) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+	if h.dao.RevokeDashboardToken(r.Context(), uid) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func generateDashboardToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return dashboardTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PublicDashboard is what a dashboard token shows. Sections the token
+// doesn't grant are left out.
+type PublicDashboard struct {
+	Name  string          `json:"name"`
+	Meals *DashboardMeal  `json:"meals,omitempty"`
+	Todos []DashboardTodo `json:"todos,omitempty"`
+}
+
+// DashboardMeal is the household's meal plan, as written in its meal_plan
+// note.
+type DashboardMeal struct {
+	Plan      string    `json:"plan"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DashboardTodo is an open todo with only what a shared screen needs.
+type DashboardTodo struct {
+	Title    string         `json:"title"`
+	DueDate  *time.Time     `json:"due_date,omitempty"`
+	Priority dao.Priority   `json:"priority"`
+	Status   dao.TodoStatus `json:"status"`
+}
+
+type PublicDashboardHandlers struct {
+	tokens dashboardTokenDAO
+	todos  todoDAO
+	notes  notesDAO
+}
+
+// NewPublicDashboard serves GET /{token}, the read-only dashboard a
+// dashboard token opens, without any login. Unknown and revoked tokens are
+// 404s.
+func NewPublicDashboard(tokens dashboardTokenDAO, todos todoDAO, notes notesDAO) http.Handler {
+	h := &PublicDashboardHandlers{tokens: tokens, todos: todos, notes: notes}
+	r := chi.NewRouter()
+	r.Get("/{token}", h.get)
+	return r
+}
+
+func (h *PublicDashboardHandlers) get(w http.ResponseWriter, r *http.Request) {
+	// The token's tenant isn't known until it is found.
+	lookup := dao.WithAllTenants(r.Context())
+	t, err := h.tokens.GetDashboardTokenByHash(lookup, hashAPIKey(chi.URLParam(r, "token")))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := h.tokens.TouchDashboardToken(lookup, t.UID); err != nil {
+		slog.Warn("Failed to record dashboard token use", slog.String("dashboard_token_uid", t.UID), slog.String("error", err.Error()))
+	}
+	ctx := dao.WithTenant(r.Context(), t.TenantUID)
+
+	out := PublicDashboard{Name: t.Name}
+	if slices.Contains(t.Sections, DashboardMeals) {
+		notes, err := h.notes.ListNotes(ctx, dao.ListOptions{
+			Limit:       1,
+			SortBy:      "updated_at",
+			SortDir:     "DESC",
+			WhereClause: "WHERE household_uid = $1 AND key = $2 AND visibility <> $3 AND archived_at IS NULL",
+			WhereArgs:   []any{t.HouseholdUID, mealPlanNoteKey, dao.NoteVisibilityPrivate},
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(notes) > 0 {
+			out.Meals = &DashboardMeal{Plan: notes[0].Data, UpdatedAt: notes[0].UpdatedAt}
+		}
+	}
+	if slices.Contains(t.Sections, DashboardTodos) {
+		todos, err := h.todos.ListTodos(ctx, dao.ListOptions{
+			Limit:       dashboardTodoLimit,
+			SortBy:      "due_date",
+			SortDir:     "ASC",
+			WhereClause: "WHERE household_uid = $1 AND marked_complete IS NULL",
+			WhereArgs:   []any{t.HouseholdUID},
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		out.Todos = []DashboardTodo{}
+		for _, todo := range todos {
+			out.Todos = append(out.Todos, DashboardTodo{Title: todo.Title, DueDate: todo.DueDate, Priority: todo.Priority, Status: todo.Status})
+		}
+	}
+	// Tablets poll; let them revalidate against the ETag rather than
+	// caching a view that may have been revoked.
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	encodeResponse(w, r, out)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardTokensCreate(t *testing.T) {
+	tokens := mocks.NewMockdashboardTokenDAO(t)
+	var storedHash string
+	tokens.On("CreateDashboardToken", mock.Anything, mock.MatchedBy(func(d postgres.DashboardToken) bool {
+		storedHash = d.TokenHash
+		return d.HouseholdUID == "house-1" && d.Name == "Fridge" && assert.ObjectsAreEqual(dashboardSections, d.Sections)
+	})).Return(postgres.DashboardToken{UID: "dt-1", HouseholdUID: "house-1", Name: "Fridge", Sections: dashboardSections}, nil)
+	handler := NewDashboardTokens(tokens)
+
+	member := WithIdentity(context.Background(), Identity{UserUID: "user-1", HouseholdUID: "house-1"})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"name": "Fridge"}`)).WithContext(member))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp CreateDashboardTokenResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.True(t, strings.HasPrefix(resp.Token, dashboardTokenPrefix))
+	assert.Equal(t, "/public/"+resp.Token, resp.Path)
+	assert.Equal(t, hashAPIKey(resp.Token), storedHash)
+	assert.NotContains(t, rr.Body.String(), "token_hash")
+
+	for _, body := range []string{`{"name": "Fridge"}`, `{"household_uid": "house-1"}`, `{"household_uid": "house-1", "name": "Fridge", "sections": ["recipes"]}`} {
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(body))))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+
+	tokens.On("RevokeDashboardToken", mock.Anything, "dt-1").Return(nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/dt-1", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestDashboardTokensStayInHousehold(t *testing.T) {
+	tokens := mocks.NewMockdashboardTokenDAO(t)
+	tokens.On("ListDashboardTokens", mock.Anything, "house-1").Return([]postgres.DashboardToken{{UID: "dt-1", HouseholdUID: "house-1"}}, nil)
+	tokens.On("RevokeDashboardToken", mock.Anything, "dt-1").Return(nil)
+	handler := NewDashboardTokens(tokens)
+	member := WithIdentity(context.Background(), Identity{UserUID: "user-1", HouseholdUID: "house-1"})
+	serve := func(ctx context.Context, method, target, body string) int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx))
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "GET", "/", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "POST", "/", `{"household_uid": "house-1", "name": "Fridge"}`))
+	assert.Equal(t, http.StatusUnauthorized, serve(context.Background(), "DELETE", "/dt-1", ""))
+	assert.Equal(t, http.StatusForbidden, serve(member, "GET", "/?household_uid=house-2", ""))
+	assert.Equal(t, http.StatusForbidden, serve(member, "POST", "/", `{"household_uid": "house-2", "name": "Fridge"}`))
+	assert.Equal(t, http.StatusForbidden, serve(WithIdentity(context.Background(), Identity{UserUID: "user-2"}), "GET", "/", ""))
+	assert.Equal(t, http.StatusOK, serve(member, "GET", "/", ""))
+	assert.Equal(t, http.StatusNotFound, serve(member, "DELETE", "/dt-other", ""))
+	assert.Equal(t, http.StatusNoContent, serve(member, "DELETE", "/dt-1", ""))
+	tokens.AssertNotCalled(t, "RevokeDashboardToken", mock.Anything, "dt-other")
+}
+
+func TestPublicDashboard(t *testing.T) {
+	due := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tokens := mocks.NewMockdashboardTokenDAO(t)
+	tokens.On("GetDashboardTokenByHash", mock.Anything, hashAPIKey("dt_fridge")).
+		Return(postgres.DashboardToken{UID: "dt-1", HouseholdUID: "house-1", Name: "Fridge", Sections: dashboardSections, TenantUID: "tenant-1"}, nil)
+	tokens.On("GetDashboardTokenByHash", mock.Anything, hashAPIKey("dt_todos")).
+		Return(postgres.DashboardToken{UID: "dt-2", HouseholdUID: "house-1", Name: "Hall", Sections: []string{DashboardTodos}}, nil)
+	tokens.On("GetDashboardTokenByHash", mock.Anything, mock.Anything).Return(postgres.DashboardToken{}, pgx.ErrNoRows)
+	tokens.On("TouchDashboardToken", mock.Anything, mock.Anything).Return(nil)
+
+	notes := &MockNotesDAO{}
+	notes.On("ListNotes", mock.MatchedBy(func(ctx context.Context) bool {
+		tenant, _ := postgres.TenantFromContext(ctx)
+		return tenant == "tenant-1"
+	}), mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND key = $2 AND visibility <> $3 AND archived_at IS NULL" &&
+			assert.ObjectsAreEqual([]any{"house-1", mealPlanNoteKey, postgres.NoteVisibilityPrivate}, o.WhereArgs)
+	})).Return([]postgres.Notes{{Key: mealPlanNoteKey, Data: "Mon: tacos\nTue: soup"}}, nil)
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND marked_complete IS NULL" && o.WhereArgs[0] == "house-1"
+	})).Return([]postgres.Todo{{UID: "t1", Title: "Bins out", Description: "secret", DueDate: &due, Priority: postgres.PriorityHigh}}, nil)
+	handler := NewPublicDashboard(tokens, todos, notes)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/dt_fridge", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	var got PublicDashboard
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, "Fridge", got.Name)
+	require.NotNil(t, got.Meals)
+	assert.Equal(t, "Mon: tacos\nTue: soup", got.Meals.Plan)
+	assert.Equal(t, []DashboardTodo{{Title: "Bins out", DueDate: &due, Priority: postgres.PriorityHigh}}, got.Todos)
+	assert.NotContains(t, rr.Body.String(), "secret")
+	assert.NotContains(t, rr.Body.String(), "t1")
+
+	// A token only shows its sections.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/dt_todos", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "meals")
+	notes.AssertNumberOfCalls(t, "ListNotes", 1)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/dt_revoked", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/schema"
+)
+
+type dataSchemaDAO interface {
+	PutDataSchema(ctx context.Context, s dao.DataSchema) (dao.DataSchema, error)
+	FindDataSchema(ctx context.Context, entity, key string) (*dao.DataSchema, error)
+	ListDataSchemas(ctx context.Context) ([]dao.DataSchema, error)
+	DeleteDataSchema(ctx context.Context, entity, key string) error
+}
+
+func validDataSchemaEntity(entity string) bool {
+	return entity == dao.DataSchemaNotes || entity == dao.DataSchemaPreferences
+}
+
+// validateData checks data against the schema registered for the entity's
+// key. Without schemas (d is nil) or a schema for the key, anything goes.
+func validateData(ctx context.Context, d dataSchemaDAO, entity, key, data string) ([]schema.FieldError, error) {
+	if d == nil {
+		return nil, nil
+	}
+	registered, err := d.FindDataSchema(ctx, entity, key)
+	if err != nil || registered == nil {
+		return nil, err
+	}
+	s, err := schema.Parse(registered.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema for %s key %q: %w", entity, key, err)
+	}
+	return s.Validate([]byte(data)), nil
+}
+
+// checkData validates a REST write's data, answering 400 with the field
+// errors when it doesn't match. It reports whether the write may go ahead.
+func checkData(w http.ResponseWriter, r *http.Request, d dataSchemaDAO, entity, key, data string) bool {
+	fields, err := validateData(r.Context(), d, entity, key, data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if fields == nil {
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error":  fmt.Sprintf("data doesn't match the schema for %s key %q", entity, key),
+		"fields": fields,
+	})
+	return false
+}
+
+// checkToolData is checkData for MCP tools. It returns an error result when
+// the data doesn't match, listing the field errors so the assistant can fix
+// them.
+func checkToolData(ctx context.Context, d dataSchemaDAO, entity, key, data string) *mcp.CallToolResult {
+	fields, err := validateData(ctx, d, entity, key, data)
+	if err != nil {
+		result := toolError("Failed to check data: %v", err)
+		return &result
+	}
+	if fields == nil {
+		return nil
+	}
+	msgs := make([]string, len(fields))
+	for i, f := range fields {
+		msgs[i] = f.Error()
+	}
+	result := toolError("data doesn't match the schema for %s key %q (see get_data_schema): %s", entity, key, strings.Join(msgs, "; "))
+	return &result
+}
+
+// WithNoteSchemas validates the data of notes whose key has a schema.
+func WithNoteSchemas(d dataSchemaDAO) NotesOption {
+	return func(h *NotesHandlers) { h.schemas = d }
+}
+
+// WithPreferenceSchemas validates the data of preferences whose key has a
+// schema.
+func WithPreferenceSchemas(d dataSchemaDAO) PreferencesOption {
+	return func(h *PreferencesHandlers) { h.schemas = d }
+}
+
+// WithDataSchemas validates the data saved by save_note and set_preference
+// and enables the get_data_schema tool.
+func WithDataSchemas(d dataSchemaDAO) MCPOption {
+	return func(h *MCPHandlers) { h.dataSchemaDAO = d }
+}
+
+func (h *MCPHandlers) handleGetDataSchema(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	entity, _ := arguments["entity"].(string)
+	if !validDataSchemaEntity(entity) {
+		return toolError("entity must be %q or %q", dao.DataSchemaNotes, dao.DataSchemaPreferences)
+	}
+	key, ok := arguments["key"].(string)
+	if !ok || key == "" {
+		return toolError("key is required")
+	}
+	s, err := h.dataSchemaDAO.FindDataSchema(ctx, entity, key)
+	if err != nil {
+		return toolError("Failed to get schema: %v", err)
+	}
+	if s == nil {
+		return toolOK(fmt.Sprintf("No schema for %s key %q; its data can be anything", entity, key), map[string]any{"schema": nil})
+	}
+	return toolOK("Schema found", map[string]any{"schema": s})
+}
+
+type DataSchemaHandlers struct{ dao dataSchemaDAO }
+
+// NewDataSchemas manages the JSON Schemas for note and preference data.
+// Only operators may change them.
+func NewDataSchemas(dao dataSchemaDAO) http.Handler {
+	h := &DataSchemaHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/", h.list)
+	r.Get("/{entity}/{key}", h.get)
+	r.With(operatorsOnly).Put("/{entity}/{key}", h.put)
+	r.With(operatorsOnly).Delete("/{entity}/{key}", h.delete)
+	return r
+}
+
+func (h *DataSchemaHandlers) list(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.ListDataSchemas(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *DataSchemaHandlers) get(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.FindDataSchema(r.Context(), chi.URLParam(r, "entity"), chi.URLParam(r, "key"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if out == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// put registers the request body as the schema for a key, replacing any it
+// had. Data already saved under the key isn't rechecked.
+func (h *DataSchemaHandlers) put(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if json.NewDecoder(r.Body).Decode(&raw) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	entity := chi.URLParam(r, "entity")
+	if !validDataSchemaEntity(entity) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if _, err := schema.Parse(raw); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.PutDataSchema(r.Context(), dao.DataSchema{Entity: entity, Key: chi.URLParam(r, "key"), Schema: raw})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *DataSchemaHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteDataSchema(r.Context(), chi.URLParam(r, "entity"), chi.URLParam(r, "key")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
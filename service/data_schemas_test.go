@@ -0,0 +1,131 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const mealPlanSchema = `{"type": "object", "required": ["dinner"], "properties": {"dinner": {"type": "string"}, "servings": {"type": "integer", "minimum": 1}}}`
+
+func mealPlanSchemas(t *testing.T) *mocks.MockdataSchemaDAO {
+	d := mocks.NewMockdataSchemaDAO(t)
+	d.On("FindDataSchema", mock.Anything, mock.Anything, "meal_plan").
+		Return(&postgres.DataSchema{Key: "meal_plan", Schema: json.RawMessage(mealPlanSchema)}, nil).Maybe()
+	d.On("FindDataSchema", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return d
+}
+
+func TestDataSchemaHandlers(t *testing.T) {
+	d := mocks.NewMockdataSchemaDAO(t)
+	d.On("PutDataSchema", mock.Anything, postgres.DataSchema{Entity: "notes", Key: "meal_plan", Schema: json.RawMessage(mealPlanSchema)}).
+		Return(postgres.DataSchema{Entity: "notes", Key: "meal_plan"}, nil)
+	d.On("FindDataSchema", mock.Anything, "preferences", "diet").Return(nil, nil)
+	d.On("DeleteDataSchema", mock.Anything, "notes", "meal_plan").Return(nil)
+	handler := NewDataSchemas(d)
+	asMember := func(r *http.Request) *http.Request { return r.WithContext(identityContext("user-1", "house-1")) }
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("PUT", "/notes/meal_plan", strings.NewReader(mealPlanSchema))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("PUT", "/notes/meal_plan", strings.NewReader(`{"oneOf": [{"type": "string"}]}`))))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `unsupported keyword \"oneOf\"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("PUT", "/recipes/dinner", strings.NewReader(`{}`))))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("PUT", "/notes/meal_plan", strings.NewReader(mealPlanSchema))))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/notes/meal_plan", strings.NewReader(mealPlanSchema)))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/preferences/diet", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("DELETE", "/notes/meal_plan", nil)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/notes/meal_plan", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestNotesValidateData(t *testing.T) {
+	notes := mocks.NewMocknotesDAO(t)
+	notes.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool { return n.Key == "meal_plan" })).
+		Return(postgres.Notes{ID: "note-1"}, nil).Once()
+	handler := NewNotes(notes, WithNoteSchemas(mealPlanSchemas(t)))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "meal_plan", "data": "{\"servings\": 0}"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var body struct {
+		Error  string `json:"error"`
+		Fields []struct {
+			Path    string `json:"path"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, `data doesn't match the schema for notes key "meal_plan"`, body.Error)
+	if assert.Len(t, body.Fields, 2) {
+		assert.Equal(t, "/dinner", body.Fields[0].Path)
+		assert.Equal(t, "/servings", body.Fields[1].Path)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "meal_plan", "data": "{\"dinner\": \"Lasagne\", \"servings\": 4}"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/note-1", strings.NewReader(`{"key": "meal_plan", "data": "Lasagne"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "must be valid JSON")
+}
+
+func TestPreferencesValidateData(t *testing.T) {
+	prefs := mocks.NewMockpreferencesDAO(t)
+	prefs.On("UpdatePreferences", mock.Anything, "diet", "user-1", mock.Anything).Return(postgres.Preferences{Key: "diet"}, nil).Once()
+	handler := NewPreferences(prefs, WithPreferenceSchemas(mealPlanSchemas(t)))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "meal_plan", "specifier": "user-1", "data": "[]"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"message":"must be object"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/diet/user-1", strings.NewReader(`{"data": "vegetarian"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestMCPHandlers_SaveNoteValidatesData(t *testing.T) {
+	notes := &MockNotesDAO{}
+	h := &MCPHandlers{notesDAO: notes, dataSchemaDAO: mealPlanSchemas(t)}
+
+	result := h.handleSaveNote(t.Context(), map[string]any{"key": "meal_plan", "data": `{"dinner": 3}`})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "/dinner: must be string")
+	notes.AssertNotCalled(t, "CreateNotes", mock.Anything, mock.Anything)
+
+	result = h.handleGetDataSchema(t.Context(), map[string]any{"entity": "notes", "key": "meal_plan"})
+	assert.False(t, result.IsError)
+	result = h.handleGetDataSchema(t.Context(), map[string]any{"entity": "recipes", "key": "meal_plan"})
+	assert.True(t, result.IsError)
+}
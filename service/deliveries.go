@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+var (
+	ErrDeliveryNotFailed  = errors.New("only failed deliveries can be replayed")
+	ErrChannelUnavailable = errors.New("delivery channel is not configured")
+)
+
+type deliveryDAO interface {
+	CreateDelivery(ctx context.Context, d dao.Delivery) (dao.Delivery, error)
+	GetDelivery(ctx context.Context, uid string) (dao.Delivery, error)
+	ListDeliveries(ctx context.Context, options dao.ListOptions) ([]dao.Delivery, error)
+	RecordDeliveryAttempt(ctx context.Context, uid, status string, lastError *string) (dao.Delivery, error)
+}
+
+// DeliveryLog records every message sent through the notifiers and pushers
+// it wraps, and can send failed ones again. Failing to record a delivery is
+// logged but doesn't stop the message being sent.
+type DeliveryLog struct {
+	dao      deliveryDAO
+	channels map[string]func(ctx context.Context, payload []byte) error
+}
+
+func NewDeliveryLog(dao deliveryDAO) *DeliveryLog {
+	return &DeliveryLog{dao: dao, channels: map[string]func(context.Context, []byte) error{}}
+}
+
+// Notifier returns n with its messages recorded as deliveries on
+// ChannelEmail. Pushes are recorded under their platform instead.
+func (l *DeliveryLog) Notifier(n notify.Notifier) notify.Notifier {
+	l.channels[ChannelEmail] = func(ctx context.Context, payload []byte) error {
+		var m notify.Message
+		if err := json.Unmarshal(payload, &m); err != nil {
+			return err
+		}
+		return n.Send(ctx, m)
+	}
+	return &loggedNotifier{log: l, next: n}
+}
+
+// Pushers returns pushers, keyed by platform, with their pushes recorded as
+// deliveries on that platform's channel.
+func (l *DeliveryLog) Pushers(pushers map[string]notify.Pusher) map[string]notify.Pusher {
+	out := make(map[string]notify.Pusher, len(pushers))
+	for platform, p := range pushers {
+		l.channels[platform] = func(ctx context.Context, payload []byte) error {
+			var push notify.Push
+			if err := json.Unmarshal(payload, &push); err != nil {
+				return err
+			}
+			return p.Push(ctx, push)
+		}
+		out[platform] = &loggedPusher{log: l, platform: platform, next: p}
+	}
+	return out
+}
+
+type loggedNotifier struct {
+	log  *DeliveryLog
+	next notify.Notifier
+}
+
+func (n *loggedNotifier) Send(ctx context.Context, m notify.Message) error {
+	err := n.next.Send(ctx, m)
+	n.log.record(ctx, ChannelEmail, m.To, m, err)
+	return err
+}
+
+type loggedPusher struct {
+	log      *DeliveryLog
+	platform string
+	next     notify.Pusher
+}
+
+func (p *loggedPusher) Push(ctx context.Context, push notify.Push) error {
+	err := p.next.Push(ctx, push)
+	p.log.record(ctx, p.platform, push.Token, push, err)
+	return err
+}
+
+func (l *DeliveryLog) record(ctx context.Context, channel, recipient string, msg any, sendErr error) {
+	payload, err := json.Marshal(msg)
+	if err == nil {
+		sum := sha256.Sum256(payload)
+		status, lastError := deliveryOutcome(sendErr)
+		_, err = l.dao.CreateDelivery(ctx, dao.Delivery{
+			Channel:     channel,
+			Recipient:   recipient,
+			Payload:     payload,
+			PayloadHash: hex.EncodeToString(sum[:]),
+			Status:      status,
+			LastError:   lastError,
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to record delivery", "channel", channel, "error", err)
+	}
+}
+
+func deliveryOutcome(err error) (string, *string) {
+	if err == nil {
+		return dao.DeliverySent, nil
+	}
+	msg := err.Error()
+	return dao.DeliveryFailed, &msg
+}
+
+// Replay sends a failed delivery's payload again on its channel and records
+// the attempt. A replay that fails again is not an error: the delivery is
+// returned still failed, with the new error.
+func (l *DeliveryLog) Replay(ctx context.Context, uid string) (dao.Delivery, error) {
+	d, err := l.dao.GetDelivery(ctx, uid)
+	if err != nil {
+		return dao.Delivery{}, err
+	}
+	if d.Status != dao.DeliveryFailed {
+		return dao.Delivery{}, ErrDeliveryNotFailed
+	}
+	send, ok := l.channels[d.Channel]
+	if !ok {
+		return dao.Delivery{}, fmt.Errorf("%w: %s", ErrChannelUnavailable, d.Channel)
+	}
+	status, lastError := deliveryOutcome(send(ctx, d.Payload))
+	return l.dao.RecordDeliveryAttempt(ctx, uid, status, lastError)
+}
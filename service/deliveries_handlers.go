@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type DeliveryHandlers struct{ log *DeliveryLog }
+
+// NewDeliveries lets operators inspect the delivery log and replay failed
+// deliveries. Payloads hold other users' addresses and messages, so it is
+// closed to callers with an API key.
+func NewDeliveries(log *DeliveryLog) http.Handler {
+	h := &DeliveryHandlers{log}
+	r := chi.NewRouter()
+	r.Use(httpLogger(), operatorsOnly)
+	r.Get("/", h.list)
+	r.Get("/{uid}", h.get)
+	r.Post("/{uid}/replay", h.replay)
+	return r
+}
+
+// operatorsOnly refuses requests from anyone but operators: 403 for callers
+// with an API key and 401 for the rest.
+func operatorsOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !IsOperator(r.Context()) {
+			if _, ok := IdentityFromContext(r.Context()); ok {
+				w.WriteHeader(http.StatusForbidden)
+			} else {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *DeliveryHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, DeliveryFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, DeliveryFilters.Filters)
+	if !ok {
+		return
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		if status != dao.DeliverySent && status != dao.DeliveryFailed {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "status must be sent or failed"})
+			return
+		}
+		whereClause, whereArgs = withStatus(whereClause, whereArgs, status)
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.log.dao.ListDeliveries(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *DeliveryHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Delivery, error) { return h.log.dao.GetDelivery(ctx, uid) },
+		h.log.dao.ListDeliveries, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// replay sends a failed delivery again. It responds 502 with the delivery
+// when the channel fails it again.
+func (h *DeliveryHandlers) replay(w http.ResponseWriter, r *http.Request) {
+	out, err := h.log.Replay(r.Context(), chi.URLParam(r, "uid"))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case errors.Is(err, ErrDeliveryNotFailed), errors.Is(err, ErrChannelUnavailable):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if out.Status == dao.DeliveryFailed {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeliveriesList(t *testing.T) {
+	deliveries := mocks.NewMockdeliveryDAO(t)
+	deliveries.On("ListDeliveries", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE channel = $1 AND status = $2" && o.WhereArgs[1] == "failed"
+	})).Return([]postgres.Delivery{{UID: "d1", Status: postgres.DeliveryFailed}}, nil)
+	handler := NewDeliveries(NewDeliveryLog(deliveries))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/?channel=email&status=failed", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"d1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/?status=done", nil)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Callers with an API key can't read other users' messages.
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Nor can callers with neither a key nor the operator token.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestDeliveriesReplay(t *testing.T) {
+	deliveries := mocks.NewMockdeliveryDAO(t)
+	failed := postgres.Delivery{Channel: notify.PlatformAPNs, Status: postgres.DeliveryFailed, Payload: []byte(`{"token":"phone","title":"Bins"}`)}
+	deliveries.On("GetDelivery", mock.Anything, "missing").Return(postgres.Delivery{}, pgx.ErrNoRows)
+	deliveries.On("GetDelivery", mock.Anything, "sent").Return(postgres.Delivery{Status: postgres.DeliverySent}, nil)
+	deliveries.On("GetDelivery", mock.Anything, mock.Anything).Return(failed, nil)
+	deliveries.On("RecordDeliveryAttempt", mock.Anything, "ok", postgres.DeliverySent, (*string)(nil)).
+		Return(postgres.Delivery{UID: "ok", Status: postgres.DeliverySent}, nil)
+	deliveries.On("RecordDeliveryAttempt", mock.Anything, "still-failing", postgres.DeliveryFailed, mock.Anything).
+		Return(postgres.Delivery{UID: "still-failing", Status: postgres.DeliveryFailed}, nil)
+	log := NewDeliveryLog(deliveries)
+	pusher := &fakePusher{}
+	log.Pushers(map[string]notify.Pusher{notify.PlatformAPNs: pusher})
+	handler := NewDeliveries(log)
+
+	replay := func(uid string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/"+uid+"/replay", nil)))
+		return rr
+	}
+	assert.Equal(t, http.StatusOK, replay("ok").Code)
+	assert.Equal(t, []notify.Push{{Token: "phone", Title: "Bins"}}, pusher.pushed)
+	assert.Equal(t, http.StatusNotFound, replay("missing").Code)
+	assert.Equal(t, http.StatusConflict, replay("sent").Code)
+
+	pusher.errs = map[string]error{"phone": context.DeadlineExceeded}
+	assert.Equal(t, http.StatusBadGateway, replay("still-failing").Code)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryLogRecordsAttempts(t *testing.T) {
+	deliveries := mocks.NewMockdeliveryDAO(t)
+	var recorded []postgres.Delivery
+	deliveries.On("CreateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(postgres.Delivery))
+	}).Return(postgres.Delivery{}, nil)
+	log := NewDeliveryLog(deliveries)
+
+	require.NoError(t, log.Notifier(&recordingNotifier{}).Send(context.Background(), notify.Message{To: "sam@example.com", Subject: "Digest"}))
+	pushers := log.Pushers(map[string]notify.Pusher{notify.PlatformAPNs: &fakePusher{errs: map[string]error{"old-phone": notify.ErrUnregistered}}})
+	assert.ErrorIs(t, pushers[notify.PlatformAPNs].Push(context.Background(), notify.Push{Token: "old-phone", Title: "Bins"}), notify.ErrUnregistered)
+
+	require.Len(t, recorded, 2)
+	assert.Equal(t, ChannelEmail, recorded[0].Channel)
+	assert.Equal(t, "sam@example.com", recorded[0].Recipient)
+	assert.Equal(t, postgres.DeliverySent, recorded[0].Status)
+	assert.JSONEq(t, `{"to":"sam@example.com","subject":"Digest","body":""}`, string(recorded[0].Payload))
+	assert.Len(t, recorded[0].PayloadHash, 64)
+	assert.Equal(t, notify.PlatformAPNs, recorded[1].Channel)
+	assert.Equal(t, postgres.DeliveryFailed, recorded[1].Status)
+	assert.Equal(t, notify.ErrUnregistered.Error(), *recorded[1].LastError)
+}
+
+func TestDeliveryLogRecordingFailureStillSends(t *testing.T) {
+	deliveries := mocks.NewMockdeliveryDAO(t)
+	deliveries.On("CreateDelivery", mock.Anything, mock.Anything).Return(postgres.Delivery{}, errors.New("db down"))
+	notifier := &recordingNotifier{}
+
+	require.NoError(t, NewDeliveryLog(deliveries).Notifier(notifier).Send(context.Background(), notify.Message{To: "sam@example.com"}))
+	assert.Len(t, notifier.sent, 1)
+}
+
+func TestDeliveryLogReplay(t *testing.T) {
+	deliveries := mocks.NewMockdeliveryDAO(t)
+	deliveries.On("GetDelivery", mock.Anything, "d1").
+		Return(postgres.Delivery{UID: "d1", Channel: ChannelEmail, Status: postgres.DeliveryFailed, Payload: []byte(`{"to":"sam@example.com","subject":"Digest"}`)}, nil)
+	deliveries.On("GetDelivery", mock.Anything, "d2").Return(postgres.Delivery{UID: "d2", Channel: ChannelEmail, Status: postgres.DeliverySent}, nil)
+	deliveries.On("GetDelivery", mock.Anything, "d3").Return(postgres.Delivery{UID: "d3", Channel: notify.PlatformFCM, Status: postgres.DeliveryFailed}, nil)
+	deliveries.On("RecordDeliveryAttempt", mock.Anything, "d1", postgres.DeliverySent, (*string)(nil)).
+		Return(postgres.Delivery{UID: "d1", Status: postgres.DeliverySent, Attempts: 2}, nil)
+	log := NewDeliveryLog(deliveries)
+	notifier := &recordingNotifier{}
+	log.Notifier(notifier)
+
+	out, err := log.Replay(context.Background(), "d1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, out.Attempts)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notify.Message{To: "sam@example.com", Subject: "Digest"}, notifier.sent[0])
+
+	_, err = log.Replay(context.Background(), "d2")
+	assert.ErrorIs(t, err, ErrDeliveryNotFailed)
+	_, err = log.Replay(context.Background(), "d3")
+	assert.ErrorIs(t, err, ErrChannelUnavailable)
+}
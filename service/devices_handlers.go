@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+type deviceDAO interface {
+	RegisterDevice(ctx context.Context, d dao.Device) (dao.Device, error)
+	ListDevicesByUserUID(ctx context.Context, userUID string) ([]dao.Device, error)
+	ListDevicesByHouseholdUID(ctx context.Context, householdUID string) ([]dao.Device, error)
+	DeleteDevice(ctx context.Context, uid string) error
+}
+
+type DeviceHandlers struct{ dao deviceDAO }
+
+func NewDevices(dao deviceDAO) http.Handler {
+	h := &DeviceHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.register)
+	r.Get("/", h.list)
+	r.Delete("/{uid}", h.delete)
+	return r
+}
+
+// register adds a device's push token. Apps should call it on every launch;
+// registering a known token just updates its owner and name.
+func (h *DeviceHandlers) register(w http.ResponseWriter, r *http.Request) {
+	var d dao.Device
+	if json.NewDecoder(r.Body).Decode(&d) != nil || d.UserUID == "" || d.Token == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if d.Platform != notify.PlatformAPNs && d.Platform != notify.PlatformFCM && d.Platform != notify.PlatformSlack {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "platform must be apns, fcm or slack"})
+		return
+	}
+	out, err := h.dao.RegisterDevice(r.Context(), d)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *DeviceHandlers) list(w http.ResponseWriter, r *http.Request) {
+	userUID := r.URL.Query().Get("user_uid")
+	if userUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.ListDevicesByUserUID(r.Context(), userUID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *DeviceHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteDevice(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDevicesRegisterValidates(t *testing.T) {
+	mockDAO := mocks.NewMockdeviceDAO(t)
+	mockDAO.On("RegisterDevice", mock.Anything, postgres.Device{UserUID: "user-1", Platform: "apns", Token: "abc", Name: "Mia's iPhone"}).
+		Return(postgres.Device{UID: "d1", UserUID: "user-1", Platform: "apns", Token: "abc"}, nil)
+	handler := NewDevices(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/",
+		strings.NewReader(`{"user_uid": "user-1", "platform": "apns", "token": "abc", "name": "Mia's iPhone"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"d1"`)
+
+	mockDAO.On("RegisterDevice", mock.Anything, postgres.Device{UserUID: "user-1", Platform: "slack", Token: "U0123ABCD"}).
+		Return(postgres.Device{UID: "d2", UserUID: "user-1", Platform: "slack", Token: "U0123ABCD"}, nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/",
+		strings.NewReader(`{"user_uid": "user-1", "platform": "slack", "token": "U0123ABCD"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	for _, body := range []string{
+		`{"platform": "apns", "token": "abc"}`,
+		`{"user_uid": "user-1", "platform": "apns"}`,
+		`{"user_uid": "user-1", "platform": "sms", "token": "abc"}`,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestDevicesListAndDelete(t *testing.T) {
+	mockDAO := mocks.NewMockdeviceDAO(t)
+	mockDAO.On("ListDevicesByUserUID", mock.Anything, "user-1").Return([]postgres.Device{{UID: "d1"}}, nil)
+	mockDAO.On("DeleteDevice", mock.Anything, "d1").Return(nil)
+	handler := NewDevices(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?user_uid=user-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"d1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/d1", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
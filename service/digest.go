@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/mail"
+	"strings"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+	// digestPageSize is how many users' preferences are read per query.
+	digestPageSize = 100
+	// digestSectionLimit caps the todos listed in each section.
+	digestSectionLimit = 20
+)
+
+// digestTodoColumns lets digests also filter todos on when they fell due.
+var digestTodoColumns = func() FilterColumns {
+	columns := maps.Clone(TodoFilters.Filters)
+	columns["due_date"] = rangeOps
+	return columns
+}()
+
+// Digests emails users whose notification preferences ask for one a summary
+// of their household's todos: what is overdue, what is coming up and what
+// was done since the last digest.
+type Digests struct {
+	todos    todoDAO
+	prefs    preferencesDAO
+	users    userDAO
+	notifier notify.Notifier
+	hour     int
+}
+
+// NewDigests sends digests at hour o'clock UTC: daily ones every day and
+// weekly ones on Mondays.
+func NewDigests(todos todoDAO, prefs preferencesDAO, users userDAO, notifier notify.Notifier, hour int) *Digests {
+	return &Digests{todos: todos, prefs: prefs, users: users, notifier: notifier, hour: hour}
+}
+
+// Job sends digests daily at the configured hour. Digests are emails, so a
+// failed run isn't retried.
+func (d *Digests) Job() Job {
+	return Job{
+		Name:     "digests",
+		Schedule: dailyAt(d.hour),
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return d.SendDue(ctx, run.RunAt)
+		},
+	}
+}
+
+// SendDue sends the digests due at now. Failures for one user are logged
+// and don't stop the others; SendDue then reports how many failed.
+func (d *Digests) SendDue(ctx context.Context, now time.Time) error {
+	periods := map[string]time.Duration{digestDaily: 24 * time.Hour}
+	if now.Weekday() == time.Monday {
+		periods[digestWeekly] = 7 * 24 * time.Hour
+	}
+
+	failed := 0
+	for offset := 0; ; offset += digestPageSize {
+		subs, err := d.prefs.ListPreferences(ctx, dao.ListOptions{
+			Limit:       digestPageSize,
+			Offset:      offset,
+			SortBy:      "specifier",
+			SortDir:     "ASC",
+			WhereClause: "WHERE key = $1",
+			WhereArgs:   []any{NotificationPreferencesKey},
+		})
+		if err != nil {
+			return fmt.Errorf("listing digest subscriptions: %w", err)
+		}
+		for _, sub := range subs {
+			prefs, err := parseNotificationPreferences(sub.Specifier, sub.Data)
+			if err != nil {
+				slog.Error("Invalid notification preferences", "user_uid", sub.Specifier, "error", err)
+				continue
+			}
+			for frequency, period := range periods {
+				if !prefs.SendsDigest(frequency) {
+					continue
+				}
+				if err := d.send(ctx, sub.Specifier, frequency, now, period); err != nil {
+					slog.Error("Failed to send digest", "user_uid", sub.Specifier, "error", err)
+					failed++
+				}
+			}
+		}
+		if len(subs) < digestPageSize {
+			break
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d digests failed to send", failed)
+	}
+	return nil
+}
+
+func (d *Digests) send(ctx context.Context, userUID, frequency string, now time.Time, period time.Duration) error {
+	user, err := d.users.GetUser(ctx, userUID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+	msg, ok, err := d.build(ctx, user, frequency, now, period)
+	if err != nil || !ok {
+		return err
+	}
+	return d.notifier.Send(ctx, msg)
+}
+
+// build writes user's digest, or reports false when it would be empty.
+func (d *Digests) build(ctx context.Context, user dao.Users, frequency string, now time.Time, period time.Duration) (notify.Message, bool, error) {
+	owner := Filter{Column: "user_uid", Op: OpEq, Value: user.UID}
+	if user.HouseholdUID != nil && *user.HouseholdUID != "" {
+		owner = Filter{Column: "household_uid", Op: OpEq, Value: *user.HouseholdUID}
+	}
+	list := func(sortBy, sortDir string, filters ...Filter) ([]dao.Todo, error) {
+		whereClause, whereArgs := BuildWhereClause(append(filters, owner), digestTodoColumns)
+		return d.todos.ListTodos(ctx, dao.ListOptions{
+			Limit:       digestSectionLimit,
+			SortBy:      sortBy,
+			SortDir:     sortDir,
+			WhereClause: whereClause,
+			WhereArgs:   whereArgs,
+		})
+	}
+
+	pending := Filter{Column: "marked_complete", Op: OpNull}
+	overdue, err := list("due_date", "ASC", pending, Filter{Column: "due_date", Op: OpLt, Value: now.Format(time.RFC3339)})
+	if err != nil {
+		return notify.Message{}, false, fmt.Errorf("overdue todos: %w", err)
+	}
+	// due_date can only take one condition, so the upper bound is applied here.
+	upcoming, err := list("due_date", "ASC", pending, Filter{Column: "due_date", Op: OpGe, Value: now.Format(time.RFC3339)})
+	if err != nil {
+		return notify.Message{}, false, fmt.Errorf("upcoming todos: %w", err)
+	}
+	for i, t := range upcoming {
+		if t.DueDate.After(now.Add(period)) {
+			upcoming = upcoming[:i]
+			break
+		}
+	}
+	done, err := list("updated_at", "DESC", Filter{Column: "marked_complete", Op: OpGe, Value: now.Add(-period).Format(time.RFC3339)})
+	if err != nil {
+		return notify.Message{}, false, fmt.Errorf("completed todos: %w", err)
+	}
+	if len(overdue)+len(upcoming)+len(done) == 0 {
+		return notify.Message{}, false, nil
+	}
+
+	span := "day"
+	if frequency == digestWeekly {
+		span = "week"
+	}
+	name := user.Name
+	if name == "" {
+		name = "there"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nHere is your %s digest for %s.\n", name, frequency, now.Format("Monday 2 January"))
+	section := func(title string, todos []dao.Todo, when func(dao.Todo) string) {
+		if len(todos) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s\n", title)
+		for _, t := range todos {
+			fmt.Fprintf(&b, "- %s%s\n", t.Title, when(t))
+		}
+	}
+	due := func(t dao.Todo) string { return " (due " + t.DueDate.Format("Mon 2 Jan") + ")" }
+	section("Overdue", overdue, due)
+	section("Due in the next "+span, upcoming, due)
+	section("Done in the last "+span, done, func(dao.Todo) string { return "" })
+	b.WriteString("\nTo stop these emails, set your digest frequency to off in your notification preferences.\n")
+
+	return notify.Message{
+		To:      (&mail.Address{Name: user.Name, Address: user.Email}).String(),
+		Subject: fmt.Sprintf("Your %s household digest", frequency),
+		Body:    b.String(),
+	}, true, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct{ sent []notify.Message }
+
+func (n *recordingNotifier) Send(_ context.Context, m notify.Message) error {
+	n.sent = append(n.sent, m)
+	return nil
+}
+
+func TestDigestsJobSchedule(t *testing.T) {
+	schedule := NewDigests(nil, nil, nil, nil, 7).Job().Schedule
+	morning := time.Date(2025, 8, 18, 6, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 8, 18, 7, 0, 0, 0, time.UTC), schedule.Next(morning))
+	assert.Equal(t, time.Date(2025, 8, 19, 7, 0, 0, 0, time.UTC), schedule.Next(morning.Add(30*time.Minute)))
+}
+
+func TestDigestsSendDue(t *testing.T) {
+	monday := time.Date(2025, 8, 18, 7, 0, 0, 0, time.UTC)
+	house := "house-1"
+	yesterday := monday.AddDate(0, 0, -1)
+	tomorrow := monday.AddDate(0, 0, 1)
+	inFiveDays := monday.AddDate(0, 0, 5)
+
+	prefs := &MockPreferencesDAO{}
+	prefs.On("ListPreferences", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return o.WhereClause == "WHERE key = $1" && o.WhereArgs[0] == NotificationPreferencesKey
+	})).Return([]dao.Preferences{
+		{Key: NotificationPreferencesKey, Specifier: "user-1", Data: `{"digest_frequency": "daily"}`},
+		{Key: NotificationPreferencesKey, Specifier: "user-2", Data: `{"digest_frequency": "weekly"}`},
+		{Key: NotificationPreferencesKey, Specifier: "user-3", Data: `{"digest_frequency": "off"}`},
+		{Key: NotificationPreferencesKey, Specifier: "user-4", Data: `{"digest_frequency": "daily"}`},
+		{Key: NotificationPreferencesKey, Specifier: "user-5", Data: `{"digest_frequency": "daily", "channels": {"email": false}}`},
+		{Key: NotificationPreferencesKey, Specifier: "user-6", Data: `not json`},
+	}, nil)
+	users := &MockUserDAO{}
+	users.On("GetUser", mock.Anything, "user-1").Return(dao.Users{UID: "user-1", Name: "Mia", Email: "mia@example.com", HouseholdUID: &house}, nil)
+	users.On("GetUser", mock.Anything, "user-2").Return(dao.Users{UID: "user-2", Name: "Sam", Email: "sam@example.com", HouseholdUID: &house}, nil)
+	users.On("GetUser", mock.Anything, "user-4").Return(dao.Users{UID: "user-4", Name: "No Email"}, nil)
+
+	todos := &MockTodoDAO{}
+	hasArg := func(arg string) func(dao.ListOptions) bool {
+		return func(o dao.ListOptions) bool {
+			for _, a := range o.WhereArgs {
+				if a == arg {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	// Mia's overdue, upcoming and done queries, in that order; Sam's get the
+	// catch-all.
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(hasArg(monday.Format(time.RFC3339)))).Return([]dao.Todo{}, nil).Once()
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(hasArg(monday.Format(time.RFC3339)))).
+		Return([]dao.Todo{{Title: "Renew passport", DueDate: &tomorrow}, {Title: "Book MOT", DueDate: &inFiveDays}}, nil).Once()
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(hasArg(yesterday.Format(time.RFC3339)))).
+		Return([]dao.Todo{{Title: "Fix the tap"}}, nil).Once()
+	todos.On("ListTodos", mock.Anything, mock.Anything).
+		Return([]dao.Todo{{Title: "Take the bins out", DueDate: &yesterday}}, nil)
+
+	notifier := &recordingNotifier{}
+	NewDigests(todos, prefs, users, notifier, 7).SendDue(t.Context(), monday)
+
+	require.Len(t, notifier.sent, 2)
+	daily := notifier.sent[0]
+	assert.Equal(t, `"Mia" <mia@example.com>`, daily.To)
+	assert.Equal(t, "Your daily household digest", daily.Subject)
+	assert.Contains(t, daily.Body, "Here is your daily digest for Monday 18 August.")
+	assert.Contains(t, daily.Body, "Due in the next day\n- Renew passport (due Tue 19 Aug)\n\n")
+	assert.Contains(t, daily.Body, "Done in the last day\n- Fix the tap\n")
+	assert.NotContains(t, daily.Body, "Book MOT")
+
+	weekly := notifier.sent[1]
+	assert.Equal(t, "Your weekly household digest", weekly.Subject)
+	assert.Contains(t, weekly.Body, "Overdue\n- Take the bins out (due Sun 17 Aug)\n")
+	assert.Contains(t, weekly.Body, "Due in the next week\n- Take the bins out")
+
+	// Weekly digests only go out on Mondays.
+	notifier.sent = nil
+	NewDigests(todos, prefs, users, notifier, 7).SendDue(t.Context(), tomorrow)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "Your daily household digest", notifier.sent[0].Subject)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// RecipeOverrides are the fields a duplicate of a recipe can change from
+// the original. Anything left out is copied.
+type RecipeOverrides struct {
+	Title        *string   `json:"title"`
+	Data         *string   `json:"data"`
+	Genre        *string   `json:"genre"`
+	GroceryList  *string   `json:"grocery_list"`
+	PrepTime     *int      `json:"prep_time"`
+	CookTime     *int      `json:"cook_time"`
+	Servings     *int      `json:"servings"`
+	Difficulty   *string   `json:"difficulty"`
+	Tags         *[]string `json:"tags"`
+	UserUID      *string   `json:"user_uid"`
+	HouseholdUID *string   `json:"household_uid"`
+}
+
+// NoteOverrides are the fields a duplicate of a note can change from the
+// original. Anything left out is copied.
+type NoteOverrides struct {
+	Key          *string   `json:"key"`
+	Data         *string   `json:"data"`
+	Tags         *[]string `json:"tags"`
+	Visibility   *string   `json:"visibility"`
+	UserUID      *string   `json:"user_uid"`
+	HouseholdUID *string   `json:"household_uid"`
+}
+
+// copyName is what a duplicate is called when it isn't given a name.
+func copyName(name string) string {
+	return name + " (copy)"
+}
+
+// duplicateRecipe is a new recipe copied from orig with o applied. It
+// doesn't keep orig's external_url, so refreshing or re-saving the page
+// leaves the copy's changes alone, and starts without ratings or a photo.
+func duplicateRecipe(orig dao.Recipes, o RecipeOverrides) dao.Recipes {
+	out := dao.Recipes{
+		ID:           uuid.NewString(),
+		Title:        copyName(orig.Title),
+		Data:         orig.Data,
+		Genre:        orig.Genre,
+		GroceryList:  orig.GroceryList,
+		PrepTime:     orig.PrepTime,
+		CookTime:     orig.CookTime,
+		TotalTime:    orig.TotalTime,
+		Servings:     orig.Servings,
+		Difficulty:   orig.Difficulty,
+		Tags:         orig.Tags,
+		UserUID:      orig.UserUID,
+		HouseholdUID: orig.HouseholdUID,
+	}
+	if o.Title != nil && strings.TrimSpace(*o.Title) != "" {
+		out.Title = strings.TrimSpace(*o.Title)
+	}
+	if o.Data != nil {
+		out.Data = *o.Data
+	}
+	for _, f := range []struct{ dst, src **string }{
+		{&out.Genre, &o.Genre}, {&out.GroceryList, &o.GroceryList}, {&out.Difficulty, &o.Difficulty},
+		{&out.UserUID, &o.UserUID}, {&out.HouseholdUID, &o.HouseholdUID},
+	} {
+		if *f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	for _, f := range []struct{ dst, src **int }{
+		{&out.PrepTime, &o.PrepTime}, {&out.CookTime, &o.CookTime}, {&out.Servings, &o.Servings},
+	} {
+		if *f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if (o.PrepTime != nil || o.CookTime != nil) && out.PrepTime != nil && out.CookTime != nil {
+		total := *out.PrepTime + *out.CookTime
+		out.TotalTime = &total
+	}
+	if o.Tags != nil {
+		out.Tags = *o.Tags
+	}
+	return out
+}
+
+// duplicateNote is a new note copied from orig with o applied. The copy
+// starts unpinned.
+func duplicateNote(orig dao.Notes, o NoteOverrides) dao.Notes {
+	out := dao.Notes{
+		ID:           uuid.NewString(),
+		Key:          copyName(orig.Key),
+		UserUID:      orig.UserUID,
+		HouseholdUID: orig.HouseholdUID,
+		Data:         orig.Data,
+		Tags:         orig.Tags,
+		Visibility:   orig.Visibility,
+	}
+	if o.Key != nil && strings.TrimSpace(*o.Key) != "" {
+		out.Key = strings.TrimSpace(*o.Key)
+	}
+	if o.Data != nil {
+		out.Data = *o.Data
+	}
+	if o.Tags != nil {
+		out.Tags = *o.Tags
+	}
+	if o.Visibility != nil {
+		out.Visibility = *o.Visibility
+	}
+	if o.UserUID != nil {
+		out.UserUID = o.UserUID
+	}
+	if o.HouseholdUID != nil {
+		out.HouseholdUID = o.HouseholdUID
+	}
+	return out
+}
+
+// decodeOverrides reads the optional overrides sent to a duplicate route.
+// An empty body copies everything. ok is false once it has answered.
+func decodeOverrides(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// duplicate saves a copy of a recipe, with any fields in the body changed,
+// leaving the original as it is. With an API key the copy is the key's
+// user's.
+func (h *RecipesHandlers) duplicate(w http.ResponseWriter, r *http.Request) {
+	var o RecipeOverrides
+	if !decodeOverrides(w, r, &o) {
+		return
+	}
+	orig, err := h.dao.GetRecipes(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok && id.UserUID != "" {
+		o.UserUID = &id.UserUID
+	}
+	out, err := h.dao.CreateRecipes(r.Context(), duplicateRecipe(orig, o))
+	if quotaExceeded(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(withPhotoURLs(out))
+}
+
+// duplicate saves a copy of a note, with any fields in the body changed,
+// leaving the original as it is. Only notes the caller can read can be
+// copied, and with an API key the copy is the key's user's.
+func (h *NotesHandlers) duplicate(w http.ResponseWriter, r *http.Request) {
+	var o NoteOverrides
+	if !decodeOverrides(w, r, &o) {
+		return
+	}
+	if o.Visibility != nil && !validNoteVisibility(*o.Visibility) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	orig, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !noteAccessible(r.Context(), orig)) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok && id.UserUID != "" {
+		o.UserUID = &id.UserUID
+	}
+	n := duplicateNote(orig, o)
+	if !checkData(w, r, h.schemas, dao.DataSchemaNotes, n.Key, n.Data) {
+		return
+	}
+	out, err := h.dao.CreateNotes(r.Context(), n)
+	if quotaExceeded(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
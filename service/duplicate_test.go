@@ -0,0 +1,112 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDuplicateRecipe(t *testing.T) {
+	orig := postgres.Recipes{
+		ID: "r1", Title: "Chilli", ExternalURL: strPtr("https://example.com/chilli"), Data: "Brown the mince",
+		PrepTime: intPtr(10), CookTime: intPtr(60), TotalTime: intPtr(70), Servings: intPtr(4),
+		Tags: []string{"spicy"}, UserUID: strPtr("user-1"), HouseholdUID: strPtr("house-1"), RatingCount: 2,
+	}
+
+	copied := duplicateRecipe(orig, RecipeOverrides{})
+	assert.NotEqual(t, "r1", copied.ID)
+	assert.Equal(t, "Chilli (copy)", copied.Title)
+	assert.Nil(t, copied.ExternalURL)
+	assert.Equal(t, 0, copied.RatingCount)
+	assert.Equal(t, orig.Tags, copied.Tags)
+
+	copied = duplicateRecipe(orig, RecipeOverrides{Title: strPtr(" Veggie chilli "), CookTime: intPtr(40), Tags: &[]string{}})
+	assert.Equal(t, "Veggie chilli", copied.Title)
+	assert.Equal(t, 50, *copied.TotalTime)
+	assert.Empty(t, copied.Tags)
+	assert.Equal(t, 60, *orig.CookTime)
+}
+
+func TestDuplicateNote(t *testing.T) {
+	orig := postgres.Notes{ID: "n1", Key: "Packing list", Data: "Passports", Visibility: postgres.NoteVisibilityPrivate, Pinned: true, UserUID: strPtr("user-1")}
+
+	copied := duplicateNote(orig, NoteOverrides{})
+	assert.Equal(t, "Packing list (copy)", copied.Key)
+	assert.Equal(t, postgres.NoteVisibilityPrivate, copied.Visibility)
+	assert.False(t, copied.Pinned)
+
+	copied = duplicateNote(orig, NoteOverrides{Key: strPtr("Ski packing list"), UserUID: strPtr("user-2")})
+	assert.Equal(t, "Ski packing list", copied.Key)
+	assert.Equal(t, "user-2", *copied.UserUID)
+}
+
+func TestRecipeDuplicateRoute(t *testing.T) {
+	mockDAO := mocks.NewMockrecipesDAO(t)
+	mockDAO.On("GetRecipes", mock.Anything, "r1").Return(postgres.Recipes{ID: "r1", Title: "Chilli", Data: "Brown the mince"}, nil)
+	mockDAO.On("GetRecipes", mock.Anything, "missing").Return(postgres.Recipes{}, pgx.ErrNoRows)
+	mockDAO.On("CreateRecipes", mock.Anything, mock.MatchedBy(func(r postgres.Recipes) bool {
+		return r.ID != "r1" && r.Title == "Chilli (copy)" && *r.Servings == 2
+	})).Return(postgres.Recipes{ID: "r2", Title: "Chilli (copy)"}, nil)
+	handler := NewRecipes(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/r1/duplicate", strings.NewReader(`{"servings": 2}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"r2"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/missing/duplicate", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestNoteDuplicateRoute(t *testing.T) {
+	mockDAO := mocks.NewMocknotesDAO(t)
+	mockDAO.On("GetNotes", mock.Anything, "n1").
+		Return(postgres.Notes{ID: "n1", Key: "Packing list", Data: "Passports", UserUID: strPtr("user-1"), HouseholdUID: strPtr("house-1"), Visibility: postgres.NoteVisibilityHousehold}, nil)
+	mockDAO.On("GetNotes", mock.Anything, "private").
+		Return(postgres.Notes{ID: "private", Key: "Diary", UserUID: strPtr("user-2"), Visibility: postgres.NoteVisibilityPrivate}, nil)
+	mockDAO.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool {
+		return n.Key == "Packing list (copy)" && n.Data == "Passports" && *n.UserUID == "user-3"
+	})).Return(postgres.Notes{ID: "n2", Key: "Packing list (copy)"}, nil)
+	handler := NewNotes(mockDAO)
+
+	serve := func(target, body string, identity bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", target, strings.NewReader(body))
+		if identity {
+			req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-3", HouseholdUID: "house-1"}))
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve("/n1/duplicate", "", true)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"n2"`)
+	assert.Equal(t, http.StatusNotFound, serve("/private/duplicate", "", true).Code)
+	assert.Equal(t, http.StatusBadRequest, serve("/n1/duplicate", `{"visibility": "public"}`, false).Code)
+}
+
+func TestMCPHandlers_DuplicateRecipe(t *testing.T) {
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r1").Return(postgres.Recipes{ID: "r1", Title: "Chilli", Tags: []string{"spicy"}}, nil)
+	mockRecipesDAO.On("CreateRecipes", mock.Anything, mock.MatchedBy(func(r postgres.Recipes) bool {
+		return r.Title == "Mild chilli" && len(r.Tags) == 2 && *r.UserUID == "user-1" && *r.HouseholdUID == "house-1"
+	})).Return(postgres.Recipes{ID: "r2", Title: "Mild chilli"}, nil)
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{})
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "duplicate_recipe", map[string]any{"recipe_id": "r1", "title": "Mild chilli", "tags": "mild, family"}), &body)
+	assert.Equal(t, "Copied Chilli to Mild chilli", body["summary"])
+	assert.Equal(t, "r1", body["original_id"])
+
+	assert.True(t, h.callTool(ctx, "duplicate_recipe", map[string]any{}).IsError)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type entityLockDAO interface {
+	AcquireEntityLock(ctx context.Context, l dao.EntityLock, ttl time.Duration) (dao.EntityLock, error)
+	GetEntityLock(ctx context.Context, entity, entityID string) (dao.EntityLock, error)
+	ReleaseEntityLock(ctx context.Context, entity, entityID string, apiKeyUID *string) error
+}
+
+const (
+	// defaultLockTTL is how long a lock is held when no ttl_seconds is
+	// given. Editors take it again to keep it while they are still editing.
+	defaultLockTTL = 2 * time.Minute
+	// maxLockTTL bounds a lock, so one left behind by a closed editor soon
+	// lapses.
+	maxLockTTL = 15 * time.Minute
+	// lockEntityNotes is the entity of note locks, as in change events.
+	lockEntityNotes = "notes"
+)
+
+// lockKey is the API key a lock taken with ctx is held by: the caller's, or
+// nil for an operator.
+func lockKey(ctx context.Context) *string {
+	if id, ok := IdentityFromContext(ctx); ok && id.APIKeyUID != "" {
+		return &id.APIKeyUID
+	}
+	return nil
+}
+
+func sameLockKey(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// currentLock returns the entity's lock, whoever holds it, or nil. Locks
+// are advisory, so one that can't be read is treated as not held.
+func currentLock(ctx context.Context, d entityLockDAO, entity, id string) *dao.EntityLock {
+	if d == nil {
+		return nil
+	}
+	l, err := d.GetEntityLock(ctx, entity, id)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("Failed to read entity lock", "entity", entity, "entity_id", id, "error", err)
+		}
+		return nil
+	}
+	return &l
+}
+
+// lockedByOther returns the entity's lock if a key other than the caller's
+// holds it, i.e. if the caller mustn't change the entity now.
+func lockedByOther(ctx context.Context, d entityLockDAO, entity, id string) *dao.EntityLock {
+	l := currentLock(ctx, d, entity, id)
+	if l == nil || sameLockKey(l.APIKeyUID, lockKey(ctx)) {
+		return nil
+	}
+	return l
+}
+
+// lockedMessage says who holds l on the noun, e.g. "Sam (Dashboard) is
+// editing this note until 14:05 UTC".
+func lockedMessage(l dao.EntityLock, noun string) string {
+	holder := "An operator"
+	if l.Holder != "" {
+		holder = l.Holder
+		if l.KeyName != "" {
+			holder += " (" + l.KeyName + ")"
+		}
+	}
+	return fmt.Sprintf("%s is editing this %s until %s", holder, noun, l.ExpiresAt.UTC().Format("15:04 UTC"))
+}
+
+// writeLocked refuses a request with 423 Locked, the lock in the way and
+// who holds it.
+func writeLocked(w http.ResponseWriter, l dao.EntityLock, noun string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusLocked)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": lockedMessage(l, noun), "lock": l})
+}
+
+// WithNoteLocks enables PUT and DELETE /notes/{id}/lock, shows a note's
+// lock in GET /notes/{id}, and refuses to update or delete a note while a
+// key other than the caller's holds its lock.
+func WithNoteLocks(d entityLockDAO) NotesOption {
+	return func(h *NotesHandlers) {
+		h.locks = d
+	}
+}
+
+type LockRequest struct {
+	// TTLSeconds defaults to defaultLockTTL.
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+// lock takes the note's lock for the caller's key, or extends the one it
+// holds. 423 with the lock when another key holds it.
+func (h *NotesHandlers) lock(w http.ResponseWriter, r *http.Request) {
+	var req LockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); (err != nil && !errors.Is(err, io.EOF)) ||
+		req.TTLSeconds < 0 || time.Duration(req.TTLSeconds)*time.Second > maxLockTTL {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("ttl_seconds must be between 1 and %d", int(maxLockTTL.Seconds()))})
+		return
+	}
+	if !h.callerCanAccess(r) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	ttl := defaultLockTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	want := dao.EntityLock{Entity: lockEntityNotes, EntityID: chi.URLParam(r, "id"), APIKeyUID: lockKey(r.Context())}
+	if id, ok := IdentityFromContext(r.Context()); ok && id.UserUID != "" {
+		want.UserUID = &id.UserUID
+	}
+	l, err := h.locks.AcquireEntityLock(r.Context(), want, ttl)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !sameLockKey(l.APIKeyUID, want.APIKeyUID) {
+		writeLocked(w, l, "note")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(l)
+}
+
+// unlock releases the caller's lock on the note. Releasing a lock that has
+// lapsed, or was never taken, succeeds too.
+func (h *NotesHandlers) unlock(w http.ResponseWriter, r *http.Request) {
+	if !h.callerCanAccess(r) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if l := lockedByOther(r.Context(), h.locks, lockEntityNotes, id); l != nil {
+		writeLocked(w, *l, "note")
+		return
+	}
+	if h.locks.ReleaseEntityLock(r.Context(), lockEntityNotes, id, lockKey(r.Context())) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// refuseLocked writes 423 and returns true if the note in the URL is locked
+// by a key other than the caller's.
+func (h *NotesHandlers) refuseLocked(w http.ResponseWriter, r *http.Request) bool {
+	l := lockedByOther(r.Context(), h.locks, lockEntityNotes, chi.URLParam(r, "id"))
+	if l == nil {
+		return false
+	}
+	writeLocked(w, *l, "note")
+	return true
+}
+
+// WithLocks makes the note tools respect note locks: recall_note reports a
+// note's lock, and save_note and delete_note refuse to change a note locked
+// by another key.
+func WithLocks(d entityLockDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.locksDAO = d
+	}
+}
+
+// lockedToolError is the tool result for a change refused because of l.
+func lockedToolError(l dao.EntityLock, noun string) mcp.CallToolResult {
+	return toolError("%s. Don't overwrite it; tell the user, or try again once they are done.", lockedMessage(l, noun))
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dashboardContext is Sam editing in a dashboard with its own key, in the
+// household whose assistant uses key-1.
+func dashboardContext() context.Context {
+	return WithIdentity(context.Background(), Identity{APIKeyUID: "key-2", UserUID: "user-2", HouseholdUID: "house-1", Scopes: []string{ScopeAll}})
+}
+
+var (
+	lockedNote    = postgres.Notes{ID: "note-1", Key: "meal_plan", Data: "Monday: tacos", UserUID: strPtr("user-1"), HouseholdUID: strPtr("house-1"), Visibility: postgres.NoteVisibilityHousehold, Tags: []string{"food"}}
+	dashboardLock = postgres.EntityLock{Entity: "notes", EntityID: "note-1", APIKeyUID: strPtr("key-2"), UserUID: strPtr("user-2"), Holder: "Sam", KeyName: "Dashboard",
+		ExpiresAt: time.Date(2025, 9, 26, 14, 5, 0, 0, time.UTC)}
+)
+
+func TestNoteLocks(t *testing.T) {
+	notes := mocks.NewMocknotesDAO(t)
+	notes.On("GetNotes", mock.Anything, "note-1").Return(lockedNote, nil)
+	locks := mocks.NewMockentityLockDAO(t)
+	locks.On("GetEntityLock", mock.Anything, "notes", "note-1").Return(dashboardLock, nil)
+	handler := NewNotes(notes, WithNoteLocks(locks))
+	serve := func(ctx context.Context, method, target, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx))
+		return rr
+	}
+
+	locks.On("AcquireEntityLock", mock.Anything, mock.MatchedBy(func(l postgres.EntityLock) bool {
+		return l.EntityID == "note-1" && *l.APIKeyUID == "key-2" && *l.UserUID == "user-2"
+	}), 5*time.Minute).Return(dashboardLock, nil)
+	rr := serve(dashboardContext(), "PUT", "/note-1/lock", `{"ttl_seconds": 300}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"holder":"Sam"`)
+
+	// The holder edits as usual.
+	notes.On("UpdateNotes", mock.Anything, "note-1", mock.Anything).Return(lockedNote, nil)
+	assert.Equal(t, http.StatusOK, serve(dashboardContext(), "PUT", "/note-1", `{"key": "meal_plan", "data": "Monday: soup"}`).Code)
+
+	// The assistant can see the lock, but not take it, change the note or
+	// release the lock.
+	rr = serve(identityContext("user-1", "house-1"), "GET", "/note-1", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got struct {
+		postgres.Notes
+		Lock *postgres.EntityLock `json:"lock"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, "meal_plan", got.Key)
+	assert.Equal(t, "Sam", got.Lock.Holder)
+
+	locks.On("AcquireEntityLock", mock.Anything, mock.MatchedBy(func(l postgres.EntityLock) bool { return *l.APIKeyUID == "key-1" }), defaultLockTTL).Return(dashboardLock, nil)
+	for _, req := range []struct{ method, target, body string }{
+		{"PUT", "/note-1/lock", ""},
+		{"PUT", "/note-1", `{"key": "meal_plan", "data": "Monday: pizza"}`},
+		{"DELETE", "/note-1", ""},
+		{"DELETE", "/note-1/lock", ""},
+	} {
+		rr := serve(identityContext("user-1", "house-1"), req.method, req.target, req.body)
+		assert.Equal(t, http.StatusLocked, rr.Code, req.method+" "+req.target)
+		assert.Contains(t, rr.Body.String(), "Sam (Dashboard) is editing this note until 14:05 UTC", req.method+" "+req.target)
+	}
+
+	locks.On("ReleaseEntityLock", mock.Anything, "notes", "note-1", strPtr("key-2")).Return(nil)
+	assert.Equal(t, http.StatusNoContent, serve(dashboardContext(), "DELETE", "/note-1/lock", "").Code)
+
+	rr = serve(dashboardContext(), "PUT", "/note-1/lock", `{"ttl_seconds": 3600}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "ttl_seconds must be between 1 and 900")
+	notes.AssertNumberOfCalls(t, "UpdateNotes", 1)
+}
+
+func TestNoteLocksPublishEvents(t *testing.T) {
+	hub := NewEventHub()
+	events, _ := hub.Subscribe("house-1")
+	r := chi.NewRouter()
+	r.Use(hub.Track)
+	r.Put("/notes/{id}/lock", func(w http.ResponseWriter, r *http.Request) {})
+	r.Delete("/notes/{id}/lock", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/notes/note-1/lock", nil).WithContext(dashboardContext()))
+	e := nextEvent(t, events)
+	assert.Equal(t, ChangeEvent{Entity: "notes", Action: "locked", ID: "note-1", HouseholdUID: "house-1", At: e.At}, e)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/notes/note-1/lock", nil).WithContext(dashboardContext()))
+	assert.Equal(t, "unlocked", nextEvent(t, events).Action)
+}
+
+func TestMCPHandlers_NoteLocks(t *testing.T) {
+	notes := &MockNotesDAO{}
+	notes.On("GetNotes", mock.Anything, "note-1").Return(lockedNote, nil)
+	locks := mocks.NewMockentityLockDAO(t)
+	locks.On("GetEntityLock", mock.Anything, "notes", "note-1").Return(dashboardLock, nil).Times(3)
+	h := NewMCP(&MockTodoDAO{}, notes, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithLocks(locks))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "recall_note", map[string]any{"note_id": "note-1"}), &body)
+	assert.Equal(t, "Sam", body["lock"].(map[string]any)["holder"])
+
+	for _, call := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"save_note", map[string]any{"note_id": "note-1", "key": "meal_plan", "data": "Monday: pizza"}},
+		{"delete_note", map[string]any{"note_id": "note-1", "confirm": true}},
+	} {
+		body = nil
+		decodeToolResult(t, h.callTool(ctx, call.tool, call.args), &body)
+		assert.Contains(t, body["error"], "Sam (Dashboard) is editing this note until 14:05 UTC", call.tool)
+	}
+
+	// Once Sam is done, the rewrite keeps the note's owner and tags.
+	locks.On("GetEntityLock", mock.Anything, "notes", "note-1").Return(postgres.EntityLock{}, pgx.ErrNoRows)
+	notes.On("UpdateNotes", mock.Anything, "note-1", mock.MatchedBy(func(n postgres.Notes) bool {
+		return n.Data == "Monday: pizza" && *n.UserUID == "user-1" && *n.HouseholdUID == "house-1" && len(n.Tags) == 1
+	})).Return(lockedNote, nil)
+	body = nil
+	decodeToolResult(t, h.callTool(ctx, "save_note", map[string]any{"note_id": "note-1", "key": "meal_plan", "data": "Monday: pizza"}), &body)
+	assert.Equal(t, "Note saved", body["summary"])
+	notes.AssertExpectations(t)
+	notes.AssertNotCalled(t, "DeleteNotes", mock.Anything, mock.Anything)
+}
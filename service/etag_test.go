@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"x", W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(``, `"abc"`))
+	assert.False(t, etagMatches(`"abcd"`, `"abc"`))
+}
+
+func TestTodoConditionalGet(t *testing.T) {
+	first := time.Date(2025, 8, 20, 9, 0, 0, 0, time.UTC)
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("GetTodo", mock.Anything, "t1").Return(postgres.Todo{UID: "t1", Title: "Call plumber", UpdatedAt: first}, nil).Twice()
+	mockTodoDAO.On("GetTodo", mock.Anything, "t1").Return(postgres.Todo{UID: "t1", Title: "Call plumber", UpdatedAt: first.Add(time.Minute)}, nil).Once()
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/t1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req := httptest.NewRequest("GET", "/t1", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	// An update moves updated_at, and with it the ETag.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	assert.Contains(t, rr.Body.String(), "Call plumber")
+}
+
+func TestListETagDependsOnRepresentation(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).
+		Return([]postgres.Todo{{UID: "t1", Title: "Call plumber", Description: "Leaky tap"}}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	etags := map[string]bool{}
+	for _, target := range []string{"/", "/?format=compact", "/?fields=uid"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusOK, rr.Code, target)
+		etags[rr.Header().Get("ETag")] = true
+	}
+	assert.Len(t, etags, 3)
+}
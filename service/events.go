@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeEvent tells live clients that something in a household changed, so
+// they can refetch it. It names the entity, not its new contents.
+type ChangeEvent struct {
+	// Entity is the REST collection that changed, e.g. "todos" or "pantry".
+	Entity string `json:"entity"`
+	// Action is "created", "updated" or "deleted", or for an entity's
+	// edit lock "locked" or "unlocked".
+	Action       string    `json:"action"`
+	ID           string    `json:"id,omitempty"`
+	Tool         string    `json:"tool,omitempty"`
+	HouseholdUID string    `json:"household_uid"`
+	At           time.Time `json:"at"`
+}
+
+// eventBuffer is how many events a slow subscriber may fall behind before
+// further events are dropped for it.
+const eventBuffer = 64
+
+// eventSubscribers counts open subscriptions across hubs, i.e. live
+// dashboards and MCP resource streams, as expvar.
+var eventSubscribers = expvar.NewInt("event_subscribers")
+
+// EventHub fans change events out to the subscribers of each household.
+type EventHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan ChangeEvent]struct{}
+}
+
+func NewEventHub() *EventHub {
+	return &EventHub{subs: map[string]map[chan ChangeEvent]struct{}{}}
+}
+
+// Subscribe returns a channel of householdUID's events and a function that
+// ends the subscription. An empty householdUID subscribes to every
+// household's events.
+func (h *EventHub) Subscribe(householdUID string) (<-chan ChangeEvent, func()) {
+	ch := make(chan ChangeEvent, eventBuffer)
+	h.mu.Lock()
+	if h.subs[householdUID] == nil {
+		h.subs[householdUID] = map[chan ChangeEvent]struct{}{}
+	}
+	h.subs[householdUID][ch] = struct{}{}
+	h.mu.Unlock()
+	eventSubscribers.Add(1)
+	return ch, func() {
+		h.mu.Lock()
+		if _, ok := h.subs[householdUID][ch]; ok {
+			eventSubscribers.Add(-1)
+		}
+		delete(h.subs[householdUID], ch)
+		if len(h.subs[householdUID]) == 0 {
+			delete(h.subs, householdUID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends e to its household's subscribers and to those of every
+// household. Events without a household, such as preference changes, go
+// only to the latter.
+func (h *EventHub) Publish(e ChangeEvent) {
+	if h == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now().UTC()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	targets := []map[chan ChangeEvent]struct{}{h.subs[""]}
+	if e.HouseholdUID != "" {
+		targets = append(targets, h.subs[e.HouseholdUID])
+	}
+	for _, subs := range targets {
+		for ch := range subs {
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+	}
+}
+
+// Track publishes a change event for every successful REST write. The
+// entity is the first path segment, the ID the next one or, for creates,
+// the uid, id or key in the response. The household is the caller's, or
+// else the household_uid in the response.
+func (h *EventHub) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rec := &eventRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status >= 300 {
+			return
+		}
+		h.Publish(restChangeEvent(r, rec.body.Bytes()))
+	})
+}
+
+func restChangeEvent(r *http.Request, body []byte) ChangeEvent {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	e := ChangeEvent{Entity: segments[0], Action: "updated"}
+	if len(segments) > 1 {
+		e.ID = segments[1]
+	}
+	switch {
+	case r.Method == http.MethodDelete && len(segments) == 2:
+		e.Action = "deleted"
+	case r.Method == http.MethodPost && len(segments) == 1:
+		e.Action = "created"
+	case len(segments) == 3 && segments[2] == "lock" && r.Method == http.MethodPut:
+		e.Action = "locked"
+	case len(segments) == 3 && segments[2] == "lock" && r.Method == http.MethodDelete:
+		e.Action = "unlocked"
+	}
+
+	var created struct {
+		UID          string `json:"uid"`
+		ID           string `json:"id"`
+		Key          string `json:"key"`
+		HouseholdUID string `json:"household_uid"`
+	}
+	_ = json.Unmarshal(body, &created)
+	if e.ID == "" {
+		e.ID = firstNonEmpty(created.UID, created.ID, created.Key)
+	}
+	e.HouseholdUID = created.HouseholdUID
+	if id, ok := IdentityFromContext(r.Context()); ok && id.HouseholdUID != "" {
+		e.HouseholdUID = id.HouseholdUID
+	}
+	return e
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// maxRecordedBody bounds how much of a response Track keeps to find the
+// new entity's ID.
+const maxRecordedBody = 64 << 10
+
+type eventRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *eventRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *eventRecorder) Write(p []byte) (int, error) {
+	if room := maxRecordedBody - r.body.Len(); room > 0 {
+		r.body.Write(p[:min(len(p), room)])
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// toolEntities are the MCP tools that change data, and the REST collection
+// each one changes.
+var toolEntities = map[string]string{
+	"create_todo":                  "todos",
+	"complete_todo":                "todos",
+	"set_todo_status":              "todos",
+	"link_todos":                   "todos",
+	"log_time":                     "todos",
+	"create_project":               "projects",
+	"move_todos_to_project":        "todos",
+	"apply_template":               "todos",
+	"extract_todos":                "todos",
+	"save_note":                    "notes",
+	"delete_note":                  "notes",
+	"pin_note":                     "notes",
+	"remind_me":                    "notes",
+	"set_preference":               "preferences",
+	"set_preferences_bulk":         "preferences",
+	"save_recipe":                  "recipes",
+	"delete_recipe":                "recipes",
+	"refresh_recipe":               "recipes",
+	"rate_recipe":                  "recipes",
+	"duplicate_recipe":             "recipes",
+	"update_user_description":      "users",
+	"update_household_description": "households",
+	"set_background":               "backgrounds",
+	"add_pantry_item":              "pantry",
+	"update_pantry_item":           "pantry",
+	"remove_pantry_item":           "pantry",
+	"record_grocery_purchase":      "grocery-purchases",
+	"set_grocery_stores":           "grocery-stores",
+	"set_preferred_store":          "grocery-stores",
+	"add_to_my_day":                "my-day",
+	"remove_from_my_day":           "my-day",
+}
+
+// entityIDArgs are the tool arguments that name the row of each entity a
+// tool changes.
+var entityIDArgs = map[string]string{
+	"todos":   "todo_id",
+	"notes":   "note_id",
+	"recipes": "recipe_id",
+	"pantry":  "pantry_item_id",
+}
+
+// WithEvents publishes a change event after every successful MCP tool call
+// that changes data, and lets MCP sessions subscribe to todo and note
+// resources.
+func WithEvents(hub *EventHub) MCPOption {
+	return func(h *MCPHandlers) { h.events = hub }
+}
+
+func (h *MCPHandlers) publishToolChange(ctx context.Context, name string, arguments map[string]any) {
+	entity, ok := toolEntities[name]
+	if !ok {
+		return
+	}
+	e := ChangeEvent{Entity: entity, Action: "updated", Tool: name}
+	e.ID, _ = arguments[entityIDArgs[entity]].(string)
+	switch {
+	case strings.HasPrefix(name, "delete_"), strings.HasPrefix(name, "remove_"):
+		e.Action = "deleted"
+	case strings.HasPrefix(name, "create_"), strings.HasPrefix(name, "add_"), strings.HasPrefix(name, "record_"):
+		e.Action = "created"
+	}
+	e.HouseholdUID, _ = arguments["household_uid"].(string)
+	if id, ok := IdentityFromContext(ctx); ok && id.HouseholdUID != "" {
+		e.HouseholdUID = id.HouseholdUID
+	}
+	// The cache is invalidated here as well as through the hub, so the
+	// caller's next read sees its own write.
+	h.cache.invalidate(e)
+	h.events.Publish(e)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func nextEvent(t *testing.T, events <-chan ChangeEvent) ChangeEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return ChangeEvent{}
+	}
+}
+
+func TestEventHubScopesHouseholds(t *testing.T) {
+	hub := NewEventHub()
+	mine, unsubscribe := hub.Subscribe("house-1")
+	theirs, _ := hub.Subscribe("house-2")
+	all, _ := hub.Subscribe("")
+
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "created", HouseholdUID: "house-1"})
+	hub.Publish(ChangeEvent{Entity: "preferences", Action: "updated"})
+	e := nextEvent(t, mine)
+	assert.Equal(t, "todos", e.Entity)
+	assert.False(t, e.At.IsZero())
+	assert.Empty(t, theirs)
+	assert.Empty(t, mine)
+
+	// Subscribers to every household get events without one too.
+	assert.Equal(t, "todos", nextEvent(t, all).Entity)
+	assert.Equal(t, "preferences", nextEvent(t, all).Entity)
+
+	unsubscribe()
+	hub.Publish(ChangeEvent{Entity: "todos", HouseholdUID: "house-1"})
+	assert.Empty(t, mine)
+
+	// A nil hub is a no-op, so handlers can publish unconditionally.
+	var none *EventHub
+	none.Publish(ChangeEvent{HouseholdUID: "house-1"})
+}
+
+func TestTrackPublishesRESTWrites(t *testing.T) {
+	hub := NewEventHub()
+	events, _ := hub.Subscribe("house-1")
+	r := chi.NewRouter()
+	r.Use(hub.Track)
+	r.Post("/pantry", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"uid": "p1", "household_uid": "house-1"}`))
+	})
+	r.Delete("/pantry/{uid}", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	r.Put("/pantry/{uid}", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadRequest) })
+	r.Get("/pantry", func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func(req *http.Request) { r.ServeHTTP(httptest.NewRecorder(), req) }
+	serve(httptest.NewRequest("POST", "/pantry", strings.NewReader(`{}`)))
+	e := nextEvent(t, events)
+	assert.Equal(t, ChangeEvent{Entity: "pantry", Action: "created", ID: "p1", HouseholdUID: "house-1", At: e.At}, e)
+
+	// Deletes have no body; the household comes from the caller's API key.
+	serve(httptest.NewRequest("DELETE", "/pantry/p1", nil).WithContext(identityContext("user-1", "house-1")))
+	e = nextEvent(t, events)
+	assert.Equal(t, "deleted", e.Action)
+	assert.Equal(t, "p1", e.ID)
+
+	// Failed writes and reads publish nothing.
+	serve(httptest.NewRequest("PUT", "/pantry/p1", strings.NewReader(`{}`)).WithContext(identityContext("user-1", "house-1")))
+	serve(httptest.NewRequest("GET", "/pantry", nil).WithContext(identityContext("user-1", "house-1")))
+	assert.Empty(t, events)
+}
+
+func TestMCPToolCallsPublishChanges(t *testing.T) {
+	hub := NewEventHub()
+	events, _ := hub.Subscribe("house-1")
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.Anything).Return(postgres.Todo{UID: "t1", Title: "Call plumber"}, nil)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithEvents(hub))
+	ctx := identityContext("user-1", "house-1")
+
+	assert.False(t, h.callTool(ctx, "create_todo", map[string]any{"title": "Call plumber"}).IsError)
+	e := nextEvent(t, events)
+	assert.Equal(t, "todos", e.Entity)
+	assert.Equal(t, "created", e.Action)
+	assert.Equal(t, "create_todo", e.Tool)
+
+	// Read-only tools and failed calls publish nothing.
+	h.callTool(ctx, "list_todos", map[string]any{})
+	h.callTool(ctx, "create_todo", map[string]any{})
+	assert.Empty(t, events)
+}
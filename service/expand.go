@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type expandDAO interface {
+	GetUsers(ctx context.Context, uids []string) ([]dao.Users, error)
+	GetHouseholds(ctx context.Context, uids []string) ([]dao.Households, error)
+}
+
+// expansionRefs are the related objects ?expand= can embed, each with the
+// field holding the UID it follows.
+var expansionRefs = map[string]string{"user": "user_uid", "household": "household_uid"}
+
+// expandTools are the MCP tools that take an expand argument.
+var expandTools = []string{"list_todos", "recall_note", "list_notes", "find_recipes", "get_recipe"}
+
+// nameTools are the MCP list tools whose results always carry user_name and
+// household_name, since a model can't make anything of bare UIDs.
+var nameTools = []string{"list_todos", "list_notes", "find_recipes", "list_pantry"}
+
+// includeNamesParam is the ?include= value that adds display names.
+const includeNamesParam = "names"
+
+var errUnknownExpansion = errors.New("unknown expansion")
+
+// parseExpand reads a comma-separated list of expansions, such as
+// "user,household".
+func parseExpand(s string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if _, ok := expansionRefs[name]; !ok {
+			return nil, fmt.Errorf("%w %q: expand takes user and household", errUnknownExpansion, name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+type expanderKey struct{}
+
+// Expand lets the get and list endpoints below it embed the users and
+// households their rows refer to, as ?expand=user,household, saving clients
+// a request per reference.
+func Expand(d expandDAO) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), expanderKey{}, d)))
+		})
+	}
+}
+
+// expandFields adds the reference fields of the requested expansions to
+// ?fields=, so that expanding still works when columns are selected.
+func expandFields(r *http.Request, fields []string) []string {
+	names, _ := parseExpand(r.URL.Query().Get("expand"))
+	for _, name := range names {
+		if ref := expansionRefs[name]; !slices.Contains(fields, ref) {
+			fields = append(fields, ref)
+		}
+	}
+	return fields
+}
+
+// expand embeds the named expansions in v, a JSON object or array of
+// objects once encoded, under the expansion's name. Each expansion is read
+// in one query for all the objects. Objects without the reference field are
+// left alone; a null or unknown reference expands to null. Anything that
+// isn't an object or array of objects is returned unchanged.
+func expand(ctx context.Context, d expandDAO, v any, names []string) (any, error) {
+	return embed(ctx, d, v, names, false)
+}
+
+// includeNames adds user_name and household_name beside the user_uid and
+// household_uid fields of v, so rows can be shown without looking up whose
+// they are. Like expand, each is read in one query for all the objects.
+func includeNames(ctx context.Context, d expandDAO, v any) (any, error) {
+	return embed(ctx, d, v, []string{"user", "household"}, true)
+}
+
+// embed does the work of expand and, with namesOnly, of includeNames.
+func embed(ctx context.Context, d expandDAO, v any, names []string, namesOnly bool) (any, error) {
+	if len(names) == 0 {
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v, nil
+	}
+	var objects []map[string]json.RawMessage
+	single := json.Unmarshal(b, &objects) != nil
+	if single {
+		var object map[string]json.RawMessage
+		if json.Unmarshal(b, &object) != nil || object == nil {
+			return v, nil
+		}
+		objects = []map[string]json.RawMessage{object}
+	}
+
+	for _, name := range names {
+		ref := expansionRefs[name]
+		var uids []string
+		for _, o := range objects {
+			var uid string
+			if _, expanded := o[name]; expanded && namesOnly {
+				continue
+			}
+			if json.Unmarshal(o[ref], &uid) == nil && uid != "" && !slices.Contains(uids, uid) {
+				uids = append(uids, uid)
+			}
+		}
+		related, err := fetchExpansion(ctx, d, name, uids)
+		if err != nil {
+			return nil, fmt.Errorf("expand %s: %w", name, err)
+		}
+		key := name
+		if namesOnly {
+			key = name + "_name"
+		}
+		for _, o := range objects {
+			raw, ok := o[ref]
+			if !ok {
+				continue
+			}
+			if expanded, ok := o[name]; ok && namesOnly {
+				// Already expanded, so there's no need to read it again.
+				o[key] = displayName(expanded)
+				continue
+			}
+			var uid string
+			_ = json.Unmarshal(raw, &uid)
+			o[key] = json.RawMessage("null")
+			if r, ok := related[uid]; ok {
+				o[key] = r
+				if namesOnly {
+					o[key] = displayName(r)
+				}
+			}
+		}
+	}
+
+	if single {
+		return objects[0], nil
+	}
+	return objects, nil
+}
+
+// displayName picks the name out of an encoded user or household.
+func displayName(related json.RawMessage) json.RawMessage {
+	var named struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(related, &named) != nil || named.Name == "" {
+		return json.RawMessage("null")
+	}
+	b, _ := json.Marshal(named.Name)
+	return b
+}
+
+// fetchExpansion reads what uids refer to, encoded and keyed by UID.
+func fetchExpansion(ctx context.Context, d expandDAO, name string, uids []string) (map[string]json.RawMessage, error) {
+	out := map[string]json.RawMessage{}
+	if len(uids) == 0 {
+		return out, nil
+	}
+	switch name {
+	case "user":
+		users, err := d.GetUsers(ctx, uids)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			out[u.UID], _ = json.Marshal(u)
+		}
+	case "household":
+		households, err := d.GetHouseholds(ctx, uids)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range households {
+			out[h.UID], _ = json.Marshal(h)
+		}
+	}
+	return out, nil
+}
+
+// expandResponse applies ?expand= and ?include=names to a REST response,
+// answering 400 for an unknown expansion or include. ok is false once it has
+// answered. Without the Expand middleware both are ignored.
+func expandResponse(w http.ResponseWriter, r *http.Request, v any) (any, bool) {
+	d, ok := r.Context().Value(expanderKey{}).(expandDAO)
+	if !ok {
+		return v, true
+	}
+	names, err := parseExpand(r.URL.Query().Get("expand"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	include := r.URL.Query().Get("include")
+	if include != "" && include != includeNamesParam {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("unknown include %q: include takes names", include)})
+		return nil, false
+	}
+	v, err = expand(r.Context(), d, v, names)
+	if err == nil && include == includeNamesParam {
+		v, err = includeNames(r.Context(), d, v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	return v, true
+}
+
+// WithExpansions gives expandTools an expand argument.
+func WithExpansions(d expandDAO) MCPOption {
+	return func(h *MCPHandlers) { h.expandDAO = d }
+}
+
+// addExpandArgument gives each of expandTools an expand argument.
+func addExpandArgument(tools []mcp.Tool) {
+	for i := range tools {
+		if !slices.Contains(expandTools, tools[i].Name) {
+			continue
+		}
+		tools[i].InputSchema.Properties["expand"] = map[string]any{
+			"type":        "string",
+			"description": "Comma-separated related objects to embed in each result, from user and household",
+		}
+	}
+}
+
+// expandToolResult applies the expand argument, and with names the display
+// names, to the payload of a successful tool result.
+func expandToolResult(ctx context.Context, d expandDAO, result mcp.CallToolResult, arg string, names bool) mcp.CallToolResult {
+	body, ok := result.StructuredContent.(map[string]any)
+	if !ok || result.IsError {
+		return result
+	}
+	expansions, err := parseExpand(arg)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	for k, v := range body {
+		if body[k], err = expand(ctx, d, v, expansions); err != nil {
+			return toolError("Failed to expand results: %v", err)
+		}
+		if !names {
+			continue
+		}
+		if body[k], err = includeNames(ctx, d, body[k]); err != nil {
+			return toolError("Failed to expand results: %v", err)
+		}
+	}
+	return toolJSON(body, false)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseExpand(t *testing.T) {
+	names, err := parseExpand("user, household,,user")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user", "household"}, names)
+
+	names, err = parseExpand("")
+	assert.NoError(t, err)
+	assert.Nil(t, names)
+
+	_, err = parseExpand("user,comments")
+	assert.ErrorIs(t, err, errUnknownExpansion)
+}
+
+func TestTodoExpand(t *testing.T) {
+	alex, mia, home := "u1", "u2", "h1"
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{
+		{UID: "t1", Title: "Call plumber", UserUID: &alex, HouseholdUID: &home},
+		{UID: "t2", Title: "Book dentist", UserUID: &mia, HouseholdUID: &home},
+		{UID: "t3", Title: "Water plants", UserUID: &alex},
+	}, nil)
+	mockTodoDAO.On("GetTodo", mock.Anything, "t1").
+		Return(postgres.Todo{UID: "t1", Title: "Call plumber", UserUID: &alex, HouseholdUID: &home}, nil)
+	// Each expansion is read once, for every row at once.
+	mockExpandDAO := mocks.NewMockexpandDAO(t)
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1", "u2"}).
+		Return([]postgres.Users{{UID: "u1", Name: "Alex"}, {UID: "u2", Name: "Mia"}}, nil).Once()
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1"}).
+		Return([]postgres.Users{{UID: "u1", Name: "Alex"}}, nil).Once()
+	mockExpandDAO.On("GetHouseholds", mock.Anything, []string{"h1"}).
+		Return([]postgres.Households{{UID: "h1", Name: "Home"}}, nil).Twice()
+	handler := Expand(mockExpandDAO)(NewTodos(mockTodoDAO))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?expand=user,household", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var todos []postgres.Todo
+	var related []struct {
+		User      *postgres.Users      `json:"user"`
+		Household *postgres.Households `json:"household"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &todos))
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &related))
+	assert.Equal(t, "Call plumber", todos[0].Title)
+	assert.Equal(t, "Alex", related[0].User.Name)
+	assert.Equal(t, "Home", related[0].Household.Name)
+	assert.Equal(t, "Mia", related[1].User.Name)
+	assert.Nil(t, related[2].Household)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/t1?expand=user,household", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var todo struct {
+		Title string          `json:"title"`
+		User  *postgres.Users `json:"user"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &todo))
+	assert.Equal(t, "Call plumber", todo.Title)
+	assert.Equal(t, "Alex", todo.User.Name)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/t1?expand=comments", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `unknown expansion \"comments\"`)
+}
+
+func TestTodoExpandWithFields(t *testing.T) {
+	alex := "u1"
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return assert.ObjectsAreEqual([]string{"uid", "title", "user_uid"}, o.Fields)
+	})).Return([]postgres.Todo{{UID: "t1", Title: "Call plumber", UserUID: &alex}}, nil)
+	mockExpandDAO := mocks.NewMockexpandDAO(t)
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1"}).Return([]postgres.Users{{UID: "u1", Name: "Alex"}}, nil)
+	handler := Expand(mockExpandDAO)(NewTodos(mockTodoDAO))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?fields=uid,title&expand=user", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"user":{"uid":"u1","name":"Alex"`)
+}
+
+func TestMCPHandlers_Expand(t *testing.T) {
+	alex := "u1"
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).
+		Return([]postgres.Todo{{UID: "t1", Title: "Call plumber", UserUID: &alex}}, nil)
+	mockExpandDAO := mocks.NewMockexpandDAO(t)
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1"}).Return([]postgres.Users{{UID: "u1", Name: "Alex"}}, nil).Once()
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithExpansions(mockExpandDAO))
+
+	tool, _ := h.findTool("list_todos")
+	assert.Contains(t, tool.InputSchema.Properties, "expand")
+	tool, _ = h.findTool("create_todo")
+	assert.NotContains(t, tool.InputSchema.Properties, "expand")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "list_todos", map[string]any{"expand": "user"}), &body)
+	todo := body["todos"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Alex", todo["user"].(map[string]any)["name"])
+	assert.Equal(t, "Found 1 todos", body["summary"])
+
+	result := h.callTool(t.Context(), "list_todos", map[string]any{"expand": "comments"})
+	assert.True(t, result.IsError)
+}
+
+func TestTodoIncludeNames(t *testing.T) {
+	alex, home := "u1", "h1"
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{
+		{UID: "t1", Title: "Call plumber", UserUID: &alex, HouseholdUID: &home},
+		{UID: "t2", Title: "Book dentist", HouseholdUID: &home},
+	}, nil)
+	mockExpandDAO := mocks.NewMockexpandDAO(t)
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1"}).Return([]postgres.Users{{UID: "u1", Name: "Alex"}}, nil).Once()
+	mockExpandDAO.On("GetHouseholds", mock.Anything, []string{"h1"}).Return([]postgres.Households{{UID: "h1", Name: "Home"}}, nil).Once()
+	handler := Expand(mockExpandDAO)(NewTodos(mockTodoDAO))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?include=names", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var todos []map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &todos))
+	assert.Equal(t, "Alex", todos[0]["user_name"])
+	assert.Equal(t, "Home", todos[0]["household_name"])
+	assert.Nil(t, todos[1]["user_name"])
+	assert.Equal(t, "Home", todos[1]["household_name"])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?include=everything", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMCPHandlers_ListNames(t *testing.T) {
+	alex := "u1"
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.Anything).
+		Return([]postgres.Notes{{ID: "n1", Key: "wifi", UserUID: &alex}}, nil)
+	mockExpandDAO := mocks.NewMockexpandDAO(t)
+	mockExpandDAO.On("GetUsers", mock.Anything, []string{"u1"}).Return([]postgres.Users{{UID: "u1", Name: "Alex"}}, nil).Once()
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithExpansions(mockExpandDAO))
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "list_notes", map[string]any{}), &body)
+	note := body["notes"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Alex", note["user_name"])
+	assert.Nil(t, note["household_name"])
+}
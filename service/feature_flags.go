@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type featureFlagDAO interface {
+	PutFeatureFlag(ctx context.Context, f dao.FeatureFlag) (dao.FeatureFlag, error)
+	ListFeatureFlags(ctx context.Context) ([]dao.FeatureFlag, error)
+	DeleteFeatureFlag(ctx context.Context, name string) error
+}
+
+// The flags the server checks. Each gates a capability that is still being
+// rolled out.
+const (
+	// FlagSearch gates GET /search.
+	FlagSearch = "search"
+	// FlagCalendarSync gates the calendar tools.
+	FlagCalendarSync = "calendar_sync"
+)
+
+// defaultFlags is whether each flag the server checks is on while it has
+// no row. The capabilities below shipped before their flags did, so they
+// stay on until an operator writes a row to stage them; a new capability
+// would start off. A name not listed here is off without a row.
+var defaultFlags = map[string]bool{
+	FlagSearch:       true,
+	FlagCalendarSync: true,
+}
+
+// flaggedTools are the MCP tools hidden and refused while their flag is off
+// for the caller's household.
+var flaggedTools = map[string]string{
+	"list_calendar_events":  FlagCalendarSync,
+	"create_calendar_event": FlagCalendarSync,
+}
+
+// FeatureFlags answers whether a flag is on for a household from a copy of
+// the feature_flags table, read again once it is ttl old, so checking a
+// flag on every request doesn't query the database. A nil *FeatureFlags
+// has every flag on.
+type FeatureFlags struct {
+	dao featureFlagDAO
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	flags  map[string]dao.FeatureFlag
+	loaded time.Time
+}
+
+func NewFeatureFlags(dao featureFlagDAO, ttl time.Duration) *FeatureFlags {
+	return &FeatureFlags{dao: dao, ttl: ttl, now: time.Now}
+}
+
+// snapshot returns the cached flags, reading them again if they are stale.
+// When that fails the stale copy is kept, and retried after another ttl,
+// so a database outage leaves flags as they were rather than resetting
+// them to their defaults.
+func (f *FeatureFlags) snapshot(ctx context.Context) map[string]dao.FeatureFlag {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	if f.flags != nil && now.Sub(f.loaded) < f.ttl {
+		return f.flags
+	}
+	f.loaded = now
+	// The cache serves every tenant, so it holds every tenant's flags.
+	list, err := f.dao.ListFeatureFlags(dao.WithAllTenants(ctx))
+	if err != nil {
+		slog.Warn("Failed to load feature flags", "error", err)
+		if f.flags == nil {
+			return map[string]dao.FeatureFlag{}
+		}
+		return f.flags
+	}
+	f.flags = make(map[string]dao.FeatureFlag, len(list))
+	for _, flag := range list {
+		f.flags[flag.Name] = flag
+	}
+	return f.flags
+}
+
+// Invalidate drops the cached flags, so the next check reads them again.
+func (f *FeatureFlags) Invalidate() {
+	f.mu.Lock()
+	f.flags = nil
+	f.mu.Unlock()
+}
+
+// Enabled reports whether the flag name is on for householdUID. Without a
+// household, as for operators, only a flag that is on for everyone is.
+func (f *FeatureFlags) Enabled(ctx context.Context, name, householdUID string) bool {
+	if f == nil {
+		return true
+	}
+	flag, ok := f.snapshot(ctx)[name]
+	if !ok {
+		return defaultFlags[name]
+	}
+	return flagOn(flag, householdUID)
+}
+
+func flagOn(flag dao.FeatureFlag, householdUID string) bool {
+	switch {
+	case flag.Enabled || flag.RolloutPercent >= 100:
+		return true
+	case householdUID == "":
+		return false
+	case slices.Contains(flag.HouseholdUIDs, householdUID):
+		return true
+	}
+	return rolloutBucket(flag.Name, householdUID) < flag.RolloutPercent
+}
+
+// rolloutBucket places a household in one of 100 buckets for a flag. The
+// flag's name is part of the hash so that the same households aren't the
+// first to get every new capability.
+func rolloutBucket(name, householdUID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + householdUID))
+	return int(h.Sum32() % 100)
+}
+
+// All reports every known flag, and whether it is on for householdUID.
+func (f *FeatureFlags) All(ctx context.Context, householdUID string) map[string]bool {
+	flags := f.snapshot(ctx)
+	out := make(map[string]bool, len(defaultFlags)+len(flags))
+	maps.Copy(out, defaultFlags)
+	for name, flag := range flags {
+		out[name] = flagOn(flag, householdUID)
+	}
+	return out
+}
+
+// Require answers 404 while the flag name is off for the caller's
+// household, as if the endpoint it guards didn't exist.
+func (f *FeatureFlags) Require(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, _ := IdentityFromContext(r.Context())
+			if !f.Enabled(r.Context(), name, id.HouseholdUID) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%s isn't enabled for this household", name)})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// permitsTool reports whether the caller's household has the flag, if any,
+// that gates tool.
+func (f *FeatureFlags) permitsTool(ctx context.Context, tool string) bool {
+	name, ok := flaggedTools[tool]
+	if !ok {
+		return true
+	}
+	id, _ := IdentityFromContext(ctx)
+	return f.Enabled(ctx, name, id.HouseholdUID)
+}
+
+// WithFeatureFlags hides the tools in flaggedTools from tools/list, and
+// refuses calls to them, while their flag is off for the caller's
+// household.
+func WithFeatureFlags(f *FeatureFlags) MCPOption {
+	return func(h *MCPHandlers) {
+		h.flags = f
+	}
+}
+
+func validateFeatureFlag(f dao.FeatureFlag) error {
+	switch {
+	case f.RolloutPercent < 0 || f.RolloutPercent > 100:
+		return errors.New("rollout_percent must be between 0 and 100")
+	case slices.Contains(f.HouseholdUIDs, ""):
+		return errors.New("household_uids must not contain empty UIDs")
+	}
+	return nil
+}
+
+type FeatureFlagHandlers struct{ flags *FeatureFlags }
+
+// NewFeatureFlagsAdmin manages feature flags at runtime. Only operators,
+// calling without an API key, may list or change them; callers with a key
+// see which flags are on for their household. A change applies to this
+// server at once and to others within the flags' ttl.
+func NewFeatureFlagsAdmin(flags *FeatureFlags) http.Handler {
+	h := &FeatureFlagHandlers{flags}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.With(operatorsOnly).Get("/", h.list)
+	r.Get("/enabled", h.enabled)
+	r.With(operatorsOnly).Put("/{name}", h.put)
+	r.With(operatorsOnly).Delete("/{name}", h.delete)
+	return r
+}
+
+func (h *FeatureFlagHandlers) list(w http.ResponseWriter, r *http.Request) {
+	out, err := h.flags.dao.ListFeatureFlags(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// enabled reports which flags are on for a household: the caller's own
+// with an API key, or ?household_uid= for operators.
+func (h *FeatureFlagHandlers) enabled(w http.ResponseWriter, r *http.Request) {
+	household := r.URL.Query().Get("household_uid")
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		household = id.HouseholdUID
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"household_uid": household,
+		"flags":         h.flags.All(r.Context(), household),
+	})
+}
+
+// put creates the flag in the path or replaces its settings.
+func (h *FeatureFlagHandlers) put(w http.ResponseWriter, r *http.Request) {
+	var f dao.FeatureFlag
+	if json.NewDecoder(r.Body).Decode(&f) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.Name = chi.URLParam(r, "name")
+	if err := validateFeatureFlag(f); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.flags.dao.PutFeatureFlag(r.Context(), f)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.flags.Invalidate()
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *FeatureFlagHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.flags.dao.DeleteFeatureFlag(r.Context(), chi.URLParam(r, "name")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.flags.Invalidate()
+	w.WriteHeader(http.StatusNoContent)
+}
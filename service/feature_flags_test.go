@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func staticFlags(t *testing.T, flags ...postgres.FeatureFlag) *FeatureFlags {
+	d := mocks.NewMockfeatureFlagDAO(t)
+	d.On("ListFeatureFlags", mock.Anything).Return(flags, nil).Maybe()
+	return NewFeatureFlags(d, time.Minute)
+}
+
+func TestFeatureFlagsEnabled(t *testing.T) {
+	ctx := context.Background()
+	flags := staticFlags(t,
+		postgres.FeatureFlag{Name: "everyone", Enabled: true},
+		postgres.FeatureFlag{Name: "listed", HouseholdUIDs: []string{"house-1"}},
+		postgres.FeatureFlag{Name: "nobody"},
+		postgres.FeatureFlag{Name: FlagCalendarSync, RolloutPercent: 100},
+		postgres.FeatureFlag{Name: "half", RolloutPercent: 50},
+	)
+
+	assert.True(t, flags.Enabled(ctx, "everyone", ""))
+	assert.True(t, flags.Enabled(ctx, "listed", "house-1"))
+	assert.False(t, flags.Enabled(ctx, "listed", "house-2"))
+	assert.False(t, flags.Enabled(ctx, "listed", ""))
+	assert.False(t, flags.Enabled(ctx, "nobody", "house-1"))
+	assert.True(t, flags.Enabled(ctx, FlagCalendarSync, "house-1"))
+	// Without a row a flag has its default.
+	assert.True(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+	assert.False(t, flags.Enabled(ctx, "unknown", "house-1"))
+
+	on := 0
+	for i := range 1000 {
+		if flags.Enabled(ctx, "half", fmt.Sprintf("house-%d", i)) {
+			on++
+		}
+	}
+	assert.InDelta(t, 500, on, 60)
+
+	var nilFlags *FeatureFlags
+	assert.True(t, nilFlags.Enabled(ctx, "nobody", "house-1"))
+}
+
+func TestFeatureFlagsRolloutOnlyGrows(t *testing.T) {
+	for i := range 200 {
+		household := fmt.Sprintf("house-%d", i)
+		was := false
+		for percent := 0; percent <= 100; percent += 10 {
+			on := flagOn(postgres.FeatureFlag{Name: "search", RolloutPercent: percent}, household)
+			assert.False(t, was && !on, "%s left the rollout at %d%%", household, percent)
+			was = on
+		}
+		assert.True(t, was)
+	}
+}
+
+func TestFeatureFlagsCache(t *testing.T) {
+	ctx := context.Background()
+	d := mocks.NewMockfeatureFlagDAO(t)
+	d.On("ListFeatureFlags", mock.Anything).Return([]postgres.FeatureFlag{{Name: FlagSearch}}, nil).Once()
+	flags := NewFeatureFlags(d, time.Minute)
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	flags.now = func() time.Time { return now }
+
+	assert.False(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+	assert.False(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+
+	// A failed reload keeps what was read before.
+	now = now.Add(2 * time.Minute)
+	d.On("ListFeatureFlags", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	assert.False(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+
+	now = now.Add(2 * time.Minute)
+	d.On("ListFeatureFlags", mock.Anything).Return([]postgres.FeatureFlag{{Name: FlagSearch, Enabled: true}}, nil).Once()
+	assert.True(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+
+	flags.Invalidate()
+	d.On("ListFeatureFlags", mock.Anything).Return([]postgres.FeatureFlag{}, nil).Once()
+	assert.True(t, flags.Enabled(ctx, FlagSearch, "house-1"))
+	d.AssertNumberOfCalls(t, "ListFeatureFlags", 4)
+}
+
+func TestFeatureFlagsRequire(t *testing.T) {
+	flags := staticFlags(t, postgres.FeatureFlag{Name: FlagSearch, HouseholdUIDs: []string{"house-1"}})
+	handler := flags.Require(FlagSearch)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?q=milk", nil).WithContext(identityContext("user-1", "house-1")))
+	assert.Equal(t, http.StatusTeapot, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?q=milk", nil).WithContext(identityContext("user-2", "house-2")))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "search isn't enabled for this household")
+}
+
+func TestFeatureFlagsGateTools(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithCalendars(mocks.NewMockcalendarCredentialDAO(t), nil),
+		WithFeatureFlags(staticFlags(t, postgres.FeatureFlag{Name: FlagCalendarSync, HouseholdUIDs: []string{"house-1"}})),
+		WithToolsPageSize(1000))
+	visible := func(ctx context.Context) bool {
+		result, err := h.listTools(ctx, "")
+		assert.NoError(t, err)
+		for _, tool := range result.Tools {
+			if tool.Name == "list_calendar_events" {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.True(t, visible(identityContext("user-1", "house-1")))
+	assert.False(t, visible(identityContext("user-2", "house-2")))
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-2", "house-2"), "create_calendar_event", map[string]any{"summary": "Dentist"}), &body)
+	assert.Equal(t, "Tool create_calendar_event isn't enabled for this household", body["error"])
+}
+
+func TestFeatureFlagHandlers(t *testing.T) {
+	d := mocks.NewMockfeatureFlagDAO(t)
+	d.On("PutFeatureFlag", mock.Anything, postgres.FeatureFlag{Name: FlagCalendarSync, RolloutPercent: 25}).
+		Return(postgres.FeatureFlag{UID: "f1", Name: FlagCalendarSync, RolloutPercent: 25}, nil)
+	d.On("ListFeatureFlags", mock.Anything).Return([]postgres.FeatureFlag{{Name: FlagSearch, HouseholdUIDs: []string{"house-1"}}}, nil)
+	d.On("DeleteFeatureFlag", mock.Anything, FlagSearch).Return(nil)
+	handler := NewFeatureFlagsAdmin(NewFeatureFlags(d, time.Minute))
+	asMember := func(r *http.Request) *http.Request { return r.WithContext(identityContext("user-1", "house-1")) }
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("PUT", "/calendar_sync", strings.NewReader(`{"rollout_percent": 25}`))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"f1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("PUT", "/calendar_sync", strings.NewReader(`{"rollout_percent": 120}`))))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "rollout_percent must be between 0 and 100")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("PUT", "/search", strings.NewReader(`{"enabled": true}`))))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"household_uids":["house-1"]`)
+
+	// A member sees their own household's flags, whatever they ask for.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("GET", "/enabled?household_uid=house-2", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"household_uid": "house-1", "flags": {"search": true, "calendar_sync": true}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/enabled?household_uid=house-2", nil))
+	assert.JSONEq(t, `{"household_uid": "house-2", "flags": {"search": false, "calendar_sync": true}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/search", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
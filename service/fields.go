@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// errNoRow is returned by getWithFields when nothing matches.
+var errNoRow = errors.New("no row")
+
+// parseFields reads ?fields=uid,title,due_date, plus the reference fields
+// ?expand= follows. The names are checked against the table's columns by the
+// DAO.
+func parseFields(r *http.Request) []string {
+	var fields []string
+	for _, f := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" && !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) > 0 {
+		fields = expandFields(r, fields)
+	}
+	return fields
+}
+
+// getWithFields fetches a single row for a get endpoint. Without ?fields=
+// it simply calls get; with it the row is read through list, where must
+// match at most one row, so that only the requested columns are selected.
+func getWithFields[T any](r *http.Request, get func(context.Context) (T, error),
+	list func(context.Context, dao.ListOptions) ([]T, error), where string, args ...any) (T, error) {
+	fields := parseFields(r)
+	if len(fields) == 0 {
+		return get(r.Context())
+	}
+	out, err := list(r.Context(), dao.ListOptions{
+		Limit:       1,
+		SortBy:      "created_at",
+		SortDir:     "DESC",
+		WhereClause: where,
+		WhereArgs:   args,
+		Fields:      fields,
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(out) == 0 {
+		var zero T
+		return zero, errNoRow
+	}
+	return out[0], nil
+}
+
+// writeFieldsError reports a failed get or list: 400 when ?fields= names an
+// unknown column, otherwise fallback.
+func writeFieldsError(w http.ResponseWriter, err error, fallback int) {
+	if errors.Is(err, dao.ErrUnknownField) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(fallback)
+}
+
+// selectFields keeps only the keys in fields of a JSON object, or of each
+// object in a JSON array, so columns that weren't selected aren't reported
+// as zero values.
+func selectFields(v any, fields []string) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var objects []map[string]json.RawMessage
+	if json.Unmarshal(b, &objects) == nil {
+		for _, o := range objects {
+			keepFields(o, fields)
+		}
+		return objects
+	}
+	var object map[string]json.RawMessage
+	if json.Unmarshal(b, &object) == nil {
+		keepFields(object, fields)
+		return object
+	}
+	return v
+}
+
+func keepFields(o map[string]json.RawMessage, fields []string) {
+	for k := range o {
+		if !slices.Contains(fields, k) {
+			delete(o, k)
+		}
+	}
+}
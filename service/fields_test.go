@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseFields(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?fields=uid,%20title,,uid,due_date&title=x", nil)
+	assert.Equal(t, []string{"uid", "title", "due_date"}, parseFields(r))
+
+	params := ParseListParams(r, TodoFilters.SortFields)
+	assert.Equal(t, []string{"uid", "title", "due_date"}, params.Fields)
+	assert.NotContains(t, params.Filters, "fields")
+
+	assert.Nil(t, parseFields(httptest.NewRequest("GET", "/", nil)))
+}
+
+func TestTodoListFields(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return assert.ObjectsAreEqual([]string{"uid", "title"}, o.Fields)
+	})).Return([]postgres.Todo{{UID: "t1", Title: "Call plumber"}}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?fields=uid,title", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// Columns that weren't selected aren't reported as zero values.
+	assert.JSONEq(t, `[{"uid": "t1", "title": "Call plumber"}]`, rr.Body.String())
+}
+
+func TestTodoGetFields(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE uid = $1" && o.Limit == 1 &&
+			assert.ObjectsAreEqual([]any{"t1"}, o.WhereArgs) && assert.ObjectsAreEqual([]string{"title"}, o.Fields)
+	})).Return([]postgres.Todo{{Title: "Call plumber"}}, nil).Once()
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil).Once()
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/t1?fields=title", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"title": "Call plumber"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/missing?fields=title", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUnknownFieldIsBadRequest(t *testing.T) {
+	unknown := fmt.Errorf("%w: password", postgres.ErrUnknownField)
+	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
+	mockRecipesDAO.On("ListRecipes", mock.Anything, mock.Anything).Return(nil, unknown)
+	handler := NewRecipes(mockRecipesDAO)
+
+	for _, target := range []string{"/?fields=password", "/r1?fields=password"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.JSONEq(t, `{"error": "unknown field: password"}`, rr.Body.String(), target)
+	}
+}
+
+func TestNoteGetFieldsKeepsVisibility(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		// The caller's visibility condition is added to the id lookup.
+		return len(o.WhereArgs) > 1 && o.WhereArgs[0] == "n1"
+	})).Return([]postgres.Notes{}, nil)
+	handler := NewNotes(mockNotesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/n1?fields=key", nil).
+		WithContext(identityContext("user-2", "house-1")))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// GroceryCategories are the aisles groceries are sorted into, in the order a
+// typical store is walked. Anything the classifier can't place is "other".
+var GroceryCategories = []string{
+	"produce", "bakery", "meat", "seafood", "dairy", "frozen",
+	"pantry", "spices", "snacks", "beverages", "household", "other",
+}
+
+// otherCategory is where groceries no rule or model could place go.
+const otherCategory = "other"
+
+// GroceryClassifier picks the category of each grocery item. Items it can't
+// place are left out of the result.
+type GroceryClassifier interface {
+	ClassifyGroceries(ctx context.Context, items []string) (map[string]string, error)
+}
+
+// GroceryRules places each item in the category of the longest keyword it
+// mentions, so "coconut milk" is pantry while "milk" is dairy. Keywords are
+// matched case-insensitively as whole words or phrases, also in the plural.
+type GroceryRules map[string][]string
+
+// DefaultGroceryRules are the rules NewGroceryRules starts from.
+var DefaultGroceryRules = GroceryRules{
+	"produce": {"apple", "banana", "lemon", "lime", "orange", "berry", "berries", "grape", "avocado", "tomato",
+		"potato", "onion", "garlic", "ginger", "carrot", "celery", "cucumber", "pepper", "courgette", "zucchini",
+		"aubergine", "eggplant", "lettuce", "spinach", "kale", "cabbage", "broccoli", "cauliflower", "mushroom",
+		"leek", "shallot", "chilli", "chili", "herbs", "basil", "parsley", "coriander", "cilantro", "mint", "salad"},
+	"bakery":  {"bread", "loaf", "baguette", "roll", "bagel", "croissant", "tortilla", "wrap", "pitta", "pita", "naan", "bun"},
+	"meat":    {"chicken", "beef", "pork", "lamb", "turkey", "mince", "bacon", "sausage", "ham", "guanciale", "pancetta", "chorizo", "steak"},
+	"seafood": {"fish", "salmon", "cod", "tuna", "haddock", "prawn", "shrimp", "mussel", "anchovy", "anchovies"},
+	"dairy":   {"milk", "butter", "cheese", "cream", "yoghurt", "yogurt", "egg", "parmesan", "mozzarella", "feta", "creme fraiche"},
+	"frozen":  {"frozen", "ice cream", "peas"},
+	"pantry": {"flour", "sugar", "rice", "pasta", "spaghetti", "noodles", "oats", "lentils", "chickpeas", "beans", "stock",
+		"chicken stock", "beef stock", "oil", "vinegar", "honey", "jam", "peanut butter", "tinned", "canned", "coconut milk",
+		"soy sauce", "lemon juice", "cereal"},
+	"spices": {"salt", "black pepper", "paprika", "cumin", "cinnamon", "turmeric", "oregano", "thyme", "nutmeg", "chilli flakes",
+		"curry powder", "garlic powder", "vanilla", "baking powder", "bicarbonate"},
+	"snacks":    {"crisps", "chips", "biscuits", "cookies", "chocolate", "nuts", "popcorn", "crackers"},
+	"beverages": {"water", "juice", "orange juice", "coffee", "tea", "wine", "beer", "soda", "lemonade"},
+	"household": {"toilet roll", "kitchen roll", "washing up liquid", "detergent", "bin bags", "foil", "cling film", "soap", "sponges"},
+}
+
+// NewGroceryRules adds rules, as "keyword|keyword" per category, to
+// DefaultGroceryRules. Categories outside GroceryCategories are an error.
+func NewGroceryRules(rules map[string]string) (GroceryRules, error) {
+	g := GroceryRules{}
+	for category, keywords := range DefaultGroceryRules {
+		g[category] = slices.Clone(keywords)
+	}
+	for category, keywords := range rules {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !slices.Contains(GroceryCategories, category) {
+			return nil, fmt.Errorf("unknown grocery category %q", category)
+		}
+		for _, kw := range strings.Split(keywords, "|") {
+			if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+				g[category] = append(g[category], kw)
+			}
+		}
+	}
+	return g, nil
+}
+
+func (g GroceryRules) ClassifyGroceries(_ context.Context, items []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, item := range items {
+		if category := g.classify(item); category != "" {
+			out[item] = category
+		}
+	}
+	return out, nil
+}
+
+func (g GroceryRules) classify(item string) string {
+	words := strings.FieldsFunc(strings.ToLower(item), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	padded := " " + strings.Join(words, " ") + " "
+	var best, bestKeyword string
+	for category, keywords := range g {
+		for _, kw := range keywords {
+			if len(kw) < len(bestKeyword) || (len(kw) == len(bestKeyword) && category > best) {
+				continue
+			}
+			if strings.Contains(padded, " "+kw+" ") || strings.Contains(padded, " "+kw+"s ") || strings.Contains(padded, " "+kw+"es ") {
+				best, bestKeyword = category, kw
+			}
+		}
+	}
+	return best
+}
+
+// GroceryCategorizer asks a model to sort items into one of categories, as
+// *llm.Chat does.
+type GroceryCategorizer interface {
+	CategorizeGroceries(ctx context.Context, items, categories []string) (map[string]string, error)
+}
+
+// NewGroceryClassifier classifies items with rules and asks the categorizer
+// about the ones no rule places.
+func NewGroceryClassifier(rules GroceryRules, categorizer GroceryCategorizer) GroceryClassifier {
+	return &fallbackClassifier{rules: rules, categorizer: categorizer}
+}
+
+type fallbackClassifier struct {
+	rules       GroceryRules
+	categorizer GroceryCategorizer
+}
+
+func (f *fallbackClassifier) ClassifyGroceries(ctx context.Context, items []string) (map[string]string, error) {
+	out, _ := f.rules.ClassifyGroceries(ctx, items)
+	var unplaced []string
+	for _, item := range items {
+		if _, ok := out[item]; !ok && !slices.Contains(unplaced, item) {
+			unplaced = append(unplaced, item)
+		}
+	}
+	if len(unplaced) == 0 {
+		return out, nil
+	}
+	suggested, err := f.categorizer.CategorizeGroceries(ctx, unplaced, GroceryCategories)
+	if err != nil {
+		return out, err
+	}
+	for _, item := range unplaced {
+		if category := suggested[item]; slices.Contains(GroceryCategories, category) {
+			out[item] = category
+		}
+	}
+	return out, nil
+}
+
+// classifyGroceries returns the category of each of items, "other" for those
+// classifier can't place. A failing classifier only costs the categories it
+// didn't return.
+func classifyGroceries(ctx context.Context, classifier GroceryClassifier, items []string) map[string]string {
+	out := map[string]string{}
+	if classifier != nil && len(items) > 0 {
+		categories, err := classifier.ClassifyGroceries(ctx, items)
+		if err != nil {
+			slog.Warn("Failed to classify groceries", "error", err)
+		}
+		out = categories
+		if out == nil {
+			out = map[string]string{}
+		}
+	}
+	for _, item := range items {
+		if out[item] == "" {
+			out[item] = otherCategory
+		}
+	}
+	return out
+}
+
+// validGroceryCategory lowercases category and checks it is one of
+// GroceryCategories; empty is allowed.
+func validGroceryCategory(category *string) error {
+	if category == nil {
+		return nil
+	}
+	*category = strings.ToLower(strings.TrimSpace(*category))
+	if *category != "" && !slices.Contains(GroceryCategories, *category) {
+		return fmt.Errorf("category must be one of %s", strings.Join(GroceryCategories, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroceryRules(t *testing.T) {
+	categories, err := DefaultGroceryRules.ClassifyGroceries(t.Context(), []string{
+		"milk", "coconut milk", "Tomatoes", "tinned tomatoes", "black pepper", "red peppers", "toilet roll", "tahini",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"milk": "dairy", "coconut milk": "pantry", "Tomatoes": "produce", "tinned tomatoes": "pantry",
+		"black pepper": "spices", "red peppers": "produce", "toilet roll": "household",
+	}, categories)
+
+	rules, err := NewGroceryRules(map[string]string{"Pantry": "tahini | miso"})
+	assert.NoError(t, err)
+	categories, _ = rules.ClassifyGroceries(t.Context(), []string{"tahini", "milk"})
+	assert.Equal(t, map[string]string{"tahini": "pantry", "milk": "dairy"}, categories)
+	assert.NotContains(t, DefaultGroceryRules["pantry"], "tahini")
+
+	_, err = NewGroceryRules(map[string]string{"deli": "olives"})
+	assert.ErrorContains(t, err, `unknown grocery category "deli"`)
+}
+
+type fakeCategorizer struct {
+	asked []string
+	err   error
+}
+
+func (f *fakeCategorizer) CategorizeGroceries(_ context.Context, items, _ []string) (map[string]string, error) {
+	f.asked = items
+	return map[string]string{"tahini": "pantry", "quark": "deli"}, f.err
+}
+
+func TestGroceryClassifierFallback(t *testing.T) {
+	categorizer := &fakeCategorizer{}
+	classifier := NewGroceryClassifier(DefaultGroceryRules, categorizer)
+
+	// Only what the rules can't place is asked about, and only known
+	// categories are taken.
+	categories, err := classifier.ClassifyGroceries(t.Context(), []string{"milk", "tahini", "quark", "tahini"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tahini", "quark"}, categorizer.asked)
+	assert.Equal(t, map[string]string{"milk": "dairy", "tahini": "pantry"}, categories)
+
+	categorizer.asked = nil
+	_, _ = classifier.ClassifyGroceries(t.Context(), []string{"milk"})
+	assert.Nil(t, categorizer.asked)
+
+	// A failing model still leaves the rules' categories, and everything
+	// else is "other".
+	categorizer.err = assert.AnError
+	assert.Equal(t, map[string]string{"milk": "dairy", "tahini": "other"},
+		classifyGroceries(t.Context(), classifier, []string{"milk", "tahini"}))
+}
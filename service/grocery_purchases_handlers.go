@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// defaultSpendMonths is how many months, including the current one, a spend
+// report covers when no range is given.
+const defaultSpendMonths = 3
+
+type groceryPurchaseDAO interface {
+	CreateGroceryPurchase(ctx context.Context, p dao.GroceryPurchase) (dao.GroceryPurchase, error)
+	GetGroceryPurchase(ctx context.Context, uid string) (dao.GroceryPurchase, error)
+	ListGroceryPurchases(ctx context.Context, options dao.ListOptions) ([]dao.GroceryPurchase, error)
+	DeleteGroceryPurchase(ctx context.Context, uid string) error
+	GroceryMonthlySpend(ctx context.Context, householdUID string, from, to time.Time) ([]dao.GrocerySpend, error)
+}
+
+type GroceryPurchaseHandlers struct{ dao groceryPurchaseDAO }
+
+func NewGroceryPurchases(dao groceryPurchaseDAO) http.Handler {
+	h := &GroceryPurchaseHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.create)
+	r.Get("/spend", h.spend)
+	r.Get("/{uid}", h.get)
+	r.Delete("/{uid}", h.delete)
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *GroceryPurchaseHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var p dao.GroceryPurchase
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := validateGroceryPurchase(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateGroceryPurchase(r.Context(), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *GroceryPurchaseHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.GroceryPurchase, error) { return h.dao.GetGroceryPurchase(ctx, uid) },
+		h.dao.ListGroceryPurchases, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *GroceryPurchaseHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteGroceryPurchase(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GroceryPurchaseHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, GroceryPurchaseFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, GroceryPurchaseFilters.Filters)
+	if !ok {
+		return
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListGroceryPurchases(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// spend reports a household's monthly grocery spend. from and to are
+// months ("2025-06"), both included, defaulting to the last three months.
+func (h *GroceryPurchaseHandlers) spend(w http.ResponseWriter, r *http.Request) {
+	householdUID := r.URL.Query().Get("household_uid")
+	if householdUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "household_uid is required"})
+		return
+	}
+	from, to, err := parseMonthRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	report, err := grocerySpendReport(r.Context(), h.dao, householdUID, from, to)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func validateGroceryPurchase(p *dao.GroceryPurchase) error {
+	p.Item = strings.TrimSpace(p.Item)
+	p.Store = strings.TrimSpace(p.Store)
+	if p.Item == "" {
+		return errors.New("item is required")
+	}
+	if p.HouseholdUID == "" {
+		return errors.New("household_uid is required")
+	}
+	if p.PriceCents < 0 {
+		return errors.New("price_cents must not be negative")
+	}
+	return nil
+}
+
+// GrocerySpendReport is a household's grocery spend per month, From and To
+// included, with each month broken down by store.
+type GrocerySpendReport struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	TotalCents int64               `json:"total_cents"`
+	Months     []MonthGrocerySpend `json:"months"`
+}
+
+type MonthGrocerySpend struct {
+	Month      string             `json:"month"`
+	TotalCents int64              `json:"total_cents"`
+	Stores     []dao.GrocerySpend `json:"stores"`
+}
+
+const monthLayout = "2006-01"
+
+// parseMonthRange turns from and to months into the first day of from and
+// the first day after to. Either may be empty; the default range ends with
+// the month of now and covers defaultSpendMonths months.
+func parseMonthRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		t, err := time.Parse(monthLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be a month like 2025-08")
+		}
+		end = t
+	}
+	start := end.AddDate(0, 1-defaultSpendMonths, 0)
+	if from != "" {
+		t, err := time.Parse(monthLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be a month like 2025-06")
+		}
+		start = t
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must not be after to")
+	}
+	return start, end.AddDate(0, 1, 0), nil
+}
+
+// grocerySpendReport totals a household's purchases from the start of from
+// up to, not including, to. Months without purchases are reported as zero.
+func grocerySpendReport(ctx context.Context, d groceryPurchaseDAO, householdUID string, from, to time.Time) (GrocerySpendReport, error) {
+	rows, err := d.GroceryMonthlySpend(ctx, householdUID, from, to)
+	if err != nil {
+		return GrocerySpendReport{}, err
+	}
+	report := GrocerySpendReport{
+		From:   from.Format(monthLayout),
+		To:     to.AddDate(0, -1, 0).Format(monthLayout),
+		Months: []MonthGrocerySpend{},
+	}
+	byMonth := map[string]int{}
+	for m := from; m.Before(to); m = m.AddDate(0, 1, 0) {
+		byMonth[m.Format(monthLayout)] = len(report.Months)
+		report.Months = append(report.Months, MonthGrocerySpend{Month: m.Format(monthLayout), Stores: []dao.GrocerySpend{}})
+	}
+	for _, row := range rows {
+		i, ok := byMonth[row.Month]
+		if !ok {
+			continue
+		}
+		report.Months[i].Stores = append(report.Months[i].Stores, row)
+		report.Months[i].TotalCents += row.TotalCents
+		report.TotalCents += row.TotalCents
+	}
+	return report, nil
+}
+
+// formatCents writes an amount of money without a currency, e.g. "12.05".
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseMonthRange(t *testing.T) {
+	now := time.Date(2025, 8, 20, 15, 0, 0, 0, time.UTC)
+
+	from, to, err := parseMonthRange("", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parseMonthRange("2024-12", "2025-01", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	for _, r := range [][2]string{{"2025-13", ""}, {"", "August"}, {"2025-08", "2025-07"}} {
+		_, _, err := parseMonthRange(r[0], r[1], now)
+		assert.Error(t, err, r)
+	}
+}
+
+func TestGrocerySpendRoute(t *testing.T) {
+	mockDAO := mocks.NewMockgroceryPurchaseDAO(t)
+	mockDAO.On("GroceryMonthlySpend", mock.Anything, "house-1",
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)).
+		Return([]postgres.GrocerySpend{
+			{Month: "2025-06", Store: "Aldi", TotalCents: 4250, Purchases: 6},
+			{Month: "2025-06", Store: "Co-op", TotalCents: 1199, Purchases: 2},
+		}, nil)
+	handler := NewGroceryPurchases(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/spend?household_uid=house-1&from=2025-06&to=2025-07", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var report GrocerySpendReport
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	assert.Equal(t, int64(5449), report.TotalCents)
+	assert.Len(t, report.Months, 2)
+	assert.Equal(t, int64(5449), report.Months[0].TotalCents)
+	assert.Len(t, report.Months[0].Stores, 2)
+	// July had no purchases but is still reported.
+	assert.Equal(t, MonthGrocerySpend{Month: "2025-07", Stores: []postgres.GrocerySpend{}}, report.Months[1])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/spend", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGroceryPurchasesCreateValidates(t *testing.T) {
+	mockDAO := mocks.NewMockgroceryPurchaseDAO(t)
+	handler := NewGroceryPurchases(mockDAO)
+
+	for _, body := range []string{
+		`{"household_uid": "house-1", "price_cents": 100}`,
+		`{"item": "milk", "price_cents": 100}`,
+		`{"item": "milk", "household_uid": "house-1", "price_cents": -5}`,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestMCPHandlers_GroceryPurchaseTools(t *testing.T) {
+	mockDAO := mocks.NewMockgroceryPurchaseDAO(t)
+	mockDAO.On("CreateGroceryPurchase", mock.Anything, postgres.GroceryPurchase{
+		HouseholdUID: "house-1",
+		Item:         "milk",
+		Store:        "Aldi",
+		PriceCents:   199,
+		PurchasedOn:  time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC),
+	}).Return(postgres.GroceryPurchase{UID: "g1", Item: "milk", PriceCents: 199}, nil)
+	mockDAO.On("GroceryMonthlySpend", mock.Anything, "house-1", mock.Anything, mock.Anything).
+		Return([]postgres.GrocerySpend{{Month: "2025-08", Store: "Aldi", TotalCents: 12005}}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithGroceryPurchases(mockDAO))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "record_grocery_purchase", map[string]any{
+		"item": "milk", "price": 1.99, "store": "Aldi", "purchased_on": "2025-08-18",
+	}), &body)
+	assert.Equal(t, "Recorded milk for 1.99", body["summary"])
+
+	decodeToolResult(t, h.callTool(ctx, "grocery_spend_report", map[string]any{"from": "2025-08", "to": "2025-08"}), &body)
+	assert.Equal(t, "Spent 120.05 on groceries from 2025-08 to 2025-08", body["summary"])
+
+	assert.True(t, h.callTool(ctx, "record_grocery_purchase", map[string]any{"item": "milk"}).IsError)
+	assert.True(t, h.callTool(ctx, "grocery_spend_report", map[string]any{"from": "last month"}).IsError)
+}
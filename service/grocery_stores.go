@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// GroceryStoresPreferenceKey is the preference key, specified by household
+// UID, holding a household's GroceryStores as JSON.
+const GroceryStoresPreferenceKey = "grocery_stores"
+
+// GroceryStores are the stores a household shops at, most preferred first,
+// and where it likes to buy particular items and categories. Anything
+// without a preferred store is bought at the first store.
+type GroceryStores struct {
+	HouseholdUID string   `json:"household_uid"`
+	Stores       []string `json:"stores"`
+	// Items sets the store for an item, by lowercase name.
+	Items map[string]string `json:"items"`
+	// Categories sets the store for a grocery category, e.g. produce from
+	// the market.
+	Categories map[string]string `json:"categories"`
+}
+
+// Validate tidies names and checks every preferred store is one of Stores,
+// spelling it as Stores does.
+func (s *GroceryStores) Validate() error {
+	stores := []string{}
+	for _, store := range s.Stores {
+		if store = strings.TrimSpace(store); store == "" {
+			continue
+		}
+		if findStore(stores, store) != "" {
+			return fmt.Errorf("store %q is listed twice", store)
+		}
+		stores = append(stores, store)
+	}
+	s.Stores = stores
+
+	items := map[string]string{}
+	for item, store := range s.Items {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			return errors.New("items: item name must not be empty")
+		}
+		if items[item] = findStore(s.Stores, store); items[item] == "" {
+			return fmt.Errorf("items: %s: %q is not one of the household's stores", item, store)
+		}
+	}
+	s.Items = items
+
+	categories := map[string]string{}
+	for category, store := range s.Categories {
+		if err := validGroceryCategory(&category); err != nil || category == "" {
+			return fmt.Errorf("categories: category must be one of %s", strings.Join(GroceryCategories, ", "))
+		}
+		if categories[category] = findStore(s.Stores, store); categories[category] == "" {
+			return fmt.Errorf("categories: %s: %q is not one of the household's stores", category, store)
+		}
+	}
+	s.Categories = categories
+	return nil
+}
+
+// findStore finds name in stores regardless of case, or returns "".
+func findStore(stores []string, name string) string {
+	name = strings.TrimSpace(name)
+	for _, store := range stores {
+		if strings.EqualFold(store, name) {
+			return store
+		}
+	}
+	return ""
+}
+
+// prune drops the preferences for stores no longer in Stores.
+func (s *GroceryStores) prune() {
+	for _, m := range []map[string]string{s.Items, s.Categories} {
+		for k, store := range m {
+			if findStore(s.Stores, store) == "" {
+				delete(m, k)
+			}
+		}
+	}
+}
+
+// StoreFor is where to buy item: its own preferred store, else its
+// category's, else the first store. It is empty when the household has no
+// stores.
+func (s GroceryStores) StoreFor(item ShoppingListItem) string {
+	for name, store := range s.Items {
+		if ingredientKey(name) == ingredientKey(item.Item) {
+			return store
+		}
+	}
+	if store, ok := s.Categories[item.Category]; ok {
+		return store
+	}
+	if len(s.Stores) > 0 {
+		return s.Stores[0]
+	}
+	return ""
+}
+
+func parseGroceryStores(householdUID, data string) (GroceryStores, error) {
+	s := GroceryStores{HouseholdUID: householdUID}
+	err := json.Unmarshal([]byte(data), &s)
+	s.HouseholdUID = householdUID
+	if s.Stores == nil {
+		s.Stores = []string{}
+	}
+	if s.Items == nil {
+		s.Items = map[string]string{}
+	}
+	if s.Categories == nil {
+		s.Categories = map[string]string{}
+	}
+	return s, err
+}
+
+// loadGroceryStores returns a household's stores, or none when it hasn't
+// set any.
+func loadGroceryStores(ctx context.Context, prefs preferencesDAO, householdUID string) (GroceryStores, error) {
+	stored, err := prefs.GetPreferences(ctx, GroceryStoresPreferenceKey, householdUID)
+	if err != nil {
+		return parseGroceryStores(householdUID, "{}")
+	}
+	return parseGroceryStores(householdUID, stored.Data)
+}
+
+func saveGroceryStores(ctx context.Context, prefs preferencesDAO, s GroceryStores) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	pref := dao.Preferences{Key: GroceryStoresPreferenceKey, Specifier: s.HouseholdUID, Data: string(data)}
+	if _, err := prefs.GetPreferences(ctx, GroceryStoresPreferenceKey, s.HouseholdUID); err == nil {
+		_, err = prefs.UpdatePreferences(ctx, GroceryStoresPreferenceKey, s.HouseholdUID, pref)
+		return err
+	}
+	_, err = prefs.CreatePreferences(ctx, pref)
+	return err
+}
+
+// StoreShoppingList is what to buy at one store, grouped by aisle. Store is
+// empty for what the household has no store for.
+type StoreShoppingList struct {
+	Store  string             `json:"store"`
+	Items  []ShoppingListItem `json:"items"`
+	Aisles []ShoppingAisle    `json:"aisles"`
+}
+
+// splitByStore divides the categorized items of l between stores, in the
+// household's order of preference.
+func (l ShoppingList) splitByStore(stores GroceryStores) []StoreShoppingList {
+	byStore := map[string][]ShoppingListItem{}
+	for _, item := range l.Items {
+		store := stores.StoreFor(item)
+		byStore[store] = append(byStore[store], item)
+	}
+	out := []StoreShoppingList{}
+	for _, store := range append(slices.Clone(stores.Stores), "") {
+		if items, ok := byStore[store]; ok {
+			out = append(out, StoreShoppingList{Store: store, Items: items, Aisles: groupAisles(items)})
+		}
+	}
+	return out
+}
+
+type GroceryStoreHandlers struct{ dao preferencesDAO }
+
+func NewGroceryStores(dao preferencesDAO) http.Handler {
+	h := &GroceryStoreHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/{household_uid}", h.get)
+	r.Put("/{household_uid}", h.put)
+	return r
+}
+
+func (h *GroceryStoreHandlers) get(w http.ResponseWriter, r *http.Request) {
+	out, err := loadGroceryStores(r.Context(), h.dao, chi.URLParam(r, "household_uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// put replaces a household's stores and preferred stores.
+func (h *GroceryStoreHandlers) put(w http.ResponseWriter, r *http.Request) {
+	householdUID := chi.URLParam(r, "household_uid")
+	var s GroceryStores
+	if json.NewDecoder(r.Body).Decode(&s) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.HouseholdUID = householdUID
+	if err := s.Validate(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err := saveGroceryStores(r.Context(), h.dao, s); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGroceryStoresValidate(t *testing.T) {
+	s := GroceryStores{
+		Stores:     []string{" Tesco", "Farmers market", ""},
+		Items:      map[string]string{" Sourdough ": "farmers MARKET"},
+		Categories: map[string]string{"Produce": "farmers market"},
+	}
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, GroceryStores{
+		Stores:     []string{"Tesco", "Farmers market"},
+		Items:      map[string]string{"sourdough": "Farmers market"},
+		Categories: map[string]string{"produce": "Farmers market"},
+	}, s)
+
+	for _, s := range []GroceryStores{
+		{Stores: []string{"Tesco", "tesco"}},
+		{Stores: []string{"Tesco"}, Items: map[string]string{"milk": "Aldi"}},
+		{Stores: []string{"Tesco"}, Categories: map[string]string{"deli": "Tesco"}},
+	} {
+		assert.Error(t, s.Validate(), s)
+	}
+}
+
+func TestShoppingListSplitByStore(t *testing.T) {
+	list := ShoppingList{Items: []ShoppingListItem{
+		{Item: "lemons", Quantity: "2", Category: "produce"},
+		{Item: "sourdough", Category: "bakery"},
+		{Item: "milk", Quantity: "1 l", Category: "dairy"},
+	}}
+	stores := GroceryStores{
+		Stores:     []string{"Tesco", "Market"},
+		Items:      map[string]string{"sourdough": "Market"},
+		Categories: map[string]string{"produce": "Market"},
+	}
+
+	assert.Equal(t, []StoreShoppingList{
+		{Store: "Tesco", Items: list.Items[2:], Aisles: []ShoppingAisle{{Category: "dairy", Items: []string{"1 l milk"}}}},
+		{Store: "Market", Items: list.Items[:2], Aisles: []ShoppingAisle{
+			{Category: "produce", Items: []string{"2 lemons"}},
+			{Category: "bakery", Items: []string{"sourdough"}},
+		}},
+	}, list.splitByStore(stores))
+
+	// Without any stores everything is on one list.
+	split := list.splitByStore(GroceryStores{})
+	assert.Len(t, split, 1)
+	assert.Equal(t, "", split[0].Store)
+	assert.Len(t, split[0].Items, 3)
+}
+
+func TestGroceryStoresHandlers(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, GroceryStoresPreferenceKey, "house-1").Return(dao.Preferences{}, errors.New("not found"))
+	prefs.On("CreatePreferences", mock.Anything, mock.MatchedBy(func(p dao.Preferences) bool {
+		return p.Key == GroceryStoresPreferenceKey && p.Specifier == "house-1" && strings.Contains(p.Data, `"items":{"bread":"Market"}`)
+	})).Return(dao.Preferences{}, nil)
+	handler := NewGroceryStores(prefs)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/house-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"household_uid": "house-1", "stores": [], "items": {}, "categories": {}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/house-1", strings.NewReader(`{"stores": ["Tesco", "Market"], "items": {"Bread": "market"}}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	prefs.AssertCalled(t, "CreatePreferences", mock.Anything, mock.Anything)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/house-1", strings.NewReader(`{"stores": ["Tesco"], "items": {"bread": "Market"}}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `\"Market\" is not one of the household's stores`)
+}
+
+func TestMCPHandlers_GroceryStores(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, GroceryStoresPreferenceKey, "house-1").Return(dao.Preferences{
+		Data: `{"stores": ["Tesco", "Market"], "items": {"bread": "Market"}, "categories": {"produce": "Market"}}`,
+	}, nil)
+	prefs.On("GetPreferences", mock.Anything, UnitsPreferenceKey, "house-1").Return(dao.Preferences{}, errors.New("not found"))
+	var saved []string
+	prefs.On("UpdatePreferences", mock.Anything, GroceryStoresPreferenceKey, "house-1", mock.Anything).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(3).(dao.Preferences).Data) }).
+		Return(dao.Preferences{}, nil)
+	recipes := &MockRecipesDAO{}
+	recipes.On("GetRecipes", mock.Anything, "r1").Return(dao.Recipes{ID: "r1", Title: "Toast", GroceryList: strPtr(`["bread", "butter", "2 tomatoes"]`)}, nil)
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, prefs, recipes, &MockUserDAO{}, &MockHouseholdDAO{})
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "split_shopping_list", map[string]any{"recipe_ids": "r1"}), &body)
+	assert.Equal(t, "3 items to buy for 1 recipes at 2 stores, 0 already in the pantry", body["summary"])
+	stores := body["stores"].([]any)
+	assert.Equal(t, "Tesco", stores[0].(map[string]any)["store"])
+	assert.Equal(t, []any{map[string]any{"category": "dairy", "items": []any{"butter"}}}, stores[0].(map[string]any)["aisles"])
+	assert.Equal(t, "Market", stores[1].(map[string]any)["store"])
+	assert.Len(t, stores[1].(map[string]any)["items"], 2)
+
+	decodeToolResult(t, h.callTool(ctx, "set_preferred_store", map[string]any{"item": "Butter", "store": "market"}), &body)
+	assert.Equal(t, "Buying butter at Market", body["summary"])
+	assert.Contains(t, saved[0], `"butter":"Market"`)
+
+	// Dropping a store drops the preferences for it.
+	decodeToolResult(t, h.callTool(ctx, "set_grocery_stores", map[string]any{"stores": "Aldi, Tesco"}), &body)
+	assert.Equal(t, "Saved stores Aldi, Tesco", body["summary"])
+	assert.JSONEq(t, `{"household_uid": "house-1", "stores": ["Aldi", "Tesco"], "items": {}, "categories": {}}`, saved[1])
+
+	for _, args := range []map[string]any{
+		{"store": "Market"},
+		{"item": "bread", "category": "bakery", "store": "Market"},
+		{"item": "bread", "store": "Aldi"},
+	} {
+		assert.True(t, h.callTool(ctx, "set_preferred_store", args).IsError, args)
+	}
+	assert.True(t, h.callTool(ctx, "set_grocery_stores", map[string]any{"stores": " , "}).IsError)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -17,7 +18,14 @@ type todoDAO interface {
 	GetTodo(ctx context.Context, uid string) (dao.Todo, error)
 	ListTodos(ctx context.Context, options dao.ListOptions) ([]dao.Todo, error)
 	UpdateTodo(ctx context.Context, uid string, t dao.UpdateTodo) (dao.Todo, error)
+	SetTodoStatus(ctx context.Context, uid string, status dao.TodoStatus, completedBy *string) (dao.Todo, error)
 	DeleteTodo(ctx context.Context, uid string) error
+	AddTodoDependency(ctx context.Context, todoUID, blockedByUID string) error
+	RemoveTodoDependency(ctx context.Context, todoUID, blockedByUID string) error
+	ListTodoDependencies(ctx context.Context, todoUID string) ([]dao.TodoDependency, error)
+	LogTodoTime(ctx context.Context, l dao.TodoTimeLog) (dao.TodoTimeLog, error)
+	ListTodoTimeLogs(ctx context.Context, todoUID string) ([]dao.TodoTimeLog, error)
+	WeeklyTodoTime(ctx context.Context, householdUID, userUID string, from, to time.Time, title string) ([]dao.TodoTimeSpent, error)
 }
 
 type todoHandlers struct{ dao todoDAO }
@@ -27,29 +35,56 @@ func NewTodos(dao todoDAO) http.Handler {
 	r := chi.NewRouter()
 	r.Use(httpLogger())
 	r.Post("/", h.create)
+	r.Post("/import", h.importCSV)
 	r.Get("/{uid}", h.get)
 	r.Put("/{uid}", h.update)
 	r.Delete("/{uid}", h.delete)
+	r.Post("/{uid}/status", h.setStatus)
+	r.Post("/{uid}/log-time", h.logTime)
+	r.Get("/{uid}/time-logs", h.timeLogs)
+	r.Get("/{uid}/dependencies", h.dependencies)
+	r.Put("/{uid}/blocked-by/{blocker}", h.addBlocker)
+	r.Delete("/{uid}/blocked-by/{blocker}", h.removeBlocker)
 	r.Get("/", h.list)
 	return r
 }
 
 type createTodoRequest struct {
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	Data         string `json:"data"`
-	Priority     int    `json:"priority"`
-	DueDate      string `json:"due_date"`
-	RecursOn     string `json:"recurs_on"`
-	ExternalURL  string `json:"external_url"`
-	UserUID      string `json:"user_uid"`
-	HouseholdUID string `json:"household_uid"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Data         string         `json:"data"`
+	Priority     dao.Priority   `json:"priority"`
+	Status       dao.TodoStatus `json:"status"`
+	DueDate      string         `json:"due_date"`
+	RecursOn     string         `json:"recurs_on"`
+	ExternalURL  string         `json:"external_url"`
+	UserUID      string         `json:"user_uid"`
+	HouseholdUID string         `json:"household_uid"`
+
+	Location        *dao.TodoLocation `json:"location"`
+	EstimateMinutes *int              `json:"estimate_minutes"`
+	ProjectUID      *string           `json:"project_uid"`
+	Tags            []string          `json:"tags"`
+}
+
+// decodeTodo decodes a todo write into v, answering 400 when it can't, with
+// the reason when the priority or status is invalid. It reports whether it
+// decoded.
+func decodeTodo(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	if errors.Is(err, dao.ErrInvalidPriority) || errors.Is(err, dao.ErrInvalidStatus) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	}
+	return false
 }
 
 func (h *todoHandlers) create(w http.ResponseWriter, r *http.Request) {
 	var todoReq createTodoRequest
-	if json.NewDecoder(r.Body).Decode(&todoReq) != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if !decodeTodo(w, r, &todoReq) {
 		return
 	}
 	var dueDate *time.Time
@@ -65,12 +100,6 @@ func (h *todoHandlers) create(w http.ResponseWriter, r *http.Request) {
 	} else {
 		dueDate = nil
 	}
-	if todoReq.Priority < 1 || todoReq.Priority > 5 {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "priority must be between 1 and 5"})
-		return
-	}
-
 	if todoReq.Data == "" {
 		todoReq.Data = "{}" // Default to empty JSON object if no data is provided
 	} else {
@@ -83,20 +112,57 @@ func (h *todoHandlers) create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	priority := dao.Priority(todoReq.Priority)
+	if err := validateTodoLocation(todoReq.Location); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid location: " + err.Error()})
+		return
+	}
+
 	t := dao.Todo{
-		Title:        todoReq.Title,
-		Description:  todoReq.Description,
-		Data:         todoReq.Data,
-		Priority:     priority,
-		DueDate:      dueDate,
-		RecursOn:     todoReq.RecursOn,
-		ExternalURL:  todoReq.ExternalURL,
-		UserUID:      &todoReq.UserUID,
-		HouseholdUID: &todoReq.HouseholdUID,
-		UID:          uuid.NewString(),
+		Title:           todoReq.Title,
+		Description:     todoReq.Description,
+		Data:            todoReq.Data,
+		Priority:        todoReq.Priority,
+		Status:          todoReq.Status,
+		DueDate:         dueDate,
+		RecursOn:        todoReq.RecursOn,
+		ExternalURL:     todoReq.ExternalURL,
+		Location:        todoReq.Location,
+		EstimateMinutes: todoReq.EstimateMinutes,
+		ProjectUID:      todoReq.ProjectUID,
+		Tags:            todoReq.Tags,
+		UserUID:         &todoReq.UserUID,
+		HouseholdUID:    &todoReq.HouseholdUID,
+		UID:             uuid.NewString(),
+	}
+	// With ?quick_add=true, the title's markers fill in what the body
+	// leaves out, with dates counted in ?timezone= (default UTC).
+	if r.URL.Query().Get("quick_add") == "true" {
+		loc := time.UTC
+		if tz := r.URL.Query().Get("timezone"); tz != "" {
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "unknown timezone " + tz})
+				return
+			}
+		}
+		quickAddTodo(&t, time.Now().In(loc))
+	}
+	if !t.Priority.Valid() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "priority is required: low, medium, high or critical (1-4)"})
+		return
 	}
 	out, err := h.dao.CreateTodo(r.Context(), t)
+	if errors.Is(err, dao.ErrInvalidStatus) || errors.Is(err, dao.ErrInvalidEstimate) || errors.Is(err, dao.ErrUnknownProject) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if quotaExceeded(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		slog.Error("failed to create todo", "error", err)
@@ -106,21 +172,32 @@ func (h *todoHandlers) create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *todoHandlers) get(w http.ResponseWriter, r *http.Request) {
-	out, err := h.dao.GetTodo(r.Context(), chi.URLParam(r, "uid"))
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Todo, error) { return h.dao.GetTodo(ctx, uid) },
+		h.dao.ListTodos, "WHERE uid = $1", uid)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		writeFieldsError(w, err, http.StatusNotFound)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, out)
 }
 
 func (h *todoHandlers) update(w http.ResponseWriter, r *http.Request) {
 	var t dao.UpdateTodo
-	if json.NewDecoder(r.Body).Decode(&t) != nil {
+	if !decodeTodo(w, r, &t) {
+		return
+	}
+	if err := validateTodoLocation(t.Location); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid location: " + err.Error()})
 		return
 	}
 	out, err := h.dao.UpdateTodo(r.Context(), chi.URLParam(r, "uid"), t)
+	if errors.Is(err, dao.ErrUnknownCompleter) || errors.Is(err, dao.ErrInvalidEstimate) || errors.Is(err, dao.ErrUnknownProject) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -138,7 +215,10 @@ func (h *todoHandlers) delete(w http.ResponseWriter, r *http.Request) {
 
 func (h *todoHandlers) list(w http.ResponseWriter, r *http.Request) {
 	params := ParseListParams(r, TodoFilters.SortFields)
-	whereClause, whereArgs := BuildWhereClause(params.Filters, TodoFilters.Filters)
+	whereClause, whereArgs, ok := whereFromParams(w, params, TodoFilters.Filters)
+	if !ok {
+		return
+	}
 
 	options := dao.ListOptions{
 		Limit:       params.Limit,
@@ -147,12 +227,17 @@ func (h *todoHandlers) list(w http.ResponseWriter, r *http.Request) {
 		SortDir:     params.SortDir,
 		WhereClause: whereClause,
 		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
 	}
 
 	out, err := h.dao.ListTodos(r.Context(), options)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeFieldsError(w, err, http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	if wantsCSV(r) {
+		writeTodosCSV(w, out, params.Fields)
+		return
+	}
+	encodeResponse(w, r, out)
 }
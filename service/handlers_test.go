@@ -28,8 +28,8 @@ func TestTodoCreate(t *testing.T) {
 		DueDate:     nil,
 		RecursOn:    "",
 		ExternalURL: "",
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -40,8 +40,8 @@ func TestTodoCreate(t *testing.T) {
 			return t.Title == "Test Todo" && 
 				   t.Description == "Test Description" &&
 				   t.Priority == postgres.PriorityMedium &&
-				   t.UserUID == "user-123" &&
-				   t.HouseholdUID == "household-456"
+				   *t.UserUID == "user-123" &&
+				   *t.HouseholdUID == "household-456"
 		})).Return(expectedTodo, nil)
 
 	handler := NewTodos(mockTodoDAO)
@@ -90,6 +90,44 @@ func TestTodoCreateInvalidJSON(t *testing.T) {
 	}
 }
 
+func TestTodoPriorityLabels(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(t postgres.Todo) bool {
+		return t.Priority == postgres.PriorityHigh
+	})).Return(postgres.Todo{UID: "todo-1", Priority: postgres.PriorityHigh}, nil).Once()
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Renew passport", "priority": "high"}`)))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"priority":"high"`) {
+		t.Errorf("Expected 200 with a high priority, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Renew passport"}`)),
+		httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Renew passport", "priority": "urgent"}`)),
+		httptest.NewRequest("PUT", "/todo-1", strings.NewReader(`{"priority": 5}`)),
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "priority") {
+			t.Errorf("%s %s: expected 400 explaining the priority, got %d: %s", req.Method, req.URL, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestTodoUpdateUnknownCompleter(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("UpdateTodo", mock.Anything, "todo-1", mock.Anything).Return(postgres.Todo{}, postgres.ErrUnknownCompleter)
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/todo-1", strings.NewReader(`{"completed_by": "Alex"}`)))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "not a known user") {
+		t.Errorf("Expected 400 naming the completer, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
 func TestTodoGet(t *testing.T) {
 	mockTodoDAO := mocks.NewMocktodoDAO(t)
 	
@@ -362,4 +400,5 @@ func TestTodoListError(t *testing.T) {
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("Expected status 500, got %d", rr.Code)
 	}
-}
\ No newline at end of file
+}
+func strPtr(s string) *string { return &s }
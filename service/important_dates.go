@@ -0,0 +1,241 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type importantDateDAO interface {
+	CreateImportantDate(ctx context.Context, i dao.ImportantDate) (dao.ImportantDate, error)
+	ListImportantDates(ctx context.Context, householdUID string) ([]dao.ImportantDate, error)
+	DeleteImportantDate(ctx context.Context, uid string) error
+}
+
+var (
+	importantDateKinds       = []string{dao.DateBirthday, dao.DateAnniversary, dao.DateRenewal, dao.DateOther}
+	importantDateRecurrences = []string{dao.RecursYearly, dao.RecursMonthly, dao.RecursNever}
+)
+
+// defaultRemindDaysAhead is how many days ahead a date shows in the
+// briefing unless it says otherwise.
+const defaultRemindDaysAhead = 7
+
+// importantDateRequest is an important date as clients send it. Kind
+// defaults to other, Recurrence to yearly and RemindDaysAhead to
+// defaultRemindDaysAhead.
+type importantDateRequest struct {
+	HouseholdUID string `json:"household_uid"`
+	Title        string `json:"title"`
+	Kind         string `json:"kind"`
+	// Date is the first occurrence, like 1990-04-17.
+	Date            string `json:"date"`
+	Recurrence      string `json:"recurrence"`
+	RemindDaysAhead *int   `json:"remind_days_ahead"`
+	Notes           string `json:"notes"`
+}
+
+func (in importantDateRequest) toImportantDate() (dao.ImportantDate, error) {
+	out := dao.ImportantDate{
+		HouseholdUID:    in.HouseholdUID,
+		Title:           strings.TrimSpace(in.Title),
+		Kind:            cmp.Or(in.Kind, dao.DateOther),
+		Recurrence:      cmp.Or(in.Recurrence, dao.RecursYearly),
+		RemindDaysAhead: defaultRemindDaysAhead,
+		Notes:           in.Notes,
+	}
+	if in.RemindDaysAhead != nil {
+		out.RemindDaysAhead = *in.RemindDaysAhead
+	}
+	date, err := time.Parse(time.DateOnly, in.Date)
+	switch {
+	case out.HouseholdUID == "":
+		return out, errors.New("household_uid is required")
+	case out.Title == "":
+		return out, errors.New("title is required")
+	case err != nil:
+		return out, errors.New("date must be a date like 1990-04-17")
+	case !slices.Contains(importantDateKinds, out.Kind):
+		return out, fmt.Errorf("kind must be one of %s", strings.Join(importantDateKinds, ", "))
+	case !slices.Contains(importantDateRecurrences, out.Recurrence):
+		return out, fmt.Errorf("recurrence must be one of %s", strings.Join(importantDateRecurrences, ", "))
+	case out.RemindDaysAhead < 0 || out.RemindDaysAhead > 365:
+		return out, errors.New("remind_days_ahead must be between 0 and 365")
+	}
+	out.Date = date
+	return out, nil
+}
+
+// onInYear is date's anniversary in year. A 29 February falls on 28
+// February in other years.
+func onInYear(date time.Time, year int) time.Time {
+	return onInMonth(date, year, date.Month())
+}
+
+// onInMonth is date's day of the month in year and month, or the month's
+// last day when it is shorter.
+func onInMonth(date time.Time, year int, month time.Month) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month, min(date.Day(), last), 0, 0, 0, 0, time.UTC)
+}
+
+// daysUntil counts the days from today to day, both midnight UTC.
+func daysUntil(today, day time.Time) int {
+	return int(day.Sub(today).Hours() / 24)
+}
+
+// nextOccurrence is the first day on or after today that d comes round,
+// or false if it never will again.
+func nextOccurrence(d dao.ImportantDate, today time.Time) (time.Time, bool) {
+	if !d.Date.Before(today) {
+		return d.Date, true
+	}
+	var next time.Time
+	switch d.Recurrence {
+	case dao.RecursYearly:
+		if next = onInYear(d.Date, today.Year()); next.Before(today) {
+			next = onInYear(d.Date, today.Year()+1)
+		}
+	case dao.RecursMonthly:
+		if next = onInMonth(d.Date, today.Year(), today.Month()); next.Before(today) {
+			next = onInMonth(d.Date, today.Year(), today.Month()+1)
+		}
+	default:
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// upcomingDate is an important date coming round soon.
+type upcomingDate struct {
+	dao.ImportantDate
+	On time.Time `json:"on"`
+	// InDays is 0 for today.
+	InDays int `json:"in_days"`
+	// Years is how many years a yearly date marks on this occurrence, such
+	// as the age a birthday person turns.
+	Years int `json:"years,omitempty"`
+}
+
+// upcomingImportantDates returns the dates that come round within their
+// own reminder window of now's date, soonest first.
+func upcomingImportantDates(dates []dao.ImportantDate, now time.Time) []upcomingDate {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	out := []upcomingDate{}
+	for _, d := range dates {
+		on, ok := nextOccurrence(d, today)
+		if !ok || daysUntil(today, on) > d.RemindDaysAhead {
+			continue
+		}
+		u := upcomingDate{ImportantDate: d, On: on, InDays: daysUntil(today, on)}
+		if d.Recurrence == dao.RecursYearly {
+			u.Years = on.Year() - d.Date.Year()
+		}
+		out = append(out, u)
+	}
+	slices.SortStableFunc(out, func(a, b upcomingDate) int { return a.InDays - b.InDays })
+	return out
+}
+
+type ImportantDateHandlers struct{ dao importantDateDAO }
+
+// NewImportantDates manages households' important dates. Callers with an
+// API key manage their own household's.
+func NewImportantDates(dao importantDateDAO) http.Handler {
+	h := &ImportantDateHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Delete("/{uid}", h.delete)
+	return r
+}
+
+func (h *ImportantDateHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var in importantDateRequest
+	if json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		if in.HouseholdUID != "" && in.HouseholdUID != id.HouseholdUID {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		in.HouseholdUID = id.HouseholdUID
+	}
+	d, err := in.toImportantDate()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateImportantDate(r.Context(), d)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// list returns a household's dates: the caller's own with an API key, or
+// ?household_uid= for operators.
+func (h *ImportantDateHandlers) list(w http.ResponseWriter, r *http.Request) {
+	household := r.URL.Query().Get("household_uid")
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		household = id.HouseholdUID
+	}
+	if household == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.ListImportantDates(r.Context(), household)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *ImportantDateHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteImportantDate(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MCPHandlers) handleAddImportantDate(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	var in importantDateRequest
+	in.HouseholdUID, _ = arguments["household_uid"].(string)
+	in.Title, _ = arguments["title"].(string)
+	in.Kind, _ = arguments["kind"].(string)
+	in.Date, _ = arguments["date"].(string)
+	in.Recurrence, _ = arguments["recurrence"].(string)
+	in.Notes, _ = arguments["notes"].(string)
+	if days, ok := arguments["remind_days_ahead"].(float64); ok {
+		n := int(days)
+		in.RemindDaysAhead = &n
+	}
+	d, err := in.toImportantDate()
+	if err != nil {
+		return toolError("%v", err)
+	}
+	created, err := h.datesDAO.CreateImportantDate(ctx, d)
+	if err != nil {
+		return toolError("Failed to add important date: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Added %s on %s", created.Title, created.Date.Format(time.DateOnly)), map[string]any{"important_date": created})
+}
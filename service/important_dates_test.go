@@ -0,0 +1,161 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func day(s string) time.Time {
+	d, _ := time.Parse(time.DateOnly, s)
+	return d
+}
+
+func TestImportantDateRequest(t *testing.T) {
+	d, err := importantDateRequest{HouseholdUID: "house-1", Title: " Car insurance ", Date: "2024-03-31"}.toImportantDate()
+	require.NoError(t, err)
+	assert.Equal(t, postgres.ImportantDate{
+		HouseholdUID:    "house-1",
+		Title:           "Car insurance",
+		Kind:            postgres.DateOther,
+		Date:            day("2024-03-31"),
+		Recurrence:      postgres.RecursYearly,
+		RemindDaysAhead: defaultRemindDaysAhead,
+	}, d)
+
+	for in, want := range map[importantDateRequest]string{
+		{Title: "Gran", Date: "2024-03-31"}:                                                "household_uid is required",
+		{HouseholdUID: "house-1", Date: "2024-03-31"}:                                      "title is required",
+		{HouseholdUID: "house-1", Title: "Gran", Date: "31 March"}:                         "date must be a date",
+		{HouseholdUID: "house-1", Title: "Gran", Date: "2024-03-31", Kind: "holiday"}:      "kind must be one of birthday",
+		{HouseholdUID: "house-1", Title: "Gran", Date: "2024-03-31", Recurrence: "weekly"}: "recurrence must be one of yearly",
+	} {
+		_, err := in.toImportantDate()
+		assert.ErrorContains(t, err, want)
+	}
+	days := 400
+	_, err = importantDateRequest{HouseholdUID: "house-1", Title: "Gran", Date: "2024-03-31", RemindDaysAhead: &days}.toImportantDate()
+	assert.ErrorContains(t, err, "remind_days_ahead")
+}
+
+func TestUpcomingImportantDates(t *testing.T) {
+	dates := []postgres.ImportantDate{
+		{UID: "gran", Kind: postgres.DateBirthday, Date: day("1941-10-20"), Recurrence: postgres.RecursYearly, RemindDaysAhead: 7},
+		{UID: "wedding", Kind: postgres.DateAnniversary, Date: day("2010-10-16"), Recurrence: postgres.RecursYearly, RemindDaysAhead: 3},
+		{UID: "rent", Date: day("2025-01-31"), Recurrence: postgres.RecursMonthly, RemindDaysAhead: 3},
+		{UID: "passport", Kind: postgres.DateRenewal, Date: day("2025-11-01"), Recurrence: postgres.RecursNever, RemindDaysAhead: 30},
+		{UID: "mot", Kind: postgres.DateRenewal, Date: day("2025-10-01"), Recurrence: postgres.RecursNever, RemindDaysAhead: 30},
+		{UID: "far", Date: day("2000-12-25"), Recurrence: postgres.RecursYearly, RemindDaysAhead: 7},
+	}
+	now := time.Date(2025, 10, 15, 18, 0, 0, 0, time.UTC)
+	got := upcomingImportantDates(dates, now)
+	var uids []string
+	for _, u := range got {
+		uids = append(uids, u.UID)
+	}
+	assert.Equal(t, []string{"wedding", "gran", "passport"}, uids)
+	assert.Equal(t, upcomingDate{ImportantDate: dates[1], On: day("2025-10-16"), InDays: 1, Years: 15}, got[0])
+	assert.Equal(t, 84, got[1].Years)
+	assert.Equal(t, 0, got[2].Years)
+
+	// A monthly date on the 31st falls on the last day of shorter months.
+	got = upcomingImportantDates(dates[2:3], time.Date(2025, 11, 28, 9, 0, 0, 0, time.UTC))
+	require.Len(t, got, 1)
+	assert.Equal(t, day("2025-11-30"), got[0].On)
+	got = upcomingImportantDates(dates[2:3], time.Date(2025, 12, 31, 9, 0, 0, 0, time.UTC))
+	require.Len(t, got, 1)
+	assert.Equal(t, 0, got[0].InDays)
+}
+
+func TestImportantDateHandlers(t *testing.T) {
+	d := mocks.NewMockimportantDateDAO(t)
+	d.On("CreateImportantDate", mock.Anything, mock.MatchedBy(func(i postgres.ImportantDate) bool {
+		return i.HouseholdUID == "house-1" && i.Kind == postgres.DateBirthday
+	})).Return(postgres.ImportantDate{UID: "d1"}, nil)
+	d.On("ListImportantDates", mock.Anything, "house-1").Return([]postgres.ImportantDate{{UID: "d1"}}, nil)
+	d.On("DeleteImportantDate", mock.Anything, "d1").Return(nil)
+	handler := NewImportantDates(d)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(identityContext("user-1", "house-1")))
+		return rr
+	}
+
+	rr := serve(httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Gran", "kind": "birthday", "date": "1941-10-20"}`)))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = serve(httptest.NewRequest("POST", "/", strings.NewReader(`{"household_uid": "house-2", "title": "Gran", "date": "1941-10-20"}`)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serve(httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Gran", "date": "1941-10-20", "recurrence": "weekly"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "recurrence must be one of")
+
+	// Members list their own household's, whatever they ask for.
+	rr = serve(httptest.NewRequest("GET", "/?household_uid=house-2", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"d1"`)
+
+	rr = serve(httptest.NewRequest("DELETE", "/d1", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestMCPHandlers_AddImportantDate(t *testing.T) {
+	d := mocks.NewMockimportantDateDAO(t)
+	d.On("CreateImportantDate", mock.Anything, postgres.ImportantDate{
+		HouseholdUID:    "house-1",
+		Title:           "Car insurance renewal",
+		Kind:            postgres.DateRenewal,
+		Date:            day("2026-02-01"),
+		Recurrence:      postgres.RecursYearly,
+		RemindDaysAhead: 21,
+	}).Return(postgres.ImportantDate{UID: "d1", Title: "Car insurance renewal", Date: day("2026-02-01")}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithImportantDates(d))
+	ctx := identityContext("user-1", "house-1")
+	result := h.callTool(ctx, "add_important_date", map[string]any{
+		"title": "Car insurance renewal", "date": "2026-02-01", "kind": "renewal", "remind_days_ahead": float64(21),
+	})
+	require.False(t, result.IsError)
+	var body map[string]any
+	decodeToolResult(t, result, &body)
+	assert.Equal(t, "d1", body["important_date"].(map[string]any)["uid"])
+
+	assert.True(t, h.callTool(ctx, "add_important_date", map[string]any{"title": "Gran", "date": "tomorrow"}).IsError)
+	assert.True(t, h.callTool(ctx, "add_important_date", map[string]any{"title": "Gran", "date": "1941-10-20", "household_uid": "house-2"}).IsError)
+
+	withoutDates := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutDates.findTool("add_important_date")
+	assert.False(t, ok)
+}
+
+func TestMCPHandlers_GetBriefingImportantDates(t *testing.T) {
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "user-1").Return(postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}, nil)
+	mockHouseholdDAO := &MockHouseholdDAO{}
+	mockHouseholdDAO.On("GetHousehold", mock.Anything, "house-1").Return(postgres.Households{UID: "house-1"}, nil)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	today := time.Now().UTC()
+	d := mocks.NewMockimportantDateDAO(t)
+	d.On("ListImportantDates", mock.Anything, "house-1").Return([]postgres.ImportantDate{
+		{UID: "soon", Title: "Gran's birthday", Date: today.AddDate(-80, 0, 2), Recurrence: postgres.RecursYearly, RemindDaysAhead: 7},
+		{UID: "later", Title: "Wedding anniversary", Date: today.AddDate(-10, 0, 20), Recurrence: postgres.RecursYearly, RemindDaysAhead: 7},
+	}, nil)
+
+	h := NewMCP(mockTodoDAO, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, mockHouseholdDAO, WithImportantDates(d))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_briefing", map[string]any{}), &body)
+	require.Len(t, body["important_dates"], 1)
+	assert.Equal(t, "Gran's birthday", body["important_dates"].([]any)[0].(map[string]any)["title"])
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+const (
+	// jobLease is how long a run may take. A run is cancelled once it has
+	// had that long, and one still marked running after it is presumed to
+	// have lost its worker and is claimed again.
+	jobLease = time.Hour
+	// jobRetryDelay is how long after its first failed attempt a run is
+	// retried, doubling with each attempt after.
+	jobRetryDelay = time.Minute
+	// jobBusyDelay is how long a run is put off while another run of its
+	// job is in progress.
+	jobBusyDelay = 30 * time.Second
+)
+
+var ErrUnknownJob = errors.New("unknown job")
+
+type jobDAO interface {
+	EnqueueJobRun(ctx context.Context, r dao.JobRun) (dao.JobRun, error)
+	ClaimJobRun(ctx context.Context, staleBefore time.Time) (dao.JobRun, error)
+	FinishJobRun(ctx context.Context, uid, status string, lastError *string, retryAt *time.Time) (dao.JobRun, error)
+	PostponeJobRun(ctx context.Context, uid string, runAt time.Time) (dao.JobRun, error)
+	LockJob(ctx context.Context, name string) (unlock func(), ok bool, err error)
+	GetJobRun(ctx context.Context, uid string) (dao.JobRun, error)
+	ListJobRuns(ctx context.Context, options dao.ListOptions) ([]dao.JobRun, error)
+}
+
+// Job is a kind of background work. A job with a Schedule is queued at each
+// of its times; others only run when queued with Enqueue. A failed run is
+// retried until it has had MaxAttempts, so jobs that mustn't repeat work,
+// such as sending messages, should leave it at 1.
+type Job struct {
+	Name        string
+	Schedule    Schedule
+	MaxAttempts int
+	Run         func(ctx context.Context, run dao.JobRun) error
+}
+
+// Jobs is the background job queue. Runs are kept in the database, where
+// the workers of any server can claim them, and stay there afterwards as a
+// history of what ran and how it went.
+type Jobs struct {
+	dao     jobDAO
+	jobs    map[string]Job
+	workers int
+	poll    time.Duration
+	now     func() time.Time
+}
+
+// NewJobs runs queued jobs on workers goroutines, checking the queue every
+// poll when it is empty.
+func NewJobs(dao jobDAO, workers int, poll time.Duration) *Jobs {
+	return &Jobs{dao: dao, jobs: map[string]Job{}, workers: max(workers, 1), poll: poll, now: time.Now}
+}
+
+// Register adds a job. It must be called before Run.
+func (j *Jobs) Register(job Job) {
+	job.MaxAttempts = max(job.MaxAttempts, 1)
+	j.jobs[job.Name] = job
+}
+
+// SetSchedule replaces a registered job's schedule.
+func (j *Jobs) SetSchedule(name string, s Schedule) error {
+	job, ok := j.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	job.Schedule = s
+	j.jobs[name] = job
+	return nil
+}
+
+// Enqueue queues a run of a registered job for runAt, with payload as its
+// JSON payload.
+func (j *Jobs) Enqueue(ctx context.Context, name string, payload any, runAt time.Time) (dao.JobRun, error) {
+	job, ok := j.jobs[name]
+	if !ok {
+		return dao.JobRun{}, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	run := dao.JobRun{Name: name, MaxAttempts: job.MaxAttempts, RunAt: runAt}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return dao.JobRun{}, err
+		}
+		run.Payload = data
+	}
+	return j.dao.EnqueueJobRun(ctx, run)
+}
+
+// Run queues the scheduled jobs' runs and works through the queue until ctx
+// is done. Runs due while no server is running are skipped, not queued
+// late, but a run that was queued is run however late it is claimed.
+func (j *Jobs) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range j.jobs {
+		if job.Schedule != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				j.schedule(ctx, job)
+			}()
+		}
+	}
+	for range j.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// schedule queues a run of job at each of its scheduled times. Every
+// server queues it under the same key, so it is only queued once.
+func (j *Jobs) schedule(ctx context.Context, job Job) {
+	for {
+		next := job.Schedule.Next(j.now())
+		if next.IsZero() {
+			slog.Error("Job schedule never runs", "job", job.Name, "schedule", job.Schedule.String())
+			return
+		}
+		timer := time.NewTimer(next.Sub(j.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		key := job.Name + "@" + next.Format(time.RFC3339)
+		_, err := j.dao.EnqueueJobRun(ctx, dao.JobRun{Name: job.Name, DedupeKey: &key, MaxAttempts: job.MaxAttempts, RunAt: next})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to queue scheduled job", "job", job.Name, "run_at", next, "error", err)
+		}
+	}
+}
+
+func (j *Jobs) work(ctx context.Context) {
+	for {
+		for j.RunNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(j.poll):
+		}
+	}
+}
+
+// RunNext claims a due run and runs it, reporting whether there was one.
+// Runs of the same job never overlap, on this server or any other: while
+// one is in progress, the next is put off.
+func (j *Jobs) RunNext(ctx context.Context) bool {
+	run, err := j.dao.ClaimJobRun(ctx, j.now().Add(-jobLease))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			slog.Error("Failed to claim job run", "error", err)
+		}
+		return false
+	}
+	unlock, ok, err := j.dao.LockJob(ctx, run.Name)
+	if err != nil || !ok {
+		if err != nil {
+			slog.Error("Failed to lock job", "job", run.Name, "error", err)
+		}
+		if _, err := j.dao.PostponeJobRun(context.WithoutCancel(ctx), run.UID, j.now().Add(jobBusyDelay)); err != nil {
+			slog.Error("Failed to postpone job run", "job", run.Name, "uid", run.UID, "error", err)
+		}
+		return true
+	}
+	defer unlock()
+	j.finish(ctx, run, j.execute(ctx, run))
+	return true
+}
+
+func (j *Jobs) execute(ctx context.Context, run dao.JobRun) (err error) {
+	job, ok := j.jobs[run.Name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, run.Name)
+	}
+	// Only a run reclaimed from a dead worker can have had too many.
+	if run.Attempts > run.MaxAttempts {
+		return fmt.Errorf("abandoned after %d attempts", run.MaxAttempts)
+	}
+	ctx, cancel := context.WithTimeout(ctx, jobLease)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx, run)
+}
+
+// finish records how an attempt went, queueing the run again, after a
+// backoff, if it failed and has attempts left.
+func (j *Jobs) finish(ctx context.Context, run dao.JobRun, err error) {
+	// A run cut short by shutdown is still recorded.
+	ctx = context.WithoutCancel(ctx)
+	status := dao.JobSucceeded
+	var lastError *string
+	var retryAt *time.Time
+	if err != nil {
+		slog.Error("Job run failed", "job", run.Name, "uid", run.UID, "attempt", run.Attempts, "error", err)
+		msg := err.Error()
+		lastError = &msg
+		status = dao.JobFailed
+		if run.Attempts < run.MaxAttempts {
+			at := j.now().Add(jobRetryDelay << (run.Attempts - 1))
+			status, retryAt = dao.JobQueued, &at
+		}
+	}
+	if _, err := j.dao.FinishJobRun(ctx, run.UID, status, lastError, retryAt); err != nil {
+		slog.Error("Failed to record job run", "job", run.Name, "uid", run.UID, "error", err)
+	}
+}
+
+// JobInfo describes a registered job: when it runs next, if it is
+// scheduled, and how its latest run went.
+type JobInfo struct {
+	Name        string      `json:"name"`
+	Schedule    string      `json:"schedule,omitempty"`
+	NextRunAt   *time.Time  `json:"next_run_at,omitempty"`
+	MaxAttempts int         `json:"max_attempts"`
+	LastRun     *dao.JobRun `json:"last_run"`
+}
+
+// Info describes the registered jobs, by name.
+func (j *Jobs) Info(ctx context.Context) ([]JobInfo, error) {
+	out := []JobInfo{}
+	for _, name := range slices.Sorted(maps.Keys(j.jobs)) {
+		job := j.jobs[name]
+		info := JobInfo{Name: job.Name, MaxAttempts: job.MaxAttempts}
+		if job.Schedule != nil {
+			info.Schedule = job.Schedule.String()
+			if next := job.Schedule.Next(j.now()); !next.IsZero() {
+				info.NextRunAt = &next
+			}
+		}
+		runs, err := j.dao.ListJobRuns(ctx, dao.ListOptions{
+			Limit:       1,
+			SortBy:      "created_at",
+			SortDir:     "DESC",
+			WhereClause: "WHERE name = $1",
+			WhereArgs:   []any{job.Name},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			info.LastRun = &runs[0]
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+var jobStatuses = []string{dao.JobQueued, dao.JobRunning, dao.JobSucceeded, dao.JobFailed}
+
+type JobHandlers struct{ jobs *Jobs }
+
+// NewJobsAdmin lets operators see the background jobs, their recent runs
+// and failures, and queue a run by hand. Callers with an API key are
+// turned away.
+func NewJobsAdmin(jobs *Jobs) http.Handler {
+	h := &JobHandlers{jobs}
+	r := chi.NewRouter()
+	r.Use(httpLogger(), operatorsOnly)
+	r.Get("/", h.info)
+	r.Get("/runs", h.listRuns)
+	r.Get("/runs/{uid}", h.getRun)
+	r.Post("/{name}/run", h.enqueue)
+	return r
+}
+
+func (h *JobHandlers) info(w http.ResponseWriter, r *http.Request) {
+	out, err := h.jobs.Info(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *JobHandlers) listRuns(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, JobRunFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, JobRunFilters.Filters)
+	if !ok {
+		return
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !slices.Contains(jobStatuses, status) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "status must be queued, running, succeeded or failed"})
+			return
+		}
+		whereClause, whereArgs = withStatus(whereClause, whereArgs, status)
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.jobs.dao.ListJobRuns(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *JobHandlers) getRun(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.JobRun, error) { return h.jobs.dao.GetJobRun(ctx, uid) },
+		h.jobs.dao.ListJobRuns, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// enqueue queues a run of a job now. The body, if any, is its payload.
+func (h *JobHandlers) enqueue(w http.ResponseWriter, r *http.Request) {
+	var payload json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var body any
+	if payload != nil {
+		body = payload
+	}
+	out, err := h.jobs.Enqueue(r.Context(), chi.URLParam(r, "name"), body, time.Now())
+	if errors.Is(err, ErrUnknownJob) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(out)
+}
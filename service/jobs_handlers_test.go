@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJobsAdmin(t *testing.T) {
+	d := mocks.NewMockjobDAO(t)
+	d.On("ListJobRuns", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE name = $1 AND status = $2" && o.WhereArgs[1] == "failed"
+	})).Return([]postgres.JobRun{{UID: "run-1", Name: "digests", Status: postgres.JobFailed}}, nil)
+	d.On("EnqueueJobRun", mock.Anything, mock.MatchedBy(func(r postgres.JobRun) bool {
+		return r.Name == "digests" && string(r.Payload) == `{"dry_run":true}`
+	})).Return(postgres.JobRun{UID: "run-2", Name: "digests", Status: postgres.JobQueued}, nil)
+	jobs := NewJobs(d, 1, 0)
+	jobs.Register(Job{Name: "digests", Run: func(context.Context, postgres.JobRun) error { return nil }})
+	handler := NewJobsAdmin(jobs)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, asOperator(httptest.NewRequest(method, target, strings.NewReader(body))))
+		return rr
+	}
+	rr := serve("GET", "/runs?name=digests&status=failed", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"run-1"`)
+	assert.Equal(t, http.StatusBadRequest, serve("GET", "/runs?status=done", "").Code)
+
+	rr = serve("POST", "/digests/run", `{"dry_run": true}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"run-2"`)
+	assert.Equal(t, http.StatusNotFound, serve("POST", "/sync/run", "").Code)
+
+	req := httptest.NewRequest("GET", "/runs", nil)
+	req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
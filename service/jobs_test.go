@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestJobsRunNext(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+	failing := errors.New("smtp down")
+	for _, tc := range []struct {
+		name    string
+		run     postgres.JobRun
+		err     error
+		status  string
+		retryAt *time.Time
+		lastErr string
+	}{
+		{name: "succeeds", run: postgres.JobRun{Name: "digests", Attempts: 1, MaxAttempts: 1}, status: postgres.JobSucceeded},
+		{name: "fails", run: postgres.JobRun{Name: "digests", Attempts: 1, MaxAttempts: 1}, err: failing, status: postgres.JobFailed, lastErr: "smtp down"},
+		{name: "retries", run: postgres.JobRun{Name: "digests", Attempts: 2, MaxAttempts: 3}, err: failing, status: postgres.JobQueued,
+			retryAt: timePtr(now.Add(2 * time.Minute)), lastErr: "smtp down"},
+		{name: "unknown", run: postgres.JobRun{Name: "sync", Attempts: 1, MaxAttempts: 3}, status: postgres.JobQueued,
+			retryAt: timePtr(now.Add(time.Minute)), lastErr: "unknown job: sync"},
+		{name: "abandoned", run: postgres.JobRun{Name: "digests", Attempts: 2, MaxAttempts: 1}, status: postgres.JobFailed, lastErr: "abandoned after 1 attempts"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := mocks.NewMockjobDAO(t)
+			tc.run.UID = "run-1"
+			unlocked := false
+			d.On("ClaimJobRun", mock.Anything, now.Add(-jobLease)).Return(tc.run, nil)
+			d.On("LockJob", mock.Anything, tc.run.Name).Return(func() { unlocked = true }, true, nil)
+			d.On("FinishJobRun", mock.Anything, "run-1", tc.status, mock.MatchedBy(func(e *string) bool {
+				return (e == nil && tc.lastErr == "") || (e != nil && *e == tc.lastErr)
+			}), tc.retryAt).Return(postgres.JobRun{}, nil)
+			jobs := NewJobs(d, 1, time.Second)
+			jobs.now = func() time.Time { return now }
+			jobs.Register(Job{Name: "digests", Run: func(context.Context, postgres.JobRun) error { return tc.err }})
+
+			assert.True(t, jobs.RunNext(t.Context()))
+			assert.True(t, unlocked)
+		})
+	}
+}
+
+func TestJobsRunNextPostponesBusyJobs(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+	d := mocks.NewMockjobDAO(t)
+	d.On("ClaimJobRun", mock.Anything, mock.Anything).Return(postgres.JobRun{UID: "run-1", Name: "digests", Attempts: 1, MaxAttempts: 1}, nil)
+	d.On("LockJob", mock.Anything, "digests").Return(nil, false, nil)
+	d.On("PostponeJobRun", mock.Anything, "run-1", now.Add(jobBusyDelay)).Return(postgres.JobRun{}, nil)
+	jobs := NewJobs(d, 1, time.Second)
+	jobs.now = func() time.Time { return now }
+	jobs.Register(Job{Name: "digests", Run: func(context.Context, postgres.JobRun) error {
+		t.Error("Expected a busy job not to run")
+		return nil
+	}})
+
+	assert.True(t, jobs.RunNext(t.Context()))
+}
+
+func TestJobsRunNextRecoversPanics(t *testing.T) {
+	d := mocks.NewMockjobDAO(t)
+	d.On("ClaimJobRun", mock.Anything, mock.Anything).Return(postgres.JobRun{UID: "run-1", Name: "boom", Attempts: 1, MaxAttempts: 1}, nil)
+	d.On("LockJob", mock.Anything, "boom").Return(func() {}, true, nil)
+	d.On("FinishJobRun", mock.Anything, "run-1", postgres.JobFailed, mock.MatchedBy(func(e *string) bool { return *e == "panic: oops" }), (*time.Time)(nil)).
+		Return(postgres.JobRun{}, nil)
+	jobs := NewJobs(d, 1, time.Second)
+	jobs.Register(Job{Name: "boom", Run: func(context.Context, postgres.JobRun) error { panic("oops") }})
+
+	assert.True(t, jobs.RunNext(t.Context()))
+}
+
+func TestJobsRunNextWithEmptyQueue(t *testing.T) {
+	d := mocks.NewMockjobDAO(t)
+	d.On("ClaimJobRun", mock.Anything, mock.Anything).Return(postgres.JobRun{}, pgx.ErrNoRows)
+
+	assert.False(t, NewJobs(d, 1, time.Second).RunNext(t.Context()))
+}
+
+func TestJobsScheduleQueuesOncePerTime(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 59, 59, 990_000_000, time.UTC)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	d := mocks.NewMockjobDAO(t)
+	d.On("EnqueueJobRun", mock.Anything, mock.MatchedBy(func(r postgres.JobRun) bool {
+		return r.Name == "retention" && *r.DedupeKey == "retention@2025-08-15T11:00:00Z" && r.MaxAttempts == 3 &&
+			r.RunAt.Equal(time.Date(2025, 8, 15, 11, 0, 0, 0, time.UTC))
+	})).Run(func(mock.Arguments) { cancel() }).Return(postgres.JobRun{}, pgx.ErrNoRows)
+	jobs := NewJobs(d, 1, time.Second)
+	jobs.now = func() time.Time { return now }
+	jobs.Register(Job{Name: "retention", Schedule: Every(time.Hour), MaxAttempts: 3, Run: func(context.Context, postgres.JobRun) error { return nil }})
+
+	jobs.schedule(ctx, jobs.jobs["retention"])
+}
+
+func TestJobsEnqueue(t *testing.T) {
+	d := mocks.NewMockjobDAO(t)
+	d.On("EnqueueJobRun", mock.Anything, mock.MatchedBy(func(r postgres.JobRun) bool {
+		return r.Name == "weekly_reviews" && r.MaxAttempts == 1 && string(r.Payload) == `{"household_uid":"house-1"}` && r.DedupeKey == nil
+	})).Return(postgres.JobRun{UID: "run-1"}, nil)
+	jobs := NewJobs(d, 1, time.Second)
+	jobs.Register(Job{Name: "weekly_reviews", Run: func(context.Context, postgres.JobRun) error { return nil }})
+
+	run, err := jobs.Enqueue(t.Context(), "weekly_reviews", map[string]string{"household_uid": "house-1"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", run.UID)
+	_, err = jobs.Enqueue(t.Context(), "sync", nil, time.Now())
+	assert.ErrorIs(t, err, ErrUnknownJob)
+	assert.ErrorIs(t, jobs.SetSchedule("sync", Every(time.Hour)), ErrUnknownJob)
+}
+
+func TestJobsInfo(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 20, 0, 0, time.UTC)
+	d := mocks.NewMockjobDAO(t)
+	d.On("ListJobRuns", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool { return o.WhereArgs[0] == "digests" })).
+		Return([]postgres.JobRun{{UID: "run-1", Status: postgres.JobFailed}}, nil)
+	d.On("ListJobRuns", mock.Anything, mock.Anything).Return([]postgres.JobRun{}, nil)
+	jobs := NewJobs(d, 1, time.Second)
+	jobs.now = func() time.Time { return now }
+	jobs.Register(Job{Name: "digests", Schedule: dailyAt(7)})
+	jobs.Register(Job{Name: "backfill"})
+
+	info, err := jobs.Info(t.Context())
+	require.NoError(t, err)
+	require.Len(t, info, 2)
+	assert.Equal(t, "backfill", info[0].Name)
+	assert.Nil(t, info[0].NextRunAt)
+	assert.Nil(t, info[0].LastRun)
+	assert.Equal(t, "0 7 * * *", info[1].Schedule)
+	assert.Equal(t, time.Date(2025, 8, 16, 7, 0, 0, 0, time.UTC), *info[1].NextRunAt)
+	assert.Equal(t, postgres.JobFailed, info[1].LastRun.Status)
+}
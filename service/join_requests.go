@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+type joinRequestDAO interface {
+	CreateJoinRequest(ctx context.Context, householdUID, userUID, message string) (dao.JoinRequest, error)
+	GetJoinRequest(ctx context.Context, uid string) (dao.JoinRequest, error)
+	ListJoinRequests(ctx context.Context, householdUID, status string) ([]dao.JoinRequest, error)
+	DecideJoinRequest(ctx context.Context, uid, status, decidedBy string) (dao.JoinRequest, error)
+}
+
+// MembershipNotifier tells a household about a request to join it, and the
+// requester what became of it.
+type MembershipNotifier interface {
+	HouseholdNotifier
+	NotifyUser(ctx context.Context, userUID, category string, p notify.Push) error
+}
+
+type JoinRequestHandlers struct {
+	dao      joinRequestDAO
+	notifier MembershipNotifier
+}
+
+// NewJoinRequests lets users ask to join a household, and its members
+// approve or deny them; a user only becomes a member once approved.
+// Operators, calling without an API key, may decide any request. notifier
+// may be nil, in which case nobody is told.
+func NewJoinRequests(d joinRequestDAO, notifier MembershipNotifier) http.Handler {
+	h := &JoinRequestHandlers{dao: d, notifier: notifier}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.With(requireIdentity).Post("/", h.create)
+	r.Get("/", h.list)
+	r.Get("/{uid}", h.get)
+	r.Post("/{uid}/approve", h.decide(dao.JoinApproved))
+	r.Post("/{uid}/deny", h.decide(dao.JoinDenied))
+	return r
+}
+
+func (h *JoinRequestHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		HouseholdUID string `json:"household_uid"`
+		Message      string `json:"message"`
+	}
+	if json.NewDecoder(r.Body).Decode(&in) != nil || in.HouseholdUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	id, _ := IdentityFromContext(r.Context())
+	if in.HouseholdUID == id.HouseholdUID {
+		writeJoinError(w, http.StatusBadRequest, "you are already a member of this household")
+		return
+	}
+	req, err := h.dao.CreateJoinRequest(r.Context(), in.HouseholdUID, id.UserUID, in.Message)
+	switch {
+	case errors.Is(err, dao.ErrJoinRequestPending):
+		writeJoinError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, dao.ErrUnknownHousehold):
+		writeJoinError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if h.notifier != nil {
+		name := req.UserName
+		if name == "" {
+			name = "Someone"
+		}
+		err := h.notifier.NotifyHousehold(unscoped(r.Context()), req.HouseholdUID, CategoryHouseholdJoins, notify.Push{
+			Title: name + " asked to join your household",
+			Body:  req.Message,
+			Data:  map[string]string{"join_request_uid": req.UID},
+		})
+		if err != nil {
+			slog.Error("Failed to send join request notification", "join_request_uid", req.UID, "error", err)
+		}
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(req)
+}
+
+// list returns the caller's own requests and those to join their
+// household, or every request for operators. ?household_uid= and ?status=
+// narrow it.
+func (h *JoinRequestHandlers) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	out, err := h.dao.ListJoinRequests(r.Context(), q.Get("household_uid"), q.Get("status"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *JoinRequestHandlers) get(w http.ResponseWriter, r *http.Request) {
+	req, err := h.dao.GetJoinRequest(r.Context(), chi.URLParam(r, "uid"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(req)
+}
+
+// decide approves or denies a pending request. Only the household's
+// members, not the requester, may decide it; operators may decide any.
+func (h *JoinRequestHandlers) decide(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := IdentityFromContext(r.Context())
+		if !ok && !IsOperator(r.Context()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req, err := h.dao.GetJoinRequest(r.Context(), chi.URLParam(r, "uid"))
+		if errors.Is(err, pgx.ErrNoRows) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var decidedBy string
+		if ok {
+			if id.HouseholdUID != req.HouseholdUID || id.UserUID == req.UserUID {
+				writeJoinError(w, http.StatusForbidden, "only the household's members may decide this request")
+				return
+			}
+			decidedBy = id.UserUID
+		}
+		if req.Status != dao.JoinPending {
+			writeJoinError(w, http.StatusConflict, "this request was already "+req.Status)
+			return
+		}
+		decided, err := h.dao.DecideJoinRequest(r.Context(), req.UID, status, decidedBy)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJoinError(w, http.StatusConflict, "this request was already decided")
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if decided.HouseholdName == "" {
+			decided.HouseholdName = req.HouseholdName
+		}
+		if h.notifier != nil {
+			err := h.notifier.NotifyUser(unscoped(r.Context()), decided.UserUID, CategoryHouseholdJoins, joinDecisionPush(decided))
+			if err != nil {
+				slog.Error("Failed to send join decision notification", "join_request_uid", decided.UID, "error", err)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(decided)
+	}
+}
+
+func joinDecisionPush(req dao.JoinRequest) notify.Push {
+	household := req.HouseholdName
+	if household == "" {
+		household = "the household"
+	}
+	title := "Your request to join " + household + " was approved"
+	if req.Status == dao.JoinDenied {
+		title = "Your request to join " + household + " was denied"
+	}
+	return notify.Push{Title: title, Data: map[string]string{"join_request_uid": req.UID, "status": req.Status}}
+}
+
+// unscoped drops ctx's user scope, keeping its tenant. Join notifications
+// reach people outside the caller's household, whose devices the scope
+// would hide.
+func unscoped(ctx context.Context) context.Context {
+	return dao.WithScope(ctx, "", "")
+}
+
+func writeJoinError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeMembershipNotifier struct {
+	households map[string][]notify.Push
+	users      map[string][]notify.Push
+}
+
+func (f *fakeMembershipNotifier) NotifyHousehold(ctx context.Context, householdUID, category string, p notify.Push) error {
+	if _, scoped := postgres.ScopeFromContext(ctx); !scoped && category == CategoryHouseholdJoins {
+		f.households[householdUID] = append(f.households[householdUID], p)
+	}
+	return nil
+}
+
+func (f *fakeMembershipNotifier) NotifyUser(ctx context.Context, userUID, category string, p notify.Push) error {
+	if _, scoped := postgres.ScopeFromContext(ctx); !scoped && category == CategoryHouseholdJoins {
+		f.users[userUID] = append(f.users[userUID], p)
+	}
+	return nil
+}
+
+func TestJoinRequestCreate(t *testing.T) {
+	d := mocks.NewMockjoinRequestDAO(t)
+	d.On("CreateJoinRequest", mock.Anything, "house-2", "user-1", "It's Sam").
+		Return(postgres.JoinRequest{UID: "jr-1", HouseholdUID: "house-2", UserUID: "user-1", UserName: "Sam", Status: postgres.JoinPending, Message: "It's Sam"}, nil).Once()
+	d.On("CreateJoinRequest", mock.Anything, "house-2", "user-1", "").Return(postgres.JoinRequest{}, postgres.ErrJoinRequestPending).Once()
+	d.On("CreateJoinRequest", mock.Anything, "house-9", "user-1", "").Return(postgres.JoinRequest{}, postgres.ErrUnknownHousehold).Once()
+	notifier := &fakeMembershipNotifier{households: map[string][]notify.Push{}, users: map[string][]notify.Push{}}
+	handler := NewJoinRequests(d, notifier)
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)).WithContext(postgres.WithScope(identityContext("user-1", "house-1"), "user-1", "house-1")))
+		return rr
+	}
+
+	rr := post(`{"household_uid": "house-2", "message": "It's Sam"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"pending"`)
+	assert.Equal(t, []notify.Push{{Title: "Sam asked to join your household", Body: "It's Sam", Data: map[string]string{"join_request_uid": "jr-1"}}}, notifier.households["house-2"])
+
+	rr = post(`{"household_uid": "house-2"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "already pending")
+
+	rr = post(`{"household_uid": "house-9"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = post(`{"household_uid": "house-1"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "already a member")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"household_uid": "house-2"}`)))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJoinRequestDecide(t *testing.T) {
+	pending := postgres.JoinRequest{UID: "jr-1", HouseholdUID: "house-2", HouseholdName: "The Smiths", UserUID: "user-1", Status: postgres.JoinPending}
+	d := mocks.NewMockjoinRequestDAO(t)
+	d.On("GetJoinRequest", mock.Anything, "jr-1").Return(pending, nil)
+	d.On("GetJoinRequest", mock.Anything, "jr-2").Return(postgres.JoinRequest{UID: "jr-2", HouseholdUID: "house-2", UserUID: "user-3", Status: postgres.JoinDenied}, nil)
+	d.On("GetJoinRequest", mock.Anything, "jr-9").Return(postgres.JoinRequest{}, pgx.ErrNoRows)
+	d.On("DecideJoinRequest", mock.Anything, "jr-1", postgres.JoinApproved, "user-2").
+		Return(postgres.JoinRequest{UID: "jr-1", HouseholdUID: "house-2", HouseholdName: "The Smiths", UserUID: "user-1", Status: postgres.JoinApproved}, nil).Once()
+	d.On("DecideJoinRequest", mock.Anything, "jr-1", postgres.JoinDenied, "").
+		Return(postgres.JoinRequest{UID: "jr-1", HouseholdUID: "house-2", UserUID: "user-1", Status: postgres.JoinDenied}, nil).Once()
+	notifier := &fakeMembershipNotifier{households: map[string][]notify.Push{}, users: map[string][]notify.Push{}}
+	handler := NewJoinRequests(d, notifier)
+	post := func(ctx context.Context, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", path, nil).WithContext(ctx))
+		return rr
+	}
+
+	// Neither the requester nor another household may decide.
+	assert.Equal(t, http.StatusForbidden, post(identityContext("user-1", "house-1"), "/jr-1/approve").Code)
+	assert.Equal(t, http.StatusForbidden, post(identityContext("user-5", "house-5"), "/jr-1/approve").Code)
+	assert.Equal(t, http.StatusNotFound, post(identityContext("user-2", "house-2"), "/jr-9/approve").Code)
+	assert.Equal(t, http.StatusConflict, post(identityContext("user-2", "house-2"), "/jr-2/approve").Code)
+
+	rr := post(identityContext("user-2", "house-2"), "/jr-1/approve")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"approved"`)
+
+	// Operators may decide any request.
+	assert.Equal(t, http.StatusUnauthorized, post(t.Context(), "/jr-1/deny").Code)
+	rr = post(WithOperator(t.Context()), "/jr-1/deny")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, []notify.Push{
+		{Title: "Your request to join The Smiths was approved", Data: map[string]string{"join_request_uid": "jr-1", "status": "approved"}},
+		{Title: "Your request to join The Smiths was denied", Data: map[string]string{"join_request_uid": "jr-1", "status": "denied"}},
+	}, notifier.users["user-1"])
+}
@@ -14,9 +14,9 @@ func httpLogger() func(http.Handler) http.Handler {
 	isLocalhost := true
 	logFormat := httplog.SchemaECS.Concise(isLocalhost)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		ReplaceAttr: logFormat.ReplaceAttr,
-	})).With(
+	}))).With(
 		slog.String("app", "assistant-server"),
 		slog.String("version", "v1.0.0-a1fa420"),
 		slog.String("env", "production"),
@@ -49,7 +49,8 @@ func httpLogger() func(http.Handler) http.Handler {
 		LogResponseHeaders: []string{},
 
 		// Optionally, enable logging of request/response body based on custom conditions.
-		// Useful for debugging payload issues in development.
+		// Useful for debugging payload issues in development. JSON bodies are
+		// redacted by NewRedactingHandler before they are written.
 		LogRequestBody: func(req *http.Request) bool {
 			return true
 		},
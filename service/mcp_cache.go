@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cachedTools are the read-only MCP tools whose results are cached, and the
+// REST collection whose changes make a cached result stale.
+var cachedTools = map[string]string{
+	"list_todos":     "todos",
+	"get_recipe":     "recipes",
+	"get_preference": "preferences",
+}
+
+// householdlessEntities aren't kept per household, so a change to one,
+// whoever makes it, may make any caller's cached result stale.
+var householdlessEntities = map[string]bool{"preferences": true}
+
+// maxCachedResults bounds the cache; once it is full of live results, new
+// ones aren't cached until some expire.
+const maxCachedResults = 1000
+
+// WithToolCache caches the results of the read-only tools in cachedTools for
+// ttl, so an assistant repeating a call within a turn doesn't query the
+// database again. Results are kept per caller and arguments, and dropped as
+// soon as a tool call, or with WithEvents any change event, touches their
+// collection in the caller's household.
+func WithToolCache(ttl time.Duration) MCPOption {
+	return func(h *MCPHandlers) {
+		if ttl > 0 {
+			h.cache = newToolCache(ttl)
+		}
+	}
+}
+
+type cachedResult struct {
+	result       mcp.CallToolResult
+	entity       string
+	householdUID string
+	expires      time.Time
+}
+
+type toolCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResult
+	// gen counts invalidations, so a result read before one isn't cached
+	// after it.
+	gen uint64
+}
+
+func newToolCache(ttl time.Duration) *toolCache {
+	return &toolCache{ttl: ttl, now: time.Now, entries: map[string]cachedResult{}}
+}
+
+// key identifies a call to name with arguments by the caller in ctx. ok is
+// false when the tool's results aren't cached.
+func (c *toolCache) key(ctx context.Context, name string, arguments map[string]any) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	if _, ok := cachedTools[name]; !ok {
+		return "", false
+	}
+	id, _ := IdentityFromContext(ctx)
+	// Maps marshal with sorted keys, so equal arguments give equal keys.
+	args, err := json.Marshal(arguments)
+	if err != nil {
+		return "", false
+	}
+	key, err := json.Marshal([]string{name, id.TenantUID, id.APIKeyUID, id.UserUID, id.HouseholdUID, string(args)})
+	return string(key), err == nil
+}
+
+// get returns the live result cached under key, and the generation to pass
+// to put if there is none.
+func (c *toolCache) get(key string) (mcp.CallToolResult, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expires) {
+		return entry.result, c.gen, true
+	}
+	delete(c.entries, key)
+	return mcp.CallToolResult{}, c.gen, false
+}
+
+// put caches the result of a call to name, unless the cache was
+// invalidated since gen.
+func (c *toolCache) put(key string, gen uint64, name, householdUID string, result mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	now := c.now()
+	if len(c.entries) >= maxCachedResults {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedResults {
+			return
+		}
+	}
+	entity := cachedTools[name]
+	if householdlessEntities[entity] {
+		householdUID = ""
+	}
+	c.entries[key] = cachedResult{result: result, entity: entity, householdUID: householdUID, expires: now.Add(c.ttl)}
+}
+
+// invalidate drops the results e may have made stale: those of its entity
+// in its household, and those of it not tied to a household. An event
+// without a household drops every result of its entity.
+func (c *toolCache) invalidate(e ChangeEvent) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for k, entry := range c.entries {
+		if entry.entity == e.Entity && (e.HouseholdUID == "" || entry.householdUID == "" || entry.householdUID == e.HouseholdUID) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// follow invalidates the cache on every event hub publishes, including
+// REST writes and those without a household.
+func (c *toolCache) follow(hub *EventHub) {
+	events, _ := hub.Subscribe("")
+	go func() {
+		for e := range events {
+			c.invalidate(e)
+		}
+	}()
+}
+
+// cachedToolHousehold is the household a cached result belongs to.
+func cachedToolHousehold(ctx context.Context, arguments map[string]any) string {
+	if id, ok := IdentityFromContext(ctx); ok && id.HouseholdUID != "" {
+		return id.HouseholdUID
+	}
+	householdUID, _ := arguments["household_uid"].(string)
+	return householdUID
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPToolCache(t *testing.T) {
+	hub := NewEventHub()
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.Anything).Return(postgres.Todo{UID: "t1", Title: "Call plumber"}, nil)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithEvents(hub), WithToolCache(time.Minute))
+	now := time.Now()
+	h.cache.now = func() time.Time { return now }
+	ctx := identityContext("user-1", "house-1")
+	listed := func(n int) {
+		t.Helper()
+		mockTodoDAO.AssertNumberOfCalls(t, "ListTodos", n)
+	}
+
+	first := h.callTool(ctx, "list_todos", map[string]any{"status": "planned", "limit": 5})
+	assert.False(t, first.IsError)
+	assert.Equal(t, first, h.callTool(ctx, "list_todos", map[string]any{"limit": 5, "status": "planned"}))
+	listed(1)
+
+	// Other arguments and other callers aren't served from the cache.
+	h.callTool(ctx, "list_todos", map[string]any{"status": "done", "limit": 5})
+	h.callTool(identityContext("user-2", "house-2"), "list_todos", map[string]any{"status": "planned", "limit": 5})
+	listed(3)
+
+	// A write through a tool is seen by the next read.
+	assert.False(t, h.callTool(ctx, "create_todo", map[string]any{"title": "Call plumber"}).IsError)
+	h.callTool(ctx, "list_todos", map[string]any{"status": "planned", "limit": 5})
+	listed(4)
+
+	// So are changes published by the REST API, in the caller's household
+	// only.
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "updated", HouseholdUID: "house-1"})
+	assert.Eventually(t, func() bool {
+		h.cache.mu.Lock()
+		defer h.cache.mu.Unlock()
+		return len(h.cache.entries) == 1
+	}, time.Second, time.Millisecond)
+	h.callTool(ctx, "list_todos", map[string]any{"status": "planned", "limit": 5})
+	listed(5)
+
+	now = now.Add(time.Minute)
+	h.callTool(ctx, "list_todos", map[string]any{"status": "planned", "limit": 5})
+	listed(6)
+
+	// Tools that aren't read-only are never cached.
+	_, cacheable := h.cache.key(ctx, "create_todo", map[string]any{})
+	assert.False(t, cacheable)
+}
+
+func TestMCPToolCacheSeesRESTPreferenceUpdates(t *testing.T) {
+	hub := NewEventHub()
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, "theme", "user-1").Return(postgres.Preferences{Key: "theme", Specifier: "user-1", Data: "light"}, nil).Once()
+	prefs.On("UpdatePreferences", mock.Anything, "theme", "user-1", mock.Anything).Return(postgres.Preferences{Key: "theme", Specifier: "user-1", Data: "dark"}, nil)
+	prefs.On("GetPreferences", mock.Anything, "theme", "user-1").Return(postgres.Preferences{Key: "theme", Specifier: "user-1", Data: "dark"}, nil).Once()
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, prefs, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithEvents(hub), WithToolCache(time.Minute))
+	r := chi.NewRouter()
+	r.Use(hub.Track)
+	r.Mount("/preferences", NewPreferences(prefs))
+	args := map[string]any{"key": "theme", "specifier": "user-1"}
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_preference", args), &body)
+	assert.Equal(t, "light", body["preference"].(map[string]any)["data"])
+
+	// Preferences have no household, so neither does the REST update's
+	// event; it still reaches the cache.
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("PUT", "/preferences/theme/user-1", strings.NewReader(`{"data": "dark"}`)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Eventually(t, func() bool {
+		h.cache.mu.Lock()
+		defer h.cache.mu.Unlock()
+		return len(h.cache.entries) == 0
+	}, time.Second, time.Millisecond)
+
+	body = nil
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_preference", args), &body)
+	assert.Equal(t, "dark", body["preference"].(map[string]any)["data"])
+	prefs.AssertNumberOfCalls(t, "GetPreferences", 2)
+}
+
+func TestToolCacheSkipsResultsReadBeforeAnInvalidation(t *testing.T) {
+	c := newToolCache(time.Minute)
+	key, _ := c.key(identityContext("user-1", "house-1"), "get_recipe", map[string]any{"recipe_id": "r1"})
+	_, gen, hit := c.get(key)
+	assert.False(t, hit)
+
+	c.invalidate(ChangeEvent{Entity: "recipes", HouseholdUID: "house-1"})
+	c.put(key, gen, "get_recipe", "house-1", toolOK("Recipe found", nil))
+	_, _, hit = c.get(key)
+	assert.False(t, hit)
+}
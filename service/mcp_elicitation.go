@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ConfirmationPolicy controls whether a tool must be confirmed by the user
+// before it runs.
+type ConfirmationPolicy string
+
+const (
+	// ConfirmNever runs the tool without asking.
+	ConfirmNever ConfirmationPolicy = "never"
+	// ConfirmIfSupported asks through elicitation when the client supports
+	// it and otherwise runs the tool.
+	ConfirmIfSupported ConfirmationPolicy = "if_supported"
+	// ConfirmRequired asks through elicitation, and clients without it must
+	// pass confirm=true explicitly.
+	ConfirmRequired ConfirmationPolicy = "required"
+
+	defaultElicitationTimeout = 5 * time.Minute
+)
+
+// defaultConfirmationPolicies covers the destructive tools. Tools missing from
+// the table are never confirmed.
+var defaultConfirmationPolicies = map[string]ConfirmationPolicy{
+	"delete_note":   ConfirmRequired,
+	"delete_recipe": ConfirmRequired,
+}
+
+var (
+	errElicitationUnsupported = errors.New("client does not support elicitation")
+	errElicitationTimeout     = errors.New("timed out waiting for elicitation response")
+)
+
+// ParseConfirmationPolicy validates a policy name from configuration.
+func ParseConfirmationPolicy(s string) (ConfirmationPolicy, error) {
+	switch p := ConfirmationPolicy(s); p {
+	case ConfirmNever, ConfirmIfSupported, ConfirmRequired:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown confirmation policy %q", s)
+	}
+}
+
+// WithConfirmationPolicy overrides the confirmation policy for one tool.
+func WithConfirmationPolicy(tool string, policy ConfirmationPolicy) MCPOption {
+	return func(h *MCPHandlers) {
+		h.confirmations[tool] = policy
+	}
+}
+
+// WithElicitationTimeout bounds how long a tool call waits for the user to
+// answer an elicitation request.
+func WithElicitationTimeout(d time.Duration) MCPOption {
+	return func(h *MCPHandlers) {
+		if d > 0 {
+			h.elicitationTimeout = d
+		}
+	}
+}
+
+// ElicitResult is the client's answer to elicitation/create.
+type ElicitResult struct {
+	Action  string         `json:"action"` // accept, decline or cancel
+	Content map[string]any `json:"content,omitempty"`
+}
+
+type clientResponse struct {
+	ID     any             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type pendingRequest struct {
+	sessionID string
+	ch        chan clientResponse
+}
+
+// pendingRequests tracks server-to-client requests awaiting the client's
+// response, which arrives on a separate POST.
+type pendingRequests struct {
+	mu      sync.Mutex
+	pending map[string]pendingRequest
+}
+
+func newPendingRequests() *pendingRequests {
+	return &pendingRequests{pending: make(map[string]pendingRequest)}
+}
+
+func (p *pendingRequests) add(id, sessionID string) chan clientResponse {
+	ch := make(chan clientResponse, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[id] = pendingRequest{sessionID: sessionID, ch: ch}
+	return ch
+}
+
+func (p *pendingRequests) remove(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, id)
+}
+
+// resolve hands resp to the waiting request if it was sent on sessionID.
+func (p *pendingRequests) resolve(sessionID string, resp clientResponse) bool {
+	id, ok := resp.ID.(string)
+	if !ok {
+		return false
+	}
+	p.mu.Lock()
+	req, ok := p.pending[id]
+	if ok && req.sessionID == sessionID {
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+	if !ok || req.sessionID != sessionID {
+		return false
+	}
+	req.ch <- resp
+	return true
+}
+
+// canElicit reports whether elicitation/create can reach the client: it needs
+// a session that advertised the capability, a revision that has it, and an
+// open event stream to carry the request.
+func canElicit(ctx context.Context) bool {
+	session, ok := sessionFrom(ctx)
+	if !ok || session.ClientCapabilities.Elicitation == nil || !featuresFrom(ctx).Elicitation {
+		return false
+	}
+	s, ok := ctx.Value(eventStreamKey{}).(*eventStream)
+	return ok && s != nil && s.enabled
+}
+
+// elicit asks the user a question through the client and waits for the
+// answer.
+func (h *MCPHandlers) elicit(ctx context.Context, message string, schema map[string]any) (ElicitResult, error) {
+	if !canElicit(ctx) {
+		return ElicitResult{}, errElicitationUnsupported
+	}
+	session, _ := sessionFrom(ctx)
+
+	id := uuid.NewString()
+	ch := h.pending.add(id, session.ID)
+	defer h.pending.remove(id)
+
+	sent := sendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  "elicitation/create",
+		Params:  map[string]any{"message": message, "requestedSchema": schema},
+	})
+	if !sent {
+		return ElicitResult{}, errElicitationUnsupported
+	}
+
+	timer := time.NewTimer(h.elicitationTimeout)
+	defer timer.Stop()
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return ElicitResult{}, fmt.Errorf("elicitation failed: %s", resp.Error.Message)
+		}
+		var result ElicitResult
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return ElicitResult{}, fmt.Errorf("invalid elicitation result: %w", err)
+		}
+		return result, nil
+	case <-timer.C:
+		return ElicitResult{}, errElicitationTimeout
+	case <-ctx.Done():
+		return ElicitResult{}, ctx.Err()
+	}
+}
+
+// confirmTool applies the tool's confirmation policy. It returns a non-nil
+// result when the call must not go ahead.
+func (h *MCPHandlers) confirmTool(ctx context.Context, name string, arguments map[string]any) *mcp.CallToolResult {
+	policy := h.confirmations[name]
+	if policy == "" || policy == ConfirmNever {
+		return nil
+	}
+
+	if !canElicit(ctx) {
+		if policy == ConfirmIfSupported {
+			return nil
+		}
+		if confirmed, _ := arguments["confirm"].(bool); confirmed {
+			return nil
+		}
+		result := toolError("%s requires confirmation: call it again with confirm=true after the user agrees", name)
+		return &result
+	}
+
+	answer, err := h.elicit(ctx, confirmationMessage(name, arguments), map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"confirm": map[string]any{
+				"type":        "boolean",
+				"title":       "Confirm",
+				"description": "Go ahead with " + name,
+			},
+		},
+		"required": []string{"confirm"},
+	})
+	if err != nil {
+		h.log().Warn("MCP tool confirmation failed",
+			slog.String("tool_name", name),
+			slog.String("error", err.Error()),
+		)
+		result := toolError("Could not confirm %s: %v", name, err)
+		return &result
+	}
+	if confirmed, _ := answer.Content["confirm"].(bool); answer.Action != "accept" || !confirmed {
+		result := toolError("%s was not confirmed by the user", name)
+		return &result
+	}
+	return nil
+}
+
+func confirmationMessage(name string, arguments map[string]any) string {
+	switch name {
+	case "delete_note":
+		return fmt.Sprintf("Delete note %v? This cannot be undone.", arguments["note_id"])
+	case "delete_recipe":
+		return fmt.Sprintf("Delete recipe %v? This cannot be undone.", arguments["recipe_id"])
+	default:
+		return fmt.Sprintf("Run %s?", name)
+	}
+}
+
+// handleClientResponse accepts the client's reply to a server-to-client
+// request such as elicitation/create.
+func (h *MCPHandlers) handleClientResponse(w http.ResponseWriter, session *mcpSession, raw json.RawMessage) {
+	if session == nil {
+		http.Error(w, "Responses require an Mcp-Session-Id", http.StatusBadRequest)
+		return
+	}
+	var resp clientResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		http.Error(w, "Invalid JSON-RPC response", http.StatusBadRequest)
+		return
+	}
+	if !h.pending.resolve(session.ID, resp) {
+		h.log().Warn("JSON-RPC response for unknown request",
+			slog.Any("id", resp.ID),
+			slog.String("session_id", session.ID),
+		)
+		http.Error(w, "Unknown request id", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
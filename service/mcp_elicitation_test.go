@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMCPHandlers_DeleteRequiresConfirmFlagWithoutElicitation(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("DeleteNotes", mock.Anything, "note-1").Return(nil).Once()
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	result := h.callTool(t.Context(), "delete_note", map[string]any{"note_id": "note-1"})
+	assert.True(t, result.IsError)
+	var body map[string]any
+	decodeToolResult(t, result, &body)
+	assert.Contains(t, body["error"], "confirm=true")
+	mockNotesDAO.AssertNotCalled(t, "DeleteNotes", mock.Anything, mock.Anything)
+
+	result = h.callTool(t.Context(), "delete_note", map[string]any{"note_id": "note-1", "confirm": true})
+	assert.False(t, result.IsError)
+	mockNotesDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_ConfirmationPolicyOverride(t *testing.T) {
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("DeleteRecipes", mock.Anything, "recipe-1").Return(nil).Twice()
+
+	for _, policy := range []ConfirmationPolicy{ConfirmNever, ConfirmIfSupported} {
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{},
+			WithConfirmationPolicy("delete_recipe", policy))
+		result := h.callTool(t.Context(), "delete_recipe", map[string]any{"recipe_id": "recipe-1"})
+		assert.False(t, result.IsError, "policy %s", policy)
+	}
+	mockRecipesDAO.AssertExpectations(t)
+}
+
+func TestParseConfirmationPolicy(t *testing.T) {
+	p, err := ParseConfirmationPolicy("if_supported")
+	assert.NoError(t, err)
+	assert.Equal(t, ConfirmIfSupported, p)
+
+	_, err = ParseConfirmationPolicy("sometimes")
+	assert.Error(t, err)
+}
+
+// callWithElicitation runs delete_note over a real HTTP server on a session
+// that supports elicitation, answering the elicitation/create request with
+// answer. It returns the final tools/call response.
+func callWithElicitation(t *testing.T, h *MCPHandlers, answer map[string]any) map[string]any {
+	t.Helper()
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	latest := map[string]string{protocolVersionHeader: LatestProtocolVersion}
+	sessionID := initializeSession(t, h, latest)
+	headers := map[string]string{
+		"Accept":              "application/json, text/event-stream",
+		"Mcp-Session-Id":      sessionID,
+		protocolVersionHeader: LatestProtocolVersion,
+	}
+
+	post := func(body map[string]any) *http.Response {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", server.URL, bytes.NewReader(data))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := post(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      7,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "delete_note",
+			"arguments": map[string]any{"note_id": "note-1"},
+		},
+	})
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var msg map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg))
+
+		if msg["method"] == "elicitation/create" {
+			params := msg["params"].(map[string]any)
+			assert.Contains(t, params["message"], "note-1")
+			assert.NotNil(t, params["requestedSchema"])
+
+			reply := post(map[string]any{"jsonrpc": "2.0", "id": msg["id"], "result": answer})
+			reply.Body.Close()
+			assert.Equal(t, http.StatusAccepted, reply.StatusCode)
+			continue
+		}
+		if msg["id"] == float64(7) {
+			return msg
+		}
+	}
+	t.Fatal("stream ended without a tools/call response")
+	return nil
+}
+
+func TestMCPHandlers_ElicitationConfirmsDelete(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("DeleteNotes", mock.Anything, "note-1").Return(nil).Once()
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	msg := callWithElicitation(t, h, map[string]any{"action": "accept", "content": map[string]any{"confirm": true}})
+	result := msg["result"].(map[string]any)
+	assert.NotEqual(t, true, result["isError"])
+	assert.Equal(t, "note-1", result["structuredContent"].(map[string]any)["note_id"])
+	mockNotesDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_ElicitationDeclined(t *testing.T) {
+	for _, answer := range []map[string]any{
+		{"action": "decline"},
+		{"action": "cancel"},
+		{"action": "accept", "content": map[string]any{"confirm": false}},
+	} {
+		mockNotesDAO := &MockNotesDAO{}
+		h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+		msg := callWithElicitation(t, h, answer)
+		result := msg["result"].(map[string]any)
+		assert.Equal(t, true, result["isError"])
+		assert.Equal(t, "delete_note was not confirmed by the user", result["structuredContent"].(map[string]any)["error"])
+		mockNotesDAO.AssertNotCalled(t, "DeleteNotes", mock.Anything, mock.Anything)
+	}
+}
+
+func TestMCPHandlers_ElicitationTimeout(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithElicitationTimeout(10*time.Millisecond))
+	sessionID := initializeSession(t, h, map[string]string{protocolVersionHeader: LatestProtocolVersion})
+
+	w := postMCP(h, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "delete_note", "arguments": map[string]any{"note_id": "note-1"}},
+	}, map[string]string{
+		"Accept":              "application/json, text/event-stream",
+		"Mcp-Session-Id":      sessionID,
+		protocolVersionHeader: LatestProtocolVersion,
+	})
+
+	msgs := readSSEMessages(t, w.Body.String())
+	if assert.GreaterOrEqual(t, len(msgs), 2) {
+		assert.Equal(t, "elicitation/create", msgs[0]["method"])
+		final := msgs[len(msgs)-1]["result"].(map[string]any)
+		assert.Contains(t, final["structuredContent"].(map[string]any)["error"], "timed out")
+	}
+}
+
+func TestMCPHandlers_ClientResponseForUnknownRequest(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	sessionID := initializeSession(t, h, nil)
+	reply := map[string]any{"jsonrpc": "2.0", "id": "nope", "result": map[string]any{"action": "accept"}}
+
+	w := postMCP(h, reply, map[string]string{"Mcp-Session-Id": sessionID})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postMCP(h, reply, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
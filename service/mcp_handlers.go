@@ -3,18 +3,26 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pbdeuchler/assistant-server/calendar"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+	"golang.org/x/oauth2"
 )
 
 type userDAO interface {
@@ -34,11 +42,42 @@ type MCPHandlers struct {
 	recipesDAO     recipesDAO
 	userDAO        userDAO
 	householdDAO   householdDAO
+	backgroundDAO  backgroundDAO
+	scratchpads    *Scratchpads
+	templateDAO    todoTemplateDAO
+	pantryDAO      pantryDAO
+	purchaseDAO    groceryPurchaseDAO
+	awayDAO        awayDAO
+	membersDAO     membersDAO
+	datesDAO       importantDateDAO
+	myDayDAO       myDayDAO
+	projectDAO     projectDAO
+	dataSchemaDAO  dataSchemaDAO
+	locksDAO       entityLockDAO
+	tagger         Tagger
+	extraction     *todoExtraction
+	calendarCreds  calendarCredentialDAO
+	googleOAuth    *oauth2.Config
 	tools          []mcp.Tool
-	clientInfo     *ClientInfo
+	sessions       *sessionStore
 	serverInfo     ServerInfo
 	capabilities   ServerCapabilities
 	logger         *slog.Logger
+	apiKeys        apiKeyDAO
+	requireAPIKey  bool
+	policy         *Policy
+	flags          *FeatureFlags
+	usage          *UsageMeter
+	toolsPageSize  int
+	events         *EventHub
+	cache          *toolCache
+	expandDAO      expandDAO
+	recipeClient   *http.Client
+
+	confirmations      map[string]ConfirmationPolicy
+	elicitationTimeout time.Duration
+	pending            *pendingRequests
+	groceryClassifier  GroceryClassifier
 }
 
 func (h *MCPHandlers) log() *slog.Logger {
@@ -117,8 +156,8 @@ type ToolsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
-func NewMCP(todoDAO todoDAO, notesDAO notesDAO, preferencesDAO preferencesDAO, recipesDAO recipesDAO, userDAO userDAO, householdDAO householdDAO) *MCPHandlers {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{})).With(
+func NewMCP(todoDAO todoDAO, notesDAO notesDAO, preferencesDAO preferencesDAO, recipesDAO recipesDAO, userDAO userDAO, householdDAO householdDAO, opts ...MCPOption) *MCPHandlers {
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))).With(
 		slog.String("component", "mcp"),
 		slog.String("app", "assistant-server"),
 	)
@@ -131,17 +170,32 @@ func NewMCP(todoDAO todoDAO, notesDAO notesDAO, preferencesDAO preferencesDAO, r
 		userDAO:        userDAO,
 		householdDAO:   householdDAO,
 		logger:         logger,
+		toolsPageSize:  defaultToolsPageSize,
+		sessions:       newSessionStore(defaultSessionTTL),
+		confirmations:  maps.Clone(defaultConfirmationPolicies),
+		pending:        newPendingRequests(),
+
+		elicitationTimeout: defaultElicitationTimeout,
+		groceryClassifier:  DefaultGroceryRules,
 		serverInfo: ServerInfo{
 			Name:    "assistant-server",
 			Title:   "Assistant Server MCP",
 			Version: "1.0.0",
 		},
 		capabilities: ServerCapabilities{
+			Logging: map[string]any{},
 			Tools: &ToolsCapability{
 				ListChanged: true,
 			},
 		},
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.capabilities.Resources = &ResourcesCapability{Subscribe: h.events != nil}
+	if h.cache != nil && h.events != nil {
+		h.cache.follow(h.events)
+	}
 
 	h.setupTools()
 	logger.Info("MCP server initialized",
@@ -156,46 +210,115 @@ func (h *MCPHandlers) setupTools() {
 	h.tools = []mcp.Tool{
 		mcp.NewTool("create_todo",
 			mcp.WithDescription("Create a new todo task"),
-			mcp.WithString("title", mcp.Required(), mcp.Description("Task title")),
+			mcp.WithString("title", mcp.Required(), mcp.Description("Task title. Quick-add markers in it fill in what the other arguments leave out: !1 to !4 for priority, #errands for a tag, and @today, @tomorrow, @sat or @2025-09-20 for the due date")),
+			mcp.WithBoolean("quick_add", mcp.Description("Read quick-add markers out of the title (default true)")),
+			mcp.WithString("tags", mcp.Description("Tags (comma-separated)")),
 			mcp.WithString("description", mcp.Description("Task description")),
-			mcp.WithNumber("priority", mcp.Description("Priority level 1-5 (5 is highest)")),
+			mcp.WithString("priority", mcp.Description("How urgent the task is (default medium)"), mcp.Enum("low", "medium", "high", "critical")),
+			mcp.WithString("status", mcp.Description("Where the task starts on the board (default backlog)"), mcp.Enum("backlog", "planned", "in_progress", "blocked", "done")),
 			mcp.WithString("due_date", mcp.Description("Due date in RFC3339 format (e.g., 2024-01-15T10:00:00Z)")),
-			mcp.WithString("user_uid", mcp.Description("User ID")),
-			mcp.WithString("household_uid", mcp.Description("Household ID")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			mcp.WithString("location_name", mcp.Description("Where the task can be done (e.g. \"Hardware store on Main St\")")),
+			mcp.WithNumber("lat", mcp.Description("Latitude of the task's location")),
+			mcp.WithNumber("lon", mcp.Description("Longitude of the task's location")),
+			mcp.WithNumber("radius_m", mcp.Description("How far from lat/lon, in metres, still counts as being there")),
+			mcp.WithNumber("estimate_minutes", mcp.Description("How long the task is expected to take, in minutes")),
+			mcp.WithString("project_uid", mcp.Description("Project to put the task in")),
 		),
 		mcp.NewTool("list_todos",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("List todos with optional filtering"),
 			mcp.WithString("user_uid", mcp.Description("Filter by user ID")),
-			mcp.WithString("household_uid", mcp.Description("Filter by household ID")),
-			mcp.WithNumber("priority", mcp.Description("Filter by priority level")),
+			mcp.WithString("household_uid", mcp.Description("Filter by household ID (defaults to the authenticated user's household)")),
+			mcp.WithString("priority", mcp.Description("Filter by priority"), mcp.Enum("low", "medium", "high", "critical")),
+			mcp.WithString("status", mcp.Description("Filter by status"), mcp.Enum("backlog", "planned", "in_progress", "blocked", "done")),
+			mcp.WithString("project_uid", mcp.Description("Filter by project ID")),
 			mcp.WithString("tags", mcp.Description("Filter by tags (comma-separated)")),
 			mcp.WithBoolean("completed_only", mcp.Description("Show only completed todos")),
 			mcp.WithBoolean("pending_only", mcp.Description("Show only pending todos")),
+			mcp.WithBoolean("actionable", mcp.Description("Show only todos with no incomplete blockers (true) or only blocked todos (false)")),
+			mcp.WithString("near", mcp.Description("Show only todos located within a radius of a point, as \"lat,lon,radius_in_metres\"")),
 			mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20)")),
 		),
 		mcp.NewTool("complete_todo",
 			mcp.WithDescription("Mark a todo as completed"),
 			mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID to complete")),
-			mcp.WithString("completed_by", mcp.Description("User ID who completed the task")),
+			mcp.WithString("completed_by", mcp.Description("User ID who completed the task (defaults to the authenticated user)")),
+		),
+		mcp.NewTool("set_todo_status",
+			mcp.WithDescription("Move a todo to another column of the board. Moving it to done completes it; moving a done todo anywhere else reopens it"),
+			mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID to move")),
+			mcp.WithString("status", mcp.Required(), mcp.Description("New status"), mcp.Enum("backlog", "planned", "in_progress", "blocked", "done")),
+			mcp.WithString("completed_by", mcp.Description("User ID who completed the task, when moving it to done (defaults to the authenticated user)")),
+		),
+		mcp.NewTool("link_todos",
+			mcp.WithDescription("Record that one todo must wait for another, to plan multi-step errands (e.g. \"buy paint\" blocks \"paint fence\"), or remove that link"),
+			mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID to link")),
+			mcp.WithString("blocked_by_id", mcp.Description("Todo UID that must be completed before todo_id")),
+			mcp.WithString("blocks_id", mcp.Description("Todo UID that cannot start until todo_id is completed")),
+			mcp.WithBoolean("unlink", mcp.Description("Remove the link instead of adding it")),
+		),
+		mcp.NewTool("log_time",
+			mcp.WithDescription("Record time spent on a todo"),
+			mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID the time was spent on")),
+			mcp.WithNumber("minutes", mcp.Required(), mcp.Description(fmt.Sprintf("Minutes spent, at most %d", maxLoggedMinutes))),
+			mcp.WithString("user_uid", mcp.Description("User ID who spent the time (defaults to the authenticated user)")),
+			mcp.WithString("logged_on", mcp.Description("Day the time was spent, as YYYY-MM-DD (default today)")),
+			mcp.WithString("note", mcp.Description("What was done")),
+		),
+		mcp.NewTool("time_report",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Total the time logged on todos per week and person, e.g. to answer \"how much time did yard work take this month\""),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			mcp.WithString("user_uid", mcp.Description("Only count time logged by this user")),
+			mcp.WithString("from", mcp.Description("First day, as YYYY-MM-DD (default the Monday three weeks before to)")),
+			mcp.WithString("to", mcp.Description("Last day, as YYYY-MM-DD (default today)")),
+			mcp.WithString("title", mcp.Description("Only count todos whose title contains this, e.g. \"yard\"")),
 		),
 		mcp.NewTool("save_note",
-			mcp.WithDescription("Save a note with a key for later retrieval"),
+			mcp.WithDescription("Save a note with a key for later retrieval, or rewrite an existing note"),
 			mcp.WithString("key", mcp.Required(), mcp.Description("Unique key for the note")),
 			mcp.WithString("data", mcp.Required(), mcp.Description("Structured note content")),
-			mcp.WithString("user_uid", mcp.Description("User ID")),
-			mcp.WithString("household_uid", mcp.Description("Household ID")),
+			mcp.WithString("note_id", mcp.Description("Note ID to rewrite, keeping its owner; omit to save a new note")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
 			mcp.WithString("tags", mcp.Description("Comma-separated tags")),
+			mcp.WithString("visibility", mcp.Description("Who can read the note"), mcp.Enum(dao.NoteVisibilityPrivate, dao.NoteVisibilityHousehold, dao.NoteVisibilitySharedLink)),
+			mcp.WithBoolean("auto_tag", mcp.Description("Apply the suggested tags when no tags are given")),
+			mcp.WithString("remind_at", mcp.Description("When to remind the owner of the note, in RFC3339 format (e.g., 2026-05-01T09:00:00Z)")),
 		),
 		mcp.NewTool("recall_note",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("Retrieve a saved note by key"),
 			mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID to retrieve")),
 		),
+		mcp.NewTool("delete_note",
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithDescription("Delete a note. The user is asked to confirm first"),
+			mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID to delete")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true once the user has agreed, for clients without elicitation support")),
+		),
+		mcp.NewTool("pin_note",
+			mcp.WithDescription("Pin a note so it is always included in the user's context (e.g. Wi-Fi password, babysitter's number), or unpin it"),
+			mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID to pin or unpin")),
+			mcp.WithBoolean("pinned", mcp.Description("Pin (true, default) or unpin (false)")),
+			mcp.WithNumber("sort_order", mcp.Description("Position among pinned notes, lowest first (default 0)")),
+		),
+		mcp.NewTool("remind_me",
+			mcp.WithDescription("Remind the note's owner about a note at a given time with a push notification, e.g. a month before the passport it records expires, or cancel the reminder"),
+			mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID to be reminded about")),
+			mcp.WithString("remind_at", mcp.Description("When to send the reminder, in RFC3339 format (e.g., 2026-05-01T09:00:00Z); required unless cancelling")),
+			mcp.WithBoolean("cancel", mcp.Description("Cancel the note's reminder instead")),
+		),
 		mcp.NewTool("list_notes",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("List notes with optional filtering"),
 			mcp.WithString("key", mcp.Description("Filter by key")),
 			mcp.WithString("user_uid", mcp.Description("Filter by user ID")),
-			mcp.WithString("household_uid", mcp.Description("Filter by household ID")),
+			mcp.WithString("household_uid", mcp.Description("Filter by household ID (defaults to the authenticated user's household)")),
 			mcp.WithString("tags", mcp.Description("Filter by tags (comma-separated)")),
+			mcp.WithBoolean("include_archived", mcp.Description("Also list old notes that were condensed into digest notes")),
 			mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20)")),
 		),
 		mcp.NewTool("set_preference",
@@ -206,26 +329,72 @@ func (h *MCPHandlers) setupTools() {
 			mcp.WithString("tags", mcp.Description("Comma-separated tags")),
 		),
 		mcp.NewTool("get_preference",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("Get a user preference"),
 			mcp.WithString("key", mcp.Required(), mcp.Description("Preference key")),
 			mcp.WithString("specifier", mcp.Required(), mcp.Description("Preference specifier")),
 		),
+		mcp.NewTool("get_preferences_bulk",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Get several preferences at once, e.g. everything needed to set up a new user; preferences that aren't set are listed as missing"),
+			mcp.WithArray("preferences", mcp.Required(),
+				mcp.Description(fmt.Sprintf("Preferences to get, at most %d", maxBulkPreferences)),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":       map[string]any{"type": "string"},
+						"specifier": map[string]any{"type": "string"},
+					},
+					"required": []string{"key", "specifier"},
+				}),
+			),
+		),
+		mcp.NewTool("set_preferences_bulk",
+			mcp.WithDescription("Set several preferences at once; either all of them are saved or, if any fails, none are"),
+			mcp.WithArray("preferences", mcp.Required(),
+				mcp.Description(fmt.Sprintf("Preferences to set, at most %d", maxBulkPreferences)),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":       map[string]any{"type": "string"},
+						"specifier": map[string]any{"type": "string"},
+						"data":      map[string]any{"type": "string", "description": "Structured preference data"},
+						"tags":      map[string]any{"type": "string", "description": "Comma-separated tags"},
+					},
+					"required": []string{"key", "specifier", "data"},
+				}),
+			),
+		),
+		mcp.NewTool("set_notification_preference",
+			mcp.WithDescription("Change how a user is notified: turn email or push on or off, choose instant, digest or no delivery for a category, set the digest frequency, or set quiet hours. Returns the resulting preferences; call it with no changes to read them."),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			mcp.WithString("channel", mcp.Description("Channel to turn on or off with enabled"), mcp.Enum(notificationChannels...)),
+			mcp.WithBoolean("enabled", mcp.Description("Whether channel is on")),
+			mcp.WithString("category", mcp.Description("Kind of notification to set the delivery of"), mcp.Enum(notificationCategories...)),
+			mcp.WithString("delivery", mcp.Description("How category is delivered"), mcp.Enum(notificationDeliveries...)),
+			mcp.WithString("digest_frequency", mcp.Description("How often the email digest is sent"), mcp.Enum(digestFrequencies...)),
+			mcp.WithString("quiet_hours", mcp.Description("Hours when nothing is pushed, like 22:00-07:00, or off")),
+			mcp.WithString("timezone", mcp.Description("IANA timezone of the quiet hours, e.g. Europe/London (default UTC)")),
+		),
 		mcp.NewTool("save_recipe",
 			mcp.WithDescription("Save a recipe"),
 			mcp.WithString("title", mcp.Required(), mcp.Description("Recipe title")),
 			mcp.WithString("data", mcp.Required(), mcp.Description("Recipe instructions as structured data")),
+			mcp.WithString("external_url", mcp.Description("Page the recipe came from; saving one the household already has updates it instead")),
 			mcp.WithString("genre", mcp.Description("Recipe genre/category")),
 			mcp.WithString("grocery_list", mcp.Description("Grocery list as structured data")),
 			mcp.WithNumber("prep_time", mcp.Description("Prep time in minutes")),
 			mcp.WithNumber("cook_time", mcp.Description("Cook time in minutes")),
 			mcp.WithNumber("servings", mcp.Description("Number of servings")),
 			mcp.WithNumber("difficulty", mcp.Description("Difficulty level 1-5")),
-			mcp.WithNumber("rating", mcp.Description("Rating 1-5")),
-			mcp.WithString("user_uid", mcp.Description("User ID")),
-			mcp.WithString("household_uid", mcp.Description("Household ID")),
+			mcp.WithNumber("rating", mcp.Description("The user's rating 1-5")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
 			mcp.WithString("tags", mcp.Description("Comma-separated tags")),
+			mcp.WithBoolean("auto_tag", mcp.Description("Apply the suggested tags when no tags are given")),
 		),
 		mcp.NewTool("find_recipes",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("Search recipes by criteria"),
 			mcp.WithString("title", mcp.Description("Filter by title (partial match)")),
 			mcp.WithString("genre", mcp.Description("Filter by genre")),
@@ -233,63 +402,442 @@ func (h *MCPHandlers) setupTools() {
 			mcp.WithNumber("min_rating", mcp.Description("Minimum rating")),
 			mcp.WithString("tags", mcp.Description("Comma-separated tags to filter by")),
 			mcp.WithString("user_uid", mcp.Description("Filter by user ID")),
-			mcp.WithString("household_uid", mcp.Description("Filter by household ID")),
+			mcp.WithString("household_uid", mcp.Description("Filter by household ID (defaults to the authenticated user's household)")),
 			mcp.WithNumber("limit", mcp.Description("Maximum number of results (default 20)")),
 		),
 		mcp.NewTool("get_recipe",
+			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDescription("Get a specific recipe by ID"),
 			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("Recipe ID")),
 		),
+		mcp.NewTool("rate_recipe",
+			mcp.WithDescription("Rate a recipe 1-5 for a user, replacing their earlier rating. The recipe's rating is the household's average"),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("Recipe ID")),
+			mcp.WithNumber("rating", mcp.Required(), mcp.Description("Rating 1-5")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+		),
+		mcp.NewTool("duplicate_recipe",
+			mcp.WithDescription("Copy a recipe so the copy can be changed while the original stays as it is. Fields given replace the original's in the copy"),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("Recipe ID to copy")),
+			mcp.WithString("title", mcp.Description("Title of the copy (defaults to the original's with \"(copy)\" added)")),
+			mcp.WithString("data", mcp.Description("Recipe instructions as structured data")),
+			mcp.WithString("genre", mcp.Description("Recipe genre/category")),
+			mcp.WithString("grocery_list", mcp.Description("Grocery list as structured data")),
+			mcp.WithNumber("prep_time", mcp.Description("Prep time in minutes")),
+			mcp.WithNumber("cook_time", mcp.Description("Cook time in minutes")),
+			mcp.WithNumber("servings", mcp.Description("Number of servings")),
+			mcp.WithString("tags", mcp.Description("Comma-separated tags")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+		),
+		mcp.NewTool("delete_recipe",
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithDescription("Delete a recipe. The user is asked to confirm first"),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("Recipe ID to delete")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true once the user has agreed, for clients without elicitation support")),
+		),
+		mcp.NewTool("convert_units",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Convert a cooking quantity between units, e.g. cups to grams. Volume to weight needs a common ingredient such as flour, sugar, butter or milk"),
+			mcp.WithString("quantity", mcp.Required(), mcp.Description("Quantity to convert, e.g. '1 1/2 cups' or '250 g'")),
+			mcp.WithString("to", mcp.Required(), mcp.Description("Target unit (e.g. 'ml', 'oz', 'tbsp'), or 'metric' or 'imperial' to pick a convenient unit")),
+			mcp.WithString("ingredient", mcp.Description("Ingredient being measured, needed to convert between volume and weight")),
+		),
+		mcp.NewTool("build_shopping_list",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Build a combined shopping list from the grocery lists of one or more recipes, leaving out what the household's pantry already has, grouped by supermarket aisle"),
+			mcp.WithString("recipe_ids", mcp.Required(), mcp.Description("Comma-separated recipe IDs")),
+			mcp.WithString("household_uid", mcp.Description("Household whose pantry to check (defaults to the authenticated user's household)")),
+		),
+		mcp.NewTool("split_shopping_list",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Build a shopping list like build_shopping_list, split into one list per store by the household's preferred stores"),
+			mcp.WithString("recipe_ids", mcp.Required(), mcp.Description("Comma-separated recipe IDs")),
+			mcp.WithString("household_uid", mcp.Description("Household whose pantry and stores to use (defaults to the authenticated user's household)")),
+		),
+		mcp.NewTool("set_grocery_stores",
+			mcp.WithDescription("Set the stores a household shops at, most preferred first. Items are bought at the first store unless they have a preferred store"),
+			mcp.WithString("stores", mcp.Required(), mcp.Description("Comma-separated store names, e.g. 'Tesco, Farmers market'")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+		),
+		mcp.NewTool("set_preferred_store",
+			mcp.WithDescription("Set which of the household's stores to buy an item, or a whole grocery category, at"),
+			mcp.WithString("store", mcp.Required(), mcp.Description("One of the household's stores, or empty to go back to the default")),
+			mcp.WithString("item", mcp.Description("Item, e.g. 'sourdough'")),
+			mcp.WithString("category", mcp.Description("Grocery category, instead of an item"), mcp.Enum(GroceryCategories...)),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+		),
 		mcp.NewTool("update_user_description",
 			mcp.WithDescription("Update a user's description"),
-			mcp.WithString("user_uid", mcp.Required(), mcp.Description("User ID")),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
 			mcp.WithString("description", mcp.Required(), mcp.Description("New description for the user")),
 		),
 		mcp.NewTool("update_household_description",
 			mcp.WithDescription("Update a household's description"),
-			mcp.WithString("household_uid", mcp.Required(), mcp.Description("Household ID")),
+			mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
 			mcp.WithString("description", mcp.Required(), mcp.Description("New description for the household")),
 		),
+		mcp.NewTool("get_briefing",
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDescription("Get a briefing for a user: their household, pinned notes, open todos, todos completed in the last day and by whom, pantry items expiring soon, who is away, whose birthday is coming up and the household's important dates coming up"),
+			mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+		),
+	}
+
+	addFormatArgument(h.tools)
+	if h.expandDAO != nil {
+		addExpandArgument(h.tools)
+	}
+
+	if h.backgroundDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("set_background",
+				mcp.WithDescription("Store a piece of background context under a key, replacing any existing value"),
+				mcp.WithString("key", mcp.Required(), mcp.Description("Background key")),
+				mcp.WithString("value", mcp.Required(), mcp.Description("Background value")),
+			),
+			mcp.NewTool("get_background",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("Get the background context stored under a key"),
+				mcp.WithString("key", mcp.Required(), mcp.Description("Background key")),
+			),
+		)
+	}
+	if h.scratchpads != nil {
+		conversation := mcp.WithString("conversation_id", mcp.Description("Conversation the scratchpad belongs to (defaults to the MCP session)"))
+		h.tools = append(h.tools,
+			mcp.NewTool("scratchpad_set",
+				mcp.WithDescription("Keep a value in this conversation's scratchpad: working memory for intermediate results that isn't shown to the user and lapses on its own. Use save_note for anything worth keeping"),
+				mcp.WithString("key", mcp.Required(), mcp.Description("Entry key, e.g. \"candidate_recipes\"")),
+				mcp.WithString("value", mcp.Required(), mcp.Description("Entry value, replacing any under the key")),
+				mcp.WithNumber("ttl_minutes", mcp.Description("How long to keep the entry (default 24 hours, at most 7 days)")),
+				conversation,
+			),
+			mcp.NewTool("scratchpad_get",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("Get a value from this conversation's scratchpad, or every entry in it when no key is given"),
+				mcp.WithString("key", mcp.Description("Entry key; omit to list the whole scratchpad")),
+				conversation,
+			),
+		)
+	}
+	if h.templateDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("apply_template",
+				mcp.WithDescription("Create todos from a saved template such as a packing list or weekly cleaning checklist"),
+				mcp.WithString("template", mcp.Required(), mcp.Description("Template UID or name")),
+				mcp.WithObject("params", mcp.Description("Values for the template's {{placeholders}}, e.g. {\"destination\": \"Lisbon\"}")),
+				mcp.WithString("start_date", mcp.Description("Date the items' due dates count from, in RFC3339 format (default now)")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+		)
+	}
+	if h.pantryDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("add_pantry_item",
+				mcp.WithDescription("Record something the household has in stock"),
+				mcp.WithString("item", mcp.Required(), mcp.Description("Item name, e.g. 'flour'")),
+				mcp.WithNumber("quantity", mcp.Description("Amount in stock; omit for staples tracked only by presence")),
+				mcp.WithString("unit", mcp.Description("Unit of the quantity, e.g. 'g', 'cup' or 'l'; omit for a count")),
+				mcp.WithString("expires_on", mcp.Description("Expiry date as YYYY-MM-DD")),
+				mcp.WithString("category", mcp.Description("Grocery aisle, e.g. 'produce' or 'dairy'; worked out from the name when omitted"), mcp.Enum(GroceryCategories...)),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+			mcp.NewTool("update_pantry_item",
+				mcp.WithDescription("Change the quantity, unit, name, category or expiry of a pantry item"),
+				mcp.WithString("pantry_item_id", mcp.Required(), mcp.Description("Pantry item ID")),
+				mcp.WithString("item", mcp.Description("New item name")),
+				mcp.WithNumber("quantity", mcp.Description("New amount in stock")),
+				mcp.WithString("unit", mcp.Description("New unit")),
+				mcp.WithString("expires_on", mcp.Description("New expiry date as YYYY-MM-DD")),
+				mcp.WithString("category", mcp.Description("New grocery aisle"), mcp.Enum(GroceryCategories...)),
+			),
+			mcp.NewTool("remove_pantry_item",
+				mcp.WithDescription("Remove an item from the pantry, e.g. once it is used up"),
+				mcp.WithString("pantry_item_id", mcp.Required(), mcp.Description("Pantry item ID")),
+			),
+			mcp.NewTool("list_pantry",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("List what the household has in stock, soonest to expire first"),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+				mcp.WithString("item", mcp.Description("Filter by item name")),
+				mcp.WithString("category", mcp.Description("Filter by grocery aisle"), mcp.Enum(GroceryCategories...)),
+				mcp.WithNumber("expiring_within_days", mcp.Description("Only items expiring within this many days")),
+			),
+		)
+	}
+	if h.purchaseDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("record_grocery_purchase",
+				mcp.WithDescription("Record what a shopping list item cost and where it was bought"),
+				mcp.WithString("item", mcp.Required(), mcp.Description("Item bought, e.g. 'milk'")),
+				mcp.WithNumber("price", mcp.Required(), mcp.Description("Price paid for the whole line, e.g. 4.99")),
+				mcp.WithString("store", mcp.Description("Store it was bought at")),
+				mcp.WithString("quantity", mcp.Description("How much was bought, e.g. '2 l'")),
+				mcp.WithString("purchased_on", mcp.Description("Purchase date as YYYY-MM-DD (default today)")),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+			mcp.NewTool("grocery_spend_report",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("Summarize a household's grocery spend per month and store, to answer budget questions"),
+				mcp.WithString("from", mcp.Description("First month as YYYY-MM (default two months before to)")),
+				mcp.WithString("to", mcp.Description("Last month as YYYY-MM (default this month)")),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+		)
+	}
+	if h.recipeClient != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("refresh_recipe",
+				mcp.WithDescription("Re-read a recipe from its external_url and update the fields that changed, reporting what changed"),
+				mcp.WithString("recipe_id", mcp.Required(), mcp.Description("Recipe ID")),
+			),
+		)
+	}
+	if h.calendarCreds != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("list_calendar_events",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("List events on the user's connected calendar (Google, iCloud, Fastmail or other CalDAV)"),
+				mcp.WithString("from", mcp.Description("First day as YYYY-MM-DD (default today)")),
+				mcp.WithNumber("days", mcp.Description("Number of days to cover (default 7, at most 31)")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			),
+			mcp.NewTool("create_calendar_event",
+				mcp.WithDescription("Add an event to the user's connected calendar"),
+				mcp.WithString("title", mcp.Required(), mcp.Description("Event title")),
+				mcp.WithString("start", mcp.Required(), mcp.Description("Start as YYYY-MM-DD for an all-day event, or a time like 2025-08-30T15:00:00+01:00")),
+				mcp.WithString("end", mcp.Description("End in the same form as start (default one hour, or one day, after start)")),
+				mcp.WithString("location", mcp.Description("Where the event is")),
+				mcp.WithString("description", mcp.Description("Notes for the event")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			),
+		)
+	}
+	if h.awayDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("set_away",
+				mcp.WithDescription("Mark a user as away, e.g. on holiday, so they get no reminders and the briefing says they are away; or end it early with back"),
+				mcp.WithString("starts_on", mcp.Description("First day away as YYYY-MM-DD (default today)")),
+				mcp.WithString("ends_on", mcp.Description("Last day away as YYYY-MM-DD; required unless back is true")),
+				mcp.WithString("note", mcp.Description("Where they are, e.g. 'Camping in Wales'")),
+				mcp.WithBoolean("back", mcp.Description("End the user's current and upcoming away periods instead")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			),
+		)
+	}
+	if h.datesDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("add_important_date",
+				mcp.WithDescription("Remember a date the household wants reminding of ahead of time, such as a birthday, anniversary or renewal. It shows in the briefing remind_days_ahead days before it comes round. For a task to do, create a todo instead"),
+				mcp.WithString("title", mcp.Required(), mcp.Description("What the date is, e.g. 'Grandma's birthday' or 'Car insurance renewal'")),
+				mcp.WithString("date", mcp.Required(), mcp.Description("The date as YYYY-MM-DD; for a birthday or anniversary, the original date so the years can be counted")),
+				mcp.WithString("kind", mcp.Description("birthday, anniversary, renewal or other (default other)"), mcp.Enum(importantDateKinds...)),
+				mcp.WithString("recurrence", mcp.Description("yearly, monthly or none (default yearly)"), mcp.Enum(importantDateRecurrences...)),
+				mcp.WithNumber("remind_days_ahead", mcp.Description("How many days ahead the briefing mentions it (default 7)")),
+				mcp.WithString("notes", mcp.Description("Anything worth knowing, e.g. gift ideas or the policy number")),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+		)
+	}
+	if h.myDayDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("add_to_my_day",
+				mcp.WithDescription("Put a todo on the user's My Day, the short list they mean to focus on today whatever its due date. My Day clears at the user's midnight"),
+				mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID to focus on today")),
+				mcp.WithString("timezone", mcp.Description("IANA timezone whose midnight clears My Day, e.g. Europe/London (default the user's quiet hours timezone, else UTC)")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			),
+			mcp.NewTool("remove_from_my_day",
+				mcp.WithDescription("Take a todo off the user's My Day; the todo itself is kept"),
+				mcp.WithString("todo_id", mcp.Required(), mcp.Description("Todo UID to take off My Day")),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+			),
+			mcp.NewTool("get_my_day",
+				mcp.WithDescription("List the todos on the user's My Day, in the order they were added"),
+				mcp.WithString("user_uid", mcp.Description("User ID (defaults to the authenticated user)")),
+				mcp.WithReadOnlyHintAnnotation(true),
+			),
+		)
+	}
+	if h.projectDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("create_project",
+				mcp.WithDescription("Create a project to group a household's todos, e.g. \"Repaint the garage\""),
+				mcp.WithString("name", mcp.Required(), mcp.Description("Project name")),
+				mcp.WithString("description", mcp.Description("What the project is for")),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+			),
+			mcp.NewTool("list_projects",
+				mcp.WithDescription("List a household's projects with how many of their todos are done"),
+				mcp.WithString("household_uid", mcp.Description("Household ID (defaults to the authenticated user's household)")),
+				mcp.WithBoolean("include_archived", mcp.Description("Also list archived projects")),
+				mcp.WithReadOnlyHintAnnotation(true),
+			),
+			mcp.NewTool("move_todos_to_project",
+				mcp.WithDescription("Put todos in a project, taking them out of any other, or take them out of it"),
+				mcp.WithString("project_id", mcp.Required(), mcp.Description("Project UID")),
+				mcp.WithArray("todo_ids", mcp.Required(), mcp.Description("Todo UIDs to move"), mcp.Items(map[string]any{"type": "string"})),
+				mcp.WithBoolean("remove", mcp.Description("Take the todos out of the project instead")),
+			),
+		)
+	}
+	if h.extraction != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("extract_todos",
+				mcp.WithDescription("Find the actionable todos in a note. First call without confirm to get proposals and show them to the user, then call again with confirm=true and the todos they agreed to (edited as they like) to create them"),
+				mcp.WithString("note_id", mcp.Required(), mcp.Description("Note to find todos in")),
+				mcp.WithBoolean("confirm", mcp.Description("Create the todos instead of only proposing them")),
+				mcp.WithArray("todos",
+					mcp.Description("Todos to create, as proposed by an earlier call; omit to use fresh proposals"),
+					mcp.Items(map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"title":       map[string]any{"type": "string"},
+							"description": map[string]any{"type": "string"},
+							"due_date":    map[string]any{"type": "string", "description": "YYYY-MM-DD"},
+						},
+						"required": []string{"title"},
+					}),
+				),
+			),
+		)
+	}
+	if h.dataSchemaDAO != nil {
+		h.tools = append(h.tools,
+			mcp.NewTool("get_data_schema",
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDescription("Get the JSON Schema that save_note or set_preference data for a key must match, if it has one"),
+				mcp.WithString("entity", mcp.Required(), mcp.Description("What the data is for"), mcp.Enum(dao.DataSchemaNotes, dao.DataSchemaPreferences)),
+				mcp.WithString("key", mcp.Required(), mcp.Description("Note or preference key")),
+			),
+		)
 	}
 }
 
-func (h *MCPHandlers) handleInitialize(ctx context.Context, params InitializeParams) InitializeResult {
-	h.clientInfo = &params.ClientInfo
+// handleInitialize negotiates the protocol version and starts a new session
+// for the client.
+func (h *MCPHandlers) handleInitialize(ctx context.Context, params InitializeParams) (InitializeResult, *mcpSession, error) {
+	version, err := negotiateProtocolVersion(params.ProtocolVersion)
+	if err != nil {
+		h.log().Warn("MCP client requested unsupported protocol version",
+			slog.String("client_name", params.ClientInfo.Name),
+			slog.String("protocol_version", params.ProtocolVersion),
+		)
+		return InitializeResult{}, nil, err
+	}
+
+	var identity *Identity
+	if id, ok := IdentityFromContext(ctx); ok {
+		identity = &id
+	}
+	session := h.sessions.Create(params.ClientInfo, params.Capabilities, version, identity)
 
 	h.log().Info("MCP client initialized",
 		slog.String("client_name", params.ClientInfo.Name),
 		slog.String("client_version", params.ClientInfo.Version),
-		slog.String("protocol_version", params.ProtocolVersion),
+		slog.String("protocol_version", version),
+		slog.String("session_id", session.ID),
 	)
 
 	return InitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: version,
 		Capabilities:    h.capabilities,
 		ServerInfo:      h.serverInfo,
 		Instructions:    "Assistant Server MCP provides tools for managing todos, notes, preferences, and recipes.",
-	}
+	}, session, nil
 }
 
 func (h *MCPHandlers) handleInitialized(ctx context.Context) {
 	h.log().Info("MCP server ready to handle requests")
 }
 
+// toolOK builds a successful tool result. The payload is returned as a JSON
+// object ({"status":"ok","summary":...,"todo":{...}}) both as text content and
+// as structuredContent, so callers never have to pull IDs out of prose.
+func toolOK(summary string, payload map[string]any) mcp.CallToolResult {
+	body := map[string]any{"status": "ok", "summary": summary}
+	for k, v := range payload {
+		body[k] = v
+	}
+	return toolJSON(body, false)
+}
+
+// toolError builds a failed tool result with a {"status":"error","error":...} body.
+func toolError(format string, args ...any) mcp.CallToolResult {
+	return toolJSON(map[string]any{"status": "error", "error": fmt.Sprintf(format, args...)}, true)
+}
+
+// toolCallResult adds structuredContent to the wire format; the custom
+// MarshalJSON on mcp.CallToolResult only emits content and isError.
+type toolCallResult struct{ mcp.CallToolResult }
+
+func (r toolCallResult) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(r.CallToolResult)
+	if err != nil || r.StructuredContent == nil {
+		return data, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	m["structuredContent"] = r.StructuredContent
+	return json.Marshal(m)
+}
+
+func toolJSON(body map[string]any, isError bool) mcp.CallToolResult {
+	text, err := json.Marshal(body)
+	if err != nil {
+		text = []byte(fmt.Sprintf(`{"status":"error","error":%q}`, "failed to encode result: "+err.Error()))
+		isError = true
+	}
+	return mcp.CallToolResult{
+		IsError:           isError,
+		Content:           []mcp.Content{mcp.TextContent{Type: "text", Text: string(text)}},
+		StructuredContent: body,
+	}
+}
+
+// priorityFromMCP reads the priority argument, a label or a number, which
+// defaults to medium.
+func priorityFromMCP(arguments map[string]any) (dao.Priority, error) {
+	switch p := arguments["priority"].(type) {
+	case nil:
+		return dao.PriorityMedium, nil
+	case string:
+		if p == "" {
+			return dao.PriorityMedium, nil
+		}
+		return dao.ParsePriority(p)
+	case float64:
+		return dao.ParsePriority(strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return 0, fmt.Errorf("%w: must be a string", dao.ErrInvalidPriority)
+}
+
 func (h *MCPHandlers) handleCreateTodo(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	h.log().Debug("Creating todo", slog.Any("arguments", arguments))
 
 	title, ok := arguments["title"].(string)
 	if !ok || title == "" {
 		h.log().Warn("Create todo failed: missing title", slog.Any("arguments", arguments))
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: title is required"}},
-		}
+		return toolError("title is required")
+	}
+
+	priority, err := priorityFromMCP(arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	if p := arguments["priority"]; p == nil || p == "" {
+		// Left for the title's quick-add markers; medium if it has none.
+		priority = 0
 	}
 
-	priority := 3
-	if p, ok := arguments["priority"].(float64); ok {
-		if p >= 1 && p <= 5 {
-			priority = int(p)
+	var status dao.TodoStatus
+	if s, _ := arguments["status"].(string); s != "" {
+		if status, err = dao.ParseTodoStatus(s); err != nil {
+			return toolError("%v", err)
 		}
 	}
 
@@ -304,18 +852,54 @@ func (h *MCPHandlers) handleCreateTodo(ctx context.Context, arguments map[string
 		}
 	}
 
+	location, err := todoLocationFromMCP(arguments)
+	if err != nil {
+		return toolError("Invalid location: %v", err)
+	}
+
+	var estimate *int
+	if n, ok := arguments["estimate_minutes"].(float64); ok {
+		m := int(n)
+		estimate = &m
+	}
+	var projectUID *string
+	if p, _ := arguments["project_uid"].(string); p != "" {
+		projectUID = &p
+	}
+
 	todo := dao.Todo{
-		UID:          uuid.NewString(),
-		Title:        title,
-		Description:  description,
-		Data:         "{}",
-		Priority:     dao.Priority(priority),
-		DueDate:      dueDate,
-		UserUID:      &userUID,
-		HouseholdUID: &householdUID,
+		UID:             uuid.NewString(),
+		Title:           title,
+		Description:     description,
+		Data:            "{}",
+		Priority:        priority,
+		Status:          status,
+		DueDate:         dueDate,
+		UserUID:         &userUID,
+		HouseholdUID:    &householdUID,
+		Location:        location,
+		EstimateMinutes: estimate,
+		ProjectUID:      projectUID,
+	}
+	if tags, _ := arguments["tags"].(string); tags != "" {
+		todo.Tags = splitTags(tags)
+	}
+	if quick, ok := arguments["quick_add"].(bool); !ok || quick {
+		// Dates like @sat are counted in the user's timezone.
+		loc := time.UTC
+		if h.preferencesDAO != nil && userUID != "" && strings.Contains(title, "@") {
+			loc, _ = myDayLocation(ctx, h.preferencesDAO, userUID, "")
+		}
+		quickAddTodo(&todo, time.Now().In(loc))
+	}
+	if todo.Priority == 0 {
+		todo.Priority = dao.PriorityMedium
 	}
 
 	created, err := h.todoDAO.CreateTodo(ctx, todo)
+	if errors.Is(err, dao.ErrQuotaExceeded) {
+		return quotaToolError(err)
+	}
 	if err != nil {
 		h.log().Error("Failed to create todo",
 			slog.String("error", err.Error()),
@@ -323,10 +907,7 @@ func (h *MCPHandlers) handleCreateTodo(ctx context.Context, arguments map[string
 			slog.String("user_uid", userUID),
 			slog.String("household_uid", householdUID),
 		)
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to create todo: %v", err)}},
-		}
+		return toolError("Failed to create todo: %v", err)
 	}
 
 	h.log().Info("Todo created successfully",
@@ -334,9 +915,7 @@ func (h *MCPHandlers) handleCreateTodo(ctx context.Context, arguments map[string
 		slog.String("title", created.Title),
 	)
 
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Todo created successfully with ID: %s", created.UID)}},
-	}
+	return toolOK("Todo created", map[string]any{"todo": created})
 }
 
 func (h *MCPHandlers) handleListTodos(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
@@ -348,7 +927,10 @@ func (h *MCPHandlers) handleListTodos(ctx context.Context, arguments map[string]
 	}
 
 	// Use shared filtering logic
-	filters := BuildFiltersFromMCP(arguments, TodoFilters.Filters)
+	filters, err := BuildFiltersFromMCP(arguments, TodoFilters.Filters)
+	if err != nil {
+		return toolError("Invalid filter: %v", err)
+	}
 	whereClause, whereArgs := BuildWhereClause(filters, TodoFilters.Filters)
 	options := dao.ListOptions{
 		Limit:       limit,
@@ -365,10 +947,7 @@ func (h *MCPHandlers) handleListTodos(ctx context.Context, arguments map[string]
 			slog.String("error", err.Error()),
 			slog.Any("filters", filters),
 		)
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to list todos: %v", err)}},
-		}
+		return toolError("Failed to list todos: %v", err)
 	}
 
 	h.log().Info("Listed todos successfully",
@@ -376,10 +955,7 @@ func (h *MCPHandlers) handleListTodos(ctx context.Context, arguments map[string]
 		slog.Int("limit", limit),
 	)
 
-	result, _ := json.Marshal(todos)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
-	}
+	return toolOK(fmt.Sprintf("Found %d todos", len(todos)), map[string]any{"todos": todos, "count": len(todos)})
 }
 
 func (h *MCPHandlers) handleCompleteTodo(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
@@ -388,10 +964,7 @@ func (h *MCPHandlers) handleCompleteTodo(ctx context.Context, arguments map[stri
 	todoID, ok := arguments["todo_id"].(string)
 	if !ok || todoID == "" {
 		h.log().Warn("Complete todo failed: missing todo_id", slog.Any("arguments", arguments))
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: todo_id is required"}},
-		}
+		return toolError("todo_id is required")
 	}
 
 	completedBy, _ := arguments["completed_by"].(string)
@@ -404,17 +977,14 @@ func (h *MCPHandlers) handleCompleteTodo(ctx context.Context, arguments map[stri
 		update.CompletedBy = &completedBy
 	}
 
-	_, err := h.todoDAO.UpdateTodo(ctx, todoID, update)
+	completed, err := h.todoDAO.UpdateTodo(ctx, todoID, update)
 	if err != nil {
 		h.log().Error("Failed to complete todo",
 			slog.String("error", err.Error()),
 			slog.String("todo_id", todoID),
 			slog.String("completed_by", completedBy),
 		)
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to complete todo: %v", err)}},
-		}
+		return toolError("Failed to complete todo: %v", err)
 	}
 
 	h.log().Info("Todo completed successfully",
@@ -422,26 +992,155 @@ func (h *MCPHandlers) handleCompleteTodo(ctx context.Context, arguments map[stri
 		slog.String("completed_by", completedBy),
 	)
 
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Todo %s marked as completed", todoID)}},
+	return toolOK("Todo marked as completed", map[string]any{"todo": completed})
+}
+
+func (h *MCPHandlers) handleSetTodoStatus(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	todoID, ok := arguments["todo_id"].(string)
+	if !ok || todoID == "" {
+		return toolError("todo_id is required")
+	}
+	s, _ := arguments["status"].(string)
+	status, err := dao.ParseTodoStatus(s)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	var completedBy *string
+	if by, _ := arguments["completed_by"].(string); by != "" && status == dao.TodoDone {
+		completedBy = &by
+	}
+
+	updated, err := h.todoDAO.SetTodoStatus(ctx, todoID, status, completedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return toolError("Todo not found: %s", todoID)
+	}
+	if err != nil {
+		h.log().Error("Failed to set todo status",
+			slog.String("error", err.Error()),
+			slog.String("todo_id", todoID),
+			slog.String("status", string(status)),
+		)
+		return toolError("Failed to set todo status: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Moved %s to %s", updated.Title, status), map[string]any{"todo": updated})
+}
+
+func (h *MCPHandlers) handleLinkTodos(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	todoID, ok := arguments["todo_id"].(string)
+	if !ok || todoID == "" {
+		return toolError("todo_id is required")
+	}
+	blockedByID, _ := arguments["blocked_by_id"].(string)
+	blocksID, _ := arguments["blocks_id"].(string)
+	if (blockedByID == "") == (blocksID == "") {
+		return toolError("exactly one of blocked_by_id or blocks_id is required")
+	}
+
+	// Normalise to "todo waits on blocker".
+	todo, blocker := todoID, blockedByID
+	if blocksID != "" {
+		todo, blocker = blocksID, todoID
+	}
+	for _, uid := range []string{todo, blocker} {
+		if _, err := h.todoDAO.GetTodo(ctx, uid); err != nil {
+			return toolError("Todo not found: %s", uid)
+		}
+	}
+
+	if unlink, _ := arguments["unlink"].(bool); unlink {
+		if err := h.todoDAO.RemoveTodoDependency(ctx, todo, blocker); err != nil {
+			return toolError("Failed to unlink todos: %v", err)
+		}
+		return toolOK("Todos unlinked", map[string]any{"todo_uid": todo, "blocked_by_uid": blocker})
+	}
+
+	if err := h.todoDAO.AddTodoDependency(ctx, todo, blocker); err != nil {
+		if errors.Is(err, dao.ErrDependencyCycle) {
+			return toolError("Cannot link todos: %v", err)
+		}
+		h.log().Error("Failed to link todos",
+			slog.String("error", err.Error()),
+			slog.String("todo_uid", todo),
+			slog.String("blocked_by_uid", blocker),
+		)
+		return toolError("Failed to link todos: %v", err)
+	}
+	return toolOK("Todos linked", map[string]any{"todo_uid": todo, "blocked_by_uid": blocker})
+}
+
+func (h *MCPHandlers) handleLogTime(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	todoID, ok := arguments["todo_id"].(string)
+	if !ok || todoID == "" {
+		return toolError("todo_id is required")
+	}
+	minutes, _ := arguments["minutes"].(float64)
+	req := logTimeRequest{Minutes: int(minutes)}
+	req.UserUID, _ = arguments["user_uid"].(string)
+	req.LoggedOn, _ = arguments["logged_on"].(string)
+	req.Note, _ = arguments["note"].(string)
+	l, err := newTodoTimeLog(todoID, req, time.Now())
+	if err != nil {
+		return toolError("%v", err)
+	}
+
+	logged, err := h.todoDAO.LogTodoTime(ctx, l)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return toolError("Todo not found: %s", todoID)
+	case errors.Is(err, dao.ErrUnknownTimeUser):
+		return toolError("%v", err)
+	case err != nil:
+		h.log().Error("Failed to log time",
+			slog.String("error", err.Error()),
+			slog.String("todo_id", todoID),
+		)
+		return toolError("Failed to log time: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Logged %s on %s", formatMinutes(int64(logged.Minutes)), logged.LoggedOn.Format(time.DateOnly)),
+		map[string]any{"log": logged})
+}
+
+func (h *MCPHandlers) handleTimeReport(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	userUID, _ := arguments["user_uid"].(string)
+	if householdUID == "" && userUID == "" {
+		return toolError("household_uid or user_uid is required")
 	}
+	fromArg, _ := arguments["from"].(string)
+	toArg, _ := arguments["to"].(string)
+	from, to, err := parseDateRange(fromArg, toArg, time.Now())
+	if err != nil {
+		return toolError("%v", err)
+	}
+	title, _ := arguments["title"].(string)
+	title = strings.TrimSpace(title)
+
+	report, err := todoTimeReport(ctx, h.todoDAO, householdUID, userUID, from, to, title)
+	if err != nil {
+		return toolError("Failed to build time report: %v", err)
+	}
+	on := "todos"
+	if title != "" {
+		on = fmt.Sprintf("todos matching %q", title)
+	}
+	return toolOK(fmt.Sprintf("Logged %s on %s from %s to %s", formatMinutes(report.TotalMinutes), on, report.From, report.To),
+		map[string]any{"report": report})
 }
 
 func (h *MCPHandlers) handleSaveNote(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	key, ok := arguments["key"].(string)
 	if !ok || key == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: key is required"}},
-		}
+		return toolError("key is required")
 	}
 
 	data, ok := arguments["data"].(string)
 	if !ok || data == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: data is required"}},
-		}
+		return toolError("data is required")
+	}
+
+	visibility, _ := arguments["visibility"].(string)
+	if visibility != "" && !validNoteVisibility(visibility) {
+		return toolError("visibility must be one of %s, %s or %s", dao.NoteVisibilityPrivate, dao.NoteVisibilityHousehold, dao.NoteVisibilitySharedLink)
 	}
 
 	userUID, _ := arguments["user_uid"].(string)
@@ -456,6 +1155,24 @@ func (h *MCPHandlers) handleSaveNote(ctx context.Context, arguments map[string]a
 		}
 	}
 
+	if result := checkToolData(ctx, h.dataSchemaDAO, dao.DataSchemaNotes, key, data); result != nil {
+		return *result
+	}
+
+	remindAt, err := remindAtFromMCP(arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+
+	suggested := suggestTags(ctx, h.tagger, tags, key+"\n"+data)
+	if autoTag, _ := arguments["auto_tag"].(bool); autoTag {
+		tags = append(tags, suggested...)
+	}
+
+	if noteID, _ := arguments["note_id"].(string); noteID != "" {
+		return h.rewriteNote(ctx, noteID, key, data, tags, visibility, remindAt)
+	}
+
 	note := dao.Notes{
 		ID:           uuid.NewString(),
 		Key:          key,
@@ -463,42 +1180,177 @@ func (h *MCPHandlers) handleSaveNote(ctx context.Context, arguments map[string]a
 		HouseholdUID: &householdUID,
 		Data:         data,
 		Tags:         tags,
+		Visibility:   visibility,
+		RemindAt:     remindAt,
 	}
 
 	created, err := h.notesDAO.CreateNotes(ctx, note)
+	if errors.Is(err, dao.ErrQuotaExceeded) {
+		return quotaToolError(err)
+	}
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to save note: %v", err)}},
-		}
+		return toolError("Failed to save note: %v", err)
 	}
 
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Note saved successfully with ID: %s", created.ID)}},
+	result := map[string]any{"note": created}
+	if len(suggested) > 0 {
+		result["suggested_tags"] = suggested
+	}
+	return toolOK("Note saved", result)
+}
+
+// rewriteNote replaces an existing note's key and data, and its tags,
+// visibility and reminder when given. It refuses while another key holds
+// the note's lock, as a person may be editing it.
+func (h *MCPHandlers) rewriteNote(ctx context.Context, noteID, key, data string, tags []string, visibility string, remindAt *time.Time) mcp.CallToolResult {
+	note, err := h.notesDAO.GetNotes(ctx, noteID)
+	if err != nil || !noteAccessible(ctx, note) {
+		return toolError("Note not found: %s", noteID)
+	}
+	if l := lockedByOther(ctx, h.locksDAO, lockEntityNotes, noteID); l != nil {
+		return lockedToolError(*l, "note")
+	}
+	note.Key, note.Data, note.Visibility = key, data, visibility
+	if tags != nil {
+		note.Tags = tags
 	}
+	if remindAt != nil {
+		note.RemindAt = remindAt
+	}
+	updated, err := h.notesDAO.UpdateNotes(ctx, noteID, note)
+	if err != nil {
+		return toolError("Failed to save note: %v", err)
+	}
+	return toolOK("Note saved", map[string]any{"note": updated})
 }
 
 func (h *MCPHandlers) handleRecallNote(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	noteID, ok := arguments["note_id"].(string)
 	if !ok || noteID == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: note_id is required"}},
-		}
+		return toolError("note_id is required")
 	}
 
 	note, err := h.notesDAO.GetNotes(ctx, noteID)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Note not found: %v", err)}},
+		return toolError("Note not found: %v", err)
+	}
+	if !noteAccessible(ctx, note) {
+		return toolError("Note not found: %s", noteID)
+	}
+
+	result := map[string]any{"note": note}
+	if l := currentLock(ctx, h.locksDAO, lockEntityNotes, noteID); l != nil {
+		result["lock"] = l
+	}
+	return toolOK("Note found", result)
+}
+
+func (h *MCPHandlers) handleDeleteNote(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	noteID, ok := arguments["note_id"].(string)
+	if !ok || noteID == "" {
+		return toolError("note_id is required")
+	}
+
+	if _, ok := IdentityFromContext(ctx); ok {
+		note, err := h.notesDAO.GetNotes(ctx, noteID)
+		if err != nil || !noteAccessible(ctx, note) {
+			return toolError("Note not found: %s", noteID)
 		}
 	}
+	if l := lockedByOther(ctx, h.locksDAO, lockEntityNotes, noteID); l != nil {
+		return lockedToolError(*l, "note")
+	}
 
-	result, _ := json.Marshal(note)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
+	if err := h.notesDAO.DeleteNotes(ctx, noteID); err != nil {
+		return toolError("Failed to delete note: %v", err)
+	}
+
+	return toolOK("Note deleted", map[string]any{"note_id": noteID})
+}
+
+func (h *MCPHandlers) handlePinNote(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	noteID, ok := arguments["note_id"].(string)
+	if !ok || noteID == "" {
+		return toolError("note_id is required")
+	}
+
+	pinned := true
+	if p, ok := arguments["pinned"].(bool); ok {
+		pinned = p
+	}
+	sortOrder := 0
+	if o, ok := arguments["sort_order"].(float64); ok && pinned {
+		sortOrder = int(o)
+	}
+
+	if _, ok := IdentityFromContext(ctx); ok {
+		note, err := h.notesDAO.GetNotes(ctx, noteID)
+		if err != nil || !noteAccessible(ctx, note) {
+			return toolError("Note not found: %s", noteID)
+		}
+	}
+
+	note, err := h.notesDAO.SetNotePinned(ctx, noteID, pinned, sortOrder)
+	if err != nil {
+		return toolError("Failed to pin note: %v", err)
+	}
+
+	if !pinned {
+		return toolOK("Note unpinned", map[string]any{"note": note})
+	}
+	return toolOK("Note pinned", map[string]any{"note": note})
+}
+
+func (h *MCPHandlers) handleRemindMe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	noteID, ok := arguments["note_id"].(string)
+	if !ok || noteID == "" {
+		return toolError("note_id is required")
+	}
+
+	cancel, _ := arguments["cancel"].(bool)
+	remindAt, err := remindAtFromMCP(arguments)
+	switch {
+	case err != nil:
+		return toolError("%v", err)
+	case cancel && remindAt != nil:
+		return toolError("remind_at can't be given when cancelling")
+	case !cancel && remindAt == nil:
+		return toolError("remind_at is required")
+	}
+
+	if _, ok := IdentityFromContext(ctx); ok {
+		note, err := h.notesDAO.GetNotes(ctx, noteID)
+		if err != nil || !noteAccessible(ctx, note) {
+			return toolError("Note not found: %s", noteID)
+		}
+	}
+
+	note, err := h.notesDAO.SetNoteReminder(ctx, noteID, remindAt)
+	if err != nil {
+		return toolError("Failed to set reminder: %v", err)
+	}
+
+	if cancel {
+		return toolOK("Reminder cancelled", map[string]any{"note": note})
+	}
+	return toolOK("Reminder set", map[string]any{"note": note})
+}
+
+// remindAtFromMCP parses the optional remind_at argument, which must be in
+// the future.
+func remindAtFromMCP(arguments map[string]any) (*time.Time, error) {
+	s, _ := arguments["remind_at"].(string)
+	if s == "" {
+		return nil, nil
+	}
+	remindAt, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, errors.New("remind_at must be in RFC3339 format, e.g. 2026-05-01T09:00:00Z")
+	}
+	if !remindAt.After(time.Now()) {
+		return nil, errors.New("remind_at must be in the future")
 	}
+	return &remindAt, nil
 }
 
 func (h *MCPHandlers) handleListNotes(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
@@ -508,8 +1360,15 @@ func (h *MCPHandlers) handleListNotes(ctx context.Context, arguments map[string]
 	}
 
 	// Use shared filtering logic
-	filters := BuildFiltersFromMCP(arguments, NotesFilters.Filters)
+	filters, err := BuildFiltersFromMCP(arguments, NotesFilters.Filters)
+	if err != nil {
+		return toolError("Invalid filter: %v", err)
+	}
+	if includeArchived, _ := arguments["include_archived"].(bool); !includeArchived {
+		filters = append(filters, Filter{Column: "archived_at", Op: OpNull})
+	}
 	whereClause, whereArgs := BuildWhereClause(filters, NotesFilters.Filters)
+	whereClause, whereArgs = withNoteVisibility(ctx, whereClause, whereArgs)
 	options := dao.ListOptions{
 		Limit:       limit,
 		Offset:      0,
@@ -521,41 +1380,78 @@ func (h *MCPHandlers) handleListNotes(ctx context.Context, arguments map[string]
 
 	notes, err := h.notesDAO.ListNotes(ctx, options)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to list notes: %v", err)}},
-		}
+		return toolError("Failed to list notes: %v", err)
 	}
 
-	result, _ := json.Marshal(notes)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
-	}
+	return toolOK(fmt.Sprintf("Found %d notes", len(notes)), map[string]any{"notes": notes, "count": len(notes)})
 }
 
-func (h *MCPHandlers) handleSetPreference(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
-	key, ok := arguments["key"].(string)
-	if !ok || key == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: key is required"}},
-		}
+func (h *MCPHandlers) handleSetNotificationPreference(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
+	}
+	prefs, err := loadNotificationPreferences(ctx, h.preferencesDAO, userUID)
+	if err != nil {
+		return toolError("Failed to read notification preferences: %v", err)
 	}
 
-	specifier, ok := arguments["specifier"].(string)
-	if !ok || specifier == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: specifier is required"}},
+	if channel, _ := arguments["channel"].(string); channel != "" {
+		enabled, ok := arguments["enabled"].(bool)
+		if !ok {
+			return toolError("enabled is required with channel")
+		}
+		prefs.Channels[channel] = enabled
+	}
+	if category, _ := arguments["category"].(string); category != "" {
+		delivery, _ := arguments["delivery"].(string)
+		if delivery == "" {
+			return toolError("delivery is required with category")
 		}
+		prefs.Categories[category] = delivery
+	}
+	if frequency, _ := arguments["digest_frequency"].(string); frequency != "" {
+		prefs.DigestFrequency = frequency
+	}
+	if quiet, _ := arguments["quiet_hours"].(string); quiet == DeliveryOff {
+		prefs.QuietHours = nil
+	} else if quiet != "" {
+		start, end, ok := strings.Cut(quiet, "-")
+		if !ok {
+			return toolError("quiet_hours must look like 22:00-07:00")
+		}
+		prefs.QuietHours = &QuietHours{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+	}
+	if tz, _ := arguments["timezone"].(string); tz != "" {
+		if prefs.QuietHours == nil {
+			return toolError("timezone only applies to quiet_hours")
+		}
+		prefs.QuietHours.Timezone = tz
+	}
+
+	if err := prefs.Validate(); err != nil {
+		return toolError("%v", err)
+	}
+	if err := saveNotificationPreferences(ctx, h.preferencesDAO, prefs); err != nil {
+		return toolError("Failed to save notification preferences: %v", err)
+	}
+	return toolOK("Notification preferences saved", map[string]any{"notification_preferences": prefs})
+}
+
+func (h *MCPHandlers) handleSetPreference(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	key, ok := arguments["key"].(string)
+	if !ok || key == "" {
+		return toolError("key is required")
+	}
+
+	specifier, ok := arguments["specifier"].(string)
+	if !ok || specifier == "" {
+		return toolError("specifier is required")
 	}
 
 	data, ok := arguments["data"].(string)
 	if !ok || data == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: data is required"}},
-		}
+		return toolError("data is required")
 	}
 
 	tagsStr, _ := arguments["tags"].(string)
@@ -567,6 +1463,10 @@ func (h *MCPHandlers) handleSetPreference(ctx context.Context, arguments map[str
 		}
 	}
 
+	if result := checkToolData(ctx, h.dataSchemaDAO, dao.DataSchemaPreferences, key, data); result != nil {
+		return *result
+	}
+
 	pref := dao.Preferences{
 		Key:       key,
 		Specifier: specifier,
@@ -575,78 +1475,140 @@ func (h *MCPHandlers) handleSetPreference(ctx context.Context, arguments map[str
 	}
 
 	if _, err := h.preferencesDAO.GetPreferences(ctx, key, specifier); err == nil {
-		_, err = h.preferencesDAO.UpdatePreferences(ctx, key, specifier, pref)
+		updated, err := h.preferencesDAO.UpdatePreferences(ctx, key, specifier, pref)
 		if err != nil {
-			return mcp.CallToolResult{
-				IsError: true,
-				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to update preference: %v", err)}},
-			}
-		}
-		return mcp.CallToolResult{
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Preference updated: %s/%s", key, specifier)}},
+			return toolError("Failed to update preference: %v", err)
 		}
+		return toolOK("Preference updated", map[string]any{"preference": updated})
 	} else {
-		_, err = h.preferencesDAO.CreatePreferences(ctx, pref)
+		created, err := h.preferencesDAO.CreatePreferences(ctx, pref)
 		if err != nil {
-			return mcp.CallToolResult{
-				IsError: true,
-				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to create preference: %v", err)}},
-			}
-		}
-		return mcp.CallToolResult{
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Preference created: %s/%s", key, specifier)}},
+			return toolError("Failed to create preference: %v", err)
 		}
+		return toolOK("Preference created", map[string]any{"preference": created})
 	}
 }
 
 func (h *MCPHandlers) handleGetPreference(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	key, ok := arguments["key"].(string)
 	if !ok || key == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: key is required"}},
-		}
+		return toolError("key is required")
 	}
 
 	specifier, ok := arguments["specifier"].(string)
 	if !ok || specifier == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: specifier is required"}},
-		}
+		return toolError("specifier is required")
 	}
 
 	pref, err := h.preferencesDAO.GetPreferences(ctx, key, specifier)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Preference not found: %v", err)}},
+		return toolError("Preference not found: %v", err)
+	}
+
+	return toolOK("Preference found", map[string]any{"preference": pref})
+}
+
+// maxBulkPreferences caps how many preferences one bulk tool call reads or
+// writes.
+const maxBulkPreferences = 50
+
+type bulkPreference struct {
+	Key       string `json:"key"`
+	Specifier string `json:"specifier"`
+	Data      string `json:"data"`
+	Tags      string `json:"tags"`
+}
+
+// bulkPreferences reads the preferences argument of the bulk tools. Every
+// entry needs a key and specifier, and none may be repeated.
+func bulkPreferences(arguments map[string]any) ([]bulkPreference, error) {
+	var prefs []bulkPreference
+	b, err := json.Marshal(arguments["preferences"])
+	if err != nil || json.Unmarshal(b, &prefs) != nil || len(prefs) == 0 {
+		return nil, errors.New("preferences must be a non-empty list of objects with key and specifier")
+	}
+	if len(prefs) > maxBulkPreferences {
+		return nil, fmt.Errorf("at most %d preferences can be handled at once", maxBulkPreferences)
+	}
+	seen := map[dao.PreferenceKey]bool{}
+	for i, p := range prefs {
+		k := dao.PreferenceKey{Key: p.Key, Specifier: p.Specifier}
+		if k.Key == "" || k.Specifier == "" {
+			return nil, fmt.Errorf("preferences[%d]: key and specifier are required", i)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("preferences[%d]: %s/%s is repeated", i, k.Key, k.Specifier)
 		}
+		seen[k] = true
 	}
+	return prefs, nil
+}
 
-	result, _ := json.Marshal(pref)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
+func (h *MCPHandlers) handleGetPreferencesBulk(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	prefs, err := bulkPreferences(arguments)
+	if err != nil {
+		return toolError("%v", err)
 	}
+	keys := make([]dao.PreferenceKey, len(prefs))
+	for i, p := range prefs {
+		keys[i] = dao.PreferenceKey{Key: p.Key, Specifier: p.Specifier}
+	}
+	found, err := h.preferencesDAO.GetPreferencesBulk(ctx, keys)
+	if err != nil {
+		return toolError("Failed to get preferences: %v", err)
+	}
+	missing := []dao.PreferenceKey{}
+	for _, k := range keys {
+		if !slices.ContainsFunc(found, func(p dao.Preferences) bool { return p.Key == k.Key && p.Specifier == k.Specifier }) {
+			missing = append(missing, k)
+		}
+	}
+	return toolOK(fmt.Sprintf("Found %d of %d preferences", len(found), len(keys)), map[string]any{"preferences": found, "missing": missing})
+}
+
+func (h *MCPHandlers) handleSetPreferencesBulk(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	prefs, err := bulkPreferences(arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	toSave := make([]dao.Preferences, len(prefs))
+	for i, p := range prefs {
+		if p.Data == "" {
+			return toolError("preferences[%d]: data is required", i)
+		}
+		if result := checkToolData(ctx, h.dataSchemaDAO, dao.DataSchemaPreferences, p.Key, p.Data); result != nil {
+			return *result
+		}
+		toSave[i] = dao.Preferences{Key: p.Key, Specifier: p.Specifier, Data: p.Data}
+		if p.Tags != "" {
+			for _, tag := range strings.Split(p.Tags, ",") {
+				toSave[i].Tags = append(toSave[i].Tags, strings.TrimSpace(tag))
+			}
+		}
+		// Checking each preference is a step, and saving them all the last.
+		reportProgress(ctx, float64(i+1), float64(len(prefs)+1), "Checked "+p.Key)
+	}
+	saved, err := h.preferencesDAO.SetPreferencesBulk(ctx, toSave)
+	if err != nil {
+		return toolError("Failed to save preferences, none were saved: %v", err)
+	}
+	reportProgress(ctx, float64(len(prefs)+1), float64(len(prefs)+1), fmt.Sprintf("Saved %d preferences", len(saved)))
+	return toolOK(fmt.Sprintf("Saved %d preferences", len(saved)), map[string]any{"preferences": saved})
 }
 
 func (h *MCPHandlers) handleSaveRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	title, ok := arguments["title"].(string)
 	if !ok || title == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: title is required"}},
-		}
+		return toolError("title is required")
 	}
 
 	data, ok := arguments["data"].(string)
 	if !ok || data == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: data is required"}},
-		}
+		return toolError("data is required")
 	}
 
+	externalURL, _ := arguments["external_url"].(string)
+	externalURL = normalizeRecipeURL(externalURL)
 	genre, _ := arguments["genre"].(string)
 	groceryList, _ := arguments["grocery_list"].(string)
 	userUID, _ := arguments["user_uid"].(string)
@@ -661,150 +1623,954 @@ func (h *MCPHandlers) handleSaveRecipe(ctx context.Context, arguments map[string
 		}
 	}
 
-	var prepTime, cookTime, servings, difficulty, rating *int
-	if pt, ok := arguments["prep_time"].(float64); ok {
-		prepTime = &[]int{int(pt)}[0]
+	var prepTime, cookTime, servings, difficulty, rating *int
+	if pt, ok := arguments["prep_time"].(float64); ok {
+		prepTime = &[]int{int(pt)}[0]
+	}
+	if ct, ok := arguments["cook_time"].(float64); ok {
+		cookTime = &[]int{int(ct)}[0]
+	}
+	if s, ok := arguments["servings"].(float64); ok {
+		servings = &[]int{int(s)}[0]
+	}
+	if d, ok := arguments["difficulty"].(float64); ok && d >= 1 && d <= 5 {
+		difficulty = &[]int{int(d)}[0]
+	}
+	if r, ok := arguments["rating"].(float64); ok && r >= 1 && r <= 5 {
+		rating = &[]int{int(r)}[0]
+	}
+
+	totalTime := 0
+	if prepTime != nil && cookTime != nil {
+		totalTime = *prepTime + *cookTime
+	}
+	var totalTimePtr *int
+	if totalTime > 0 {
+		totalTimePtr = &totalTime
+	}
+
+	var externalURLPtr, genrePtr, groceryListPtr, difficultyPtr *string
+	if externalURL != "" {
+		externalURLPtr = &externalURL
+	}
+	if genre != "" {
+		genrePtr = &genre
+	}
+	if groceryList != "" {
+		groceryListPtr = &groceryList
+	}
+	if difficulty != nil {
+		difficultyStr := strconv.Itoa(*difficulty)
+		difficultyPtr = &difficultyStr
+	}
+
+	recipe := dao.Recipes{
+		ID:           uuid.NewString(),
+		Title:        title,
+		ExternalURL:  externalURLPtr,
+		Data:         data,
+		Genre:        genrePtr,
+		GroceryList:  groceryListPtr,
+		PrepTime:     prepTime,
+		CookTime:     cookTime,
+		TotalTime:    totalTimePtr,
+		Servings:     servings,
+		Difficulty:   difficultyPtr,
+		Tags:         tags,
+		UserUID:      &userUID,
+		HouseholdUID: &householdUID,
+	}
+
+	suggested := suggestTags(ctx, h.tagger, recipe.Tags, recipeTagText(recipe))
+	if autoTag, _ := arguments["auto_tag"].(bool); autoTag {
+		recipe.Tags = append(recipe.Tags, suggested...)
+	}
+
+	// Saving a page the household already has updates the recipe from it
+	// rather than adding a duplicate.
+	var existing *dao.Recipes
+	if externalURL != "" {
+		var err error
+		if existing, err = h.recipeByURL(ctx, userUID, householdUID, externalURL); err != nil {
+			return toolError("Failed to save recipe: %v", err)
+		}
+	}
+
+	var saved dao.Recipes
+	var err error
+	if existing != nil {
+		saved, err = h.recipesDAO.UpdateRecipes(ctx, existing.ID, mergeRecipe(*existing, recipe))
+	} else {
+		saved, err = h.recipesDAO.CreateRecipes(ctx, recipe)
+	}
+	if errors.Is(err, dao.ErrQuotaExceeded) {
+		return quotaToolError(err)
+	}
+	if err != nil {
+		return toolError("Failed to save recipe: %v", err)
+	}
+	if rating != nil && userUID != "" {
+		if saved, err = h.recipesDAO.RateRecipe(ctx, saved.ID, userUID, *rating); err != nil {
+			return toolError("Failed to rate recipe: %v", err)
+		}
+	}
+
+	result := map[string]any{"recipe": saved}
+	if len(suggested) > 0 {
+		result["suggested_tags"] = suggested
+	}
+	if existing != nil {
+		result["updated"] = true
+		return toolOK("Recipe from that URL was already saved; updated it", result)
+	}
+	return toolOK("Recipe saved", result)
+}
+
+func (h *MCPHandlers) handleFindRecipes(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	limit := 20
+	if l, ok := arguments["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	// Use shared filtering logic
+	filters, err := BuildFiltersFromMCP(arguments, RecipesFilters.Filters)
+	if err != nil {
+		return toolError("Invalid filter: %v", err)
+	}
+
+	// Handle special min_rating filter
+	if minRating, ok := arguments["min_rating"].(float64); ok {
+		filters = append(filters, Filter{Column: "rating", Op: OpGe, Value: strconv.Itoa(int(minRating))})
+	}
+
+	whereClause, whereArgs := BuildWhereClause(filters, RecipesFilters.Filters)
+	options := dao.ListOptions{
+		Limit:       limit,
+		Offset:      0,
+		SortBy:      "rating",
+		SortDir:     "DESC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	}
+
+	recipes, err := h.recipesDAO.ListRecipes(ctx, options)
+	if err != nil {
+		return toolError("Failed to find recipes: %v", err)
+	}
+
+	return toolOK(fmt.Sprintf("Found %d recipes", len(recipes)), map[string]any{"recipes": withPhotoURLsList(recipes), "count": len(recipes)})
+}
+
+func (h *MCPHandlers) handleGetRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	recipeID, ok := arguments["recipe_id"].(string)
+	if !ok || recipeID == "" {
+		return toolError("recipe_id is required")
+	}
+
+	recipe, err := h.recipesDAO.GetRecipes(ctx, recipeID)
+	if err != nil {
+		return toolError("Recipe not found: %v", err)
+	}
+
+	return toolOK("Recipe found", map[string]any{"recipe": withUnits(withPhotoURLs(recipe), householdUnits(ctx, h.preferencesDAO, recipe.HouseholdUID))})
+}
+
+func (h *MCPHandlers) handleRateRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	recipeID, ok := arguments["recipe_id"].(string)
+	if !ok || recipeID == "" {
+		return toolError("recipe_id is required")
+	}
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
+	}
+	rating, ok := arguments["rating"].(float64)
+	if !ok || rating != float64(int(rating)) {
+		return toolError("rating must be a whole number from 1 to 5")
+	}
+
+	recipe, err := h.recipesDAO.RateRecipe(ctx, recipeID, userUID, int(rating))
+	if errors.Is(err, dao.ErrInvalidRating) {
+		return toolError("%v", err)
+	}
+	if err != nil {
+		return toolError("Failed to rate recipe: %v", err)
+	}
+	summary := fmt.Sprintf("Rated %s %d", recipe.Title, int(rating))
+	if recipe.Rating != nil {
+		summary += fmt.Sprintf("; household average %.1f from %d ratings", *recipe.Rating, recipe.RatingCount)
+	}
+	return toolOK(summary, map[string]any{"recipe": withPhotoURLs(recipe)})
+}
+
+func (h *MCPHandlers) handleDuplicateRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	recipeID, ok := arguments["recipe_id"].(string)
+	if !ok || recipeID == "" {
+		return toolError("recipe_id is required")
+	}
+
+	var o RecipeOverrides
+	for arg, dst := range map[string]**string{
+		"title": &o.Title, "data": &o.Data, "genre": &o.Genre, "grocery_list": &o.GroceryList,
+		"user_uid": &o.UserUID, "household_uid": &o.HouseholdUID,
+	} {
+		if v, ok := arguments[arg].(string); ok && v != "" {
+			*dst = &v
+		}
+	}
+	for arg, dst := range map[string]**int{"prep_time": &o.PrepTime, "cook_time": &o.CookTime, "servings": &o.Servings} {
+		if v, ok := arguments[arg].(float64); ok {
+			*dst = &[]int{int(v)}[0]
+		}
+	}
+	if tags, ok := arguments["tags"].(string); ok && tags != "" {
+		t := splitTags(tags)
+		o.Tags = &t
+	}
+
+	orig, err := h.recipesDAO.GetRecipes(ctx, recipeID)
+	if err != nil {
+		return toolError("Recipe not found: %v", err)
+	}
+	recipe, err := h.recipesDAO.CreateRecipes(ctx, duplicateRecipe(orig, o))
+	if errors.Is(err, dao.ErrQuotaExceeded) {
+		return quotaToolError(err)
+	}
+	if err != nil {
+		return toolError("Failed to duplicate recipe: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Copied %s to %s", orig.Title, recipe.Title), map[string]any{"recipe": withPhotoURLs(recipe), "original_id": orig.ID})
+}
+
+func (h *MCPHandlers) handleDeleteRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	recipeID, ok := arguments["recipe_id"].(string)
+	if !ok || recipeID == "" {
+		return toolError("recipe_id is required")
+	}
+
+	if err := h.recipesDAO.DeleteRecipes(ctx, recipeID); err != nil {
+		return toolError("Failed to delete recipe: %v", err)
+	}
+
+	return toolOK("Recipe deleted", map[string]any{"recipe_id": recipeID})
+}
+
+func (h *MCPHandlers) handleConvertUnits(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	input, _ := arguments["quantity"].(string)
+	to, _ := arguments["to"].(string)
+	ingredient, _ := arguments["ingredient"].(string)
+	if input == "" || to == "" {
+		return toolError("quantity and to are required")
+	}
+
+	q, err := measurement.Parse(input)
+	if err != nil {
+		return toolError("Invalid quantity: %v", err)
+	}
+
+	var out measurement.Quantity
+	switch sys := measurement.System(strings.ToLower(to)); sys {
+	case measurement.Metric, measurement.Imperial:
+		out = q.ToSystem(sys)
+	default:
+		unit, err := measurement.ParseUnit(to)
+		if err != nil {
+			return toolError("Invalid unit: %v", err)
+		}
+		if out, err = q.Convert(unit, ingredient); err != nil {
+			return toolError("Cannot convert %s to %s: %v", q, unit.Name, err)
+		}
+	}
+
+	return toolOK(fmt.Sprintf("%s = %s", q, out), map[string]any{
+		"quantity": out.String(),
+		"amount":   math.Round(out.Amount*100) / 100,
+		"unit":     out.Unit.Name,
+	})
+}
+
+func (h *MCPHandlers) handleSetBackground(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	key, ok := arguments["key"].(string)
+	if !ok || key == "" {
+		return toolError("key is required")
+	}
+
+	if strings.HasPrefix(key, scratchpadKeyPrefix) {
+		return toolError("Keys starting with %s belong to scratchpads; use scratchpad_set", scratchpadKeyPrefix)
+	}
+
+	value, ok := arguments["value"].(string)
+	if !ok || value == "" {
+		return toolError("value is required")
+	}
+
+	background := dao.Background{Key: key, Value: value}
+
+	if _, err := h.backgroundDAO.GetBackground(ctx, key); err == nil {
+		updated, err := h.backgroundDAO.UpdateBackground(ctx, key, background)
+		if err != nil {
+			return toolError("Failed to update background: %v", err)
+		}
+		return toolOK("Background updated", map[string]any{"background": updated})
+	}
+
+	created, err := h.backgroundDAO.CreateBackground(ctx, background)
+	if err != nil {
+		return toolError("Failed to save background: %v", err)
+	}
+	return toolOK("Background saved", map[string]any{"background": created})
+}
+
+func (h *MCPHandlers) handleGetBackground(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	key, ok := arguments["key"].(string)
+	if !ok || key == "" {
+		return toolError("key is required")
+	}
+
+	if strings.HasPrefix(key, scratchpadKeyPrefix) {
+		return toolError("Keys starting with %s belong to scratchpads; use scratchpad_get", scratchpadKeyPrefix)
+	}
+
+	background, err := h.backgroundDAO.GetBackground(ctx, key)
+	if err != nil {
+		return toolError("Background not found: %v", err)
+	}
+
+	return toolOK("Background found", map[string]any{"background": background})
+}
+
+func (h *MCPHandlers) handleApplyTemplate(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	ref, ok := arguments["template"].(string)
+	if !ok || ref == "" {
+		return toolError("template is required")
+	}
+
+	var template dao.TodoTemplate
+	if uuid.Validate(ref) == nil {
+		found, err := h.templateDAO.GetTodoTemplate(ctx, ref)
+		if err != nil {
+			return toolError("Template not found: %s", ref)
+		}
+		template = found
+	} else {
+		found, err := h.templateDAO.ListTodoTemplates(ctx, dao.ListOptions{
+			Limit:       1,
+			SortBy:      "created_at",
+			SortDir:     "DESC",
+			WhereClause: "WHERE name = $1",
+			WhereArgs:   []any{ref},
+		})
+		if err != nil {
+			return toolError("Failed to find template: %v", err)
+		}
+		if len(found) == 0 {
+			return toolError("Template not found: %s", ref)
+		}
+		template = found[0]
+	}
+
+	req := ApplyTemplateRequest{Params: map[string]string{}}
+	if params, ok := arguments["params"].(map[string]any); ok {
+		for k, v := range params {
+			req.Params[k] = fmt.Sprint(v)
+		}
+	}
+	req.StartDate, _ = arguments["start_date"].(string)
+	req.UserUID, _ = arguments["user_uid"].(string)
+	req.HouseholdUID, _ = arguments["household_uid"].(string)
+
+	reportProgress(ctx, 1, 2, fmt.Sprintf("Creating %d todos from %s", len(template.Items), template.Name))
+	todos, err := applyTemplate(ctx, h.templateDAO, template, req)
+	if err != nil {
+		var invalid *invalidTemplateInput
+		if errors.As(err, &invalid) {
+			return toolError("%v", err)
+		}
+		if errors.Is(err, dao.ErrQuotaExceeded) {
+			return quotaToolError(err)
+		}
+		h.log().Error("Failed to apply template",
+			slog.String("error", err.Error()),
+			slog.String("template_uid", template.UID),
+		)
+		return toolError("Failed to apply template: %v", err)
+	}
+
+	reportProgress(ctx, 2, 2, fmt.Sprintf("Created %d todos", len(todos)))
+	return toolOK(fmt.Sprintf("Created %d todos from %s", len(todos), template.Name), map[string]any{"todos": todos})
+}
+
+func (h *MCPHandlers) handleBuildShoppingList(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	list, recipes, err := h.shoppingList(ctx, arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	return toolOK(fmt.Sprintf("%d items to buy for %d recipes, %d already in the pantry", len(list.Items), recipes, len(list.InPantry)),
+		map[string]any{"items": list.Items, "in_pantry": list.InPantry, "aisles": list.Aisles})
+}
+
+func (h *MCPHandlers) handleSplitShoppingList(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	list, recipes, err := h.shoppingList(ctx, arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	var stores GroceryStores
+	if householdUID, _ := arguments["household_uid"].(string); householdUID != "" {
+		if stores, err = loadGroceryStores(ctx, h.preferencesDAO, householdUID); err != nil {
+			return toolError("Failed to read grocery stores: %v", err)
+		}
+	}
+	split := list.splitByStore(stores)
+	return toolOK(fmt.Sprintf("%d items to buy for %d recipes at %d stores, %d already in the pantry", len(list.Items), recipes, len(split), len(list.InPantry)),
+		map[string]any{"stores": split, "in_pantry": list.InPantry})
+}
+
+// shoppingList builds the categorized shopping list for the recipe_ids
+// argument, less what the household_uid's pantry holds, and counts the
+// recipes.
+func (h *MCPHandlers) shoppingList(ctx context.Context, arguments map[string]any) (ShoppingList, int, error) {
+	ids, _ := arguments["recipe_ids"].(string)
+	var recipes []dao.Recipes
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		recipe, err := h.recipesDAO.GetRecipes(ctx, id)
+		if err != nil {
+			return ShoppingList{}, 0, fmt.Errorf("Recipe not found: %s", id)
+		}
+		recipes = append(recipes, recipe)
+	}
+	if len(recipes) == 0 {
+		return ShoppingList{}, 0, errors.New("recipe_ids is required")
+	}
+
+	var pantry []dao.PantryItem
+	if householdUID, _ := arguments["household_uid"].(string); h.pantryDAO != nil && householdUID != "" {
+		whereClause, whereArgs := BuildWhereClause([]Filter{{Column: "household_uid", Op: OpEq, Value: householdUID}}, PantryFilters.Filters)
+		items, err := h.pantryDAO.ListPantryItems(ctx, dao.ListOptions{
+			Limit:       1000,
+			SortBy:      "created_at",
+			SortDir:     "ASC",
+			WhereClause: whereClause,
+			WhereArgs:   whereArgs,
+		})
+		if err != nil {
+			return ShoppingList{}, 0, fmt.Errorf("Failed to list pantry items: %w", err)
+		}
+		pantry = items
+	}
+
+	householdUID, _ := arguments["household_uid"].(string)
+	list := buildShoppingList(recipes, pantry, householdUnits(ctx, h.preferencesDAO, &householdUID))
+	list.categorize(h.shoppingCategories(ctx, list, pantry))
+	return list, len(recipes), nil
+}
+
+// shoppingCategories keys the category of each item on list by
+// ingredientKey. An item the pantry has a category for keeps it; the rest
+// are classified.
+func (h *MCPHandlers) shoppingCategories(ctx context.Context, list ShoppingList, pantry []dao.PantryItem) map[string]string {
+	categories := map[string]string{}
+	for _, p := range pantry {
+		if p.Category != "" {
+			categories[ingredientKey(p.Item)] = p.Category
+		}
+	}
+	var unknown []string
+	for _, item := range slices.Concat(list.Items, list.InPantry) {
+		if _, ok := categories[ingredientKey(item.Item)]; !ok {
+			unknown = append(unknown, item.Item)
+		}
+	}
+	for item, category := range classifyGroceries(ctx, h.groceryClassifier, unknown) {
+		categories[ingredientKey(item)] = category
+	}
+	return categories
+}
+
+func (h *MCPHandlers) handleSetGroceryStores(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	if householdUID == "" {
+		return toolError("household_uid is required")
+	}
+	stores, err := loadGroceryStores(ctx, h.preferencesDAO, householdUID)
+	if err != nil {
+		return toolError("Failed to read grocery stores: %v", err)
+	}
+	list, _ := arguments["stores"].(string)
+	stores.Stores = nil
+	for _, store := range strings.Split(list, ",") {
+		if store = strings.TrimSpace(store); store != "" {
+			stores.Stores = append(stores.Stores, store)
+		}
+	}
+	if len(stores.Stores) == 0 {
+		return toolError("stores is required")
+	}
+	// Preferences for stores that were dropped go with them.
+	stores.prune()
+	if err := stores.Validate(); err != nil {
+		return toolError("%v", err)
+	}
+	if err := saveGroceryStores(ctx, h.preferencesDAO, stores); err != nil {
+		return toolError("Failed to save grocery stores: %v", err)
+	}
+	return toolOK("Saved stores "+strings.Join(stores.Stores, ", "), map[string]any{"grocery_stores": stores})
+}
+
+func (h *MCPHandlers) handleSetPreferredStore(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	if householdUID == "" {
+		return toolError("household_uid is required")
+	}
+	item, _ := arguments["item"].(string)
+	category, _ := arguments["category"].(string)
+	item, category = strings.ToLower(strings.TrimSpace(item)), strings.ToLower(strings.TrimSpace(category))
+	if (item == "") == (category == "") {
+		return toolError("Give either item or category")
+	}
+	stores, err := loadGroceryStores(ctx, h.preferencesDAO, householdUID)
+	if err != nil {
+		return toolError("Failed to read grocery stores: %v", err)
+	}
+
+	prefs, key := stores.Items, item
+	if category != "" {
+		prefs, key = stores.Categories, category
+	}
+	store, _ := arguments["store"].(string)
+	if store = strings.TrimSpace(store); store == "" {
+		delete(prefs, key)
+	} else {
+		prefs[key] = store
+	}
+	if err := stores.Validate(); err != nil {
+		return toolError("%v", err)
+	}
+	if err := saveGroceryStores(ctx, h.preferencesDAO, stores); err != nil {
+		return toolError("Failed to save grocery stores: %v", err)
+	}
+	summary := fmt.Sprintf("Buying %s at the first store", key)
+	if store != "" {
+		summary = fmt.Sprintf("Buying %s at %s", key, findStore(stores.Stores, store))
+	}
+	return toolOK(summary, map[string]any{"grocery_stores": stores})
+}
+
+// pantryExpiry reads an expires_on argument given as YYYY-MM-DD.
+func pantryExpiry(arguments map[string]any) (*time.Time, error) {
+	s, _ := arguments["expires_on"].(string)
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, fmt.Errorf("expires_on must be a date like 2025-08-30")
+	}
+	return &t, nil
+}
+
+func (h *MCPHandlers) handleAddPantryItem(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	p := dao.PantryItem{}
+	p.Item, _ = arguments["item"].(string)
+	p.HouseholdUID, _ = arguments["household_uid"].(string)
+	p.Unit, _ = arguments["unit"].(string)
+	p.Category, _ = arguments["category"].(string)
+	if q, ok := arguments["quantity"].(float64); ok {
+		p.Quantity = &q
+	}
+	expires, err := pantryExpiry(arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	p.ExpiresOn = expires
+	if err := validatePantryItem(&p); err != nil {
+		return toolError("%v", err)
+	}
+	categorizePantryItem(ctx, h.groceryClassifier, &p.Item, &p.Category)
+
+	created, err := h.pantryDAO.CreatePantryItem(ctx, p)
+	if err != nil {
+		return toolError("Failed to add pantry item: %v", err)
+	}
+	return toolOK("Added "+created.Item+" to the pantry", map[string]any{"pantry_item": created})
+}
+
+func (h *MCPHandlers) handleUpdatePantryItem(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	uid, _ := arguments["pantry_item_id"].(string)
+	if uid == "" {
+		return toolError("pantry_item_id is required")
+	}
+	var update dao.UpdatePantryItem
+	if item, ok := arguments["item"].(string); ok {
+		update.Item = &item
+	}
+	if unit, ok := arguments["unit"].(string); ok {
+		update.Unit = &unit
+	}
+	if q, ok := arguments["quantity"].(float64); ok {
+		update.Quantity = &q
+	}
+	if category, ok := arguments["category"].(string); ok {
+		update.Category = &category
+	}
+	expires, err := pantryExpiry(arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	update.ExpiresOn = expires
+	if err := validatePantryUpdate(&update); err != nil {
+		return toolError("%v", err)
+	}
+	recategorizePantryItem(ctx, h.groceryClassifier, &update)
+
+	updated, err := h.pantryDAO.UpdatePantryItem(ctx, uid, update)
+	if err != nil {
+		return toolError("Failed to update pantry item: %v", err)
+	}
+	return toolOK("Updated "+updated.Item, map[string]any{"pantry_item": updated})
+}
+
+func (h *MCPHandlers) handleRemovePantryItem(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	uid, _ := arguments["pantry_item_id"].(string)
+	if uid == "" {
+		return toolError("pantry_item_id is required")
+	}
+	if err := h.pantryDAO.DeletePantryItem(ctx, uid); err != nil {
+		return toolError("Failed to remove pantry item: %v", err)
+	}
+	return toolOK("Pantry item removed", map[string]any{"pantry_item_id": uid})
+}
+
+func (h *MCPHandlers) handleListPantry(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	if householdUID == "" {
+		return toolError("household_uid is required")
+	}
+	filters := []Filter{{Column: "household_uid", Op: OpEq, Value: householdUID}}
+	if item, _ := arguments["item"].(string); item != "" {
+		filters = append(filters, Filter{Column: "item", Op: OpEq, Value: item})
+	}
+	if category, _ := arguments["category"].(string); category != "" {
+		filters = append(filters, Filter{Column: "category", Op: OpEq, Value: category})
+	}
+	if days, ok := arguments["expiring_within_days"].(float64); ok && days >= 0 {
+		filters = append(filters, Filter{Column: "expires_on", Op: OpLe, Value: time.Now().AddDate(0, 0, int(days)).Format(time.DateOnly)})
+	}
+	whereClause, whereArgs := BuildWhereClause(filters, PantryFilters.Filters)
+
+	items, err := h.pantryDAO.ListPantryItems(ctx, dao.ListOptions{
+		Limit:       200,
+		SortBy:      "expires_on",
+		SortDir:     "ASC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	})
+	if err != nil {
+		return toolError("Failed to list pantry items: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Found %d pantry items", len(items)), map[string]any{"pantry_items": items})
+}
+
+func (h *MCPHandlers) handleRecordGroceryPurchase(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	price, ok := arguments["price"].(float64)
+	if !ok {
+		return toolError("price is required")
+	}
+	p := dao.GroceryPurchase{PriceCents: int(math.Round(price * 100))}
+	p.Item, _ = arguments["item"].(string)
+	p.Store, _ = arguments["store"].(string)
+	p.Quantity, _ = arguments["quantity"].(string)
+	p.HouseholdUID, _ = arguments["household_uid"].(string)
+	if s, _ := arguments["purchased_on"].(string); s != "" {
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return toolError("purchased_on must be a date like 2025-08-30")
+		}
+		p.PurchasedOn = t
+	}
+	if err := validateGroceryPurchase(&p); err != nil {
+		return toolError("%v", err)
+	}
+
+	created, err := h.purchaseDAO.CreateGroceryPurchase(ctx, p)
+	if err != nil {
+		return toolError("Failed to record purchase: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Recorded %s for %s", created.Item, formatCents(int64(created.PriceCents))), map[string]any{"purchase": created})
+}
+
+func (h *MCPHandlers) handleGrocerySpendReport(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	if householdUID == "" {
+		return toolError("household_uid is required")
+	}
+	fromArg, _ := arguments["from"].(string)
+	toArg, _ := arguments["to"].(string)
+	from, to, err := parseMonthRange(fromArg, toArg, time.Now())
+	if err != nil {
+		return toolError("%v", err)
+	}
+
+	report, err := grocerySpendReport(ctx, h.purchaseDAO, householdUID, from, to)
+	if err != nil {
+		return toolError("Failed to build spend report: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Spent %s on groceries from %s to %s", formatCents(report.TotalCents), report.From, report.To),
+		map[string]any{"report": report})
+}
+
+func (h *MCPHandlers) handleListCalendarEvents(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
 	}
-	if ct, ok := arguments["cook_time"].(float64); ok {
-		cookTime = &[]int{int(ct)}[0]
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if s, _ := arguments["from"].(string); s != "" {
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return toolError("from must be a date like 2025-08-30")
+		}
+		from = t
 	}
-	if s, ok := arguments["servings"].(float64); ok {
-		servings = &[]int{int(s)}[0]
+	days := 7
+	if n, ok := arguments["days"].(float64); ok {
+		days = min(max(int(n), 1), 31)
 	}
-	if d, ok := arguments["difficulty"].(float64); ok && d >= 1 && d <= 5 {
-		difficulty = &[]int{int(d)}[0]
+	to := from.AddDate(0, 0, days)
+
+	cal, err := h.calendarFor(ctx, userUID)
+	if err != nil {
+		return toolError("%v", err)
 	}
-	if r, ok := arguments["rating"].(float64); ok && r >= 1 && r <= 5 {
-		rating = &[]int{int(r)}[0]
+	events, err := cal.Events(ctx, from, to)
+	if err != nil {
+		return toolError("Failed to list calendar events: %v", err)
 	}
+	return toolOK(fmt.Sprintf("Found %d events from %s to %s", len(events), from.Format(time.DateOnly), to.AddDate(0, 0, -1).Format(time.DateOnly)),
+		map[string]any{"events": events, "count": len(events)})
+}
 
-	totalTime := 0
-	if prepTime != nil && cookTime != nil {
-		totalTime = *prepTime + *cookTime
+func (h *MCPHandlers) handleCreateCalendarEvent(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
+	}
+	e := calendar.Event{}
+	e.Title, _ = arguments["title"].(string)
+	e.Location, _ = arguments["location"].(string)
+	e.Description, _ = arguments["description"].(string)
+	startArg, _ := arguments["start"].(string)
+	start, allDay, err := parseEventTime(startArg)
+	if err != nil {
+		return toolError("start: %v", err)
 	}
-	var totalTimePtr *int
-	if totalTime > 0 {
-		totalTimePtr = &totalTime
+	e.Start, e.AllDay, e.End = start, allDay, start.Add(time.Hour)
+	if allDay {
+		e.End = start.AddDate(0, 0, 1)
+	}
+	if endArg, _ := arguments["end"].(string); endArg != "" {
+		end, endAllDay, err := parseEventTime(endArg)
+		if err != nil {
+			return toolError("end: %v", err)
+		}
+		if endAllDay != allDay {
+			return toolError("start and end must both be dates or both be times")
+		}
+		// An all-day event's end is exclusive; the tool takes the last day.
+		e.End = end
+		if allDay {
+			e.End = end.AddDate(0, 0, 1)
+		}
 	}
 
-	var genrePtr, groceryListPtr, difficultyPtr *string
-	if genre != "" {
-		genrePtr = &genre
+	cal, err := h.calendarFor(ctx, userUID)
+	if err != nil {
+		return toolError("%v", err)
 	}
-	if groceryList != "" {
-		groceryListPtr = &groceryList
+	created, err := cal.CreateEvent(ctx, e)
+	if errors.Is(err, calendar.ErrInvalidEvent) {
+		return toolError("%v", err)
 	}
-	if difficulty != nil {
-		difficultyStr := strconv.Itoa(*difficulty)
-		difficultyPtr = &difficultyStr
+	if err != nil {
+		return toolError("Failed to create calendar event: %v", err)
 	}
+	return toolOK(fmt.Sprintf("Added %s on %s", created.Title, created.Start.Format(time.DateOnly)), map[string]any{"event": created})
+}
 
-	recipe := dao.Recipes{
-		ID:           uuid.NewString(),
-		Title:        title,
-		Data:         data,
-		Genre:        genrePtr,
-		GroceryList:  groceryListPtr,
-		PrepTime:     prepTime,
-		CookTime:     cookTime,
-		TotalTime:    totalTimePtr,
-		Servings:     servings,
-		Difficulty:   difficultyPtr,
-		Rating:       rating,
-		Tags:         tags,
-		UserUID:      &userUID,
-		HouseholdUID: &householdUID,
+func (h *MCPHandlers) handleSetAway(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
 	}
+	now := time.Now()
 
-	created, err := h.recipesDAO.CreateRecipes(ctx, recipe)
-	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to save recipe: %v", err)}},
+	if back, _ := arguments["back"].(bool); back {
+		periods, err := h.awayDAO.ListAwayPeriodsByUserUID(ctx, userUID, awayDay(now))
+		if err != nil {
+			return toolError("Failed to list away periods: %v", err)
+		}
+		for _, p := range periods {
+			if err := h.awayDAO.DeleteAwayPeriod(ctx, p.UID); err != nil {
+				return toolError("Failed to end away period: %v", err)
+			}
 		}
+		return toolOK(fmt.Sprintf("Ended %d away periods", len(periods)), map[string]any{"ended": periods})
 	}
 
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Recipe saved successfully with ID: %s", created.ID)}},
+	startsOn, _ := arguments["starts_on"].(string)
+	endsOn, _ := arguments["ends_on"].(string)
+	note, _ := arguments["note"].(string)
+	a, err := newAwayPeriod(userUID, startsOn, endsOn, note, now)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	created, err := h.awayDAO.CreateAwayPeriod(ctx, a)
+	if err != nil {
+		return toolError("Failed to set away: %v", err)
 	}
+	return toolOK(fmt.Sprintf("Away from %s to %s", created.StartsOn.Format(time.DateOnly), created.EndsOn.Format(time.DateOnly)),
+		map[string]any{"away_period": created})
 }
 
-func (h *MCPHandlers) handleFindRecipes(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
-	limit := 20
-	if l, ok := arguments["limit"].(float64); ok && l > 0 {
-		limit = int(l)
+func (h *MCPHandlers) handleAddToMyDay(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	todoID, _ := arguments["todo_id"].(string)
+	if userUID == "" || todoID == "" {
+		return toolError("user_uid and todo_id are required")
 	}
+	timezone, _ := arguments["timezone"].(string)
+	loc, err := myDayLocation(ctx, h.preferencesDAO, userUID, timezone)
+	if err != nil {
+		return toolError("%v", err)
+	}
+	todo, expiresAt, err := addToMyDay(ctx, h.myDayDAO, loc, userUID, todoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return toolError("Todo %s not found", todoID)
+	}
+	if err != nil {
+		return toolError("Failed to add to My Day: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Added %s to My Day until %s", todo.Title, expiresAt.Format(time.RFC3339)),
+		map[string]any{"todo": todo, "expires_at": expiresAt})
+}
 
-	// Use shared filtering logic
-	filters := BuildFiltersFromMCP(arguments, RecipesFilters.Filters)
-
-	// Handle special min_rating filter
-	if minRating, ok := arguments["min_rating"].(float64); ok {
-		filters["rating"] = ">=" + strconv.Itoa(int(minRating))
+func (h *MCPHandlers) handleRemoveFromMyDay(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	todoID, _ := arguments["todo_id"].(string)
+	if userUID == "" || todoID == "" {
+		return toolError("user_uid and todo_id are required")
+	}
+	if err := h.myDayDAO.RemoveFromMyDay(ctx, userUID, todoID); err != nil {
+		return toolError("Failed to remove from My Day: %v", err)
 	}
+	return toolOK("Removed from My Day", map[string]any{"todo_id": todoID})
+}
 
-	whereClause, whereArgs := BuildWhereClause(filters, RecipesFilters.Filters)
-	options := dao.ListOptions{
-		Limit:       limit,
-		Offset:      0,
-		SortBy:      "rating",
-		SortDir:     "DESC",
-		WhereClause: whereClause,
-		WhereArgs:   whereArgs,
+func (h *MCPHandlers) handleGetMyDay(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, _ := arguments["user_uid"].(string)
+	if userUID == "" {
+		return toolError("user_uid is required")
+	}
+	todos, err := h.myDayDAO.ListMyDay(ctx, userUID)
+	if err != nil {
+		return toolError("Failed to list My Day: %v", err)
 	}
+	return toolOK(fmt.Sprintf("%d todos on My Day", len(todos)), map[string]any{"todos": todos})
+}
 
-	recipes, err := h.recipesDAO.ListRecipes(ctx, options)
+func (h *MCPHandlers) handleCreateProject(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	p := dao.Project{}
+	p.Name, _ = arguments["name"].(string)
+	p.Description, _ = arguments["description"].(string)
+	p.HouseholdUID, _ = arguments["household_uid"].(string)
+	if err := validateProject(&p); err != nil {
+		return toolError("%v", err)
+	}
+	created, err := h.projectDAO.CreateProject(ctx, p)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to find recipes: %v", err)}},
-		}
+		return toolError("Failed to create project: %v", err)
 	}
+	return toolOK(fmt.Sprintf("Created project %s", created.Name), map[string]any{"project": created})
+}
 
-	result, _ := json.Marshal(recipes)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
+func (h *MCPHandlers) handleListProjects(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	householdUID, _ := arguments["household_uid"].(string)
+	if householdUID == "" {
+		return toolError("household_uid is required")
+	}
+	filters := []Filter{{Column: "household_uid", Op: OpEq, Value: householdUID}}
+	if all, _ := arguments["include_archived"].(bool); !all {
+		filters = append(filters, Filter{Column: "archived", Op: OpEq, Value: false})
 	}
+	whereClause, whereArgs := BuildWhereClause(filters, ProjectFilters.Filters)
+	projects, err := h.projectDAO.ListProjects(ctx, dao.ListOptions{
+		Limit:       100,
+		SortBy:      "name",
+		SortDir:     "ASC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	})
+	if err != nil {
+		return toolError("Failed to list projects: %v", err)
+	}
+	return toolOK(fmt.Sprintf("Found %d projects", len(projects)), map[string]any{"projects": projects, "count": len(projects)})
 }
 
-func (h *MCPHandlers) handleGetRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
-	recipeID, ok := arguments["recipe_id"].(string)
-	if !ok || recipeID == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: recipe_id is required"}},
+// handleMoveTodosToProject moves todos into or out of a project and lists
+// the todo_ids it didn't find.
+func (h *MCPHandlers) handleMoveTodosToProject(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	projectID, _ := arguments["project_id"].(string)
+	if projectID == "" {
+		return toolError("project_id is required")
+	}
+	ids, _ := arguments["todo_ids"].([]any)
+	var todoIDs []string
+	for _, id := range ids {
+		if s, ok := id.(string); ok && s != "" {
+			todoIDs = append(todoIDs, s)
 		}
 	}
+	if len(todoIDs) == 0 {
+		return toolError("todo_ids must be a non-empty list of todo UIDs")
+	}
 
-	recipe, err := h.recipesDAO.GetRecipes(ctx, recipeID)
+	remove, _ := arguments["remove"].(bool)
+	move, verb := h.projectDAO.MoveTodosToProject, "Moved %d todos into the project"
+	if remove {
+		move, verb = h.projectDAO.RemoveTodosFromProject, "Took %d todos out of the project"
+	}
+	todos, err := move(ctx, projectID, todoIDs)
+	if errors.Is(err, dao.ErrUnknownProject) || errors.Is(err, dao.ErrProjectArchived) {
+		return toolError("%v", err)
+	}
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Recipe not found: %v", err)}},
-		}
+		return toolError("Failed to move todos: %v", err)
 	}
 
-	result, _ := json.Marshal(recipe)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(result)}},
+	moved := map[string]bool{}
+	for _, t := range todos {
+		moved[t.UID] = true
+	}
+	missing := []string{}
+	for _, id := range todoIDs {
+		if !moved[id] {
+			missing = append(missing, id)
+		}
 	}
+	return toolOK(fmt.Sprintf(verb, len(todos)), map[string]any{"todos": todos, "not_found": missing})
 }
 
 func (h *MCPHandlers) handleUpdateUserDescription(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	userUID, ok := arguments["user_uid"].(string)
 	if !ok || userUID == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: user_uid is required"}},
-		}
+		return toolError("user_uid is required")
 	}
 
 	description, ok := arguments["description"].(string)
 	if !ok {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: description is required"}},
-		}
+		return toolError("description is required")
 	}
 
 	update := dao.UpdateUser{
@@ -813,33 +2579,21 @@ func (h *MCPHandlers) handleUpdateUserDescription(ctx context.Context, arguments
 
 	updatedUser, err := h.userDAO.UpdateUser(ctx, userUID, update)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to update user description: %v", err)}},
-		}
+		return toolError("Failed to update user description: %v", err)
 	}
 
-	result, _ := json.Marshal(updatedUser)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("User description updated successfully: %s", string(result))}},
-	}
+	return toolOK("User description updated successfully", map[string]any{"user": updatedUser})
 }
 
 func (h *MCPHandlers) handleUpdateHouseholdDescription(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
 	householdUID, ok := arguments["household_uid"].(string)
 	if !ok || householdUID == "" {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: household_uid is required"}},
-		}
+		return toolError("household_uid is required")
 	}
 
 	description, ok := arguments["description"].(string)
 	if !ok {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: description is required"}},
-		}
+		return toolError("description is required")
 	}
 
 	update := dao.UpdateHousehold{
@@ -848,16 +2602,136 @@ func (h *MCPHandlers) handleUpdateHouseholdDescription(ctx context.Context, argu
 
 	updatedHousehold, err := h.householdDAO.UpdateHousehold(ctx, householdUID, update)
 	if err != nil {
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Failed to update household description: %v", err)}},
+		return toolError("Failed to update household description: %v", err)
+	}
+
+	return toolOK("Household description updated successfully", map[string]any{"household": updatedHousehold})
+}
+
+// briefingCompletedWindow is how far back the briefing lists completed
+// todos.
+const briefingCompletedWindow = 24 * time.Hour
+
+func (h *MCPHandlers) handleGetBriefing(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	userUID, ok := arguments["user_uid"].(string)
+	if !ok || userUID == "" {
+		return toolError("user_uid is required")
+	}
+
+	user, err := h.userDAO.GetUser(ctx, userUID)
+	if err != nil {
+		return toolError("User not found: %v", err)
+	}
+	briefing := map[string]any{"user": withAvatarURLs(user)}
+
+	// Everything below is scoped to the household when there is one.
+	owner := Filter{Column: "user_uid", Op: OpEq, Value: user.UID}
+	if user.HouseholdUID != nil && *user.HouseholdUID != "" {
+		owner = Filter{Column: "household_uid", Op: OpEq, Value: *user.HouseholdUID}
+		if household, err := h.householdDAO.GetHousehold(ctx, *user.HouseholdUID); err == nil {
+			briefing["household"] = household
+		}
+	}
+	if h.membersDAO != nil {
+		members := []dao.Users{user}
+		if user.HouseholdUID != nil && *user.HouseholdUID != "" {
+			if members, err = h.membersDAO.ListHouseholdMembers(ctx, *user.HouseholdUID); err != nil {
+				return toolError("Failed to list household members: %v", err)
+			}
+		}
+		briefing["upcoming_birthdays"] = upcomingBirthdays(members, time.Now(), birthdayWindow)
+	}
+	if h.datesDAO != nil && user.HouseholdUID != nil && *user.HouseholdUID != "" {
+		dates, err := h.datesDAO.ListImportantDates(ctx, *user.HouseholdUID)
+		if err != nil {
+			return toolError("Failed to list important dates: %v", err)
 		}
+		briefing["important_dates"] = upcomingImportantDates(dates, time.Now())
 	}
 
-	result, _ := json.Marshal(updatedHousehold)
-	return mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Household description updated successfully: %s", string(result))}},
+	whereClause, whereArgs := BuildWhereClause([]Filter{owner}, NotesFilters.Filters)
+	whereClause, whereArgs = withNoteVisibility(ctx, whereClause+" AND pinned", whereArgs)
+	notes, err := h.notesDAO.ListNotes(ctx, dao.ListOptions{
+		Limit:       50,
+		SortBy:      "sort_order",
+		SortDir:     "ASC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	})
+	if err != nil {
+		return toolError("Failed to list pinned notes: %v", err)
+	}
+	briefing["pinned_notes"] = budgetPinnedNotes(notes, defaultPinnedNotesBudget)
+
+	whereClause, whereArgs = BuildWhereClause([]Filter{{Column: "marked_complete", Op: OpNull}, owner}, TodoFilters.Filters)
+	todos, err := h.todoDAO.ListTodos(ctx, dao.ListOptions{
+		Limit:       20,
+		SortBy:      "due_date",
+		SortDir:     "ASC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	})
+	if err != nil {
+		return toolError("Failed to list todos: %v", err)
+	}
+	if h.myDayDAO != nil {
+		myDay, err := h.myDayDAO.ListMyDay(ctx, user.UID)
+		if err != nil {
+			return toolError("Failed to list My Day: %v", err)
+		}
+		briefing["my_day"] = myDay
+		todos = myDayFirst(todos, myDay)
 	}
+	briefing["todos"] = todos
+
+	// Each carries its completer, so "who did this?" needs no lookup.
+	since := time.Now().Add(-briefingCompletedWindow).Format(time.RFC3339)
+	whereClause, whereArgs = BuildWhereClause([]Filter{{Column: "marked_complete", Op: OpGe, Value: since}, owner}, TodoFilters.Filters)
+	done, err := h.todoDAO.ListTodos(ctx, dao.ListOptions{
+		Limit:       20,
+		SortBy:      "marked_complete",
+		SortDir:     "DESC",
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+	})
+	if err != nil {
+		return toolError("Failed to list completed todos: %v", err)
+	}
+	briefing["recently_completed"] = done
+
+	if h.pantryDAO != nil && user.HouseholdUID != nil && *user.HouseholdUID != "" {
+		whereClause, whereArgs = BuildWhereClause([]Filter{
+			{Column: "household_uid", Op: OpEq, Value: *user.HouseholdUID},
+			{Column: "expires_on", Op: OpLe, Value: time.Now().Add(pantryExpiringSoon).Format(time.DateOnly)},
+		}, PantryFilters.Filters)
+		expiring, err := h.pantryDAO.ListPantryItems(ctx, dao.ListOptions{
+			Limit:       20,
+			SortBy:      "expires_on",
+			SortDir:     "ASC",
+			WhereClause: whereClause,
+			WhereArgs:   whereArgs,
+		})
+		if err != nil {
+			return toolError("Failed to list pantry items: %v", err)
+		}
+		briefing["expiring_pantry_items"] = expiring
+	}
+
+	if h.awayDAO != nil {
+		now := time.Now()
+		var away []dao.AwayPeriod
+		if user.HouseholdUID != nil && *user.HouseholdUID != "" {
+			away, err = h.awayDAO.ListAwayPeriodsByHouseholdUID(ctx, *user.HouseholdUID, awayDay(now))
+		} else {
+			away, err = h.awayDAO.ListAwayPeriodsByUserUID(ctx, user.UID, awayDay(now))
+		}
+		if err != nil {
+			return toolError("Failed to list away periods: %v", err)
+		}
+		briefing["away"] = awayOn(away, now)
+	}
+
+	return toolOK(fmt.Sprintf("Briefing for %s", user.Name), briefing)
 }
 
 func (h *MCPHandlers) callTool(ctx context.Context, name string, arguments map[string]any) mcp.CallToolResult {
@@ -874,6 +2748,74 @@ func (h *MCPHandlers) callTool(ctx context.Context, name string, arguments map[s
 		)
 	}()
 
+	h.usage.countToolCall(ctx)
+	if tool, ok := h.findTool(name); ok && !toolAllowed(ctx, tool) {
+		h.log().Warn("MCP tool not permitted for API key",
+			slog.String("tool_name", name),
+		)
+		h.clientLog(ctx, "warning", map[string]any{"tool": name, "error": "tool not permitted for this API key"})
+		return toolError("Tool %s is not permitted for this API key", name)
+	}
+	if !h.policy.AllowTool(ctx, name) {
+		h.clientLog(ctx, "warning", map[string]any{"tool": name, "error": "tool denied by authorization policy"})
+		return toolError("Tool %s is not permitted by the authorization policy", name)
+	}
+	if !h.flags.permitsTool(ctx, name) {
+		h.clientLog(ctx, "warning", map[string]any{"tool": name, "error": "tool not enabled for this household"})
+		return toolError("Tool %s isn't enabled for this household", name)
+	}
+
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	if err := h.applyIdentityDefaults(ctx, name, arguments); err != nil {
+		h.log().Warn("MCP tool arguments rejected",
+			slog.String("tool_name", name),
+			slog.String("error", err.Error()),
+		)
+		return toolError("%v", err)
+	}
+	if refused := h.confirmTool(ctx, name, arguments); refused != nil {
+		return *refused
+	}
+
+	cacheKey, cacheable := h.cache.key(ctx, name, arguments)
+	var gen uint64
+	if cacheable {
+		cached, cachedGen, hit := h.cache.get(cacheKey)
+		if hit {
+			h.log().Debug("Serving cached MCP tool result", slog.String("tool_name", name))
+			return cached
+		}
+		gen = cachedGen
+	}
+
+	result := h.dispatchTool(ctx, name, arguments)
+	if format, _ := arguments["format"].(string); format == formatCompact && slices.Contains(compactTools, name) {
+		result = compactToolResult(result)
+	}
+	if h.expandDAO != nil {
+		expand, _ := arguments["expand"].(string)
+		if !slices.Contains(expandTools, name) {
+			expand = ""
+		}
+		if names := slices.Contains(nameTools, name); expand != "" || names {
+			result = expandToolResult(ctx, h.expandDAO, result, expand, names)
+		}
+	}
+	if result.IsError {
+		body, _ := result.StructuredContent.(map[string]any)
+		h.clientLog(ctx, "error", map[string]any{"tool": name, "error": body["error"]})
+	} else {
+		h.publishToolChange(ctx, name, arguments)
+		if cacheable {
+			h.cache.put(cacheKey, gen, name, cachedToolHousehold(ctx, arguments), result)
+		}
+	}
+	return result
+}
+
+func (h *MCPHandlers) dispatchTool(ctx context.Context, name string, arguments map[string]any) mcp.CallToolResult {
 	switch name {
 	case "create_todo":
 		return h.handleCreateTodo(ctx, arguments)
@@ -881,37 +2823,172 @@ func (h *MCPHandlers) callTool(ctx context.Context, name string, arguments map[s
 		return h.handleListTodos(ctx, arguments)
 	case "complete_todo":
 		return h.handleCompleteTodo(ctx, arguments)
+	case "set_todo_status":
+		return h.handleSetTodoStatus(ctx, arguments)
+	case "link_todos":
+		return h.handleLinkTodos(ctx, arguments)
+	case "log_time":
+		return h.handleLogTime(ctx, arguments)
+	case "time_report":
+		return h.handleTimeReport(ctx, arguments)
 	case "save_note":
 		return h.handleSaveNote(ctx, arguments)
 	case "recall_note":
 		return h.handleRecallNote(ctx, arguments)
+	case "delete_note":
+		return h.handleDeleteNote(ctx, arguments)
+	case "pin_note":
+		return h.handlePinNote(ctx, arguments)
+	case "remind_me":
+		return h.handleRemindMe(ctx, arguments)
 	case "list_notes":
 		return h.handleListNotes(ctx, arguments)
 	case "set_preference":
 		return h.handleSetPreference(ctx, arguments)
 	case "get_preference":
 		return h.handleGetPreference(ctx, arguments)
+	case "get_preferences_bulk":
+		return h.handleGetPreferencesBulk(ctx, arguments)
+	case "set_preferences_bulk":
+		return h.handleSetPreferencesBulk(ctx, arguments)
+	case "set_notification_preference":
+		return h.handleSetNotificationPreference(ctx, arguments)
 	case "save_recipe":
 		return h.handleSaveRecipe(ctx, arguments)
 	case "find_recipes":
 		return h.handleFindRecipes(ctx, arguments)
 	case "get_recipe":
 		return h.handleGetRecipe(ctx, arguments)
+	case "rate_recipe":
+		return h.handleRateRecipe(ctx, arguments)
+	case "duplicate_recipe":
+		return h.handleDuplicateRecipe(ctx, arguments)
+	case "delete_recipe":
+		return h.handleDeleteRecipe(ctx, arguments)
+	case "convert_units":
+		return h.handleConvertUnits(ctx, arguments)
+	case "build_shopping_list":
+		return h.handleBuildShoppingList(ctx, arguments)
+	case "split_shopping_list":
+		return h.handleSplitShoppingList(ctx, arguments)
+	case "set_grocery_stores":
+		return h.handleSetGroceryStores(ctx, arguments)
+	case "set_preferred_store":
+		return h.handleSetPreferredStore(ctx, arguments)
 	case "update_user_description":
 		return h.handleUpdateUserDescription(ctx, arguments)
 	case "update_household_description":
 		return h.handleUpdateHouseholdDescription(ctx, arguments)
-	default:
-		return mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: Unknown tool: %s", name)}},
+	case "get_briefing":
+		return h.handleGetBriefing(ctx, arguments)
+	case "set_background":
+		if h.backgroundDAO != nil {
+			return h.handleSetBackground(ctx, arguments)
+		}
+	case "get_background":
+		if h.backgroundDAO != nil {
+			return h.handleGetBackground(ctx, arguments)
+		}
+	case "scratchpad_set":
+		if h.scratchpads != nil {
+			return h.handleScratchpadSet(ctx, arguments)
+		}
+	case "scratchpad_get":
+		if h.scratchpads != nil {
+			return h.handleScratchpadGet(ctx, arguments)
+		}
+	case "apply_template":
+		if h.templateDAO != nil {
+			return h.handleApplyTemplate(ctx, arguments)
+		}
+	case "add_pantry_item":
+		if h.pantryDAO != nil {
+			return h.handleAddPantryItem(ctx, arguments)
+		}
+	case "update_pantry_item":
+		if h.pantryDAO != nil {
+			return h.handleUpdatePantryItem(ctx, arguments)
+		}
+	case "remove_pantry_item":
+		if h.pantryDAO != nil {
+			return h.handleRemovePantryItem(ctx, arguments)
+		}
+	case "list_pantry":
+		if h.pantryDAO != nil {
+			return h.handleListPantry(ctx, arguments)
+		}
+	case "record_grocery_purchase":
+		if h.purchaseDAO != nil {
+			return h.handleRecordGroceryPurchase(ctx, arguments)
+		}
+	case "grocery_spend_report":
+		if h.purchaseDAO != nil {
+			return h.handleGrocerySpendReport(ctx, arguments)
+		}
+	case "refresh_recipe":
+		if h.recipeClient != nil {
+			return h.handleRefreshRecipe(ctx, arguments)
+		}
+	case "list_calendar_events":
+		if h.calendarCreds != nil {
+			return h.handleListCalendarEvents(ctx, arguments)
+		}
+	case "create_calendar_event":
+		if h.calendarCreds != nil {
+			return h.handleCreateCalendarEvent(ctx, arguments)
+		}
+	case "set_away":
+		if h.awayDAO != nil {
+			return h.handleSetAway(ctx, arguments)
+		}
+	case "add_important_date":
+		if h.datesDAO != nil {
+			return h.handleAddImportantDate(ctx, arguments)
+		}
+	case "add_to_my_day":
+		if h.myDayDAO != nil {
+			return h.handleAddToMyDay(ctx, arguments)
+		}
+	case "remove_from_my_day":
+		if h.myDayDAO != nil {
+			return h.handleRemoveFromMyDay(ctx, arguments)
+		}
+	case "get_my_day":
+		if h.myDayDAO != nil {
+			return h.handleGetMyDay(ctx, arguments)
+		}
+	case "create_project":
+		if h.projectDAO != nil {
+			return h.handleCreateProject(ctx, arguments)
+		}
+	case "list_projects":
+		if h.projectDAO != nil {
+			return h.handleListProjects(ctx, arguments)
+		}
+	case "move_todos_to_project":
+		if h.projectDAO != nil {
+			return h.handleMoveTodosToProject(ctx, arguments)
+		}
+	case "extract_todos":
+		if h.extraction != nil {
+			return h.handleExtractTodos(ctx, arguments)
+		}
+	case "get_data_schema":
+		if h.dataSchemaDAO != nil {
+			return h.handleGetDataSchema(ctx, arguments)
 		}
 	}
+	return toolError("Unknown tool: %s", name)
 }
 
 func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
 	var req JSONRPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	err := json.NewDecoder(r.Body).Decode(&raw)
+	if err == nil {
+		err = json.Unmarshal(raw, &req)
+	}
+	if err != nil {
 		h.log().Error("Invalid JSON-RPC request",
 			slog.String("error", err.Error()),
 			slog.String("remote_addr", r.RemoteAddr),
@@ -926,10 +3003,61 @@ func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		slog.String("remote_addr", r.RemoteAddr),
 	)
 
+	// Requests after initialize may name the session it created. Requests
+	// without one are handled statelessly.
+	var session *mcpSession
+	if id := r.Header.Get(sessionIDHeader); id != "" && req.Method != "initialize" {
+		s, ok := h.sessions.Get(id)
+		if !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if !sessionIdentityMatches(s, r) {
+			http.Error(w, "Session belongs to a different API key", http.StatusForbidden)
+			return
+		}
+		session = s
+	}
+
+	// A message with an id but no method is the client answering one of our
+	// own requests (elicitation/create).
+	if req.Method == "" && req.ID != nil {
+		h.handleClientResponse(w, session, raw)
+		return
+	}
+
+	// initialize negotiates the version in its params; every later request
+	// names it in the MCP-Protocol-Version header or inherits it from its
+	// session.
+	version := defaultProtocolVersion
+	if session != nil && r.Header.Get(protocolVersionHeader) == "" {
+		version = session.ProtocolVersion
+	} else if req.Method != "initialize" {
+		v, err := protocolVersionFromRequest(r)
+		if err != nil {
+			h.log().Warn("Unsupported MCP-Protocol-Version header",
+				slog.String("protocol_version", r.Header.Get(protocolVersionHeader)),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+			http.Error(w, "Unsupported MCP-Protocol-Version", http.StatusBadRequest)
+			return
+		}
+		version = v
+	}
+
 	var response JSONRPCResponse
 	response.JSONRPC = "2.0"
 	response.ID = req.ID
 
+	stream := newEventStream(w, r)
+	if !featuresFor(version).Streaming {
+		stream.enabled = false
+	}
+	ctx := withProtocolVersion(withEventStream(r.Context(), stream), version)
+	if session != nil {
+		ctx = withSession(ctx, session)
+	}
+
 	switch req.Method {
 	case "initialize":
 		if params, ok := req.Params.(map[string]any); ok {
@@ -965,16 +3093,45 @@ func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
-			result := h.handleInitialize(r.Context(), initParams)
-			response.Result = result
+			result, session, err := h.handleInitialize(ctx, initParams)
+			if err != nil {
+				response.Error = map[string]any{
+					"code":    -32602,
+					"message": "Unsupported protocol version",
+					"data": map[string]any{
+						"supported": SupportedProtocolVersions,
+						"requested": initParams.ProtocolVersion,
+					},
+				}
+			} else {
+				w.Header().Set(sessionIDHeader, session.ID)
+				response.Result = result
+			}
 		} else {
 			response.Error = map[string]any{"code": -32602, "message": "Invalid params"}
 		}
 	case "initialized":
-		h.handleInitialized(r.Context())
+		h.handleInitialized(ctx)
 		response.Result = map[string]any{}
+	case "logging/setLevel":
+		params, _ := req.Params.(map[string]any)
+		level, _ := params["level"].(string)
+		if err := h.handleSetLevel(ctx, level); errors.Is(err, errSessionRequired) {
+			response.Error = map[string]any{"code": -32600, "message": "logging/setLevel requires an Mcp-Session-Id"}
+		} else if err != nil {
+			response.Error = map[string]any{"code": -32602, "message": "Invalid log level"}
+		} else {
+			response.Result = map[string]any{}
+		}
 	case "tools/list":
-		response.Result = mcp.ListToolsResult{Tools: h.tools}
+		params, _ := req.Params.(map[string]any)
+		cursor, _ := params["cursor"].(string)
+		result, err := h.listTools(ctx, cursor)
+		if err != nil {
+			response.Error = map[string]any{"code": -32602, "message": "Invalid cursor"}
+		} else {
+			response.Result = toolsListResult(ctx, result)
+		}
 	case "tools/call":
 		params, ok := req.Params.(map[string]any)
 		if !ok {
@@ -985,10 +3142,14 @@ func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				response.Error = map[string]any{"code": -32602, "message": "Tool name is required"}
 			} else {
 				arguments, _ := params["arguments"].(map[string]any)
-				result := h.callTool(r.Context(), toolName, arguments)
-				response.Result = result
+				toolCtx := withProgressToken(ctx, progressTokenFromParams(params))
+				result := h.callTool(toolCtx, toolName, arguments)
+				response.Result = toolCallResultFor(ctx, result)
 			}
 		}
+	case "resources/list", "resources/templates/list", "resources/read", "resources/subscribe", "resources/unsubscribe":
+		params, _ := req.Params.(map[string]any)
+		response.Result, response.Error = h.resourceResponse(ctx, req.Method, params)
 	default:
 		h.log().Warn("Unknown JSON-RPC method",
 			slog.String("method", req.Method),
@@ -1010,6 +3171,15 @@ func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		)
 	}
 
+	if stream.Started() {
+		if err := stream.Send(response); err != nil {
+			h.log().Error("Failed to write JSON-RPC response to event stream",
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log().Error("Failed to encode JSON-RPC response",
@@ -1018,10 +3188,15 @@ func (h *MCPHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func NewMCPRouter(todoDAO todoDAO, notesDAO notesDAO, preferencesDAO preferencesDAO, recipesDAO recipesDAO, userDAO userDAO, householdDAO householdDAO) http.Handler {
-	h := NewMCP(todoDAO, notesDAO, preferencesDAO, recipesDAO, userDAO, householdDAO)
+func NewMCPRouter(todoDAO todoDAO, notesDAO notesDAO, preferencesDAO preferencesDAO, recipesDAO recipesDAO, userDAO userDAO, householdDAO householdDAO, opts ...MCPOption) http.Handler {
+	h := NewMCP(todoDAO, notesDAO, preferencesDAO, recipesDAO, userDAO, householdDAO, opts...)
 
 	r := chi.NewRouter()
+	if h.apiKeys != nil {
+		r.Use(APIKeyAuth(h.apiKeys, h.requireAPIKey))
+	}
 	r.Post("/", h.ServeHTTP)
+	r.Get("/", h.resourceStream)
+	r.Delete("/", h.deleteSession)
 	return r
 }
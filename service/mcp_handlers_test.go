@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
@@ -38,11 +40,46 @@ func (m *MockTodoDAO) UpdateTodo(ctx context.Context, uid string, t dao.UpdateTo
 	return args.Get(0).(dao.Todo), args.Error(1)
 }
 
+func (m *MockTodoDAO) SetTodoStatus(ctx context.Context, uid string, status dao.TodoStatus, completedBy *string) (dao.Todo, error) {
+	args := m.Called(ctx, uid, status, completedBy)
+	return args.Get(0).(dao.Todo), args.Error(1)
+}
+
 func (m *MockTodoDAO) DeleteTodo(ctx context.Context, uid string) error {
 	args := m.Called(ctx, uid)
 	return args.Error(0)
 }
 
+func (m *MockTodoDAO) AddTodoDependency(ctx context.Context, todoUID, blockedByUID string) error {
+	args := m.Called(ctx, todoUID, blockedByUID)
+	return args.Error(0)
+}
+
+func (m *MockTodoDAO) RemoveTodoDependency(ctx context.Context, todoUID, blockedByUID string) error {
+	args := m.Called(ctx, todoUID, blockedByUID)
+	return args.Error(0)
+}
+
+func (m *MockTodoDAO) ListTodoDependencies(ctx context.Context, todoUID string) ([]dao.TodoDependency, error) {
+	args := m.Called(ctx, todoUID)
+	return args.Get(0).([]dao.TodoDependency), args.Error(1)
+}
+
+func (m *MockTodoDAO) LogTodoTime(ctx context.Context, l dao.TodoTimeLog) (dao.TodoTimeLog, error) {
+	args := m.Called(ctx, l)
+	return args.Get(0).(dao.TodoTimeLog), args.Error(1)
+}
+
+func (m *MockTodoDAO) ListTodoTimeLogs(ctx context.Context, todoUID string) ([]dao.TodoTimeLog, error) {
+	args := m.Called(ctx, todoUID)
+	return args.Get(0).([]dao.TodoTimeLog), args.Error(1)
+}
+
+func (m *MockTodoDAO) WeeklyTodoTime(ctx context.Context, householdUID, userUID string, from, to time.Time, title string) ([]dao.TodoTimeSpent, error) {
+	args := m.Called(ctx, householdUID, userUID, from, to, title)
+	return args.Get(0).([]dao.TodoTimeSpent), args.Error(1)
+}
+
 type MockNotesDAO struct {
 	mock.Mock
 }
@@ -67,6 +104,16 @@ func (m *MockNotesDAO) UpdateNotes(ctx context.Context, id string, n dao.Notes)
 	return args.Get(0).(dao.Notes), args.Error(1)
 }
 
+func (m *MockNotesDAO) SetNotePinned(ctx context.Context, id string, pinned bool, sortOrder int) (dao.Notes, error) {
+	args := m.Called(ctx, id, pinned, sortOrder)
+	return args.Get(0).(dao.Notes), args.Error(1)
+}
+
+func (m *MockNotesDAO) SetNoteReminder(ctx context.Context, id string, remindAt *time.Time) (dao.Notes, error) {
+	args := m.Called(ctx, id, remindAt)
+	return args.Get(0).(dao.Notes), args.Error(1)
+}
+
 func (m *MockNotesDAO) DeleteNotes(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -101,6 +148,16 @@ func (m *MockPreferencesDAO) DeletePreferences(ctx context.Context, key, specifi
 	return args.Error(0)
 }
 
+func (m *MockPreferencesDAO) GetPreferencesBulk(ctx context.Context, keys []dao.PreferenceKey) ([]dao.Preferences, error) {
+	args := m.Called(ctx, keys)
+	return args.Get(0).([]dao.Preferences), args.Error(1)
+}
+
+func (m *MockPreferencesDAO) SetPreferencesBulk(ctx context.Context, prefs []dao.Preferences) ([]dao.Preferences, error) {
+	args := m.Called(ctx, prefs)
+	return args.Get(0).([]dao.Preferences), args.Error(1)
+}
+
 type MockRecipesDAO struct {
 	mock.Mock
 }
@@ -125,11 +182,31 @@ func (m *MockRecipesDAO) UpdateRecipes(ctx context.Context, id string, r dao.Rec
 	return args.Get(0).(dao.Recipes), args.Error(1)
 }
 
+func (m *MockRecipesDAO) RateRecipe(ctx context.Context, recipeID, userUID string, rating int) (dao.Recipes, error) {
+	args := m.Called(ctx, recipeID, userUID, rating)
+	return args.Get(0).(dao.Recipes), args.Error(1)
+}
+
 func (m *MockRecipesDAO) DeleteRecipes(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockRecipesDAO) SetRecipePhoto(ctx context.Context, recipeID string, photos []dao.RecipePhoto) (dao.Recipes, error) {
+	args := m.Called(ctx, recipeID, photos)
+	return args.Get(0).(dao.Recipes), args.Error(1)
+}
+
+func (m *MockRecipesDAO) GetRecipePhoto(ctx context.Context, recipeID, size string) (dao.RecipePhoto, error) {
+	args := m.Called(ctx, recipeID, size)
+	return args.Get(0).(dao.RecipePhoto), args.Error(1)
+}
+
+func (m *MockRecipesDAO) DeleteRecipePhoto(ctx context.Context, recipeID string) error {
+	args := m.Called(ctx, recipeID)
+	return args.Error(0)
+}
+
 type MockUserDAO struct {
 	mock.Mock
 }
@@ -158,6 +235,24 @@ func (m *MockHouseholdDAO) GetHousehold(ctx context.Context, uid string) (dao.Ho
 	return args.Get(0).(dao.Households), args.Error(1)
 }
 
+// decodeToolResult unmarshals the JSON text content of a tool result into out
+// and checks that it matches the structuredContent.
+func decodeToolResult(t *testing.T, result mcp.CallToolResult, out any) {
+	t.Helper()
+	if !assert.Len(t, result.Content, 1) {
+		return
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.NoError(t, json.Unmarshal([]byte(textContent.Text), out))
+
+	structured, err := json.Marshal(result.StructuredContent)
+	assert.NoError(t, err)
+	assert.JSONEq(t, textContent.Text, string(structured))
+}
+
 func TestMCPHandlers_CreateTodo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -179,11 +274,28 @@ func TestMCPHandlers_CreateTodo(t *testing.T) {
 				Title:       "Test Todo",
 				Description: "Test Description",
 				Priority:    dao.Priority(4),
-				UserUID:      "user123",
+				UserUID:      strPtr("user123"),
 			},
 			mockError:     nil,
 			expectedError: false,
 		},
+		{
+			name: "priority label",
+			request: map[string]any{
+				"title":    "Test Todo",
+				"priority": "critical",
+			},
+			mockTodo:      dao.Todo{UID: "todo123", Title: "Test Todo", Priority: dao.PriorityCritical},
+			expectedError: false,
+		},
+		{
+			name: "invalid priority",
+			request: map[string]any{
+				"title":    "Test Todo",
+				"priority": float64(5),
+			},
+			expectedError: true,
+		},
 		{
 			name: "missing title",
 			request: map[string]any{
@@ -197,7 +309,9 @@ func TestMCPHandlers_CreateTodo(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDAO := &MockTodoDAO{}
 			if !tt.expectedError {
-				mockDAO.On("CreateTodo", mock.Anything, mock.AnythingOfType("postgres.Todo")).Return(tt.mockTodo, tt.mockError)
+				mockDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(todo dao.Todo) bool {
+					return todo.Priority == tt.mockTodo.Priority
+				})).Return(tt.mockTodo, tt.mockError)
 			}
 
 			h := &MCPHandlers{todoDAO: mockDAO}
@@ -209,10 +323,13 @@ func TestMCPHandlers_CreateTodo(t *testing.T) {
 				assert.False(t, result.IsError)
 				assert.NotNil(t, result)
 				if tt.mockError == nil {
-					assert.Len(t, result.Content, 1)
-					if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-						assert.Contains(t, textContent.Text, "Todo created successfully")
+					var body struct {
+						Status string   `json:"status"`
+						Todo   dao.Todo `json:"todo"`
 					}
+					decodeToolResult(t, result, &body)
+					assert.Equal(t, "ok", body.Status)
+					assert.Equal(t, tt.mockTodo.UID, body.Todo.UID)
 				}
 			}
 
@@ -237,8 +354,8 @@ func TestMCPHandlers_ListTodos(t *testing.T) {
 				"limit":   float64(10),
 			},
 			mockTodos: []dao.Todo{
-				{UID: "todo1", Title: "Todo 1", UserUID: "user123"},
-				{UID: "todo2", Title: "Todo 2", UserUID: "user123"},
+				{UID: "todo1", Title: "Todo 1", UserUID: strPtr("user123")},
+				{UID: "todo2", Title: "Todo 2", UserUID: strPtr("user123")},
 			},
 			mockError: nil,
 		},
@@ -250,7 +367,7 @@ func TestMCPHandlers_ListTodos(t *testing.T) {
 				"limit":   float64(5),
 			},
 			mockTodos: []dao.Todo{
-				{UID: "todo1", Title: "Work Task", UserUID: "user123"},
+				{UID: "todo1", Title: "Work Task", UserUID: strPtr("user123")},
 			},
 			mockError: nil,
 		},
@@ -274,12 +391,13 @@ func TestMCPHandlers_ListTodos(t *testing.T) {
 			assert.NotNil(t, result)
 
 			if tt.mockError == nil {
-				assert.Len(t, result.Content, 1)
-				if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-					var todos []dao.Todo
-					json.Unmarshal([]byte(textContent.Text), &todos)
-					assert.Equal(t, len(tt.mockTodos), len(todos))
+				var body struct {
+					Todos []dao.Todo `json:"todos"`
+					Count int        `json:"count"`
 				}
+				decodeToolResult(t, result, &body)
+				assert.Equal(t, len(tt.mockTodos), len(body.Todos))
+				assert.Equal(t, len(tt.mockTodos), body.Count)
 			}
 
 			mockDAO.AssertExpectations(t)
@@ -326,9 +444,17 @@ func TestMCPHandlers_CompleteTodo(t *testing.T) {
 
 			if tt.expectedError {
 				assert.True(t, result.IsError)
+				var body map[string]any
+				decodeToolResult(t, result, &body)
+				assert.Equal(t, "error", body["status"])
+				assert.NotEmpty(t, body["error"])
 			} else {
 				assert.False(t, result.IsError)
-				assert.NotNil(t, result)
+				var body struct {
+					Todo dao.Todo `json:"todo"`
+				}
+				decodeToolResult(t, result, &body)
+				assert.Equal(t, tt.mockTodo.UID, body.Todo.UID)
 			}
 
 			if !tt.expectedError {
@@ -376,6 +502,7 @@ func TestMCPHandlers_HTTPIntegration(t *testing.T) {
 	reqBody, _ := json.Marshal(mcpRequest)
 	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("MCP-Protocol-Version", LatestProtocolVersion)
 
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -389,6 +516,13 @@ func TestMCPHandlers_HTTPIntegration(t *testing.T) {
 	assert.Equal(t, "2.0", response["jsonrpc"])
 	assert.Equal(t, float64(1), response["id"])
 
+	result := response["result"].(map[string]any)
+	structured, ok := result["structuredContent"].(map[string]any)
+	if assert.True(t, ok, "structuredContent missing from tools/call response") {
+		assert.Equal(t, "ok", structured["status"])
+		assert.Equal(t, "test-todo-id", structured["todo"].(map[string]any)["uid"])
+	}
+
 	mockTodoDAO.AssertExpectations(t)
 }
 
@@ -407,8 +541,8 @@ func TestMCPHandlers_FindRecipes(t *testing.T) {
 				"limit":   float64(10),
 			},
 			mockRecipes: []dao.Recipes{
-				{ID: "recipe1", Title: "Pasta Carbonara", UserUID: "user123"},
-				{ID: "recipe2", Title: "Pasta Bolognese", UserUID: "user123"},
+				{ID: "recipe1", Title: "Pasta Carbonara", UserUID: strPtr("user123")},
+				{ID: "recipe2", Title: "Pasta Bolognese", UserUID: strPtr("user123")},
 			},
 			mockError: nil,
 		},
@@ -420,7 +554,7 @@ func TestMCPHandlers_FindRecipes(t *testing.T) {
 				"limit":   float64(5),
 			},
 			mockRecipes: []dao.Recipes{
-				{ID: "recipe1", Title: "Pasta Carbonara", UserUID: "user123"},
+				{ID: "recipe1", Title: "Pasta Carbonara", UserUID: strPtr("user123")},
 			},
 			mockError: nil,
 		},
@@ -444,12 +578,13 @@ func TestMCPHandlers_FindRecipes(t *testing.T) {
 			assert.NotNil(t, result)
 
 			if tt.mockError == nil {
-				assert.Len(t, result.Content, 1)
-				if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-					var recipes []dao.Recipes
-					json.Unmarshal([]byte(textContent.Text), &recipes)
-					assert.Equal(t, len(tt.mockRecipes), len(recipes))
+				var body struct {
+					Recipes []dao.Recipes `json:"recipes"`
+					Count   int           `json:"count"`
 				}
+				decodeToolResult(t, result, &body)
+				assert.Equal(t, len(tt.mockRecipes), len(body.Recipes))
+				assert.Equal(t, len(tt.mockRecipes), body.Count)
 			}
 
 			mockDAO.AssertExpectations(t)
@@ -492,7 +627,7 @@ func TestMCPHandlers_ToolsList(t *testing.T) {
 
 	tools, ok := result["tools"].([]any)
 	assert.True(t, ok)
-	assert.Len(t, tools, 13) // We have 13 tools defined
+	assert.Len(t, tools, 32) // We have 15 tools defined
 }
 
 func TestMCPHandlers_Initialize(t *testing.T) {
@@ -573,7 +708,8 @@ func TestMCPHandlers_Initialize(t *testing.T) {
 				}
 			}
 
-			result := h.handleInitialize(context.Background(), initParams)
+			result, session, err := h.handleInitialize(context.Background(), initParams)
+			assert.NoError(t, err)
 
 			assert.Equal(t, "2024-11-05", result.ProtocolVersion)
 			assert.Equal(t, "assistant-server", result.ServerInfo.Name)
@@ -583,10 +719,10 @@ func TestMCPHandlers_Initialize(t *testing.T) {
 			assert.True(t, result.Capabilities.Tools.ListChanged)
 			assert.NotEmpty(t, result.Instructions)
 
-			// Check that client info was stored
-			assert.NotNil(t, h.clientInfo)
-			assert.Equal(t, initParams.ClientInfo.Name, h.clientInfo.Name)
-			assert.Equal(t, initParams.ClientInfo.Version, h.clientInfo.Version)
+			// Check that client info was stored on the session
+			assert.NotNil(t, session)
+			assert.Equal(t, initParams.ClientInfo.Name, session.ClientInfo.Name)
+			assert.Equal(t, initParams.ClientInfo.Version, session.ClientInfo.Version)
 		})
 	}
 }
@@ -750,9 +886,13 @@ func TestMCPHandlers_UpdateUserDescription(t *testing.T) {
 				assert.NotNil(t, result)
 				if tt.mockError == nil {
 					assert.Len(t, result.Content, 1)
-					if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-						assert.Contains(t, textContent.Text, "User description updated successfully")
+					var body struct {
+						Summary string    `json:"summary"`
+						User    dao.Users `json:"user"`
 					}
+					decodeToolResult(t, result, &body)
+					assert.Contains(t, body.Summary, "User description updated successfully")
+					assert.Equal(t, tt.mockUser.UID, body.User.UID)
 				}
 			}
 
@@ -818,9 +958,13 @@ func TestMCPHandlers_UpdateHouseholdDescription(t *testing.T) {
 				assert.NotNil(t, result)
 				if tt.mockError == nil {
 					assert.Len(t, result.Content, 1)
-					if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-						assert.Contains(t, textContent.Text, "Household description updated successfully")
+					var body struct {
+						Summary   string         `json:"summary"`
+						Household dao.Households `json:"household"`
 					}
+					decodeToolResult(t, result, &body)
+					assert.Contains(t, body.Summary, "Household description updated successfully")
+					assert.Equal(t, tt.mockHousehold.UID, body.Household.UID)
 				}
 			}
 
@@ -830,3 +974,69 @@ func TestMCPHandlers_UpdateHouseholdDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPHandlers_GetPreferencesBulk(t *testing.T) {
+	mockPrefsDAO := &MockPreferencesDAO{}
+	mockPrefsDAO.On("GetPreferencesBulk", mock.Anything, []dao.PreferenceKey{{Key: "diet", Specifier: "user-1"}, {Key: "units", Specifier: "user-1"}}).
+		Return([]dao.Preferences{{Key: "diet", Specifier: "user-1", Data: "vegetarian"}}, nil)
+	h := &MCPHandlers{preferencesDAO: mockPrefsDAO}
+
+	result := h.handleGetPreferencesBulk(t.Context(), map[string]any{"preferences": []any{
+		map[string]any{"key": "diet", "specifier": "user-1"},
+		map[string]any{"key": "units", "specifier": "user-1"},
+	}})
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Found 1 of 2 preferences")
+	assert.Contains(t, text, `"missing":[{"key":"units","specifier":"user-1"}]`)
+	mockPrefsDAO.AssertExpectations(t)
+
+	for _, bad := range []any{nil, []any{}, []any{map[string]any{"key": "diet"}}, "diet"} {
+		result = h.handleGetPreferencesBulk(t.Context(), map[string]any{"preferences": bad})
+		assert.True(t, result.IsError, "%v", bad)
+	}
+	tooMany := make([]any, maxBulkPreferences+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"key": "k" + strconv.Itoa(i), "specifier": "user-1"}
+	}
+	result = h.handleGetPreferencesBulk(t.Context(), map[string]any{"preferences": tooMany})
+	assert.True(t, result.IsError)
+}
+
+func TestMCPHandlers_SetPreferencesBulk(t *testing.T) {
+	mockPrefsDAO := &MockPreferencesDAO{}
+	want := []dao.Preferences{
+		{Key: "diet", Specifier: "user-1", Data: "vegetarian", Tags: []string{"food", "health"}},
+		{Key: "units", Specifier: "user-1", Data: "metric"},
+	}
+	mockPrefsDAO.On("SetPreferencesBulk", mock.Anything, want).Return(want, nil).Once()
+	h := &MCPHandlers{preferencesDAO: mockPrefsDAO}
+
+	result := h.handleSetPreferencesBulk(t.Context(), map[string]any{"preferences": []any{
+		map[string]any{"key": "diet", "specifier": "user-1", "data": "vegetarian", "tags": "food, health"},
+		map[string]any{"key": "units", "specifier": "user-1", "data": "metric"},
+	}})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Saved 2 preferences")
+
+	result = h.handleSetPreferencesBulk(t.Context(), map[string]any{"preferences": []any{
+		map[string]any{"key": "diet", "specifier": "user-1", "data": "vegetarian"},
+		map[string]any{"key": "diet", "specifier": "user-1", "data": "vegan"},
+	}})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "preferences[1]: diet/user-1 is repeated")
+
+	result = h.handleSetPreferencesBulk(t.Context(), map[string]any{"preferences": []any{
+		map[string]any{"key": "units", "specifier": "user-1"},
+	}})
+	assert.True(t, result.IsError)
+
+	h.dataSchemaDAO = mealPlanSchemas(t)
+	result = h.handleSetPreferencesBulk(t.Context(), map[string]any{"preferences": []any{
+		map[string]any{"key": "units", "specifier": "user-1", "data": "metric"},
+		map[string]any{"key": "meal_plan", "specifier": "user-1", "data": "{}"},
+	}})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "/dinner: is required")
+	mockPrefsDAO.AssertExpectations(t)
+}
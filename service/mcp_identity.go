@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// ownerArgs names the arguments of a tool that identify a user or household.
+type ownerArgs struct {
+	// userArgs default to the authenticated user when omitted.
+	userArgs []string
+	// householdArg defaults to the authenticated user's household.
+	householdArg string
+	// listFilter marks list/search tools: when no owner is given they are
+	// scoped to the caller's household (or to the caller when they have none)
+	// instead of having every owner argument filled in.
+	listFilter bool
+}
+
+var toolOwnerArgs = map[string]ownerArgs{
+	"create_todo":                  {userArgs: []string{"user_uid"}, householdArg: "household_uid"},
+	"list_todos":                   {userArgs: []string{"user_uid"}, householdArg: "household_uid", listFilter: true},
+	"complete_todo":                {userArgs: []string{"completed_by"}},
+	"set_todo_status":              {userArgs: []string{"completed_by"}},
+	"log_time":                     {userArgs: []string{"user_uid"}},
+	"time_report":                  {userArgs: []string{"user_uid"}, householdArg: "household_uid", listFilter: true},
+	"save_note":                    {userArgs: []string{"user_uid"}, householdArg: "household_uid"},
+	"list_notes":                   {userArgs: []string{"user_uid"}, householdArg: "household_uid", listFilter: true},
+	"save_recipe":                  {userArgs: []string{"user_uid"}, householdArg: "household_uid"},
+	"find_recipes":                 {userArgs: []string{"user_uid"}, householdArg: "household_uid", listFilter: true},
+	"rate_recipe":                  {userArgs: []string{"user_uid"}},
+	"duplicate_recipe":             {userArgs: []string{"user_uid"}, householdArg: "household_uid"},
+	"update_user_description":      {userArgs: []string{"user_uid"}},
+	"update_household_description": {householdArg: "household_uid"},
+	"get_briefing":                 {userArgs: []string{"user_uid"}},
+	"set_notification_preference":  {userArgs: []string{"user_uid"}},
+	"apply_template":               {userArgs: []string{"user_uid"}, householdArg: "household_uid"},
+	"build_shopping_list":          {householdArg: "household_uid"},
+	"split_shopping_list":          {householdArg: "household_uid"},
+	"set_grocery_stores":           {householdArg: "household_uid"},
+	"set_preferred_store":          {householdArg: "household_uid"},
+	"add_pantry_item":              {householdArg: "household_uid"},
+	"list_pantry":                  {householdArg: "household_uid"},
+	"record_grocery_purchase":      {householdArg: "household_uid"},
+	"grocery_spend_report":         {householdArg: "household_uid"},
+	"list_calendar_events":         {userArgs: []string{"user_uid"}},
+	"create_calendar_event":        {userArgs: []string{"user_uid"}},
+	"set_away":                     {userArgs: []string{"user_uid"}},
+	"add_important_date":           {householdArg: "household_uid"},
+	"add_to_my_day":                {userArgs: []string{"user_uid"}},
+	"remove_from_my_day":           {userArgs: []string{"user_uid"}},
+	"get_my_day":                   {userArgs: []string{"user_uid"}},
+	"create_project":               {householdArg: "household_uid"},
+	"list_projects":                {householdArg: "household_uid"},
+}
+
+// applyIdentityDefaults fills in omitted user/household arguments from the
+// caller's API key identity and rejects explicit IDs outside the caller's
+// household. Unauthenticated calls are left untouched.
+func (h *MCPHandlers) applyIdentityDefaults(ctx context.Context, tool string, arguments map[string]any) error {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	owner, ok := toolOwnerArgs[tool]
+	if !ok {
+		return nil
+	}
+
+	for _, arg := range owner.userArgs {
+		uid, _ := arguments[arg].(string)
+		if uid == "" {
+			continue
+		}
+		if err := h.checkUserInHousehold(ctx, id, uid); err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	if owner.householdArg != "" {
+		if uid, _ := arguments[owner.householdArg].(string); uid != "" && uid != id.HouseholdUID {
+			return fmt.Errorf("%s: household %s does not belong to the authenticated user", owner.householdArg, uid)
+		}
+	}
+
+	if owner.listFilter {
+		if hasAnyArg(arguments, append(owner.userArgs, owner.householdArg)...) {
+			return nil
+		}
+		if id.HouseholdUID != "" {
+			arguments[owner.householdArg] = id.HouseholdUID
+		} else {
+			arguments[owner.userArgs[0]] = id.UserUID
+		}
+		return nil
+	}
+
+	for _, arg := range owner.userArgs {
+		if !hasAnyArg(arguments, arg) {
+			arguments[arg] = id.UserUID
+		}
+	}
+	if owner.householdArg != "" && id.HouseholdUID != "" && !hasAnyArg(arguments, owner.householdArg) {
+		arguments[owner.householdArg] = id.HouseholdUID
+	}
+	return nil
+}
+
+// checkUserInHousehold allows the caller themselves or another member of the
+// caller's household.
+func (h *MCPHandlers) checkUserInHousehold(ctx context.Context, id Identity, userUID string) error {
+	if userUID == id.UserUID {
+		return nil
+	}
+	if id.HouseholdUID == "" {
+		return fmt.Errorf("user %s is not the authenticated user", userUID)
+	}
+	user, err := h.userDAO.GetUser(ctx, userUID)
+	if err != nil || user.HouseholdUID == nil || *user.HouseholdUID != id.HouseholdUID {
+		return fmt.Errorf("user %s is not in the authenticated user's household", userUID)
+	}
+	return nil
+}
+
+func hasAnyArg(arguments map[string]any, names ...string) bool {
+	for _, name := range names {
+		if v, _ := arguments[name].(string); v != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func identityContext(userUID, householdUID string) context.Context {
+	return WithIdentity(context.Background(), Identity{
+		APIKeyUID:    "key-1",
+		UserUID:      userUID,
+		HouseholdUID: householdUID,
+		Scopes:       []string{ScopeMCPWrite},
+	})
+}
+
+func TestMCPHandlers_CreateDefaultsOwnerFromIdentity(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(todo dao.Todo) bool {
+		return todo.UserUID != nil && *todo.UserUID == "user-1" &&
+			todo.HouseholdUID != nil && *todo.HouseholdUID == "house-1"
+	})).Return(dao.Todo{UID: "todo-1"}, nil)
+
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	result := h.callTool(identityContext("user-1", "house-1"), "create_todo", map[string]any{"title": "Buy milk"})
+
+	assert.False(t, result.IsError)
+	mockTodoDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_ListDefaultsToCallerHousehold(t *testing.T) {
+	tests := []struct {
+		name      string
+		household string
+		args      map[string]any
+		wantArgs  []any
+	}{
+		{name: "household member", household: "house-1", args: map[string]any{}, wantArgs: []any{"house-1"}},
+		{name: "no household", household: "", args: map[string]any{}, wantArgs: []any{"user-1"}},
+		{name: "explicit user filter", household: "house-1", args: map[string]any{"user_uid": "user-1"}, wantArgs: []any{"user-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTodoDAO := &MockTodoDAO{}
+			mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+				return assert.ObjectsAreEqual(tt.wantArgs, o.WhereArgs)
+			})).Return([]dao.Todo{}, nil)
+
+			h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+			result := h.callTool(identityContext("user-1", tt.household), "list_todos", tt.args)
+
+			assert.False(t, result.IsError)
+			mockTodoDAO.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMCPHandlers_RejectsForeignOwnerIDs(t *testing.T) {
+	house := "house-1"
+	other := "house-2"
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "member").Return(dao.Users{UID: "member", HouseholdUID: &house}, nil)
+	mockUserDAO.On("GetUser", mock.Anything, "stranger").Return(dao.Users{UID: "stranger", HouseholdUID: &other}, nil)
+	mockUserDAO.On("GetUser", mock.Anything, "ghost").Return(dao.Users{}, errors.New("no rows"))
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]any
+		wantErr string
+	}{
+		{
+			name:    "household from another account",
+			tool:    "save_note",
+			args:    map[string]any{"key": "k", "data": "d", "household_uid": "house-2"},
+			wantErr: "household_uid: household house-2 does not belong to the authenticated user",
+		},
+		{
+			name:    "user outside household",
+			tool:    "create_todo",
+			args:    map[string]any{"title": "t", "user_uid": "stranger"},
+			wantErr: "user_uid: user stranger is not in the authenticated user's household",
+		},
+		{
+			name:    "unknown user",
+			tool:    "complete_todo",
+			args:    map[string]any{"todo_id": "todo-1", "completed_by": "ghost"},
+			wantErr: "completed_by: user ghost is not in the authenticated user's household",
+		},
+		{
+			name:    "foreign household filter",
+			tool:    "find_recipes",
+			args:    map[string]any{"household_uid": "house-2"},
+			wantErr: "household_uid: household house-2 does not belong to the authenticated user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, &MockHouseholdDAO{})
+			result := h.callTool(identityContext("user-1", "house-1"), tt.tool, tt.args)
+
+			assert.True(t, result.IsError)
+			var body map[string]any
+			decodeToolResult(t, result, &body)
+			assert.Equal(t, tt.wantErr, body["error"])
+		})
+	}
+
+	t.Run("household member allowed", func(t *testing.T) {
+		mockTodoDAO := &MockTodoDAO{}
+		mockTodoDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(todo dao.Todo) bool {
+			return *todo.UserUID == "member"
+		})).Return(dao.Todo{UID: "todo-1"}, nil)
+
+		h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, &MockHouseholdDAO{})
+		result := h.callTool(identityContext("user-1", "house-1"), "create_todo", map[string]any{"title": "t", "user_uid": "member"})
+		assert.False(t, result.IsError)
+		mockTodoDAO.AssertExpectations(t)
+	})
+}
+
+func TestMCPHandlers_UpdateDescriptionsDefaultToCaller(t *testing.T) {
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("UpdateUser", mock.Anything, "user-1", mock.AnythingOfType("postgres.UpdateUser")).Return(dao.Users{UID: "user-1"}, nil)
+	mockHouseholdDAO := &MockHouseholdDAO{}
+	mockHouseholdDAO.On("UpdateHousehold", mock.Anything, "house-1", mock.AnythingOfType("postgres.UpdateHousehold")).Return(dao.Households{UID: "house-1"}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, mockHouseholdDAO)
+	ctx := identityContext("user-1", "house-1")
+
+	assert.False(t, h.callTool(ctx, "update_user_description", map[string]any{"description": "Vegetarian"}).IsError)
+	assert.False(t, h.callTool(ctx, "update_household_description", map[string]any{"description": "Family of four"}).IsError)
+	mockUserDAO.AssertExpectations(t)
+	mockHouseholdDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_NoIdentityKeepsArgumentsAsGiven(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	result := h.callTool(context.Background(), "update_user_description", map[string]any{"description": "x"})
+
+	var body map[string]any
+	decodeToolResult(t, result, &body)
+	assert.Equal(t, "user_uid is required", body["error"])
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MCP log levels, in increasing severity (RFC 5424).
+var logLevelSeverity = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// defaultClientLogLevel is used until a client calls logging/setLevel, so tool
+// failures are surfaced without any opt-in.
+const defaultClientLogLevel = "error"
+
+var errSessionRequired = errors.New("session required")
+
+// handleSetLevel stores the client's log level on its session; stateless
+// requests have nowhere to keep it.
+func (h *MCPHandlers) handleSetLevel(ctx context.Context, level string) error {
+	if _, ok := logLevelSeverity[level]; !ok {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	session, ok := sessionFrom(ctx)
+	if !ok {
+		return errSessionRequired
+	}
+	session.SetLogLevel(level)
+	return nil
+}
+
+func clientLogLevel(ctx context.Context) string {
+	if session, ok := sessionFrom(ctx); ok {
+		if level := session.LogLevel(); level != "" {
+			return level
+		}
+	}
+	return defaultClientLogLevel
+}
+
+// clientLog sends a notifications/message log event to the client if level is
+// at or above the level it asked for. Like other notifications it is only
+// delivered to clients that accept an event stream.
+func (h *MCPHandlers) clientLog(ctx context.Context, level string, data any) {
+	if logLevelSeverity[level] < logLevelSeverity[clientLogLevel(ctx)] {
+		return
+	}
+	sendNotification(ctx, "notifications/message", map[string]any{
+		"level":  level,
+		"logger": h.serverInfo.Name,
+		"data":   data,
+	})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPHandlers_SetLevel(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	sessionID := initializeSession(t, h, nil)
+	session, _ := h.sessions.Get(sessionID)
+	headers := map[string]string{"Mcp-Session-Id": sessionID}
+
+	w := postMCP(h, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "logging/setLevel", "params": map[string]any{"level": "debug"}}, headers)
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]any{}, response["result"])
+	assert.Equal(t, "debug", session.LogLevel())
+
+	w = postMCP(h, map[string]any{"jsonrpc": "2.0", "id": 2, "method": "logging/setLevel", "params": map[string]any{"level": "loud"}}, headers)
+	response = nil
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32602), response["error"].(map[string]any)["code"])
+	assert.Equal(t, "debug", session.LogLevel())
+
+	w = postMCP(h, map[string]any{"jsonrpc": "2.0", "id": 3, "method": "logging/setLevel", "params": map[string]any{"level": "info"}}, nil)
+	response = nil
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32600), response["error"].(map[string]any)["code"])
+}
+
+func TestMCPHandlers_ToolErrorsEmitLogMessages(t *testing.T) {
+	failingCall := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "create_todo",
+			"arguments": map[string]any{},
+		},
+	}
+	streamHeaders := map[string]string{"Accept": "application/json, text/event-stream"}
+
+	t.Run("streams error log before the response", func(t *testing.T) {
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+		w := postMCP(h, failingCall, streamHeaders)
+
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		msgs := readSSEMessages(t, w.Body.String())
+		if assert.Len(t, msgs, 2) {
+			assert.Equal(t, "notifications/message", msgs[0]["method"])
+			params := msgs[0]["params"].(map[string]any)
+			assert.Equal(t, "error", params["level"])
+			assert.Equal(t, "assistant-server", params["logger"])
+			assert.Equal(t, map[string]any{"tool": "create_todo", "error": "title is required"}, params["data"])
+			assert.Equal(t, float64(1), msgs[1]["id"])
+		}
+	})
+
+	t.Run("suppressed below the client's level", func(t *testing.T) {
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+		sessionID := initializeSession(t, h, nil)
+		session, _ := h.sessions.Get(sessionID)
+		session.SetLogLevel("critical")
+
+		w := postMCP(h, failingCall, map[string]string{
+			"Accept":         "application/json, text/event-stream",
+			"Mcp-Session-Id": sessionID,
+		})
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("plain JSON for clients without event streams", func(t *testing.T) {
+		h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+		w := postMCP(h, failingCall, nil)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+}
+
+func TestMCPHandlers_AdvertisesLoggingCapability(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	result, _, err := h.handleInitialize(t.Context(), InitializeParams{ProtocolVersion: LatestProtocolVersion})
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Capabilities.Logging)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Resource URIs name a single todo or note, e.g. todo://{uid}. Each scheme
+// maps to the REST collection its change events are published under.
+var resourceSchemes = map[string]string{
+	"todo": "todos",
+	"note": "notes",
+}
+
+var errResourceNotFound = errors.New("resource not found")
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+var resourceTemplates = []ResourceTemplate{
+	{URITemplate: "todo://{uid}", Name: "todo", Description: "A todo", MimeType: "application/json"},
+	{URITemplate: "note://{id}", Name: "note", Description: "A note the caller can read", MimeType: "application/json"},
+}
+
+// parseResourceURI splits a resource URI into the collection it belongs to
+// and the row's ID.
+func parseResourceURI(uri string) (entity, id string, ok bool) {
+	scheme, id, found := strings.Cut(uri, "://")
+	entity, known := resourceSchemes[scheme]
+	if !found || !known || id == "" || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	return entity, id, true
+}
+
+// readResource returns the row a resource URI names, as JSON, and the
+// household it belongs to.
+func (h *MCPHandlers) readResource(ctx context.Context, uri string) (ResourceContents, string, error) {
+	entity, id, ok := parseResourceURI(uri)
+	if !ok {
+		return ResourceContents{}, "", errResourceNotFound
+	}
+	var row any
+	var householdUID *string
+	switch entity {
+	case "todos":
+		t, err := h.todoDAO.GetTodo(ctx, id)
+		if err != nil {
+			return ResourceContents{}, "", errResourceNotFound
+		}
+		row, householdUID = t, t.HouseholdUID
+	case "notes":
+		n, err := h.notesDAO.GetNotes(ctx, id)
+		if err != nil || !noteAccessible(ctx, n) {
+			return ResourceContents{}, "", errResourceNotFound
+		}
+		row, householdUID = n, n.HouseholdUID
+	}
+	text, err := json.Marshal(row)
+	if err != nil {
+		return ResourceContents{}, "", err
+	}
+	household := ""
+	if householdUID != nil {
+		household = *householdUID
+	}
+	return ResourceContents{URI: uri, MimeType: "application/json", Text: string(text)}, household, nil
+}
+
+// handleSubscribe records that the session wants notifications/resources/updated
+// for uri. Only resources the caller can read can be subscribed to.
+func (h *MCPHandlers) handleSubscribe(ctx context.Context, uri string) error {
+	session, ok := sessionFrom(ctx)
+	if !ok {
+		return errSessionRequired
+	}
+	_, householdUID, err := h.readResource(ctx, uri)
+	if err != nil {
+		return err
+	}
+	// A row without a household has its changes published under the
+	// household of whoever makes them, most likely the subscriber's.
+	if householdUID == "" && session.Identity != nil {
+		householdUID = session.Identity.HouseholdUID
+	}
+	session.subscribe(uri, householdUID)
+	return nil
+}
+
+func (h *MCPHandlers) handleUnsubscribe(ctx context.Context, uri string) error {
+	session, ok := sessionFrom(ctx)
+	if !ok {
+		return errSessionRequired
+	}
+	session.unsubscribe(uri)
+	return nil
+}
+
+// resourceResponse answers the resources/* methods.
+func (h *MCPHandlers) resourceResponse(ctx context.Context, method string, params map[string]any) (result, rpcErr any) {
+	uri, _ := params["uri"].(string)
+	switch method {
+	case "resources/list":
+		// Rows are reached through the templates rather than listed.
+		return map[string]any{"resources": []any{}}, nil
+	case "resources/templates/list":
+		return map[string]any{"resourceTemplates": resourceTemplates}, nil
+	case "resources/read":
+		contents, _, err := h.readResource(ctx, uri)
+		if err != nil {
+			return nil, resourceError(uri, err)
+		}
+		return map[string]any{"contents": []ResourceContents{contents}}, nil
+	case "resources/subscribe":
+		if err := h.handleSubscribe(ctx, uri); err != nil {
+			return nil, resourceError(uri, err)
+		}
+		return map[string]any{}, nil
+	default:
+		if err := h.handleUnsubscribe(ctx, uri); err != nil {
+			return nil, resourceError(uri, err)
+		}
+		return map[string]any{}, nil
+	}
+}
+
+func resourceError(uri string, err error) map[string]any {
+	switch {
+	case errors.Is(err, errSessionRequired):
+		return map[string]any{"code": -32600, "message": "Resource subscriptions require an Mcp-Session-Id"}
+	case errors.Is(err, errResourceNotFound):
+		return map[string]any{"code": -32002, "message": "Resource not found", "data": map[string]any{"uri": uri}}
+	default:
+		return map[string]any{"code": -32603, "message": err.Error()}
+	}
+}
+
+func (s *mcpSession) subscribe(uri, householdUID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscriptions == nil {
+		s.subscriptions = map[string]string{}
+	}
+	s.subscriptions[uri] = householdUID
+}
+
+func (s *mcpSession) unsubscribe(uri string) {
+	s.mu.Lock()
+	delete(s.subscriptions, uri)
+	s.mu.Unlock()
+}
+
+// updatedResources are the session's subscribed URIs that e may have
+// changed. An event naming no row may have changed any row of its entity in
+// its household; a row that was just created can't have been subscribed to.
+func (s *mcpSession) updatedResources(e ChangeEvent) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var uris []string
+	for uri, householdUID := range s.subscriptions {
+		entity, id, _ := parseResourceURI(uri)
+		if entity != e.Entity {
+			continue
+		}
+		if id == e.ID || (e.ID == "" && e.Action != "created" && householdUID == e.HouseholdUID) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// resourceStream serves GET /mcp: the event stream on which a session
+// receives notifications/resources/updated for the resources it subscribed
+// to. It stays open until the client goes away.
+func (h *MCPHandlers) resourceStream(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil || !acceptsEventStream(r) {
+		w.Header().Set("Allow", http.MethodPost+", "+http.MethodDelete)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.Header.Get(sessionIDHeader)
+	if id == "" {
+		http.Error(w, "Missing Mcp-Session-Id header", http.StatusBadRequest)
+		return
+	}
+	session, ok := h.sessions.Get(id)
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if !sessionIdentityMatches(session, r) {
+		http.Error(w, "Session belongs to a different API key", http.StatusForbidden)
+		return
+	}
+
+	// Subscriptions are checked against every household's events, as a
+	// session without an API key may subscribe to rows of any household.
+	events, unsubscribe := h.events.Subscribe("")
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	stream := &eventStream{w: w, enabled: true, started: true}
+	h.log().Info("MCP resource stream opened", slog.String("session_id", session.ID))
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			for _, uri := range session.updatedResources(e) {
+				stream.notify("notifications/resources/updated", map[string]any{"uri": uri})
+			}
+		}
+	}
+}
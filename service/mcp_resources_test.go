@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceURI(t *testing.T) {
+	entity, id, ok := parseResourceURI("todo://t1")
+	assert.True(t, ok)
+	assert.Equal(t, "todos", entity)
+	assert.Equal(t, "t1", id)
+
+	for _, uri := range []string{"todo://", "recipe://r1", "note:n1", "note://n1/extra"} {
+		_, _, ok := parseResourceURI(uri)
+		assert.False(t, ok, uri)
+	}
+}
+
+func TestSessionUpdatedResources(t *testing.T) {
+	s := &mcpSession{}
+	s.subscribe("todo://t1", "house-1")
+	s.subscribe("note://n1", "house-1")
+
+	assert.Equal(t, []string{"todo://t1"}, s.updatedResources(ChangeEvent{Entity: "todos", Action: "updated", ID: "t1", HouseholdUID: "house-1"}))
+	assert.Empty(t, s.updatedResources(ChangeEvent{Entity: "todos", Action: "updated", ID: "t2", HouseholdUID: "house-1"}))
+	// Tools such as move_todos_to_project change todos without naming one.
+	assert.Equal(t, []string{"todo://t1"}, s.updatedResources(ChangeEvent{Entity: "todos", Action: "updated", HouseholdUID: "house-1"}))
+	assert.Empty(t, s.updatedResources(ChangeEvent{Entity: "todos", Action: "updated", HouseholdUID: "house-2"}))
+	assert.Empty(t, s.updatedResources(ChangeEvent{Entity: "todos", Action: "created", HouseholdUID: "house-1"}))
+
+	s.unsubscribe("todo://t1")
+	assert.Empty(t, s.updatedResources(ChangeEvent{Entity: "todos", Action: "updated", ID: "t1", HouseholdUID: "house-1"}))
+}
+
+func TestMCPHandlers_ResourceSubscriptions(t *testing.T) {
+	hub := NewEventHub()
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("GetTodo", mock.Anything, "t1").Return(postgres.Todo{UID: "t1", Title: "Bins", HouseholdUID: strPtr("house-1")}, nil)
+	mockTodoDAO.On("GetTodo", mock.Anything, "missing").Return(postgres.Todo{}, errors.New("no rows in result set"))
+	router := NewMCPRouter(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithEvents(hub))
+	sessionID := initializeSession(t, router, nil)
+	headers := map[string]string{"Mcp-Session-Id": sessionID}
+
+	call := func(method, uri string, headers map[string]string) map[string]any {
+		w := postMCP(router, map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": map[string]any{"uri": uri}}, headers)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	assert.Equal(t, map[string]any{}, call("resources/subscribe", "todo://t1", headers)["result"])
+	assert.Equal(t, float64(-32002), call("resources/subscribe", "todo://missing", headers)["error"].(map[string]any)["code"])
+	assert.Equal(t, float64(-32600), call("resources/subscribe", "todo://t1", nil)["error"].(map[string]any)["code"])
+
+	contents := call("resources/read", "todo://t1", nil)["result"].(map[string]any)["contents"].([]any)
+	assert.Contains(t, contents[0].(map[string]any)["text"], `"title":"Bins"`)
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "updated", ID: "t2", HouseholdUID: "house-1"})
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "updated", ID: "t1", HouseholdUID: "house-1"})
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && !strings.HasPrefix(scanner.Text(), "data: ") {
+	}
+	msgs := readSSEMessages(t, scanner.Text())
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "notifications/resources/updated", msgs[0]["method"])
+	assert.Equal(t, map[string]any{"uri": "todo://t1"}, msgs[0]["params"])
+
+	// Without an event stream to send them on, GET is not allowed.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestMCPToolChangesNameTheirRow(t *testing.T) {
+	hub := NewEventHub()
+	events, _ := hub.Subscribe("house-1")
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("UpdateTodo", mock.Anything, "t1", mock.Anything).Return(postgres.Todo{UID: "t1"}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithEvents(hub))
+
+	result := h.callTool(identityContext("user-1", "house-1"), "complete_todo", map[string]any{"todo_id": "t1"})
+	require.False(t, result.IsError)
+	assert.Equal(t, "t1", nextEvent(t, events).ID)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sessionIDHeader = "Mcp-Session-Id"
+
+	defaultSessionTTL = 24 * time.Hour
+	// defaultMaxSessions bounds how many sessions a server keeps for each
+	// API key, and for anonymous clients.
+	defaultMaxSessions = 10000
+	// sessionPruneInterval is how often lookups sweep out idle sessions.
+	sessionPruneInterval = time.Minute
+)
+
+// mcpSession is the state negotiated by initialize. It is shared by every
+// request that carries the session's Mcp-Session-Id header.
+type mcpSession struct {
+	ID                 string
+	ClientInfo         ClientInfo
+	ClientCapabilities ClientCapabilities
+	ProtocolVersion    string
+	// Identity is the API key identity that created the session, if any.
+	// Later requests must present the same key.
+	Identity *Identity
+
+	mu       sync.Mutex
+	logLevel string
+	// subscriptions maps each subscribed resource URI to the household
+	// whose change events can update it.
+	subscriptions map[string]string
+
+	// lastSeen and elem belong to the store and are guarded by its mutex.
+	lastSeen time.Time
+	elem     *list.Element
+}
+
+func (s *mcpSession) LogLevel() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logLevel
+}
+
+func (s *mcpSession) SetLogLevel(level string) {
+	s.mu.Lock()
+	s.logLevel = level
+	s.mu.Unlock()
+}
+
+// owner is whose sessions s counts against: its API key's, or, without
+// one, every anonymous client's together.
+func (s *mcpSession) owner() string {
+	if s.Identity == nil {
+		return ""
+	}
+	return s.Identity.APIKeyUID
+}
+
+// sessionStore keeps sessions in memory. Sessions idle for longer than ttl
+// are swept out when a session is created, and by lookups at most once per
+// sessionPruneInterval. Each API key, and anonymous clients together, may
+// keep at most max sessions; creating one more drops that owner's session
+// idle the longest, so no one can crowd out anyone else's sessions. Each
+// owner's sessions are kept most recently used first, so neither sweeping
+// nor making room has to look at sessions that stay.
+type sessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*mcpSession
+	owners    map[string]*list.List
+	ttl       time.Duration
+	max       int
+	now       func() time.Time
+	lastPrune time.Time
+}
+
+func newSessionStore(ttl time.Duration) *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*mcpSession),
+		owners:   make(map[string]*list.List),
+		ttl:      ttl,
+		max:      defaultMaxSessions,
+		now:      time.Now,
+	}
+}
+
+func (st *sessionStore) Create(clientInfo ClientInfo, capabilities ClientCapabilities, version string, identity *Identity) *mcpSession {
+	now := st.now()
+	s := &mcpSession{
+		ID:                 uuid.NewString(),
+		ClientInfo:         clientInfo,
+		ClientCapabilities: capabilities,
+		ProtocolVersion:    version,
+		Identity:           identity,
+		lastSeen:           now,
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.prune(now)
+	sessions := st.owners[s.owner()]
+	if sessions == nil {
+		sessions = list.New()
+		st.owners[s.owner()] = sessions
+	}
+	for sessions.Len() >= st.max {
+		st.remove(sessions.Back().Value.(*mcpSession))
+	}
+	s.elem = sessions.PushFront(s)
+	st.sessions[s.ID] = s
+	return s
+}
+
+// prune drops the sessions idle for longer than ttl. st.mu must be held.
+func (st *sessionStore) prune(now time.Time) {
+	st.lastPrune = now
+	for _, sessions := range st.owners {
+		for e := sessions.Back(); e != nil; e = sessions.Back() {
+			if s := e.Value.(*mcpSession); now.Sub(s.lastSeen) > st.ttl {
+				st.remove(s)
+				continue
+			}
+			break
+		}
+	}
+}
+
+// remove drops s from the store. st.mu must be held.
+func (st *sessionStore) remove(s *mcpSession) {
+	delete(st.sessions, s.ID)
+	sessions := st.owners[s.owner()]
+	sessions.Remove(s.elem)
+	if sessions.Len() == 0 {
+		delete(st.owners, s.owner())
+	}
+}
+
+func (st *sessionStore) Get(id string) (*mcpSession, bool) {
+	now := st.now()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if now.Sub(st.lastPrune) >= sessionPruneInterval {
+		st.prune(now)
+	}
+	s, ok := st.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	if now.Sub(s.lastSeen) > st.ttl {
+		st.remove(s)
+		return nil, false
+	}
+	s.lastSeen = now
+	st.owners[s.owner()].MoveToFront(s.elem)
+	return s, true
+}
+
+func (st *sessionStore) Delete(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[id]
+	if ok {
+		st.remove(s)
+	}
+	return ok
+}
+
+func (st *sessionStore) Len() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return len(st.sessions)
+}
+
+// WithSessionTTL sets how long an idle MCP session is kept.
+func WithSessionTTL(ttl time.Duration) MCPOption {
+	return func(h *MCPHandlers) {
+		if ttl > 0 {
+			h.sessions.ttl = ttl
+		}
+	}
+}
+
+// WithMaxSessions caps how many MCP sessions are kept at once for each API
+// key, and for anonymous clients.
+func WithMaxSessions(n int) MCPOption {
+	return func(h *MCPHandlers) {
+		if n > 0 {
+			h.sessions.max = n
+		}
+	}
+}
+
+type sessionKey struct{}
+
+func withSession(ctx context.Context, s *mcpSession) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// sessionFrom returns the session the current request belongs to. Requests
+// without an Mcp-Session-Id header are stateless and have no session.
+func sessionFrom(ctx context.Context) (*mcpSession, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*mcpSession)
+	return s, ok && s != nil
+}
+
+// sessionIdentityMatches reports whether the identity on the request may use
+// session s: a session created with an API key only accepts that same key.
+func sessionIdentityMatches(s *mcpSession, r *http.Request) bool {
+	if s.Identity == nil {
+		return true
+	}
+	id, ok := IdentityFromContext(r.Context())
+	return ok && id.APIKeyUID == s.Identity.APIKeyUID
+}
+
+// deleteSession terminates the session named in the Mcp-Session-Id header.
+func (h *MCPHandlers) deleteSession(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(sessionIDHeader)
+	if id == "" {
+		http.Error(w, "Missing Mcp-Session-Id header", http.StatusBadRequest)
+		return
+	}
+	s, ok := h.sessions.Get(id)
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if !sessionIdentityMatches(s, r) {
+		http.Error(w, "Session belongs to a different API key", http.StatusForbidden)
+		return
+	}
+	h.sessions.Delete(id)
+	w.WriteHeader(http.StatusNoContent)
+}
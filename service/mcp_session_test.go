@@ -0,0 +1,227 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// initializeSession runs initialize against router and returns the session ID
+// from the response.
+func initializeSession(t *testing.T, router http.Handler, headers map[string]string) string {
+	t.Helper()
+	w := postMCP(router, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": ProtocolVersion20250326,
+			"capabilities":    map[string]any{"elicitation": map[string]any{}},
+			"clientInfo":      map[string]any{"name": "TestClient", "version": "1.0.0"},
+		},
+	}, headers)
+	assert.Equal(t, http.StatusOK, w.Code)
+	id := w.Header().Get("Mcp-Session-Id")
+	assert.NotEmpty(t, id)
+	return id
+}
+
+func TestMCPHandlers_InitializeCreatesSession(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	first := initializeSession(t, h, nil)
+	second := initializeSession(t, h, nil)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, 2, h.sessions.Len())
+
+	session, ok := h.sessions.Get(first)
+	if assert.True(t, ok) {
+		assert.Equal(t, "TestClient", session.ClientInfo.Name)
+		assert.Equal(t, ProtocolVersion20250326, session.ProtocolVersion)
+		assert.NotNil(t, session.ClientCapabilities.Elicitation)
+		assert.Nil(t, session.Identity)
+	}
+}
+
+func TestMCPHandlers_ConcurrentInitializeKeepsClientsApart(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var wg sync.WaitGroup
+	ids := make([]string, 20)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, session, err := h.handleInitialize(t.Context(), InitializeParams{
+				ProtocolVersion: LatestProtocolVersion,
+				ClientInfo:      ClientInfo{Name: "client", Version: time.Duration(i).String()},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, LatestProtocolVersion, result.ProtocolVersion)
+			ids[i] = session.ID
+		}()
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		session, ok := h.sessions.Get(id)
+		if assert.True(t, ok) {
+			assert.Equal(t, time.Duration(i).String(), session.ClientInfo.Version)
+		}
+	}
+}
+
+func TestMCPHandlers_SessionProtocolVersion(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	w := postMCP(h, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": ProtocolVersion20241105,
+			"clientInfo":      map[string]any{"name": "LegacyClient", "version": "1.0.0"},
+		},
+	}, nil)
+	sessionID := w.Header().Get("Mcp-Session-Id")
+
+	// No MCP-Protocol-Version header: the session's version applies.
+	w = postMCP(h, map[string]any{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}, map[string]string{"Mcp-Session-Id": sessionID})
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	tools := response["result"].(map[string]any)["tools"].([]any)
+	assert.NotContains(t, tools[0].(map[string]any), "annotations")
+}
+
+func TestMCPHandlers_UnknownSession(t *testing.T) {
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	w := postMCP(h, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}, map[string]string{"Mcp-Session-Id": "missing"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMCPHandlers_SessionBoundToAPIKey(t *testing.T) {
+	keys := mocks.NewMockapiKeyDAO(t)
+	keys.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_one")).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", Scopes: []string{ScopeMCPWrite}}, nil)
+	keys.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_two")).Return(postgres.APIKeys{UID: "key-2", UserUID: "user-2", Scopes: []string{ScopeMCPWrite}}, nil)
+	keys.On("TouchAPIKey", mock.Anything, mock.Anything).Return(nil)
+
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithAPIKeys(keys, false))
+
+	sessionID := initializeSession(t, router, map[string]string{"Authorization": "Bearer ak_one"})
+	list := map[string]any{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}
+
+	w := postMCP(router, list, map[string]string{"Mcp-Session-Id": sessionID, "Authorization": "Bearer ak_one"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = postMCP(router, list, map[string]string{"Mcp-Session-Id": sessionID, "Authorization": "Bearer ak_two"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postMCP(router, list, map[string]string{"Mcp-Session-Id": sessionID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMCPHandlers_DeleteSession(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	sessionID := initializeSession(t, router, nil)
+
+	del := func() int {
+		req := httptest.NewRequest("DELETE", "/", nil)
+		req.Header.Set("Mcp-Session-Id", sessionID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusNoContent, del())
+	assert.Equal(t, http.StatusNotFound, del())
+
+	w := postMCP(router, map[string]any{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}, map[string]string{"Mcp-Session-Id": sessionID})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSessionStore_ExpiresIdleSessions(t *testing.T) {
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	store := newSessionStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	stale := store.Create(ClientInfo{Name: "stale"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	now = now.Add(30 * time.Minute)
+	fresh := store.Create(ClientInfo{Name: "fresh"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+
+	now = now.Add(45 * time.Minute)
+	_, ok := store.Get(stale.ID)
+	assert.False(t, ok)
+	_, ok = store.Get(fresh.ID)
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Hour)
+	store.Create(ClientInfo{Name: "new"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestSessionStore_LookupsSweepIdleSessions(t *testing.T) {
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	store := newSessionStore(time.Hour)
+	store.now = func() time.Time { return now }
+	for range 3 {
+		store.Create(ClientInfo{Name: "gone"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	}
+	active := store.Create(ClientInfo{Name: "active"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+
+	// No new sessions are created, but the one client still around keeps
+	// looking its session up.
+	for range 4 {
+		now = now.Add(30 * time.Minute)
+		_, ok := store.Get(active.ID)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestSessionStore_CapsSessions(t *testing.T) {
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	store := newSessionStore(time.Hour)
+	store.max = 2
+	store.now = func() time.Time { return now }
+
+	first := store.Create(ClientInfo{Name: "first"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	now = now.Add(time.Minute)
+	second := store.Create(ClientInfo{Name: "second"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	now = now.Add(time.Minute)
+	_, ok := store.Get(first.ID)
+	require.True(t, ok)
+
+	// The session idle the longest makes way.
+	third := store.Create(ClientInfo{Name: "third"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	assert.Equal(t, 2, store.Len())
+	_, ok = store.Get(second.ID)
+	assert.False(t, ok)
+	for _, s := range []*mcpSession{first, third} {
+		_, ok = store.Get(s.ID)
+		assert.True(t, ok, s.ClientInfo.Name)
+	}
+
+	// Anonymous clients only ever make way for each other, so they can't
+	// push out the sessions of a key.
+	keyed := store.Create(ClientInfo{Name: "keyed"}, ClientCapabilities{}, LatestProtocolVersion, &Identity{APIKeyUID: "key-1"})
+	for range 5 {
+		now = now.Add(time.Minute)
+		store.Create(ClientInfo{Name: "anonymous"}, ClientCapabilities{}, LatestProtocolVersion, nil)
+	}
+	assert.Equal(t, 3, store.Len())
+	_, ok = store.Get(keyed.ID)
+	assert.True(t, ok)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithMaxSessions(5))
+	assert.Equal(t, 5, h.sessions.max)
+}
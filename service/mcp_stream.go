@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type JSONRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// eventStream upgrades a POST /mcp response to a text/event-stream the first
+// time a notification is sent, as described by the streamable HTTP transport.
+// Requests that never emit a notification are answered with plain JSON.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	enabled bool
+	started bool
+}
+
+func newEventStream(w http.ResponseWriter, r *http.Request) *eventStream {
+	return &eventStream{w: w, enabled: acceptsEventStream(r)}
+}
+
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
+
+// notify writes a JSON-RPC notification as an SSE event. It is a no-op when
+// the client did not advertise text/event-stream support.
+func (s *eventStream) notify(method string, params any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	_ = s.writeLocked(JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// request writes a server-to-client JSON-RPC request as an SSE event. It
+// reports false, without writing, when the client cannot receive one.
+func (s *eventStream) request(req JSONRPCRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return false
+	}
+	return s.writeLocked(req) == nil
+}
+
+// Started reports whether the response has been switched to an event stream.
+func (s *eventStream) Started() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started
+}
+
+// Send writes the final JSON-RPC response as the last event of the stream.
+func (s *eventStream) Send(v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeLocked(v)
+}
+
+func (s *eventStream) writeLocked(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+type eventStreamKey struct{}
+type progressTokenKey struct{}
+
+func withEventStream(ctx context.Context, s *eventStream) context.Context {
+	return context.WithValue(ctx, eventStreamKey{}, s)
+}
+
+func withProgressToken(ctx context.Context, token any) context.Context {
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressTokenKey{}, token)
+}
+
+// sendNotification emits a server-to-client notification on the request's
+// event stream, if there is one.
+func sendNotification(ctx context.Context, method string, params any) {
+	if s, ok := ctx.Value(eventStreamKey{}).(*eventStream); ok && s != nil {
+		s.notify(method, params)
+	}
+}
+
+// sendRequest writes a server-to-client request on the current request's
+// event stream, reporting whether it could be delivered.
+func sendRequest(ctx context.Context, req JSONRPCRequest) bool {
+	s, ok := ctx.Value(eventStreamKey{}).(*eventStream)
+	return ok && s != nil && s.request(req)
+}
+
+// reportProgress emits notifications/progress for the tool call in ctx. Tools
+// that may run for a while should call it as they make headway; it does
+// nothing unless the client supplied a progressToken in _meta.
+func reportProgress(ctx context.Context, progress, total float64, message string) {
+	token := ctx.Value(progressTokenKey{})
+	if token == nil {
+		return
+	}
+	params := map[string]any{"progressToken": token, "progress": progress}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" && featuresFrom(ctx).ProgressMessage {
+		params["message"] = message
+	}
+	sendNotification(ctx, "notifications/progress", params)
+}
+
+// progressTokenFromParams extracts params._meta.progressToken from a request.
+func progressTokenFromParams(params map[string]any) any {
+	meta, ok := params["_meta"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	switch token := meta["progressToken"].(type) {
+	case string, float64:
+		return token
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// readSSEMessages returns the JSON payload of every "data:" line in body.
+func readSSEMessages(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var msg map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg))
+		out = append(out, msg)
+	}
+	return out
+}
+
+func TestProgressTokenFromParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]any
+		want   any
+	}{
+		{name: "string token", params: map[string]any{"_meta": map[string]any{"progressToken": "abc"}}, want: "abc"},
+		{name: "numeric token", params: map[string]any{"_meta": map[string]any{"progressToken": float64(7)}}, want: float64(7)},
+		{name: "no meta", params: map[string]any{"name": "create_todo"}, want: nil},
+		{name: "invalid token type", params: map[string]any{"_meta": map[string]any{"progressToken": true}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, progressTokenFromParams(tt.params))
+		})
+	}
+}
+
+func TestReportProgress_StreamsNotifications(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	w := httptest.NewRecorder()
+
+	stream := newEventStream(w, req)
+	ctx := withProgressToken(withEventStream(context.Background(), stream), "tok-1")
+
+	reportProgress(ctx, 1, 3, "fetching")
+	reportProgress(ctx, 3, 3, "")
+	assert.True(t, stream.Started())
+	assert.NoError(t, stream.Send(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: map[string]any{}}))
+
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	msgs := readSSEMessages(t, w.Body.String())
+	if assert.Len(t, msgs, 3) {
+		assert.Equal(t, "notifications/progress", msgs[0]["method"])
+		params := msgs[0]["params"].(map[string]any)
+		assert.Equal(t, "tok-1", params["progressToken"])
+		assert.Equal(t, float64(1), params["progress"])
+		assert.Equal(t, float64(3), params["total"])
+		assert.Equal(t, "fetching", params["message"])
+		assert.NotContains(t, msgs[1]["params"].(map[string]any), "message")
+		assert.Equal(t, float64(1), msgs[2]["id"])
+	}
+}
+
+func TestReportProgress_NoopWithoutTokenOrStreamSupport(t *testing.T) {
+	t.Run("no progress token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Accept", "text/event-stream")
+		stream := newEventStream(httptest.NewRecorder(), req)
+
+		reportProgress(withEventStream(context.Background(), stream), 1, 2, "")
+		assert.False(t, stream.Started())
+	})
+
+	t.Run("client does not accept event streams", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Accept", "application/json")
+		stream := newEventStream(httptest.NewRecorder(), req)
+
+		ctx := withProgressToken(withEventStream(context.Background(), stream), "tok")
+		reportProgress(ctx, 1, 2, "")
+		assert.False(t, stream.Started())
+	})
+}
+
+func TestMCPHandlers_ToolCallWithoutNotificationsReturnsJSON(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.AnythingOfType("postgres.Todo")).Return(dao.Todo{UID: "todo-1"}, nil)
+
+	router := NewMCPRouter(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	reqBody, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "create_todo",
+			"arguments": map[string]any{"title": "Streamed"},
+			"_meta":     map[string]any{"progressToken": "tok"},
+		},
+	})
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	mockTodoDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_ToolCallStreamsProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, carbonaraPage)
+	}))
+	defer srv.Close()
+
+	url := srv.URL + "/carbonara"
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r1").Return(dao.Recipes{ID: "r1", Title: "Spaghetti Carbonara", ExternalURL: &url}, nil)
+	mockRecipesDAO.On("UpdateRecipes", mock.Anything, "r1", mock.Anything).Return(dao.Recipes{ID: "r1"}, nil)
+	mockPreferencesDAO := &MockPreferencesDAO{}
+	mockPreferencesDAO.On("SetPreferencesBulk", mock.Anything, mock.Anything).Return([]dao.Preferences{{Key: "diet"}, {Key: "units"}}, nil)
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, mockPreferencesDAO, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithRecipeRefresh(srv.Client()))
+
+	call := func(tool string, arguments map[string]any) []map[string]any {
+		reqBody, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params": map[string]any{
+				"name":      tool,
+				"arguments": arguments,
+				"_meta":     map[string]any{"progressToken": "tok-1"},
+			},
+		})
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"), tool)
+		return readSSEMessages(t, w.Body.String())
+	}
+	progress := func(msgs []map[string]any) (steps []any) {
+		for _, msg := range msgs[:len(msgs)-1] {
+			assert.Equal(t, "notifications/progress", msg["method"])
+			params := msg["params"].(map[string]any)
+			assert.Equal(t, "tok-1", params["progressToken"])
+			steps = append(steps, []any{params["progress"], params["total"], params["message"]})
+		}
+		last := msgs[len(msgs)-1]
+		assert.Equal(t, float64(1), last["id"])
+		assert.NotEqual(t, true, last["result"].(map[string]any)["isError"])
+		return steps
+	}
+
+	msgs := call("refresh_recipe", map[string]any{"recipe_id": "r1"})
+	assert.Equal(t, []any{
+		[]any{float64(0), float64(2), "Fetching " + url},
+		[]any{float64(1), float64(2), "Saving 6 changes"},
+		[]any{float64(2), float64(2), "Recipe updated"},
+	}, progress(msgs))
+
+	msgs = call("set_preferences_bulk", map[string]any{"preferences": []any{
+		map[string]any{"key": "diet", "specifier": "user-1", "data": "vegetarian"},
+		map[string]any{"key": "units", "specifier": "user-1", "data": "metric"},
+	}})
+	assert.Equal(t, []any{
+		[]any{float64(1), float64(3), "Checked diet"},
+		[]any{float64(2), float64(3), "Checked units"},
+		[]any{float64(3), float64(3), "Saved 2 preferences"},
+	}, progress(msgs))
+}
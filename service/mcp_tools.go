@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultToolsPageSize = 50
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// MCPOption configures optional MCPHandlers behaviour.
+type MCPOption func(*MCPHandlers)
+
+// WithAPIKeys authenticates MCP requests with API keys. Each key's scopes
+// decide which tools it can list and call. When required is false, requests
+// without a key keep access to every tool.
+func WithAPIKeys(keys apiKeyDAO, required bool) MCPOption {
+	return func(h *MCPHandlers) {
+		h.apiKeys = keys
+		h.requireAPIKey = required
+	}
+}
+
+// WithBackgroundDAO enables the set_background and get_background tools.
+func WithBackgroundDAO(backgrounds backgroundDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.backgroundDAO = backgrounds
+	}
+}
+
+// WithTodoTemplates enables the apply_template tool.
+func WithTodoTemplates(templates todoTemplateDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.templateDAO = templates
+	}
+}
+
+// WithPantry enables the pantry tools and lets build_shopping_list and
+// get_briefing use the household's pantry.
+func WithPantry(pantry pantryDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.pantryDAO = pantry
+	}
+}
+
+// WithGroceryPurchases enables the record_grocery_purchase and
+// grocery_spend_report tools.
+func WithGroceryPurchases(purchases groceryPurchaseDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.purchaseDAO = purchases
+	}
+}
+
+// WithAway enables the set_away tool and lets get_briefing say who is away.
+func WithAway(away awayDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.awayDAO = away
+	}
+}
+
+// WithHouseholdMembers lets get_briefing list the birthdays coming up in
+// the user's household.
+func WithHouseholdMembers(members membersDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.membersDAO = members
+	}
+}
+
+// WithImportantDates enables the add_important_date tool and lets
+// get_briefing list the household's important dates coming up.
+func WithImportantDates(dates importantDateDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.datesDAO = dates
+	}
+}
+
+// WithMyDay enables the My Day tools and puts the user's My Day first in
+// get_briefing.
+func WithMyDay(myDay myDayDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.myDayDAO = myDay
+	}
+}
+
+// WithProjects enables the tools that create projects and move todos into
+// them.
+func WithProjects(projects projectDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.projectDAO = projects
+	}
+}
+
+// WithTagger suggests tags for notes and recipes saved without any.
+func WithTagger(t Tagger) MCPOption {
+	return func(h *MCPHandlers) {
+		h.tagger = t
+	}
+}
+
+// WithGroceryClassifier sets how build_shopping_list and the pantry tools
+// categorize groceries, DefaultGroceryRules unless set.
+func WithGroceryClassifier(c GroceryClassifier) MCPOption {
+	return func(h *MCPHandlers) {
+		h.groceryClassifier = c
+	}
+}
+
+// WithToolsPageSize sets how many tools tools/list returns per page.
+func WithToolsPageSize(n int) MCPOption {
+	return func(h *MCPHandlers) {
+		if n > 0 {
+			h.toolsPageSize = n
+		}
+	}
+}
+
+func (h *MCPHandlers) findTool(name string) (mcp.Tool, bool) {
+	for _, tool := range h.tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return mcp.Tool{}, false
+}
+
+// toolAllowed reports whether the caller in ctx may see and call tool.
+// Unauthenticated callers (only possible when API keys are optional) are
+// allowed every tool.
+func toolAllowed(ctx context.Context, tool mcp.Tool) bool {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return true
+	}
+	if id.HasScope(ScopeMCPWrite) || id.HasScope(ScopeToolPrefix+tool.Name) {
+		return true
+	}
+	readOnly := tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+	return readOnly && id.HasScope(ScopeMCPRead)
+}
+
+// listTools returns the page of tools visible to the caller starting at cursor.
+func (h *MCPHandlers) listTools(ctx context.Context, cursor string) (mcp.ListToolsResult, error) {
+	var visible []mcp.Tool
+	for _, tool := range h.tools {
+		if toolAllowed(ctx, tool) && h.policy.permitsTool(ctx, tool.Name) && h.flags.permitsTool(ctx, tool.Name) {
+			visible = append(visible, tool)
+		}
+	}
+
+	offset, err := decodeToolsCursor(cursor)
+	if err != nil || offset > len(visible) {
+		return mcp.ListToolsResult{}, errInvalidCursor
+	}
+
+	end := min(offset+h.toolsPageSize, len(visible))
+	result := mcp.ListToolsResult{Tools: visible[offset:end]}
+	if result.Tools == nil {
+		result.Tools = []mcp.Tool{}
+	}
+	if end < len(visible) {
+		result.NextCursor = encodeToolsCursor(end)
+	}
+	return result, nil
+}
+
+func encodeToolsCursor(offset int) mcp.Cursor {
+	return mcp.Cursor(base64.RawURLEncoding.EncodeToString([]byte("tools:" + strconv.Itoa(offset))))
+}
+
+func decodeToolsCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := strings.CutPrefix(string(raw), "tools:")
+	if !ok {
+		return 0, errInvalidCursor
+	}
+	offset, err := strconv.Atoi(n)
+	if err != nil || offset < 0 {
+		return 0, errInvalidCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mcpCall(t *testing.T, router http.Handler, method string, params map[string]any, apiKey string) (int, map[string]any) {
+	t.Helper()
+	body := map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		body["params"] = params
+	}
+	reqBody, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+	req.Header.Set("MCP-Protocol-Version", LatestProtocolVersion)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp map[string]any
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+func toolNames(t *testing.T, resp map[string]any) []string {
+	t.Helper()
+	result, ok := resp["result"].(map[string]any)
+	if !assert.True(t, ok, "missing result in %v", resp) {
+		return nil
+	}
+	var names []string
+	for _, tool := range result["tools"].([]any) {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func TestMCPHandlers_ToolsListPagination(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithToolsPageSize(5))
+
+	var all []string
+	var cursor string
+	for page := 0; ; page++ {
+		var params map[string]any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		_, resp := mcpCall(t, router, "tools/list", params, "")
+		names := toolNames(t, resp)
+		assert.LessOrEqual(t, len(names), 5)
+		all = append(all, names...)
+
+		next, _ := resp["result"].(map[string]any)["nextCursor"].(string)
+		if next == "" {
+			break
+		}
+		cursor = next
+		if page > 10 {
+			t.Fatal("pagination did not terminate")
+		}
+	}
+	assert.Len(t, all, 32)
+	assert.Equal(t, "create_todo", all[0])
+	assert.Equal(t, "get_briefing", all[31])
+}
+
+func TestMCPHandlers_ToolsListInvalidCursor(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	for _, cursor := range []string{"not-base64!", string(encodeToolsCursor(99)), "Zm9vOjE"} {
+		_, resp := mcpCall(t, router, "tools/list", map[string]any{"cursor": cursor}, "")
+		errObj, ok := resp["error"].(map[string]any)
+		if assert.True(t, ok, "cursor %q should be rejected", cursor) {
+			assert.Equal(t, float64(-32602), errObj["code"])
+		}
+	}
+}
+
+func TestMCPHandlers_ToolsFilteredByAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   []string
+	}{
+		{
+			name:   "read-only key",
+			scopes: []string{ScopeMCPRead},
+			want:   []string{"list_todos", "time_report", "recall_note", "list_notes", "get_preference", "get_preferences_bulk", "find_recipes", "get_recipe", "convert_units", "build_shopping_list", "split_shopping_list", "get_briefing"},
+		},
+		{
+			name:   "single tool grant",
+			scopes: []string{ScopeMCPRead, ScopeToolPrefix + "create_todo"},
+			want:   []string{"create_todo", "list_todos", "time_report", "recall_note", "list_notes", "get_preference", "get_preferences_bulk", "find_recipes", "get_recipe", "convert_units", "build_shopping_list", "split_shopping_list", "get_briefing"},
+		},
+		{
+			name:   "tool grant only",
+			scopes: []string{ScopeToolPrefix + "save_note"},
+			want:   []string{"save_note"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := mocks.NewMockapiKeyDAO(t)
+			keys.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_test")).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", Scopes: tt.scopes}, nil)
+			keys.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+
+			router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+				WithAPIKeys(keys, true))
+
+			_, resp := mcpCall(t, router, "tools/list", nil, "ak_test")
+			assert.Equal(t, tt.want, toolNames(t, resp))
+		})
+	}
+}
+
+func TestMCPHandlers_ToolCallRejectedOutsideScopes(t *testing.T) {
+	keys := mocks.NewMockapiKeyDAO(t)
+	keys.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_read")).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", Scopes: []string{ScopeMCPRead}}, nil)
+	keys.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+
+	mockTodoDAO := &MockTodoDAO{}
+	router := NewMCPRouter(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithAPIKeys(keys, true))
+
+	_, resp := mcpCall(t, router, "tools/call", map[string]any{
+		"name":      "create_todo",
+		"arguments": map[string]any{"title": "Nope"},
+	}, "ak_read")
+
+	result := resp["result"].(map[string]any)
+	assert.Equal(t, true, result["isError"])
+	body := result["structuredContent"].(map[string]any)
+	assert.Equal(t, "Tool create_todo is not permitted for this API key", body["error"])
+	mockTodoDAO.AssertNotCalled(t, "CreateTodo", mock.Anything, mock.Anything)
+}
+
+func TestMCPHandlers_RequiresAPIKey(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithAPIKeys(mocks.NewMockapiKeyDAO(t), true))
+
+	code, _ := mcpCall(t, router, "tools/list", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
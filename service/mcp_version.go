@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Supported MCP protocol revisions, newest first.
+const (
+	ProtocolVersion20250618 = "2025-06-18"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20241105 = "2024-11-05"
+
+	LatestProtocolVersion = ProtocolVersion20250618
+
+	// defaultProtocolVersion is assumed for requests that omit the
+	// MCP-Protocol-Version header, as the 2025-06-18 spec requires.
+	defaultProtocolVersion = ProtocolVersion20250326
+
+	protocolVersionHeader = "MCP-Protocol-Version"
+)
+
+var SupportedProtocolVersions = []string{ProtocolVersion20250618, ProtocolVersion20250326, ProtocolVersion20241105}
+
+// UnsupportedProtocolVersionError is returned from initialize when the client
+// asks for a revision this server does not speak.
+type UnsupportedProtocolVersionError struct {
+	Requested string
+}
+
+func (e UnsupportedProtocolVersionError) Error() string {
+	return fmt.Sprintf("unsupported protocol version %q", e.Requested)
+}
+
+func negotiateProtocolVersion(requested string) (string, error) {
+	if !slices.Contains(SupportedProtocolVersions, requested) {
+		return "", UnsupportedProtocolVersionError{Requested: requested}
+	}
+	return requested, nil
+}
+
+// protocolVersionFromRequest returns the revision named in the
+// MCP-Protocol-Version header, falling back to defaultProtocolVersion.
+func protocolVersionFromRequest(r *http.Request) (string, error) {
+	v := r.Header.Get(protocolVersionHeader)
+	if v == "" {
+		return defaultProtocolVersion, nil
+	}
+	return negotiateProtocolVersion(v)
+}
+
+// protocolFeatures lists the parts of the protocol that differ between the
+// revisions we support.
+type protocolFeatures struct {
+	// Streaming allows POST responses to be upgraded to an event stream
+	// (streamable HTTP transport, 2025-03-26).
+	Streaming bool
+	// ToolAnnotations exposes readOnlyHint and friends in tools/list (2025-03-26).
+	ToolAnnotations bool
+	// ProgressMessage allows a message on notifications/progress (2025-03-26).
+	ProgressMessage bool
+	// StructuredOutput adds structuredContent to tool results (2025-06-18).
+	StructuredOutput bool
+	// Elicitation lets the server ask the user for input mid-call (2025-06-18).
+	Elicitation bool
+}
+
+func featuresFor(version string) protocolFeatures {
+	switch version {
+	case ProtocolVersion20241105:
+		return protocolFeatures{}
+	case ProtocolVersion20250326:
+		return protocolFeatures{Streaming: true, ToolAnnotations: true, ProgressMessage: true}
+	default:
+		return protocolFeatures{Streaming: true, ToolAnnotations: true, ProgressMessage: true, StructuredOutput: true, Elicitation: true}
+	}
+}
+
+type protocolVersionKey struct{}
+
+func withProtocolVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, protocolVersionKey{}, version)
+}
+
+// protocolVersionFrom returns the revision in use for ctx. Contexts that did
+// not come through ServeHTTP are treated as the latest revision.
+func protocolVersionFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(protocolVersionKey{}).(string); ok {
+		return v
+	}
+	return LatestProtocolVersion
+}
+
+func featuresFrom(ctx context.Context) protocolFeatures {
+	return featuresFor(protocolVersionFrom(ctx))
+}
+
+// legacyTool hides tool annotations from clients on revisions that predate
+// them.
+type legacyTool struct{ mcp.Tool }
+
+func (t legacyTool) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(t.Tool)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "annotations")
+	return json.Marshal(m)
+}
+
+// toolsListResult adapts a tools/list result to the negotiated revision.
+func toolsListResult(ctx context.Context, result mcp.ListToolsResult) any {
+	if featuresFrom(ctx).ToolAnnotations {
+		return result
+	}
+	tools := make([]legacyTool, len(result.Tools))
+	for i, tool := range result.Tools {
+		tools[i] = legacyTool{tool}
+	}
+	return struct {
+		Tools      []legacyTool `json:"tools"`
+		NextCursor mcp.Cursor   `json:"nextCursor,omitempty"`
+	}{tools, result.NextCursor}
+}
+
+// toolCallResultFor adapts a tools/call result to the negotiated revision.
+func toolCallResultFor(ctx context.Context, result mcp.CallToolResult) any {
+	if featuresFrom(ctx).StructuredOutput {
+		return toolCallResult{result}
+	}
+	return result
+}
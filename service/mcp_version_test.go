@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func postMCP(router http.Handler, body map[string]any, headers map[string]string) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMCPHandlers_InitializeNegotiatesProtocolVersion(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	for _, version := range SupportedProtocolVersions {
+		t.Run(version, func(t *testing.T) {
+			w := postMCP(router, map[string]any{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "initialize",
+				"params": map[string]any{
+					"protocolVersion": version,
+					"capabilities":    map[string]any{},
+					"clientInfo":      map[string]any{"name": "TestClient", "version": "1.0.0"},
+				},
+			}, nil)
+
+			var response map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			result := response["result"].(map[string]any)
+			assert.Equal(t, version, result["protocolVersion"])
+		})
+	}
+}
+
+func TestMCPHandlers_InitializeRejectsUnsupportedVersion(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	for _, version := range []string{"1999-01-01", ""} {
+		w := postMCP(router, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "initialize",
+			"params": map[string]any{
+				"protocolVersion": version,
+				"clientInfo":      map[string]any{"name": "OldClient", "version": "0.1.0"},
+			},
+		}, nil)
+
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Nil(t, response["result"])
+		errObj := response["error"].(map[string]any)
+		assert.Equal(t, float64(-32602), errObj["code"])
+		assert.Equal(t, "Unsupported protocol version", errObj["message"])
+		data := errObj["data"].(map[string]any)
+		assert.Equal(t, version, data["requested"])
+		assert.Len(t, data["supported"], len(SupportedProtocolVersions))
+	}
+}
+
+func TestMCPHandlers_RejectsUnsupportedProtocolHeader(t *testing.T) {
+	router := NewMCPRouter(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	w := postMCP(router, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"},
+		map[string]string{"MCP-Protocol-Version": "1999-01-01"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMCPHandlers_LegacyProtocolFeatures(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.AnythingOfType("postgres.Todo")).Return(dao.Todo{UID: "todo-1"}, nil)
+	router := NewMCPRouter(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	headers := map[string]string{
+		"MCP-Protocol-Version": ProtocolVersion20241105,
+		"Accept":               "application/json, text/event-stream",
+	}
+
+	t.Run("tools/list omits annotations", func(t *testing.T) {
+		w := postMCP(router, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}, headers)
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		tools := response["result"].(map[string]any)["tools"].([]any)
+		assert.Len(t, tools, 32)
+		assert.NotContains(t, tools[0].(map[string]any), "annotations")
+		assert.Contains(t, tools[0].(map[string]any), "inputSchema")
+	})
+
+	t.Run("tools/call omits structuredContent", func(t *testing.T) {
+		w := postMCP(router, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params": map[string]any{
+				"name":      "create_todo",
+				"arguments": map[string]any{"title": "Legacy"},
+				"_meta":     map[string]any{"progressToken": "tok"},
+			},
+		}, headers)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		result := response["result"].(map[string]any)
+		assert.NotContains(t, result, "structuredContent")
+		assert.Len(t, result["content"], 1)
+	})
+
+	t.Run("latest protocol keeps annotations", func(t *testing.T) {
+		w := postMCP(router, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"},
+			map[string]string{"MCP-Protocol-Version": LatestProtocolVersion})
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		tools := response["result"].(map[string]any)["tools"].([]any)
+		assert.Contains(t, tools[0].(map[string]any), "annotations")
+	})
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// MeResponse is the caller: their user and what their API key grants.
+type MeResponse struct {
+	User   userResponse `json:"user"`
+	Scopes []string     `json:"scopes"`
+}
+
+type MeHandlers struct {
+	users userDAO
+	todos http.Handler
+	notes http.Handler
+	prefs http.Handler
+	usage apiUsageDAO
+}
+
+type MeOption func(*MeHandlers)
+
+// NewMe serves the caller's own user, todos, notes and preferences at /,
+// /todos, /notes and /preferences, resolving their UID from the API key
+// rather than the URL. PATCH / updates their profile. The lists take the same parameters as /todos,
+// /notes and /preferences, except that they are always the caller's.
+func NewMe(users userDAO, todos todoDAO, notes notesDAO, prefs preferencesDAO, opts ...MeOption) http.Handler {
+	h := &MeHandlers{users: users, todos: NewTodos(todos), notes: NewNotes(notes), prefs: NewPreferences(prefs)}
+	for _, opt := range opts {
+		opt(h)
+	}
+	r := chi.NewRouter()
+	r.Use(requireIdentity)
+	// The lists log through their own routers.
+	r.With(httpLogger()).Get("/", h.get)
+	r.With(httpLogger()).Patch("/", h.update)
+	r.Get("/todos", callersOwn(h.todos, "user_uid"))
+	r.Get("/notes", callersOwn(h.notes, "user_uid"))
+	r.Get("/preferences", callersOwn(h.prefs, "specifier"))
+	if h.usage != nil {
+		r.With(httpLogger()).Get("/usage", h.myUsage)
+	}
+	return r
+}
+
+// requireIdentity rejects requests that didn't authenticate with an API
+// key; there is no "me" without one.
+func requireIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := IdentityFromContext(r.Context()); !ok || id.UserUID == "" {
+			http.Error(w, "Missing API key", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *MeHandlers) get(w http.ResponseWriter, r *http.Request) {
+	id, _ := IdentityFromContext(r.Context())
+	user, err := h.users.GetUser(r.Context(), id.UserUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, MeResponse{User: withAvatarURLs(user), Scopes: id.Scopes})
+}
+
+// callersOwn serves list's collection with column, whatever the request
+// asked for, set to the caller's UID.
+func callersOwn(list http.Handler, column string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := IdentityFromContext(r.Context())
+		query := r.URL.Query()
+		query.Set(column, id.UserUID)
+
+		// list routes the request afresh, from its root.
+		req := r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+		req.URL.Path, req.URL.RawPath, req.URL.RawQuery = "/", "", query.Encode()
+		list.ServeHTTP(w, req)
+	}
+}
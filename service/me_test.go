@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeHandlers(t *testing.T) {
+	users := &MockUserDAO{}
+	users.On("GetUser", mock.Anything, "user-1").Return(dao.Users{UID: "user-1", Name: "Ada"}, nil)
+	users.On("GetUser", mock.Anything, "user-gone").Return(dao.Users{}, pgx.ErrNoRows)
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{"user-1"}, o.WhereArgs) && o.Limit == 5
+	})).Return([]dao.Todo{{UID: "todo-1", Title: "Bins out"}}, nil)
+	notes := &MockNotesDAO{}
+	notes.On("ListNotes", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return len(o.WhereArgs) > 0 && o.WhereArgs[0] == "user-1"
+	})).Return([]dao.Notes{{ID: "note-1", Key: "wifi"}}, nil)
+	prefs := &MockPreferencesDAO{}
+	prefs.On("ListPreferences", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{"user-1"}, o.WhereArgs)
+	})).Return([]dao.Preferences{{Key: "diet", Specifier: "user-1", Data: "vegetarian"}}, nil)
+
+	// Mounted as the server mounts it, so the lists are routed from under
+	// /me.
+	r := chi.NewRouter()
+	r.Mount("/me", NewMe(users, todos, notes, prefs))
+	serve := func(userUID, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if userUID != "" {
+			req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: userUID, Scopes: []string{ScopeMCPRead}}))
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("user-1", "/me")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"uid":"user-1"`)
+	assert.Contains(t, rec.Body.String(), `"scopes":["mcp:read"]`)
+
+	// Asking for someone else's todos still lists the caller's.
+	rec = serve("user-1", "/me/todos?user_uid=user-2&limit=5")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Bins out")
+
+	rec = serve("user-1", "/me/notes")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "wifi")
+
+	rec = serve("user-1", "/me/preferences")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "vegetarian")
+
+	assert.Equal(t, http.StatusUnauthorized, serve("", "/me/todos").Code)
+	assert.Equal(t, http.StatusNotFound, serve("user-gone", "/me").Code)
+	// Only the lists are served; changes go through their own collections.
+	assert.Equal(t, http.StatusNotFound, serve("user-1", "/me/todos/todo-1").Code)
+}
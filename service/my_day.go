@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// myDayDAO keeps each user's My Day: the todos they mean to focus on today,
+// apart from their due dates.
+type myDayDAO interface {
+	GetTodo(ctx context.Context, uid string) (dao.Todo, error)
+	AddToMyDay(ctx context.Context, userUID, todoUID string, expiresAt time.Time) error
+	RemoveFromMyDay(ctx context.Context, userUID, todoUID string) error
+	ListMyDay(ctx context.Context, userUID string) ([]dao.Todo, error)
+}
+
+// myDayLocation is where a user's day ends: timezone when given, else the
+// timezone of their quiet hours, else UTC.
+func myDayLocation(ctx context.Context, prefs preferencesDAO, userUID, timezone string) (*time.Location, error) {
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", timezone)
+		}
+		return loc, nil
+	}
+	if p, err := loadNotificationPreferences(ctx, prefs, userUID); err == nil && p.QuietHours != nil {
+		if loc, err := time.LoadLocation(p.QuietHours.Timezone); err == nil {
+			return loc, nil
+		}
+	}
+	return time.UTC, nil
+}
+
+// myDayExpiry is the first midnight in loc after now, when My Day clears.
+func myDayExpiry(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+}
+
+// addToMyDay puts a todo on a user's My Day until the next midnight in loc
+// and returns it with that expiry.
+func addToMyDay(ctx context.Context, d myDayDAO, loc *time.Location, userUID, todoUID string) (dao.Todo, time.Time, error) {
+	expiresAt := myDayExpiry(time.Now(), loc)
+	if err := d.AddToMyDay(ctx, userUID, todoUID, expiresAt); err != nil {
+		return dao.Todo{}, time.Time{}, err
+	}
+	todo, err := d.GetTodo(ctx, todoUID)
+	return todo, expiresAt, err
+}
+
+// myDayFirst puts the open todos on My Day ahead of the rest of todos,
+// without listing any twice.
+func myDayFirst(todos, myDay []dao.Todo) []dao.Todo {
+	out := []dao.Todo{}
+	seen := map[string]bool{}
+	for _, t := range myDay {
+		if t.MarkedComplete == nil {
+			out = append(out, t)
+			seen[t.UID] = true
+		}
+	}
+	for _, t := range todos {
+		if !seen[t.UID] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+type MyDayHandlers struct {
+	dao   myDayDAO
+	prefs preferencesDAO
+}
+
+func NewMyDay(dao myDayDAO, prefs preferencesDAO) http.Handler {
+	h := &MyDayHandlers{dao, prefs}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/{user_uid}", h.list)
+	r.Put("/{user_uid}/{todo_uid}", h.add)
+	r.Delete("/{user_uid}/{todo_uid}", h.remove)
+	return r
+}
+
+func (h *MyDayHandlers) list(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.ListMyDay(r.Context(), chi.URLParam(r, "user_uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// add puts a todo on the user's My Day. The body may give the timezone
+// whose midnight clears it.
+func (h *MyDayHandlers) add(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Timezone string `json:"timezone"`
+	}
+	if r.ContentLength != 0 && json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	userUID := chi.URLParam(r, "user_uid")
+	loc, err := myDayLocation(r.Context(), h.prefs, userUID, in.Timezone)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	todo, expiresAt, err := addToMyDay(r.Context(), h.dao, loc, userUID, chi.URLParam(r, "todo_uid"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"todo": todo, "expires_at": expiresAt})
+}
+
+func (h *MyDayHandlers) remove(w http.ResponseWriter, r *http.Request) {
+	if h.dao.RemoveFromMyDay(r.Context(), chi.URLParam(r, "user_uid"), chi.URLParam(r, "todo_uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
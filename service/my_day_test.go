@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMyDayExpiry(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// 23:30 UTC is already tomorrow in London during summer time.
+	now := time.Date(2025, 8, 18, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 8, 19, 0, 0, 0, 0, time.UTC), myDayExpiry(now, time.UTC))
+	assert.Equal(t, time.Date(2025, 8, 20, 0, 0, 0, 0, london), myDayExpiry(now, london))
+}
+
+func TestMyDayLocation(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "user-1").
+		Return(postgres.Preferences{Data: `{"quiet_hours": {"start": "22:00", "end": "07:00", "timezone": "America/New_York"}}`}, nil)
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "user-2").Return(postgres.Preferences{}, errors.New("not found"))
+
+	loc, err := myDayLocation(t.Context(), prefs, "user-1", "Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+	loc, _ = myDayLocation(t.Context(), prefs, "user-1", "")
+	assert.Equal(t, "America/New_York", loc.String())
+	loc, _ = myDayLocation(t.Context(), prefs, "user-2", "")
+	assert.Equal(t, time.UTC, loc)
+	_, err = myDayLocation(t.Context(), prefs, "user-2", "Mars/Olympus")
+	assert.EqualError(t, err, `unknown timezone "Mars/Olympus"`)
+}
+
+func TestMyDayFirst(t *testing.T) {
+	done := time.Now()
+	todos := []postgres.Todo{{UID: "t1"}, {UID: "t2"}, {UID: "t3"}}
+	myDay := []postgres.Todo{{UID: "t3"}, {UID: "t9"}, {UID: "t1", MarkedComplete: &done}}
+
+	var uids []string
+	for _, t := range myDayFirst(todos, myDay) {
+		uids = append(uids, t.UID)
+	}
+	assert.Equal(t, []string{"t3", "t9", "t1", "t2"}, uids)
+}
+
+func TestMyDayHandlers(t *testing.T) {
+	mockDAO := mocks.NewMockmyDayDAO(t)
+	mockDAO.On("AddToMyDay", mock.Anything, "user-1", "t1", myDayExpiry(time.Now(), time.UTC)).Return(nil)
+	mockDAO.On("AddToMyDay", mock.Anything, "user-1", "missing", mock.Anything).Return(pgx.ErrNoRows)
+	mockDAO.On("GetTodo", mock.Anything, "t1").Return(postgres.Todo{UID: "t1", Title: "Call the plumber"}, nil)
+	mockDAO.On("ListMyDay", mock.Anything, "user-1").Return([]postgres.Todo{{UID: "t1"}}, nil)
+	mockDAO.On("RemoveFromMyDay", mock.Anything, "user-1", "t1").Return(nil)
+	handler := NewMyDay(mockDAO, &MockPreferencesDAO{})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/user-1/t1", strings.NewReader(`{"timezone": "UTC"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"title":"Call the plumber"`)
+	assert.Contains(t, rr.Body.String(), `"expires_at"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/user-1/t1", strings.NewReader(`{"timezone": "Nowhere"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown timezone")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/user-1/missing", strings.NewReader(`{"timezone": "UTC"}`)))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/user-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"t1"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/user-1/t1", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestMCPHandlers_MyDay(t *testing.T) {
+	mockDAO := mocks.NewMockmyDayDAO(t)
+	mockDAO.On("AddToMyDay", mock.Anything, "user-1", "t1", mock.AnythingOfType("time.Time")).Return(nil)
+	mockDAO.On("GetTodo", mock.Anything, "t1").Return(postgres.Todo{UID: "t1", Title: "Call the plumber"}, nil)
+	mockDAO.On("ListMyDay", mock.Anything, "user-1").Return([]postgres.Todo{{UID: "t1"}}, nil)
+	mockDAO.On("RemoveFromMyDay", mock.Anything, "user-1", "t1").Return(nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithMyDay(mockDAO))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "add_to_my_day", map[string]any{"todo_id": "t1", "timezone": "Europe/London"}), &body)
+	assert.Equal(t, "t1", body["todo"].(map[string]any)["uid"])
+	assert.True(t, strings.HasPrefix(body["summary"].(string), "Added Call the plumber to My Day until "))
+
+	decodeToolResult(t, h.callTool(ctx, "get_my_day", map[string]any{}), &body)
+	assert.Len(t, body["todos"], 1)
+
+	decodeToolResult(t, h.callTool(ctx, "remove_from_my_day", map[string]any{"todo_id": "t1"}), &body)
+	assert.Equal(t, "t1", body["todo_id"])
+
+	assert.True(t, h.callTool(ctx, "add_to_my_day", map[string]any{"todo_id": "t1", "timezone": "Nowhere"}).IsError)
+	assert.True(t, h.callTool(ctx, "add_to_my_day", map[string]any{}).IsError)
+
+	withoutMyDay := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutMyDay.findTool("get_my_day")
+	assert.False(t, ok)
+}
+
+func TestMCPHandlers_GetBriefingMyDay(t *testing.T) {
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "user-1").Return(postgres.Users{UID: "user-1", Name: "Sam"}, nil)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{{UID: "t1"}, {UID: "t2"}}, nil)
+	mockDAO := mocks.NewMockmyDayDAO(t)
+	mockDAO.On("ListMyDay", mock.Anything, "user-1").Return([]postgres.Todo{{UID: "t2"}}, nil)
+
+	h := NewMCP(mockTodoDAO, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, &MockHouseholdDAO{}, WithMyDay(mockDAO))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", ""), "get_briefing", map[string]any{}), &body)
+	require.Len(t, body["my_day"], 1)
+	require.Len(t, body["todos"], 2)
+	assert.Equal(t, "t2", body["todos"].([]any)[0].(map[string]any)["uid"])
+	assert.Equal(t, "t1", body["todos"].([]any)[1].(map[string]any)["uid"])
+}
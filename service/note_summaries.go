@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/llm"
+)
+
+const (
+	// DigestNoteTag marks the notes NoteSummaries writes.
+	DigestNoteTag = "digest"
+	// noteSummaryBatch caps how many old notes are read per run; the rest
+	// wait for the next run.
+	noteSummaryBatch = 200
+	// noteSummaryMinNotes is the fewest notes worth condensing into one.
+	noteSummaryMinNotes = 3
+)
+
+type noteSummaryDAO interface {
+	CreateNotes(ctx context.Context, n dao.Notes) (dao.Notes, error)
+	ListNotes(ctx context.Context, options dao.ListOptions) ([]dao.Notes, error)
+	ArchiveNotes(ctx context.Context, ids []string) error
+}
+
+// NoteSummaries condenses notes that haven't changed in a while into digest
+// notes and archives the originals, so what bootstrap hands the assistant
+// stays current instead of growing forever. Pinned and shared-link notes
+// are left alone, and notes are only condensed with others of the same
+// owner and visibility. Digest notes age like any other and are condensed
+// again in time.
+type NoteSummaries struct {
+	notes      noteSummaryDAO
+	summarizer llm.Summarizer
+	maxAge     time.Duration
+	interval   time.Duration
+}
+
+// NewNoteSummaries condenses notes last updated more than maxAge ago,
+// checking every interval.
+func NewNoteSummaries(notes noteSummaryDAO, summarizer llm.Summarizer, maxAge, interval time.Duration) *NoteSummaries {
+	return &NoteSummaries{notes: notes, summarizer: summarizer, maxAge: maxAge, interval: interval}
+}
+
+// Job condenses old notes every interval. Notes condensed by a failed run
+// are archived, so retrying it only picks up the rest.
+func (s *NoteSummaries) Job() Job {
+	return Job{
+		Name:        "note_summaries",
+		Schedule:    Every(s.interval),
+		MaxAttempts: 3,
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return s.SummarizeDue(ctx, run.RunAt)
+		},
+	}
+}
+
+// noteOwner is who can see a note; only notes with the same owner are
+// condensed together.
+type noteOwner struct {
+	userUID, householdUID, visibility string
+}
+
+// SummarizeDue condenses the notes last updated before now minus maxAge.
+func (s *NoteSummaries) SummarizeDue(ctx context.Context, now time.Time) error {
+	return s.SummarizeBefore(ctx, "", now.Add(-s.maxAge))
+}
+
+// SummarizeBefore condenses the notes of a household, or of every household
+// when householdUID is empty, last updated before before. Failures for one
+// owner are logged and don't stop the others; SummarizeBefore then reports
+// how many failed.
+func (s *NoteSummaries) SummarizeBefore(ctx context.Context, householdUID string, before time.Time) error {
+	where := "WHERE NOT pinned AND archived_at IS NULL AND visibility <> $1 AND updated_at < $2"
+	args := []any{dao.NoteVisibilitySharedLink, before}
+	if householdUID != "" {
+		where += " AND household_uid = $3"
+		args = append(args, householdUID)
+	}
+	old, err := s.notes.ListNotes(ctx, dao.ListOptions{
+		Limit:       noteSummaryBatch,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: where,
+		WhereArgs:   args,
+	})
+	if err != nil {
+		return fmt.Errorf("listing notes to summarize: %w", err)
+	}
+
+	var owners []noteOwner
+	groups := map[noteOwner][]dao.Notes{}
+	for _, n := range old {
+		o := noteOwner{visibility: n.Visibility}
+		if n.UserUID != nil {
+			o.userUID = *n.UserUID
+		}
+		if n.HouseholdUID != nil {
+			o.householdUID = *n.HouseholdUID
+		}
+		if _, ok := groups[o]; !ok {
+			owners = append(owners, o)
+		}
+		groups[o] = append(groups[o], n)
+	}
+	failed := 0
+	for _, o := range owners {
+		if len(groups[o]) < noteSummaryMinNotes {
+			continue
+		}
+		if err := s.summarize(ctx, o, groups[o]); err != nil {
+			slog.Error("Failed to summarize notes", "user_uid", o.userUID, "household_uid", o.householdUID, "error", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("summarizing the notes of %d owners failed", failed)
+	}
+	return nil
+}
+
+// summarize writes one digest note for notes, oldest first, and archives
+// them. The digest keeps every tag of the notes it replaces.
+func (s *NoteSummaries) summarize(ctx context.Context, o noteOwner, notes []dao.Notes) error {
+	docs := make([]llm.Document, len(notes))
+	ids := make([]string, len(notes))
+	tags := []string{DigestNoteTag}
+	for i, n := range notes {
+		docs[i] = llm.Document{Title: n.Key, Text: n.Data}
+		ids[i] = n.ID
+		for _, tag := range n.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	summary, err := s.summarizer.Summarize(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	digest := dao.Notes{
+		Key: fmt.Sprintf("Digest of %d notes from %s to %s", len(notes),
+			notes[0].CreatedAt.Format(time.DateOnly), notes[len(notes)-1].CreatedAt.Format(time.DateOnly)),
+		Data:       summary,
+		Tags:       tags,
+		Visibility: o.visibility,
+	}
+	if o.userUID != "" {
+		digest.UserUID = &o.userUID
+	}
+	if o.householdUID != "" {
+		digest.HouseholdUID = &o.householdUID
+	}
+	created, err := s.notes.CreateNotes(ctx, digest)
+	if err != nil {
+		return err
+	}
+	if err := s.notes.ArchiveNotes(ctx, ids); err != nil {
+		return err
+	}
+	slog.Info("Summarized notes", "digest_note_id", created.ID, "notes", len(ids))
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/llm"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSummarizer joins the notes' texts, failing when a note says "fail".
+type fakeSummarizer struct{ calls [][]llm.Document }
+
+func (f *fakeSummarizer) Summarize(_ context.Context, docs []llm.Document) (string, error) {
+	f.calls = append(f.calls, docs)
+	out := ""
+	for _, d := range docs {
+		if d.Text == "fail" {
+			return "", errors.New("model unavailable")
+		}
+		out += d.Text + ";"
+	}
+	return out, nil
+}
+
+func TestNoteSummariesSummarizeDue(t *testing.T) {
+	now := time.Date(2025, 8, 18, 3, 0, 0, 0, time.UTC)
+	house, mia, sam := "house-1", "user-1", "user-2"
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 9, 0, 0, 0, time.UTC) }
+	note := func(id string, user *string, visibility, data string, created time.Time, tags ...string) postgres.Notes {
+		return postgres.Notes{ID: id, Key: "k-" + id, UserUID: user, HouseholdUID: &house, Visibility: visibility, Data: data, CreatedAt: created, Tags: tags}
+	}
+
+	notes := mocks.NewMocknoteSummaryDAO(t)
+	notes.On("ListNotes", mock.Anything, postgres.ListOptions{
+		Limit:       noteSummaryBatch,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: "WHERE NOT pinned AND archived_at IS NULL AND visibility <> $1 AND updated_at < $2",
+		WhereArgs:   []any{postgres.NoteVisibilitySharedLink, now.Add(-90 * 24 * time.Hour)},
+	}).Return([]postgres.Notes{
+		note("n1", &mia, postgres.NoteVisibilityHousehold, "wifi is hunter2", day(1), "home"),
+		note("n2", &mia, postgres.NoteVisibilityPrivate, "gift idea: kite", day(2)),
+		note("n3", &mia, postgres.NoteVisibilityHousehold, "bins on tuesday", day(3), "home", "chores"),
+		note("n4", &mia, postgres.NoteVisibilityHousehold, "plumber is Ana", day(4)),
+		note("n5", &sam, postgres.NoteVisibilityHousehold, "fail", day(5)),
+		note("n6", &sam, postgres.NoteVisibilityHousehold, "b", day(6)),
+		note("n7", &sam, postgres.NoteVisibilityHousehold, "c", day(7)),
+	}, nil)
+	notes.On("CreateNotes", mock.Anything, postgres.Notes{
+		Key:          "Digest of 3 notes from 2025-05-01 to 2025-05-04",
+		UserUID:      &mia,
+		HouseholdUID: &house,
+		Data:         "wifi is hunter2;bins on tuesday;plumber is Ana;",
+		Tags:         []string{DigestNoteTag, "home", "chores"},
+		Visibility:   postgres.NoteVisibilityHousehold,
+	}).Return(postgres.Notes{ID: "d1"}, nil)
+	notes.On("ArchiveNotes", mock.Anything, []string{"n1", "n3", "n4"}).Return(nil)
+
+	summarizer := &fakeSummarizer{}
+	NewNoteSummaries(notes, summarizer, 90*24*time.Hour, time.Hour).SummarizeDue(t.Context(), now)
+
+	// Mia's lone private note is left alone, and Sam's notes stay put when
+	// the summarizer fails.
+	require.Len(t, summarizer.calls, 2)
+	assert.Equal(t, llm.Document{Title: "k-n1", Text: "wifi is hunter2"}, summarizer.calls[0][0])
+	notes.AssertNumberOfCalls(t, "ArchiveNotes", 1)
+}
+
+func TestMCPHandlers_ListNotesHidesArchived(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE archived_at IS NULL"
+	})).Return([]postgres.Notes{}, nil).Once()
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == ""
+	})).Return([]postgres.Notes{{ID: "old"}}, nil).Once()
+	h := &MCPHandlers{notesDAO: mockNotesDAO}
+
+	var body map[string]any
+	decodeToolResult(t, h.handleListNotes(context.Background(), map[string]any{}), &body)
+	assert.Equal(t, float64(0), body["count"])
+	decodeToolResult(t, h.handleListNotes(context.Background(), map[string]any{"include_archived": true}), &body)
+	assert.Equal(t, float64(1), body["count"])
+	mockNotesDAO.AssertExpectations(t)
+}
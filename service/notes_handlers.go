@@ -3,11 +3,16 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/markdown"
 )
 
 type notesDAO interface {
@@ -15,52 +20,140 @@ type notesDAO interface {
 	GetNotes(ctx context.Context, id string) (dao.Notes, error)
 	ListNotes(ctx context.Context, options dao.ListOptions) ([]dao.Notes, error)
 	UpdateNotes(ctx context.Context, id string, n dao.Notes) (dao.Notes, error)
+	SetNotePinned(ctx context.Context, id string, pinned bool, sortOrder int) (dao.Notes, error)
+	SetNoteReminder(ctx context.Context, id string, remindAt *time.Time) (dao.Notes, error)
 	DeleteNotes(ctx context.Context, id string) error
 }
 
-type NotesHandlers struct{ dao notesDAO }
+type NotesHandlers struct {
+	dao          notesDAO
+	signer       *noteSigner
+	shareBaseURL string
+	tagger       Tagger
+	extraction   *todoExtraction
+	schemas      dataSchemaDAO
+	locks        entityLockDAO
+}
 
-func NewNotes(dao notesDAO) http.Handler {
-	h := &NotesHandlers{dao}
+// NewNotes serves the notes API. When the request carries an API key
+// identity, notes are filtered by their visibility.
+func NewNotes(dao notesDAO, opts ...NotesOption) http.Handler {
+	h := &NotesHandlers{dao: dao}
+	for _, opt := range opts {
+		opt(h)
+	}
 	r := chi.NewRouter()
 	r.Post("/", h.create)
 	r.Get("/{id}", h.get)
+	r.Get("/{id}/rendered", h.rendered)
 	r.Put("/{id}", h.update)
 	r.Delete("/{id}", h.delete)
 	r.Get("/", h.list)
+	r.Put("/{id}/pin", h.pin)
+	r.Delete("/{id}/pin", h.unpin)
+	r.Post("/{id}/duplicate", h.duplicate)
+	if h.signer != nil {
+		r.Post("/{id}/share", h.share)
+	}
+	if h.extraction != nil {
+		r.Post("/{id}/extract-todos", h.extractTodos)
+	}
+	if h.locks != nil {
+		r.Put("/{id}/lock", h.lock)
+		r.Delete("/{id}/lock", h.unlock)
+	}
 	return r
 }
 
 func (h *NotesHandlers) create(w http.ResponseWriter, r *http.Request) {
 	var n dao.Notes
-	if json.NewDecoder(r.Body).Decode(&n) != nil {
+	if json.NewDecoder(r.Body).Decode(&n) != nil || (n.Visibility != "" && !validNoteVisibility(n.Visibility)) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !checkData(w, r, h.schemas, dao.DataSchemaNotes, n.Key, n.Data) {
+		return
+	}
 	n.ID = uuid.NewString()
+	suggested := suggestTags(r.Context(), h.tagger, n.Tags, n.Key+"\n"+n.Data)
+	if autoTagRequested(r) {
+		n.Tags = append(n.Tags, suggested...)
+	}
 	out, err := h.dao.CreateNotes(r.Context(), n)
+	if quotaExceeded(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	_ = json.NewEncoder(w).Encode(struct {
+		dao.Notes
+		SuggestedTags []string `json:"suggested_tags,omitempty"`
+	}{out, suggested})
 }
 
 func (h *NotesHandlers) get(w http.ResponseWriter, r *http.Request) {
-	out, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	id := chi.URLParam(r, "id")
+	// With ?fields= the visibility check has to happen in the query, as the
+	// columns it needs may not be selected.
+	where, args := withNoteVisibility(r.Context(), "WHERE id = $1", []interface{}{id})
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Notes, error) {
+		n, err := h.dao.GetNotes(ctx, id)
+		if err == nil && !noteAccessible(ctx, n) {
+			return n, errNoRow
+		}
+		return n, err
+	}, h.dao.ListNotes, where, args...)
 	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	if l := currentLock(r.Context(), h.locks, lockEntityNotes, id); l != nil {
+		encodeResponse(w, r, struct {
+			dao.Notes
+			Lock *dao.EntityLock `json:"lock"`
+		}{out, l})
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// rendered serves a note's data as an HTML fragment for email digests and
+// dashboards to embed. The markdown is rendered and sanitized here, so the
+// fragment can go into a page as it is.
+func (h *NotesHandlers) rendered(w http.ResponseWriter, r *http.Request) {
+	n, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !noteAccessible(r.Context(), n)) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	_, _ = io.WriteString(w, markdown.Render(n.Data))
 }
 
 func (h *NotesHandlers) update(w http.ResponseWriter, r *http.Request) {
 	var n dao.Notes
-	if json.NewDecoder(r.Body).Decode(&n) != nil {
+	if json.NewDecoder(r.Body).Decode(&n) != nil || (n.Visibility != "" && !validNoteVisibility(n.Visibility)) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !h.callerCanAccess(r) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if h.refuseLocked(w, r) {
+		return
+	}
+	if !checkData(w, r, h.schemas, dao.DataSchemaNotes, n.Key, n.Data) {
+		return
+	}
 	out, err := h.dao.UpdateNotes(r.Context(), chi.URLParam(r, "id"), n)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -70,6 +163,13 @@ func (h *NotesHandlers) update(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *NotesHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if !h.callerCanAccess(r) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if h.refuseLocked(w, r) {
+		return
+	}
 	if h.dao.DeleteNotes(r.Context(), chi.URLParam(r, "id")) != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -79,7 +179,11 @@ func (h *NotesHandlers) delete(w http.ResponseWriter, r *http.Request) {
 
 func (h *NotesHandlers) list(w http.ResponseWriter, r *http.Request) {
 	params := ParseListParams(r, NotesFilters.SortFields)
-	whereClause, whereArgs := BuildWhereClause(params.Filters, NotesFilters.Filters)
+	whereClause, whereArgs, ok := whereFromParams(w, params, NotesFilters.Filters)
+	if !ok {
+		return
+	}
+	whereClause, whereArgs = withNoteVisibility(r.Context(), whereClause, whereArgs)
 
 	options := dao.ListOptions{
 		Limit:       params.Limit,
@@ -88,12 +192,23 @@ func (h *NotesHandlers) list(w http.ResponseWriter, r *http.Request) {
 		SortDir:     params.SortDir,
 		WhereClause: whereClause,
 		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
 	}
 
 	out, err := h.dao.ListNotes(r.Context(), options)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeFieldsError(w, err, http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, out)
+}
+
+// callerCanAccess checks the note named in the URL against the caller's
+// identity before it is changed. Unauthenticated requests skip the lookup.
+func (h *NotesHandlers) callerCanAccess(r *http.Request) bool {
+	if _, ok := IdentityFromContext(r.Context()); !ok {
+		return true
+	}
+	note, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	return err == nil && noteAccessible(r.Context(), note)
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/pbdeuchler/assistant-server/dao/postgres"
 	"github.com/pbdeuchler/assistant-server/mocks"
 	"github.com/stretchr/testify/mock"
@@ -22,8 +23,8 @@ func TestNotesCreate(t *testing.T) {
 	expectedNote := postgres.Notes{
 		ID:          "generated-id",
 		Key:         "Test Note",
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		Data:        "This is the content",
 		Tags:        []string{"tag1", "tag2"},
 		CreatedAt:   time.Now(),
@@ -34,8 +35,8 @@ func TestNotesCreate(t *testing.T) {
 		mock.Anything, 
 		mock.MatchedBy(func(n postgres.Notes) bool {
 			return n.Key == "Test Note" && 
-				   n.UserUID == "user-123" &&
-				   n.HouseholdUID == "household-456" &&
+				   *n.UserUID == "user-123" &&
+				   *n.HouseholdUID == "household-456" &&
 				   n.Data == "This is the content" &&
 				   len(n.Tags) == 2
 		})).Return(expectedNote, nil)
@@ -116,8 +117,8 @@ func TestNotesGet(t *testing.T) {
 	expectedNote := postgres.Notes{
 		ID:          "test-id",
 		Key:         "Test Note",
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		Data:        "This is the content",
 		Tags:        []string{"tag1"},
 		CreatedAt:   time.Now(),
@@ -176,8 +177,8 @@ func TestNotesUpdate(t *testing.T) {
 	expectedNote := postgres.Notes{
 		ID:          "test-id",
 		Key:         "Updated Note",
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		Data:        "Updated content",
 		Tags:        []string{"updated"},
 		CreatedAt:   time.Now(),
@@ -310,8 +311,8 @@ func TestNotesList(t *testing.T) {
 		{
 			ID:          "test-id-1",
 			Key:         "Test Note 1",
-			UserUID:      "user-123",
-			HouseholdUID: "household-456",
+			UserUID:      strPtr("user-123"),
+			HouseholdUID: strPtr("household-456"),
 			Data:        "Content 1",
 			Tags:        []string{"tag1"},
 			CreatedAt:   time.Now(),
@@ -320,8 +321,8 @@ func TestNotesList(t *testing.T) {
 		{
 			ID:          "test-id-2",
 			Key:         "Test Note 2",
-			UserUID:      "user-123",
-			HouseholdUID: "household-456",
+			UserUID:      strPtr("user-123"),
+			HouseholdUID: strPtr("household-456"),
 			Data:        "Content 2",
 			Tags:        []string{"tag2"},
 			CreatedAt:   time.Now(),
@@ -365,4 +366,31 @@ func TestNotesListError(t *testing.T) {
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("Expected status 500, got %d", rr.Code)
 	}
-}
\ No newline at end of file
+}
+
+func TestNotesRendered(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("GetNotes", mock.Anything, "n1").Return(postgres.Notes{ID: "n1", Data: "# Wi-Fi\n**Password:** <hunter2>", Visibility: postgres.NoteVisibilityHousehold}, nil)
+	mockNotesDAO.On("GetNotes", mock.Anything, "missing").Return(postgres.Notes{}, pgx.ErrNoRows)
+
+	handler := NewNotes(mockNotesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/n1/rendered", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Expected an HTML response, got %q", ct)
+	}
+	want := "<h1>Wi-Fi</h1>\n<p><strong>Password:</strong> &lt;hunter2&gt;</p>\n"
+	if rr.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/missing/rendered", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+}
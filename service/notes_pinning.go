@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// defaultPinnedNotesBudget caps how many characters of pinned note data are
+// put in front of the assistant by bootstrap and get_briefing.
+const defaultPinnedNotesBudget = 2000
+
+// budgetPinnedNotes returns the pinned notes from notes, keeping their order,
+// that fit within budget characters of key and data. A note too large for
+// the remaining budget is skipped so smaller ones after it still fit.
+func budgetPinnedNotes(notes []dao.Notes, budget int) []dao.Notes {
+	var out []dao.Notes
+	for _, n := range notes {
+		if !n.Pinned {
+			continue
+		}
+		size := len(n.Key) + len(n.Data)
+		if size > budget {
+			continue
+		}
+		budget -= size
+		out = append(out, n)
+	}
+	return out
+}
+
+type PinNoteRequest struct {
+	SortOrder int `json:"sort_order"`
+}
+
+func (h *NotesHandlers) pin(w http.ResponseWriter, r *http.Request) {
+	var req PinNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.setPinned(w, r, true, req.SortOrder)
+}
+
+func (h *NotesHandlers) unpin(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, false, 0)
+}
+
+func (h *NotesHandlers) setPinned(w http.ResponseWriter, r *http.Request, pinned bool, sortOrder int) {
+	if !h.callerCanAccess(r) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	out, err := h.dao.SetNotePinned(r.Context(), chi.URLParam(r, "id"), pinned, sortOrder)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
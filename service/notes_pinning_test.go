@@ -0,0 +1,171 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBudgetPinnedNotes(t *testing.T) {
+	notes := []postgres.Notes{
+		{ID: "1", Key: "wifi", Data: "hunter2", Pinned: true},
+		{ID: "2", Key: "groceries", Data: "milk"},
+		{ID: "3", Key: "manual", Data: strings.Repeat("x", 100), Pinned: true},
+		{ID: "4", Key: "sitter", Data: "555-0100", Pinned: true},
+	}
+
+	var ids []string
+	for _, n := range budgetPinnedNotes(notes, 30) {
+		ids = append(ids, n.ID)
+	}
+	assert.Equal(t, []string{"1", "4"}, ids)
+	assert.Empty(t, budgetPinnedNotes(notes, 0))
+}
+
+func TestNotesPinAndUnpin(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("SetNotePinned", mock.Anything, "note-1", true, 3).Return(postgres.Notes{ID: "note-1", Pinned: true, SortOrder: 3}, nil)
+	mockNotesDAO.On("SetNotePinned", mock.Anything, "note-1", true, 0).Return(postgres.Notes{ID: "note-1", Pinned: true}, nil)
+	mockNotesDAO.On("SetNotePinned", mock.Anything, "note-1", false, 0).Return(postgres.Notes{ID: "note-1"}, nil)
+	handler := NewNotes(mockNotesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/note-1/pin", strings.NewReader(`{"sort_order": 3}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/note-1/pin", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/note-1/pin", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/note-1/pin", strings.NewReader(`{"sort_order": "first"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestNotesPinChecksVisibility(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(postgres.Notes{
+		ID: "note-1", UserUID: strPtr("user-2"), HouseholdUID: strPtr("house-1"), Visibility: postgres.NoteVisibilityPrivate,
+	}, nil)
+	handler := NewNotes(mockNotesDAO)
+
+	req := httptest.NewRequest("PUT", "/note-1/pin", nil).WithContext(identityContext("user-1", "house-1"))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockNotesDAO.AssertNotCalled(t, "SetNotePinned", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkdownPromptPinnedNotesFirst(t *testing.T) {
+	notes := []postgres.Notes{
+		{ID: "1", Key: "groceries", Data: "milk"},
+		{ID: "2", Key: "wifi", Data: "hunter2", Pinned: true},
+	}
+	todos := []postgres.Todo{{Title: "Call plumber"}}
+
+	prompt := MarkdownPrompt{}.CompilePrompt(PromptInput{User: postgres.Users{UID: "user-1", Name: "Sam"}, Todos: todos, Notes: notes, Now: time.Now()})
+
+	pinned := strings.Index(prompt, "# Pinned Notes")
+	if assert.GreaterOrEqual(t, pinned, 0) {
+		assert.Less(t, pinned, strings.Index(prompt, "# Todos"))
+		assert.Less(t, strings.Index(prompt, "# Todos"), strings.Index(prompt, "# Notes\n"))
+	}
+	assert.Equal(t, 1, strings.Count(prompt, "**wifi**"))
+	assert.Equal(t, 1, strings.Count(prompt, "**groceries**"))
+}
+
+func TestMCPHandlers_PinNote(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("SetNotePinned", mock.Anything, "note-1", true, 2).Return(postgres.Notes{ID: "note-1", Pinned: true, SortOrder: 2}, nil)
+	mockNotesDAO.On("SetNotePinned", mock.Anything, "note-1", false, 0).Return(postgres.Notes{ID: "note-1"}, nil)
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "pin_note", map[string]any{"note_id": "note-1", "sort_order": float64(2)}), &body)
+	assert.Equal(t, "Note pinned", body["summary"])
+
+	body = nil
+	decodeToolResult(t, h.callTool(t.Context(), "pin_note", map[string]any{"note_id": "note-1", "pinned": false}), &body)
+	assert.Equal(t, "Note unpinned", body["summary"])
+
+	body = nil
+	decodeToolResult(t, h.callTool(t.Context(), "pin_note", map[string]any{}), &body)
+	assert.Equal(t, "note_id is required", body["error"])
+	mockNotesDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_RemindMe(t *testing.T) {
+	remindAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("SetNoteReminder", mock.Anything, "note-1", mock.MatchedBy(func(t *time.Time) bool { return t != nil && t.Equal(remindAt) })).
+		Return(postgres.Notes{ID: "note-1", RemindAt: &remindAt}, nil)
+	mockNotesDAO.On("SetNoteReminder", mock.Anything, "note-1", (*time.Time)(nil)).Return(postgres.Notes{ID: "note-1"}, nil)
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "remind_me", map[string]any{"note_id": "note-1", "remind_at": remindAt.Format(time.RFC3339)}), &body)
+	assert.Equal(t, "Reminder set", body["summary"])
+
+	body = nil
+	decodeToolResult(t, h.callTool(t.Context(), "remind_me", map[string]any{"note_id": "note-1", "cancel": true}), &body)
+	assert.Equal(t, "Reminder cancelled", body["summary"])
+
+	for want, args := range map[string]map[string]any{
+		"remind_at is required":                                          {"note_id": "note-1"},
+		"remind_at must be in the future":                                {"note_id": "note-1", "remind_at": "2020-01-01T00:00:00Z"},
+		"remind_at can't be given when cancelling":                       {"note_id": "note-1", "remind_at": remindAt.Format(time.RFC3339), "cancel": true},
+		"remind_at must be in RFC3339 format, e.g. 2026-05-01T09:00:00Z": {"note_id": "note-1", "remind_at": "June"},
+	} {
+		body = nil
+		decodeToolResult(t, h.callTool(t.Context(), "remind_me", args), &body)
+		assert.Equal(t, want, body["error"])
+	}
+	mockNotesDAO.AssertExpectations(t)
+}
+
+func TestMCPHandlers_GetBriefing(t *testing.T) {
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "user-1").Return(postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}, nil)
+	mockHouseholdDAO := &MockHouseholdDAO{}
+	mockHouseholdDAO.On("GetHousehold", mock.Anything, "house-1").Return(postgres.Households{UID: "house-1", Name: "Home"}, nil)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND pinned AND (user_uid = $2 OR (visibility <> 'private' AND household_uid = $3))" &&
+			o.SortBy == "sort_order"
+	})).Return([]postgres.Notes{{ID: "note-1", Key: "wifi", Data: "hunter2", Pinned: true}}, nil)
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return strings.Contains(o.WhereClause, "marked_complete IS NULL")
+	})).Return([]postgres.Todo{{UID: "todo-1", Title: "Call plumber"}}, nil)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return strings.Contains(o.WhereClause, "marked_complete >= $1") && o.SortBy == "marked_complete"
+	})).Return([]postgres.Todo{{UID: "todo-2", Title: "Take the bins out", CompletedBy: strPtr("user-2"),
+		Completer: &postgres.TodoCompleter{UID: "user-2", Name: "Mia", Email: "mia@example.com"}}}, nil)
+
+	h := NewMCP(mockTodoDAO, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, mockHouseholdDAO)
+	tool, ok := h.findTool("get_briefing")
+	if assert.True(t, ok) {
+		assert.True(t, *tool.Annotations.ReadOnlyHint)
+	}
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_briefing", map[string]any{}), &body)
+	assert.Equal(t, "Briefing for Sam", body["summary"])
+	assert.Len(t, body["pinned_notes"], 1)
+	assert.Len(t, body["todos"], 1)
+	if done, _ := body["recently_completed"].([]any); assert.Len(t, done, 1) {
+		assert.Equal(t, "Mia", done[0].(map[string]any)["completer"].(map[string]any)["name"])
+	}
+	assert.Equal(t, "Home", body["household"].(map[string]any)["name"])
+}
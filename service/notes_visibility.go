@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+const defaultNoteShareTTL = 7 * 24 * time.Hour
+
+var errInvalidShareToken = errors.New("invalid share token")
+
+func validNoteVisibility(v string) bool {
+	switch v {
+	case dao.NoteVisibilityPrivate, dao.NoteVisibilityHousehold, dao.NoteVisibilitySharedLink:
+		return true
+	default:
+		return false
+	}
+}
+
+// noteVisibleTo reports whether the authenticated caller may read n. Owners
+// always can; household and shared-link notes are also visible to the rest
+// of the owner's household.
+func noteVisibleTo(n dao.Notes, id Identity) bool {
+	if n.UserUID != nil && *n.UserUID == id.UserUID {
+		return true
+	}
+	if n.Visibility == dao.NoteVisibilityPrivate {
+		return false
+	}
+	return id.HouseholdUID != "" && n.HouseholdUID != nil && *n.HouseholdUID == id.HouseholdUID
+}
+
+// noteAccessible applies noteVisibleTo to the identity in ctx. Requests
+// without an identity (API keys not required) see every note.
+func noteAccessible(ctx context.Context, n dao.Notes) bool {
+	id, ok := IdentityFromContext(ctx)
+	return !ok || noteVisibleTo(n, id)
+}
+
+// withNoteVisibility narrows a notes list query built by BuildWhereClause to
+// the notes the caller in ctx may read.
+func withNoteVisibility(ctx context.Context, whereClause string, whereArgs []interface{}) (string, []interface{}) {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return whereClause, whereArgs
+	}
+
+	n := len(whereArgs) + 1
+	cond := fmt.Sprintf("user_uid = $%d", n)
+	whereArgs = append(whereArgs, id.UserUID)
+	if id.HouseholdUID != "" {
+		cond = fmt.Sprintf("(%s OR (visibility <> '%s' AND household_uid = $%d))", cond, dao.NoteVisibilityPrivate, n+1)
+		whereArgs = append(whereArgs, id.HouseholdUID)
+	}
+
+	if whereClause == "" {
+		return "WHERE " + cond, whereArgs
+	}
+	return whereClause + " AND " + cond, whereArgs
+}
+
+// noteSigner issues and checks read-only share links for notes. A token is
+// the note ID and expiry, signed with HMAC-SHA256.
+type noteSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func newNoteSigner(secret []byte, ttl time.Duration) *noteSigner {
+	if ttl <= 0 {
+		ttl = defaultNoteShareTTL
+	}
+	return &noteSigner{secret: secret, ttl: ttl, now: time.Now}
+}
+
+func (s *noteSigner) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Token returns a share token for noteID and when it expires.
+func (s *noteSigner) Token(noteID string) (string, time.Time) {
+	expires := s.now().Add(s.ttl).Truncate(time.Second)
+	payload := noteID + "." + strconv.FormatInt(expires.Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + s.sign(payload), expires
+}
+
+// Verify returns the note ID in token if its signature is valid and it has
+// not expired.
+func (s *noteSigner) Verify(token string) (string, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errInvalidShareToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errInvalidShareToken
+	}
+	payload := string(raw)
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return "", errInvalidShareToken
+	}
+	noteID, exp, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", errInvalidShareToken
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || !s.now().Before(time.Unix(unix, 0)) {
+		return "", errInvalidShareToken
+	}
+	return noteID, nil
+}
+
+// NotesOption configures the notes router.
+type NotesOption func(*NotesHandlers)
+
+// WithNoteSharing enables POST /notes/{id}/share, which returns a signed link
+// under baseURL that NewSharedNotes serves without authentication.
+func WithNoteSharing(secret []byte, baseURL string, ttl time.Duration) NotesOption {
+	return func(h *NotesHandlers) {
+		h.signer = newNoteSigner(secret, ttl)
+		h.shareBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+type ShareNoteResponse struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// share marks a note as shared-link and returns a signed link to it.
+func (h *NotesHandlers) share(w http.ResponseWriter, r *http.Request) {
+	note, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || !noteAccessible(r.Context(), note) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if note.Visibility != dao.NoteVisibilitySharedLink {
+		note.Visibility = dao.NoteVisibilitySharedLink
+		if note, err = h.dao.UpdateNotes(r.Context(), note.ID, note); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	token, expires := h.signer.Token(note.ID)
+	_ = json.NewEncoder(w).Encode(ShareNoteResponse{
+		URL:       h.shareBaseURL + "/shared/notes/" + token,
+		Token:     token,
+		ExpiresAt: expires,
+	})
+}
+
+// SharedNote is the read-only view of a note served from a share link. It
+// leaves out who owns the note.
+type SharedNote struct {
+	Key       string    `json:"key"`
+	Data      string    `json:"data"`
+	Tags      []string  `json:"tags"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SharedNotesHandlers struct {
+	dao    notesDAO
+	signer *noteSigner
+}
+
+// NewSharedNotes serves notes from share links created by POST
+// /notes/{id}/share. It must be mounted outside any authentication.
+// Changing a note's visibility away from shared-link revokes its links.
+func NewSharedNotes(dao notesDAO, secret []byte) http.Handler {
+	h := &SharedNotesHandlers{dao: dao, signer: newNoteSigner(secret, 0)}
+	r := chi.NewRouter()
+	r.Get("/{token}", h.get)
+	return r
+}
+
+func (h *SharedNotesHandlers) get(w http.ResponseWriter, r *http.Request) {
+	noteID, err := h.signer.Verify(chi.URLParam(r, "token"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	// The signed link names the note, whichever tenant it belongs to.
+	note, err := h.dao.GetNotes(dao.WithAllTenants(r.Context()), noteID)
+	if err != nil || note.Visibility != dao.NoteVisibilitySharedLink {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(SharedNote{
+		Key:       note.Key,
+		Data:      note.Data,
+		Tags:      note.Tags,
+		UpdatedAt: note.UpdatedAt,
+	})
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNoteVisibleTo(t *testing.T) {
+	caller := Identity{UserUID: "user-1", HouseholdUID: "house-1"}
+	note := func(owner, household, visibility string) postgres.Notes {
+		return postgres.Notes{UserUID: strPtr(owner), HouseholdUID: strPtr(household), Visibility: visibility}
+	}
+
+	tests := []struct {
+		name string
+		note postgres.Notes
+		want bool
+	}{
+		{"own private note", note("user-1", "house-1", postgres.NoteVisibilityPrivate), true},
+		{"housemate's private note", note("user-2", "house-1", postgres.NoteVisibilityPrivate), false},
+		{"housemate's household note", note("user-2", "house-1", postgres.NoteVisibilityHousehold), true},
+		{"housemate's shared note", note("user-2", "house-1", postgres.NoteVisibilitySharedLink), true},
+		{"other household's note", note("user-3", "house-2", postgres.NoteVisibilityHousehold), false},
+		{"other household's shared note", note("user-3", "house-2", postgres.NoteVisibilitySharedLink), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, noteVisibleTo(tt.note, caller))
+		})
+	}
+}
+
+func TestWithNoteVisibility(t *testing.T) {
+	clause, args := withNoteVisibility(t.Context(), "WHERE key = $1", []interface{}{"groceries"})
+	assert.Equal(t, "WHERE key = $1", clause)
+	assert.Equal(t, []interface{}{"groceries"}, args)
+
+	clause, args = withNoteVisibility(identityContext("user-1", "house-1"), "WHERE key = $1", []interface{}{"groceries"})
+	assert.Equal(t, "WHERE key = $1 AND (user_uid = $2 OR (visibility <> 'private' AND household_uid = $3))", clause)
+	assert.Equal(t, []interface{}{"groceries", "user-1", "house-1"}, args)
+
+	clause, args = withNoteVisibility(identityContext("user-1", ""), "", nil)
+	assert.Equal(t, "WHERE user_uid = $1", clause)
+	assert.Equal(t, []interface{}{"user-1"}, args)
+}
+
+func TestNotesGetHidesPrivateNotes(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(postgres.Notes{
+		ID: "note-1", UserUID: strPtr("user-2"), HouseholdUID: strPtr("house-1"), Visibility: postgres.NoteVisibilityPrivate,
+	}, nil)
+	handler := NewNotes(mockNotesDAO)
+
+	req := httptest.NewRequest("GET", "/note-1", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req.WithContext(identityContext("user-1", "house-1")))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req.WithContext(identityContext("user-2", "house-1")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Without an API key identity the note is served as before.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/note-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestNotesCreateRejectsUnknownVisibility(t *testing.T) {
+	handler := NewNotes(mocks.NewMocknotesDAO(t))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"key": "k", "data": "d", "visibility": "public"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestNotesDeleteChecksVisibility(t *testing.T) {
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(postgres.Notes{
+		ID: "note-1", UserUID: strPtr("user-3"), HouseholdUID: strPtr("house-2"), Visibility: postgres.NoteVisibilityHousehold,
+	}, nil)
+	handler := NewNotes(mockNotesDAO)
+
+	req := httptest.NewRequest("DELETE", "/note-1", nil).WithContext(identityContext("user-1", "house-1"))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockNotesDAO.AssertNotCalled(t, "DeleteNotes", mock.Anything, mock.Anything)
+}
+
+func TestNoteShareLinks(t *testing.T) {
+	secret := []byte("test-secret")
+	note := postgres.Notes{
+		ID: "note-1", Key: "groceries", Data: "milk, eggs", UserUID: strPtr("user-1"), HouseholdUID: strPtr("house-1"),
+		Visibility: postgres.NoteVisibilityHousehold,
+	}
+	shared := note
+	shared.Visibility = postgres.NoteVisibilitySharedLink
+
+	mockNotesDAO := mocks.NewMocknotesDAO(t)
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(note, nil).Once()
+	mockNotesDAO.On("UpdateNotes", mock.Anything, "note-1", shared).Return(shared, nil).Once()
+
+	notes := NewNotes(mockNotesDAO, WithNoteSharing(secret, "https://assistant.example/", time.Hour))
+	req := httptest.NewRequest("POST", "/note-1/share", nil).WithContext(identityContext("user-1", "house-1"))
+	rr := httptest.NewRecorder()
+	notes.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var link ShareNoteResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &link))
+	assert.Equal(t, "https://assistant.example/shared/notes/"+link.Token, link.URL)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), link.ExpiresAt, time.Minute)
+
+	public := NewSharedNotes(mockNotesDAO, secret)
+	get := func(token string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		public.ServeHTTP(rr, httptest.NewRequest("GET", "/"+token, nil))
+		return rr
+	}
+
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(shared, nil).Once()
+	rr = get(link.Token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "milk, eggs", body["data"])
+	assert.NotContains(t, body, "user_uid")
+
+	assert.Equal(t, http.StatusNotFound, get(link.Token+"x").Code)
+	assert.Equal(t, http.StatusNotFound, get("garbage").Code)
+
+	// Moving the note off shared-link revokes the link.
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(note, nil).Once()
+	assert.Equal(t, http.StatusNotFound, get(link.Token).Code)
+}
+
+func TestNoteSignerExpiry(t *testing.T) {
+	now := time.Date(2025, 8, 17, 12, 0, 0, 0, time.UTC)
+	signer := newNoteSigner([]byte("secret"), time.Hour)
+	signer.now = func() time.Time { return now }
+
+	token, _ := signer.Token("note-1")
+	id, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "note-1", id)
+
+	now = now.Add(2 * time.Hour)
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, errInvalidShareToken)
+
+	other := newNoteSigner([]byte("other"), time.Hour)
+	_, err = other.Verify(token)
+	assert.ErrorIs(t, err, errInvalidShareToken)
+}
+
+func TestMCPHandlers_NoteVisibility(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("GetNotes", mock.Anything, "note-1").Return(postgres.Notes{
+		ID: "note-1", UserUID: strPtr("user-2"), HouseholdUID: strPtr("house-1"), Visibility: postgres.NoteVisibilityPrivate,
+	}, nil)
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "recall_note", map[string]any{"note_id": "note-1"}), &body)
+	assert.Equal(t, "Note not found: note-1", body["error"])
+
+	assert.False(t, h.callTool(identityContext("user-2", "house-1"), "recall_note", map[string]any{"note_id": "note-1"}).IsError)
+
+	body = nil
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "save_note", map[string]any{"key": "k", "data": "d", "visibility": "public"}), &body)
+	assert.Equal(t, "visibility must be one of private, household or shared-link", body["error"])
+}
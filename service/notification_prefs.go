@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// NotificationPreferencesKey is the preference key, specified by user UID,
+// holding a user's NotificationPreferences as JSON.
+const NotificationPreferencesKey = "notifications"
+
+// Notification channels.
+const (
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+)
+
+// Notification categories, each delivered instantly, in the digest, or not
+// at all.
+const (
+	CategoryTodoReminders  = "todo_reminders"
+	CategoryNoteReminders  = "note_reminders"
+	CategoryWeeklyReview   = "weekly_review"
+	CategoryHouseholdJoins = "household_joins"
+)
+
+const (
+	DeliveryInstant = "instant"
+	DeliveryDigest  = "digest"
+	DeliveryOff     = "off"
+)
+
+var (
+	notificationChannels   = []string{ChannelEmail, ChannelPush}
+	notificationCategories = []string{CategoryTodoReminders, CategoryNoteReminders, CategoryWeeklyReview, CategoryHouseholdJoins}
+	notificationDeliveries = []string{DeliveryInstant, DeliveryDigest, DeliveryOff}
+	digestFrequencies      = []string{digestDaily, digestWeekly, DeliveryOff}
+)
+
+// NotificationPreferences is what a user wants to hear about and how.
+// Fields missing from the stored JSON take their defaults.
+type NotificationPreferences struct {
+	UserUID string `json:"user_uid"`
+	// Channels turns each delivery channel on or off.
+	Channels map[string]bool `json:"channels"`
+	// Categories sets how each kind of notification is delivered.
+	Categories map[string]string `json:"categories"`
+	// DigestFrequency is "daily", "weekly" or "off".
+	DigestFrequency string `json:"digest_frequency"`
+	// QuietHours hold back instant notifications; nil means none.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+}
+
+// QuietHours run from Start to End ("22:00" to "07:00") in Timezone, an
+// IANA zone name that defaults to UTC.
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DefaultNotificationPreferences has every channel on, reminders and weekly
+// reviews delivered instantly and no digest.
+func DefaultNotificationPreferences(userUID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserUID:         userUID,
+		Channels:        map[string]bool{ChannelEmail: true, ChannelPush: true},
+		Categories:      map[string]string{CategoryTodoReminders: DeliveryInstant, CategoryNoteReminders: DeliveryInstant, CategoryWeeklyReview: DeliveryInstant, CategoryHouseholdJoins: DeliveryInstant},
+		DigestFrequency: DeliveryOff,
+	}
+}
+
+func (p NotificationPreferences) Validate() error {
+	for channel := range p.Channels {
+		if !slices.Contains(notificationChannels, channel) {
+			return fmt.Errorf("unknown channel %q", channel)
+		}
+	}
+	for category, delivery := range p.Categories {
+		if !slices.Contains(notificationCategories, category) {
+			return fmt.Errorf("unknown category %q", category)
+		}
+		if !slices.Contains(notificationDeliveries, delivery) {
+			return fmt.Errorf("%s delivery must be instant, digest or off", category)
+		}
+	}
+	if !slices.Contains(digestFrequencies, p.DigestFrequency) {
+		return errors.New("digest_frequency must be daily, weekly or off")
+	}
+	if q := p.QuietHours; q != nil {
+		if _, err := time.Parse("15:04", q.Start); err != nil {
+			return errors.New("quiet_hours.start must be a time like 22:00")
+		}
+		if _, err := time.Parse("15:04", q.End); err != nil {
+			return errors.New("quiet_hours.end must be a time like 07:00")
+		}
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			return fmt.Errorf("unknown quiet_hours.timezone %q", q.Timezone)
+		}
+	}
+	return nil
+}
+
+// InQuietHours reports whether t falls in the user's quiet hours. Quiet
+// hours may run past midnight.
+func (p NotificationPreferences) InQuietHours(t time.Time) bool {
+	q := p.QuietHours
+	if q == nil || q.Start == q.End {
+		return false
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := t.In(loc).Format("15:04")
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// SendsInstant reports whether a notification of category may go out on
+// channel right now.
+func (p NotificationPreferences) SendsInstant(channel, category string, now time.Time) bool {
+	return p.Channels[channel] && p.Categories[category] == DeliveryInstant && !p.InQuietHours(now)
+}
+
+// SendsDigest reports whether the user wants a digest at this frequency.
+func (p NotificationPreferences) SendsDigest(frequency string) bool {
+	return p.Channels[ChannelEmail] && p.DigestFrequency == frequency
+}
+
+// parseNotificationPreferences reads stored preferences over the defaults.
+// Decoding into the default maps keeps entries the JSON leaves out.
+func parseNotificationPreferences(userUID, data string) (NotificationPreferences, error) {
+	p := DefaultNotificationPreferences(userUID)
+	err := json.Unmarshal([]byte(data), &p)
+	p.UserUID = userUID
+	return p, err
+}
+
+// loadNotificationPreferences returns a user's preferences, or the defaults
+// when they haven't set any.
+func loadNotificationPreferences(ctx context.Context, prefs preferencesDAO, userUID string) (NotificationPreferences, error) {
+	stored, err := prefs.GetPreferences(ctx, NotificationPreferencesKey, userUID)
+	if err != nil {
+		return DefaultNotificationPreferences(userUID), nil
+	}
+	return parseNotificationPreferences(userUID, stored.Data)
+}
+
+func saveNotificationPreferences(ctx context.Context, prefs preferencesDAO, p NotificationPreferences) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	pref := dao.Preferences{Key: NotificationPreferencesKey, Specifier: p.UserUID, Data: string(data)}
+	if _, err := prefs.GetPreferences(ctx, NotificationPreferencesKey, p.UserUID); err == nil {
+		_, err = prefs.UpdatePreferences(ctx, NotificationPreferencesKey, p.UserUID, pref)
+		return err
+	}
+	_, err = prefs.CreatePreferences(ctx, pref)
+	return err
+}
+
+type NotificationPreferenceHandlers struct{ dao preferencesDAO }
+
+func NewNotificationPreferences(dao preferencesDAO) http.Handler {
+	h := &NotificationPreferenceHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/{user_uid}", h.get)
+	r.Put("/{user_uid}", h.put)
+	return r
+}
+
+func (h *NotificationPreferenceHandlers) get(w http.ResponseWriter, r *http.Request) {
+	out, err := loadNotificationPreferences(r.Context(), h.dao, chi.URLParam(r, "user_uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// put replaces a user's preferences; omitted fields take their defaults.
+func (h *NotificationPreferenceHandlers) put(w http.ResponseWriter, r *http.Request) {
+	userUID := chi.URLParam(r, "user_uid")
+	p := DefaultNotificationPreferences(userUID)
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	p.UserUID = userUID
+	if err := p.Validate(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err := saveNotificationPreferences(r.Context(), h.dao, p); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
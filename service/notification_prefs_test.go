@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferencesInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 8, 18, hour, minute, 0, 0, time.UTC) }
+
+	overnight := NotificationPreferences{QuietHours: &QuietHours{Start: "22:00", End: "07:00"}}
+	assert.True(t, overnight.InQuietHours(at(23, 0)))
+	assert.True(t, overnight.InQuietHours(at(6, 59)))
+	assert.False(t, overnight.InQuietHours(at(7, 0)))
+	assert.False(t, overnight.InQuietHours(at(12, 0)))
+
+	lunch := NotificationPreferences{QuietHours: &QuietHours{Start: "12:00", End: "13:00", Timezone: "America/New_York"}}
+	assert.True(t, lunch.InQuietHours(at(16, 30)))
+	assert.False(t, lunch.InQuietHours(at(12, 30)))
+
+	assert.False(t, NotificationPreferences{}.InQuietHours(at(23, 0)))
+}
+
+func TestNotificationPreferencesValidate(t *testing.T) {
+	assert.NoError(t, DefaultNotificationPreferences("user-1").Validate())
+
+	for _, data := range []string{
+		`{"channels": {"sms": true}}`,
+		`{"categories": {"birthdays": "instant"}}`,
+		`{"categories": {"todo_reminders": "hourly"}}`,
+		`{"digest_frequency": "monthly"}`,
+		`{"quiet_hours": {"start": "10pm", "end": "07:00"}}`,
+		`{"quiet_hours": {"start": "22:00", "end": "07:00", "timezone": "Mars/Olympus"}}`,
+	} {
+		p, err := parseNotificationPreferences("user-1", data)
+		require.NoError(t, err)
+		assert.Error(t, p.Validate(), data)
+	}
+}
+
+func TestNotificationPreferencesHandlers(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "user-1").Return(dao.Preferences{}, errors.New("not found"))
+	prefs.On("CreatePreferences", mock.Anything, mock.MatchedBy(func(p dao.Preferences) bool {
+		return p.Key == NotificationPreferencesKey && p.Specifier == "user-1" &&
+			strings.Contains(p.Data, `"digest_frequency":"weekly"`) && strings.Contains(p.Data, `"push":true`)
+	})).Return(dao.Preferences{}, nil)
+	handler := NewNotificationPreferences(prefs)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/user-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"user_uid": "user-1",
+		"channels": {"email": true, "push": true},
+		"categories": {"todo_reminders": "instant", "note_reminders": "instant", "weekly_review": "instant", "household_joins": "instant"},
+		"digest_frequency": "off"
+	}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/user-1", strings.NewReader(`{"digest_frequency": "weekly", "channels": {"email": true}}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	prefs.AssertCalled(t, "CreatePreferences", mock.Anything, mock.Anything)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/user-1", strings.NewReader(`{"digest_frequency": "hourly"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "digest_frequency must be daily, weekly or off")
+}
+
+func TestMCPHandlers_SetNotificationPreference(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "user-1").
+		Return(dao.Preferences{Data: `{"digest_frequency": "daily"}`}, nil)
+	prefs.On("UpdatePreferences", mock.Anything, NotificationPreferencesKey, "user-1", mock.Anything).Return(dao.Preferences{}, nil)
+	h := &MCPHandlers{preferencesDAO: prefs}
+
+	result := h.handleSetNotificationPreference(identityContext("user-1", ""), map[string]any{
+		"user_uid":    "user-1",
+		"channel":     ChannelEmail,
+		"enabled":     false,
+		"category":    CategoryTodoReminders,
+		"delivery":    DeliveryDigest,
+		"quiet_hours": "22:00 - 07:00",
+		"timezone":    "Europe/London",
+	})
+	require.False(t, result.IsError)
+	var out struct {
+		NotificationPreferences NotificationPreferences `json:"notification_preferences"`
+	}
+	decodeToolResult(t, result, &out)
+	assert.Equal(t, NotificationPreferences{
+		UserUID:         "user-1",
+		Channels:        map[string]bool{ChannelEmail: false, ChannelPush: true},
+		Categories:      map[string]string{CategoryTodoReminders: DeliveryDigest, CategoryNoteReminders: DeliveryInstant, CategoryWeeklyReview: DeliveryInstant, CategoryHouseholdJoins: DeliveryInstant},
+		DigestFrequency: digestDaily,
+		QuietHours:      &QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/London"},
+	}, out.NotificationPreferences)
+
+	for _, args := range []map[string]any{
+		{"user_uid": "user-1", "channel": ChannelPush},
+		{"user_uid": "user-1", "category": CategoryTodoReminders},
+		{"user_uid": "user-1", "quiet_hours": "late"},
+		{"user_uid": "user-1", "timezone": "Europe/London"},
+	} {
+		assert.True(t, h.handleSetNotificationPreference(identityContext("user-1", ""), args).IsError, args)
+	}
+}
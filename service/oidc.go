@@ -0,0 +1,687 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"golang.org/x/oauth2"
+)
+
+// OIDCCredentialPrefix starts the credential type of an account linked
+// through an OpenID Connect provider, e.g. OIDC_AUTHENTIK.
+const OIDCCredentialPrefix = "OIDC_"
+
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// OIDCProvider is an OpenID Connect identity provider, such as Authentik or
+// Keycloak. Its endpoints are discovered from Issuer.
+type OIDCProvider struct {
+	// Name is the provider's path segment, e.g. /oauth/oidc/authentik.
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes default to openid, email and profile.
+	Scopes []string
+}
+
+// OIDCIdentity is the account a user signed in to at a provider. It is
+// stored, with the provider's token, as the user's credential.
+type OIDCIdentity struct {
+	Provider string        `json:"provider"`
+	Issuer   string        `json:"issuer"`
+	Subject  string        `json:"subject"`
+	Email    string        `json:"email,omitempty"`
+	Name     string        `json:"name,omitempty"`
+	Token    *oauth2.Token `json:"token,omitempty"`
+}
+
+// OIDCLinkResponse is where to send the browser to link an account. The
+// URL stands in for the caller's API key until it expires, so it should
+// only be opened by the key's user.
+type OIDCLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCLoginResponse carries the API key a login issued. Like any API key it
+// is only ever returned once.
+type OIDCLoginResponse struct {
+	UserUID  string       `json:"user_uid"`
+	Identity OIDCIdentity `json:"identity"`
+	Key      string       `json:"key"`
+	APIKey   dao.APIKeys  `json:"api_key"`
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcClient discovers its provider's endpoints on first use, so a provider
+// that is down when the server starts doesn't stop it starting.
+type oidcClient struct {
+	provider    OIDCProvider
+	baseURL     string
+	redirectURL string
+
+	mu        sync.Mutex
+	issuer    string
+	jwksURI   string
+	oauth2Cfg *oauth2.Config
+	// keys are the provider's signing keys by key ID, refetched when a
+	// token is signed with one we haven't seen, at most once a minute.
+	keys        map[string]crypto.PublicKey
+	keysFetched time.Time
+}
+
+func newOIDCClient(p OIDCProvider, baseURL string) *oidcClient {
+	if len(p.Scopes) == 0 {
+		p.Scopes = []string{"openid", "email", "profile"}
+	} else if !slices.Contains(p.Scopes, "openid") {
+		p.Scopes = append([]string{"openid"}, p.Scopes...)
+	}
+	return &oidcClient{provider: p, baseURL: baseURL, redirectURL: baseURL + "/oauth/oidc/" + p.Name + "/callback"}
+}
+
+func (c *oidcClient) config(ctx context.Context) (*oauth2.Config, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.oauth2Cfg != nil {
+		return c.oauth2Cfg, c.issuer, nil
+	}
+
+	url := strings.TrimSuffix(c.provider.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("discovery returned %s", resp.Status)
+	}
+	var d oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(c.provider.Issuer, "/") {
+		return nil, "", fmt.Errorf("discovery is for issuer %q, not %q", d.Issuer, c.provider.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, "", errors.New("discovery has no authorization, token or JWKS endpoint")
+	}
+
+	c.issuer = d.Issuer
+	c.jwksURI = d.JWKSURI
+	c.oauth2Cfg = &oauth2.Config{
+		ClientID:     c.provider.ClientID,
+		ClientSecret: c.provider.ClientSecret,
+		RedirectURL:  c.redirectURL,
+		Scopes:       c.provider.Scopes,
+		Endpoint:     oauth2.Endpoint{AuthURL: d.AuthorizationEndpoint, TokenURL: d.TokenEndpoint},
+	}
+	return c.oauth2Cfg, c.issuer, nil
+}
+
+func (c *oidcClient) credentialType() string {
+	return OIDCCredentialPrefix + strings.ToUpper(c.provider.Name)
+}
+
+// publicKey returns the provider's signing key with the given ID, or its
+// only key when the token doesn't name one. config must have run first.
+func (c *oidcClient) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.findKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(c.keysFetched) < time.Minute {
+		return nil, fmt.Errorf("no signing key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS returned %s", resp.Status)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+	c.keys = map[string]crypto.PublicKey{}
+	c.keysFetched = time.Now()
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			slog.Warn("Skipping OIDC signing key", "provider", c.provider.Name, "kid", k.ID, "error", err)
+			continue
+		}
+		c.keys[k.ID] = key
+	}
+	if key, ok := c.findKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key %q", kid)
+}
+
+func (c *oidcClient) findKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey is an RSA or elliptic curve public key from a provider's JWKS
+// (RFC 7517).
+type jsonWebKey struct {
+	ID  string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	number := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(b) == 0 {
+			return nil, errors.New("malformed key")
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := number(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := number(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("malformed key")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := number(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := number(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("key is not on its curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// verifyJWS checks a compact JWS's signature with key, for the RS* and ES*
+// algorithms providers sign ID tokens with.
+func verifyJWS(raw, alg string, key crypto.PublicKey) error {
+	hashes := map[string]crypto.Hash{"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512, "ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512}
+	hash, ok := hashes[alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	dot := strings.LastIndex(raw, ".")
+	sig, err := base64.RawURLEncoding.DecodeString(raw[dot+1:])
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	h := hash.New()
+	h.Write([]byte(raw[:dot]))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			break
+		}
+		if rsa.VerifyPKCS1v15(key, hash, digest, sig) != nil {
+			return errors.New("signature doesn't match")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			break
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("malformed signature")
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("signature doesn't match")
+		}
+		return nil
+	}
+	return fmt.Errorf("algorithm %q doesn't match the signing key", alg)
+}
+
+// idTokenClaims are the ID token claims a login needs. aud may be a string
+// or a list.
+type idTokenClaims struct {
+	Issuer   string          `json:"iss"`
+	Subject  string          `json:"sub"`
+	Audience json.RawMessage `json:"aud"`
+	Expiry   int64           `json:"exp"`
+	Nonce    string          `json:"nonce"`
+	Email    string          `json:"email"`
+	Name     string          `json:"name"`
+}
+
+// parseIDToken verifies an ID token's signature with the key keyFor
+// returns for it, then reads and checks its claims.
+func parseIDToken(raw, issuer, clientID, nonce string, now time.Time, keyFor func(kid string) (crypto.PublicKey, error)) (idTokenClaims, error) {
+	var claims idTokenClaims
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("malformed id_token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(rawHeader, &header) != nil {
+		return claims, errors.New("malformed id_token")
+	}
+	key, err := keyFor(header.Kid)
+	if err != nil {
+		return claims, err
+	}
+	if err := verifyJWS(raw, header.Alg, key); err != nil {
+		return claims, fmt.Errorf("id_token signature: %w", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, errors.New("malformed id_token")
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, errors.New("malformed id_token")
+	}
+
+	var audience []string
+	if json.Unmarshal(claims.Audience, &audience) != nil {
+		var single string
+		_ = json.Unmarshal(claims.Audience, &single)
+		audience = []string{single}
+	}
+	switch {
+	case claims.Issuer != issuer:
+		return claims, fmt.Errorf("id_token issuer is %q, not %q", claims.Issuer, issuer)
+	case !slices.Contains(audience, clientID):
+		return claims, errors.New("id_token is not for this client")
+	case now.Unix() >= claims.Expiry:
+		return claims, errors.New("id_token has expired")
+	case claims.Nonce != nonce:
+		return claims, errors.New("id_token nonce doesn't match")
+	case claims.Subject == "":
+		return claims, errors.New("id_token has no subject")
+	}
+	return claims, nil
+}
+
+// OIDC login cookies, kept for the round trip to the provider.
+const (
+	oidcStateCookie    = "oidc_state"
+	oidcNonceCookie    = "oidc_nonce"
+	oidcVerifierCookie = "oidc_verifier"
+)
+
+// oidcLinkTTL is how long a link URL, and a round trip to the provider, may
+// take.
+const oidcLinkTTL = 10 * time.Minute
+
+// oidcLink is the user an account is being linked to, taken from the API
+// key that asked for the link.
+type oidcLink struct {
+	UserUID      string `json:"user_uid"`
+	HouseholdUID string `json:"household_uid,omitempty"`
+	TenantUID    string `json:"tenant_uid"`
+}
+
+// oidcToken is signed into link URLs ("link") and the state parameter of a
+// round trip to the provider ("state"), so neither the browser nor the
+// provider can change who an account is linked to.
+type oidcToken struct {
+	Purpose string    `json:"purpose"`
+	Random  string    `json:"random"`
+	Link    *oidcLink `json:"link,omitempty"`
+	Expiry  int64     `json:"exp"`
+}
+
+// sign encodes t with an HMAC keyed by the provider's client secret.
+func (c *oidcClient) sign(t oidcToken) (string, error) {
+	random, err := generateRandomState()
+	if err != nil {
+		return "", err
+	}
+	t.Random = random
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.mac(encoded), nil
+}
+
+func (c *oidcClient) mac(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(c.provider.ClientSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify decodes a token sign made for purpose, unless it has expired.
+func (c *oidcClient) verify(raw, purpose string, now time.Time) (oidcToken, error) {
+	var t oidcToken
+	encoded, sig, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(c.mac(encoded))) {
+		return t, errors.New("bad signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &t) != nil {
+		return t, errors.New("malformed token")
+	}
+	if t.Purpose != purpose || now.Unix() >= t.Expiry {
+		return t, errors.New("expired or not for this use")
+	}
+	return t, nil
+}
+
+func (h *AuthHandlers) oidcProvider(w http.ResponseWriter, r *http.Request) (*oidcClient, bool) {
+	c, ok := h.oidc[chi.URLParam(r, "provider")]
+	if !ok {
+		http.Error(w, "Unknown identity provider", http.StatusNotFound)
+	}
+	return c, ok
+}
+
+// oidcLinkURL gives the caller's API key a URL that, opened in their
+// browser within oidcLinkTTL, links their account at the provider to the
+// key's user. Logging in with that account issues keys with mcp:write, so
+// the caller's key must hold it.
+func (h *AuthHandlers) oidcLinkURL(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.oidcProvider(w, r)
+	if !ok {
+		return
+	}
+	id, ok := IdentityFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Linking an account needs an API key", http.StatusUnauthorized)
+		return
+	}
+	if !id.HasScope(ScopeMCPWrite) {
+		http.Error(w, "Linking an account needs an API key with scope "+ScopeMCPWrite, http.StatusForbidden)
+		return
+	}
+	if c.provider.ClientSecret == "" {
+		http.Error(w, "Identity provider has no client secret to sign links with", http.StatusInternalServerError)
+		return
+	}
+
+	expires := time.Now().Add(oidcLinkTTL)
+	link, err := c.sign(oidcToken{
+		Purpose: "link",
+		Link:    &oidcLink{UserUID: id.UserUID, HouseholdUID: id.HouseholdUID, TenantUID: id.TenantUID},
+		Expiry:  expires.Unix(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to sign link", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(OIDCLinkResponse{
+		URL:       c.baseURL + "/oauth/oidc/" + c.provider.Name + "?link=" + url.QueryEscape(link),
+		ExpiresAt: expires,
+	})
+}
+
+// oidcAuth sends the browser to the provider. With a link from oidcLinkURL
+// it links the account to the link's user; without one, it logs in the
+// user the account is already linked to.
+func (h *AuthHandlers) oidcAuth(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.oidcProvider(w, r)
+	if !ok {
+		return
+	}
+	state := oidcToken{Purpose: "state", Expiry: time.Now().Add(oidcLinkTTL).Unix()}
+	if raw := r.URL.Query().Get("link"); raw != "" {
+		link, err := c.verify(raw, "link", time.Now())
+		if err != nil || link.Link == nil {
+			http.Error(w, "Invalid or expired link; ask for a new one", http.StatusBadRequest)
+			return
+		}
+		state.Link = link.Link
+	}
+	cfg, _, err := c.config(r.Context())
+	if err != nil {
+		slog.Error("OIDC discovery failed", "provider", c.provider.Name, "error", err)
+		http.Error(w, "Identity provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	signedState, err := c.sign(state)
+	if err != nil {
+		http.Error(w, "Failed to generate state", http.StatusInternalServerError)
+		return
+	}
+	nonce, err := generateRandomState()
+	if err != nil {
+		http.Error(w, "Failed to generate nonce", http.StatusInternalServerError)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	cookies := map[string]string{oidcStateCookie: signedState, oidcNonceCookie: nonce, oidcVerifierCookie: verifier}
+	for name, value := range cookies {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    value,
+			Path:     "/oauth/oidc/",
+			Expires:  time.Now().Add(oidcLinkTTL),
+			HttpOnly: true,
+			Secure:   r.URL.Scheme == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	url := cfg.AuthCodeURL(signedState, oauth2.S256ChallengeOption(verifier), oauth2.SetAuthURLParam("nonce", nonce))
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
+
+func (h *AuthHandlers) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.oidcProvider(w, r)
+	if !ok {
+		return
+	}
+	cookie := func(name string) string {
+		if c, err := r.Cookie(name); err == nil {
+			return c.Value
+		}
+		return ""
+	}
+	state, nonce, verifier := cookie(oidcStateCookie), cookie(oidcNonceCookie), cookie(oidcVerifierCookie)
+	for _, name := range []string{oidcStateCookie, oidcNonceCookie, oidcVerifierCookie} {
+		http.SetCookie(w, &http.Cookie{Name: name, Value: "", Path: "/oauth/oidc/", Expires: time.Now().Add(-time.Hour), HttpOnly: true})
+	}
+
+	query := r.URL.Query()
+	if state == "" || state != query.Get("state") {
+		http.Error(w, "Invalid state parameter", http.StatusBadRequest)
+		return
+	}
+	verified, err := c.verify(state, "state", time.Now())
+	if err != nil {
+		http.Error(w, "Invalid state parameter", http.StatusBadRequest)
+		return
+	}
+	if e := query.Get("error"); e != "" {
+		http.Error(w, "Identity provider returned "+e+": "+query.Get("error_description"), http.StatusBadRequest)
+		return
+	}
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "No authorization code", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	cfg, issuer, err := c.config(ctx)
+	if err != nil {
+		slog.Error("OIDC discovery failed", "provider", c.provider.Name, "error", err)
+		http.Error(w, "Identity provider is unavailable", http.StatusBadGateway)
+		return
+	}
+	token, err := cfg.Exchange(context.WithValue(ctx, oauth2.HTTPClient, oidcHTTPClient), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		http.Error(w, "Failed to exchange token: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	rawIDToken, _ := token.Extra("id_token").(string)
+	claims, err := parseIDToken(rawIDToken, issuer, c.provider.ClientID, nonce, time.Now(), func(kid string) (crypto.PublicKey, error) {
+		return c.publicKey(ctx, kid)
+	})
+	if err != nil {
+		http.Error(w, "Invalid ID token: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	identity := OIDCIdentity{Provider: c.provider.Name, Issuer: issuer, Subject: claims.Subject, Email: claims.Email, Name: claims.Name, Token: token}
+	value, err := json.Marshal(identity)
+	if err != nil {
+		http.Error(w, "Failed to marshal identity: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	identity.Token = nil
+
+	link := verified.Link
+	if link == nil {
+		h.oidcLogin(w, r, c, identity, value)
+		return
+	}
+
+	// The credential belongs to the linking key's user, in their tenant.
+	ctx = dao.WithScope(dao.WithTenant(ctx, link.TenantUID), link.UserUID, link.HouseholdUID)
+	userID := link.UserUID
+	credential := dao.Credentials{ID: uuid.NewString(), UserUID: userID, CredentialType: c.credentialType(), Value: value}
+	if existingCred, err := h.dao.GetCredentialsByUserAndType(ctx, userID, c.credentialType()); err == nil {
+		_, err = h.dao.UpdateCredentials(ctx, existingCred.ID, credential)
+		if err != nil {
+			http.Error(w, "Failed to update credential: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else if _, err := h.dao.CreateCredentials(ctx, credential); err != nil {
+		http.Error(w, "Failed to create credential: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("OIDC account linked", "provider", c.provider.Name, "user_id", userID, "subject", claims.Subject)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "user": identity})
+}
+
+// oidcLogin issues an API key to the user the account is linked to. The
+// ID token has been verified, so the account may be looked up in every
+// tenant; the key is created in its user's.
+func (h *AuthHandlers) oidcLogin(w http.ResponseWriter, r *http.Request, c *oidcClient, identity OIDCIdentity, value json.RawMessage) {
+	ctx := dao.WithAllTenants(r.Context())
+	linked, err := h.dao.ListCredentials(ctx, dao.ListOptions{
+		Limit:       1,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: "WHERE credential_type = $1 AND value->>'issuer' = $2 AND value->>'subject' = $3",
+		WhereArgs:   []any{c.credentialType(), identity.Issuer, identity.Subject},
+	})
+	if err != nil {
+		http.Error(w, "Failed to look up account: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(linked) == 0 {
+		http.Error(w, "No user is linked to this account; link it with POST /oauth/oidc/"+c.provider.Name+"/link first", http.StatusForbidden)
+		return
+	}
+	cred := linked[0]
+	cred.Value = value
+	if _, err := h.dao.UpdateCredentials(ctx, cred.ID, cred); err != nil {
+		http.Error(w, "Failed to update credential: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		http.Error(w, "Failed to generate key", http.StatusInternalServerError)
+		return
+	}
+	apiKey, err := h.dao.CreateAPIKey(ctx, dao.APIKeys{
+		UserUID: cred.UserUID,
+		Name:    "Login with " + c.provider.Name,
+		KeyHash: hashAPIKey(key),
+		Scopes:  []string{ScopeMCPWrite},
+	})
+	if err != nil {
+		http.Error(w, "Failed to create API key: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("OIDC login", "provider", c.provider.Name, "user_id", cred.UserUID, "api_key_uid", apiKey.UID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(OIDCLoginResponse{UserUID: cred.UserUID, Identity: identity, Key: key, APIKey: apiKey})
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// oidcTestKey signs the test provider's ID tokens.
+var oidcTestKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+// signIDToken signs claims as a compact JWS with key, an *rsa.PrivateKey
+// (RS256) or *ecdsa.PrivateKey (ES256).
+func signIDToken(t *testing.T, key crypto.Signer, kid string, claims map[string]any) string {
+	alg := "RS256"
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var sig []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func fakeIDToken(t *testing.T, claims map[string]any) string {
+	return signIDToken(t, oidcTestKey(), "key-1", claims)
+}
+
+// oidcServer is an identity provider that issues an ID token for "sub-1"
+// once the PKCE verifier matches the challenge it was sent.
+func oidcServer(t *testing.T, challenge, nonce *string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(oidcDiscovery{Issuer: srv.URL, AuthorizationEndpoint: srv.URL + "/authorize", TokenEndpoint: srv.URL + "/token", JWKSURI: srv.URL + "/jwks"})
+		case "/jwks":
+			pub := oidcTestKey().PublicKey
+			_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+				"kid": "key-1", "kty": "RSA", "use": "sig",
+				"n": base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			}}})
+		case "/token":
+			_ = r.ParseForm()
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if r.PostForm.Get("code") != "code-1" || base64.RawURLEncoding.EncodeToString(sum[:]) != *challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access-1",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+				"id_token": fakeIDToken(t, map[string]any{
+					"iss": srv.URL, "sub": "sub-1", "aud": "assistant", "exp": time.Now().Add(time.Hour).Unix(),
+					"nonce": *nonce, "email": "ada@example.com", "name": "Ada",
+				}),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// oidcRoundTrip starts a login at the handler and returns its callback's
+// response, as the provider would redirect the browser back.
+func oidcRoundTrip(t *testing.T, h http.Handler, start string, challenge, nonce *string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, start, nil))
+	require.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	query := location.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	*challenge, *nonce = query.Get("code_challenge"), query.Get("nonce")
+
+	req := httptest.NewRequest(http.MethodGet, "/oidc/authentik/callback?code=code-1&state="+url.QueryEscape(query.Get("state")), nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOIDCLinkAndLogin(t *testing.T) {
+	var challenge, nonce string
+	srv := oidcServer(t, &challenge, &nonce)
+
+	authDAO := mocks.NewMockauthDAO(t)
+	h := NewAuthHandlers(AuthConfig{
+		BaseURL:       "https://assistant.example.com",
+		OIDCProviders: []OIDCProvider{{Name: "authentik", Issuer: srv.URL, ClientID: "assistant", ClientSecret: "secret"}},
+	}, authDAO)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oidc/keycloak", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Only a key that may write can ask for a link, and the link names its
+	// user.
+	linkURL := func(ctx context.Context) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/oidc/authentik/link", nil).WithContext(ctx))
+		return rec
+	}
+	assert.Equal(t, http.StatusUnauthorized, linkURL(context.Background()).Code)
+	assert.Equal(t, http.StatusForbidden, linkURL(WithIdentity(context.Background(), Identity{UserUID: "user-1", Scopes: []string{ScopeMCPRead}})).Code)
+	rec = linkURL(WithIdentity(context.Background(), Identity{UserUID: "user-1", HouseholdUID: "house-1", TenantUID: "tenant-1", Scopes: []string{ScopeMCPWrite}}))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var link OIDCLinkResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &link))
+	require.True(t, strings.HasPrefix(link.URL, "https://assistant.example.com/oauth/oidc/authentik?link="), link.URL)
+
+	// Linking stores the account as a credential of the link's user, in
+	// their tenant.
+	inTenant := mock.MatchedBy(func(ctx context.Context) bool {
+		tenant, _ := postgres.TenantFromContext(ctx)
+		return tenant == "tenant-1"
+	})
+	authDAO.On("GetCredentialsByUserAndType", inTenant, "user-1", "OIDC_AUTHENTIK").Return(postgres.Credentials{}, assert.AnError)
+	authDAO.On("CreateCredentials", inTenant, mock.MatchedBy(func(c postgres.Credentials) bool {
+		var identity OIDCIdentity
+		_ = json.Unmarshal(c.Value, &identity)
+		return c.UserUID == "user-1" && c.CredentialType == "OIDC_AUTHENTIK" &&
+			identity.Subject == "sub-1" && identity.Issuer == srv.URL && identity.Token.AccessToken == "access-1"
+	})).Return(postgres.Credentials{}, nil).Once()
+	rec = oidcRoundTrip(t, h, strings.TrimPrefix(link.URL, "https://assistant.example.com/oauth"), &challenge, &nonce)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"email":"ada@example.com"`)
+	assert.NotContains(t, rec.Body.String(), "access-1")
+
+	// A forged or altered link is refused before the browser leaves.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oidc/authentik?link=forged", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oidc/authentik?link="+url.QueryEscape(link.URL[strings.Index(link.URL, "=")+1:]+"x"), nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Logging in finds the linked user, in any tenant, and issues them an
+	// API key. A user_id in the query no longer links anything.
+	authDAO.On("ListCredentials", mock.MatchedBy(postgres.AllTenants), mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{"OIDC_AUTHENTIK", srv.URL, "sub-1"}, o.WhereArgs)
+	})).Return([]postgres.Credentials{{ID: "cred-1", UserUID: "user-1", CredentialType: "OIDC_AUTHENTIK"}}, nil).Once()
+	authDAO.On("UpdateCredentials", mock.Anything, "cred-1", mock.Anything).Return(postgres.Credentials{}, nil)
+	var storedHash string
+	authDAO.On("CreateAPIKey", mock.MatchedBy(postgres.AllTenants), mock.MatchedBy(func(k postgres.APIKeys) bool {
+		storedHash = k.KeyHash
+		return k.UserUID == "user-1" && k.Name == "Login with authentik"
+	})).Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1"}, nil)
+	rec = oidcRoundTrip(t, h, "/oidc/authentik?user_id=user-2", &challenge, &nonce)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var login OIDCLoginResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+	assert.Equal(t, "user-1", login.UserUID)
+	assert.True(t, strings.HasPrefix(login.Key, apiKeyPrefix))
+	assert.Equal(t, hashAPIKey(login.Key), storedHash)
+
+	// An account nobody linked can't log in.
+	authDAO.On("ListCredentials", mock.Anything, mock.Anything).Return([]postgres.Credentials{}, nil)
+	rec = oidcRoundTrip(t, h, "/oidc/authentik", &challenge, &nonce)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// A callback without the state it was sent with is refused.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oidc/authentik/callback?code=code-1&state=forged", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestParseIDToken(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	valid := map[string]any{"iss": "https://id.example.com", "sub": "sub-1", "aud": []string{"other", "assistant"}, "exp": now.Add(time.Minute).Unix(), "nonce": "n-1"}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keys := map[string]crypto.PublicKey{"key-1": &oidcTestKey().PublicKey, "key-2": &ecKey.PublicKey}
+	keyFor := func(kid string) (crypto.PublicKey, error) {
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		return nil, assert.AnError
+	}
+	claims, err := parseIDToken(fakeIDToken(t, valid), "https://id.example.com", "assistant", "n-1", now, keyFor)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", claims.Subject)
+	claims, err = parseIDToken(signIDToken(t, ecKey, "key-2", valid), "https://id.example.com", "assistant", "n-1", now, keyFor)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", claims.Subject)
+
+	// Tokens not signed by the provider's key are refused.
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = parseIDToken(signIDToken(t, otherKey, "key-2", valid), "https://id.example.com", "assistant", "n-1", now, keyFor)
+	assert.ErrorContains(t, err, "signature doesn't match")
+	_, err = parseIDToken(signIDToken(t, ecKey, "key-1", valid), "https://id.example.com", "assistant", "n-1", now, keyFor)
+	assert.ErrorContains(t, err, "doesn't match the signing key")
+	unsigned := strings.Split(fakeIDToken(t, valid), ".")
+	unsigned[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-1"}`))
+	_, err = parseIDToken(strings.Join(unsigned, "."), "https://id.example.com", "assistant", "n-1", now, keyFor)
+	assert.ErrorContains(t, err, "unsupported algorithm")
+
+	tests := []struct {
+		name    string
+		change  map[string]any
+		wantErr string
+	}{
+		{"other issuer", map[string]any{"iss": "https://evil.example.com"}, "issuer"},
+		{"other audience", map[string]any{"aud": "other"}, "not for this client"},
+		{"expired", map[string]any{"exp": now.Add(-time.Minute).Unix()}, "expired"},
+		{"replayed", map[string]any{"nonce": "n-0"}, "nonce"},
+		{"no subject", map[string]any{"sub": ""}, "subject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := map[string]any{}
+			for k, v := range valid {
+				c[k] = v
+			}
+			for k, v := range tt.change {
+				c[k] = v
+			}
+			_, err := parseIDToken(fakeIDToken(t, c), "https://id.example.com", "assistant", "n-1", now, keyFor)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err = parseIDToken("not-a-jwt", "https://id.example.com", "assistant", "n-1", now, keyFor)
+	assert.Error(t, err)
+}
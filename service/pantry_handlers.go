@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pbdeuchler/assistant-server/barcode"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+)
+
+// pantryExpiringSoon is how far ahead get_briefing looks for pantry items
+// about to expire.
+const pantryExpiringSoon = 3 * 24 * time.Hour
+
+type pantryDAO interface {
+	CreatePantryItem(ctx context.Context, p dao.PantryItem) (dao.PantryItem, error)
+	GetPantryItem(ctx context.Context, uid string) (dao.PantryItem, error)
+	ListPantryItems(ctx context.Context, options dao.ListOptions) ([]dao.PantryItem, error)
+	UpdatePantryItem(ctx context.Context, uid string, p dao.UpdatePantryItem) (dao.PantryItem, error)
+	DeletePantryItem(ctx context.Context, uid string) error
+}
+
+type PantryHandlers struct {
+	dao        pantryDAO
+	classifier GroceryClassifier
+	barcodes   barcode.Provider
+}
+
+// PantryOption configures the pantry router.
+type PantryOption func(*PantryHandlers)
+
+// WithPantryClassifier sets how items saved without a category are
+// categorized, DefaultGroceryRules unless set.
+func WithPantryClassifier(c GroceryClassifier) PantryOption {
+	return func(h *PantryHandlers) { h.classifier = c }
+}
+
+// WithBarcodeLookup enables GET /pantry/lookup, which resolves barcodes
+// with p.
+func WithBarcodeLookup(p barcode.Provider) PantryOption {
+	return func(h *PantryHandlers) { h.barcodes = p }
+}
+
+func NewPantry(dao pantryDAO, opts ...PantryOption) http.Handler {
+	h := &PantryHandlers{dao: dao, classifier: DefaultGroceryRules}
+	for _, opt := range opts {
+		opt(h)
+	}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.create)
+	r.Get("/lookup", h.lookup)
+	r.Get("/{uid}", h.get)
+	r.Put("/{uid}", h.update)
+	r.Delete("/{uid}", h.delete)
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *PantryHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var p dao.PantryItem
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := validatePantryItem(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	categorizePantryItem(r.Context(), h.classifier, &p.Item, &p.Category)
+	out, err := h.dao.CreatePantryItem(r.Context(), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *PantryHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.PantryItem, error) { return h.dao.GetPantryItem(ctx, uid) },
+		h.dao.ListPantryItems, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *PantryHandlers) update(w http.ResponseWriter, r *http.Request) {
+	var p dao.UpdatePantryItem
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := validatePantryUpdate(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	recategorizePantryItem(r.Context(), h.classifier, &p)
+	out, err := h.dao.UpdatePantryItem(r.Context(), chi.URLParam(r, "uid"), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *PantryHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeletePantryItem(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PantryHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, PantryFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, PantryFilters.Filters)
+	if !ok {
+		return
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListPantryItems(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// BarcodeLookup is a scanned product and the pantry item it makes.
+type BarcodeLookup struct {
+	Product    barcode.Product   `json:"product"`
+	PantryItem ScannedPantryItem `json:"pantry_item"`
+}
+
+// ScannedPantryItem is one pack of a scanned product, ready to be sent to
+// POST /pantry once it has a household_uid.
+type ScannedPantryItem struct {
+	Item     string   `json:"item"`
+	Quantity *float64 `json:"quantity,omitempty"`
+	Unit     string   `json:"unit,omitempty"`
+	Category string   `json:"category"`
+}
+
+// lookup resolves ?barcode= to a product, for scanning groceries into the
+// pantry.
+func (h *PantryHandlers) lookup(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.barcodes == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "barcode lookup is not configured"})
+		return
+	}
+	code, err := barcode.Normalize(r.URL.Query().Get("barcode"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	product, err := h.barcodes.Lookup(r.Context(), code)
+	if errors.Is(err, barcode.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	encodeResponse(w, r, BarcodeLookup{Product: product, PantryItem: h.scannedItem(r.Context(), product)})
+}
+
+// scannedItem is the pantry item for one pack of product. The pack size is
+// kept when it is in a unit the pantry knows, and the category comes from
+// the product's name or, failing that, its database categories.
+func (h *PantryHandlers) scannedItem(ctx context.Context, product barcode.Product) ScannedPantryItem {
+	p := ScannedPantryItem{Item: product.Name, Quantity: product.Quantity, Unit: product.Unit}
+	if validatePantryAmount(p.Quantity, &p.Unit) != nil {
+		p.Quantity, p.Unit = nil, ""
+	}
+	p.Category = classifyGroceries(ctx, h.classifier, []string{p.Item})[p.Item]
+	if p.Category != otherCategory {
+		return p
+	}
+	categories := classifyGroceries(ctx, h.classifier, product.Categories)
+	for _, name := range product.Categories {
+		if categories[name] != otherCategory {
+			p.Category = categories[name]
+			break
+		}
+	}
+	return p
+}
+
+// validatePantryItem checks a new pantry item and normalizes its unit to the
+// measurement package's name for it, so "cups" is stored as "cup".
+func validatePantryItem(p *dao.PantryItem) error {
+	p.Item = strings.TrimSpace(p.Item)
+	if p.Item == "" {
+		return errors.New("item is required")
+	}
+	if p.HouseholdUID == "" {
+		return errors.New("household_uid is required")
+	}
+	if err := validGroceryCategory(&p.Category); err != nil {
+		return err
+	}
+	return validatePantryAmount(p.Quantity, &p.Unit)
+}
+
+func validatePantryUpdate(p *dao.UpdatePantryItem) error {
+	if p.Item != nil && strings.TrimSpace(*p.Item) == "" {
+		return errors.New("item must not be empty")
+	}
+	if err := validGroceryCategory(p.Category); err != nil {
+		return err
+	}
+	return validatePantryAmount(p.Quantity, p.Unit)
+}
+
+// categorizePantryItem classifies item when it is saved without a category.
+func categorizePantryItem(ctx context.Context, c GroceryClassifier, item, category *string) {
+	if *category == "" {
+		*category = classifyGroceries(ctx, c, []string{*item})[*item]
+	}
+}
+
+// recategorizePantryItem classifies a renamed item again, unless the update
+// sets its category too.
+func recategorizePantryItem(ctx context.Context, c GroceryClassifier, p *dao.UpdatePantryItem) {
+	if p.Item == nil || (p.Category != nil && *p.Category != "") {
+		return
+	}
+	category := ""
+	categorizePantryItem(ctx, c, p.Item, &category)
+	p.Category = &category
+}
+
+func validatePantryAmount(quantity *float64, unit *string) error {
+	if quantity != nil && *quantity < 0 {
+		return errors.New("quantity must not be negative")
+	}
+	if unit == nil || *unit == "" {
+		return nil
+	}
+	u, err := measurement.ParseUnit(*unit)
+	if err != nil {
+		return err
+	}
+	if u == measurement.Each {
+		*unit = ""
+	} else {
+		*unit = u.Name
+	}
+	return nil
+}
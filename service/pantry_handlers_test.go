@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/barcode"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestPantryCreateValidates(t *testing.T) {
+	mockDAO := mocks.NewMockpantryDAO(t)
+	mockDAO.On("CreatePantryItem", mock.Anything, mock.MatchedBy(func(p postgres.PantryItem) bool {
+		return p.Item == "flour" && p.Unit == "kg" && *p.Quantity == 1.5
+	})).Return(postgres.PantryItem{UID: "p1", Item: "flour"}, nil)
+	handler := NewPantry(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/",
+		strings.NewReader(`{"item": " flour ", "household_uid": "house-1", "quantity": 1.5, "unit": "kilograms"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	for _, body := range []string{
+		`{"household_uid": "house-1"}`,
+		`{"item": "flour"}`,
+		`{"item": "flour", "household_uid": "house-1", "quantity": -1}`,
+		`{"item": "flour", "household_uid": "house-1", "unit": "handfuls"}`,
+		`{"item": "flour", "household_uid": "house-1", "category": "deli"}`,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestPantryCategorizes(t *testing.T) {
+	mockDAO := mocks.NewMockpantryDAO(t)
+	mockDAO.On("CreatePantryItem", mock.Anything, mock.MatchedBy(func(p postgres.PantryItem) bool {
+		return p.Item == "flour" && p.Category == "pantry"
+	})).Return(postgres.PantryItem{UID: "p1", Item: "flour", Category: "pantry"}, nil)
+	mockDAO.On("CreatePantryItem", mock.Anything, mock.MatchedBy(func(p postgres.PantryItem) bool {
+		return p.Item == "flour" && p.Category == "bakery"
+	})).Return(postgres.PantryItem{UID: "p2", Item: "flour", Category: "bakery"}, nil)
+	// A renamed item is categorized again; other updates leave it alone.
+	mockDAO.On("UpdatePantryItem", mock.Anything, "p1", mock.MatchedBy(func(p postgres.UpdatePantryItem) bool {
+		return *p.Item == "Cheddar" && *p.Category == "dairy"
+	})).Return(postgres.PantryItem{UID: "p1", Item: "Cheddar", Category: "dairy"}, nil)
+	mockDAO.On("UpdatePantryItem", mock.Anything, "p2", mock.MatchedBy(func(p postgres.UpdatePantryItem) bool {
+		return p.Item == nil && p.Category == nil
+	})).Return(postgres.PantryItem{UID: "p2"}, nil)
+	handler := NewPantry(mockDAO, WithPantryClassifier(GroceryRules{"dairy": {"cheddar"}, "pantry": {"flour"}}))
+
+	for _, req := range []struct{ method, target, body string }{
+		{"POST", "/", `{"item": "flour", "household_uid": "house-1"}`},
+		{"POST", "/", `{"item": "flour", "household_uid": "house-1", "category": " Bakery"}`},
+		{"PUT", "/p1", `{"item": "Cheddar"}`},
+		{"PUT", "/p2", `{"quantity": 2}`},
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(req.method, req.target, strings.NewReader(req.body)))
+		assert.Equal(t, http.StatusOK, rr.Code, req.body)
+	}
+}
+
+func TestMCPHandlers_PantryTools(t *testing.T) {
+	mockDAO := mocks.NewMockpantryDAO(t)
+	mockDAO.On("CreatePantryItem", mock.Anything, mock.MatchedBy(func(p postgres.PantryItem) bool {
+		return p.Item == "milk" && p.HouseholdUID == "house-1" && p.Unit == "l" && p.Category == "dairy" && p.ExpiresOn.Equal(time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC))
+	})).Return(postgres.PantryItem{UID: "p1", Item: "milk"}, nil)
+	mockDAO.On("ListPantryItems", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.SortBy == "expires_on" && strings.Contains(o.WhereClause, "expires_on <=")
+	})).Return([]postgres.PantryItem{{UID: "p1", Item: "milk"}}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithPantry(mockDAO))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "add_pantry_item", map[string]any{
+		"item": "milk", "quantity": float64(2), "unit": "litres", "expires_on": "2025-08-30",
+	}), &body)
+	assert.Equal(t, "Added milk to the pantry", body["summary"])
+
+	decodeToolResult(t, h.callTool(ctx, "list_pantry", map[string]any{"expiring_within_days": float64(2)}), &body)
+	assert.Len(t, body["pantry_items"], 1)
+
+	assert.True(t, h.callTool(ctx, "add_pantry_item", map[string]any{"item": "milk", "expires_on": "next week"}).IsError)
+
+	withoutPantry := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutPantry.findTool("add_pantry_item")
+	assert.False(t, ok)
+}
+
+func TestMCPHandlers_GetBriefingExpiringPantryItems(t *testing.T) {
+	mockUserDAO := &MockUserDAO{}
+	mockUserDAO.On("GetUser", mock.Anything, "user-1").Return(postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}, nil)
+	mockHouseholdDAO := &MockHouseholdDAO{}
+	mockHouseholdDAO.On("GetHousehold", mock.Anything, "house-1").Return(postgres.Households{UID: "house-1"}, nil)
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	mockPantryDAO := mocks.NewMockpantryDAO(t)
+	soon := time.Now().Add(pantryExpiringSoon).Format(time.DateOnly)
+	mockPantryDAO.On("ListPantryItems", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{"house-1", soon}, o.WhereArgs) || assert.ObjectsAreEqual([]any{soon, "house-1"}, o.WhereArgs)
+	})).Return([]postgres.PantryItem{{UID: "p1", Item: "spinach"}}, nil)
+
+	h := NewMCP(mockTodoDAO, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, mockUserDAO, mockHouseholdDAO, WithPantry(mockPantryDAO))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "get_briefing", map[string]any{}), &body)
+	assert.Len(t, body["expiring_pantry_items"], 1)
+}
+
+type fakeBarcodes map[string]barcode.Product
+
+func (f fakeBarcodes) Lookup(_ context.Context, code string) (barcode.Product, error) {
+	if code == "5000000000005" {
+		return barcode.Product{}, errors.New("timeout")
+	}
+	p, ok := f[code]
+	if !ok {
+		return barcode.Product{}, barcode.ErrNotFound
+	}
+	return p, nil
+}
+
+func TestPantryLookup(t *testing.T) {
+	handler := NewPantry(mocks.NewMockpantryDAO(t), WithBarcodeLookup(fakeBarcodes{
+		"3017620422003": {Barcode: "3017620422003", Name: "Nutella", Quantity: floatPtr(400), Unit: "g", Categories: []string{"spreads", "sweet spreads"}},
+		"96385074":      {Barcode: "96385074", Name: "Semi-skimmed milk", Quantity: floatPtr(2), Unit: "pt"},
+		"036000291452":  {Barcode: "036000291452", Name: "Strawberry jam", Quantity: floatPtr(1), Unit: "jar"},
+	}))
+
+	lookup := func(code string) (int, map[string]any) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/lookup?barcode="+code, nil))
+		var body map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &body)
+		return rr.Code, body
+	}
+
+	code, body := lookup("96385074")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"item": "Semi-skimmed milk", "quantity": float64(2), "unit": "pint", "category": "dairy"}, body["pantry_item"])
+	assert.Equal(t, "96385074", body["product"].(map[string]any)["barcode"])
+
+	// A unit the pantry doesn't know loses the pack size.
+	_, body = lookup("0360-0029-1452")
+	assert.Equal(t, map[string]any{"item": "Strawberry jam", "category": "pantry"}, body["pantry_item"])
+
+	// Nothing in the name places it, so the categories are tried.
+	rules := NewPantry(mocks.NewMockpantryDAO(t), WithPantryClassifier(GroceryRules{"snacks": {"sweet spreads"}}),
+		WithBarcodeLookup(fakeBarcodes{"3017620422003": {Name: "Nutella", Categories: []string{"spreads", "sweet spreads"}}}))
+	rr := httptest.NewRecorder()
+	rules.ServeHTTP(rr, httptest.NewRequest("GET", "/lookup?barcode=3017620422003", nil))
+	assert.Contains(t, rr.Body.String(), `"category":"snacks"`)
+
+	code, _ = lookup("3017620422004")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, body = lookup("40000008")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, barcode.ErrNotFound.Error(), body["error"])
+	code, _ = lookup("5000000000005")
+	assert.Equal(t, http.StatusBadGateway, code)
+
+	rr = httptest.NewRecorder()
+	NewPantry(mocks.NewMockpantryDAO(t)).ServeHTTP(rr, httptest.NewRequest("GET", "/lookup?barcode=96385074", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+)
+
+// The benchmarks below cover what an assistant waits on: bootstrap when it
+// starts, then list_todos and other tools/call requests during its turns.
+// The DAOs answer from memory and request logging is off, so they measure
+// the server's own work: decoding, filtering, prompt compilation and
+// encoding. scripts/loadtest measures the same endpoints over the network
+// against a real database.
+
+// perfTodos is a household's worth of todos, a fifth of them overdue.
+func perfTodos() []postgres.Todo {
+	now := time.Now()
+	todos := make([]postgres.Todo, 50)
+	for i := range todos {
+		due := now.AddDate(0, 0, i-10)
+		todos[i] = fixtures.Todo(func(td *postgres.Todo) {
+			td.UID = fmt.Sprintf("todo-%d", i)
+			td.Title = fmt.Sprintf("Chore %d", i)
+			td.DueDate = &due
+			td.UserUID, td.HouseholdUID = strPtr("user-1"), strPtr("house-1")
+		})
+	}
+	return todos
+}
+
+type perfBootstrapDAO struct {
+	bootstrapDAO
+	todos []postgres.Todo
+	notes []postgres.Notes
+	prefs []postgres.Preferences
+}
+
+func newPerfBootstrapDAO() *perfBootstrapDAO {
+	d := &perfBootstrapDAO{todos: perfTodos()}
+	for i := range 30 {
+		d.notes = append(d.notes, fixtures.Note(func(n *postgres.Notes) {
+			n.ID = fmt.Sprintf("note-%d", i)
+			n.Key = fmt.Sprintf("note-%d", i)
+			n.Pinned = i < 3
+		}))
+	}
+	for i := range 10 {
+		d.prefs = append(d.prefs, fixtures.Preference(func(p *postgres.Preferences) { p.Key = fmt.Sprintf("pref-%d", i) }))
+	}
+	return d
+}
+
+func (d *perfBootstrapDAO) GetUserBySlackUserUID(context.Context, string) (postgres.Users, error) {
+	return fixtures.User(func(u *postgres.Users) { u.UID, u.HouseholdUID = "user-1", strPtr("house-1") }), nil
+}
+
+func (d *perfBootstrapDAO) GetCredentialsByUserUID(context.Context, string) ([]postgres.Credentials, error) {
+	return nil, nil
+}
+
+func (d *perfBootstrapDAO) GetTodosByUserUID(context.Context, string) ([]postgres.Todo, error) {
+	return d.todos, nil
+}
+
+func (d *perfBootstrapDAO) GetNotesByUserUID(context.Context, string) ([]postgres.Notes, error) {
+	return d.notes, nil
+}
+
+func (d *perfBootstrapDAO) GetPreferencesByUserUID(context.Context, string) ([]postgres.Preferences, error) {
+	return d.prefs, nil
+}
+
+func (d *perfBootstrapDAO) GetHousehold(context.Context, string) (postgres.Households, error) {
+	return fixtures.Household(func(h *postgres.Households) { h.UID = "house-1" }), nil
+}
+
+func (d *perfBootstrapDAO) GetToolPoliciesForUser(context.Context, string, *string) ([]postgres.ToolPolicy, error) {
+	return nil, nil
+}
+
+type perfTodoDAO struct {
+	todoDAO
+	todos []postgres.Todo
+}
+
+func (d *perfTodoDAO) ListTodos(_ context.Context, options postgres.ListOptions) ([]postgres.Todo, error) {
+	return d.todos[:min(options.Limit, len(d.todos))], nil
+}
+
+func newPerfMCP() *MCPHandlers {
+	h := NewMCP(&perfTodoDAO{todos: perfTodos()}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	h.logger = slog.New(slog.DiscardHandler)
+	return h
+}
+
+func BenchmarkBootstrap(b *testing.B) {
+	h := &bootstrapHandlers{dao: newPerfBootstrapDAO(), prompt: MarkdownPrompt{Budget: defaultPromptBudget}}
+	req := httptest.NewRequest("GET", "/?slack_id=U123", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		rr := httptest.NewRecorder()
+		h.bootstrap(rr, req)
+		if rr.Code != http.StatusOK {
+			b.Fatalf("bootstrap returned %d: %s", rr.Code, rr.Body)
+		}
+	}
+}
+
+func BenchmarkListTodos(b *testing.B) {
+	h := newPerfMCP()
+	ctx := identityContext("user-1", "house-1")
+	args := map[string]any{"limit": float64(50), "status": "planned"}
+	b.ReportAllocs()
+	for b.Loop() {
+		if result := h.callTool(ctx, "list_todos", args); result.IsError {
+			b.Fatalf("list_todos failed: %+v", result.Content)
+		}
+	}
+}
+
+func BenchmarkToolsCall(b *testing.B) {
+	h := newPerfMCP()
+	w := postMCP(h, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": LatestProtocolVersion,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "bench", "version": "1.0.0"},
+		},
+	}, nil)
+	session := w.Header().Get("Mcp-Session-Id")
+	body, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "list_todos", "arguments": map[string]any{"limit": 20}},
+	})
+	b.ReportAllocs()
+	for b.Loop() {
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+		req.Header.Set("Mcp-Session-Id", session)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			b.Fatalf("tools/call returned %d: %s", rr.Code, rr.Body)
+		}
+	}
+}
+
+// perfBudgets are the most each benchmark may take per request. They sit
+// well above what the benchmarks take on a laptop, so that a loaded CI
+// runner passes, but low enough to catch a quadratic loop or a regexp
+// compiled per request. Raise one only with the reason in the commit.
+var perfBudgets = map[string]struct {
+	bench  func(*testing.B)
+	perOp  time.Duration
+	allocs int64
+}{
+	"bootstrap":  {BenchmarkBootstrap, 2 * time.Millisecond, 1300},
+	"list_todos": {BenchmarkListTodos, time.Millisecond, 350},
+	"tools/call": {BenchmarkToolsCall, 2 * time.Millisecond, 450},
+}
+
+// TestPerformanceBudgets runs the benchmarks and fails those over budget.
+// Timings mean nothing under the race detector, and the benchmarks take a
+// few seconds, so it is skipped then and with -short; make test-perf runs
+// it alone.
+func TestPerformanceBudgets(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("performance budgets need a full, race-free run")
+	}
+	for name, budget := range perfBudgets {
+		t.Run(name, func(t *testing.T) {
+			res := testing.Benchmark(budget.bench)
+			if res.N == 0 {
+				t.Fatal("benchmark failed")
+			}
+			perOp := time.Duration(res.NsPerOp())
+			t.Logf("%v/op, %d allocs/op (budget %v, %d allocs)", perOp, res.AllocsPerOp(), budget.perOp, budget.allocs)
+			if perOp > budget.perOp {
+				t.Errorf("%v/op is over the budget of %v", perOp, budget.perOp)
+			}
+			if res.AllocsPerOp() > budget.allocs {
+				t.Errorf("%d allocs/op is over the budget of %d", res.AllocsPerOp(), budget.allocs)
+			}
+		})
+	}
+}
@@ -15,12 +15,23 @@ type preferencesDAO interface {
 	ListPreferences(ctx context.Context, options dao.ListOptions) ([]dao.Preferences, error)
 	UpdatePreferences(ctx context.Context, key, specifier string, p dao.Preferences) (dao.Preferences, error)
 	DeletePreferences(ctx context.Context, key, specifier string) error
+	GetPreferencesBulk(ctx context.Context, keys []dao.PreferenceKey) ([]dao.Preferences, error)
+	SetPreferencesBulk(ctx context.Context, prefs []dao.Preferences) ([]dao.Preferences, error)
 }
 
-type PreferencesHandlers struct{ dao preferencesDAO }
+type PreferencesHandlers struct {
+	dao     preferencesDAO
+	schemas dataSchemaDAO
+}
+
+// PreferencesOption configures the preferences router.
+type PreferencesOption func(*PreferencesHandlers)
 
-func NewPreferences(dao preferencesDAO) http.Handler {
-	h := &PreferencesHandlers{dao}
+func NewPreferences(dao preferencesDAO, opts ...PreferencesOption) http.Handler {
+	h := &PreferencesHandlers{dao: dao}
+	for _, opt := range opts {
+		opt(h)
+	}
 	r := chi.NewRouter()
 	r.Post("/", h.create)
 	r.Get("/{key}/{specifier}", h.get)
@@ -36,6 +47,9 @@ func (h *PreferencesHandlers) create(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if !checkData(w, r, h.schemas, dao.DataSchemaPreferences, p.Key, p.Data) {
+		return
+	}
 	out, err := h.dao.CreatePreferences(r.Context(), p)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -47,12 +61,13 @@ func (h *PreferencesHandlers) create(w http.ResponseWriter, r *http.Request) {
 func (h *PreferencesHandlers) get(w http.ResponseWriter, r *http.Request) {
 	key := chi.URLParam(r, "key")
 	specifier := chi.URLParam(r, "specifier")
-	out, err := h.dao.GetPreferences(r.Context(), key, specifier)
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Preferences, error) { return h.dao.GetPreferences(ctx, key, specifier) },
+		h.dao.ListPreferences, "WHERE key = $1 AND specifier = $2", key, specifier)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		writeFieldsError(w, err, http.StatusNotFound)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, out)
 }
 
 func (h *PreferencesHandlers) update(w http.ResponseWriter, r *http.Request) {
@@ -63,6 +78,9 @@ func (h *PreferencesHandlers) update(w http.ResponseWriter, r *http.Request) {
 	}
 	key := chi.URLParam(r, "key")
 	specifier := chi.URLParam(r, "specifier")
+	if !checkData(w, r, h.schemas, dao.DataSchemaPreferences, key, p.Data) {
+		return
+	}
 	out, err := h.dao.UpdatePreferences(r.Context(), key, specifier, p)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -83,7 +101,10 @@ func (h *PreferencesHandlers) delete(w http.ResponseWriter, r *http.Request) {
 
 func (h *PreferencesHandlers) list(w http.ResponseWriter, r *http.Request) {
 	params := ParseListParams(r, PreferencesFilters.SortFields)
-	whereClause, whereArgs := BuildWhereClause(params.Filters, PreferencesFilters.Filters)
+	whereClause, whereArgs, ok := whereFromParams(w, params, PreferencesFilters.Filters)
+	if !ok {
+		return
+	}
 
 	options := dao.ListOptions{
 		Limit:       params.Limit,
@@ -92,12 +113,13 @@ func (h *PreferencesHandlers) list(w http.ResponseWriter, r *http.Request) {
 		SortDir:     params.SortDir,
 		WhereClause: whereClause,
 		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
 	}
 
 	out, err := h.dao.ListPreferences(r.Context(), options)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeFieldsError(w, err, http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, out)
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type projectDAO interface {
+	CreateProject(ctx context.Context, p dao.Project) (dao.Project, error)
+	GetProject(ctx context.Context, uid string) (dao.Project, error)
+	ListProjects(ctx context.Context, options dao.ListOptions) ([]dao.Project, error)
+	UpdateProject(ctx context.Context, uid string, p dao.UpdateProject) (dao.Project, error)
+	DeleteProject(ctx context.Context, uid string) error
+	MoveTodosToProject(ctx context.Context, projectUID string, todoUIDs []string) ([]dao.Todo, error)
+	RemoveTodosFromProject(ctx context.Context, projectUID string, todoUIDs []string) ([]dao.Todo, error)
+}
+
+type ProjectHandlers struct{ dao projectDAO }
+
+func NewProjects(dao projectDAO) http.Handler {
+	h := &ProjectHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.create)
+	r.Get("/{uid}", h.get)
+	r.Put("/{uid}", h.update)
+	r.Delete("/{uid}", h.delete)
+	r.Post("/{uid}/todos", h.moveTodos)
+	r.Delete("/{uid}/todos/{todo_uid}", h.removeTodo)
+	r.Get("/", h.list)
+	return r
+}
+
+func validateProject(p *dao.Project) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if p.HouseholdUID == "" {
+		return errors.New("household_uid is required")
+	}
+	return nil
+}
+
+func (h *ProjectHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var p dao.Project
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := validateProject(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateProject(r.Context(), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *ProjectHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Project, error) { return h.dao.GetProject(ctx, uid) },
+		h.dao.ListProjects, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// update renames, redescribes, archives or unarchives a project.
+func (h *ProjectHandlers) update(w http.ResponseWriter, r *http.Request) {
+	var p dao.UpdateProject
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if p.Name != nil && strings.TrimSpace(*p.Name) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "name must not be empty"})
+		return
+	}
+	out, err := h.dao.UpdateProject(r.Context(), chi.URLParam(r, "uid"), p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *ProjectHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteProject(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, ProjectFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, ProjectFilters.Filters)
+	if !ok {
+		return
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListProjects(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// moveTodos puts the todos in the body's todo_uids in the project, taking
+// them out of any other, and returns the todos it moved.
+func (h *ProjectHandlers) moveTodos(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		TodoUIDs []string `json:"todo_uids"`
+	}
+	if json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if len(in.TodoUIDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "todo_uids is required"})
+		return
+	}
+	out, err := h.dao.MoveTodosToProject(r.Context(), chi.URLParam(r, "uid"), in.TodoUIDs)
+	switch {
+	case errors.Is(err, dao.ErrUnknownProject):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case errors.Is(err, dao.ErrProjectArchived):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// removeTodo takes a todo out of the project. The todo itself is kept.
+func (h *ProjectHandlers) removeTodo(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.dao.RemoveTodosFromProject(r.Context(), chi.URLParam(r, "uid"), []string{chi.URLParam(r, "todo_uid")}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
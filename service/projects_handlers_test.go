@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProjectHandlers(t *testing.T) {
+	mockDAO := mocks.NewMockprojectDAO(t)
+	mockDAO.On("CreateProject", mock.Anything, postgres.Project{Name: "Garage", HouseholdUID: "house-1"}).
+		Return(postgres.Project{UID: "p1", Name: "Garage", HouseholdUID: "house-1"}, nil)
+	mockDAO.On("GetProject", mock.Anything, "p1").Return(postgres.Project{UID: "p1", Name: "Garage", DoneTodos: 1, TotalTodos: 3}, nil)
+	mockDAO.On("ListProjects", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE archived = $1 AND household_uid = $2" && o.WhereArgs[0] == false
+	})).Return([]postgres.Project{{UID: "p1"}}, nil)
+	mockDAO.On("UpdateProject", mock.Anything, "missing", mock.Anything).Return(postgres.Project{}, pgx.ErrNoRows)
+	mockDAO.On("MoveTodosToProject", mock.Anything, "p1", []string{"t1", "t2"}).Return([]postgres.Todo{{UID: "t1"}, {UID: "t2"}}, nil)
+	mockDAO.On("MoveTodosToProject", mock.Anything, "old", []string{"t1"}).Return(nil, fmt.Errorf("%w: Old", postgres.ErrProjectArchived))
+	mockDAO.On("RemoveTodosFromProject", mock.Anything, "p1", []string{"t1"}).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	handler := NewProjects(mockDAO)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rr
+	}
+
+	rr := serve("POST", "/", `{"name": " Garage ", "household_uid": "house-1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"p1"`)
+	rr = serve("POST", "/", `{"household_uid": "house-1"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "name is required")
+
+	rr = serve("GET", "/p1", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"done_todos":1`)
+	assert.Contains(t, rr.Body.String(), `"total_todos":3`)
+
+	assert.Equal(t, http.StatusOK, serve("GET", "/?household_uid=house-1&archived=false", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("GET", "/?archived=maybe", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, serve("PUT", "/missing", `{"archived": true}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve("PUT", "/p1", `{"name": " "}`).Code)
+
+	rr = serve("POST", "/p1/todos", `{"todo_uids": ["t1", "t2"]}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"t2"`)
+	assert.Equal(t, http.StatusConflict, serve("POST", "/old/todos", `{"todo_uids": ["t1"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve("POST", "/p1/todos", `{}`).Code)
+
+	assert.Equal(t, http.StatusNoContent, serve("DELETE", "/p1/todos/t1", "").Code)
+}
+
+func TestProjectFilterOnTodos(t *testing.T) {
+	filters, err := ParseFilters(map[string]string{"project_uid": "p1"}, TodoFilters.Filters)
+	assert.NoError(t, err)
+	where, args := BuildWhereClause(filters, TodoFilters.Filters)
+	assert.Equal(t, "WHERE project_uid = $1", where)
+	assert.Equal(t, []any{"p1"}, args)
+}
+
+func TestMCPHandlers_Projects(t *testing.T) {
+	mockDAO := mocks.NewMockprojectDAO(t)
+	mockDAO.On("CreateProject", mock.Anything, postgres.Project{Name: "Garage", HouseholdUID: "house-1"}).
+		Return(postgres.Project{UID: "p1", Name: "Garage", HouseholdUID: "house-1"}, nil)
+	mockDAO.On("ListProjects", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND archived = $2"
+	})).Return([]postgres.Project{{UID: "p1", DoneTodos: 1, TotalTodos: 2}}, nil)
+	mockDAO.On("MoveTodosToProject", mock.Anything, "p1", []string{"t1", "t9"}).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	mockDAO.On("RemoveTodosFromProject", mock.Anything, "p1", []string{"t1"}).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	mockDAO.On("MoveTodosToProject", mock.Anything, "nope", []string{"t1"}).Return(nil, fmt.Errorf("%w: nope", postgres.ErrUnknownProject))
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithProjects(mockDAO))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "create_project", map[string]any{"name": "Garage"}), &body)
+	assert.Equal(t, "Created project Garage", body["summary"])
+
+	decodeToolResult(t, h.callTool(ctx, "list_projects", map[string]any{}), &body)
+	assert.Equal(t, float64(2), body["projects"].([]any)[0].(map[string]any)["total_todos"])
+
+	decodeToolResult(t, h.callTool(ctx, "move_todos_to_project", map[string]any{"project_id": "p1", "todo_ids": []any{"t1", "t9"}}), &body)
+	assert.Equal(t, "Moved 1 todos into the project", body["summary"])
+	assert.Equal(t, []any{"t9"}, body["not_found"])
+
+	decodeToolResult(t, h.callTool(ctx, "move_todos_to_project", map[string]any{"project_id": "p1", "todo_ids": []any{"t1"}, "remove": true}), &body)
+	assert.Equal(t, "Took 1 todos out of the project", body["summary"])
+
+	assert.True(t, h.callTool(ctx, "move_todos_to_project", map[string]any{"project_id": "nope", "todo_ids": []any{"t1"}}).IsError)
+	assert.True(t, h.callTool(ctx, "move_todos_to_project", map[string]any{"project_id": "p1"}).IsError)
+
+	withoutProjects := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutProjects.findTool("create_project")
+	assert.False(t, ok)
+}
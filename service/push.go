@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+// reminderBatchSize caps how many todos or notes falling due are read per
+// check.
+const reminderBatchSize = 500
+
+// PushNotifier sends push notifications to every registered device of a
+// user or household, forgetting devices whose tokens the platform rejects.
+// Users who are away, or whose notification preferences don't want a
+// category pushed right now, are skipped.
+type PushNotifier struct {
+	devices deviceDAO
+	prefs   preferencesDAO
+	away    awayDAO
+	pushers map[string]notify.Pusher
+	now     func() time.Time
+}
+
+// NewPushNotifier sends through pushers, keyed by platform ("apns" or
+// "fcm"). Devices on a platform without a pusher are skipped.
+func NewPushNotifier(devices deviceDAO, prefs preferencesDAO, away awayDAO, pushers map[string]notify.Pusher) *PushNotifier {
+	return &PushNotifier{devices: devices, prefs: prefs, away: away, pushers: pushers, now: time.Now}
+}
+
+func (n *PushNotifier) NotifyUser(ctx context.Context, userUID, category string, p notify.Push) error {
+	devices, err := n.devices.ListDevicesByUserUID(ctx, userUID)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, devices, category, p)
+}
+
+func (n *PushNotifier) NotifyHousehold(ctx context.Context, householdUID, category string, p notify.Push) error {
+	devices, err := n.devices.ListDevicesByHouseholdUID(ctx, householdUID)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, devices, category, p)
+}
+
+// send pushes to each device whose owner is here and wants category pushed,
+// returning the first failure after trying them all.
+func (n *PushNotifier) send(ctx context.Context, devices []dao.Device, category string, p notify.Push) error {
+	now := n.now()
+	wants := map[string]bool{}
+	var first error
+	for _, d := range devices {
+		pusher, ok := n.pushers[d.Platform]
+		if !ok {
+			continue
+		}
+		want, seen := wants[d.UserUID]
+		if !seen {
+			want = n.wants(ctx, d.UserUID, category, now)
+			wants[d.UserUID] = want
+		}
+		if !want {
+			continue
+		}
+		p.Token = d.Token
+		err := pusher.Push(ctx, p)
+		if errors.Is(err, notify.ErrUnregistered) {
+			slog.Info("Removing unregistered device", "device_uid", d.UID, "user_uid", d.UserUID)
+			err = n.devices.DeleteDevice(ctx, d.UID)
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (n *PushNotifier) wants(ctx context.Context, userUID, category string, now time.Time) bool {
+	away, err := n.away.ListAwayPeriodsByUserUID(ctx, userUID, awayDay(now))
+	if err != nil {
+		slog.Error("Failed to list away periods", "user_uid", userUID, "error", err)
+	}
+	if len(awayOn(away, now)) > 0 {
+		return false
+	}
+	prefs, err := loadNotificationPreferences(ctx, n.prefs, userUID)
+	if err != nil {
+		slog.Error("Invalid notification preferences", "user_uid", userUID, "error", err)
+	}
+	return prefs.SendsInstant(ChannelPush, category, now)
+}
+
+// TodoReminders pushes a reminder to a todo's owner when it falls due: its
+// user, or everyone in its household for household todos. Users who chose
+// digest delivery for todo reminders see them in their digest instead, and
+// users who are away get none.
+type TodoReminders struct {
+	todos    todoDAO
+	notifier *PushNotifier
+	interval time.Duration
+}
+
+func NewTodoReminders(todos todoDAO, notifier *PushNotifier, interval time.Duration) *TodoReminders {
+	return &TodoReminders{todos: todos, notifier: notifier, interval: interval}
+}
+
+// Job checks for todos falling due every interval, each run covering the
+// interval up to when it was scheduled. Runs missed while no server is up
+// aren't queued, so todos falling due then get no reminder, and a failed
+// run isn't retried, as some of its reminders may have gone out.
+func (t *TodoReminders) Job() Job {
+	return Job{
+		Name:     "todo_reminders",
+		Schedule: Every(t.interval),
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return t.SendDue(ctx, run.RunAt.Add(-t.interval), run.RunAt)
+		},
+	}
+}
+
+// SendDue reminds owners of the open todos due in [from, to). Failures for
+// one todo are logged and don't stop the others; SendDue then reports how
+// many failed.
+func (t *TodoReminders) SendDue(ctx context.Context, from, to time.Time) error {
+	todos, err := t.todos.ListTodos(ctx, dao.ListOptions{
+		Limit:       reminderBatchSize,
+		SortBy:      "due_date",
+		SortDir:     "ASC",
+		WhereClause: "WHERE marked_complete IS NULL AND due_date >= $1 AND due_date < $2",
+		WhereArgs:   []any{from, to},
+	})
+	if err != nil {
+		return fmt.Errorf("listing todos for reminders: %w", err)
+	}
+	failed := 0
+	for _, todo := range todos {
+		p := notify.Push{Title: todo.Title, Body: "Due now", Data: map[string]string{"todo_uid": todo.UID}}
+		switch {
+		case todo.UserUID != nil && *todo.UserUID != "":
+			err = t.notifier.NotifyUser(ctx, *todo.UserUID, CategoryTodoReminders, p)
+		case todo.HouseholdUID != nil && *todo.HouseholdUID != "":
+			err = t.notifier.NotifyHousehold(ctx, *todo.HouseholdUID, CategoryTodoReminders, p)
+		default:
+			continue
+		}
+		if err != nil {
+			slog.Error("Failed to send todo reminder", "todo_uid", todo.UID, "error", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d todo reminders failed to send", failed)
+	}
+	return nil
+}
+
+// noteReminderBodyMax caps, in runes, the part of a note shown in its
+// reminder.
+const noteReminderBodyMax = 120
+
+// NoteReminders pushes a reminder to a note's owner when its remind_at comes
+// round, so a note like "passport expires in June" can be acted on in time.
+type NoteReminders struct {
+	notes    notesDAO
+	notifier *PushNotifier
+	interval time.Duration
+}
+
+func NewNoteReminders(notes notesDAO, notifier *PushNotifier, interval time.Duration) *NoteReminders {
+	return &NoteReminders{notes: notes, notifier: notifier, interval: interval}
+}
+
+// Job checks for note reminders falling due every interval, on the same
+// terms as TodoReminders.Job.
+func (n *NoteReminders) Job() Job {
+	return Job{
+		Name:     "note_reminders",
+		Schedule: Every(n.interval),
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return n.SendDue(ctx, run.RunAt.Add(-n.interval), run.RunAt)
+		},
+	}
+}
+
+// SendDue reminds owners of the notes whose remind_at is in [from, to).
+// Private notes are only ever pushed to their owner; other notes without an
+// owner go to their household. Archived notes are skipped.
+func (n *NoteReminders) SendDue(ctx context.Context, from, to time.Time) error {
+	notes, err := n.notes.ListNotes(ctx, dao.ListOptions{
+		Limit:       reminderBatchSize,
+		SortBy:      "remind_at",
+		SortDir:     "ASC",
+		WhereClause: "WHERE archived_at IS NULL AND remind_at >= $1 AND remind_at < $2",
+		WhereArgs:   []any{from, to},
+	})
+	if err != nil {
+		return fmt.Errorf("listing notes for reminders: %w", err)
+	}
+	failed := 0
+	for _, note := range notes {
+		p := notify.Push{Title: note.Key, Body: noteReminderBody(note.Data), Data: map[string]string{"note_id": note.ID}}
+		switch {
+		case note.UserUID != nil && *note.UserUID != "":
+			err = n.notifier.NotifyUser(ctx, *note.UserUID, CategoryNoteReminders, p)
+		case note.Visibility != dao.NoteVisibilityPrivate && note.HouseholdUID != nil && *note.HouseholdUID != "":
+			err = n.notifier.NotifyHousehold(ctx, *note.HouseholdUID, CategoryNoteReminders, p)
+		default:
+			continue
+		}
+		if err != nil {
+			slog.Error("Failed to send note reminder", "note_id", note.ID, "error", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d note reminders failed to send", failed)
+	}
+	return nil
+}
+
+// noteReminderBody is the first line of a note's data, cut short.
+func noteReminderBody(data string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(data), "\n")
+	if runes := []rune(line); len(runes) > noteReminderBodyMax {
+		return string(runes[:noteReminderBodyMax-1]) + "…"
+	}
+	if line == "" {
+		return "Reminder"
+	}
+	return line
+}
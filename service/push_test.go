@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePusher records pushes, failing for tokens in errs.
+type fakePusher struct {
+	pushed []notify.Push
+	errs   map[string]error
+}
+
+func (f *fakePusher) Push(_ context.Context, p notify.Push) error {
+	if err := f.errs[p.Token]; err != nil {
+		return err
+	}
+	f.pushed = append(f.pushed, p)
+	return nil
+}
+
+func TestPushNotifierRemovesUnregisteredDevices(t *testing.T) {
+	devices := mocks.NewMockdeviceDAO(t)
+	devices.On("ListDevicesByUserUID", mock.Anything, "user-1").Return([]postgres.Device{
+		{UID: "d1", Platform: notify.PlatformAPNs, Token: "phone"},
+		{UID: "d2", Platform: notify.PlatformAPNs, Token: "old-phone"},
+		{UID: "d3", Platform: notify.PlatformFCM, Token: "tablet"},
+		{UID: "d4", Platform: notify.PlatformFCM, Token: "broken"},
+	}, nil)
+	devices.On("DeleteDevice", mock.Anything, "d2").Return(nil)
+
+	apns := &fakePusher{errs: map[string]error{"old-phone": notify.ErrUnregistered}}
+	fcm := &fakePusher{errs: map[string]error{"broken": errors.New("fcm: 500")}}
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, mock.Anything).Return(postgres.Preferences{}, errors.New("not found"))
+	n := NewPushNotifier(devices, prefs, noneAway(t), map[string]notify.Pusher{notify.PlatformAPNs: apns, notify.PlatformFCM: fcm})
+
+	err := n.NotifyUser(t.Context(), "user-1", CategoryTodoReminders, notify.Push{Title: "Bins"})
+	assert.EqualError(t, err, "fcm: 500")
+	require.Len(t, apns.pushed, 1)
+	assert.Equal(t, notify.Push{Token: "phone", Title: "Bins"}, apns.pushed[0])
+	require.Len(t, fcm.pushed, 1)
+	assert.Equal(t, "tablet", fcm.pushed[0].Token)
+}
+
+func TestTodoRemindersSendDue(t *testing.T) {
+	from := time.Date(2025, 8, 18, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+	user, house := "user-1", "house-1"
+
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE marked_complete IS NULL AND due_date >= $1 AND due_date < $2" &&
+			o.WhereArgs[0] == from && o.WhereArgs[1] == to
+	})).Return([]postgres.Todo{
+		{UID: "t1", Title: "Call the dentist", UserUID: &user, HouseholdUID: &house},
+		{UID: "t2", Title: "Bins", HouseholdUID: &house},
+		{UID: "t3", Title: "Nobody's"},
+	}, nil)
+	devices := mocks.NewMockdeviceDAO(t)
+	devices.On("ListDevicesByUserUID", mock.Anything, "user-1").Return([]postgres.Device{{Platform: notify.PlatformAPNs, Token: "mia"}}, nil)
+	devices.On("ListDevicesByHouseholdUID", mock.Anything, "house-1").Return([]postgres.Device{
+		{Platform: notify.PlatformAPNs, Token: "mia"},
+		{Platform: notify.PlatformAPNs, Token: "sam"},
+	}, nil)
+
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, mock.Anything).Return(postgres.Preferences{}, errors.New("not found"))
+
+	pusher := &fakePusher{}
+	NewTodoReminders(todos, NewPushNotifier(devices, prefs, noneAway(t), map[string]notify.Pusher{notify.PlatformAPNs: pusher}), time.Minute).
+		SendDue(t.Context(), from, to)
+
+	require.Len(t, pusher.pushed, 3)
+	assert.Equal(t, notify.Push{Token: "mia", Title: "Call the dentist", Body: "Due now", Data: map[string]string{"todo_uid": "t1"}}, pusher.pushed[0])
+	assert.Equal(t, []string{"mia", "sam"}, []string{pusher.pushed[1].Token, pusher.pushed[2].Token})
+	assert.Equal(t, "Bins", pusher.pushed[2].Title)
+}
+
+func TestNoteRemindersSendDue(t *testing.T) {
+	from := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+	user, house := "user-1", "house-1"
+
+	notes := &MockNotesDAO{}
+	notes.On("ListNotes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE archived_at IS NULL AND remind_at >= $1 AND remind_at < $2" &&
+			o.WhereArgs[0] == from && o.WhereArgs[1] == to && o.SortBy == "remind_at"
+	})).Return([]postgres.Notes{
+		{ID: "n1", Key: "passport", Data: "Expires in June\nNumber 123", UserUID: &user, HouseholdUID: &house, Visibility: postgres.NoteVisibilityPrivate},
+		{ID: "n2", Key: "boiler service", Data: strings.Repeat("x", 200), HouseholdUID: &house, Visibility: postgres.NoteVisibilityHousehold},
+		{ID: "n3", Key: "diary", HouseholdUID: &house, Visibility: postgres.NoteVisibilityPrivate},
+	}, nil)
+	devices := mocks.NewMockdeviceDAO(t)
+	devices.On("ListDevicesByUserUID", mock.Anything, "user-1").Return([]postgres.Device{{Platform: notify.PlatformAPNs, Token: "mia"}}, nil)
+	devices.On("ListDevicesByHouseholdUID", mock.Anything, "house-1").Return([]postgres.Device{{Platform: notify.PlatformAPNs, Token: "sam"}}, nil)
+
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, mock.Anything).Return(postgres.Preferences{}, errors.New("not found"))
+
+	pusher := &fakePusher{}
+	require.NoError(t, NewNoteReminders(notes, NewPushNotifier(devices, prefs, noneAway(t), map[string]notify.Pusher{notify.PlatformAPNs: pusher}), time.Minute).
+		SendDue(t.Context(), from, to))
+
+	// The private note without an owner reaches nobody.
+	require.Len(t, pusher.pushed, 2)
+	assert.Equal(t, notify.Push{Token: "mia", Title: "passport", Body: "Expires in June", Data: map[string]string{"note_id": "n1"}}, pusher.pushed[0])
+	assert.Equal(t, "sam", pusher.pushed[1].Token)
+	assert.Equal(t, noteReminderBodyMax, utf8.RuneCountInString(pusher.pushed[1].Body))
+}
+
+func TestPushNotifierFollowsPreferences(t *testing.T) {
+	devices := mocks.NewMockdeviceDAO(t)
+	devices.On("ListDevicesByHouseholdUID", mock.Anything, "house-1").Return([]postgres.Device{
+		{UserUID: "mia", Platform: notify.PlatformAPNs, Token: "mia-phone"},
+		{UserUID: "mia", Platform: notify.PlatformFCM, Token: "mia-tablet"},
+		{UserUID: "sam", Platform: notify.PlatformAPNs, Token: "sam-phone"},
+		{UserUID: "ana", Platform: notify.PlatformAPNs, Token: "ana-phone"},
+		{UserUID: "leo", Platform: notify.PlatformAPNs, Token: "leo-phone"},
+		{UserUID: "zoe", Platform: notify.PlatformAPNs, Token: "zoe-phone"},
+	}, nil)
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "mia").Return(postgres.Preferences{}, errors.New("not found"))
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "sam").
+		Return(postgres.Preferences{Data: `{"quiet_hours": {"start": "22:00", "end": "07:00", "timezone": "Europe/London"}}`}, nil)
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "ana").
+		Return(postgres.Preferences{Data: `{"categories": {"todo_reminders": "digest"}}`}, nil)
+	prefs.On("GetPreferences", mock.Anything, NotificationPreferencesKey, "leo").
+		Return(postgres.Preferences{Data: `{"channels": {"push": false}}`}, nil)
+
+	now := time.Date(2025, 8, 18, 22, 30, 0, 0, time.UTC)
+	away := mocks.NewMockawayDAO(t)
+	away.On("ListAwayPeriodsByUserUID", mock.Anything, "zoe", awayDay(now)).Return([]postgres.AwayPeriod{
+		{UserUID: "zoe", StartsOn: awayDay(now).AddDate(0, 0, -2), EndsOn: awayDay(now)},
+	}, nil)
+	away.On("ListAwayPeriodsByUserUID", mock.Anything, mock.Anything, mock.Anything).Return([]postgres.AwayPeriod{}, nil)
+
+	apns, fcm := &fakePusher{}, &fakePusher{}
+	n := NewPushNotifier(devices, prefs, away, map[string]notify.Pusher{notify.PlatformAPNs: apns, notify.PlatformFCM: fcm})
+	// 23:30 in London, inside Sam's quiet hours.
+	n.now = func() time.Time { return now }
+
+	require.NoError(t, n.NotifyHousehold(t.Context(), "house-1", CategoryTodoReminders, notify.Push{Title: "Bins"}))
+	require.Len(t, apns.pushed, 1)
+	assert.Equal(t, "mia-phone", apns.pushed[0].Token)
+	require.Len(t, fcm.pushed, 1)
+	// Preferences are read once per user, not once per device, and not at
+	// all for Zoe, who is away.
+	prefs.AssertNumberOfCalls(t, "GetPreferences", 4)
+}
+
+// noneAway is an awayDAO with nobody away.
+func noneAway(t *testing.T) *mocks.MockawayDAO {
+	away := mocks.NewMockawayDAO(t)
+	away.On("ListAwayPeriodsByUserUID", mock.Anything, mock.Anything, mock.Anything).Return([]postgres.AwayPeriod{}, nil).Maybe()
+	return away
+}
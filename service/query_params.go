@@ -1,10 +1,16 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
 )
 
 type ListParams struct {
@@ -12,7 +18,10 @@ type ListParams struct {
 	Offset  int
 	SortBy  string
 	SortDir string
+	// Filters holds the remaining query parameters; ParseFilters turns the
+	// ones naming filterable columns into Filters.
 	Filters map[string]string
+	Fields  []string
 }
 
 func ParseListParams(r *http.Request, allowedSortFields []string) ListParams {
@@ -49,6 +58,8 @@ func ParseListParams(r *http.Request, allowedSortFields []string) ListParams {
 		params.SortDir = sortDir
 	}
 
+	params.Fields = parseFields(r)
+
 	for key, values := range r.URL.Query() {
 		if len(values) > 0 && !isReservedParam(key) {
 			params.Filters[key] = values[0]
@@ -59,7 +70,7 @@ func ParseListParams(r *http.Request, allowedSortFields []string) ListParams {
 }
 
 func isReservedParam(key string) bool {
-	reserved := []string{"limit", "offset", "sort_by", "sort_dir"}
+	reserved := []string{"limit", "offset", "sort_by", "sort_dir", "fields"}
 	for _, r := range reserved {
 		if key == r {
 			return true
@@ -68,139 +79,389 @@ func isReservedParam(key string) bool {
 	return false
 }
 
-func BuildWhereClause(filters map[string]string, allowedFilters []string) (string, []interface{}) {
-	if len(filters) == 0 {
-		return "", nil
+// Op is the comparison a Filter makes. Columns and Ops both come from the
+// fixed lists below, so the only user input in a WHERE clause built from
+// Filters is its values, which are always bound as parameters.
+type Op string
+
+const (
+	OpEq      Op = "="
+	OpNe      Op = "!="
+	OpGt      Op = ">"
+	OpGe      Op = ">="
+	OpLt      Op = "<"
+	OpLe      Op = "<="
+	OpNull    Op = "IS NULL"
+	OpNotNull Op = "IS NOT NULL"
+	// OpMatch is a case-insensitive substring match. The value is matched
+	// literally: % and _ in it are not wildcards.
+	OpMatch Op = "ILIKE"
+	// OpContains keeps rows whose array column holds every value.
+	OpContains Op = "@>"
+	// OpOverlaps keeps rows whose array column holds any of the values.
+	OpOverlaps Op = "&&"
+	// OpIn keeps rows whose column equals one of the values.
+	OpIn Op = "= ANY"
+	// OpWithin keeps todos within a dao.TodoLocation's radius of its point.
+	OpWithin Op = "within"
+)
+
+// likeEscaper escapes the characters LIKE patterns treat specially, with \
+// as the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Filter is one condition of a list query.
+type Filter struct {
+	Column string
+	Op     Op
+	Value  any
+}
+
+// FilterColumns are the columns a list can be filtered on and the Ops each
+// accepts.
+type FilterColumns map[string][]Op
+
+var (
+	eqOps    = []Op{OpEq, OpNe, OpNull, OpNotNull}
+	rangeOps = []Op{OpEq, OpNe, OpGt, OpGe, OpLt, OpLe, OpNull, OpNotNull}
+	matchOps = []Op{OpMatch, OpNe}
+	tagOps   = []Op{OpContains}
+)
+
+// ErrInvalidFilter is returned by ParseFilters when a filter uses an
+// operator its column doesn't accept.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ParseFilters turns query parameters into Filters on columns. A value may
+// start with >=, <=, >, < or != or be IS NULL or NOT NULL; otherwise it is
+// matched exactly, or as a substring for columns that accept OpMatch. tags
+// takes a comma-separated list, actionable true or false, and near
+// lat,lon,radius. Parameters naming other columns are ignored.
+func ParseFilters(params map[string]string, columns FilterColumns) ([]Filter, error) {
+	var out []Filter
+	for _, column := range slices.Sorted(maps.Keys(params)) {
+		ops, ok := columns[column]
+		if !ok {
+			continue
+		}
+		f, err := parseFilter(column, params[column])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFilter, column, err)
+		}
+		if f.Op == OpEq && slices.Contains(ops, OpMatch) {
+			f.Op = OpMatch
+		}
+		if !slices.Contains(ops, f.Op) {
+			return nil, fmt.Errorf("%w: %s can't be compared with %s", ErrInvalidFilter, column, f.Op)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func parseFilter(column, value string) (Filter, error) {
+	switch column {
+	case "tags":
+		return Filter{Column: column, Op: OpContains, Value: splitTags(value)}, nil
+	case "actionable":
+		return Filter{Column: column, Op: OpEq, Value: value == "true"}, nil
+	case "near":
+		lat, lon, radius, err := parseNear(value)
+		if err != nil {
+			return Filter{}, err
+		}
+		return Filter{Column: column, Op: OpWithin, Value: dao.TodoLocation{Lat: lat, Lon: lon, RadiusM: radius}}, nil
+	}
+	switch value {
+	case "IS NULL":
+		return Filter{Column: column, Op: OpNull}, nil
+	case "NOT NULL":
+		return Filter{Column: column, Op: OpNotNull}, nil
+	}
+	for _, op := range []Op{OpGe, OpLe, OpNe, OpGt, OpLt} {
+		if rest, ok := strings.CutPrefix(value, string(op)); ok {
+			return typedFilter(column, op, rest)
+		}
+	}
+	return typedFilter(column, OpEq, value)
+}
+
+// typedFilter converts value to the type column holds. Priorities may be
+// labels, so priority=>=high works, statuses must be valid and archived
+// must be true or false.
+func typedFilter(column string, op Op, value string) (Filter, error) {
+	if column == "priority" {
+		p, err := dao.ParsePriority(value)
+		if err != nil {
+			return Filter{}, err
+		}
+		return Filter{Column: column, Op: op, Value: p}, nil
+	}
+	if column == "status" {
+		status, err := dao.ParseTodoStatus(value)
+		if err != nil {
+			return Filter{}, err
+		}
+		return Filter{Column: column, Op: op, Value: status}, nil
+	}
+	if column == "archived" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return Filter{}, errors.New("must be true or false")
+		}
+		return Filter{Column: column, Op: op, Value: archived}, nil
+	}
+	return Filter{Column: column, Op: op, Value: value}, nil
+}
+
+func splitTags(value string) []string {
+	tags := strings.Split(value, ",")
+	for i, tag := range tags {
+		tags[i] = strings.TrimSpace(tag)
+	}
+	return tags
+}
+
+// whereFromParams builds the WHERE clause for a list request's filters,
+// answering 400 when one is invalid. ok is false once it has answered.
+func whereFromParams(w http.ResponseWriter, params ListParams, columns FilterColumns) (string, []any, bool) {
+	filters, err := ParseFilters(params.Filters, columns)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return "", nil, false
+	}
+	where, args := BuildWhereClause(filters, columns)
+	return where, args, true
+}
+
+// withStatus narrows a list query built by BuildWhereClause to rows with
+// status, for entities whose statuses aren't todo statuses.
+func withStatus(whereClause string, whereArgs []any, status string) (string, []any) {
+	whereArgs = append(whereArgs, status)
+	cond := fmt.Sprintf("status = $%d", len(whereArgs))
+	if whereClause == "" {
+		return "WHERE " + cond, whereArgs
 	}
+	return whereClause + " AND " + cond, whereArgs
+}
 
+// BuildWhereClause renders filters as a WHERE clause and its arguments.
+// Filters on columns, or with Ops, that columns doesn't allow are dropped, as
+// are filters whose value is the wrong type for their Op.
+func BuildWhereClause(filters []Filter, columns FilterColumns) (string, []any) {
 	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	for key, value := range filters {
-		// Handle tag filtering specially
-		if key == "tags" {
-			// Handle comma-separated tags from URL parameters
-			var tagList []string
-			if strings.Contains(value, ",") {
-				tagList = strings.Split(value, ",")
-				for i, tag := range tagList {
-					tagList[i] = strings.TrimSpace(tag)
-				}
-			} else {
-				tagList = []string{strings.TrimSpace(value)}
-			}
-			conditions = append(conditions, fmt.Sprintf("tags @> $%d", argIndex))
-			args = append(args, tagList)
-			argIndex++
+	var args []any
+	for _, f := range filters {
+		if !slices.Contains(columns[f.Column], f.Op) {
 			continue
 		}
-
-		// Handle regular filters
-		for _, allowed := range allowedFilters {
-			if key == allowed {
-				// Handle operators in the value (like ">=3", "<5", etc.)
-				if strings.HasPrefix(value, ">=") {
-					conditions = append(conditions, fmt.Sprintf("%s >= $%d", key, argIndex))
-					args = append(args, value[2:]) // Remove ">=" prefix
-				} else if strings.HasPrefix(value, "<=") {
-					conditions = append(conditions, fmt.Sprintf("%s <= $%d", key, argIndex))
-					args = append(args, value[2:]) // Remove "<=" prefix
-				} else if strings.HasPrefix(value, ">") {
-					conditions = append(conditions, fmt.Sprintf("%s > $%d", key, argIndex))
-					args = append(args, value[1:]) // Remove ">" prefix
-				} else if strings.HasPrefix(value, "<") {
-					conditions = append(conditions, fmt.Sprintf("%s < $%d", key, argIndex))
-					args = append(args, value[1:]) // Remove "<" prefix
-				} else if strings.HasPrefix(value, "!=") {
-					conditions = append(conditions, fmt.Sprintf("%s != $%d", key, argIndex))
-					args = append(args, value[2:]) // Remove "!=" prefix
-				} else if value == "IS NULL" {
-					conditions = append(conditions, fmt.Sprintf("%s IS NULL", key))
-					// Don't increment argIndex since we don't add an arg
-					continue
-				} else if value == "NOT NULL" {
-					conditions = append(conditions, fmt.Sprintf("%s IS NOT NULL", key))
-					// Don't increment argIndex since we don't add an arg
-					continue
-				} else {
-					// Handle partial matching for title fields
-					if key == "title" {
-						conditions = append(conditions, fmt.Sprintf("%s ILIKE $%d", key, argIndex))
-						args = append(args, "%"+value+"%") // Add wildcards for partial matching
-					} else {
-						// Default equality
-						conditions = append(conditions, fmt.Sprintf("%s = $%d", key, argIndex))
-						args = append(args, value)
-					}
-				}
-				argIndex++
-				break
+		n := len(args) + 1
+		switch {
+		// actionable=true keeps todos with no incomplete blockers, false only blocked ones
+		case f.Column == "actionable":
+			actionable, ok := f.Value.(bool)
+			if !ok {
+				continue
+			}
+			if actionable {
+				conditions = append(conditions, "NOT "+todoBlockedCondition)
+			} else {
+				conditions = append(conditions, todoBlockedCondition)
+			}
+		case f.Op == OpWithin:
+			near, ok := f.Value.(dao.TodoLocation)
+			if !ok {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf(todoNearCondition, n, n+1, n+2))
+			args = append(args, near.Lat, near.Lon, near.RadiusM)
+		case f.Op == OpNull || f.Op == OpNotNull:
+			conditions = append(conditions, fmt.Sprintf("%s %s", f.Column, f.Op))
+		case f.Op == OpContains || f.Op == OpOverlaps:
+			tags, ok := f.Value.([]string)
+			if !ok {
+				continue
 			}
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d", f.Column, f.Op, n))
+			args = append(args, tags)
+		case f.Op == OpIn:
+			values, ok := f.Value.([]string)
+			if !ok {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf("%s = ANY($%d)", f.Column, n))
+			args = append(args, values)
+		case f.Op == OpMatch:
+			s, ok := f.Value.(string)
+			if !ok {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, f.Column, n))
+			args = append(args, "%"+likeEscaper.Replace(s)+"%")
+		default:
+			if f.Value == nil {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d", f.Column, f.Op, n))
+			args = append(args, f.Value)
 		}
 	}
 
 	if len(conditions) == 0 {
 		return "", nil
 	}
-
 	return "WHERE " + strings.Join(conditions, " AND "), args
 }
 
-// BuildFiltersFromMCP creates a filter map from MCP tool arguments
-func BuildFiltersFromMCP(arguments map[string]any, supportedFilters []string) map[string]string {
-	filters := make(map[string]string)
-	
-	for _, filterName := range supportedFilters {
-		if value, ok := arguments[filterName]; ok {
-			switch v := value.(type) {
-			case string:
-				if v != "" {
-					filters[filterName] = v
-				}
-			case float64:
-				filters[filterName] = strconv.FormatFloat(v, 'f', -1, 64)
-			case int:
-				filters[filterName] = strconv.Itoa(v)
+// BuildFiltersFromMCP creates Filters from MCP tool arguments. Values are
+// taken literally: strings are matched exactly, or as a substring for columns
+// that accept OpMatch, and never parsed for operators. A priority must be a
+// label or number.
+func BuildFiltersFromMCP(arguments map[string]any, columns FilterColumns) ([]Filter, error) {
+	var out []Filter
+	for _, column := range slices.Sorted(maps.Keys(columns)) {
+		var value string
+		switch v := arguments[column].(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			value = strconv.Itoa(v)
+		}
+		if value == "" {
+			continue
+		}
+		f, err := typedFilter(column, OpEq, value)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case column == "tags":
+			f = Filter{Column: column, Op: OpContains, Value: splitTags(value)}
+		case column == "near":
+			near, err := parseFilter(column, value)
+			if err != nil {
+				return nil, err
 			}
+			f = near
+		case slices.Contains(columns[column], OpMatch):
+			f.Op = OpMatch
 		}
+		out = append(out, f)
 	}
-	
+
 	// Handle special boolean filters
 	if completedOnly, ok := arguments["completed_only"].(bool); ok && completedOnly {
-		filters["completed_by"] = "NOT NULL"
+		out = append(out, Filter{Column: "marked_complete", Op: OpNotNull})
 	}
 	if pendingOnly, ok := arguments["pending_only"].(bool); ok && pendingOnly {
-		filters["completed_by"] = "IS NULL"
+		out = append(out, Filter{Column: "marked_complete", Op: OpNull})
+	}
+	if actionable, ok := arguments["actionable"].(bool); ok {
+		out = append(out, Filter{Column: "actionable", Op: OpEq, Value: actionable})
 	}
-	
-	return filters
+	return out, nil
 }
 
 // Common filter configurations for each entity type
 type EntityFilters struct {
 	SortFields []string
-	Filters    []string
+	Filters    FilterColumns
 }
 
 var (
 	TodoFilters = EntityFilters{
 		SortFields: []string{"uid", "title", "priority", "due_date", "created_at", "updated_at", "user_uid", "household_uid", "completed_by"},
-		Filters:    []string{"title", "priority", "user_uid", "household_uid", "completed_by", "tags"},
+		Filters: FilterColumns{
+			"title":           matchOps,
+			"priority":        rangeOps,
+			"status":          eqOps,
+			"user_uid":        eqOps,
+			"household_uid":   eqOps,
+			"completed_by":    eqOps,
+			"project_uid":     eqOps,
+			"marked_complete": rangeOps,
+			"tags":            tagOps,
+			"actionable":      {OpEq},
+			"near":            {OpWithin},
+		},
 	}
-	
+
 	NotesFilters = EntityFilters{
-		SortFields: []string{"id", "key", "user_uid", "household_uid", "created_at", "updated_at"},
-		Filters:    []string{"key", "user_uid", "household_uid", "tags"},
+		SortFields: []string{"id", "key", "user_uid", "household_uid", "pinned", "sort_order", "created_at", "updated_at", "remind_at"},
+		Filters: FilterColumns{
+			"key":           eqOps,
+			"user_uid":      eqOps,
+			"household_uid": eqOps,
+			"tags":          tagOps,
+			"archived_at":   rangeOps,
+			"remind_at":     rangeOps,
+		},
+	}
+
+	TodoTemplateFilters = EntityFilters{
+		SortFields: []string{"uid", "name", "user_uid", "household_uid", "created_at", "updated_at"},
+		Filters:    FilterColumns{"name": eqOps, "user_uid": eqOps, "household_uid": eqOps},
+	}
+
+	ProjectFilters = EntityFilters{
+		SortFields: []string{"uid", "name", "household_uid", "archived", "done_todos", "total_todos", "created_at", "updated_at"},
+		Filters:    FilterColumns{"name": matchOps, "household_uid": eqOps, "archived": {OpEq, OpNe}},
+	}
+
+	PantryFilters = EntityFilters{
+		SortFields: []string{"uid", "item", "category", "expires_on", "household_uid", "created_at", "updated_at"},
+		Filters:    FilterColumns{"item": eqOps, "unit": eqOps, "category": eqOps, "expires_on": rangeOps, "household_uid": eqOps},
+	}
+
+	GroceryPurchaseFilters = EntityFilters{
+		SortFields: []string{"uid", "item", "store", "price_cents", "purchased_on", "household_uid", "created_at", "updated_at"},
+		Filters:    FilterColumns{"item": eqOps, "store": eqOps, "purchased_on": rangeOps, "household_uid": eqOps},
+	}
+
+	// A status filter would be checked as a todo status, so the deliveries
+	// and job runs handlers add theirs with withStatus.
+	DeliveryFilters = EntityFilters{
+		SortFields: []string{"uid", "channel", "recipient", "status", "attempts", "created_at", "updated_at"},
+		Filters:    FilterColumns{"channel": eqOps, "recipient": eqOps, "payload_hash": eqOps, "attempts": rangeOps, "created_at": rangeOps},
+	}
+
+	JobRunFilters = EntityFilters{
+		SortFields: []string{"uid", "name", "status", "attempts", "run_at", "started_at", "finished_at", "created_at", "updated_at"},
+		Filters:    FilterColumns{"name": eqOps, "attempts": rangeOps, "run_at": rangeOps, "finished_at": rangeOps, "created_at": rangeOps},
+	}
+
+	BackgroundsFilters = EntityFilters{
+		SortFields: []string{"key", "created_at", "updated_at"},
+		Filters:    FilterColumns{"key": eqOps},
 	}
-	
+
+	ToolPolicyFilters = EntityFilters{
+		SortFields: []string{"uid", "user_uid", "household_uid", "created_at", "updated_at"},
+		Filters:    FilterColumns{"user_uid": eqOps, "household_uid": eqOps},
+	}
+
 	PreferencesFilters = EntityFilters{
 		SortFields: []string{"key", "specifier", "created_at", "updated_at"},
-		Filters:    []string{"key", "specifier", "tags"},
+		Filters:    FilterColumns{"key": eqOps, "specifier": eqOps, "tags": tagOps},
 	}
-	
+
 	RecipesFilters = EntityFilters{
 		SortFields: []string{"id", "title", "genre", "rating", "prep_time", "cook_time", "total_time", "servings", "difficulty", "user_uid", "household_uid", "created_at", "updated_at"},
-		Filters:    []string{"title", "genre", "rating", "cook_time", "prep_time", "total_time", "servings", "difficulty", "user_uid", "household_uid", "tags"},
+		Filters: FilterColumns{
+			"title":         matchOps,
+			"genre":         eqOps,
+			"rating":        rangeOps,
+			"cook_time":     rangeOps,
+			"prep_time":     rangeOps,
+			"total_time":    rangeOps,
+			"servings":      rangeOps,
+			"difficulty":    rangeOps,
+			"user_uid":      eqOps,
+			"household_uid": eqOps,
+			"tags":          tagOps,
+		},
 	}
-)
\ No newline at end of file
+)
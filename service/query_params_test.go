@@ -2,10 +2,17 @@ package service
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -105,11 +112,8 @@ func TestParseListParams_InvalidSortDir(t *testing.T) {
 }
 
 func TestBuildWhereClause_NoFilters(t *testing.T) {
-	filters := map[string]string{}
-	allowedFilters := []string{"name", "status"}
-	
-	whereClause, args := BuildWhereClause(filters, allowedFilters)
-	
+	whereClause, args := BuildWhereClause(nil, TodoFilters.Filters)
+
 	if whereClause != "" {
 		t.Errorf("Expected empty where clause, got '%s'", whereClause)
 	}
@@ -119,46 +123,100 @@ func TestBuildWhereClause_NoFilters(t *testing.T) {
 }
 
 func TestBuildWhereClause_SingleFilter(t *testing.T) {
-	filters := map[string]string{"status": "active"}
-	allowedFilters := []string{"status", "name"}
-	
-	whereClause, args := BuildWhereClause(filters, allowedFilters)
-	
-	expected := "WHERE status = $1"
+	filters := []Filter{{Column: "household_uid", Op: OpEq, Value: "house-1"}}
+
+	whereClause, args := BuildWhereClause(filters, TodoFilters.Filters)
+
+	expected := "WHERE household_uid = $1"
 	if whereClause != expected {
 		t.Errorf("Expected '%s', got '%s'", expected, whereClause)
 	}
-	if len(args) != 1 || args[0] != "active" {
-		t.Errorf("Expected args ['active'], got %v", args)
+	if len(args) != 1 || args[0] != "house-1" {
+		t.Errorf("Expected args ['house-1'], got %v", args)
 	}
 }
 
 func TestBuildWhereClause_MultipleFilters(t *testing.T) {
-	filters := map[string]string{"status": "active", "name": "test"}
-	allowedFilters := []string{"status", "name"}
-	
-	whereClause, args := BuildWhereClause(filters, allowedFilters)
-	
-	if whereClause != "WHERE status = $1 AND name = $2" && whereClause != "WHERE name = $1 AND status = $2" {
-		t.Errorf("Unexpected where clause: '%s'", whereClause)
+	filters := []Filter{
+		{Column: "priority", Op: OpGe, Value: "3"},
+		{Column: "completed_by", Op: OpNull},
+		{Column: "title", Op: OpMatch, Value: "milk"},
+		{Column: "tags", Op: OpContains, Value: []string{"urgent"}},
 	}
-	if len(args) != 2 {
-		t.Errorf("Expected 2 args, got %d", len(args))
+
+	whereClause, args := BuildWhereClause(filters, TodoFilters.Filters)
+
+	assert.Equal(t, `WHERE priority >= $1 AND completed_by IS NULL AND title ILIKE $2 ESCAPE '\' AND tags @> $3`, whereClause)
+	assert.Equal(t, []any{"3", "%milk%", []string{"urgent"}}, args)
+}
+
+func TestBuildWhereClause_MatchIsLiteral(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"milk", "%milk%"},
+		{"_", `%\_%`},
+		{"100%", `%100\%%`},
+		{`C:\temp`, `%C:\\temp%`},
+	}
+
+	for _, test := range tests {
+		whereClause, args := BuildWhereClause([]Filter{{Column: "title", Op: OpMatch, Value: test.value}}, TodoFilters.Filters)
+		assert.Equal(t, `WHERE title ILIKE $1 ESCAPE '\'`, whereClause)
+		assert.Equal(t, []any{test.expected}, args, test.value)
 	}
 }
 
 func TestBuildWhereClause_DisallowedFilter(t *testing.T) {
-	filters := map[string]string{"status": "active", "password": "secret"}
-	allowedFilters := []string{"status"}
-	
-	whereClause, args := BuildWhereClause(filters, allowedFilters)
-	
-	expected := "WHERE status = $1"
+	filters := []Filter{
+		{Column: "household_uid", Op: OpEq, Value: "house-1"},
+		{Column: "password", Op: OpEq, Value: "secret"},
+		{Column: "title", Op: OpGt, Value: "a"},
+		{Column: "tags", Op: OpContains, Value: "not-a-list"},
+		{Column: "priority", Op: Op("= 1 OR 1"), Value: "1"},
+	}
+
+	whereClause, args := BuildWhereClause(filters, TodoFilters.Filters)
+
+	expected := "WHERE household_uid = $1"
 	if whereClause != expected {
 		t.Errorf("Expected '%s', got '%s'", expected, whereClause)
 	}
-	if len(args) != 1 || args[0] != "active" {
-		t.Errorf("Expected args ['active'], got %v", args)
+	if len(args) != 1 || args[0] != "house-1" {
+		t.Errorf("Expected args ['house-1'], got %v", args)
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	filters, err := ParseFilters(map[string]string{
+		"priority":      ">=high",
+		"completed_by":  "IS NULL",
+		"user_uid":      "NOT NULL",
+		"title":         "milk",
+		"tags":          "urgent, work",
+		"household_uid": "!=house-2",
+		"auto_tag":      "true",
+	}, TodoFilters.Filters)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Filter{
+		{Column: "completed_by", Op: OpNull},
+		{Column: "household_uid", Op: OpNe, Value: "house-2"},
+		{Column: "priority", Op: OpGe, Value: dao.PriorityHigh},
+		{Column: "tags", Op: OpContains, Value: []string{"urgent", "work"}},
+		{Column: "title", Op: OpMatch, Value: "milk"},
+		{Column: "user_uid", Op: OpNotNull},
+	}, filters)
+
+	for column, value := range map[string]string{"title": ">=a", "archived_at": "x", "near": "downtown", "priority": "<=5"} {
+		_, err := ParseFilters(map[string]string{column: value}, TodoFilters.Filters)
+		if column == "archived_at" {
+			// Columns that can't be filtered on are ignored, not rejected.
+			assert.NoError(t, err)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrInvalidFilter, column)
 	}
 }
 
@@ -181,25 +239,23 @@ func TestIsReservedParam(t *testing.T) {
 
 func TestBuildFiltersFromMCP(t *testing.T) {
 	tests := []struct {
-		name             string
-		arguments        map[string]any
-		supportedFilters []string
-		expectedFilters  map[string]string
+		name            string
+		arguments       map[string]any
+		expectedFilters []Filter
 	}{
 		{
-			name: "basic string filters",
+			name: "basic filters",
 			arguments: map[string]any{
 				"user_uid":      "user123",
 				"household_uid": "house456",
-				"priority":     float64(3),
-				"tags":         "urgent,work",
+				"priority":      float64(3),
+				"tags":          "urgent,work",
 			},
-			supportedFilters: []string{"user_uid", "household_uid", "priority", "tags"},
-			expectedFilters: map[string]string{
-				"user_uid":      "user123",
-				"household_uid": "house456",
-				"priority":     "3",
-				"tags":         "urgent,work",
+			expectedFilters: []Filter{
+				{Column: "household_uid", Op: OpEq, Value: "house456"},
+				{Column: "priority", Op: OpEq, Value: dao.PriorityHigh},
+				{Column: "tags", Op: OpContains, Value: []string{"urgent", "work"}},
+				{Column: "user_uid", Op: OpEq, Value: "user123"},
 			},
 		},
 		{
@@ -208,10 +264,9 @@ func TestBuildFiltersFromMCP(t *testing.T) {
 				"user_uid":       "user123",
 				"completed_only": true,
 			},
-			supportedFilters: []string{"user_uid", "completed_by"},
-			expectedFilters: map[string]string{
-				"user_uid":      "user123",
-				"completed_by": "NOT NULL",
+			expectedFilters: []Filter{
+				{Column: "user_uid", Op: OpEq, Value: "user123"},
+				{Column: "marked_complete", Op: OpNotNull},
 			},
 		},
 		{
@@ -220,22 +275,20 @@ func TestBuildFiltersFromMCP(t *testing.T) {
 				"user_uid":     "user123",
 				"pending_only": true,
 			},
-			supportedFilters: []string{"user_uid", "completed_by"},
-			expectedFilters: map[string]string{
-				"user_uid":      "user123",
-				"completed_by": "IS NULL",
+			expectedFilters: []Filter{
+				{Column: "user_uid", Op: OpEq, Value: "user123"},
+				{Column: "marked_complete", Op: OpNull},
 			},
 		},
 		{
 			name: "empty values ignored",
 			arguments: map[string]any{
 				"user_uid": "user123",
-				"title":   "",
-				"tags":    "",
+				"title":    "",
+				"tags":     "",
 			},
-			supportedFilters: []string{"user_uid", "title", "tags"},
-			expectedFilters: map[string]string{
-				"user_uid": "user123",
+			expectedFilters: []Filter{
+				{Column: "user_uid", Op: OpEq, Value: "user123"},
 			},
 		},
 		{
@@ -244,19 +297,35 @@ func TestBuildFiltersFromMCP(t *testing.T) {
 				"user_uid":    "user123",
 				"unsupported": "value",
 			},
-			supportedFilters: []string{"user_uid"},
-			expectedFilters: map[string]string{
-				"user_uid": "user123",
+			expectedFilters: []Filter{
+				{Column: "user_uid", Op: OpEq, Value: "user123"},
+			},
+		},
+		{
+			name: "operators in values are taken literally",
+			arguments: map[string]any{
+				"household_uid": "!=house-1",
+				"completed_by":  "NOT NULL",
+				"title":         "IS NULL",
+			},
+			expectedFilters: []Filter{
+				{Column: "completed_by", Op: OpEq, Value: "NOT NULL"},
+				{Column: "household_uid", Op: OpEq, Value: "!=house-1"},
+				{Column: "title", Op: OpMatch, Value: "IS NULL"},
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildFiltersFromMCP(tt.arguments, tt.supportedFilters)
+			result, err := BuildFiltersFromMCP(tt.arguments, TodoFilters.Filters)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedFilters, result)
 		})
 	}
+
+	_, err := BuildFiltersFromMCP(map[string]any{"near": "downtown"}, TodoFilters.Filters)
+	assert.Error(t, err)
 }
 
 func TestEntityFilters(t *testing.T) {
@@ -270,4 +339,142 @@ func TestEntityFilters(t *testing.T) {
 	assert.Contains(t, NotesFilters.SortFields, "created_at")
 	assert.Contains(t, PreferencesFilters.SortFields, "created_at")
 	assert.Contains(t, RecipesFilters.SortFields, "created_at")
-}
\ No newline at end of file
+}
+var allEntityFilters = []EntityFilters{
+	TodoFilters, NotesFilters, TodoTemplateFilters, PantryFilters, GroceryPurchaseFilters,
+	BackgroundsFilters, ToolPolicyFilters, PreferencesFilters, RecipesFilters, DeliveryFilters, JobRunFilters,
+}
+
+var (
+	// columnCondition is every condition BuildWhereClause writes for an
+	// ordinary column: its name, a fixed operator and a parameter, if any.
+	columnCondition = regexp.MustCompile(`^([a-z_]+) (?:(?:=|!=|>|>=|<|<=|@>) \$(\d+)|ILIKE \$(\d+) ESCAPE '\\'|IS NULL|IS NOT NULL)$`)
+	nearCondition   = regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(todoNearCondition), `\$%d`, `\$(\d+)`))
+)
+
+// checkWhereClause fails t unless where is made only of conditions on
+// columns, with one parameter, numbered in order, for each of args. Any user
+// input spliced into the SQL would break that shape.
+func checkWhereClause(t *testing.T, where string, args []any, columns FilterColumns) {
+	t.Helper()
+	if where == "" {
+		assert.Empty(t, args)
+		return
+	}
+	body, ok := strings.CutPrefix(where, "WHERE ")
+	if !assert.True(t, ok, where) {
+		return
+	}
+	var params []string
+	for _, m := range nearCondition.FindAllStringSubmatch(body, -1) {
+		params = append(params, m[1:]...)
+	}
+	body = nearCondition.ReplaceAllString(body, "near")
+	body = strings.ReplaceAll(body, todoBlockedCondition, "blocked")
+	for _, cond := range strings.Split(body, " AND ") {
+		if cond == "near" || cond == "blocked" || cond == "NOT blocked" {
+			continue
+		}
+		m := columnCondition.FindStringSubmatch(cond)
+		if !assert.NotNil(t, m, "unexpected condition %q in %q", cond, where) {
+			return
+		}
+		assert.Contains(t, columns, m[1])
+		if p := m[2] + m[3]; p != "" {
+			params = append(params, p)
+		}
+	}
+	slices.SortFunc(params, func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+	for i, p := range params {
+		assert.Equal(t, strconv.Itoa(i+1), p, where)
+	}
+	assert.Len(t, args, len(params), where)
+}
+
+func FuzzParseFilters(f *testing.F) {
+	for _, seed := range []string{
+		"priority=>=3&completed_by=NOT NULL",
+		"title=milk'; DROP TABLE todos; --",
+		"household_uid=!=house-1&user_uid=IS NULL",
+		"tags=a,b&actionable=true&near=47.6,-122.3,500",
+		"priority=1 OR 1=1",
+		"rating=>=4&cook_time=<=30&archived_at=IS NULL",
+		"key=x&specifier=<>&expires_on=<2025-01-01",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, query string) {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return
+		}
+		params := map[string]string{}
+		for k, v := range values {
+			params[k] = v[0]
+		}
+		for _, entity := range allEntityFilters {
+			filters, err := ParseFilters(params, entity.Filters)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+				continue
+			}
+			where, args := BuildWhereClause(filters, entity.Filters)
+			checkWhereClause(t, where, args, entity.Filters)
+		}
+	})
+}
+
+func FuzzBuildFiltersFromMCP(f *testing.F) {
+	f.Add("priority", ">=3")
+	f.Add("completed_by", "NOT NULL")
+	f.Add("title", "x' OR '1'='1")
+	f.Add("near", "47.6,-122.3,500")
+	f.Add("tags", "a,b")
+	f.Fuzz(func(t *testing.T, column, value string) {
+		for _, entity := range allEntityFilters {
+			filters, err := BuildFiltersFromMCP(map[string]any{column: value, "pending_only": true, "actionable": true}, entity.Filters)
+			if err != nil {
+				continue
+			}
+			where, args := BuildWhereClause(filters, entity.Filters)
+			checkWhereClause(t, where, args, entity.Filters)
+		}
+	})
+}
+
+func FuzzBuildWhereClause(f *testing.F) {
+	f.Add("priority", ">=", "3")
+	f.Add("title; DROP TABLE todos", "=", "x")
+	f.Add("priority", "= 1 OR 1 =", "1")
+	f.Add("completed_by", "IS NOT NULL", "")
+	f.Fuzz(func(t *testing.T, column, op, value string) {
+		for _, entity := range allEntityFilters {
+			where, args := BuildWhereClause([]Filter{{Column: column, Op: Op(op), Value: value}}, entity.Filters)
+			checkWhereClause(t, where, args, entity.Filters)
+		}
+	})
+}
+
+func FuzzParseListParamsSort(f *testing.F) {
+	f.Add("due_date", "asc")
+	f.Add("created_at; DROP TABLE todos", "DESC, (SELECT 1)")
+	f.Fuzz(func(t *testing.T, sortBy, sortDir string) {
+		q := url.Values{"sort_by": {sortBy}, "sort_dir": {sortDir}}
+		params := ParseListParams(&http.Request{URL: &url.URL{RawQuery: q.Encode()}}, TodoFilters.SortFields)
+		assert.Contains(t, TodoFilters.SortFields, params.SortBy)
+		assert.Contains(t, []string{"ASC", "DESC"}, params.SortDir, sortDir)
+	})
+}
+
+func TestListRejectsUnsupportedComparison(t *testing.T) {
+	handler := NewTodos(mocks.NewMocktodoDAO(t))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?user_uid=>=a", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "user_uid can't be compared")
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/quickadd"
+)
+
+// quickAddTodo reads the quick-add markers in t's title, e.g. "buy milk !3
+// #errands @sat", into t, counting relative dates from now. A priority or
+// due date t already has is kept, and tags are added to its own. A title
+// that is nothing but markers is left as it is, so the todo still says
+// something.
+func quickAddTodo(t *dao.Todo, now time.Time) {
+	q := quickadd.Parse(t.Title, now)
+	if q.Title == "" {
+		return
+	}
+	t.Title = q.Title
+	if t.Priority == 0 {
+		t.Priority = dao.Priority(q.Priority)
+	}
+	if t.DueDate == nil {
+		t.DueDate = q.Due
+	}
+	for _, tag := range q.Tags {
+		if !slices.Contains(t.Tags, tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuickAddTodo(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2025, 9, 17, 9, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	todo := dao.Todo{Title: "buy milk !3 #errands @sat"}
+	quickAddTodo(&todo, now)
+	assert.Equal(t, dao.Todo{Title: "buy milk", Priority: dao.PriorityHigh, Tags: []string{"errands"}, DueDate: &saturday}, todo)
+
+	// What the caller gave explicitly wins over the markers.
+	monday := time.Date(2025, 9, 22, 0, 0, 0, 0, time.UTC)
+	todo = dao.Todo{Title: "buy milk !3 #errands @sat", Priority: dao.PriorityLow, DueDate: &monday, Tags: []string{"dairy", "errands"}}
+	quickAddTodo(&todo, now)
+	assert.Equal(t, dao.Todo{Title: "buy milk", Priority: dao.PriorityLow, Tags: []string{"dairy", "errands"}, DueDate: &monday}, todo)
+
+	todo = dao.Todo{Title: "#errands !2"}
+	quickAddTodo(&todo, now)
+	assert.Equal(t, dao.Todo{Title: "#errands !2"}, todo)
+}
+
+func TestTodoCreateQuickAdd(t *testing.T) {
+	d := mocks.NewMocktodoDAO(t)
+	d.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "buy milk" && td.Priority == dao.PriorityHigh && assert.ObjectsAreEqual([]string{"errands"}, td.Tags) &&
+			td.DueDate != nil && td.DueDate.Weekday() == time.Saturday
+	})).Return(dao.Todo{UID: "todo-1"}, nil).Once()
+	d.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "Release #1 @home"
+	})).Return(dao.Todo{UID: "todo-2"}, nil).Once()
+	handler := NewTodos(d)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/?quick_add=true&timezone=Europe/London", strings.NewReader(`{"title": "buy milk !3 #errands @sat"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Without a priority marker the body still needs one.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/?quick_add=true", strings.NewReader(`{"title": "buy milk #errands"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "priority is required")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/?quick_add=true&timezone=Mars/Olympus", strings.NewReader(`{"title": "buy milk !3"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown timezone")
+
+	// Titles are taken as written unless asked.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Release #1 @home", "priority": "low"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestMCPHandlers_CreateTodoQuickAdd(t *testing.T) {
+	todos := &MockTodoDAO{}
+	todos.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "buy milk" && td.Priority == dao.PriorityHigh && assert.ObjectsAreEqual([]string{"dairy", "errands"}, td.Tags) &&
+			td.DueDate != nil && td.DueDate.Weekday() == time.Saturday
+	})).Return(dao.Todo{UID: "todo-1"}, nil).Once()
+	todos.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "buy milk !3" && td.Priority == dao.PriorityMedium && td.Tags == nil
+	})).Return(dao.Todo{UID: "todo-2"}, nil).Once()
+	todos.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "buy milk" && td.Priority == dao.PriorityLow
+	})).Return(dao.Todo{UID: "todo-3"}, nil).Once()
+	h := &MCPHandlers{todoDAO: todos}
+
+	assert.False(t, h.handleCreateTodo(t.Context(), map[string]any{"title": "buy milk !3 #errands @sat", "tags": "dairy"}).IsError)
+	assert.False(t, h.handleCreateTodo(t.Context(), map[string]any{"title": "buy milk !3", "quick_add": false}).IsError)
+	assert.False(t, h.handleCreateTodo(t.Context(), map[string]any{"title": "buy milk !3", "priority": "low"}).IsError)
+	todos.AssertExpectations(t)
+}
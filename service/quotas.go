@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type quotaDAO interface {
+	PutQuota(ctx context.Context, q dao.Quota) (dao.Quota, error)
+	ListQuotas(ctx context.Context) ([]dao.Quota, error)
+	DeleteQuota(ctx context.Context, uid string) error
+	GetQuotaUsage(ctx context.Context, householdUID string) (dao.QuotaUsage, error)
+}
+
+func validateQuota(q dao.Quota) error {
+	switch {
+	case q.MaxTodos != nil && *q.MaxTodos < 0:
+		return errors.New("max_todos must not be negative")
+	case q.MaxNotes != nil && *q.MaxNotes < 0:
+		return errors.New("max_notes must not be negative")
+	case q.MaxRecipes != nil && *q.MaxRecipes < 0:
+		return errors.New("max_recipes must not be negative")
+	case q.MaxPhotoBytes != nil && *q.MaxPhotoBytes < 0:
+		return errors.New("max_photo_bytes must not be negative")
+	}
+	return nil
+}
+
+// quotaExceeded writes a 403 with the quota's message if err is
+// dao.ErrQuotaExceeded, and reports whether it did.
+func quotaExceeded(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, dao.ErrQuotaExceeded) {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": quotaMessage(err)})
+	return true
+}
+
+// quotaMessage is the trigger's explanation without the sentinel's
+// prefix, for showing to a user.
+func quotaMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), dao.ErrQuotaExceeded.Error()+": ")
+}
+
+// quotaToolError is the tool result for a write a quota refused. It tells
+// the assistant not to try again, so a runaway loop stops at the first
+// refusal rather than the thousandth.
+func quotaToolError(err error) mcp.CallToolResult {
+	return toolError("%s. Don't retry; tell the user the household is at its limit.", quotaMessage(err))
+}
+
+type QuotaHandlers struct{ dao quotaDAO }
+
+// NewQuotas manages household quotas. Only operators, calling without an
+// API key, may change them; callers with a key see the quota that applies
+// to their household and how much of it they use.
+func NewQuotas(dao quotaDAO) http.Handler {
+	h := &QuotaHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/", h.list)
+	r.Get("/usage", h.usage)
+	r.With(operatorsOnly).Post("/", h.put)
+	r.With(operatorsOnly).Delete("/{uid}", h.delete)
+	return r
+}
+
+// put sets a household's quota, or without a household_uid the default,
+// replacing the one it had.
+func (h *QuotaHandlers) put(w http.ResponseWriter, r *http.Request) {
+	var q dao.Quota
+	if json.NewDecoder(r.Body).Decode(&q) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if q.HouseholdUID != nil && *q.HouseholdUID == "" {
+		q.HouseholdUID = nil
+	}
+	if err := validateQuota(q); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.PutQuota(r.Context(), q)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *QuotaHandlers) list(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.ListQuotas(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		out = slices.DeleteFunc(out, func(q dao.Quota) bool {
+			return q.HouseholdUID != nil && *q.HouseholdUID != id.HouseholdUID
+		})
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// usage reports a household's usage against its quota: the caller's own
+// household with an API key, or ?household_uid= for operators.
+func (h *QuotaHandlers) usage(w http.ResponseWriter, r *http.Request) {
+	household := r.URL.Query().Get("household_uid")
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		household = id.HouseholdUID
+	}
+	if household == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.GetQuotaUsage(r.Context(), household)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *QuotaHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteQuota(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
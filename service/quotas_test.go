@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateQuota(t *testing.T) {
+	assert.NoError(t, validateQuota(postgres.Quota{MaxTodos: intPtr(5000), MaxNotes: intPtr(0)}))
+	assert.NoError(t, validateQuota(postgres.Quota{}))
+	assert.ErrorContains(t, validateQuota(postgres.Quota{MaxRecipes: intPtr(-1)}), "max_recipes")
+	bytes := int64(-1)
+	assert.ErrorContains(t, validateQuota(postgres.Quota{MaxPhotoBytes: &bytes}), "max_photo_bytes")
+}
+
+func TestQuotaHandlers(t *testing.T) {
+	d := mocks.NewMockquotaDAO(t)
+	d.On("PutQuota", mock.Anything, postgres.Quota{MaxTodos: intPtr(5000)}).Return(postgres.Quota{UID: "q1"}, nil)
+	d.On("ListQuotas", mock.Anything).Return([]postgres.Quota{
+		{UID: "q1"},
+		{UID: "q2", HouseholdUID: strPtr("house-1")},
+		{UID: "q3", HouseholdUID: strPtr("house-2")},
+	}, nil)
+	d.On("GetQuotaUsage", mock.Anything, "house-1").Return(postgres.QuotaUsage{HouseholdUID: "house-1", Todos: 12}, nil)
+	d.On("DeleteQuota", mock.Anything, "q1").Return(nil)
+	handler := NewQuotas(d)
+	asMember := func(r *http.Request) *http.Request { return r.WithContext(identityContext("user-1", "house-1")) }
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"household_uid": "", "max_todos": 5000}`))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"max_notes": -5}`))))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "max_notes must not be negative")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("POST", "/", strings.NewReader(`{"max_todos": 1000000}`))))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"q2"`)
+	assert.NotContains(t, rr.Body.String(), `"uid":"q3"`)
+
+	// A member sees their own household's usage, whatever they ask for.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("GET", "/usage?household_uid=house-2", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"todos":12`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/usage", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("DELETE", "/q1", nil)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/q1", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	err := fmt.Errorf("%w: this household has reached its limit of 3 todos; delete some or ask an operator to raise the limit", postgres.ErrQuotaExceeded)
+
+	todos := mocks.NewMocktodoDAO(t)
+	todos.On("CreateTodo", mock.Anything, mock.Anything).Return(postgres.Todo{}, err)
+	rr := httptest.NewRecorder()
+	NewTodos(todos).ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Buy milk", "priority": 2}`)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"error":"this household has reached its limit of 3 todos`)
+
+	mcpTodos := &MockTodoDAO{}
+	mcpTodos.On("CreateTodo", mock.Anything, mock.Anything).Return(postgres.Todo{}, err)
+	result := (&MCPHandlers{todoDAO: mcpTodos}).handleCreateTodo(t.Context(), map[string]any{"title": "Buy milk"})
+	assert.True(t, result.IsError)
+	var out map[string]any
+	decodeToolResult(t, result, &out)
+	assert.Contains(t, out["error"], "limit of 3 todos")
+	assert.Contains(t, out["error"], "Don't retry")
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+	"github.com/pbdeuchler/assistant-server/pdf"
+)
+
+// exportPhotoSize is the photo rendition printed in an exported recipe.
+// Thumbnails are always JPEG, which is what a PDF can hold as is.
+const exportPhotoSize = "medium"
+
+// recipeExport is a recipe laid out for printing, with its ingredients
+// scaled and in its household's units.
+type recipeExport struct {
+	Title       string
+	Times       []string
+	Ingredients []string
+	Steps       []string
+	Source      string
+}
+
+// newRecipeExport lays out r. servings, when not zero, scales the
+// ingredients from the servings the recipe makes; a recipe that doesn't
+// say how many it serves is left as written.
+func newRecipeExport(r dao.Recipes, servings int, sys measurement.System) recipeExport {
+	out := recipeExport{Title: r.Title, Steps: recipeLines(r.Data)}
+	for _, t := range []struct {
+		label   string
+		minutes *int
+	}{{"Prep", r.PrepTime}, {"Cook", r.CookTime}, {"Total", r.TotalTime}} {
+		if t.minutes != nil && *t.minutes > 0 {
+			out.Times = append(out.Times, t.label+" "+formatMinutes(int64(*t.minutes)))
+		}
+	}
+	factor := 1.0
+	if servings > 0 && r.Servings != nil && *r.Servings > 0 {
+		factor = float64(servings) / float64(*r.Servings)
+	}
+	if servings == 0 && r.Servings != nil {
+		servings = *r.Servings
+	}
+	if servings > 0 {
+		out.Times = append(out.Times, "Serves "+strconv.Itoa(servings))
+	}
+	if r.GroceryList != nil {
+		for _, line := range parseGroceryList(*r.GroceryList) {
+			ingredient := measurement.ParseIngredient(line)
+			if q := ingredient.Quantity; q != nil && ingredient.Name != "" {
+				if shown := displayQuantity(q.Scale(factor), sys); shown != *q {
+					line = strings.TrimSpace(shown.String() + " " + ingredient.Name)
+				}
+			}
+			out.Ingredients = append(out.Ingredients, line)
+		}
+	}
+	if r.ExternalURL != nil {
+		out.Source = *r.ExternalURL
+	}
+	return out
+}
+
+// markdown writes the recipe as Markdown, linking photoURL when it isn't
+// empty.
+func (e recipeExport) markdown(photoURL string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", e.Title)
+	if photoURL != "" {
+		fmt.Fprintf(&b, "![%s](%s)\n\n", e.Title, photoURL)
+	}
+	if len(e.Times) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(e.Times, " · "))
+	}
+	if len(e.Ingredients) > 0 {
+		b.WriteString("## Ingredients\n\n")
+		for _, line := range e.Ingredients {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	if len(e.Steps) > 0 {
+		b.WriteString("## Steps\n\n")
+		for i, step := range e.Steps {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+		}
+		b.WriteString("\n")
+	}
+	if e.Source != "" {
+		fmt.Fprintf(&b, "Source: <%s>\n", e.Source)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// pdf writes the recipe as a PDF with photo, a JPEG, under the title when
+// it isn't nil.
+func (e recipeExport) pdf(photo []byte) []byte {
+	doc := pdf.New(e.Title)
+	doc.Title(e.Title)
+	if photo != nil {
+		if err := doc.Image(photo, 360, 270); err != nil {
+			slog.Warn("failed to add recipe photo to export", "error", err)
+		}
+	}
+	if len(e.Times) > 0 {
+		doc.Paragraph(strings.Join(e.Times, "  ·  "))
+	}
+	if len(e.Ingredients) > 0 {
+		doc.Heading("Ingredients")
+		doc.Bullets(e.Ingredients)
+	}
+	if len(e.Steps) > 0 {
+		doc.Heading("Steps")
+		doc.Numbered(e.Steps)
+	}
+	if e.Source != "" {
+		doc.Paragraph("Source: " + e.Source)
+	}
+	return doc.Bytes()
+}
+
+// exportFilename is the recipe's title as a file name, e.g.
+// "banana-bread.pdf".
+func exportFilename(title, ext string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return unicode.ToLower(r)
+		default:
+			return '-'
+		}
+	}, title)
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	if name = strings.Trim(name, "-"); name == "" {
+		name = "recipe"
+	}
+	return name + "." + ext
+}
+
+// export writes the recipe as a printable document: ?format=md (the
+// default) or pdf. ?servings= scales its ingredients.
+func (h *RecipesHandlers) export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "md"
+	}
+	if format != "md" && format != "pdf" {
+		writeImportError(w, "format must be md or pdf")
+		return
+	}
+	var servings int
+	if v := query.Get("servings"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeImportError(w, "servings must be a positive number")
+			return
+		}
+		servings = n
+	}
+	recipe, err := h.dao.GetRecipes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	export := newRecipeExport(recipe, servings, householdUnits(r.Context(), h.prefs, recipe.HouseholdUID))
+
+	if format == "md" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+exportFilename(recipe.Title, "md")+`"`)
+		_, _ = w.Write([]byte(export.markdown(withPhotoURLs(recipe).PhotoURLs[exportPhotoSize])))
+		return
+	}
+	var photo []byte
+	if recipe.PhotoUpdatedAt != nil {
+		// The recipe prints without its photo rather than not at all.
+		if p, err := h.dao.GetRecipePhoto(r.Context(), recipe.ID, exportPhotoSize); err == nil {
+			photo = p.Data
+		} else {
+			slog.Warn("failed to load recipe photo for export", "recipe_id", recipe.ID, "error", err)
+		}
+	}
+	file := export.pdf(photo)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+exportFilename(recipe.Title, "pdf")+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file)))
+	_, _ = w.Write(file)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeExport(t *testing.T) {
+	photoAt := time.Unix(1700000000, 0)
+	recipe := dao.Recipes{
+		ID:             "r1",
+		Title:          "Banana Bread (Gran's)",
+		PrepTime:       intPtr(15),
+		CookTime:       intPtr(60),
+		TotalTime:      intPtr(75),
+		Servings:       intPtr(4),
+		GroceryList:    strPtr(`["2 cups flour", "3 bananas", "salt"]`),
+		Data:           `["Mash the bananas.", "Fold in the flour.", "Bake."]`,
+		ExternalURL:    strPtr("https://example.com/banana-bread"),
+		PhotoUpdatedAt: &photoAt,
+	}
+	var photo bytes.Buffer
+	require.NoError(t, jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 8, 6)), nil))
+	recipes := mocks.NewMockrecipesDAO(t)
+	recipes.On("GetRecipes", mock.Anything, "r1").Return(recipe, nil)
+	recipes.On("GetRecipes", mock.Anything, "gone").Return(dao.Recipes{}, errors.New("no rows"))
+	handler := NewRecipes(recipes)
+	serve := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		return rr
+	}
+
+	rr := serve("/r1/export?servings=8")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="banana-bread-gran-s.md"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "# Banana Bread (Gran's)\n\n"+
+		"![Banana Bread (Gran's)](/recipes/r1/photo?size=medium&v=1700000000)\n\n"+
+		"Prep 15m · Cook 1h · Total 1h 15m · Serves 8\n\n"+
+		"## Ingredients\n\n- 4 cup flour\n- 6 bananas\n- salt\n\n"+
+		"## Steps\n\n1. Mash the bananas.\n2. Fold in the flour.\n3. Bake.\n\n"+
+		"Source: <https://example.com/banana-bread>\n", rr.Body.String())
+
+	recipes.On("GetRecipePhoto", mock.Anything, "r1", "medium").Return(dao.RecipePhoto{Data: photo.Bytes()}, nil)
+	rr = serve("/r1/export?format=pdf")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="banana-bread-gran-s.pdf"`, rr.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "%PDF-"))
+	assert.Contains(t, rr.Body.String(), "(2 cups flour) Tj")
+	assert.Contains(t, rr.Body.String(), "(Fold in the flour.) Tj")
+	assert.Contains(t, rr.Body.String(), "/Subtype /Image")
+
+	assert.Equal(t, http.StatusNotFound, serve("/gone/export").Code)
+	for target, want := range map[string]string{
+		"/r1/export?format=docx":  "format must be md or pdf",
+		"/r1/export?servings=0":   "servings must be a positive number",
+		"/r1/export?servings=two": "servings must be a positive number",
+	} {
+		rr := serve(target)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), want, target)
+	}
+}
+
+func TestNewRecipeExport(t *testing.T) {
+	// Without servings to scale from, the ingredients are kept as written,
+	// and plain text instructions are read a step a line.
+	e := newRecipeExport(dao.Recipes{
+		Title:       "Toast",
+		GroceryList: strPtr("2 slices bread, 1 tbsp butter"),
+		Data:        "Toast the bread.\n\nButter it.",
+	}, 3, "")
+	assert.Equal(t, []string{"Serves 3"}, e.Times)
+	assert.Equal(t, []string{"2 slices bread", "1 tbsp butter"}, e.Ingredients)
+	assert.Equal(t, []string{"Toast the bread.", "Butter it."}, e.Steps)
+	assert.Equal(t, "# Toast\n\nServes 3\n\n## Ingredients\n\n- 2 slices bread\n- 1 tbsp butter\n\n"+
+		"## Steps\n\n1. Toast the bread.\n2. Butter it.\n", e.markdown(""))
+
+	assert.Equal(t, "recipe.pdf", exportFilename("¡¿!", "pdf"))
+	assert.Equal(t, "creme-brulee.md", exportFilename("Creme  Brulee", "md"))
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// maxRecipePageSize caps how much of a recipe page is read.
+const maxRecipePageSize = 4 << 20
+
+var errNoRecipeOnPage = errors.New("no schema.org recipe found on the page")
+
+var errPrivateAddress = errors.New("refusing to connect to a private, loopback or link-local address")
+
+// WithRecipeRefresh enables the refresh_recipe tool, which re-reads a
+// recipe from its external_url with client. Anyone who can save a recipe
+// chooses that URL, so client should be one from NewPublicHTTPClient.
+func WithRecipeRefresh(client *http.Client) MCPOption {
+	return func(h *MCPHandlers) { h.recipeClient = client }
+}
+
+// NewPublicHTTPClient returns a client that only connects to public
+// addresses. The address is checked as each connection is dialed, after
+// the name is resolved, so neither a hostname pointing inside the network
+// nor a redirect there gets through. Proxies from the environment are
+// ignored, since they would be dialed instead of the host.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: dialPublicOnly}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("refusing to follow a redirect to %q", req.URL.Scheme)
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to addresses that aren't publicly
+// routable, such as 127.0.0.1, 10.0.0.0/8 or 169.254.169.254.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// normalizeRecipeURL tidies an external_url so that the same page saved
+// twice compares equal: surrounding space and any #fragment are dropped.
+func normalizeRecipeURL(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// recipeByURL finds the recipe the household, or the user when there's no
+// household, already saved from externalURL.
+func (h *MCPHandlers) recipeByURL(ctx context.Context, userUID, householdUID, externalURL string) (*dao.Recipes, error) {
+	where, args := "WHERE household_uid = $1 AND external_url = $2", []any{householdUID, externalURL}
+	if householdUID == "" {
+		var owner any
+		if userUID != "" {
+			owner = userUID
+		}
+		where, args = "WHERE household_uid IS NULL AND user_uid IS NOT DISTINCT FROM $1 AND external_url = $2", []any{owner, externalURL}
+	}
+	found, err := h.recipesDAO.ListRecipes(ctx, dao.ListOptions{
+		Limit:       1,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: where,
+		WhereArgs:   args,
+	})
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// mergeRecipe applies a re-save of existing: the owner and anything the
+// save leaves out are kept.
+func mergeRecipe(existing, save dao.Recipes) dao.Recipes {
+	out := existing
+	out.Title, out.Data = save.Title, save.Data
+	for _, f := range []struct{ dst, src **string }{
+		{&out.Genre, &save.Genre}, {&out.GroceryList, &save.GroceryList}, {&out.Difficulty, &save.Difficulty},
+	} {
+		if *f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	for _, f := range []struct{ dst, src **int }{
+		{&out.PrepTime, &save.PrepTime}, {&out.CookTime, &save.CookTime}, {&out.TotalTime, &save.TotalTime},
+		{&out.Servings, &save.Servings},
+	} {
+		if *f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if len(save.Tags) > 0 {
+		out.Tags = save.Tags
+	}
+	return out
+}
+
+// RecipeChange is one field refresh_recipe changed. Title and times report
+// their old and new values; the instructions (data) and grocery list report
+// the lines added and removed.
+type RecipeChange struct {
+	Field   string   `json:"field"`
+	From    any      `json:"from,omitempty"`
+	To      any      `json:"to,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// refreshRecipe applies what the page now says to r, leaving fields the
+// page doesn't give alone, and reports what changed.
+func refreshRecipe(r *dao.Recipes, page dao.Recipes) []RecipeChange {
+	var changes []RecipeChange
+	if page.Title != "" && page.Title != r.Title {
+		changes = append(changes, RecipeChange{Field: "title", From: r.Title, To: page.Title})
+		r.Title = page.Title
+	}
+	// The instructions and grocery list count as changed when their lines
+	// do, however they were stored.
+	if before, after := recipeLines(r.Data), recipeLines(page.Data); len(after) > 0 && !slices.Equal(before, after) {
+		changes = append(changes, lineChange("data", before, after))
+		r.Data = page.Data
+	}
+	if page.GroceryList != nil {
+		var before []string
+		if r.GroceryList != nil {
+			before = recipeLines(*r.GroceryList)
+		}
+		if after := recipeLines(*page.GroceryList); !slices.Equal(before, after) {
+			changes = append(changes, lineChange("grocery_list", before, after))
+			r.GroceryList = page.GroceryList
+		}
+	}
+	for _, f := range []struct {
+		name      string
+		dst, page **int
+	}{
+		{"prep_time", &r.PrepTime, &page.PrepTime},
+		{"cook_time", &r.CookTime, &page.CookTime},
+		{"total_time", &r.TotalTime, &page.TotalTime},
+		{"servings", &r.Servings, &page.Servings},
+	} {
+		if *f.page == nil || *f.dst != nil && **f.dst == **f.page {
+			continue
+		}
+		change := RecipeChange{Field: f.name, To: **f.page}
+		if *f.dst != nil {
+			change.From = **f.dst
+		}
+		changes = append(changes, change)
+		*f.dst = *f.page
+	}
+	return changes
+}
+
+func lineChange(field string, before, after []string) RecipeChange {
+	change := RecipeChange{Field: field}
+	for _, line := range after {
+		if !slices.Contains(before, line) {
+			change.Added = append(change.Added, line)
+		}
+	}
+	for _, line := range before {
+		if !slices.Contains(after, line) {
+			change.Removed = append(change.Removed, line)
+		}
+	}
+	return change
+}
+
+// recipeLines splits instructions or a grocery list, stored as a JSON array
+// of strings or as lines of text.
+func recipeLines(s string) []string {
+	var lines []string
+	if json.Unmarshal([]byte(s), &lines) != nil {
+		lines = strings.Split(s, "\n")
+	}
+	out := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func (h *MCPHandlers) handleRefreshRecipe(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	recipeID, ok := arguments["recipe_id"].(string)
+	if !ok || recipeID == "" {
+		return toolError("recipe_id is required")
+	}
+	recipe, err := h.recipesDAO.GetRecipes(ctx, recipeID)
+	if err != nil {
+		return toolError("Recipe not found: %v", err)
+	}
+	if recipe.ExternalURL == nil || *recipe.ExternalURL == "" {
+		return toolError("Recipe has no external_url to refresh from")
+	}
+
+	reportProgress(ctx, 0, 2, "Fetching "+*recipe.ExternalURL)
+	page, err := fetchRecipe(ctx, h.recipeClient, *recipe.ExternalURL)
+	if err != nil {
+		return toolError("Failed to fetch recipe: %v", err)
+	}
+	changes := refreshRecipe(&recipe, page)
+	if len(changes) == 0 {
+		reportProgress(ctx, 2, 2, "Recipe is up to date")
+		return toolOK("Recipe is up to date", map[string]any{"recipe": withPhotoURLs(recipe), "changes": []RecipeChange{}})
+	}
+	reportProgress(ctx, 1, 2, fmt.Sprintf("Saving %d changes", len(changes)))
+	updated, err := h.recipesDAO.UpdateRecipes(ctx, recipeID, recipe)
+	if err != nil {
+		return toolError("Failed to update recipe: %v", err)
+	}
+	reportProgress(ctx, 2, 2, "Recipe updated")
+	fields := make([]string, len(changes))
+	for i, c := range changes {
+		fields[i] = c.Field
+	}
+	return toolOK(fmt.Sprintf("Updated %s from %s", strings.Join(fields, ", "), *recipe.ExternalURL),
+		map[string]any{"recipe": withPhotoURLs(updated), "changes": changes})
+}
+
+var jsonLDScript = regexp.MustCompile(`(?is)<script[^>]+type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+// fetchRecipe reads the schema.org Recipe that the page at rawURL publishes
+// as JSON-LD, as most recipe sites do for search engines. Only the fields
+// refresh_recipe compares are set.
+func fetchRecipe(ctx context.Context, client *http.Client, rawURL string) (dao.Recipes, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" {
+		return dao.Recipes{}, fmt.Errorf("%q is not an http(s) URL", rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return dao.Recipes{}, err
+	}
+	req.Header.Set("Accept", "text/html")
+	resp, err := client.Do(req)
+	if err != nil {
+		return dao.Recipes{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return dao.Recipes{}, fmt.Errorf("%s answered %s", u.Host, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRecipePageSize))
+	if err != nil {
+		return dao.Recipes{}, err
+	}
+
+	for _, m := range jsonLDScript.FindAllSubmatch(body, -1) {
+		var doc any
+		if json.Unmarshal(m[1], &doc) != nil {
+			continue
+		}
+		if node := findRecipeNode(doc); node != nil {
+			return recipeFromNode(node), nil
+		}
+	}
+	return dao.Recipes{}, errNoRecipeOnPage
+}
+
+// findRecipeNode finds the Recipe in a JSON-LD document, which may be a
+// single node, an array of them or a @graph.
+func findRecipeNode(v any) map[string]any {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			if node := findRecipeNode(e); node != nil {
+				return node
+			}
+		}
+	case map[string]any:
+		switch t := v["@type"].(type) {
+		case string:
+			if t == "Recipe" {
+				return v
+			}
+		case []any:
+			if slices.Contains(t, any("Recipe")) {
+				return v
+			}
+		}
+		return findRecipeNode(v["@graph"])
+	}
+	return nil
+}
+
+func recipeFromNode(node map[string]any) dao.Recipes {
+	var r dao.Recipes
+	if name, ok := node["name"].(string); ok {
+		r.Title = html.UnescapeString(strings.TrimSpace(name))
+	}
+	if steps := recipeSteps(node["recipeInstructions"]); len(steps) > 0 {
+		r.Data = encodeLines(steps)
+	}
+	var ingredients []string
+	for _, v := range asList(node["recipeIngredient"]) {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			ingredients = append(ingredients, html.UnescapeString(strings.TrimSpace(s)))
+		}
+	}
+	if len(ingredients) > 0 {
+		list := encodeLines(ingredients)
+		r.GroceryList = &list
+	}
+	r.PrepTime = durationMinutes(node["prepTime"])
+	r.CookTime = durationMinutes(node["cookTime"])
+	r.TotalTime = durationMinutes(node["totalTime"])
+	r.Servings = recipeYield(node["recipeYield"])
+	return r
+}
+
+// encodeLines stores lines as a JSON array, leaving characters like & as
+// they are.
+func encodeLines(lines []string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(lines)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func asList(v any) []any {
+	if list, ok := v.([]any); ok {
+		return list
+	}
+	if v == nil {
+		return nil
+	}
+	return []any{v}
+}
+
+// recipeSteps flattens recipeInstructions: text, a list of texts, HowToStep
+// nodes or HowToSection nodes of steps.
+func recipeSteps(v any) []string {
+	var steps []string
+	for _, e := range asList(v) {
+		switch e := e.(type) {
+		case string:
+			for _, line := range strings.Split(e, "\n") {
+				if line = strings.TrimSpace(html.UnescapeString(line)); line != "" {
+					steps = append(steps, line)
+				}
+			}
+		case map[string]any:
+			if items, ok := e["itemListElement"]; ok {
+				steps = append(steps, recipeSteps(items)...)
+			} else if text, ok := e["text"].(string); ok {
+				steps = append(steps, recipeSteps(text)...)
+			} else if name, ok := e["name"].(string); ok {
+				steps = append(steps, recipeSteps(name)...)
+			}
+		}
+	}
+	return steps
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:\d+(?:\.\d+)?S)?)?$`)
+
+// durationMinutes reads an ISO 8601 duration such as PT1H30M in whole
+// minutes.
+func durationMinutes(v any) *int {
+	s, _ := v.(string)
+	m := isoDuration.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil || s == "" {
+		return nil
+	}
+	minutes := 0
+	for i, per := range []int{24 * 60, 60, 1} {
+		n, _ := strconv.Atoi(m[i+1])
+		minutes += n * per
+	}
+	if minutes == 0 {
+		return nil
+	}
+	return &minutes
+}
+
+var firstNumber = regexp.MustCompile(`\d+`)
+
+// recipeYield reads the servings from a recipeYield such as 4, "4" or
+// "Serves 4-6", taking the first number.
+func recipeYield(v any) *int {
+	for _, e := range asList(v) {
+		var n int
+		switch e := e.(type) {
+		case float64:
+			n = int(e)
+		case string:
+			n, _ = strconv.Atoi(firstNumber.FindString(e))
+		}
+		if n > 0 {
+			return &n
+		}
+	}
+	return nil
+}
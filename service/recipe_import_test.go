@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const carbonaraPage = `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+	{"@type": "WebPage", "name": "Carbonara | Recipes"},
+	{"@type": ["Recipe"], "name": "Spaghetti Carbonara",
+	 "recipeIngredient": ["200g spaghetti", "100g guanciale", "2 eggs"],
+	 "recipeInstructions": [{"@type": "HowToSection", "itemListElement": [
+		{"@type": "HowToStep", "text": "Boil the pasta."},
+		{"@type": "HowToStep", "text": "Fry the guanciale &amp; mix with eggs."}]}],
+	 "prepTime": "PT10M", "cookTime": "PT15M", "totalTime": "PT25M", "recipeYield": ["2", "2 servings"]}
+]}</script></head><body></body></html>`
+
+func TestFetchRecipe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			fmt.Fprint(w, "<html>no recipe here</html>")
+			return
+		}
+		fmt.Fprint(w, carbonaraPage)
+	}))
+	defer srv.Close()
+
+	r, err := fetchRecipe(t.Context(), srv.Client(), srv.URL+"/carbonara")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Spaghetti Carbonara", r.Title)
+	assert.Equal(t, `["Boil the pasta.","Fry the guanciale & mix with eggs."]`, r.Data)
+	assert.Equal(t, `["200g spaghetti","100g guanciale","2 eggs"]`, *r.GroceryList)
+	assert.Equal(t, 10, *r.PrepTime)
+	assert.Equal(t, 15, *r.CookTime)
+	assert.Equal(t, 25, *r.TotalTime)
+	assert.Equal(t, 2, *r.Servings)
+
+	_, err = fetchRecipe(t.Context(), srv.Client(), srv.URL+"/plain")
+	assert.ErrorIs(t, err, errNoRecipeOnPage)
+	_, err = fetchRecipe(t.Context(), srv.Client(), "file:///etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestDurationMinutes(t *testing.T) {
+	assert.Equal(t, 90, *durationMinutes("PT1H30M"))
+	assert.Equal(t, 1500, *durationMinutes("P1DT1H"))
+	assert.Nil(t, durationMinutes("PT0M"))
+	assert.Nil(t, durationMinutes("20 minutes"))
+	assert.Nil(t, durationMinutes(nil))
+}
+
+func TestMCPHandlers_SaveRecipeDeduplicatesURL(t *testing.T) {
+	genre, household := "Italian", "h1"
+	existing := dao.Recipes{ID: "r1", Title: "Carbonara", Data: "old", Genre: &genre, Tags: []string{"pasta"}, HouseholdUID: &household}
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("ListRecipes", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND external_url = $2" &&
+			assert.ObjectsAreEqual([]any{"h1", "https://example.com/carbonara"}, o.WhereArgs)
+	})).Return([]dao.Recipes{existing}, nil)
+	// The re-save keeps what it doesn't set, like the genre and tags.
+	mockRecipesDAO.On("UpdateRecipes", mock.Anything, "r1", mock.MatchedBy(func(r dao.Recipes) bool {
+		return r.ID == "r1" && r.Data == "new" && *r.Genre == "Italian" && assert.ObjectsAreEqual([]string{"pasta"}, r.Tags)
+	})).Return(dao.Recipes{ID: "r1", Title: "Carbonara", Data: "new"}, nil)
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "save_recipe", map[string]any{
+		"title": "Carbonara", "data": "new", "household_uid": "h1", "external_url": " https://example.com/carbonara#method",
+	}), &body)
+	assert.Equal(t, true, body["updated"])
+	assert.Equal(t, "r1", body["recipe"].(map[string]any)["id"])
+	mockRecipesDAO.AssertNotCalled(t, "CreateRecipes", mock.Anything, mock.Anything)
+}
+
+func TestMCPHandlers_RefreshRecipe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, carbonaraPage)
+	}))
+	defer srv.Close()
+
+	url, prep, list := srv.URL+"/carbonara", 10, `["200g spaghetti","150g pancetta","2 eggs"]`
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r1").Return(dao.Recipes{
+		ID: "r1", Title: "Spaghetti Carbonara", ExternalURL: &url, PrepTime: &prep, GroceryList: &list,
+		Data: `["Boil the pasta.","Fry the guanciale & mix with eggs."]`,
+	}, nil)
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r2").Return(dao.Recipes{ID: "r2", Title: "Toast"}, nil)
+	mockRecipesDAO.On("UpdateRecipes", mock.Anything, "r1", mock.MatchedBy(func(r dao.Recipes) bool {
+		return *r.CookTime == 15 && *r.GroceryList == `["200g spaghetti","100g guanciale","2 eggs"]`
+	})).Return(dao.Recipes{ID: "r1"}, nil).Once()
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithRecipeRefresh(srv.Client()))
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "refresh_recipe", map[string]any{"recipe_id": "r1"}), &body)
+	assert.Equal(t, "Updated grocery_list, cook_time, total_time, servings from "+url, body["summary"])
+	assert.Equal(t, []any{
+		map[string]any{"field": "grocery_list", "added": []any{"100g guanciale"}, "removed": []any{"150g pancetta"}},
+		map[string]any{"field": "cook_time", "to": float64(15)},
+		map[string]any{"field": "total_time", "to": float64(25)},
+		map[string]any{"field": "servings", "to": float64(2)},
+	}, body["changes"])
+
+	result := h.callTool(t.Context(), "refresh_recipe", map[string]any{"recipe_id": "r2"})
+	assert.True(t, result.IsError)
+}
+
+func TestPublicHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:80", "10.1.2.3:443", "192.168.1.1:80", "169.254.169.254:80", "0.0.0.0:80", "[::1]:80", "[fd00::1]:80", "[::ffff:127.0.0.1]:80"} {
+		assert.ErrorIs(t, dialPublicOnly("tcp", addr, nil), errPrivateAddress, addr)
+	}
+	for _, addr := range []string{"93.184.216.34:443", "[2606:2800:220:1:248:1893:25c8:1946]:443"} {
+		assert.NoError(t, dialPublicOnly("tcp", addr, nil), addr)
+	}
+
+	// The check runs as the connection is dialed, whatever the URL says.
+	inside := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, carbonaraPage)
+	}))
+	defer inside.Close()
+	_, err := fetchRecipe(t.Context(), NewPublicHTTPClient(time.Second), inside.URL+"/carbonara")
+	assert.ErrorIs(t, err, errPrivateAddress)
+}
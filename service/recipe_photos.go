@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strconv"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+)
+
+// maxRecipePhotoBytes caps uploaded recipe photos, and maxPhotoPixels
+// every uploaded image. The pixel cap stops small, highly compressed files
+// from decoding into enormous images.
+const (
+	maxRecipePhotoBytes = 10 << 20
+	maxPhotoPixels      = 40_000_000
+)
+
+// originalPhotoSize names the uploaded photo, kept as it was sent.
+const originalPhotoSize = "original"
+
+// thumbnailSize names a thumbnail scaled to fit within a square of Max
+// pixels.
+type thumbnailSize struct {
+	Name string
+	Max  int
+}
+
+// recipeThumbnailSizes are generated for every recipe photo.
+var recipeThumbnailSizes = []thumbnailSize{
+	{"small", 160},
+	{"medium", 480},
+	{"large", 1024},
+}
+
+// recipeResponse is a recipe as returned by the API, with links to its photo
+// renditions when it has one and, when its household has chosen a
+// measurement system, its grocery list in that system.
+type recipeResponse struct {
+	dao.Recipes
+	PhotoURLs   map[string]string  `json:"photo_urls,omitempty"`
+	Units       measurement.System `json:"units,omitempty"`
+	Ingredients []string           `json:"ingredients,omitempty"`
+}
+
+func withPhotoURLs(r dao.Recipes) recipeResponse {
+	out := recipeResponse{Recipes: r}
+	if r.PhotoUpdatedAt == nil {
+		return out
+	}
+	// The version changes with every upload so clients can cache freely.
+	version := strconv.FormatInt(r.PhotoUpdatedAt.Unix(), 10)
+	out.PhotoURLs = map[string]string{
+		originalPhotoSize: fmt.Sprintf("/recipes/%s/photo?v=%s", r.ID, version),
+	}
+	for _, size := range recipeThumbnailSizes {
+		out.PhotoURLs[size.Name] = fmt.Sprintf("/recipes/%s/photo?size=%s&v=%s", r.ID, size.Name, version)
+	}
+	return out
+}
+
+func withPhotoURLsList(recipes []dao.Recipes) []recipeResponse {
+	out := make([]recipeResponse, len(recipes))
+	for i, r := range recipes {
+		out[i] = withPhotoURLs(r)
+	}
+	return out
+}
+
+// rendition is an uploaded image or one of its thumbnails.
+type rendition struct {
+	Size        string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// makeRecipePhotos decodes an uploaded JPEG, PNG or GIF and returns it
+// together with a JPEG thumbnail for each of recipeThumbnailSizes.
+func makeRecipePhotos(data []byte) ([]dao.RecipePhoto, error) {
+	renditions, err := makeRenditions(data, recipeThumbnailSizes)
+	if err != nil {
+		return nil, err
+	}
+	photos := make([]dao.RecipePhoto, len(renditions))
+	for i, r := range renditions {
+		photos[i] = dao.RecipePhoto{Size: r.Size, ContentType: r.ContentType, Width: r.Width, Height: r.Height, Data: r.Data}
+	}
+	return photos, nil
+}
+
+// makeRenditions decodes an uploaded JPEG, PNG or GIF and returns it as
+// the original together with a JPEG thumbnail for each of sizes.
+func makeRenditions(data []byte, sizes []thumbnailSize) ([]rendition, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return nil, fmt.Errorf("image is too large: %dx%d", cfg.Width, cfg.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	bounds := img.Bounds()
+	out := []rendition{{
+		Size:        originalPhotoSize,
+		ContentType: "image/" + format,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Data:        data,
+	}}
+	for _, size := range sizes {
+		thumb := resizeToFit(img, size.Max)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		out = append(out, rendition{
+			Size:        size.Name,
+			ContentType: "image/jpeg",
+			Width:       thumb.Bounds().Dx(),
+			Height:      thumb.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		})
+	}
+	return out, nil
+}
+
+// resizeToFit scales src down, keeping its aspect ratio, so neither side is
+// longer than limit. Each output pixel averages the source pixels it covers.
+// Images that already fit are copied unscaled.
+func resizeToFit(src image.Image, limit int) *image.RGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dw, dh := sw, sh
+	if sw > limit || sh > limit {
+		if sw >= sh {
+			dw, dh = limit, sh*limit/sw
+		} else {
+			dw, dh = sw*limit/sh, limit
+		}
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := sb.Min.Y+y*sh/dh, sb.Min.Y+max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := sb.Min.X+x*sw/dw, sb.Min.X+max((x+1)*sw/dw, x*sw/dw+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+func (h *RecipesHandlers) putPhoto(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRecipePhotoBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	photos, err := makeRecipePhotos(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	out, err := h.dao.SetRecipePhoto(r.Context(), chi.URLParam(r, "id"), photos)
+	if quotaExceeded(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(withPhotoURLs(out))
+}
+
+func (h *RecipesHandlers) getPhoto(w http.ResponseWriter, r *http.Request) {
+	size := r.URL.Query().Get("size")
+	if size == "" {
+		size = originalPhotoSize
+	}
+	photo, err := h.dao.GetRecipePhoto(r.Context(), chi.URLParam(r, "id"), size)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", photo.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(photo.Data)))
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	_, _ = w.Write(photo.Data)
+}
+
+func (h *RecipesHandlers) deletePhoto(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteRecipePhoto(r.Context(), chi.URLParam(r, "id")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
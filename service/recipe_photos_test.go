@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{200, 100, 50, 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestResizeToFit(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1200, 600))
+	assert.Equal(t, image.Rect(0, 0, 160, 80), resizeToFit(src, 160).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 1, 480), resizeToFit(image.NewRGBA(image.Rect(0, 0, 1, 2000)), 480).Bounds())
+	// Small images are never scaled up.
+	assert.Equal(t, image.Rect(0, 0, 100, 50), resizeToFit(image.NewRGBA(image.Rect(0, 0, 100, 50)), 1024).Bounds())
+}
+
+func TestMakeRecipePhotos(t *testing.T) {
+	data := testPNG(t, 800, 400)
+	photos, err := makeRecipePhotos(data)
+	assert.NoError(t, err)
+	assert.Len(t, photos, 1+len(recipeThumbnailSizes))
+
+	assert.Equal(t, "original", photos[0].Size)
+	assert.Equal(t, "image/png", photos[0].ContentType)
+	assert.Equal(t, data, photos[0].Data)
+
+	assert.Equal(t, "small", photos[1].Size)
+	assert.Equal(t, "image/jpeg", photos[1].ContentType)
+	assert.Equal(t, []int{160, 80}, []int{photos[1].Width, photos[1].Height})
+	assert.Equal(t, []int{800, 400}, []int{photos[3].Width, photos[3].Height})
+
+	_, err = makeRecipePhotos([]byte("not an image"))
+	assert.Error(t, err)
+}
+
+func TestRecipePhotoRoutes(t *testing.T) {
+	updated := time.Unix(1755600000, 0)
+	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
+	mockRecipesDAO.On("SetRecipePhoto", mock.Anything, "recipe-1", mock.MatchedBy(func(photos []postgres.RecipePhoto) bool {
+		return len(photos) == 4
+	})).Return(postgres.Recipes{ID: "recipe-1", PhotoUpdatedAt: &updated}, nil)
+	mockRecipesDAO.On("GetRecipePhoto", mock.Anything, "recipe-1", "small").
+		Return(postgres.RecipePhoto{ContentType: "image/jpeg", Data: []byte("jpeg")}, nil)
+	mockRecipesDAO.On("DeleteRecipePhoto", mock.Anything, "recipe-1").Return(nil)
+	handler := NewRecipes(mockRecipesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/recipe-1/photo", bytes.NewReader(testPNG(t, 300, 200))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, map[string]any{
+		"original": "/recipes/recipe-1/photo?v=1755600000",
+		"small":    "/recipes/recipe-1/photo?size=small&v=1755600000",
+		"medium":   "/recipes/recipe-1/photo?size=medium&v=1755600000",
+		"large":    "/recipes/recipe-1/photo?size=large&v=1755600000",
+	}, out["photo_urls"])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/recipe-1/photo", bytes.NewReader([]byte("<svg/>"))))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/recipe-1/photo?size=small", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/jpeg", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "jpeg", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("DELETE", "/recipe-1/photo", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestRecipesWithoutPhotoHaveNoURLs(t *testing.T) {
+	body, err := json.Marshal(withPhotoURLs(postgres.Recipes{ID: "recipe-1"}))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "photo_urls")
+	assert.Contains(t, string(body), `"id":"recipe-1"`)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// recipeSearchColumns are the filters GET /recipes/search builds.
+var recipeSearchColumns = FilterColumns{
+	"title":         {OpMatch},
+	"genre":         {OpIn},
+	"difficulty":    {OpIn},
+	"tags":          {OpContains, OpOverlaps},
+	"user_uid":      {OpEq},
+	"household_uid": {OpEq},
+	"rating":        {OpGe, OpLe},
+	"prep_time":     {OpGe, OpLe},
+	"cook_time":     {OpGe, OpLe},
+	"total_time":    {OpGe, OpLe},
+	"servings":      {OpGe, OpLe},
+}
+
+// recipeSearchRange is a column searched with min_<column> and
+// max_<column>, and the values they may take. A negative most means there
+// is no upper limit.
+type recipeSearchRange struct {
+	column   string
+	least    float64
+	most     float64
+	fraction bool
+}
+
+var recipeSearchRanges = []recipeSearchRange{
+	{column: "rating", least: 1, most: 5, fraction: true},
+	{column: "prep_time", most: -1},
+	{column: "cook_time", most: -1},
+	{column: "total_time", most: -1},
+	{column: "servings", least: 1, most: -1},
+}
+
+// recipeSearchParams are the query parameters of GET /recipes/search
+// besides those of every list.
+var recipeSearchParams = func() []string {
+	params := []string{"title", "genre", "difficulty", "tags_all", "tags_any", "user_uid", "household_uid", "format", "expand", "include"}
+	for _, r := range recipeSearchRanges {
+		params = append(params, "min_"+r.column, "max_"+r.column)
+	}
+	return params
+}()
+
+// errInvalidSearch is wrapped by the errors parseRecipeSearch returns.
+var errInvalidSearch = errors.New("invalid search")
+
+// search is GET /recipes/search. Unlike the list endpoint, which takes
+// filters named after columns, it has a fixed set of parameters, each
+// checked before anything is queried:
+//
+//   - title: a case-insensitive substring of the title
+//   - genre, difficulty: comma-separated values, any of which may match
+//   - tags_all, tags_any: comma-separated tags the recipe must have all of,
+//     or at least one of
+//   - min_/max_rating (1 to 5), min_/max_prep_time, min_/max_cook_time,
+//     min_/max_total_time (minutes) and min_/max_servings: inclusive bounds
+//   - user_uid, household_uid: the owner
+//
+// limit, offset, sort_by, sort_dir, fields, format, expand and include
+// work as they do for GET /recipes.
+func (h *RecipesHandlers) search(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseRecipeSearch(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	params := ParseListParams(r, RecipesFilters.SortFields)
+	whereClause, whereArgs := BuildWhereClause(filters, recipeSearchColumns)
+
+	out, err := h.dao.ListRecipes(r.Context(), dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	})
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, withPhotoURLsList(out))
+}
+
+// parseRecipeSearch turns the parameters of GET /recipes/search into
+// Filters, rejecting unknown parameters and out of range values.
+func parseRecipeSearch(query url.Values) ([]Filter, error) {
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		if !isReservedParam(key) && !slices.Contains(recipeSearchParams, key) {
+			return nil, fmt.Errorf("%w: unknown parameter %s", errInvalidSearch, key)
+		}
+	}
+
+	var filters []Filter
+	if title := query.Get("title"); title != "" {
+		filters = append(filters, Filter{Column: "title", Op: OpMatch, Value: title})
+	}
+	for _, column := range []string{"genre", "difficulty"} {
+		if v := query.Get(column); v != "" {
+			filters = append(filters, Filter{Column: column, Op: OpIn, Value: splitTags(v)})
+		}
+	}
+	if v := query.Get("tags_all"); v != "" {
+		filters = append(filters, Filter{Column: "tags", Op: OpContains, Value: splitTags(v)})
+	}
+	if v := query.Get("tags_any"); v != "" {
+		filters = append(filters, Filter{Column: "tags", Op: OpOverlaps, Value: splitTags(v)})
+	}
+	for _, column := range []string{"user_uid", "household_uid"} {
+		if v := query.Get(column); v != "" {
+			filters = append(filters, Filter{Column: column, Op: OpEq, Value: v})
+		}
+	}
+
+	for _, rng := range recipeSearchRanges {
+		least, hasLeast, err := rng.bound(query, "min_")
+		if err != nil {
+			return nil, err
+		}
+		most, hasMost, err := rng.bound(query, "max_")
+		if err != nil {
+			return nil, err
+		}
+		if hasLeast && hasMost && least > most {
+			return nil, fmt.Errorf("%w: min_%s is above max_%s", errInvalidSearch, rng.column, rng.column)
+		}
+		if hasLeast {
+			filters = append(filters, Filter{Column: rng.column, Op: OpGe, Value: rng.value(least)})
+		}
+		if hasMost {
+			filters = append(filters, Filter{Column: rng.column, Op: OpLe, Value: rng.value(most)})
+		}
+	}
+	return filters, nil
+}
+
+// bound parses the prefix+column parameter. ok is false when it is absent.
+func (rng recipeSearchRange) bound(query url.Values, prefix string) (v float64, ok bool, err error) {
+	name := prefix + rng.column
+	raw := query.Get(name)
+	if raw == "" {
+		return 0, false, nil
+	}
+	v, err = strconv.ParseFloat(raw, 64)
+	switch {
+	case err != nil, math.IsNaN(v), !rng.fraction && v != float64(int(v)):
+		if rng.fraction {
+			return 0, false, fmt.Errorf("%w: %s must be a number", errInvalidSearch, name)
+		}
+		return 0, false, fmt.Errorf("%w: %s must be a whole number", errInvalidSearch, name)
+	case v < rng.least || rng.most >= 0 && v > rng.most:
+		if rng.most >= 0 {
+			return 0, false, fmt.Errorf("%w: %s must be from %g to %g", errInvalidSearch, name, rng.least, rng.most)
+		}
+		return 0, false, fmt.Errorf("%w: %s must be at least %g", errInvalidSearch, name, rng.least)
+	}
+	return v, true, nil
+}
+
+// value is v as the column's type.
+func (rng recipeSearchRange) value(v float64) any {
+	if rng.fraction {
+		return v
+	}
+	return int(v)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeSearch(t *testing.T) {
+	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
+	mockRecipesDAO.On("ListRecipes", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == `WHERE title ILIKE $1 ESCAPE '\' AND genre = ANY($2) AND tags && $3 AND rating >= $4 AND cook_time <= $5 AND servings >= $6 AND servings <= $7` &&
+			assert.ObjectsAreEqual([]any{"%taco%", []string{"mexican", "tex-mex"}, []string{"quick", "kids"}, 3.5, 30, 2, 6}, o.WhereArgs) &&
+			o.SortBy == "rating" && o.Limit == 10
+	})).Return([]postgres.Recipes{{ID: "r1", Title: "Weeknight tacos"}}, nil)
+	handler := NewRecipes(mockRecipesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/search?title=taco&genre=mexican,tex-mex&tags_any=quick,kids&min_rating=3.5&max_cook_time=30&min_servings=2&max_servings=6&sort_by=rating&limit=10", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"r1"`)
+}
+
+func TestParseRecipeSearch(t *testing.T) {
+	filters, err := parseRecipeSearch(url.Values{"tags_all": {"vegetarian"}, "difficulty": {"easy"}, "household_uid": {"h1"}, "limit": {"5"}})
+	require.NoError(t, err)
+	assert.Equal(t, []Filter{
+		{Column: "difficulty", Op: OpIn, Value: []string{"easy"}},
+		{Column: "tags", Op: OpContains, Value: []string{"vegetarian"}},
+		{Column: "household_uid", Op: OpEq, Value: "h1"},
+	}, filters)
+
+	for query, want := range map[string]string{
+		"min_rating=6":                      "min_rating must be from 1 to 5",
+		"max_rating=NaN":                    "max_rating must be a number",
+		"min_prep_time=10.5":                "min_prep_time must be a whole number",
+		"max_total_time=-5":                 "max_total_time must be at least 0",
+		"min_servings=0":                    "min_servings must be at least 1",
+		"min_cook_time=40&max_cook_time=20": "min_cook_time is above max_cook_time",
+		"rating=5":                          "unknown parameter rating",
+	} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		_, err = parseRecipeSearch(values)
+		assert.ErrorIs(t, err, errInvalidSearch, query)
+		assert.ErrorContains(t, err, want, query)
+	}
+
+	// Nothing is queried for an invalid search.
+	rr := httptest.NewRecorder()
+	NewRecipes(mocks.NewMockrecipesDAO(t)).ServeHTTP(rr, httptest.NewRequest("GET", "/search?min_rating=0", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "min_rating must be from 1 to 5")
+}
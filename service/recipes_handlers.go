@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
 )
 
@@ -15,19 +17,40 @@ type recipesDAO interface {
 	GetRecipes(ctx context.Context, id string) (dao.Recipes, error)
 	ListRecipes(ctx context.Context, options dao.ListOptions) ([]dao.Recipes, error)
 	UpdateRecipes(ctx context.Context, id string, r dao.Recipes) (dao.Recipes, error)
+	RateRecipe(ctx context.Context, recipeID, userUID string, rating int) (dao.Recipes, error)
 	DeleteRecipes(ctx context.Context, id string) error
+	SetRecipePhoto(ctx context.Context, recipeID string, photos []dao.RecipePhoto) (dao.Recipes, error)
+	GetRecipePhoto(ctx context.Context, recipeID, size string) (dao.RecipePhoto, error)
+	DeleteRecipePhoto(ctx context.Context, recipeID string) error
 }
 
-type RecipesHandlers struct{ dao recipesDAO }
+type RecipesHandlers struct {
+	dao    recipesDAO
+	tagger Tagger
+	prefs  preferencesDAO
+}
+
+// RecipesOption configures the recipes router.
+type RecipesOption func(*RecipesHandlers)
 
-func NewRecipes(dao recipesDAO) http.Handler {
-	h := &RecipesHandlers{dao}
+func NewRecipes(dao recipesDAO, opts ...RecipesOption) http.Handler {
+	h := &RecipesHandlers{dao: dao}
+	for _, opt := range opts {
+		opt(h)
+	}
 	r := chi.NewRouter()
 	r.Post("/", h.create)
 	r.Get("/{id}", h.get)
 	r.Put("/{id}", h.update)
+	r.Put("/{id}/rating", h.rate)
+	r.Post("/{id}/duplicate", h.duplicate)
 	r.Delete("/{id}", h.delete)
+	r.Put("/{id}/photo", h.putPhoto)
+	r.Get("/{id}/photo", h.getPhoto)
+	r.Delete("/{id}/photo", h.deletePhoto)
+	r.Get("/{id}/export", h.export)
 	r.Get("/", h.list)
+	r.Get("/search", h.search)
 	return r
 }
 
@@ -37,22 +60,41 @@ func (h *RecipesHandlers) create(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	rating, ok := bodyRating(w, recipe.Rating)
+	if !ok {
+		return
+	}
 	recipe.ID = uuid.NewString()
+	suggested := suggestTags(r.Context(), h.tagger, recipe.Tags, recipeTagText(recipe))
+	if autoTagRequested(r) {
+		recipe.Tags = append(recipe.Tags, suggested...)
+	}
 	out, err := h.dao.CreateRecipes(r.Context(), recipe)
+	if err == nil && rating != 0 {
+		out, err = h.rateAs(r, out, recipe.UserUID, rating)
+	}
+	if quotaExceeded(w, err) {
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	_ = json.NewEncoder(w).Encode(struct {
+		dao.Recipes
+		SuggestedTags []string `json:"suggested_tags,omitempty"`
+	}{out, suggested})
 }
 
 func (h *RecipesHandlers) get(w http.ResponseWriter, r *http.Request) {
-	out, err := h.dao.GetRecipes(r.Context(), chi.URLParam(r, "id"))
+	id := chi.URLParam(r, "id")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.Recipes, error) { return h.dao.GetRecipes(ctx, id) },
+		h.dao.ListRecipes, "WHERE id = $1", id)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		writeFieldsError(w, err, http.StatusNotFound)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, withUnits(withPhotoURLs(out), householdUnits(r.Context(), h.prefs, out.HouseholdUID)))
 }
 
 func (h *RecipesHandlers) update(w http.ResponseWriter, r *http.Request) {
@@ -61,12 +103,92 @@ func (h *RecipesHandlers) update(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	rating, ok := bodyRating(w, recipe.Rating)
+	if !ok {
+		return
+	}
 	out, err := h.dao.UpdateRecipes(r.Context(), chi.URLParam(r, "id"), recipe)
+	if err == nil && rating != 0 {
+		out, err = h.rateAs(r, out, recipe.UserUID, rating)
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	_ = json.NewEncoder(w).Encode(withPhotoURLs(out))
+}
+
+type rateRecipeRequest struct {
+	Rating  *float64 `json:"rating"`
+	UserUID *string  `json:"user_uid"`
+}
+
+// rate sets the caller's rating of a recipe; without an API key the body
+// names the user.
+func (h *RecipesHandlers) rate(w http.ResponseWriter, r *http.Request) {
+	var req rateRecipeRequest
+	if json.NewDecoder(r.Body).Decode(&req) != nil || req.Rating == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": dao.ErrInvalidRating.Error()})
+		return
+	}
+	rating, ok := bodyRating(w, req.Rating)
+	if !ok {
+		return
+	}
+	userUID := rater(r, req.UserUID)
+	if userUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "user_uid is required"})
+		return
+	}
+	out, err := h.dao.RateRecipe(r.Context(), chi.URLParam(r, "id"), userUID, rating)
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(withPhotoURLs(out))
+}
+
+// bodyRating reads the rating sent with a recipe, 0 when there is none,
+// answering 400 unless it is a whole number from 1 to 5. ok is false once
+// it has answered.
+func bodyRating(w http.ResponseWriter, v *float64) (int, bool) {
+	if v == nil {
+		return 0, true
+	}
+	if *v != float64(int(*v)) || *v < 1 || *v > 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": dao.ErrInvalidRating.Error()})
+		return 0, false
+	}
+	return int(*v), true
+}
+
+// rater is the user a rating sent to the REST API is from: the API key's
+// user, or else the one given.
+func rater(r *http.Request, userUID *string) string {
+	if id, ok := IdentityFromContext(r.Context()); ok && id.UserUID != "" {
+		return id.UserUID
+	}
+	if userUID != nil {
+		return *userUID
+	}
+	return ""
+}
+
+// rateAs records the rating sent with a saved recipe. Without a user to
+// give it to, it is dropped.
+func (h *RecipesHandlers) rateAs(r *http.Request, saved dao.Recipes, userUID *string, rating int) (dao.Recipes, error) {
+	uid := rater(r, userUID)
+	if uid == "" {
+		return saved, nil
+	}
+	return h.dao.RateRecipe(r.Context(), saved.ID, uid, rating)
 }
 
 func (h *RecipesHandlers) delete(w http.ResponseWriter, r *http.Request) {
@@ -88,7 +210,10 @@ func (h *RecipesHandlers) list(w http.ResponseWriter, r *http.Request) {
 		params.Filters["cook_time"] = "<=" + maxCookTime
 	}
 	
-	whereClause, whereArgs := BuildWhereClause(params.Filters, RecipesFilters.Filters)
+	whereClause, whereArgs, ok := whereFromParams(w, params, RecipesFilters.Filters)
+	if !ok {
+		return
+	}
 
 	options := dao.ListOptions{
 		Limit:       params.Limit,
@@ -97,12 +222,13 @@ func (h *RecipesHandlers) list(w http.ResponseWriter, r *http.Request) {
 		SortDir:     params.SortDir,
 		WhereClause: whereClause,
 		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
 	}
 
 	out, err := h.dao.ListRecipes(r.Context(), options)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeFieldsError(w, err, http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(out)
+	encodeResponse(w, r, withPhotoURLsList(out))
 }
\ No newline at end of file
@@ -11,8 +11,10 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/pbdeuchler/assistant-server/dao/postgres"
 	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,7 +25,7 @@ func TestRecipesCreate(t *testing.T) {
 	cookTime := 30
 	totalTime := 45
 	servings := 4
-	rating := 5
+	rating := 5.0
 	difficulty := "medium"
 	externalURL := "https://example.com/recipe"
 	genre := "Italian"
@@ -43,8 +45,8 @@ func TestRecipesCreate(t *testing.T) {
 		Difficulty:  &difficulty,
 		Rating:      &rating,
 		Tags:        []string{"pasta", "dinner"},
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -53,11 +55,13 @@ func TestRecipesCreate(t *testing.T) {
 		mock.Anything, 
 		mock.MatchedBy(func(r postgres.Recipes) bool {
 			return r.Title == "Test Recipe" && 
-				   r.UserUID == "user-123" &&
-				   r.HouseholdUID == "household-456" &&
+				   *r.UserUID == "user-123" &&
+				   *r.HouseholdUID == "household-456" &&
 				   r.Data == "Recipe instructions here" &&
 				   len(r.Tags) == 2
 		})).Return(expectedRecipe, nil)
+	// The rating sent is the creator's.
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "generated-id", "user-123", 5).Return(expectedRecipe, nil)
 
 	handler := NewRecipes(mockRecipesDAO)
 
@@ -141,7 +145,7 @@ func TestRecipesCreateDAOError(t *testing.T) {
 func TestRecipesGet(t *testing.T) {
 	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
 	
-	rating := 4
+	rating := 4.0
 	servings := 6
 	expectedRecipe := postgres.Recipes{
 		ID:          "test-id",
@@ -150,8 +154,8 @@ func TestRecipesGet(t *testing.T) {
 		Rating:      &rating,
 		Servings:    &servings,
 		Tags:        []string{"dessert"},
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -205,20 +209,21 @@ func TestRecipesGetNotFound(t *testing.T) {
 func TestRecipesUpdate(t *testing.T) {
 	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
 	
-	rating := 5
+	rating := 5.0
 	expectedRecipe := postgres.Recipes{
 		ID:          "test-id",
 		Title:       "Updated Recipe",
 		Data:        "Updated instructions",
 		Rating:      &rating,
 		Tags:        []string{"updated"},
-		UserUID:      "user-123",
-		HouseholdUID: "household-456",
+		UserUID:      strPtr("user-123"),
+		HouseholdUID: strPtr("household-456"),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
 	mockRecipesDAO.On("UpdateRecipes", mock.Anything, "test-id", mock.AnythingOfType("postgres.Recipes")).Return(expectedRecipe, nil)
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "test-id", "user-123", 5).Return(expectedRecipe, nil)
 
 	handler := NewRecipes(mockRecipesDAO)
 
@@ -341,8 +346,8 @@ func TestRecipesDeleteError(t *testing.T) {
 func TestRecipesList(t *testing.T) {
 	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
 	
-	rating1 := 4
-	rating2 := 5
+	rating1 := 4.0
+	rating2 := 5.0
 	expectedRecipes := []postgres.Recipes{
 		{
 			ID:          "test-id-1",
@@ -350,8 +355,8 @@ func TestRecipesList(t *testing.T) {
 			Data:        "Instructions 1",
 			Rating:      &rating1,
 			Tags:        []string{"breakfast"},
-			UserUID:      "user-123",
-			HouseholdUID: "household-456",
+			UserUID:      strPtr("user-123"),
+			HouseholdUID: strPtr("household-456"),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
@@ -361,8 +366,8 @@ func TestRecipesList(t *testing.T) {
 			Data:        "Instructions 2",
 			Rating:      &rating2,
 			Tags:        []string{"dinner"},
-			UserUID:      "user-123",
-			HouseholdUID: "household-456",
+			UserUID:      strPtr("user-123"),
+			HouseholdUID: strPtr("household-456"),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
@@ -404,4 +409,50 @@ func TestRecipesListError(t *testing.T) {
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("Expected status 500, got %d", rr.Code)
 	}
-}
\ No newline at end of file
+}
+
+func TestRecipesRate(t *testing.T) {
+	average := 4.5
+	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "r1", "user-123", 4).
+		Return(postgres.Recipes{ID: "r1", Rating: &average, RatingCount: 2, MyRating: &[]int{4}[0]}, nil).Once()
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "missing", "user-123", 4).Return(postgres.Recipes{}, pgx.ErrNoRows).Once()
+	handler := NewRecipes(mockRecipesDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/r1/rating", strings.NewReader(`{"rating": 4, "user_uid": "user-123"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var out postgres.Recipes
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, 4.5, *out.Rating)
+	assert.Equal(t, 2, out.RatingCount)
+	assert.Equal(t, 4, *out.MyRating)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/missing/rating", strings.NewReader(`{"rating": 4, "user_uid": "user-123"}`)))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	for _, body := range []string{`{"rating": 6, "user_uid": "user-123"}`, `{"rating": 3.5, "user_uid": "user-123"}`, `{"user_uid": "user-123"}`, `{"rating": 4}`} {
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/r1/rating", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestMCPHandlers_RateRecipe(t *testing.T) {
+	average := 4.5
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "r1", "user-123", 5).
+		Return(postgres.Recipes{ID: "r1", Title: "Carbonara", Rating: &average, RatingCount: 2}, nil)
+	mockRecipesDAO.On("RateRecipe", mock.Anything, "r1", "user-123", 9).Return(postgres.Recipes{}, postgres.ErrInvalidRating)
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "rate_recipe", map[string]any{"recipe_id": "r1", "rating": float64(5), "user_uid": "user-123"}), &body)
+	assert.Equal(t, "Rated Carbonara 5; household average 4.5 from 2 ratings", body["summary"])
+
+	result := h.callTool(t.Context(), "rate_recipe", map[string]any{"recipe_id": "r1", "rating": float64(9), "user_uid": "user-123"})
+	assert.True(t, result.IsError)
+	result = h.callTool(t.Context(), "rate_recipe", map[string]any{"recipe_id": "r1", "rating": 4.5, "user_uid": "user-123"})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const redacted = "[REDACTED]"
+
+// DefaultSensitiveKeys are the key patterns redacted from logs when no others
+// are configured. They are case-insensitive regular expressions matched
+// against attribute names and the keys of logged maps and JSON bodies.
+var DefaultSensitiveKeys = []string{
+	`token$`,
+	`secret`,
+	`passw(or)?d`,
+	`^(x-)?api[-_]?key$`,
+	`authorization`,
+	`cookie`,
+	`private[-_]?key`,
+}
+
+// sensitiveValues catch secrets that turn up inside otherwise harmless
+// strings, such as error messages that quote a header or an API key.
+var sensitiveValues = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "$1 " + redacted},
+	{regexp.MustCompile(`\b` + apiKeyPrefix + `[A-Za-z0-9_-]{8,}`), apiKeyPrefix + redacted},
+}
+
+// Redactor masks sensitive values before they are logged.
+type Redactor struct {
+	keys []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor for the given key patterns. Each pattern
+// is a case-insensitive regular expression.
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.keys = append(r.keys, re)
+	}
+	return r, nil
+}
+
+var defaultRedactor = func() *Redactor {
+	r, _ := NewRedactor(DefaultSensitiveKeys)
+	return r
+}()
+
+var logRedactor atomic.Pointer[Redactor]
+
+// SetLogRedactor replaces the Redactor used by every logger in this package
+// and by NewRedactingHandler.
+func SetLogRedactor(r *Redactor) { logRedactor.Store(r) }
+
+func currentRedactor() *Redactor {
+	if r := logRedactor.Load(); r != nil {
+		return r
+	}
+	return defaultRedactor
+}
+
+func (r *Redactor) sensitive(key string) bool {
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of v with the values under sensitive keys masked.
+// Maps and slices are walked, and strings holding a JSON object or array
+// are redacted as JSON.
+func (r *Redactor) Redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			if r.sensitive(k) {
+				out[k] = redacted
+			} else {
+				out[k] = r.Redact(val)
+			}
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, val := range v {
+			if r.sensitive(k) {
+				out[k] = redacted
+			} else {
+				out[k] = r.redactString(val)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = r.Redact(val)
+		}
+		return out
+	case json.RawMessage:
+		return json.RawMessage(r.redactString(string(v)))
+	case string:
+		return r.redactString(v)
+	case error:
+		return r.redactString(v.Error())
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) redactString(s string) string {
+	if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var parsed any
+		if json.Unmarshal([]byte(trimmed), &parsed) == nil {
+			if out, err := json.Marshal(r.Redact(parsed)); err == nil {
+				return string(out)
+			}
+		}
+	}
+	for _, sv := range sensitiveValues {
+		s = sv.re.ReplaceAllString(s, sv.repl)
+	}
+	return s
+}
+
+// Attr redacts a single log attribute, descending into groups.
+func (r *Redactor) Attr(a slog.Attr) slog.Attr {
+	if r.sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		out := make([]any, len(attrs))
+		for i, ga := range attrs {
+			out[i] = r.Attr(ga)
+		}
+		return slog.Group(a.Key, out...)
+	case slog.KindString:
+		return slog.String(a.Key, r.redactString(v.String()))
+	case slog.KindAny:
+		return slog.Any(a.Key, r.Redact(v.Any()))
+	default:
+		return a
+	}
+}
+
+// redactingHandler redacts every attribute before passing records on.
+type redactingHandler struct{ next slog.Handler }
+
+// NewRedactingHandler wraps h so that sensitive attributes are masked using
+// the Redactor set by SetLogRedactor.
+func NewRedactingHandler(h slog.Handler) slog.Handler {
+	return redactingHandler{next: h}
+}
+
+func (h redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	r := currentRedactor()
+	out := slog.NewRecord(rec.Time, rec.Level, r.redactString(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(r.Attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	r := currentRedactor()
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = r.Attr(a)
+	}
+	return redactingHandler{next: h.next.WithAttrs(out)}
+}
+
+func (h redactingHandler) WithGroup(name string) slog.Handler {
+	return redactingHandler{next: h.next.WithGroup(name)}
+}
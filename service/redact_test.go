@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactorRedact(t *testing.T) {
+	r, err := NewRedactor(DefaultSensitiveKeys)
+	assert.NoError(t, err)
+
+	args := map[string]any{
+		"title":        "Renew passport",
+		"access_token": "ya29.abc",
+		"nested":       map[string]any{"Password": "hunter2", "key": "wifi"},
+		"items":        []any{map[string]any{"client_secret": "s3cret"}},
+	}
+	assert.Equal(t, map[string]any{
+		"title":        "Renew passport",
+		"access_token": redacted,
+		"nested":       map[string]any{"Password": redacted, "key": "wifi"},
+		"items":        []any{map[string]any{"client_secret": redacted}},
+	}, r.Redact(args))
+	assert.Equal(t, "ya29.abc", args["access_token"], "input must not be modified")
+
+	assert.Equal(t, `{"api_key":"[REDACTED]","name":"cli"}`, r.Redact(`{"name": "cli", "api_key": "ak_0123456789"}`))
+	assert.Equal(t, "refresh failed: Bearer [REDACTED] rejected", r.Redact(errors.New("refresh failed: Bearer ya29.abc rejected")))
+	assert.Equal(t, "bad key ak_[REDACTED]", r.Redact("bad key ak_abcdefghijkl"))
+	assert.Equal(t, "api_key_uid", r.Redact("api_key_uid"))
+}
+
+func TestNewRedactorRejectsBadPattern(t *testing.T) {
+	_, err := NewRedactor([]string{"("})
+	assert.Error(t, err)
+}
+
+func TestRedactingHandler(t *testing.T) {
+	r, err := NewRedactor(append(DefaultSensitiveKeys, `^ssn$`))
+	assert.NoError(t, err)
+	SetLogRedactor(r)
+	t.Cleanup(func() { SetLogRedactor(nil) })
+
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("client_secret", "s3cret"))
+	logger.Info("Creating todo",
+		slog.Any("arguments", map[string]any{"title": "Call bank", "ssn": "123-45-6789"}),
+		slog.Group("auth", slog.String("token", "abc"), slog.String("user_uid", "user-1")),
+		slog.String("api_key_uid", "key-1"),
+	)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, redacted, entry["client_secret"])
+	assert.Equal(t, map[string]any{"title": "Call bank", "ssn": redacted}, entry["arguments"])
+	assert.Equal(t, map[string]any{"token": redacted, "user_uid": "user-1"}, entry["auth"])
+	assert.Equal(t, "key-1", entry["api_key_uid"])
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type retentionDAO interface {
+	CreateRetentionPolicy(ctx context.Context, p dao.RetentionPolicy) (dao.RetentionPolicy, error)
+	ListRetentionPolicies(ctx context.Context) ([]dao.RetentionPolicy, error)
+	DeleteRetentionPolicy(ctx context.Context, uid string) error
+	ArchiveNotesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error)
+	DeleteNotesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error)
+	DeleteCompletedTodosBefore(ctx context.Context, householdUID string, before time.Time) (int64, error)
+	DeleteGroceryPurchasesBefore(ctx context.Context, householdUID string, before time.Time) (int64, error)
+}
+
+// retentionActions are the actions each entity supports. Todos and grocery
+// purchases have nowhere to be archived to, and only notes can be
+// summarized.
+var retentionActions = map[string][]string{
+	dao.RetentionEntityNotes:            {dao.RetentionArchive, dao.RetentionDelete, dao.RetentionSummarize},
+	dao.RetentionEntityTodos:            {dao.RetentionDelete},
+	dao.RetentionEntityGroceryPurchases: {dao.RetentionDelete},
+}
+
+func validateRetentionPolicy(p dao.RetentionPolicy) error {
+	actions, ok := retentionActions[p.Entity]
+	if !ok {
+		return fmt.Errorf("unknown entity %q", p.Entity)
+	}
+	if !slices.Contains(actions, p.Action) {
+		return fmt.Errorf("%s can't be kept with action %q", p.Entity, p.Action)
+	}
+	if p.MaxAgeDays <= 0 {
+		return errors.New("max_age_days must be positive")
+	}
+	return nil
+}
+
+// Retention enforces the retention policies. A household's own policy and
+// the policy for every household both apply, so the stricter one wins.
+type Retention struct {
+	dao       retentionDAO
+	summaries *NoteSummaries
+	interval  time.Duration
+}
+
+// NewRetention enforces the policies every interval. summaries may be nil,
+// in which case summarize policies fail and are logged.
+func NewRetention(d retentionDAO, summaries *NoteSummaries, interval time.Duration) *Retention {
+	return &Retention{dao: d, summaries: summaries, interval: interval}
+}
+
+// Job enforces the policies every interval. Enforcing a policy twice does
+// no harm, so failed runs are retried.
+func (r *Retention) Job() Job {
+	return Job{
+		Name:        "retention",
+		Schedule:    Every(r.interval),
+		MaxAttempts: 3,
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return r.EnforceDue(ctx, run.RunAt)
+		},
+	}
+}
+
+// EnforceDue applies every policy to the records older than its maximum age
+// at now. Failures for one policy are logged and don't stop the others;
+// EnforceDue then reports how many failed.
+func (r *Retention) EnforceDue(ctx context.Context, now time.Time) error {
+	policies, err := r.dao.ListRetentionPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("listing retention policies: %w", err)
+	}
+	failed := 0
+	for _, p := range policies {
+		household := ""
+		if p.HouseholdUID != nil {
+			household = *p.HouseholdUID
+		}
+		before := now.AddDate(0, 0, -p.MaxAgeDays)
+		n, err := r.enforce(ctx, p, household, before)
+		if err != nil {
+			slog.Error("Failed to enforce retention policy", "uid", p.UID, "entity", p.Entity, "household_uid", household, "error", err)
+			failed++
+			continue
+		}
+		if n > 0 {
+			slog.Info("Enforced retention policy", "uid", p.UID, "entity", p.Entity, "action", p.Action, "household_uid", household, "records", n)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d retention policies failed to enforce", failed)
+	}
+	return nil
+}
+
+func (r *Retention) enforce(ctx context.Context, p dao.RetentionPolicy, household string, before time.Time) (int64, error) {
+	switch {
+	case p.Entity == dao.RetentionEntityNotes && p.Action == dao.RetentionArchive:
+		return r.dao.ArchiveNotesBefore(ctx, household, before)
+	case p.Entity == dao.RetentionEntityNotes && p.Action == dao.RetentionDelete:
+		return r.dao.DeleteNotesBefore(ctx, household, before)
+	case p.Entity == dao.RetentionEntityNotes && p.Action == dao.RetentionSummarize:
+		if r.summaries == nil {
+			return 0, errors.New("summarizing notes needs LLM_URL")
+		}
+		return 0, r.summaries.SummarizeBefore(ctx, household, before)
+	case p.Entity == dao.RetentionEntityTodos && p.Action == dao.RetentionDelete:
+		return r.dao.DeleteCompletedTodosBefore(ctx, household, before)
+	case p.Entity == dao.RetentionEntityGroceryPurchases && p.Action == dao.RetentionDelete:
+		return r.dao.DeleteGroceryPurchasesBefore(ctx, household, before)
+	}
+	return 0, fmt.Errorf("%s can't be kept with action %q", p.Entity, p.Action)
+}
+
+type RetentionPolicyHandlers struct{ dao retentionDAO }
+
+// NewRetentionPolicies manages retention policies. Only operators may change
+// them; callers with a key see the policies that apply to their household.
+func NewRetentionPolicies(dao retentionDAO) http.Handler {
+	h := &RetentionPolicyHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.With(operatorsOnly).Post("/", h.create)
+	r.Get("/", h.list)
+	r.With(operatorsOnly).Delete("/{uid}", h.delete)
+	return r
+}
+
+// create adds a policy, replacing any for the same entity and household.
+func (h *RetentionPolicyHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var p dao.RetentionPolicy
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if p.HouseholdUID != nil && *p.HouseholdUID == "" {
+		p.HouseholdUID = nil
+	}
+	if err := validateRetentionPolicy(p); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateRetentionPolicy(r.Context(), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *RetentionPolicyHandlers) list(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.ListRetentionPolicies(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok {
+		out = slices.DeleteFunc(out, func(p dao.RetentionPolicy) bool {
+			return p.HouseholdUID != nil && *p.HouseholdUID != id.HouseholdUID
+		})
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *RetentionPolicyHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteRetentionPolicy(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	assert.NoError(t, validateRetentionPolicy(postgres.RetentionPolicy{Entity: "notes", Action: "summarize", MaxAgeDays: 365}))
+	assert.NoError(t, validateRetentionPolicy(postgres.RetentionPolicy{Entity: "todos", Action: "delete", MaxAgeDays: 90}))
+	for _, p := range []postgres.RetentionPolicy{
+		{Entity: "recipes", Action: "delete", MaxAgeDays: 30},
+		{Entity: "todos", Action: "archive", MaxAgeDays: 30},
+		{Entity: "grocery_purchases", Action: "summarize", MaxAgeDays: 30},
+		{Entity: "notes", Action: "delete"},
+	} {
+		assert.Error(t, validateRetentionPolicy(p), p)
+	}
+}
+
+func TestRetentionEnforceDue(t *testing.T) {
+	now := time.Date(2025, 8, 31, 3, 0, 0, 0, time.UTC)
+	d := mocks.NewMockretentionDAO(t)
+	d.On("ListRetentionPolicies", mock.Anything).Return([]postgres.RetentionPolicy{
+		{UID: "p1", Entity: "notes", Action: "archive", MaxAgeDays: 365},
+		{UID: "p2", Entity: "todos", Action: "delete", MaxAgeDays: 30, HouseholdUID: strPtr("house-1")},
+		{UID: "p3", Entity: "grocery_purchases", Action: "delete", MaxAgeDays: 730},
+		{UID: "p4", Entity: "notes", Action: "summarize", MaxAgeDays: 90, HouseholdUID: strPtr("house-2")},
+	}, nil)
+	d.On("ArchiveNotesBefore", mock.Anything, "", now.AddDate(-1, 0, 0)).Return(int64(4), nil)
+	d.On("DeleteCompletedTodosBefore", mock.Anything, "house-1", now.AddDate(0, 0, -30)).Return(int64(0), errors.New("boom"))
+	d.On("DeleteGroceryPurchasesBefore", mock.Anything, "", now.AddDate(0, 0, -730)).Return(int64(12), nil)
+
+	notes := mocks.NewMocknoteSummaryDAO(t)
+	notes.On("ListNotes", mock.Anything, postgres.ListOptions{
+		Limit:       noteSummaryBatch,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: "WHERE NOT pinned AND archived_at IS NULL AND visibility <> $1 AND updated_at < $2 AND household_uid = $3",
+		WhereArgs:   []any{postgres.NoteVisibilitySharedLink, now.AddDate(0, 0, -90), "house-2"},
+	}).Return([]postgres.Notes{}, nil)
+
+	// A failing policy doesn't stop the ones after it.
+	NewRetention(d, NewNoteSummaries(notes, &fakeSummarizer{}, time.Hour, time.Hour), time.Hour).EnforceDue(t.Context(), now)
+}
+
+func TestRetentionSummarizeWithoutLLM(t *testing.T) {
+	d := mocks.NewMockretentionDAO(t)
+	_, err := NewRetention(d, nil, time.Hour).enforce(t.Context(), postgres.RetentionPolicy{Entity: "notes", Action: "summarize"}, "", time.Now())
+	assert.ErrorContains(t, err, "LLM_URL")
+}
+
+func TestRetentionPolicyHandlers(t *testing.T) {
+	d := mocks.NewMockretentionDAO(t)
+	d.On("CreateRetentionPolicy", mock.Anything, postgres.RetentionPolicy{Entity: "notes", Action: "delete", MaxAgeDays: 730}).
+		Return(postgres.RetentionPolicy{UID: "p1"}, nil)
+	d.On("ListRetentionPolicies", mock.Anything).Return([]postgres.RetentionPolicy{
+		{UID: "p1"},
+		{UID: "p2", HouseholdUID: strPtr("house-1")},
+		{UID: "p3", HouseholdUID: strPtr("house-2")},
+	}, nil)
+	d.On("DeleteRetentionPolicy", mock.Anything, "p1").Return(nil)
+	handler := NewRetentionPolicies(d)
+	asMember := func(r *http.Request) *http.Request { return r.WithContext(identityContext("user-1", "house-1")) }
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"entity": "notes", "action": "delete", "max_age_days": 730, "household_uid": ""}`))))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("POST", "/", strings.NewReader(`{"entity": "todos", "action": "archive", "max_age_days": 30}`))))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "todos can't be kept with action")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("POST", "/", strings.NewReader(`{"entity": "notes", "action": "delete", "max_age_days": 1}`))))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"p2"`)
+	assert.NotContains(t, rr.Body.String(), `"uid":"p3"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asMember(httptest.NewRequest("DELETE", "/p1", nil)))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("DELETE", "/p1", nil)))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
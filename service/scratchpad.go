@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type scratchpadDAO interface {
+	PutBackground(ctx context.Context, b dao.Background) (dao.Background, error)
+	GetBackground(ctx context.Context, key string) (dao.Background, error)
+	ListBackgroundsByPrefix(ctx context.Context, prefix string) ([]dao.Background, error)
+	DeleteExpiredBackgrounds(ctx context.Context) (int64, error)
+}
+
+const (
+	// scratchpadKeyPrefix starts the background keys of scratchpad entries,
+	// which are scratchpad:<owner>:<conversation>:<key>.
+	scratchpadKeyPrefix = "scratchpad:"
+	// maxScratchpadTTL bounds how long an entry may be kept.
+	maxScratchpadTTL = 7 * 24 * time.Hour
+	// maxScratchpadName bounds entry keys and conversation IDs, so
+	// namespaced keys fit the backgrounds table.
+	maxScratchpadName = 64
+	// scratchpadCleanupInterval is how often expired entries are deleted.
+	scratchpadCleanupInterval = time.Hour
+)
+
+// Scratchpads give the assistant working memory for a conversation, apart
+// from the notes users see. Entries are backgrounds namespaced by the
+// caller and the conversation, which lapse after a TTL and are deleted by
+// the scratchpad_cleanup job.
+type Scratchpads struct {
+	dao scratchpadDAO
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewScratchpads keeps entries for ttl unless they are set with another.
+func NewScratchpads(d scratchpadDAO, ttl time.Duration) *Scratchpads {
+	return &Scratchpads{dao: d, ttl: ttl, now: time.Now}
+}
+
+// Job deletes expired entries, and any other expired backgrounds, every
+// scratchpadCleanupInterval. Deleting twice does no harm, so failed runs
+// are retried.
+func (s *Scratchpads) Job() Job {
+	return Job{
+		Name:        "scratchpad_cleanup",
+		Schedule:    Every(scratchpadCleanupInterval),
+		MaxAttempts: 3,
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			n, err := s.dao.DeleteExpiredBackgrounds(ctx)
+			if err != nil {
+				return fmt.Errorf("deleting expired backgrounds: %w", err)
+			}
+			if n > 0 {
+				slog.Info("Deleted expired scratchpad entries", "entries", n)
+			}
+			return nil
+		},
+	}
+}
+
+// WithScratchpads enables the scratchpad_set and scratchpad_get tools.
+func WithScratchpads(s *Scratchpads) MCPOption {
+	return func(h *MCPHandlers) {
+		h.scratchpads = s
+	}
+}
+
+// ScratchpadEntry is an entry as the tools show it, without its namespace.
+type ScratchpadEntry struct {
+	Key       string     `json:"key"`
+	Value     string     `json:"value"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func scratchpadEntry(b dao.Background, namespace string) ScratchpadEntry {
+	return ScratchpadEntry{Key: strings.TrimPrefix(b.Key, namespace), Value: b.Value, UpdatedAt: b.UpdatedAt, ExpiresAt: b.ExpiresAt}
+}
+
+// scratchpadNamespace is the key prefix of the caller's entries for the
+// conversation named by the conversation_id argument or, without one, the
+// MCP session.
+func scratchpadNamespace(ctx context.Context, arguments map[string]any) (string, error) {
+	conversation, _ := arguments["conversation_id"].(string)
+	if conversation == "" {
+		if s, ok := sessionFrom(ctx); ok {
+			conversation = s.ID
+		}
+	}
+	switch {
+	case conversation == "":
+		return "", errors.New("conversation_id is required outside an MCP session")
+	case len(conversation) > maxScratchpadName || strings.Contains(conversation, ":"):
+		return "", fmt.Errorf("conversation_id must be at most %d characters, without colons", maxScratchpadName)
+	}
+	owner := "operator"
+	if id, ok := IdentityFromContext(ctx); ok {
+		owner = firstNonEmpty(id.UserUID, id.APIKeyUID)
+	}
+	return scratchpadKeyPrefix + owner + ":" + conversation + ":", nil
+}
+
+func (h *MCPHandlers) handleScratchpadSet(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	key, _ := arguments["key"].(string)
+	if key == "" || len(key) > maxScratchpadName {
+		return toolError("key is required, and at most %d characters", maxScratchpadName)
+	}
+	value, ok := arguments["value"].(string)
+	if !ok {
+		return toolError("value is required")
+	}
+	ttl := h.scratchpads.ttl
+	if m, ok := arguments["ttl_minutes"].(float64); ok {
+		ttl = time.Duration(m) * time.Minute
+		if ttl <= 0 || ttl > maxScratchpadTTL {
+			return toolError("ttl_minutes must be between 1 and %d", int(maxScratchpadTTL.Minutes()))
+		}
+	}
+	namespace, err := scratchpadNamespace(ctx, arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+
+	expires := h.scratchpads.now().Add(ttl)
+	saved, err := h.scratchpads.dao.PutBackground(ctx, dao.Background{Key: namespace + key, Value: value, ExpiresAt: &expires})
+	if err != nil {
+		return toolError("Failed to save scratchpad entry: %v", err)
+	}
+	return toolOK("Scratchpad entry saved", map[string]any{"entry": scratchpadEntry(saved, namespace)})
+}
+
+func (h *MCPHandlers) handleScratchpadGet(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	namespace, err := scratchpadNamespace(ctx, arguments)
+	if err != nil {
+		return toolError("%v", err)
+	}
+
+	if key, _ := arguments["key"].(string); key != "" {
+		b, err := h.scratchpads.dao.GetBackground(ctx, namespace+key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return toolError("Nothing is in the scratchpad under %s", key)
+		}
+		if err != nil {
+			return toolError("Failed to get scratchpad entry: %v", err)
+		}
+		return toolOK("Scratchpad entry found", map[string]any{"entry": scratchpadEntry(b, namespace)})
+	}
+
+	saved, err := h.scratchpads.dao.ListBackgroundsByPrefix(ctx, namespace)
+	if err != nil {
+		return toolError("Failed to list scratchpad entries: %v", err)
+	}
+	entries := make([]ScratchpadEntry, 0, len(saved))
+	for _, b := range saved {
+		entries = append(entries, scratchpadEntry(b, namespace))
+	}
+	return toolOK(fmt.Sprintf("Found %d scratchpad entries", len(entries)), map[string]any{"entries": entries, "count": len(entries)})
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPHandlers_Scratchpad(t *testing.T) {
+	d := mocks.NewMockscratchpadDAO(t)
+	pads := NewScratchpads(d, 24*time.Hour)
+	now := time.Date(2025, 9, 27, 9, 0, 0, 0, time.UTC)
+	pads.now = func() time.Time { return now }
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithScratchpads(pads), WithBackgroundDAO(mocks.NewMockbackgroundDAO(t)))
+	ctx := withSession(identityContext("user-1", "house-1"), &mcpSession{ID: "session-1"})
+	const ns = "scratchpad:user-1:session-1:"
+
+	put := func(key string, expires time.Time) {
+		d.On("PutBackground", mock.Anything, mock.MatchedBy(func(b postgres.Background) bool {
+			return b.Key == ns+key && b.ExpiresAt.Equal(expires)
+		})).Return(postgres.Background{Key: ns + key, Value: "tacos, soup", ExpiresAt: &expires}, nil).Once()
+	}
+	put("candidates", now.Add(24*time.Hour))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "scratchpad_set", map[string]any{"key": "candidates", "value": "tacos, soup"}), &body)
+	require.Equal(t, "Scratchpad entry saved", body["summary"], body["error"])
+	assert.Equal(t, "candidates", body["entry"].(map[string]any)["key"])
+
+	put("shortlist", now.Add(30*time.Minute))
+	body = nil
+	decodeToolResult(t, h.callTool(ctx, "scratchpad_set", map[string]any{"key": "shortlist", "value": "tacos", "ttl_minutes": float64(30)}), &body)
+	assert.Equal(t, "Scratchpad entry saved", body["summary"], body["error"])
+
+	d.On("GetBackground", mock.Anything, ns+"candidates").Return(postgres.Background{Key: ns + "candidates", Value: "tacos, soup"}, nil)
+	d.On("GetBackground", mock.Anything, ns+"missing").Return(postgres.Background{}, pgx.ErrNoRows)
+	body = nil
+	decodeToolResult(t, h.callTool(ctx, "scratchpad_get", map[string]any{"key": "candidates"}), &body)
+	assert.Equal(t, "tacos, soup", body["entry"].(map[string]any)["value"])
+	body = nil
+	decodeToolResult(t, h.callTool(ctx, "scratchpad_get", map[string]any{"key": "missing"}), &body)
+	assert.Equal(t, "Nothing is in the scratchpad under missing", body["error"])
+
+	// A conversation_id names the conversation outside a session, or
+	// another one within it.
+	d.On("ListBackgroundsByPrefix", mock.Anything, "scratchpad:user-1:trip-planning:").Return([]postgres.Background{
+		{Key: "scratchpad:user-1:trip-planning:flights", Value: "LIS 09:40"},
+		{Key: "scratchpad:user-1:trip-planning:hotels", Value: "Alfama"},
+	}, nil)
+	body = nil
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "scratchpad_get", map[string]any{"conversation_id": "trip-planning"}), &body)
+	assert.Equal(t, float64(2), body["count"])
+	assert.Equal(t, "hotels", body["entries"].([]any)[1].(map[string]any)["key"])
+
+	for _, c := range []struct {
+		session bool
+		tool    string
+		args    map[string]any
+		want    string
+	}{
+		{false, "scratchpad_get", map[string]any{}, "conversation_id is required outside an MCP session"},
+		{false, "scratchpad_get", map[string]any{"conversation_id": "a:b"}, "without colons"},
+		{true, "scratchpad_set", map[string]any{"key": "k", "value": "v", "ttl_minutes": float64(20000)}, "ttl_minutes must be between 1 and 10080"},
+		{true, "scratchpad_set", map[string]any{"value": "v"}, "key is required"},
+		{true, "get_background", map[string]any{"key": ns + "candidates"}, "belong to scratchpads"},
+		{true, "set_background", map[string]any{"key": "scratchpad:user-2:x:y", "value": "v"}, "belong to scratchpads"},
+	} {
+		callCtx := identityContext("user-1", "house-1")
+		if c.session {
+			callCtx = ctx
+		}
+		body = nil
+		decodeToolResult(t, h.callTool(callCtx, c.tool, c.args), &body)
+		assert.Contains(t, body["error"], c.want, c.tool)
+	}
+}
+
+func TestScratchpadCleanupJob(t *testing.T) {
+	d := mocks.NewMockscratchpadDAO(t)
+	d.On("DeleteExpiredBackgrounds", mock.Anything).Return(int64(3), nil)
+	job := NewScratchpads(d, time.Hour).Job()
+	assert.Equal(t, "scratchpad_cleanup", job.Name)
+	require.NoError(t, job.Run(t.Context(), postgres.JobRun{}))
+}
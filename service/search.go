@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+type searchDAO interface {
+	Search(ctx context.Context, target, query string, options dao.ListOptions) ([]dao.SearchHit, error)
+}
+
+// SearchGroup is the hits of one entity type, best first.
+type SearchGroup struct {
+	Type    string          `json:"type"`
+	Results []dao.SearchHit `json:"results"`
+}
+
+// SearchResults groups the hits of a search by entity type. The group with
+// the best hit comes first; empty groups come last.
+type SearchResults struct {
+	Query  string        `json:"query"`
+	Groups []SearchGroup `json:"groups"`
+}
+
+type searchHandlers struct {
+	dao searchDAO
+}
+
+// NewSearch serves GET /search?q=..., a full-text search across todos,
+// notes and recipes for dashboard search boxes. types narrows it to some of
+// them, limit (1 to 50) caps the hits per type and household_uid keeps only
+// that household's rows. Notes are searched only as far as the caller may
+// read them.
+func NewSearch(d searchDAO) http.Handler {
+	h := &searchHandlers{dao: d}
+	r := chi.NewRouter()
+	r.Get("/", h.search)
+	return r
+}
+
+func (h *searchHandlers) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		writeSearchError(w, "q is required")
+		return
+	}
+	types := dao.SearchTargets
+	if v := query.Get("types"); v != "" {
+		types = nil
+		for _, t := range splitTags(v) {
+			if !slices.Contains(dao.SearchTargets, t) {
+				writeSearchError(w, "unknown type "+t+": types takes "+strings.Join(dao.SearchTargets, ", "))
+				return
+			}
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	limit := defaultSearchLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			writeSearchError(w, "limit must be from 1 to "+strconv.Itoa(maxSearchLimit))
+			return
+		}
+		limit = n
+	}
+
+	out := SearchResults{Query: q, Groups: []SearchGroup{}}
+	for _, t := range types {
+		var whereClause string
+		var whereArgs []any
+		if householdUID := query.Get("household_uid"); householdUID != "" {
+			whereClause, whereArgs = "WHERE household_uid = $1", []any{householdUID}
+		}
+		if t == "notes" {
+			whereClause, whereArgs = withNoteVisibility(r.Context(), whereClause, whereArgs)
+		}
+		hits, err := h.dao.Search(r.Context(), t, q, dao.ListOptions{Limit: limit, WhereClause: whereClause, WhereArgs: whereArgs})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		out.Groups = append(out.Groups, SearchGroup{Type: t, Results: hits})
+	}
+	slices.SortStableFunc(out.Groups, func(a, b SearchGroup) int {
+		switch {
+		case len(a.Results) == 0 || len(b.Results) == 0:
+			return len(b.Results) - len(a.Results)
+		case a.Results[0].Rank > b.Results[0].Rank:
+			return -1
+		case a.Results[0].Rank < b.Results[0].Rank:
+			return 1
+		}
+		return 0
+	})
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func writeSearchError(w http.ResponseWriter, msg string) {
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	d := mocks.NewMocksearchDAO(t)
+	d.On("Search", mock.Anything, "recipes", "tacos", mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.Limit == 5 && o.WhereClause == "WHERE household_uid = $1"
+	})).Return([]postgres.SearchHit{{ID: "r1", Title: "Weeknight tacos", Snippet: "<mark>tacos</mark> with slaw", Rank: 0.6}}, nil)
+	d.On("Search", mock.Anything, "notes", "tacos", mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1 AND (user_uid = $2 OR (visibility <> 'private' AND household_uid = $3))"
+	})).Return([]postgres.SearchHit{{ID: "n1", Title: "taco-night", Rank: 0.9}}, nil)
+	d.On("Search", mock.Anything, "todos", "tacos", mock.Anything).Return([]postgres.SearchHit{}, nil)
+	handler := NewSearch(d)
+
+	req := httptest.NewRequest("GET", "/?q=tacos&types=todos,recipes,notes,todos&limit=5&household_uid=house-1", nil)
+	req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1", HouseholdUID: "house-1"}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var out SearchResults
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&out))
+	assert.Equal(t, "tacos", out.Query)
+	// Groups are ordered by their best hit, with empty ones last.
+	require.Len(t, out.Groups, 3)
+	assert.Equal(t, []string{"notes", "recipes", "todos"}, []string{out.Groups[0].Type, out.Groups[1].Type, out.Groups[2].Type})
+	assert.Equal(t, "<mark>tacos</mark> with slaw", out.Groups[1].Results[0].Snippet)
+	d.AssertNumberOfCalls(t, "Search", 3)
+}
+
+func TestSearchValidatesParams(t *testing.T) {
+	handler := NewSearch(mocks.NewMocksearchDAO(t))
+	for target, want := range map[string]string{
+		"/":                      "q is required",
+		"/?q=tacos&types=pantry": "unknown type pantry",
+		"/?q=tacos&limit=0":      "limit must be from 1 to 50",
+		"/?q=tacos&limit=lots":   "limit must be from 1 to 50",
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), want, target)
+	}
+}
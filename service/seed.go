@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+)
+
+type seedDAO interface {
+	fixtures.Store
+	CreateAPIKey(ctx context.Context, k dao.APIKeys) (dao.APIKeys, error)
+}
+
+// SeededUser is a seeded user with the plaintext of the API key made for
+// them, which isn't stored anywhere else.
+type SeededUser struct {
+	User   dao.Users `json:"user"`
+	APIKey string    `json:"api_key"`
+}
+
+// SeedResult is what Seed created.
+type SeedResult struct {
+	Household   dao.Households `json:"household"`
+	Users       []SeededUser   `json:"users"`
+	Todos       int            `json:"todos"`
+	Notes       int            `json:"notes"`
+	Recipes     int            `json:"recipes"`
+	Preferences int            `json:"preferences"`
+}
+
+// Seed inserts set, such as fixtures.Demo(), with due dates relative to
+// now, and gives each of its users an mcp:write API key. Every call
+// creates a new household; see fixtures.Insert.
+func Seed(ctx context.Context, d seedDAO, set fixtures.Set, now time.Time) (SeedResult, error) {
+	inserted, err := fixtures.Insert(ctx, d, set, now)
+	if err != nil {
+		return SeedResult{}, err
+	}
+	out := SeedResult{
+		Household:   inserted.Household,
+		Todos:       len(inserted.Todos),
+		Notes:       len(inserted.Notes),
+		Recipes:     len(inserted.Recipes),
+		Preferences: len(inserted.Preferences),
+	}
+	for _, user := range inserted.Users {
+		key, err := generateAPIKey()
+		if err != nil {
+			return out, err
+		}
+		if _, err := d.CreateAPIKey(ctx, dao.APIKeys{UserUID: user.UID, Name: "seed", KeyHash: hashAPIKey(key), Scopes: []string{ScopeMCPWrite}}); err != nil {
+			return out, fmt.Errorf("creating API key for %s: %w", user.Name, err)
+		}
+		out.Users = append(out.Users, SeededUser{User: user, APIKey: key})
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/fixtures"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+	d := mocks.NewMockseedDAO(t)
+	d.On("CreateHousehold", mock.Anything, mock.Anything).Return(postgres.Households{UID: "0d9c6f1e-demo", Name: "The Riveras"}, nil)
+	d.On("CreateUser", mock.Anything, mock.Anything).Return(func(_ context.Context, u postgres.Users) (postgres.Users, error) {
+		u.UID = "user-" + strings.Split(u.Email, "+")[0]
+		return u, nil
+	})
+	var keys []postgres.APIKeys
+	d.On("CreateAPIKey", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		keys = append(keys, args.Get(1).(postgres.APIKeys))
+	}).Return(postgres.APIKeys{}, nil)
+	var todos []postgres.Todo
+	d.On("CreateTodos", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		todos = args.Get(1).([]postgres.Todo)
+	}).Return(func(_ context.Context, todos []postgres.Todo) ([]postgres.Todo, error) { return todos, nil })
+	d.On("CreateNotes", mock.Anything, mock.Anything).Return(postgres.Notes{}, nil)
+	d.On("CreateRecipes", mock.Anything, mock.Anything).Return(postgres.Recipes{}, nil)
+	var prefs []postgres.Preferences
+	d.On("CreatePreferences", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		prefs = append(prefs, args.Get(1).(postgres.Preferences))
+	}).Return(postgres.Preferences{}, nil)
+
+	res, err := Seed(t.Context(), d, fixtures.Demo(), now)
+	require.NoError(t, err)
+	require.Len(t, res.Users, 2)
+	assert.Equal(t, "alex+0d9c6f1e@example.com", res.Users[0].User.Email)
+	assert.Equal(t, "0d9c6f1e-demo", *res.Users[0].User.HouseholdUID)
+	for i, u := range res.Users {
+		assert.True(t, strings.HasPrefix(u.APIKey, apiKeyPrefix))
+		assert.Equal(t, hashAPIKey(u.APIKey), keys[i].KeyHash)
+		assert.Equal(t, u.User.UID, keys[i].UserUID)
+	}
+	assert.Equal(t, len(todos), res.Todos)
+	assert.Equal(t, 2, res.Recipes)
+	assert.Equal(t, 3, res.Notes)
+	assert.Equal(t, "user-alex", prefs[0].Specifier)
+	assert.Equal(t, now.AddDate(0, 0, 3), *todos[0].DueDate)
+	for _, todo := range todos {
+		assert.Equal(t, "{}", todo.Data)
+	}
+}
+
+func TestSeedStopsAtFirstFailure(t *testing.T) {
+	d := mocks.NewMockseedDAO(t)
+	d.On("CreateHousehold", mock.Anything, mock.Anything).Return(postgres.Households{}, errors.New("relation \"households\" does not exist"))
+
+	_, err := Seed(t.Context(), d, fixtures.Demo(), time.Now())
+	assert.ErrorContains(t, err, "creating household")
+}
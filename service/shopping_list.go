@@ -0,0 +1,198 @@
+package service
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+)
+
+// shoppingEpsilon is the smallest amount still worth buying; anything less
+// is rounding left over from unit conversions.
+const shoppingEpsilon = 1e-6
+
+// ShoppingList is what to buy for a set of recipes. InPantry lists what the
+// recipes need that the pantry already covers. Aisles groups Items by
+// category once they are categorized.
+type ShoppingList struct {
+	Items    []ShoppingListItem `json:"items"`
+	InPantry []ShoppingListItem `json:"in_pantry,omitempty"`
+	Aisles   []ShoppingAisle    `json:"aisles,omitempty"`
+}
+
+// ShoppingListItem is one ingredient, with the titles of the recipes that
+// call for it. Quantity is empty when no recipe gave an amount, and joins
+// amounts that cannot be added up with "+", e.g. "2 cup + 3".
+type ShoppingListItem struct {
+	Item     string   `json:"item"`
+	Quantity string   `json:"quantity,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Recipes  []string `json:"recipes"`
+}
+
+// ShoppingAisle is what to pick up in one category, each item with its
+// quantity in front, e.g. "3 eggs".
+type ShoppingAisle struct {
+	Category string   `json:"category"`
+	Items    []string `json:"items"`
+}
+
+// shoppingNeed collects one ingredient across recipes. Each quantity holds
+// amounts that could be converted into each other.
+type shoppingNeed struct {
+	name       string
+	quantities []measurement.Quantity
+	recipes    []string
+}
+
+// parseGroceryList splits a recipe's grocery list, stored either as a JSON
+// array of strings or as comma or newline separated text.
+func parseGroceryList(s string) []string {
+	var lines []string
+	if json.Unmarshal([]byte(s), &lines) != nil {
+		lines = strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' })
+	}
+	out := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// ingredientKey matches ingredients regardless of case and a plural "s", so
+// "Eggs" on a recipe finds "egg" in the pantry.
+func ingredientKey(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), "s")
+}
+
+// buildShoppingList adds up the grocery lists of recipes and subtracts what
+// pantry holds. Pantry items tracked without a quantity cover the whole
+// need. Quantities are shown in units, or as the recipes give them when it
+// is empty.
+func buildShoppingList(recipes []dao.Recipes, pantry []dao.PantryItem, units measurement.System) ShoppingList {
+	var needs []*shoppingNeed
+	byKey := map[string]*shoppingNeed{}
+	for _, recipe := range recipes {
+		if recipe.GroceryList == nil {
+			continue
+		}
+		for _, line := range parseGroceryList(*recipe.GroceryList) {
+			ingredient := measurement.ParseIngredient(line)
+			if ingredient.Name == "" {
+				continue
+			}
+			key := ingredientKey(ingredient.Name)
+			need, ok := byKey[key]
+			if !ok {
+				need = &shoppingNeed{name: ingredient.Name}
+				byKey[key] = need
+				needs = append(needs, need)
+			}
+			if len(need.recipes) == 0 || need.recipes[len(need.recipes)-1] != recipe.Title {
+				need.recipes = append(need.recipes, recipe.Title)
+			}
+			if ingredient.Quantity != nil {
+				need.add(*ingredient.Quantity)
+			}
+		}
+	}
+
+	covered := map[*shoppingNeed]bool{}
+	for _, p := range pantry {
+		need, ok := byKey[ingredientKey(p.Item)]
+		if !ok {
+			continue
+		}
+		if p.Quantity == nil || len(need.quantities) == 0 {
+			covered[need] = covered[need] || p.Quantity == nil || *p.Quantity > 0
+			continue
+		}
+		unit, err := measurement.ParseUnit(p.Unit)
+		if err != nil {
+			continue
+		}
+		need.subtract(measurement.Quantity{Amount: *p.Quantity, Unit: unit})
+	}
+
+	list := ShoppingList{Items: []ShoppingListItem{}}
+	for _, need := range needs {
+		var amounts []string
+		for _, q := range need.quantities {
+			if q.Amount > shoppingEpsilon {
+				amounts = append(amounts, displayQuantity(q, units).String())
+			}
+		}
+		item := ShoppingListItem{Item: need.name, Quantity: strings.Join(amounts, " + "), Recipes: need.recipes}
+		if covered[need] || (len(need.quantities) > 0 && len(amounts) == 0) {
+			list.InPantry = append(list.InPantry, item)
+			continue
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list
+}
+
+func (n *shoppingNeed) add(q measurement.Quantity) {
+	for i, have := range n.quantities {
+		if sum, err := have.Add(q, n.name); err == nil {
+			n.quantities[i] = sum
+			return
+		}
+	}
+	n.quantities = append(n.quantities, q)
+}
+
+// subtract takes stock off the amounts it can be converted into, until it
+// runs out.
+func (n *shoppingNeed) subtract(stock measurement.Quantity) {
+	for i, need := range n.quantities {
+		if stock.Amount <= shoppingEpsilon {
+			return
+		}
+		available, err := stock.Convert(need.Unit, n.name)
+		if err != nil {
+			continue
+		}
+		used := min(available.Amount, need.Amount)
+		n.quantities[i].Amount -= used
+		stock.Amount -= stock.Amount * used / available.Amount
+	}
+}
+
+// categorize sets the category of each item from categories, keyed by
+// ingredientKey, sorts Items into the order of GroceryCategories and groups
+// them into Aisles.
+func (l *ShoppingList) categorize(categories map[string]string) {
+	for _, items := range [][]ShoppingListItem{l.Items, l.InPantry} {
+		for i := range items {
+			if items[i].Category = categories[ingredientKey(items[i].Item)]; items[i].Category == "" {
+				items[i].Category = otherCategory
+			}
+		}
+	}
+	aisle := func(item ShoppingListItem) int { return slices.Index(GroceryCategories, item.Category) }
+	slices.SortStableFunc(l.Items, func(a, b ShoppingListItem) int { return aisle(a) - aisle(b) })
+
+	l.Aisles = groupAisles(l.Items)
+}
+
+// groupAisles groups items, already in aisle order, by category.
+func groupAisles(items []ShoppingListItem) []ShoppingAisle {
+	aisles := []ShoppingAisle{}
+	for _, item := range items {
+		if len(aisles) == 0 || aisles[len(aisles)-1].Category != item.Category {
+			aisles = append(aisles, ShoppingAisle{Category: item.Category})
+		}
+		line := item.Item
+		if item.Quantity != "" {
+			line = item.Quantity + " " + line
+		}
+		last := &aisles[len(aisles)-1]
+		last.Items = append(last.Items, line)
+	}
+	return aisles
+}
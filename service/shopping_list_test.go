@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseGroceryList(t *testing.T) {
+	assert.Equal(t, []string{"spaghetti", "2 eggs"}, parseGroceryList(`["spaghetti", " 2 eggs", ""]`))
+	assert.Equal(t, []string{"tomatoes", "pasta", "cheese"}, parseGroceryList("tomatoes, pasta,\ncheese"))
+}
+
+func TestBuildShoppingList(t *testing.T) {
+	recipes := []postgres.Recipes{
+		{Title: "Pancakes", GroceryList: strPtr(`["2 cups flour", "2 eggs", "1 cup milk", "salt"]`)},
+		{Title: "Bread", GroceryList: strPtr("500 g flour, 1 tsp salt, 1 Egg")},
+	}
+
+	// 500 g of flour is added to the 2 cups through flour's density.
+	assert.Equal(t, []ShoppingListItem{
+		{Item: "flour", Quantity: "5.99 cup", Recipes: []string{"Pancakes", "Bread"}},
+		{Item: "eggs", Quantity: "3", Recipes: []string{"Pancakes", "Bread"}},
+		{Item: "milk", Quantity: "1 cup", Recipes: []string{"Pancakes"}},
+		{Item: "salt", Quantity: "1 tsp", Recipes: []string{"Pancakes", "Bread"}},
+	}, buildShoppingList(recipes, nil, "").Items)
+
+	// Without a density, amounts in different dimensions stay separate.
+	list := buildShoppingList([]postgres.Recipes{
+		{Title: "Stew", GroceryList: strPtr("1 cup kale, 200 g kale")},
+	}, nil, "")
+	assert.Equal(t, "1 cup + 200 g", list.Items[0].Quantity)
+
+	list = buildShoppingList(recipes, []postgres.PantryItem{
+		{Item: "Flour", Quantity: floatPtr(1), Unit: "kg"},
+		{Item: "egg", Quantity: floatPtr(2)},
+		{Item: "salt"},
+		{Item: "milk", Quantity: floatPtr(0.1), Unit: "l"},
+	}, "")
+	assert.Equal(t, []ShoppingListItem{
+		{Item: "eggs", Quantity: "1", Recipes: []string{"Pancakes", "Bread"}},
+		{Item: "milk", Quantity: "0.58 cup", Recipes: []string{"Pancakes"}},
+	}, list.Items)
+	assert.Equal(t, []string{"flour", "salt"}, []string{list.InPantry[0].Item, list.InPantry[1].Item})
+}
+
+func TestShoppingListCategorize(t *testing.T) {
+	list := buildShoppingList([]postgres.Recipes{
+		{Title: "Pancakes", GroceryList: strPtr(`["2 cups flour", "2 eggs", "1 lemon", "maple syrup", "1 cup milk"]`)},
+	}, []postgres.PantryItem{{Item: "flour"}}, "")
+	list.categorize(map[string]string{"egg": "dairy", "lemon": "produce", "milk": "dairy", "flour": "pantry"})
+
+	// Items are in store order, and anything uncategorized comes last.
+	assert.Equal(t, []string{"lemon", "eggs", "milk", "maple syrup"},
+		[]string{list.Items[0].Item, list.Items[1].Item, list.Items[2].Item, list.Items[3].Item})
+	assert.Equal(t, "other", list.Items[3].Category)
+	assert.Equal(t, "pantry", list.InPantry[0].Category)
+	assert.Equal(t, []ShoppingAisle{
+		{Category: "produce", Items: []string{"1 lemon"}},
+		{Category: "dairy", Items: []string{"2 eggs", "1 cup milk"}},
+		{Category: "other", Items: []string{"maple syrup"}},
+	}, list.Aisles)
+}
+
+func TestMCPHandlers_BuildShoppingList(t *testing.T) {
+	mockRecipesDAO := &MockRecipesDAO{}
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r1").Return(postgres.Recipes{ID: "r1", Title: "Pancakes", GroceryList: strPtr(`["2 eggs", "1 cup milk"]`)}, nil)
+	mockPantryDAO := mocks.NewMockpantryDAO(t)
+	mockPantryDAO.On("ListPantryItems", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1" && o.WhereArgs[0] == "house-1"
+	})).Return([]postgres.PantryItem{{Item: "eggs", Quantity: floatPtr(6)}, {Item: "milk", Quantity: floatPtr(0), Category: "beverages"}}, nil)
+
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, UnitsPreferenceKey, "house-1").Return(postgres.Preferences{}, assert.AnError)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, prefs, mockRecipesDAO, &MockUserDAO{}, &MockHouseholdDAO{}, WithPantry(mockPantryDAO))
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "build_shopping_list", map[string]any{"recipe_ids": "r1"}), &body)
+	assert.Equal(t, "1 items to buy for 1 recipes, 1 already in the pantry", body["summary"])
+	assert.Equal(t, "milk", body["items"].([]any)[0].(map[string]any)["item"])
+	// The pantry's category for milk beats the rules' dairy.
+	assert.Equal(t, []any{map[string]any{"category": "beverages", "items": []any{"1 cup milk"}}}, body["aisles"])
+	assert.Equal(t, "dairy", body["in_pantry"].([]any)[0].(map[string]any)["category"])
+
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "missing").Return(postgres.Recipes{}, assert.AnError)
+	assert.True(t, h.callTool(t.Context(), "build_shopping_list", map[string]any{"recipe_ids": "r1, missing"}).IsError)
+	assert.True(t, h.callTool(t.Context(), "build_shopping_list", map[string]any{"recipe_ids": " "}).IsError)
+}
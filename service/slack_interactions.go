@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+type slackInteractionDAO interface {
+	GetUserBySlackUserUID(ctx context.Context, slackUserUID string) (dao.Users, error)
+	GetTodo(ctx context.Context, uid string) (dao.Todo, error)
+	UpdateTodo(ctx context.Context, uid string, t dao.UpdateTodo) (dao.Todo, error)
+	SetTodoStatus(ctx context.Context, uid string, status dao.TodoStatus, completedBy *string) (dao.Todo, error)
+}
+
+const (
+	// slackMaxSkew is how old a request's timestamp may be before it is
+	// refused as a possible replay, as Slack recommends.
+	slackMaxSkew = 5 * time.Minute
+	// slackSnooze is how far the Snooze button moves a todo's due date.
+	slackSnooze = time.Hour
+	// maxSlackPayload bounds the interaction payloads read.
+	maxSlackPayload = 1 << 20
+)
+
+// slackInteraction is the part of a Slack block_actions payload the
+// handler uses.
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+type SlackInteractionHandlers struct {
+	dao    slackInteractionDAO
+	secret []byte
+	events *EventHub
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSlackInteractions serves POST /interactions, the Slack app's
+// interactivity request URL. It handles the Done and Snooze 1h buttons on
+// todo reminders sent through notify.Slack, acting as the Slack user who
+// pressed them, and then replaces the reminder with what was done. Requests
+// must be signed with the app's signing secret.
+func NewSlackInteractions(dao slackInteractionDAO, signingSecret string, events *EventHub) http.Handler {
+	h := &SlackInteractionHandlers{
+		dao:    dao,
+		secret: []byte(signingSecret),
+		events: events,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/interactions", h.interact)
+	return r
+}
+
+// verify checks the request's X-Slack-Signature, an HMAC of its timestamp
+// and body, and that the timestamp is recent.
+func (h *SlackInteractionHandlers) verify(r *http.Request, body []byte) bool {
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := h.now().Sub(time.Unix(sent, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	fmt.Fprintf(mac, "v0:%s:", ts)
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(want), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+func (h *SlackInteractionHandlers) interact(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackPayload))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var in slackInteraction
+	if json.Unmarshal([]byte(form.Get("payload")), &in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// Slack only waits three seconds for the acknowledgement; the outcome
+	// goes to response_url instead of in this response.
+	w.WriteHeader(http.StatusOK)
+	if in.Type != "block_actions" || len(in.Actions) == 0 {
+		return
+	}
+	action := in.Actions[0]
+	// Slack's signature vouches for the request, whichever tenant the Slack
+	// user is in; slackCanEdit decides what they may change.
+	reply := h.act(dao.WithAllTenants(r.Context()), in.User.ID, action.ActionID, action.Value)
+	if in.ResponseURL != "" {
+		h.respond(r.Context(), in.ResponseURL, reply)
+	}
+}
+
+// act performs action on the todo uid for the Slack user slackUser and
+// returns the message to reply with.
+func (h *SlackInteractionHandlers) act(ctx context.Context, slackUser, action, uid string) map[string]any {
+	ephemeral := func(text string) map[string]any {
+		return map[string]any{"response_type": "ephemeral", "replace_original": false, "text": text}
+	}
+	if action != notify.SlackActionTodoDone && action != notify.SlackActionTodoSnooze {
+		return ephemeral("That button isn't supported any more.")
+	}
+	user, err := h.dao.GetUserBySlackUserUID(ctx, slackUser)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ephemeral("Your Slack account isn't linked to an assistant user, so the todo wasn't changed.")
+	}
+	if err != nil {
+		slog.Error("Failed to look up Slack user", "slack_user_uid", slackUser, "error", err)
+		return ephemeral("Something went wrong; the todo wasn't changed. Please try again.")
+	}
+	todo, err := h.dao.GetTodo(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !slackCanEdit(user, todo)) {
+		return ephemeral("That todo no longer exists.")
+	}
+	if err != nil {
+		slog.Error("Failed to get todo", "todo_uid", uid, "error", err)
+		return ephemeral("Something went wrong; the todo wasn't changed. Please try again.")
+	}
+
+	var text string
+	switch action {
+	case notify.SlackActionTodoDone:
+		todo, err = h.dao.SetTodoStatus(ctx, uid, dao.TodoDone, &user.UID)
+		text = fmt.Sprintf(":white_check_mark: *%s* was done by %s.", todo.Title, user.Name)
+	case notify.SlackActionTodoSnooze:
+		due := h.now().Add(slackSnooze).Truncate(time.Minute)
+		todo, err = h.dao.UpdateTodo(ctx, uid, dao.UpdateTodo{DueDate: &due})
+		// Slack shows the time in the reader's own time zone.
+		text = fmt.Sprintf(":alarm_clock: *%s* is snoozed until <!date^%d^{time}|%s>.", todo.Title, due.Unix(), due.UTC().Format("15:04 UTC"))
+	}
+	if err != nil {
+		slog.Error("Failed to update todo from Slack", "todo_uid", uid, "action", action, "error", err)
+		return ephemeral("Something went wrong; the todo wasn't changed. Please try again.")
+	}
+	household := ""
+	if todo.HouseholdUID != nil {
+		household = *todo.HouseholdUID
+	} else if user.HouseholdUID != nil {
+		household = *user.HouseholdUID
+	}
+	h.events.Publish(ChangeEvent{Entity: "todos", Action: "updated", ID: todo.UID, HouseholdUID: household})
+	return map[string]any{"replace_original": true, "text": text}
+}
+
+// slackCanEdit reports whether user may change todo: it is theirs or their
+// household's.
+func slackCanEdit(user dao.Users, todo dao.Todo) bool {
+	if todo.UserUID != nil && *todo.UserUID == user.UID {
+		return true
+	}
+	return todo.HouseholdUID != nil && user.HouseholdUID != nil && *todo.HouseholdUID == *user.HouseholdUID
+}
+
+// respond posts msg to an interaction's response_url. Failures are only
+// logged, as the todo has already been changed.
+func (h *SlackInteractionHandlers) respond(ctx context.Context, responseURL string, msg map[string]any) {
+	body, _ := json.Marshal(msg)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("Invalid Slack response_url", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		slog.Warn("Failed to reply to Slack interaction", "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		slog.Warn("Failed to reply to Slack interaction", "status", resp.Status)
+	}
+}
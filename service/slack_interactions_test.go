@@ -0,0 +1,149 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSlackSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// slackRequest is an interaction request for pressing action on the
+// reminder for todoUID as the Slack user slackUser, signed at sentAt.
+func slackRequest(t *testing.T, sentAt time.Time, slackUser, action, todoUID, responseURL string) *http.Request {
+	payload, err := json.Marshal(map[string]any{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": slackUser},
+		"actions":      []map[string]string{{"action_id": action, "value": todoUID}},
+		"response_url": responseURL,
+	})
+	require.NoError(t, err)
+	body := url.Values{"payload": {string(payload)}}.Encode()
+	ts := strconv.FormatInt(sentAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSlackSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	req := httptest.NewRequest("POST", "/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// slackReplies collects what is posted to the interactions' response_url.
+func slackReplies(t *testing.T) (*httptest.Server, chan map[string]any) {
+	replies := make(chan map[string]any, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		replies <- msg
+	}))
+	t.Cleanup(srv.Close)
+	return srv, replies
+}
+
+func TestSlackInteractionsDone(t *testing.T) {
+	srv, replies := slackReplies(t)
+	sam := postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}
+	todo := postgres.Todo{UID: "todo-1", Title: "Take the bins out", HouseholdUID: strPtr("house-1")}
+	d := mocks.NewMockslackInteractionDAO(t)
+	d.On("GetUserBySlackUserUID", mock.Anything, "U123").Return(sam, nil)
+	d.On("GetTodo", mock.Anything, "todo-1").Return(todo, nil)
+	d.On("SetTodoStatus", mock.Anything, "todo-1", postgres.TodoDone, strPtr("user-1")).Return(todo, nil)
+	events := NewEventHub()
+	changes, stop := events.Subscribe("house-1")
+	defer stop()
+
+	rr := httptest.NewRecorder()
+	NewSlackInteractions(d, testSlackSecret, events).ServeHTTP(rr, slackRequest(t, time.Now(), "U123", notify.SlackActionTodoDone, "todo-1", srv.URL))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	reply := <-replies
+	assert.Equal(t, true, reply["replace_original"])
+	assert.Equal(t, ":white_check_mark: *Take the bins out* was done by Sam.", reply["text"])
+	change := <-changes
+	assert.Equal(t, "todos", change.Entity)
+	assert.Equal(t, "todo-1", change.ID)
+}
+
+func TestSlackInteractionsSnooze(t *testing.T) {
+	srv, replies := slackReplies(t)
+	todo := postgres.Todo{UID: "todo-1", Title: "Call the dentist", UserUID: strPtr("user-1")}
+	d := mocks.NewMockslackInteractionDAO(t)
+	d.On("GetUserBySlackUserUID", mock.Anything, "U123").Return(postgres.Users{UID: "user-1", Name: "Sam"}, nil)
+	d.On("GetTodo", mock.Anything, "todo-1").Return(todo, nil)
+	start := time.Now()
+	d.On("UpdateTodo", mock.Anything, "todo-1", mock.MatchedBy(func(u postgres.UpdateTodo) bool {
+		return u.DueDate != nil && u.DueDate.Sub(start) > 58*time.Minute && u.DueDate.Sub(start) <= time.Hour
+	})).Return(todo, nil)
+
+	rr := httptest.NewRecorder()
+	NewSlackInteractions(d, testSlackSecret, nil).ServeHTTP(rr, slackRequest(t, time.Now(), "U123", notify.SlackActionTodoSnooze, "todo-1", srv.URL))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, (<-replies)["text"], ":alarm_clock: *Call the dentist* is snoozed until <!date^")
+}
+
+func TestSlackInteractionsRefused(t *testing.T) {
+	srv, replies := slackReplies(t)
+	d := mocks.NewMockslackInteractionDAO(t)
+	d.On("GetUserBySlackUserUID", mock.Anything, "U123").Return(postgres.Users{UID: "user-1", HouseholdUID: strPtr("house-1")}, nil)
+	d.On("GetUserBySlackUserUID", mock.Anything, "UNKNOWN").Return(postgres.Users{}, pgx.ErrNoRows)
+	d.On("GetTodo", mock.Anything, "theirs").Return(postgres.Todo{UID: "theirs", HouseholdUID: strPtr("house-2")}, nil)
+	handler := NewSlackInteractions(d, testSlackSecret, nil)
+
+	for name, c := range map[string]struct {
+		slackUser, todo, want string
+	}{
+		"unlinked user":         {"UNKNOWN", "todo-1", "Your Slack account isn't linked"},
+		"other household's":     {"U123", "theirs", "That todo no longer exists."},
+		"unknown action button": {"U123", "todo-1", "That button isn't supported any more."},
+	} {
+		t.Run(name, func(t *testing.T) {
+			action := notify.SlackActionTodoDone
+			if name == "unknown action button" {
+				action = "todo_delete"
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, slackRequest(t, time.Now(), c.slackUser, action, c.todo, srv.URL))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			reply := <-replies
+			assert.Equal(t, "ephemeral", reply["response_type"])
+			assert.Contains(t, reply["text"], c.want)
+		})
+	}
+}
+
+func TestSlackInteractionsVerifySignature(t *testing.T) {
+	d := mocks.NewMockslackInteractionDAO(t)
+	handler := NewSlackInteractions(d, testSlackSecret, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, slackRequest(t, time.Now().Add(-10*time.Minute), "U123", notify.SlackActionTodoDone, "todo-1", ""))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req := slackRequest(t, time.Now(), "U123", notify.SlackActionTodoDone, "todo-1", "")
+	req.Header.Set("X-Slack-Signature", "v0=00")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	NewSlackInteractions(d, "another secret", nil).ServeHTTP(rr, slackRequest(t, time.Now(), "U123", notify.SlackActionTodoDone, "todo-1", ""))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
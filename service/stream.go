@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ndjsonType is the media type of newline-delimited JSON, one value per
+// line.
+const ndjsonType = "application/x-ndjson"
+
+// streamListMin is the length from which a JSON list is written an entry at
+// a time rather than marshalled whole. It is above the default page size,
+// so pages of that size keep their ETag.
+const streamListMin = 200
+
+// writeStreamed writes v, when it is a list, one entry at a time instead of
+// marshalling the whole body first: as NDJSON when the request accepts it,
+// or as a JSON array once it has streamListMin entries. The list itself is
+// already in memory; what this saves is a second, encoded copy of it, and
+// the client starts receiving entries sooner. Streamed lists carry no ETag,
+// as it would have to be sent before the body it hashes. It reports whether
+// it wrote v.
+func writeStreamed(w http.ResponseWriter, r *http.Request, v any) bool {
+	list := reflect.ValueOf(v)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	w.Header().Add("Vary", "Accept")
+	if acceptsNDJSON(r) {
+		w.Header().Set("Content-Type", ndjsonType)
+		enc := json.NewEncoder(w)
+		for i := range list.Len() {
+			if enc.Encode(list.Index(i).Interface()) != nil {
+				return true
+			}
+		}
+		return true
+	}
+	if list.Len() < streamListMin {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	sep := []byte{'['}
+	for i := range list.Len() {
+		entry, err := json.Marshal(list.Index(i).Interface())
+		if err != nil {
+			// The status is already sent; a truncated body is the only
+			// way left to signal the failure.
+			return true
+		}
+		if _, err := w.Write(append(sep, entry...)); err != nil {
+			return true
+		}
+		sep = []byte{','}
+	}
+	_, _ = w.Write([]byte("]\n"))
+	return true
+}
+
+// acceptsNDJSON reports whether the request's Accept header names
+// ndjsonType.
+func acceptsNDJSON(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == ndjsonType {
+			return true
+		}
+	}
+	return false
+}
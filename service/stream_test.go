@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListsAreStreamed(t *testing.T) {
+	todos := make([]postgres.Todo, streamListMin)
+	for i := range todos {
+		todos[i] = postgres.Todo{UID: fmt.Sprintf("t%d", i), Title: "Water <the> plants"}
+	}
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return(todos, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	// A long list is written an entry at a time, to the same bytes
+	// marshalling it whole would give.
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?limit=500", nil))
+	want, err := json.Marshal(todos)
+	require.NoError(t, err)
+	assert.Equal(t, string(want)+"\n", rr.Body.String())
+	assert.Empty(t, rr.Header().Get("ETag"))
+
+	req := httptest.NewRequest("GET", "/?format=compact", nil)
+	req.Header.Set("Accept", "application/json;q=0.5, application/x-ndjson")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, ndjsonType, rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Values("Vary"), "Accept")
+	lines := strings.Split(strings.TrimSuffix(rr.Body.String(), "\n"), "\n")
+	require.Len(t, lines, streamListMin)
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "t0", first["uid"])
+	assert.NotContains(t, first, "description")
+}
+
+func TestShortListsKeepTheirETag(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), `[{"uid":"t1"`))
+}
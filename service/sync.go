@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// Synced entities, named for their REST collections.
+const (
+	syncTodos = "todos"
+	syncNotes = "notes"
+)
+
+const (
+	defaultSyncLimit = 100
+	maxSyncLimit     = 500
+	// maxSyncPush caps the changes one push sends.
+	maxSyncPush = 500
+)
+
+type syncDAO interface {
+	ListSyncChanges(ctx context.Context, householdUID, userUID string, since int64, limit int) ([]dao.SyncState, error)
+	GetSyncState(ctx context.Context, entity, entityID string) (dao.SyncState, error)
+	SetSyncState(ctx context.Context, s dao.SyncState) (dao.SyncState, error)
+}
+
+// SyncChange is a todo or note as it stands after a change: its version,
+// whether it has been deleted and, unless it has, its data as its REST
+// collection returns it. UpdatedAt is when the change was made.
+type SyncChange struct {
+	Entity    string          `json:"entity"`
+	ID        string          `json:"id"`
+	Version   dao.SyncVersion `json:"version"`
+	Deleted   bool            `json:"deleted,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// SyncPullResponse is a page of the changes after a cursor. Pass Cursor as
+// ?since= for the next page, or to pull again later.
+type SyncPullResponse struct {
+	Changes []SyncChange `json:"changes"`
+	Cursor  string       `json:"cursor"`
+	HasMore bool         `json:"has_more"`
+}
+
+// SyncPushRequest carries a client's offline changes. Each counts under
+// ClientID in its version, so a client increments its own entry for every
+// local change.
+type SyncPushRequest struct {
+	ClientID string       `json:"client_id"`
+	Changes  []SyncChange `json:"changes"`
+}
+
+// SyncResult is the server's copy of an entity after a pushed change, for
+// the client to keep. Applied is false when the server already had the
+// change, or had a concurrent one made later: Conflict reports changes the
+// client hadn't seen. LocalID is the ID a client gave an entity it
+// created, which the server replaces.
+type SyncResult struct {
+	SyncChange
+	LocalID  string `json:"local_id,omitempty"`
+	Applied  bool   `json:"applied"`
+	Conflict bool   `json:"conflict,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type SyncPushResponse struct {
+	Results []SyncResult `json:"results"`
+}
+
+type SyncHandlers struct {
+	dao   syncDAO
+	todos todoDAO
+	notes notesDAO
+	now   func() time.Time
+}
+
+// NewSync serves the caller's todos and notes to offline clients. GET
+// /?since= pulls what changed after a cursor, deletions as tombstones, and
+// POST / pushes the client's changes, the later of two concurrent changes
+// winning.
+func NewSync(sync syncDAO, todos todoDAO, notes notesDAO) http.Handler {
+	h := &SyncHandlers{dao: sync, todos: todos, notes: notes, now: time.Now}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Use(requireIdentity)
+	r.Get("/", h.pull)
+	r.Post("/", h.push)
+	return r
+}
+
+// syncOwned reports whether an entity owned by householdUID and userUID is
+// the caller's to sync.
+func syncOwned(id Identity, householdUID, userUID *string) bool {
+	if householdUID != nil && *householdUID != "" {
+		return *householdUID == id.HouseholdUID
+	}
+	return userUID != nil && *userUID == id.UserUID
+}
+
+func (h *SyncHandlers) pull(w http.ResponseWriter, r *http.Request) {
+	id, _ := IdentityFromContext(r.Context())
+	query := r.URL.Query()
+	var since int64
+	if v := query.Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeSyncError(w, "since must be a cursor from an earlier pull")
+			return
+		}
+		since = n
+	}
+	limit := defaultSyncLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSyncLimit {
+			writeSyncError(w, "limit must be 1 to "+strconv.Itoa(maxSyncLimit))
+			return
+		}
+		limit = n
+	}
+
+	states, err := h.dao.ListSyncChanges(r.Context(), id.HouseholdUID, id.UserUID, since, limit+1)
+	if err != nil {
+		slog.Error("failed to list sync changes", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	out := SyncPullResponse{Changes: []SyncChange{}, Cursor: strconv.FormatInt(since, 10)}
+	if len(states) > limit {
+		states, out.HasMore = states[:limit], true
+	}
+	current, err := h.current(r.Context(), states)
+	if err != nil {
+		slog.Error("failed to read synced entities", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, s := range states {
+		c := SyncChange{Entity: s.Entity, ID: s.EntityID, Version: s.Version, Deleted: s.Deleted, UpdatedAt: s.UpdatedAt}
+		if !s.Deleted {
+			// An entity gone since, or a note made private by a housemate,
+			// is gone from the client too.
+			c.Data = current[s.Entity+"/"+s.EntityID]
+			c.Deleted = c.Data == nil
+		}
+		out.Changes = append(out.Changes, c)
+		out.Cursor = strconv.FormatInt(s.Seq, 10)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// current reads the todos and notes in states that haven't been deleted,
+// keyed by entity and ID. Notes the caller may not read are left out.
+func (h *SyncHandlers) current(ctx context.Context, states []dao.SyncState) (map[string]json.RawMessage, error) {
+	ids := map[string][]string{}
+	for _, s := range states {
+		if !s.Deleted {
+			ids[s.Entity] = append(ids[s.Entity], s.EntityID)
+		}
+	}
+	out := map[string]json.RawMessage{}
+	if len(ids[syncTodos]) > 0 {
+		todos, err := h.todos.ListTodos(ctx, dao.ListOptions{
+			WhereClause: "WHERE uid = ANY($1::uuid[])",
+			WhereArgs:   []any{ids[syncTodos]},
+			Limit:       len(ids[syncTodos]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range todos {
+			out[syncTodos+"/"+t.UID], _ = json.Marshal(t)
+		}
+	}
+	if len(ids[syncNotes]) > 0 {
+		where, args := withNoteVisibility(ctx, "WHERE id = ANY($1::uuid[])", []any{ids[syncNotes]})
+		notes, err := h.notes.ListNotes(ctx, dao.ListOptions{WhereClause: where, WhereArgs: args, Limit: len(ids[syncNotes])})
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			out[syncNotes+"/"+n.ID], _ = json.Marshal(n)
+		}
+	}
+	return out, nil
+}
+
+func (h *SyncHandlers) push(w http.ResponseWriter, r *http.Request) {
+	id, _ := IdentityFromContext(r.Context())
+	var req SyncPushRequest
+	if json.NewDecoder(r.Body).Decode(&req) != nil {
+		writeSyncError(w, "invalid JSON")
+		return
+	}
+	if req.ClientID == "" || req.ClientID == dao.SyncServer {
+		writeSyncError(w, "client_id is required and can't be "+dao.SyncServer)
+		return
+	}
+	if len(req.Changes) > maxSyncPush {
+		writeSyncError(w, "a push takes at most "+strconv.Itoa(maxSyncPush)+" changes")
+		return
+	}
+	out := SyncPushResponse{Results: make([]SyncResult, len(req.Changes))}
+	for i, c := range req.Changes {
+		res, err := h.apply(r.Context(), id, req.ClientID, c)
+		if err != nil {
+			res = SyncResult{SyncChange: SyncChange{Entity: c.Entity, ID: c.ID}, Error: err.Error()}
+		}
+		out.Results[i] = res
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// errSyncNotFound is a change to an entity the caller can't see, which may
+// not exist at all.
+var errSyncNotFound = errors.New("not found")
+
+// apply saves one pushed change unless the server's copy supersedes it:
+// when the server has seen everything the change has, or when they are
+// concurrent and the server's was made later. Either way the versions are
+// merged, so the client's next change to the entity follows on from both.
+func (h *SyncHandlers) apply(ctx context.Context, id Identity, clientID string, c SyncChange) (SyncResult, error) {
+	if c.Entity != syncTodos && c.Entity != syncNotes {
+		return SyncResult{}, errors.New("entity must be todos or notes")
+	}
+	if !c.Deleted && len(c.Data) == 0 {
+		return SyncResult{}, errors.New("data is required unless the change is a deletion")
+	}
+	if c.Version[clientID] == 0 {
+		return SyncResult{}, errors.New("version must count the change under client_id")
+	}
+	// A client clock running fast doesn't get to win every conflict.
+	now := h.now().UTC()
+	if c.UpdatedAt.IsZero() || c.UpdatedAt.After(now) {
+		c.UpdatedAt = now
+	}
+
+	state := dao.SyncState{Entity: c.Entity, EntityID: c.ID, Version: dao.SyncVersion{}, Deleted: true}
+	if uuid.Validate(c.ID) == nil {
+		saved, err := h.dao.GetSyncState(ctx, c.Entity, c.ID)
+		switch {
+		case err == nil:
+			if !syncOwned(id, saved.HouseholdUID, saved.UserUID) {
+				return SyncResult{}, errSyncNotFound
+			}
+			state = saved
+		case !errors.Is(err, pgx.ErrNoRows):
+			slog.Error("failed to read sync state", "error", err)
+			return SyncResult{}, errors.New("failed to read sync state")
+		}
+	}
+	if state.Seq == 0 && c.Deleted {
+		// Created and deleted offline: the server never had it.
+		return SyncResult{SyncChange: c, Applied: true}, nil
+	}
+
+	res := SyncResult{}
+	switch {
+	case state.Version.Covers(c.Version):
+		return h.result(ctx, state, res)
+	case !c.Version.Covers(state.Version):
+		res.Conflict = true
+		if !c.UpdatedAt.After(state.UpdatedAt) {
+			state.Version = state.Version.Merge(c.Version)
+			saved, err := h.dao.SetSyncState(ctx, state)
+			if err != nil {
+				slog.Error("failed to save sync state", "error", err)
+				return SyncResult{}, errors.New("failed to save sync state")
+			}
+			return h.result(ctx, saved, res)
+		}
+	}
+
+	next := dao.SyncState{Entity: c.Entity, EntityID: c.ID, Version: state.Version.Merge(c.Version), Deleted: c.Deleted, UpdatedAt: c.UpdatedAt}
+	var err error
+	switch c.Entity {
+	case syncTodos:
+		err = h.writeTodo(ctx, id, state, c, &next)
+	case syncNotes:
+		err = h.writeNote(ctx, id, state, c, &next)
+	}
+	if err != nil {
+		return SyncResult{}, err
+	}
+	if next.EntityID != c.ID {
+		res.LocalID = c.ID
+	}
+	saved, err := h.dao.SetSyncState(ctx, next)
+	if err != nil {
+		slog.Error("failed to save sync state", "error", err)
+		return SyncResult{}, errors.New("failed to save sync state")
+	}
+	res.Applied = true
+	return h.result(ctx, saved, res)
+}
+
+// result fills res in with the server's copy of the entity in state.
+func (h *SyncHandlers) result(ctx context.Context, state dao.SyncState, res SyncResult) (SyncResult, error) {
+	res.SyncChange = SyncChange{Entity: state.Entity, ID: state.EntityID, Version: state.Version, Deleted: state.Deleted, UpdatedAt: state.UpdatedAt}
+	if !state.Deleted {
+		current, err := h.current(ctx, []dao.SyncState{state})
+		if err != nil {
+			slog.Error("failed to read synced entity", "error", err)
+			return res, errors.New("failed to read " + state.Entity)
+		}
+		res.Data = current[state.Entity+"/"+state.EntityID]
+	}
+	return res, nil
+}
+
+// writeTodo makes c's change to a todo, setting next's owners, and its ID
+// when the todo is new.
+func (h *SyncHandlers) writeTodo(ctx context.Context, id Identity, state dao.SyncState, c SyncChange, next *dao.SyncState) error {
+	if c.Deleted {
+		next.HouseholdUID, next.UserUID = state.HouseholdUID, state.UserUID
+		if state.Deleted {
+			return nil
+		}
+		return syncWriteErr(h.todos.DeleteTodo(ctx, c.ID))
+	}
+	var t dao.Todo
+	if err := json.Unmarshal(c.Data, &t); err != nil {
+		return errors.New("invalid todo: " + err.Error())
+	}
+	if t.HouseholdUID == nil && t.UserUID == nil {
+		t.UserUID = &id.UserUID
+		if id.HouseholdUID != "" {
+			t.HouseholdUID = &id.HouseholdUID
+		}
+	}
+	if !syncOwned(id, t.HouseholdUID, t.UserUID) {
+		return errors.New("a todo can only be synced to your household or yourself")
+	}
+
+	if state.Deleted {
+		// New, or deleted on the server before a later edit.
+		if t.Priority == 0 {
+			t.Priority = dao.PriorityMedium
+		}
+		if t.Data == "" {
+			t.Data = "{}"
+		}
+		created, err := h.todos.CreateTodo(ctx, t)
+		if err != nil {
+			return syncWriteErr(err)
+		}
+		next.EntityID, next.HouseholdUID, next.UserUID = created.UID, created.HouseholdUID, created.UserUID
+		return nil
+	}
+	var update dao.UpdateTodo
+	if err := json.Unmarshal(c.Data, &update); err != nil {
+		return errors.New("invalid todo: " + err.Error())
+	}
+	updated, err := h.todos.UpdateTodo(ctx, c.ID, update)
+	if err == nil && t.Status != "" && t.Status != updated.Status {
+		updated, err = h.todos.SetTodoStatus(ctx, c.ID, t.Status, t.CompletedBy)
+	}
+	if err != nil {
+		return syncWriteErr(err)
+	}
+	next.HouseholdUID, next.UserUID = updated.HouseholdUID, updated.UserUID
+	return nil
+}
+
+// writeNote makes c's change to a note, as writeTodo does to a todo. Only
+// notes the caller may read can be changed.
+func (h *SyncHandlers) writeNote(ctx context.Context, id Identity, state dao.SyncState, c SyncChange, next *dao.SyncState) error {
+	if !state.Deleted {
+		current, err := h.notes.GetNotes(ctx, c.ID)
+		if err != nil || !noteVisibleTo(current, id) {
+			return errSyncNotFound
+		}
+	}
+	if c.Deleted {
+		next.HouseholdUID, next.UserUID = state.HouseholdUID, state.UserUID
+		if state.Deleted {
+			return nil
+		}
+		return syncWriteErr(h.notes.DeleteNotes(ctx, c.ID))
+	}
+	var n dao.Notes
+	if err := json.Unmarshal(c.Data, &n); err != nil {
+		return errors.New("invalid note: " + err.Error())
+	}
+	if n.Visibility != "" && !validNoteVisibility(n.Visibility) {
+		return errors.New("invalid note visibility " + n.Visibility)
+	}
+	if n.HouseholdUID == nil && n.UserUID == nil {
+		n.UserUID = &id.UserUID
+		if id.HouseholdUID != "" {
+			n.HouseholdUID = &id.HouseholdUID
+		}
+	}
+	if !syncOwned(id, n.HouseholdUID, n.UserUID) {
+		return errors.New("a note can only be synced to your household or yourself")
+	}
+
+	var saved dao.Notes
+	var err error
+	if state.Deleted {
+		saved, err = h.notes.CreateNotes(ctx, n)
+	} else {
+		saved, err = h.notes.UpdateNotes(ctx, c.ID, n)
+	}
+	if err != nil {
+		return syncWriteErr(err)
+	}
+	next.EntityID, next.HouseholdUID, next.UserUID = saved.ID, saved.HouseholdUID, saved.UserUID
+	return nil
+}
+
+// syncWriteErr is what a client is told of a failed write: validation
+// errors as they are, anything else logged and summarized.
+func syncWriteErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, dao.ErrInvalidPriority), errors.Is(err, dao.ErrInvalidStatus), errors.Is(err, dao.ErrInvalidEstimate),
+		errors.Is(err, dao.ErrUnknownProject), errors.Is(err, dao.ErrUnknownCompleter):
+		return err
+	case errors.Is(err, dao.ErrQuotaExceeded):
+		return errors.New(quotaMessage(err))
+	case errors.Is(err, pgx.ErrNoRows):
+		return errSyncNotFound
+	}
+	slog.Error("failed to apply sync change", "error", err)
+	return errors.New("failed to save the change")
+}
+
+func writeSyncError(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
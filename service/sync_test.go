@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	syncTodoUID = "6f1c1a52-8d1e-4c57-9a0b-0d3a0c1b2a01"
+	syncNoteID  = "6f1c1a52-8d1e-4c57-9a0b-0d3a0c1b2a02"
+	syncGoneUID = "6f1c1a52-8d1e-4c57-9a0b-0d3a0c1b2a03"
+)
+
+func serveSync(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1", HouseholdUID: "house-1"}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSyncPull(t *testing.T) {
+	at := time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC)
+	syncs := mocks.NewMocksyncDAO(t)
+	syncs.On("ListSyncChanges", mock.Anything, "house-1", "user-1", int64(40), 3).Return([]dao.SyncState{
+		{Entity: syncTodos, EntityID: syncTodoUID, Seq: 41, Version: dao.SyncVersion{"server": 2}, UpdatedAt: at},
+		{Entity: syncNotes, EntityID: syncNoteID, Seq: 42, Version: dao.SyncVersion{"server": 1}, UpdatedAt: at},
+		{Entity: syncTodos, EntityID: syncGoneUID, Seq: 43, Version: dao.SyncVersion{"server": 3}, Deleted: true, UpdatedAt: at},
+	}, nil)
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{[]string{syncTodoUID}}, o.WhereArgs)
+	})).Return([]dao.Todo{{UID: syncTodoUID, Title: "Bins out"}}, nil)
+	// The note was made private by a housemate, so it isn't listed.
+	notes := &MockNotesDAO{}
+	notes.On("ListNotes", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return strings.Contains(o.WhereClause, "visibility")
+	})).Return([]dao.Notes{}, nil)
+	handler := NewSync(syncs, todos, notes)
+
+	rr := serveSync(handler, http.MethodGet, "/?since=40&limit=2", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var out SyncPullResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.True(t, out.HasMore)
+	assert.Equal(t, "42", out.Cursor)
+	require.Len(t, out.Changes, 2)
+	assert.Equal(t, syncTodoUID, out.Changes[0].ID)
+	assert.Equal(t, dao.SyncVersion{"server": 2}, out.Changes[0].Version)
+	assert.Contains(t, string(out.Changes[0].Data), `"title":"Bins out"`)
+	assert.True(t, out.Changes[1].Deleted)
+	assert.Nil(t, out.Changes[1].Data)
+
+	for _, target := range []string{"/?since=next", "/?since=-1", "/?limit=0", "/?limit=501"} {
+		assert.Equal(t, http.StatusBadRequest, serveSync(handler, http.MethodGet, target, "").Code, target)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestSyncPush(t *testing.T) {
+	serverAt := time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC)
+	house := "house-1"
+	state := dao.SyncState{Entity: syncTodos, EntityID: syncTodoUID, HouseholdUID: &house, Seq: 7,
+		Version: dao.SyncVersion{"server": 2, "phone": 1}, UpdatedAt: serverAt}
+	syncs := mocks.NewMocksyncDAO(t)
+	syncs.On("GetSyncState", mock.Anything, syncTodos, syncTodoUID).Return(state, nil)
+	syncs.On("GetSyncState", mock.Anything, syncTodos, syncGoneUID).Return(dao.SyncState{}, pgx.ErrNoRows)
+	other := "house-2"
+	syncs.On("GetSyncState", mock.Anything, syncNotes, syncNoteID).
+		Return(dao.SyncState{Entity: syncNotes, EntityID: syncNoteID, HouseholdUID: &other, Seq: 9, Version: dao.SyncVersion{"server": 1}}, nil)
+	syncs.On("SetSyncState", mock.Anything, mock.Anything).Return(func(_ context.Context, s dao.SyncState) (dao.SyncState, error) {
+		s.Seq = 50
+		return s, nil
+	})
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.Anything).Return([]dao.Todo{{UID: syncTodoUID, Title: "Server copy"}}, nil)
+	todos.On("UpdateTodo", mock.Anything, syncTodoUID, mock.MatchedBy(func(u dao.UpdateTodo) bool {
+		return *u.Title == "Bins out tonight"
+	})).Return(dao.Todo{UID: syncTodoUID, HouseholdUID: &house, Status: dao.TodoPlanned}, nil)
+	todos.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return td.Title == "Buy milk" && *td.UserUID == "user-1" && *td.HouseholdUID == "house-1" && td.Priority == dao.PriorityMedium
+	})).Return(dao.Todo{UID: syncGoneUID, HouseholdUID: &house}, nil)
+	handler := NewSync(syncs, todos, &MockNotesDAO{})
+	push := func(changes string) SyncPushResponse {
+		t.Helper()
+		rr := serveSync(handler, http.MethodPost, "/", `{"client_id": "phone", "changes": [`+changes+`]}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var out SyncPushResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		require.Len(t, out.Results, 1)
+		return out
+	}
+	change := func(version, updatedAt string) string {
+		return `{"entity": "todos", "id": "` + syncTodoUID + `", "version": ` + version + `, "updated_at": "` + updatedAt +
+			`", "data": {"title": "Bins out tonight"}}`
+	}
+
+	// The client had seen everything, so its change is saved.
+	res := push(change(`{"server": 2, "phone": 2}`, "2025-09-20T10:00:00Z")).Results[0]
+	assert.True(t, res.Applied)
+	assert.False(t, res.Conflict)
+	assert.Equal(t, dao.SyncVersion{"server": 2, "phone": 2}, res.Version)
+	assert.Contains(t, string(res.Data), "Server copy")
+	todos.AssertNumberOfCalls(t, "UpdateTodo", 1)
+
+	// A change the server already has is ignored.
+	res = push(change(`{"server": 1, "phone": 1}`, "2025-09-20T10:00:00Z")).Results[0]
+	assert.False(t, res.Applied)
+	assert.Equal(t, dao.SyncVersion{"server": 2, "phone": 1}, res.Version)
+	todos.AssertNumberOfCalls(t, "UpdateTodo", 1)
+
+	// Concurrent changes: the earlier one loses, but the versions merge.
+	res = push(change(`{"server": 1, "phone": 2}`, "2025-09-20T08:00:00Z")).Results[0]
+	assert.False(t, res.Applied)
+	assert.True(t, res.Conflict)
+	assert.Equal(t, dao.SyncVersion{"server": 2, "phone": 2}, res.Version)
+	assert.Equal(t, serverAt, res.UpdatedAt)
+	todos.AssertNumberOfCalls(t, "UpdateTodo", 1)
+
+	res = push(change(`{"server": 1, "phone": 2}`, "2025-09-20T10:00:00Z")).Results[0]
+	assert.True(t, res.Applied)
+	assert.True(t, res.Conflict)
+	todos.AssertNumberOfCalls(t, "UpdateTodo", 2)
+
+	// A todo made offline gets the server's ID.
+	res = push(`{"entity": "todos", "id": "local-1", "version": {"phone": 1}, "data": {"title": "Buy milk"}}`).Results[0]
+	assert.True(t, res.Applied)
+	assert.Equal(t, syncGoneUID, res.ID)
+	assert.Equal(t, "local-1", res.LocalID)
+
+	// Made and deleted offline, it never reaches the server.
+	res = push(`{"entity": "todos", "id": "` + syncGoneUID + `", "version": {"phone": 2}, "deleted": true}`).Results[0]
+	assert.True(t, res.Applied)
+	assert.True(t, res.Deleted)
+
+	for changes, want := range map[string]string{
+		`{"entity": "recipes", "id": "r1", "version": {"phone": 1}, "data": {}}`:                    "entity must be todos or notes",
+		`{"entity": "todos", "id": "` + syncTodoUID + `", "version": {"phone": 3}}`:                 "data is required",
+		`{"entity": "todos", "id": "` + syncTodoUID + `", "version": {"server": 2}, "data": {}}`:    "version must count the change under client_id",
+		`{"entity": "notes", "id": "` + syncNoteID + `", "version": {"phone": 1}, "deleted": true}`: "not found",
+	} {
+		assert.Contains(t, push(changes).Results[0].Error, want, changes)
+	}
+	todos.AssertNotCalled(t, "DeleteTodo", mock.Anything, mock.Anything)
+
+	for body, want := range map[string]string{
+		`{"changes": []}`:                        "client_id is required",
+		`{"client_id": "server", "changes": []}`: "client_id is required",
+		`[`:                                      "invalid JSON",
+	} {
+		rr := serveSync(handler, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+		assert.Contains(t, rr.Body.String(), want, body)
+	}
+}
+
+func TestSyncVersion(t *testing.T) {
+	a := dao.SyncVersion{"server": 2, "phone": 1}
+	b := dao.SyncVersion{"server": 1, "phone": 3, "tablet": 1}
+	assert.False(t, a.Covers(b))
+	assert.False(t, b.Covers(a))
+	merged := a.Merge(b)
+	assert.Equal(t, dao.SyncVersion{"server": 2, "phone": 3, "tablet": 1}, merged)
+	assert.True(t, merged.Covers(a))
+	assert.True(t, merged.Covers(b))
+	assert.True(t, a.Covers(dao.SyncVersion{}))
+}
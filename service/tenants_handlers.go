@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type tenantDAO interface {
+	CreateTenant(ctx context.Context, t dao.Tenant) (dao.Tenant, error)
+	GetTenant(ctx context.Context, uid string) (dao.Tenant, error)
+	ListTenants(ctx context.Context) ([]dao.Tenant, error)
+}
+
+type TenantHandlers struct{ dao tenantDAO }
+
+// Tenancy decides whose rows a request's DAO calls may touch before any API
+// key is looked at: every tenant's for operators, and otherwise only the
+// default tenant's. APIKeyAuth then confines requests with a key to the
+// key's tenant. It must come after OperatorAuth. Requests made on an API
+// key's behalf, such as a dashboard socket's, keep the key's tenant.
+func Tenancy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := dao.TenantFromContext(r.Context()); ok || dao.AllTenants(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if IsOperator(r.Context()) {
+			r = r.WithContext(dao.WithAllTenants(r.Context()))
+		} else {
+			r = r.WithContext(dao.WithTenant(r.Context(), dao.DefaultTenantUID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewTenants manages tenants. Callers with an API key only ever see their own
+// tenant and cannot create new ones; that is left to operators. Anyone else
+// is refused.
+func NewTenants(dao tenantDAO) http.Handler {
+	h := &TenantHandlers{dao}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/{uid}", h.get)
+	r.Get("/", h.list)
+	return r
+}
+
+// tenantCaller writes 401 and returns false for callers with neither an API
+// key nor the operator token.
+func tenantCaller(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := IdentityFromContext(r.Context()); !ok && !IsOperator(r.Context()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func (h *TenantHandlers) create(w http.ResponseWriter, r *http.Request) {
+	if !tenantCaller(w, r) {
+		return
+	}
+	if !IsOperator(r.Context()) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	var t dao.Tenant
+	if json.NewDecoder(r.Body).Decode(&t) != nil || t.Name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.CreateTenant(r.Context(), t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *TenantHandlers) get(w http.ResponseWriter, r *http.Request) {
+	if !tenantCaller(w, r) {
+		return
+	}
+	uid := chi.URLParam(r, "uid")
+	if id, ok := IdentityFromContext(r.Context()); ok && !IsOperator(r.Context()) && id.TenantUID != uid {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	out, err := h.dao.GetTenant(r.Context(), uid)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *TenantHandlers) list(w http.ResponseWriter, r *http.Request) {
+	if !tenantCaller(w, r) {
+		return
+	}
+	if id, ok := IdentityFromContext(r.Context()); ok && !IsOperator(r.Context()) {
+		t, err := h.dao.GetTenant(r.Context(), id.TenantUID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]dao.Tenant{t})
+		return
+	}
+	out, err := h.dao.ListTenants(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// tenantRequest is from an API key in tenantUID, or from an operator
+// without one.
+func tenantRequest(method, target, body, tenantUID string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if tenantUID == "" {
+		return asOperator(req)
+	}
+	return req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1", TenantUID: tenantUID}))
+}
+
+func TestTenantsCreate(t *testing.T) {
+	mockTenantDAO := mocks.NewMocktenantDAO(t)
+	mockTenantDAO.On("CreateTenant", mock.Anything, postgres.Tenant{Name: "The Smiths"}).Return(postgres.Tenant{UID: "tenant-1", Name: "The Smiths"}, nil)
+	handler := NewTenants(mockTenantDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("POST", "/", `{"name": "The Smiths"}`, ""))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("POST", "/", `{}`, ""))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// API key holders are confined to their tenant and cannot make new ones.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("POST", "/", `{"name": "Mine"}`, "tenant-1"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Callers with neither a key nor the operator token get nothing.
+	for _, method := range []string{"POST", "GET"} {
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, "/", strings.NewReader(`{"name": "Mine"}`)))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, method)
+	}
+}
+
+func TestTenancy(t *testing.T) {
+	var tenant string
+	var all bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ = postgres.TenantFromContext(r.Context())
+		all = postgres.AllTenants(r.Context())
+	})
+
+	Tenancy(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, postgres.DefaultTenantUID, tenant)
+	assert.False(t, all)
+
+	Tenancy(next).ServeHTTP(httptest.NewRecorder(), asOperator(httptest.NewRequest("GET", "/", nil)))
+	assert.Empty(t, tenant)
+	assert.True(t, all)
+
+	keyed := httptest.NewRequest("GET", "/", nil)
+	Tenancy(next).ServeHTTP(httptest.NewRecorder(), keyed.WithContext(postgres.WithTenant(keyed.Context(), "tenant-1")))
+	assert.Equal(t, "tenant-1", tenant)
+}
+
+func TestTenantsScopedToCaller(t *testing.T) {
+	mockTenantDAO := mocks.NewMocktenantDAO(t)
+	mockTenantDAO.On("GetTenant", mock.Anything, "tenant-1").Return(postgres.Tenant{UID: "tenant-1", Name: "The Smiths"}, nil)
+	mockTenantDAO.On("ListTenants", mock.Anything).Return([]postgres.Tenant{{UID: "tenant-1"}, {UID: "tenant-2"}}, nil)
+	handler := NewTenants(mockTenantDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("GET", "/tenant-2", "", "tenant-1"))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("GET", "/tenant-1", "", "tenant-1"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var out []postgres.Tenant
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("GET", "/", "", "tenant-1"))
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Len(t, out, 1)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, tenantRequest("GET", "/", "", ""))
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Len(t, out, 2)
+}
+
+func TestAPIKeyAuthScopesTenant(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	// The key is looked up in every tenant, as its own isn't known yet.
+	mockDAO.On("GetAPIKeyByHash", mock.MatchedBy(postgres.AllTenants), hashAPIKey("ak_good")).
+		Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", TenantUID: "tenant-1"}, nil)
+	mockDAO.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+
+	var tenant string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ = postgres.TenantFromContext(r.Context())
+		id, _ := IdentityFromContext(r.Context())
+		assert.Equal(t, "tenant-1", id.TenantUID)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer ak_good")
+	Tenancy(APIKeyAuth(mockDAO, true)(next)).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "tenant-1", tenant)
+}
+
+func TestAPIKeyAuthScopesHousehold(t *testing.T) {
+	mockDAO := mocks.NewMockapiKeyDAO(t)
+	mockDAO.On("GetAPIKeyByHash", mock.Anything, hashAPIKey("ak_good")).
+		Return(postgres.APIKeys{UID: "key-1", UserUID: "user-1", HouseholdUID: strPtr("house-1"), TenantUID: "tenant-1"}, nil)
+	mockDAO.On("TouchAPIKey", mock.Anything, "key-1").Return(nil)
+
+	var scope postgres.Scope
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, _ = postgres.ScopeFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer ak_good")
+	APIKeyAuth(mockDAO, true)(next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, postgres.Scope{UserUID: "user-1", HouseholdUID: "house-1"}, scope)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// todoBlockedCondition matches todos with at least one incomplete blocker.
+// The actionable filter is its negation.
+const todoBlockedCondition = "EXISTS (SELECT 1 FROM todo_dependencies d JOIN todos b ON b.uid = d.blocked_by_uid WHERE d.todo_uid = todos.uid AND b.marked_complete IS NULL)"
+
+// TodoDependencies lists the todos a todo waits on and the todos waiting on
+// it.
+type TodoDependencies struct {
+	BlockedBy []string `json:"blocked_by"`
+	Blocks    []string `json:"blocks"`
+}
+
+func splitTodoDependencies(todoUID string, deps []dao.TodoDependency) TodoDependencies {
+	out := TodoDependencies{BlockedBy: []string{}, Blocks: []string{}}
+	for _, dep := range deps {
+		if dep.TodoUID == todoUID {
+			out.BlockedBy = append(out.BlockedBy, dep.BlockedByUID)
+		} else {
+			out.Blocks = append(out.Blocks, dep.TodoUID)
+		}
+	}
+	return out
+}
+
+func (h *todoHandlers) dependencies(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	deps, err := h.dao.ListTodoDependencies(r.Context(), uid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(splitTodoDependencies(uid, deps))
+}
+
+func (h *todoHandlers) addBlocker(w http.ResponseWriter, r *http.Request) {
+	uid, blocker := chi.URLParam(r, "uid"), chi.URLParam(r, "blocker")
+	for _, u := range []string{uid, blocker} {
+		if _, err := h.dao.GetTodo(r.Context(), u); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+	err := h.dao.AddTodoDependency(r.Context(), uid, blocker)
+	if errors.Is(err, dao.ErrDependencyCycle) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *todoHandlers) removeBlocker(w http.ResponseWriter, r *http.Request) {
+	if h.dao.RemoveTodoDependency(r.Context(), chi.URLParam(r, "uid"), chi.URLParam(r, "blocker")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
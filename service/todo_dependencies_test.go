@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTodoDependencyRoutes(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("GetTodo", mock.Anything, "paint").Return(postgres.Todo{UID: "paint"}, nil)
+	mockTodoDAO.On("GetTodo", mock.Anything, "buy").Return(postgres.Todo{UID: "buy"}, nil)
+	mockTodoDAO.On("GetTodo", mock.Anything, "missing").Return(postgres.Todo{}, errors.New("no rows"))
+	mockTodoDAO.On("AddTodoDependency", mock.Anything, "paint", "buy").Return(nil)
+	mockTodoDAO.On("AddTodoDependency", mock.Anything, "buy", "paint").Return(postgres.ErrDependencyCycle)
+	mockTodoDAO.On("RemoveTodoDependency", mock.Anything, "paint", "buy").Return(nil)
+	mockTodoDAO.On("ListTodoDependencies", mock.Anything, "buy").Return([]postgres.TodoDependency{
+		{TodoUID: "paint", BlockedByUID: "buy"},
+		{TodoUID: "buy", BlockedByUID: "drive"},
+	}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve("PUT", "/paint/blocked-by/buy").Code)
+	assert.Equal(t, http.StatusConflict, serve("PUT", "/buy/blocked-by/paint").Code)
+	assert.Equal(t, http.StatusNotFound, serve("PUT", "/paint/blocked-by/missing").Code)
+	assert.Equal(t, http.StatusNoContent, serve("DELETE", "/paint/blocked-by/buy").Code)
+
+	rr := serve("GET", "/buy/dependencies")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var deps TodoDependencies
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &deps))
+	assert.Equal(t, TodoDependencies{BlockedBy: []string{"drive"}, Blocks: []string{"paint"}}, deps)
+}
+
+func TestActionableFilter(t *testing.T) {
+	filters, _ := ParseFilters(map[string]string{"actionable": "true"}, TodoFilters.Filters)
+	where, args := BuildWhereClause(filters, TodoFilters.Filters)
+	assert.Equal(t, "WHERE NOT "+todoBlockedCondition, where)
+	assert.Empty(t, args)
+
+	filters, _ = ParseFilters(map[string]string{"actionable": "false"}, TodoFilters.Filters)
+	where, _ = BuildWhereClause(filters, TodoFilters.Filters)
+	assert.Equal(t, "WHERE "+todoBlockedCondition, where)
+
+	// Only todos have dependencies.
+	where, _ = BuildWhereClause([]Filter{{Column: "actionable", Op: OpEq, Value: true}}, NotesFilters.Filters)
+	assert.Empty(t, where)
+
+	filters, err := BuildFiltersFromMCP(map[string]any{"actionable": true, "pending_only": true}, TodoFilters.Filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []Filter{{Column: "marked_complete", Op: OpNull}, {Column: "actionable", Op: OpEq, Value: true}}, filters)
+}
+
+func TestMCPHandlers_LinkTodos(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("GetTodo", mock.Anything, "paint").Return(postgres.Todo{UID: "paint"}, nil)
+	mockTodoDAO.On("GetTodo", mock.Anything, "buy").Return(postgres.Todo{UID: "buy"}, nil)
+	mockTodoDAO.On("AddTodoDependency", mock.Anything, "paint", "buy").Return(nil)
+	mockTodoDAO.On("AddTodoDependency", mock.Anything, "buy", "paint").Return(postgres.ErrDependencyCycle)
+	mockTodoDAO.On("RemoveTodoDependency", mock.Anything, "paint", "buy").Return(nil)
+	h := &MCPHandlers{todoDAO: mockTodoDAO}
+	ctx := t.Context()
+
+	var body map[string]any
+	decodeToolResult(t, h.handleLinkTodos(ctx, map[string]any{"todo_id": "paint", "blocked_by_id": "buy"}), &body)
+	assert.Equal(t, "Todos linked", body["summary"])
+
+	// blocks_id is the same link seen from the blocker's side.
+	decodeToolResult(t, h.handleLinkTodos(ctx, map[string]any{"todo_id": "buy", "blocks_id": "paint"}), &body)
+	assert.Equal(t, "paint", body["todo_uid"])
+	assert.Equal(t, "buy", body["blocked_by_uid"])
+
+	result := h.handleLinkTodos(ctx, map[string]any{"todo_id": "paint", "blocks_id": "buy"})
+	assert.True(t, result.IsError)
+	decodeToolResult(t, result, &body)
+	assert.Equal(t, "Cannot link todos: dependency would create a cycle", body["error"])
+
+	decodeToolResult(t, h.handleLinkTodos(ctx, map[string]any{"todo_id": "paint", "blocked_by_id": "buy", "unlink": true}), &body)
+	assert.Equal(t, "Todos unlinked", body["summary"])
+
+	assert.True(t, h.handleLinkTodos(ctx, map[string]any{"todo_id": "paint"}).IsError)
+	assert.True(t, h.handleLinkTodos(ctx, map[string]any{"todo_id": "paint", "blocked_by_id": "buy", "blocks_id": "buy"}).IsError)
+	mockTodoDAO.AssertExpectations(t)
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/llm"
+)
+
+type todoBatchDAO interface {
+	CreateTodos(ctx context.Context, todos []dao.Todo) ([]dao.Todo, error)
+}
+
+// todoExtraction turns a note into todos in two steps: the proposals are
+// shown to the user first, and only the ones they confirm are created.
+type todoExtraction struct {
+	extractor llm.TodoExtractor
+	todos     todoBatchDAO
+}
+
+// WithNoteTodoExtraction enables POST /notes/{id}/extract-todos.
+func WithNoteTodoExtraction(extractor llm.TodoExtractor, todos todoBatchDAO) NotesOption {
+	return func(h *NotesHandlers) {
+		h.extraction = &todoExtraction{extractor: extractor, todos: todos}
+	}
+}
+
+// WithTodoExtraction enables the extract_todos tool.
+func WithTodoExtraction(extractor llm.TodoExtractor, todos todoBatchDAO) MCPOption {
+	return func(h *MCPHandlers) {
+		h.extraction = &todoExtraction{extractor: extractor, todos: todos}
+	}
+}
+
+// propose asks the model for the todos in note.
+func (e *todoExtraction) propose(ctx context.Context, note dao.Notes) ([]llm.TodoProposal, error) {
+	return e.extractor.ExtractTodos(ctx, note.Key+"\n"+note.Data, time.Now())
+}
+
+// create saves the confirmed proposals as todos owned like note, each
+// recording the note it came from in its data.
+func (e *todoExtraction) create(ctx context.Context, note dao.Notes, proposals []llm.TodoProposal) ([]dao.Todo, error) {
+	if len(proposals) == 0 {
+		return nil, errors.New("no todos to create")
+	}
+	data, err := json.Marshal(map[string]string{"source_note_id": note.ID})
+	if err != nil {
+		return nil, err
+	}
+	todos := make([]dao.Todo, len(proposals))
+	for i, p := range proposals {
+		if p.Title == "" {
+			return nil, fmt.Errorf("todo %d has no title", i+1)
+		}
+		todos[i] = dao.Todo{
+			UID:          uuid.NewString(),
+			Title:        p.Title,
+			Description:  p.Description,
+			Data:         string(data),
+			Priority:     dao.PriorityMedium,
+			UserUID:      note.UserUID,
+			HouseholdUID: note.HouseholdUID,
+		}
+		if p.DueDate != "" {
+			due, err := time.Parse(time.DateOnly, p.DueDate)
+			if err != nil {
+				return nil, fmt.Errorf("todo %d: due_date must be a date like 2025-08-30", i+1)
+			}
+			todos[i].DueDate = &due
+		}
+	}
+	return e.todos.CreateTodos(ctx, todos)
+}
+
+func todoUIDs(todos []dao.Todo) []string {
+	uids := make([]string, len(todos))
+	for i, t := range todos {
+		uids[i] = t.UID
+	}
+	return uids
+}
+
+// extractTodos proposes todos from a note. With "confirm": true it creates
+// the given todos, normally the proposals as the user edited them, or all
+// fresh proposals when none are given.
+func (h *NotesHandlers) extractTodos(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Confirm bool               `json:"confirm"`
+		Todos   []llm.TodoProposal `json:"todos"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	note, err := h.dao.GetNotes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || !noteAccessible(r.Context(), note) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	proposals := in.Todos
+	if proposals == nil {
+		if proposals, err = h.extraction.propose(r.Context(), note); err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+	}
+	if !in.Confirm {
+		_ = json.NewEncoder(w).Encode(map[string]any{"note_id": note.ID, "todos": proposals})
+		return
+	}
+	created, err := h.extraction.create(r.Context(), note, proposals)
+	if quotaExceeded(w, err) {
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"note_id": note.ID, "created": todoUIDs(created)})
+}
+
+func (h *MCPHandlers) handleExtractTodos(ctx context.Context, arguments map[string]any) mcp.CallToolResult {
+	noteID, _ := arguments["note_id"].(string)
+	if noteID == "" {
+		return toolError("note_id is required")
+	}
+	note, err := h.notesDAO.GetNotes(ctx, noteID)
+	if err != nil || !noteAccessible(ctx, note) {
+		return toolError("Note not found: %s", noteID)
+	}
+
+	// Proposing todos is one step and creating them, once confirmed, another.
+	confirm, _ := arguments["confirm"].(bool)
+	steps := 1.0
+	if confirm {
+		steps = 2
+	}
+	var proposals []llm.TodoProposal
+	if raw, ok := arguments["todos"]; ok {
+		b, err := json.Marshal(raw)
+		if err != nil || json.Unmarshal(b, &proposals) != nil {
+			return toolError("todos must be a list of objects with title, description and due_date")
+		}
+	} else {
+		reportProgress(ctx, 0, steps, "Reading "+note.Key)
+		if proposals, err = h.extraction.propose(ctx, note); err != nil {
+			return toolError("Failed to extract todos: %v", err)
+		}
+	}
+	reportProgress(ctx, 1, steps, fmt.Sprintf("Found %d todos", len(proposals)))
+
+	if !confirm {
+		return toolOK(fmt.Sprintf("Found %d todos. Show them to the user, then call extract_todos again with confirm=true and the todos they want", len(proposals)),
+			map[string]any{"note_id": note.ID, "todos": proposals})
+	}
+	created, err := h.extraction.create(ctx, note, proposals)
+	if errors.Is(err, dao.ErrQuotaExceeded) {
+		return quotaToolError(err)
+	}
+	if err != nil {
+		return toolError("Failed to create todos: %v", err)
+	}
+	reportProgress(ctx, 2, steps, fmt.Sprintf("Created %d todos", len(created)))
+	return toolOK(fmt.Sprintf("Created %d todos", len(created)), map[string]any{"note_id": note.ID, "created": todoUIDs(created)})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/llm"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExtractor struct {
+	todos []llm.TodoProposal
+	err   error
+}
+
+func (f fakeExtractor) ExtractTodos(context.Context, string, time.Time) ([]llm.TodoProposal, error) {
+	return f.todos, f.err
+}
+
+func TestTodoExtractionCreate(t *testing.T) {
+	note := postgres.Notes{ID: "n1", UserUID: strPtr("user-1"), HouseholdUID: strPtr("house-1")}
+	due := time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)
+	todos := mocks.NewMocktodoBatchDAO(t)
+	todos.On("CreateTodos", mock.Anything, mock.MatchedBy(func(ts []postgres.Todo) bool {
+		return len(ts) == 2 && ts[0].Title == "Book dentist" && ts[0].DueDate.Equal(due) && ts[1].DueDate == nil &&
+			*ts[1].HouseholdUID == "house-1" && ts[1].Data == `{"source_note_id":"n1"}`
+	})).Return([]postgres.Todo{{UID: "t1"}, {UID: "t2"}}, nil)
+	e := &todoExtraction{todos: todos}
+
+	created, err := e.create(t.Context(), note, []llm.TodoProposal{{Title: "Book dentist", DueDate: "2025-08-22"}, {Title: "Renew passport"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, todoUIDs(created))
+
+	_, err = e.create(t.Context(), note, nil)
+	assert.Error(t, err)
+	_, err = e.create(t.Context(), note, []llm.TodoProposal{{Title: "x", DueDate: "Friday"}})
+	assert.ErrorContains(t, err, "due_date")
+}
+
+func TestNotesExtractTodos(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("GetNotes", mock.Anything, "n1").Return(postgres.Notes{ID: "n1", Key: "school", Data: "Sign the trip form"}, nil)
+	mockNotesDAO.On("GetNotes", mock.Anything, "missing").Return(postgres.Notes{}, errors.New("no rows"))
+	todos := mocks.NewMocktodoBatchDAO(t)
+	todos.On("CreateTodos", mock.Anything, mock.MatchedBy(func(ts []postgres.Todo) bool {
+		return len(ts) == 1 && ts[0].Title == "Sign trip form by Friday"
+	})).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	handler := NewNotes(mockNotesDAO, WithNoteTodoExtraction(fakeExtractor{todos: []llm.TodoProposal{{Title: "Sign trip form"}}}, todos))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/n1/extract-todos", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"note_id": "n1", "todos": [{"title": "Sign trip form"}]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/n1/extract-todos", strings.NewReader(`{"confirm": true, "todos": [{"title": "Sign trip form by Friday"}]}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"note_id": "n1", "created": ["t1"]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/n1/extract-todos", strings.NewReader(`{"confirm": true, "todos": []}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/missing/extract-todos", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	NewNotes(mockNotesDAO).ServeHTTP(rr, httptest.NewRequest("POST", "/n1/extract-todos", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMCPHandlers_ExtractTodos(t *testing.T) {
+	mockNotesDAO := &MockNotesDAO{}
+	mockNotesDAO.On("GetNotes", mock.Anything, "n1").Return(postgres.Notes{ID: "n1", HouseholdUID: strPtr("house-1")}, nil)
+	todos := mocks.NewMocktodoBatchDAO(t)
+	todos.On("CreateTodos", mock.Anything, mock.MatchedBy(func(ts []postgres.Todo) bool {
+		return len(ts) == 1 && ts[0].Title == "Call plumber" && ts[0].DueDate != nil
+	})).Return([]postgres.Todo{{UID: "t1"}}, nil)
+	extractor := fakeExtractor{todos: []llm.TodoProposal{{Title: "Call plumber", DueDate: "2025-08-20"}, {Title: "Buy bulbs"}}}
+	h := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{}, WithTodoExtraction(extractor, todos))
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "extract_todos", map[string]any{"note_id": "n1"}), &body)
+	assert.Len(t, body["todos"], 2)
+
+	decodeToolResult(t, h.callTool(ctx, "extract_todos", map[string]any{
+		"note_id": "n1",
+		"confirm": true,
+		"todos":   []any{map[string]any{"title": "Call plumber", "due_date": "2025-08-20"}},
+	}), &body)
+	assert.Equal(t, []any{"t1"}, body["created"])
+
+	assert.True(t, h.callTool(ctx, "extract_todos", map[string]any{"note_id": "n1", "todos": "Call plumber"}).IsError)
+
+	withoutLLM := NewMCP(&MockTodoDAO{}, mockNotesDAO, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok := withoutLLM.findTool("extract_todos")
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// todoNearCondition matches todos whose location, widened by its own
+// geofence radius, is within $3 metres of ($1, $2). The placeholders are
+// filled in with the first argument index.
+const todoNearCondition = "(location IS NOT NULL AND distance_m((location->>'lat')::float8, (location->>'lon')::float8, $%d, $%d) <= $%d + COALESCE((location->>'radius_m')::float8, 0))"
+
+// parseNear parses a near filter of the form "lat,lon,radius" with the
+// radius in metres.
+func parseNear(value string) (lat, lon, radius float64, err error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return 0, 0, 0, errors.New("near must be lat,lon,radius")
+	}
+	var nums [3]float64
+	for i, part := range parts {
+		if nums[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil {
+			return 0, 0, 0, fmt.Errorf("near must be lat,lon,radius: %w", err)
+		}
+	}
+	lat, lon, radius = nums[0], nums[1], nums[2]
+	if err := validateTodoLocation(&dao.TodoLocation{Lat: lat, Lon: lon, RadiusM: radius}); err != nil {
+		return 0, 0, 0, err
+	}
+	return lat, lon, radius, nil
+}
+
+func validateTodoLocation(l *dao.TodoLocation) error {
+	switch {
+	case l == nil:
+		return nil
+	case l.Lat < -90 || l.Lat > 90:
+		return errors.New("latitude must be between -90 and 90")
+	case l.Lon < -180 || l.Lon > 180:
+		return errors.New("longitude must be between -180 and 180")
+	case l.RadiusM < 0:
+		return errors.New("radius must not be negative")
+	}
+	return nil
+}
+
+// todoLocationFromMCP builds a location from the lat, lon, location_name and
+// radius_m tool arguments. It returns nil when no coordinates were given.
+func todoLocationFromMCP(arguments map[string]any) (*dao.TodoLocation, error) {
+	lat, hasLat := arguments["lat"].(float64)
+	lon, hasLon := arguments["lon"].(float64)
+	if !hasLat && !hasLon {
+		return nil, nil
+	}
+	if hasLat != hasLon {
+		return nil, errors.New("lat and lon must be given together")
+	}
+	l := &dao.TodoLocation{Lat: lat, Lon: lon}
+	l.Name, _ = arguments["location_name"].(string)
+	l.RadiusM, _ = arguments["radius_m"].(float64)
+	return l, validateTodoLocation(l)
+}
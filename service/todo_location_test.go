@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseNear(t *testing.T) {
+	lat, lon, radius, err := parseNear("47.6062, -122.3321, 1500")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{47.6062, -122.3321, 1500}, []float64{lat, lon, radius})
+
+	for _, bad := range []string{"", "47.6,-122.3", "47.6,-122.3,x", "91,0,100", "0,181,100", "0,0,-1"} {
+		_, _, _, err := parseNear(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestNearFilter(t *testing.T) {
+	filters, err := ParseFilters(map[string]string{"near": "47.6,-122.3,500"}, TodoFilters.Filters)
+	assert.NoError(t, err)
+	where, args := BuildWhereClause(filters, TodoFilters.Filters)
+	assert.Equal(t, "WHERE "+fmt.Sprintf(todoNearCondition, 1, 2, 3), where)
+	assert.Equal(t, []any{47.6, -122.3, 500.0}, args)
+
+	_, err = ParseFilters(map[string]string{"near": "downtown"}, TodoFilters.Filters)
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestTodosLocationValidation(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	handler := NewTodos(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"title": "Return library books", "priority": 2, "location": {"lat": 123, "lon": 0}}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "latitude")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?near=downtown", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMCPHandlers_CreateTodoWithLocation(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	want := &postgres.TodoLocation{Name: "Hardware store", Lat: 47.61, Lon: -122.33, RadiusM: 200}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(todo postgres.Todo) bool {
+		return assert.ObjectsAreEqual(want, todo.Location)
+	})).Return(postgres.Todo{UID: "todo-1", Location: want}, nil)
+	h := &MCPHandlers{todoDAO: mockTodoDAO}
+
+	result := h.handleCreateTodo(t.Context(), map[string]any{
+		"title": "Buy paint", "location_name": "Hardware store", "lat": 47.61, "lon": -122.33, "radius_m": float64(200),
+	})
+	assert.False(t, result.IsError)
+	mockTodoDAO.AssertExpectations(t)
+
+	result = h.handleCreateTodo(t.Context(), map[string]any{"title": "Buy paint", "lat": 47.61})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type setTodoStatusRequest struct {
+	Status      dao.TodoStatus `json:"status"`
+	CompletedBy *string        `json:"completed_by"`
+}
+
+// setStatus moves a todo to another column of the board. Moving it to done
+// completes it, by completed_by if given, and moving it out of done reopens
+// it.
+func (h *todoHandlers) setStatus(w http.ResponseWriter, r *http.Request) {
+	var req setTodoStatusRequest
+	if !decodeTodo(w, r, &req) {
+		return
+	}
+	if req.Status == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "status is required: backlog, planned, in_progress, blocked or done"})
+		return
+	}
+	out, err := h.dao.SetTodoStatus(r.Context(), chi.URLParam(r, "uid"), req.Status, req.CompletedBy)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case errors.Is(err, dao.ErrUnknownCompleter):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
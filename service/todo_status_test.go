@@ -0,0 +1,96 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTodoStatusRoute(t *testing.T) {
+	by := "11111111-1111-1111-1111-111111111111"
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "t1", postgres.TodoInProgress, (*string)(nil)).
+		Return(postgres.Todo{UID: "t1", Status: postgres.TodoInProgress}, nil)
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "t1", postgres.TodoDone, &by).
+		Return(postgres.Todo{UID: "t1", Status: postgres.TodoDone, CompletedBy: &by}, nil)
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "missing", postgres.TodoPlanned, (*string)(nil)).
+		Return(postgres.Todo{}, pgx.ErrNoRows)
+	handler := NewTodos(mockTodoDAO)
+
+	serve := func(target, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", target, strings.NewReader(body)))
+		return rr
+	}
+
+	rr := serve("/t1/status", `{"status": "In progress"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"in_progress"`)
+
+	rr = serve("/t1/status", `{"status": "done", "completed_by": "`+by+`"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"completed_by":"`+by+`"`)
+
+	assert.Equal(t, http.StatusNotFound, serve("/missing/status", `{"status": "planned"}`).Code)
+
+	rr = serve("/t1/status", `{"status": "someday"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "must be backlog, planned, in_progress, blocked or done")
+	assert.Equal(t, http.StatusBadRequest, serve("/t1/status", `{}`).Code)
+}
+
+func TestStatusFilter(t *testing.T) {
+	filters, err := ParseFilters(map[string]string{"status": "!=Done"}, TodoFilters.Filters)
+	assert.NoError(t, err)
+	where, args := BuildWhereClause(filters, TodoFilters.Filters)
+	assert.Equal(t, "WHERE status != $1", where)
+	assert.Equal(t, []any{postgres.TodoDone}, args)
+
+	_, err = ParseFilters(map[string]string{"status": "someday"}, TodoFilters.Filters)
+	assert.ErrorIs(t, err, postgres.ErrInvalidStatus)
+}
+
+func TestMCPHandlers_SetTodoStatus(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	by := "user-1"
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "t1", postgres.TodoDone, &by).
+		Return(postgres.Todo{UID: "t1", Title: "Paint fence", Status: postgres.TodoDone}, nil)
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "t1", postgres.TodoBlocked, (*string)(nil)).
+		Return(postgres.Todo{UID: "t1", Title: "Paint fence", Status: postgres.TodoBlocked}, nil)
+	mockTodoDAO.On("SetTodoStatus", mock.Anything, "missing", postgres.TodoPlanned, (*string)(nil)).
+		Return(postgres.Todo{}, pgx.ErrNoRows)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "set_todo_status", map[string]any{"todo_id": "t1", "status": "done"}), &body)
+	assert.Equal(t, "Moved Paint fence to done", body["summary"])
+
+	// Only moving to done records who did it.
+	decodeToolResult(t, h.callTool(ctx, "set_todo_status", map[string]any{"todo_id": "t1", "status": "blocked"}), &body)
+	assert.Equal(t, "blocked", body["todo"].(map[string]any)["status"])
+
+	assert.True(t, h.callTool(ctx, "set_todo_status", map[string]any{"todo_id": "missing", "status": "planned"}).IsError)
+	assert.True(t, h.callTool(ctx, "set_todo_status", map[string]any{"todo_id": "t1", "status": "someday"}).IsError)
+}
+
+func TestMCPHandlers_CreateTodoWithStatus(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("CreateTodo", mock.Anything, mock.MatchedBy(func(todo postgres.Todo) bool {
+		return todo.Status == postgres.TodoPlanned
+	})).Return(postgres.Todo{UID: "t1", Status: postgres.TodoPlanned}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "create_todo", map[string]any{"title": "Paint fence", "status": "planned"}), &body)
+	assert.Equal(t, "planned", body["todo"].(map[string]any)["status"])
+
+	assert.True(t, h.callTool(t.Context(), "create_todo", map[string]any{"title": "Paint fence", "status": "someday"}).IsError)
+}
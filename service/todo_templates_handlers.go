@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type todoTemplateDAO interface {
+	CreateTodoTemplate(ctx context.Context, t dao.TodoTemplate) (dao.TodoTemplate, error)
+	GetTodoTemplate(ctx context.Context, uid string) (dao.TodoTemplate, error)
+	ListTodoTemplates(ctx context.Context, options dao.ListOptions) ([]dao.TodoTemplate, error)
+	UpdateTodoTemplate(ctx context.Context, uid string, t dao.UpdateTodoTemplate) (dao.TodoTemplate, error)
+	DeleteTodoTemplate(ctx context.Context, uid string) error
+	CreateTodos(ctx context.Context, todos []dao.Todo) ([]dao.Todo, error)
+}
+
+type TodoTemplateHandlers struct{ dao todoTemplateDAO }
+
+func NewTodoTemplates(dao todoTemplateDAO) http.Handler {
+	h := &TodoTemplateHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/", h.create)
+	r.Get("/{uid}", h.get)
+	r.Put("/{uid}", h.update)
+	r.Delete("/{uid}", h.delete)
+	r.Post("/{uid}/apply", h.apply)
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *TodoTemplateHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var t dao.TodoTemplate
+	if !decodeTodo(w, r, &t) {
+		return
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "name is required"})
+		return
+	}
+	if err := validateTemplateItems(t.Items); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.CreateTodoTemplate(r.Context(), t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *TodoTemplateHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.TodoTemplate, error) { return h.dao.GetTodoTemplate(ctx, uid) },
+		h.dao.ListTodoTemplates, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+func (h *TodoTemplateHandlers) update(w http.ResponseWriter, r *http.Request) {
+	var t dao.UpdateTodoTemplate
+	if !decodeTodo(w, r, &t) {
+		return
+	}
+	if err := validateTemplateItems(t.Items); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.UpdateTodoTemplate(r.Context(), chi.URLParam(r, "uid"), t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *TodoTemplateHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteTodoTemplate(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TodoTemplateHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, TodoTemplateFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, TodoTemplateFilters.Filters)
+	if !ok {
+		return
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListTodoTemplates(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// ApplyTemplateRequest fills in a template's placeholders and says who the
+// resulting todos belong to. StartDate (RFC3339, default now) anchors the
+// items' due_in_days.
+type ApplyTemplateRequest struct {
+	Params       map[string]string `json:"params"`
+	UserUID      string            `json:"user_uid"`
+	HouseholdUID string            `json:"household_uid"`
+	StartDate    string            `json:"start_date"`
+}
+
+func (h *TodoTemplateHandlers) apply(w http.ResponseWriter, r *http.Request) {
+	var req ApplyTemplateRequest
+	if json.NewDecoder(r.Body).Decode(&req) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	t, err := h.dao.GetTodoTemplate(r.Context(), chi.URLParam(r, "uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	todos, err := applyTemplate(r.Context(), h.dao, t, req)
+	if err != nil {
+		var invalid *invalidTemplateInput
+		if errors.As(err, &invalid) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if quotaExceeded(w, err) {
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(todos)
+}
+
+// invalidTemplateInput is a problem with the caller's ApplyTemplateRequest
+// rather than with storing the todos.
+type invalidTemplateInput struct{ msg string }
+
+func (e *invalidTemplateInput) Error() string { return e.msg }
+
+// applyTemplate turns t into todos and creates them in one transaction.
+func applyTemplate(ctx context.Context, d todoTemplateDAO, t dao.TodoTemplate, req ApplyTemplateRequest) ([]dao.Todo, error) {
+	start := time.Now()
+	if req.StartDate != "" {
+		parsed, err := time.Parse(time.RFC3339, req.StartDate)
+		if err != nil {
+			return nil, &invalidTemplateInput{"invalid start_date: " + err.Error()}
+		}
+		start = parsed
+	}
+	todos, err := instantiateTemplate(t, req.Params, start, req.UserUID, req.HouseholdUID)
+	if err != nil {
+		return nil, &invalidTemplateInput{err.Error()}
+	}
+	return d.CreateTodos(ctx, todos)
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// templateParams returns the placeholder names used by items, sorted.
+func templateParams(items []dao.TemplateItem) []string {
+	var names []string
+	for _, item := range items {
+		for _, text := range []string{item.Title, item.Description} {
+			for _, m := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+				names = append(names, m[1])
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func validateTemplateItems(items []dao.TemplateItem) error {
+	for i, item := range items {
+		if strings.TrimSpace(item.Title) == "" {
+			return fmt.Errorf("item %d: title is required", i)
+		}
+		if item.Priority != 0 && !item.Priority.Valid() {
+			return fmt.Errorf("item %d: %w %d", i, dao.ErrInvalidPriority, item.Priority)
+		}
+		if item.DueInDays != nil && *item.DueInDays < 0 {
+			return fmt.Errorf("item %d: due_in_days must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// instantiateTemplate builds one todo per template item with its
+// placeholders replaced by params. Every placeholder must have a value.
+func instantiateTemplate(t dao.TodoTemplate, params map[string]string, start time.Time, userUID, householdUID string) ([]dao.Todo, error) {
+	var missing []string
+	for _, name := range templateParams(t.Items) {
+		if _, ok := params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing template parameters: %s", strings.Join(missing, ", "))
+	}
+	fill := func(s string) string {
+		return templatePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+			return params[templatePlaceholder.FindStringSubmatch(m)[1]]
+		})
+	}
+
+	data, _ := json.Marshal(map[string]string{"template_uid": t.UID})
+	todos := make([]dao.Todo, 0, len(t.Items))
+	for _, item := range t.Items {
+		priority := item.Priority
+		if priority == 0 {
+			priority = dao.PriorityMedium
+		}
+		todo := dao.Todo{
+			Title:        fill(item.Title),
+			Description:  fill(item.Description),
+			Data:         string(data),
+			Priority:     priority,
+			UserUID:      &userUID,
+			HouseholdUID: &householdUID,
+		}
+		if item.DueInDays != nil {
+			due := start.AddDate(0, 0, *item.DueInDays)
+			todo.DueDate = &due
+		}
+		todos = append(todos, todo)
+	}
+	return todos, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(i int) *int { return &i }
+
+var packingList = postgres.TodoTemplate{
+	UID:  "11111111-1111-1111-1111-111111111111",
+	Name: "packing list",
+	Items: []postgres.TemplateItem{
+		{Title: "Check the weather in {{destination}}"},
+		{Title: "Pack for {{ nights }} nights", Priority: 4, DueInDays: intPtr(1)},
+		{Title: "Book airport parking", Description: "Flight to {{destination}}"},
+	},
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	assert.Equal(t, []string{"destination", "nights"}, templateParams(packingList.Items))
+
+	start := time.Date(2025, 8, 20, 9, 0, 0, 0, time.UTC)
+	todos, err := instantiateTemplate(packingList, map[string]string{"destination": "Lisbon", "nights": "5"}, start, "user-1", "")
+	assert.NoError(t, err)
+	assert.Len(t, todos, 3)
+	assert.Equal(t, "Check the weather in Lisbon", todos[0].Title)
+	assert.Equal(t, postgres.PriorityMedium, todos[0].Priority)
+	assert.Nil(t, todos[0].DueDate)
+	assert.Equal(t, "Pack for 5 nights", todos[1].Title)
+	assert.Equal(t, start.AddDate(0, 0, 1), *todos[1].DueDate)
+	assert.Equal(t, "Flight to Lisbon", todos[2].Description)
+	assert.JSONEq(t, `{"template_uid": "11111111-1111-1111-1111-111111111111"}`, todos[2].Data)
+
+	_, err = instantiateTemplate(packingList, map[string]string{"destination": "Lisbon"}, start, "user-1", "")
+	assert.EqualError(t, err, "missing template parameters: nights")
+}
+
+func TestTodoTemplatesCreateValidates(t *testing.T) {
+	mockDAO := mocks.NewMocktodoTemplateDAO(t)
+	handler := NewTodoTemplates(mockDAO)
+
+	for _, body := range []string{
+		`{"items": [{"title": "Vacuum"}]}`,
+		`{"name": "weekly cleaning", "items": [{"title": ""}]}`,
+		`{"name": "weekly cleaning", "items": [{"title": "Vacuum", "priority": 9}]}`,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestTodoTemplatesApply(t *testing.T) {
+	mockDAO := mocks.NewMocktodoTemplateDAO(t)
+	mockDAO.On("GetTodoTemplate", mock.Anything, packingList.UID).Return(packingList, nil)
+	mockDAO.On("CreateTodos", mock.Anything, mock.MatchedBy(func(todos []postgres.Todo) bool {
+		return len(todos) == 3 && todos[0].Title == "Check the weather in Oslo"
+	})).Return([]postgres.Todo{{UID: "t1"}, {UID: "t2"}, {UID: "t3"}}, nil)
+	handler := NewTodoTemplates(mockDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/"+packingList.UID+"/apply",
+		strings.NewReader(`{"params": {"destination": "Oslo", "nights": "3"}, "start_date": "2025-08-20T09:00:00Z"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var todos []postgres.Todo
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &todos))
+	assert.Len(t, todos, 3)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/"+packingList.UID+"/apply", strings.NewReader(`{"params": {}}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "missing template parameters")
+}
+
+func TestMCPHandlers_ApplyTemplate(t *testing.T) {
+	mockDAO := mocks.NewMocktodoTemplateDAO(t)
+	mockDAO.On("ListTodoTemplates", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE name = $1" && o.WhereArgs[0] == "packing list"
+	})).Return([]postgres.TodoTemplate{packingList}, nil)
+	mockDAO.On("CreateTodos", mock.Anything, mock.MatchedBy(func(todos []postgres.Todo) bool {
+		return todos[1].Title == "Pack for 4 nights"
+	})).Return([]postgres.Todo{{UID: "t1"}, {UID: "t2"}, {UID: "t3"}}, nil)
+
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{},
+		WithTodoTemplates(mockDAO))
+	_, ok := h.findTool("apply_template")
+	assert.True(t, ok)
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(t.Context(), "apply_template", map[string]any{
+		"template": "packing list",
+		"params":   map[string]any{"destination": "Rome", "nights": float64(4)},
+	}), &body)
+	assert.Equal(t, "Created 3 todos from packing list", body["summary"])
+
+	withoutTemplates := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	_, ok = withoutTemplates.findTool("apply_template")
+	assert.False(t, ok)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// maxLoggedMinutes is the most time one log can record: a whole day.
+const maxLoggedMinutes = 24 * 60
+
+// defaultTimeReportWeeks is how many weeks, including the current one, a
+// time report covers when no range is given.
+const defaultTimeReportWeeks = 4
+
+type logTimeRequest struct {
+	UserUID  string `json:"user_uid"`
+	Minutes  int    `json:"minutes"`
+	LoggedOn string `json:"logged_on"`
+	Note     string `json:"note"`
+}
+
+// newTodoTimeLog checks time logged on a todo. loggedOn, as YYYY-MM-DD,
+// defaults to today and can't be in the future.
+func newTodoTimeLog(todoUID string, req logTimeRequest, now time.Time) (dao.TodoTimeLog, error) {
+	if req.UserUID == "" {
+		return dao.TodoTimeLog{}, errors.New("user_uid is required")
+	}
+	if req.Minutes < 1 || req.Minutes > maxLoggedMinutes {
+		return dao.TodoTimeLog{}, fmt.Errorf("minutes must be between 1 and %d", maxLoggedMinutes)
+	}
+	today := awayDay(now)
+	loggedOn := today
+	if req.LoggedOn != "" {
+		var err error
+		if loggedOn, err = time.Parse(time.DateOnly, req.LoggedOn); err != nil {
+			return dao.TodoTimeLog{}, errors.New("logged_on must be a date like 2025-08-30")
+		}
+	}
+	if loggedOn.After(today) {
+		return dao.TodoTimeLog{}, errors.New("logged_on is in the future")
+	}
+	return dao.TodoTimeLog{TodoUID: todoUID, UserUID: req.UserUID, Minutes: req.Minutes, LoggedOn: loggedOn, Note: strings.TrimSpace(req.Note)}, nil
+}
+
+// logTime records time spent on a todo.
+func (h *todoHandlers) logTime(w http.ResponseWriter, r *http.Request) {
+	var req logTimeRequest
+	if !decodeTodo(w, r, &req) {
+		return
+	}
+	l, err := newTodoTimeLog(chi.URLParam(r, "uid"), req, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	out, err := h.dao.LogTodoTime(r.Context(), l)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case errors.Is(err, dao.ErrUnknownTimeUser), errors.Is(err, dao.ErrInvalidMinutes):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *todoHandlers) timeLogs(w http.ResponseWriter, r *http.Request) {
+	out, err := h.dao.ListTodoTimeLogs(r.Context(), chi.URLParam(r, "uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// TodoTimeReport is the time logged on todos per week, From and To
+// included, with each week broken down by user.
+type TodoTimeReport struct {
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Title        string     `json:"title,omitempty"`
+	TotalMinutes int64      `json:"total_minutes"`
+	Weeks        []WeekTime `json:"weeks"`
+}
+
+// WeekTime is the time logged in the week starting Week, a Monday.
+type WeekTime struct {
+	Week         string              `json:"week"`
+	TotalMinutes int64               `json:"total_minutes"`
+	Users        []dao.TodoTimeSpent `json:"users"`
+}
+
+// weekStart is the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	t = awayDay(t)
+	return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+}
+
+// parseDateRange turns from and to dates into the start of from and the day
+// after to. Either may be empty; the default range ends today and starts on
+// the Monday defaultTimeReportWeeks weeks back, counting this week.
+func parseDateRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	end := awayDay(now)
+	if to != "" {
+		t, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date like 2025-08-30")
+		}
+		end = t
+	}
+	start := weekStart(end).AddDate(0, 0, -7*(defaultTimeReportWeeks-1))
+	if from != "" {
+		t, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date like 2025-08-01")
+		}
+		start = t
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// todoTimeReport totals the time logged on the todos of a household, by a
+// user, or both, from the start of from up to, not including, to. Weeks
+// without any time are reported as zero.
+func todoTimeReport(ctx context.Context, d todoDAO, householdUID, userUID string, from, to time.Time, title string) (TodoTimeReport, error) {
+	rows, err := d.WeeklyTodoTime(ctx, householdUID, userUID, from, to, title)
+	if err != nil {
+		return TodoTimeReport{}, err
+	}
+	report := TodoTimeReport{
+		From:  from.Format(time.DateOnly),
+		To:    to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Title: title,
+		Weeks: []WeekTime{},
+	}
+	byWeek := map[string]int{}
+	for w := weekStart(from); w.Before(to); w = w.AddDate(0, 0, 7) {
+		byWeek[w.Format(time.DateOnly)] = len(report.Weeks)
+		report.Weeks = append(report.Weeks, WeekTime{Week: w.Format(time.DateOnly), Users: []dao.TodoTimeSpent{}})
+	}
+	for _, row := range rows {
+		i, ok := byWeek[row.Week]
+		if !ok {
+			continue
+		}
+		report.Weeks[i].Users = append(report.Weeks[i].Users, row)
+		report.Weeks[i].TotalMinutes += row.Minutes
+		report.TotalMinutes += row.Minutes
+	}
+	return report, nil
+}
+
+// formatMinutes writes a duration of whole minutes, e.g. "3h 20m".
+func formatMinutes(minutes int64) string {
+	switch h, m := minutes/60, minutes%60; {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+}
+
+type StatsHandlers struct{ dao todoDAO }
+
+func NewStats(dao todoDAO) http.Handler {
+	h := &StatsHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/time", h.time)
+	return r
+}
+
+// time reports the time logged on a household's or user's todos per week.
+// from and to are dates, both included, defaulting to the last four weeks;
+// title narrows it to todos whose title contains it.
+func (h *StatsHandlers) time(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	householdUID, userUID := q.Get("household_uid"), q.Get("user_uid")
+	if householdUID == "" && userUID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "household_uid or user_uid is required"})
+		return
+	}
+	from, to, err := parseDateRange(q.Get("from"), q.Get("to"), time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	report, err := todoTimeReport(r.Context(), h.dao, householdUID, userUID, from, to, strings.TrimSpace(q.Get("title")))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTodoTimeLog(t *testing.T) {
+	now := time.Date(2025, 9, 10, 18, 0, 0, 0, time.UTC)
+
+	l, err := newTodoTimeLog("t1", logTimeRequest{UserUID: "user-1", Minutes: 90, Note: " mowed "}, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC), l.LoggedOn)
+	assert.Equal(t, "mowed", l.Note)
+
+	l, err = newTodoTimeLog("t1", logTimeRequest{UserUID: "user-1", Minutes: 30, LoggedOn: "2025-09-01"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), l.LoggedOn)
+
+	for req, want := range map[logTimeRequest]string{
+		{Minutes: 30}:                      "user_uid is required",
+		{UserUID: "user-1"}:                "minutes must be between 1 and 1440",
+		{UserUID: "user-1", Minutes: 1441}: "minutes must be between 1 and 1440",
+		{UserUID: "user-1", Minutes: 30, LoggedOn: "Monday"}:     "logged_on must be a date like 2025-08-30",
+		{UserUID: "user-1", Minutes: 30, LoggedOn: "2025-09-11"}: "logged_on is in the future",
+	} {
+		_, err := newTodoTimeLog("t1", req, now)
+		assert.EqualError(t, err, want)
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	// A Wednesday: the default range starts on the Monday three weeks back.
+	now := time.Date(2025, 9, 10, 18, 0, 0, 0, time.UTC)
+	from, to, err := parseDateRange("", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 9, 11, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parseDateRange("2025-09-01", "2025-09-30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = parseDateRange("2025-09-30", "2025-09-01", now)
+	assert.EqualError(t, err, "from must not be after to")
+	_, _, err = parseDateRange("September", "", now)
+	assert.Error(t, err)
+}
+
+func TestTodoTimeReport(t *testing.T) {
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 9, 22, 0, 0, 0, 0, time.UTC)
+	mockDAO := mocks.NewMocktodoDAO(t)
+	mockDAO.On("WeeklyTodoTime", mock.Anything, "house-1", "", from, to, "yard").Return([]postgres.TodoTimeSpent{
+		{Week: "2025-09-01", UserUID: "user-1", Minutes: 90, Logs: 2},
+		{Week: "2025-09-01", UserUID: "user-2", Minutes: 30, Logs: 1},
+		{Week: "2025-09-15", UserUID: "user-1", Minutes: 45, Logs: 1},
+	}, nil)
+
+	report, err := todoTimeReport(t.Context(), mockDAO, "house-1", "", from, to, "yard")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-09-21", report.To)
+	assert.Equal(t, int64(165), report.TotalMinutes)
+	require.Len(t, report.Weeks, 3)
+	assert.Equal(t, int64(120), report.Weeks[0].TotalMinutes)
+	assert.Len(t, report.Weeks[0].Users, 2)
+	assert.Equal(t, "2025-09-08", report.Weeks[1].Week)
+	assert.Empty(t, report.Weeks[1].Users)
+	assert.Equal(t, int64(45), report.Weeks[2].TotalMinutes)
+}
+
+func TestFormatMinutes(t *testing.T) {
+	assert.Equal(t, "45m", formatMinutes(45))
+	assert.Equal(t, "2h", formatMinutes(120))
+	assert.Equal(t, "3h 20m", formatMinutes(200))
+}
+
+func TestLogTimeRoute(t *testing.T) {
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("LogTodoTime", mock.Anything, mock.MatchedBy(func(l postgres.TodoTimeLog) bool { return l.TodoUID == "t1" })).
+		Return(postgres.TodoTimeLog{UID: "l1", TodoUID: "t1", Minutes: 45}, nil)
+	mockTodoDAO.On("LogTodoTime", mock.Anything, mock.MatchedBy(func(l postgres.TodoTimeLog) bool { return l.TodoUID == "missing" })).
+		Return(postgres.TodoTimeLog{}, pgx.ErrNoRows)
+	mockTodoDAO.On("ListTodoTimeLogs", mock.Anything, "t1").Return([]postgres.TodoTimeLog{{UID: "l1"}}, nil)
+	handler := NewTodos(mockTodoDAO)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rr
+	}
+
+	rr := serve("POST", "/t1/log-time", `{"user_uid": "user-1", "minutes": 45}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"minutes":45`)
+
+	assert.Equal(t, http.StatusNotFound, serve("POST", "/missing/log-time", `{"user_uid": "user-1", "minutes": 45}`).Code)
+	rr = serve("POST", "/t1/log-time", `{"user_uid": "user-1", "minutes": 0}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "minutes must be between 1 and 1440")
+
+	rr = serve("GET", "/t1/time-logs", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uid":"l1"`)
+}
+
+func TestStatsTimeRoute(t *testing.T) {
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	mockTodoDAO := mocks.NewMocktodoDAO(t)
+	mockTodoDAO.On("WeeklyTodoTime", mock.Anything, "house-1", "", from, to, "yard").
+		Return([]postgres.TodoTimeSpent{{Week: "2025-09-08", UserUID: "user-1", Minutes: 200, Logs: 3}}, nil)
+	handler := NewStats(mockTodoDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/time?household_uid=house-1&from=2025-09-01&to=2025-09-30&title=yard", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"total_minutes":200`)
+	assert.Contains(t, rr.Body.String(), `"week":"2025-09-29"`)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/time", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/time?user_uid=user-1&from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMCPHandlers_LogTimeAndReport(t *testing.T) {
+	mockTodoDAO := &MockTodoDAO{}
+	mockTodoDAO.On("LogTodoTime", mock.Anything, mock.MatchedBy(func(l postgres.TodoTimeLog) bool {
+		return l.TodoUID == "t1" && l.UserUID == "user-1" && l.Minutes == 200
+	})).Return(postgres.TodoTimeLog{UID: "l1", TodoUID: "t1", Minutes: 200, LoggedOn: time.Date(2025, 9, 6, 0, 0, 0, 0, time.UTC)}, nil)
+	mockTodoDAO.On("WeeklyTodoTime", mock.Anything, "house-1", "", mock.Anything, mock.Anything, "yard").
+		Return([]postgres.TodoTimeSpent{{Week: "2025-09-01", UserUID: "user-1", Minutes: 200, Logs: 1}}, nil)
+	h := NewMCP(mockTodoDAO, &MockNotesDAO{}, &MockPreferencesDAO{}, &MockRecipesDAO{}, &MockUserDAO{}, &MockHouseholdDAO{})
+	ctx := identityContext("user-1", "house-1")
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(ctx, "log_time", map[string]any{"todo_id": "t1", "minutes": float64(200), "logged_on": "2025-09-06"}), &body)
+	assert.Equal(t, "Logged 3h 20m on 2025-09-06", body["summary"])
+
+	decodeToolResult(t, h.callTool(ctx, "time_report", map[string]any{"from": "2025-09-01", "to": "2025-09-30", "title": "yard"}), &body)
+	assert.Equal(t, `Logged 3h 20m on todos matching "yard" from 2025-09-01 to 2025-09-30`, body["summary"])
+
+	assert.True(t, h.callTool(ctx, "log_time", map[string]any{"todo_id": "t1"}).IsError)
+	assert.True(t, h.callTool(ctx, "time_report", map[string]any{"from": "2025-09-30", "to": "2025-09-01"}).IsError)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// csvType is the media type todo lists are exported and imported as.
+const csvType = "text/csv"
+
+// maxImportRows caps the todos one import creates.
+const maxImportRows = 1000
+
+// todoCSVColumn is a column of an exported todo list and how a todo fills
+// it.
+type todoCSVColumn struct {
+	name  string
+	value func(t dao.Todo) string
+}
+
+// todoCSVColumns are the columns of an exported todo list. An import reads
+// the ones in todoImportFields back.
+var todoCSVColumns = []todoCSVColumn{
+	{"uid", func(t dao.Todo) string { return t.UID }},
+	{"title", func(t dao.Todo) string { return t.Title }},
+	{"description", func(t dao.Todo) string { return t.Description }},
+	{"priority", func(t dao.Todo) string {
+		if t.Priority == 0 {
+			return ""
+		}
+		return t.Priority.String()
+	}},
+	{"status", func(t dao.Todo) string { return string(t.Status) }},
+	{"due_date", func(t dao.Todo) string { return csvTime(t.DueDate) }},
+	{"recurs_on", func(t dao.Todo) string { return t.RecursOn }},
+	{"estimate_minutes", func(t dao.Todo) string {
+		if t.EstimateMinutes == nil {
+			return ""
+		}
+		return strconv.Itoa(*t.EstimateMinutes)
+	}},
+	{"external_url", func(t dao.Todo) string { return t.ExternalURL }},
+	{"user_uid", func(t dao.Todo) string { return derefString(t.UserUID) }},
+	{"household_uid", func(t dao.Todo) string { return derefString(t.HouseholdUID) }},
+	{"project_uid", func(t dao.Todo) string { return derefString(t.ProjectUID) }},
+	{"marked_complete", func(t dao.Todo) string { return csvTime(t.MarkedComplete) }},
+	{"created_at", func(t dao.Todo) string { return csvTime(&t.CreatedAt) }},
+}
+
+// todoImportFields are the todo fields an import can set from a column.
+var todoImportFields = []string{"title", "description", "priority", "status", "due_date", "recurs_on", "estimate_minutes", "external_url", "project_uid"}
+
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// wantsCSV reports whether the request asks for CSV, with ?format=csv or
+// an Accept header naming csvType.
+func wantsCSV(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "csv" {
+		return true
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == csvType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTodosCSV writes todos as a spreadsheet with a header row. fields,
+// when given, picks and orders the columns.
+func writeTodosCSV(w http.ResponseWriter, todos []dao.Todo, fields []string) {
+	columns := todoCSVColumns
+	if len(fields) > 0 {
+		columns = nil
+		for _, f := range fields {
+			if i := slices.IndexFunc(todoCSVColumns, func(c todoCSVColumn) bool { return c.name == f }); i >= 0 {
+				columns = append(columns, todoCSVColumns[i])
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", csvType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="todos.csv"`)
+	w.Header().Add("Vary", "Accept")
+	out := csv.NewWriter(w)
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = c.name
+	}
+	_ = out.Write(row)
+	for _, t := range todos {
+		for i, c := range columns {
+			row[i] = c.value(t)
+		}
+		if out.Write(row) != nil {
+			return
+		}
+	}
+	out.Flush()
+}
+
+// ImportTodosResponse is what an import created, or on a dry run would
+// create, and the rows it couldn't read. Rows count from 1 at the header.
+type ImportTodosResponse struct {
+	DryRun bool             `json:"dry_run"`
+	Todos  []dao.Todo       `json:"todos"`
+	Errors []ImportRowError `json:"errors"`
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// parseColumnMapping reads ?columns=title:Chore,due_date:Due into the todo
+// field each named header fills. Fields not mapped are read from a header
+// of their own name, in any case, so an export imports as it is.
+func parseColumnMapping(s string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, header, ok := strings.Cut(pair, ":")
+		field, header = strings.TrimSpace(field), strings.TrimSpace(header)
+		if !ok || header == "" {
+			return nil, fmt.Errorf("columns takes field:header pairs, got %q", pair)
+		}
+		if !slices.Contains(todoImportFields, field) {
+			return nil, fmt.Errorf("unknown field %s: columns maps %s", field, strings.Join(todoImportFields, ", "))
+		}
+		mapping[field] = header
+	}
+	return mapping, nil
+}
+
+// parseImportDate reads an RFC 3339 time or a plain date, as spreadsheets
+// tend to hold.
+func parseImportDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("due_date %q must be a date (2006-01-02) or an RFC 3339 time", s)
+}
+
+// readImportRow makes a todo of one row, cells keyed by todo field.
+func readImportRow(cells map[string]string) (dao.Todo, error) {
+	t := dao.Todo{
+		UID:         uuid.NewString(),
+		Title:       cells["title"],
+		Description: cells["description"],
+		Data:        "{}",
+		Priority:    dao.PriorityMedium,
+		Status:      dao.TodoStatus(strings.ToLower(cells["status"])),
+		RecursOn:    cells["recurs_on"],
+		ExternalURL: cells["external_url"],
+	}
+	if t.Title == "" {
+		return t, errors.New("title is required")
+	}
+	if v := cells["priority"]; v != "" {
+		p, err := dao.ParsePriority(v)
+		if err != nil {
+			return t, err
+		}
+		t.Priority = p
+	}
+	if t.Status != "" && !slices.Contains(dao.TodoStatuses, t.Status) {
+		return t, fmt.Errorf("%w: %s", dao.ErrInvalidStatus, cells["status"])
+	}
+	if v := cells["due_date"]; v != "" {
+		due, err := parseImportDate(v)
+		if err != nil {
+			return t, err
+		}
+		t.DueDate = &due
+	}
+	if v := cells["estimate_minutes"]; v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			return t, fmt.Errorf("estimate_minutes %q must be a positive number", v)
+		}
+		t.EstimateMinutes = &minutes
+	}
+	if v := cells["project_uid"]; v != "" {
+		t.ProjectUID = &v
+	}
+	return t, nil
+}
+
+// importCSV creates todos from a CSV body for the household_uid and
+// user_uid in the query. ?columns= maps todo fields to headers, and
+// ?dry_run=true previews the todos without creating any. Nothing is
+// created unless every row can be read.
+func (h *todoHandlers) importCSV(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	householdUID, userUID := query.Get("household_uid"), query.Get("user_uid")
+	if householdUID == "" && userUID == "" {
+		writeImportError(w, "household_uid or user_uid is required")
+		return
+	}
+	mapping, err := parseColumnMapping(query.Get("columns"))
+	if err != nil {
+		writeImportError(w, err.Error())
+		return
+	}
+	dryRun := query.Get("dry_run") == "true"
+
+	in := csv.NewReader(r.Body)
+	in.FieldsPerRecord = -1
+	in.TrimLeadingSpace = true
+	header, err := in.Read()
+	if errors.Is(err, io.EOF) {
+		writeImportError(w, "the CSV is empty")
+		return
+	}
+	if err != nil {
+		writeImportError(w, "invalid CSV: "+err.Error())
+		return
+	}
+	index := map[string]int{}
+	for _, field := range todoImportFields {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+		i := slices.IndexFunc(header, func(h string) bool { return strings.EqualFold(strings.TrimSpace(h), name) })
+		if i < 0 {
+			if _, ok := mapping[field]; ok {
+				writeImportError(w, "no "+name+" column for "+field)
+				return
+			}
+			continue
+		}
+		index[field] = i
+	}
+	if _, ok := index["title"]; !ok {
+		writeImportError(w, "no title column; map one with columns=title:<header>")
+		return
+	}
+
+	out := ImportTodosResponse{DryRun: dryRun, Todos: []dao.Todo{}, Errors: []ImportRowError{}}
+	var rows []int
+	for row := 2; ; row++ {
+		record, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			out.Errors = append(out.Errors, ImportRowError{Row: row, Error: err.Error()})
+			break
+		}
+		if len(out.Todos)+len(out.Errors) >= maxImportRows {
+			writeImportError(w, "an import takes at most "+strconv.Itoa(maxImportRows)+" rows")
+			return
+		}
+		cells := map[string]string{}
+		for field, i := range index {
+			if i < len(record) {
+				cells[field] = strings.TrimSpace(record[i])
+			}
+		}
+		if !slices.ContainsFunc(record, func(cell string) bool { return strings.TrimSpace(cell) != "" }) {
+			continue
+		}
+		t, err := readImportRow(cells)
+		if err != nil {
+			out.Errors = append(out.Errors, ImportRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		if householdUID != "" {
+			t.HouseholdUID = &householdUID
+		}
+		if userUID != "" {
+			t.UserUID = &userUID
+		}
+		out.Todos = append(out.Todos, t)
+		rows = append(rows, row)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(out.Errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(out)
+		return
+	}
+	if dryRun {
+		_ = json.NewEncoder(w).Encode(out)
+		return
+	}
+	for i, t := range out.Todos {
+		created, err := h.dao.CreateTodo(r.Context(), t)
+		if errors.Is(err, dao.ErrInvalidStatus) || errors.Is(err, dao.ErrInvalidEstimate) || errors.Is(err, dao.ErrUnknownProject) {
+			// The rows before this one are already created; say which.
+			out.Todos = out.Todos[:i]
+			out.Errors = append(out.Errors, ImportRowError{Row: rows[i], Error: err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		if errors.Is(err, dao.ErrQuotaExceeded) {
+			out.Todos = out.Todos[:i]
+			out.Errors = append(out.Errors, ImportRowError{Row: rows[i], Error: quotaMessage(err)})
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		if err != nil {
+			slog.Error("failed to import todo", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		out.Todos[i] = created
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func writeImportError(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
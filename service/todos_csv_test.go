@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodosCSVExport(t *testing.T) {
+	due := time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC)
+	todos := &MockTodoDAO{}
+	todos.On("ListTodos", mock.Anything, mock.MatchedBy(func(o dao.ListOptions) bool {
+		return assert.ObjectsAreEqual([]any{"house-1"}, o.WhereArgs)
+	})).Return([]dao.Todo{
+		{UID: "t1", Title: "Bins out", Priority: dao.PriorityHigh, Status: dao.TodoPlanned, DueDate: &due, HouseholdUID: strPtr("house-1")},
+		{UID: "t2", Title: "Water plants, front and back", Priority: dao.PriorityLow, HouseholdUID: strPtr("house-1")},
+	}, nil)
+	handler := NewTodos(todos)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?household_uid=house-1&format=csv&fields=title,priority,due_date", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "title,priority,due_date\n"+
+		"Bins out,high,2025-09-20T09:00:00Z\n"+
+		"\"Water plants, front and back\",low,\n", rr.Body.String())
+
+	req := httptest.NewRequest("GET", "/?household_uid=house-1", nil)
+	req.Header.Set("Accept", "text/csv")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "uid,title,description,priority,status,due_date,"))
+	assert.Contains(t, rr.Body.String(), "t1,Bins out,,high,planned,2025-09-20T09:00:00Z,")
+}
+
+func TestTodosCSVImport(t *testing.T) {
+	sheet := "Chore,Due,Priority,Notes\n" +
+		"Bins out,2025-09-20,high,Blue bin\n" +
+		",,,\n" +
+		"Mow the lawn,,,\n"
+	todos := &MockTodoDAO{}
+	handler := NewTodos(todos)
+	post := func(target, body string) (*httptest.ResponseRecorder, ImportTodosResponse) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", target, strings.NewReader(body)))
+		var out ImportTodosResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &out)
+		return rr, out
+	}
+	mapping := "&columns=title:Chore,due_date:Due,description:Notes"
+
+	// A dry run previews the todos without creating them.
+	rr, out := post("/import?household_uid=house-1&dry_run=true"+mapping, sheet)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.True(t, out.DryRun)
+	require.Len(t, out.Todos, 2)
+	assert.Equal(t, "Bins out", out.Todos[0].Title)
+	assert.Equal(t, "Blue bin", out.Todos[0].Description)
+	assert.Equal(t, dao.PriorityHigh, out.Todos[0].Priority)
+	assert.Equal(t, time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC), *out.Todos[0].DueDate)
+	assert.Equal(t, "house-1", *out.Todos[0].HouseholdUID)
+	assert.Equal(t, dao.PriorityMedium, out.Todos[1].Priority)
+	todos.AssertNotCalled(t, "CreateTodo", mock.Anything, mock.Anything)
+
+	todos.On("CreateTodo", mock.Anything, mock.MatchedBy(func(td dao.Todo) bool {
+		return *td.HouseholdUID == "house-1" && td.UID != ""
+	})).Return(dao.Todo{UID: "created"}, nil)
+	rr, out = post("/import?household_uid=house-1"+mapping, sheet)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.False(t, out.DryRun)
+	require.Len(t, out.Todos, 2)
+	assert.Equal(t, "created", out.Todos[0].UID)
+	todos.AssertNumberOfCalls(t, "CreateTodo", 2)
+
+	// Rows that can't be read are reported, and nothing is created.
+	rr, out = post("/import?household_uid=house-1", "title,priority,due_date\nBins out,urgent,\nMow,low,next week\nSweep,low,\n")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Len(t, out.Errors, 2)
+	assert.Equal(t, 2, out.Errors[0].Row)
+	assert.Contains(t, out.Errors[0].Error, "urgent")
+	assert.Equal(t, 3, out.Errors[1].Row)
+	todos.AssertNumberOfCalls(t, "CreateTodo", 2)
+
+	for _, tc := range []struct{ target, body, want string }{
+		{"/import", sheet, "household_uid or user_uid is required"},
+		{"/import?household_uid=house-1&columns=colour:Chore", sheet, "unknown field colour"},
+		{"/import?household_uid=house-1", sheet, "no title column"},
+		{"/import?household_uid=house-1&columns=title:Task", sheet, "no Task column for title"},
+		{"/import?household_uid=house-1", "", "the CSV is empty"},
+	} {
+		rr, _ := post(tc.target, tc.body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, tc.target)
+		assert.Contains(t, rr.Body.String(), tc.want, tc.target)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// Tools the bootstrap response allows and disallows when no tool policy
+// says otherwise.
+var (
+	defaultAllowedTools    = []string{"mcp__assistant-mcp"}
+	defaultDisallowedTools = []string{"TodoWrite"}
+)
+
+type toolPolicyDAO interface {
+	CreateToolPolicy(ctx context.Context, p dao.ToolPolicy) (dao.ToolPolicy, error)
+	GetToolPolicy(ctx context.Context, uid string) (dao.ToolPolicy, error)
+	ListToolPolicies(ctx context.Context, options dao.ListOptions) ([]dao.ToolPolicy, error)
+	UpdateToolPolicy(ctx context.Context, uid string, p dao.ToolPolicy) (dao.ToolPolicy, error)
+	DeleteToolPolicy(ctx context.Context, uid string) error
+}
+
+type ToolPolicyHandlers struct{ dao toolPolicyDAO }
+
+func NewToolPolicies(dao toolPolicyDAO) http.Handler {
+	h := &ToolPolicyHandlers{dao}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/{uid}", h.get)
+	r.Put("/{uid}", h.update)
+	r.Delete("/{uid}", h.delete)
+	r.Get("/", h.list)
+	return r
+}
+
+func (h *ToolPolicyHandlers) create(w http.ResponseWriter, r *http.Request) {
+	var p dao.ToolPolicy
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// A policy applies to exactly one user or one household.
+	if (p.UserUID == nil || *p.UserUID == "") == (p.HouseholdUID == nil || *p.HouseholdUID == "") {
+		http.Error(w, "exactly one of user_uid or household_uid is required", http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.CreateToolPolicy(r.Context(), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *ToolPolicyHandlers) get(w http.ResponseWriter, r *http.Request) {
+	uid := chi.URLParam(r, "uid")
+	out, err := getWithFields(r, func(ctx context.Context) (dao.ToolPolicy, error) { return h.dao.GetToolPolicy(ctx, uid) },
+		h.dao.ListToolPolicies, "WHERE uid = $1", uid)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusNotFound)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// update replaces both tool lists; omit a list to inherit it again.
+func (h *ToolPolicyHandlers) update(w http.ResponseWriter, r *http.Request) {
+	var p dao.ToolPolicy
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	out, err := h.dao.UpdateToolPolicy(r.Context(), chi.URLParam(r, "uid"), p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *ToolPolicyHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteToolPolicy(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ToolPolicyHandlers) list(w http.ResponseWriter, r *http.Request) {
+	params := ParseListParams(r, ToolPolicyFilters.SortFields)
+	whereClause, whereArgs, ok := whereFromParams(w, params, ToolPolicyFilters.Filters)
+	if !ok {
+		return
+	}
+
+	options := dao.ListOptions{
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+		SortBy:      params.SortBy,
+		SortDir:     params.SortDir,
+		WhereClause: whereClause,
+		WhereArgs:   whereArgs,
+		Fields:      params.Fields,
+	}
+
+	out, err := h.dao.ListToolPolicies(r.Context(), options)
+	if err != nil {
+		writeFieldsError(w, err, http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// resolveToolPolicy picks the allowed and disallowed tools for a user from
+// the policies returned by GetToolPoliciesForUser. Each list comes from the
+// user's policy if it sets one, else the household's, else the default.
+func resolveToolPolicy(policies []dao.ToolPolicy, userUID string) (allowed, disallowed []string) {
+	var user, household *dao.ToolPolicy
+	for i := range policies {
+		if policies[i].UserUID != nil && *policies[i].UserUID == userUID {
+			user = &policies[i]
+		} else if policies[i].HouseholdUID != nil {
+			household = &policies[i]
+		}
+	}
+
+	allowed, disallowed = defaultAllowedTools, defaultDisallowedTools
+	for _, p := range []*dao.ToolPolicy{household, user} {
+		if p == nil {
+			continue
+		}
+		if p.AllowedTools != nil {
+			allowed = p.AllowedTools
+		}
+		if p.DisallowedTools != nil {
+			disallowed = p.DisallowedTools
+		}
+	}
+	return allowed, disallowed
+}
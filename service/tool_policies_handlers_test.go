@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestToolPoliciesCreate(t *testing.T) {
+	mockToolPolicyDAO := mocks.NewMocktoolPolicyDAO(t)
+	mockToolPolicyDAO.On("CreateToolPolicy", mock.Anything, postgres.ToolPolicy{UserUID: strPtr("user-1"), AllowedTools: []string{"mcp__assistant-mcp", "WebSearch"}}).
+		Return(postgres.ToolPolicy{UID: "policy-1", UserUID: strPtr("user-1"), AllowedTools: []string{"mcp__assistant-mcp", "WebSearch"}}, nil)
+	handler := NewToolPolicies(mockToolPolicyDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(`{"user_uid": "user-1", "allowed_tools": ["mcp__assistant-mcp", "WebSearch"]}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var out postgres.ToolPolicy
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, "policy-1", out.UID)
+	assert.Nil(t, out.DisallowedTools)
+}
+
+func TestToolPoliciesCreateRequiresOneOwner(t *testing.T) {
+	handler := NewToolPolicies(mocks.NewMocktoolPolicyDAO(t))
+	for _, body := range []string{
+		`{"allowed_tools": ["WebSearch"]}`,
+		`{"user_uid": "user-1", "household_uid": "house-1"}`,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestToolPoliciesList(t *testing.T) {
+	mockToolPolicyDAO := mocks.NewMocktoolPolicyDAO(t)
+	mockToolPolicyDAO.On("ListToolPolicies", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool {
+		return o.WhereClause == "WHERE household_uid = $1" && assert.ObjectsAreEqual([]any{"house-1"}, o.WhereArgs)
+	})).Return([]postgres.ToolPolicy{{UID: "policy-1", HouseholdUID: strPtr("house-1")}}, nil)
+	handler := NewToolPolicies(mockToolPolicyDAO)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/?household_uid=house-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestResolveToolPolicy(t *testing.T) {
+	household := postgres.ToolPolicy{HouseholdUID: strPtr("house-1"), AllowedTools: []string{"mcp__assistant-mcp", "WebFetch"}, DisallowedTools: []string{}}
+	user := postgres.ToolPolicy{UserUID: strPtr("user-1"), DisallowedTools: []string{"Bash"}}
+
+	allowed, disallowed := resolveToolPolicy(nil, "user-1")
+	assert.Equal(t, defaultAllowedTools, allowed)
+	assert.Equal(t, defaultDisallowedTools, disallowed)
+
+	allowed, disallowed = resolveToolPolicy([]postgres.ToolPolicy{household}, "user-1")
+	assert.Equal(t, []string{"mcp__assistant-mcp", "WebFetch"}, allowed)
+	assert.Empty(t, disallowed)
+
+	// The user's policy overrides only the lists it sets.
+	allowed, disallowed = resolveToolPolicy([]postgres.ToolPolicy{user, household}, "user-1")
+	assert.Equal(t, []string{"mcp__assistant-mcp", "WebFetch"}, allowed)
+	assert.Equal(t, []string{"Bash"}, disallowed)
+}
+
+func TestBootstrapUsesToolPolicies(t *testing.T) {
+	user := postgres.Users{UID: "user-1", Name: "Sam", HouseholdUID: strPtr("house-1")}
+	mockBootstrapDAO := mocks.NewMockbootstrapDAO(t)
+	mockBootstrapDAO.On("GetUserBySlackUserUID", mock.Anything, "U123").Return(user, nil)
+	mockBootstrapDAO.On("GetCredentialsByUserUID", mock.Anything, "user-1").Return([]postgres.Credentials{}, nil)
+	mockBootstrapDAO.On("GetTodosByUserUID", mock.Anything, "user-1").Return([]postgres.Todo{}, nil)
+	mockBootstrapDAO.On("GetNotesByUserUID", mock.Anything, "user-1").Return([]postgres.Notes{}, nil)
+	mockBootstrapDAO.On("GetPreferencesByUserUID", mock.Anything, "user-1").Return([]postgres.Preferences{}, nil)
+	mockBootstrapDAO.On("GetHousehold", mock.Anything, "house-1").Return(postgres.Households{UID: "house-1", Name: "Home"}, nil)
+	mockBootstrapDAO.On("GetToolPoliciesForUser", mock.Anything, "user-1", strPtr("house-1")).Return([]postgres.ToolPolicy{
+		{HouseholdUID: strPtr("house-1"), AllowedTools: []string{"mcp__assistant-mcp", "WebSearch"}},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	NewBootstrap(mockBootstrapDAO).ServeHTTP(rr, httptest.NewRequest("GET", "/?slack_id=U123", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var out BootstrapResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, []string{"mcp__assistant-mcp", "WebSearch"}, out.AllowedTools)
+	assert.Equal(t, []string{"TodoWrite"}, out.DisallowedTools)
+}
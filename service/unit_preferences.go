@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+)
+
+// UnitsPreferenceKey is the preference key, specified by household UID,
+// holding a household's UnitPreferences as JSON.
+const UnitsPreferenceKey = "units"
+
+// UnitPreferences is the measurement system a household reads quantities
+// in. Recipes and shopping lists show their quantities converted to System;
+// what is stored is never changed. An empty System shows quantities as the
+// recipe gives them.
+type UnitPreferences struct {
+	HouseholdUID string             `json:"household_uid"`
+	System       measurement.System `json:"system"`
+}
+
+func (p UnitPreferences) Validate() error {
+	switch p.System {
+	case "", measurement.Metric, measurement.Imperial:
+		return nil
+	}
+	return errors.New("system must be metric or imperial")
+}
+
+// loadUnitPreferences returns a household's unit preferences, or none when
+// it hasn't set any.
+func loadUnitPreferences(ctx context.Context, prefs preferencesDAO, householdUID string) (UnitPreferences, error) {
+	p := UnitPreferences{HouseholdUID: householdUID}
+	stored, err := prefs.GetPreferences(ctx, UnitsPreferenceKey, householdUID)
+	if err != nil {
+		return p, nil
+	}
+	err = json.Unmarshal([]byte(stored.Data), &p)
+	p.HouseholdUID = householdUID
+	return p, err
+}
+
+func saveUnitPreferences(ctx context.Context, prefs preferencesDAO, p UnitPreferences) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	pref := dao.Preferences{Key: UnitsPreferenceKey, Specifier: p.HouseholdUID, Data: string(data)}
+	if _, err := prefs.GetPreferences(ctx, UnitsPreferenceKey, p.HouseholdUID); err == nil {
+		_, err = prefs.UpdatePreferences(ctx, UnitsPreferenceKey, p.HouseholdUID, pref)
+		return err
+	}
+	_, err = prefs.CreatePreferences(ctx, pref)
+	return err
+}
+
+// WithRecipeUnits shows recipes' grocery lists in the measurement system
+// of their household, read from prefs.
+func WithRecipeUnits(prefs preferencesDAO) RecipesOption {
+	return func(h *RecipesHandlers) { h.prefs = prefs }
+}
+
+// householdUnits is the system householdUID reads quantities in, or "" when
+// it has none, it can't be read or prefs is nil. Displaying a recipe
+// shouldn't fail over its units.
+func householdUnits(ctx context.Context, prefs preferencesDAO, householdUID *string) measurement.System {
+	if prefs == nil || householdUID == nil || *householdUID == "" {
+		return ""
+	}
+	p, err := loadUnitPreferences(ctx, prefs, *householdUID)
+	if err != nil || p.Validate() != nil {
+		return ""
+	}
+	return p.System
+}
+
+// displayQuantity is q as a household reading sys would see it. Quantities
+// already in sys, counts, and every quantity when sys is empty, are left as
+// they are.
+func displayQuantity(q measurement.Quantity, sys measurement.System) measurement.Quantity {
+	if sys == "" || q.Unit.System == "" || q.Unit.System == sys {
+		return q
+	}
+	return q.ToSystem(sys)
+}
+
+// withUnits adds the recipe's grocery list with its quantities in sys, e.g.
+// "1 1/2 cups rolled oats" as "354.88 ml rolled oats". Lines without an
+// amount to convert are kept as written.
+func withUnits(r recipeResponse, sys measurement.System) recipeResponse {
+	if sys == "" || r.GroceryList == nil {
+		return r
+	}
+	r.Units = sys
+	r.Ingredients = []string{}
+	for _, line := range parseGroceryList(*r.GroceryList) {
+		ingredient := measurement.ParseIngredient(line)
+		if q := ingredient.Quantity; q != nil && ingredient.Name != "" {
+			if shown := displayQuantity(*q, sys); shown != *q {
+				line = strings.TrimSpace(shown.String() + " " + ingredient.Name)
+			}
+		}
+		r.Ingredients = append(r.Ingredients, line)
+	}
+	return r
+}
+
+type UnitPreferenceHandlers struct{ dao preferencesDAO }
+
+func NewUnitPreferences(dao preferencesDAO) http.Handler {
+	h := &UnitPreferenceHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/{household_uid}", h.get)
+	r.Put("/{household_uid}", h.put)
+	return r
+}
+
+func (h *UnitPreferenceHandlers) get(w http.ResponseWriter, r *http.Request) {
+	out, err := loadUnitPreferences(r.Context(), h.dao, chi.URLParam(r, "household_uid"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	encodeResponse(w, r, out)
+}
+
+// put replaces a household's unit preferences.
+func (h *UnitPreferenceHandlers) put(w http.ResponseWriter, r *http.Request) {
+	var p UnitPreferences
+	if json.NewDecoder(r.Body).Decode(&p) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	p.HouseholdUID = chi.URLParam(r, "household_uid")
+	if err := p.Validate(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err := saveUnitPreferences(r.Context(), h.dao, p); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
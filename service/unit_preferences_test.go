@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/measurement"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUnitPreferenceHandlers(t *testing.T) {
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, UnitsPreferenceKey, "house-1").Return(dao.Preferences{}, errors.New("not found"))
+	prefs.On("CreatePreferences", mock.Anything, mock.MatchedBy(func(p dao.Preferences) bool {
+		return p.Key == UnitsPreferenceKey && p.Specifier == "house-1" && p.Data == `{"household_uid":"house-1","system":"metric"}`
+	})).Return(dao.Preferences{}, nil)
+	handler := NewUnitPreferences(prefs)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/house-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"household_uid": "house-1", "system": ""}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/house-1", strings.NewReader(`{"system": "metric"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	prefs.AssertCalled(t, "CreatePreferences", mock.Anything, mock.Anything)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("PUT", "/house-1", strings.NewReader(`{"system": "cubits"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "system must be metric or imperial")
+}
+
+func TestRecipesShownInHouseholdUnits(t *testing.T) {
+	recipe := dao.Recipes{
+		ID:           "r1",
+		Title:        "Flapjacks",
+		HouseholdUID: strPtr("house-1"),
+		GroceryList:  strPtr(`["1 1/2 cups rolled oats", "100 g butter", "2 tbsp golden syrup", "3 eggs", "salt"]`),
+	}
+	mockRecipesDAO := mocks.NewMockrecipesDAO(t)
+	mockRecipesDAO.On("GetRecipes", mock.Anything, "r1").Return(recipe, nil)
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, UnitsPreferenceKey, "house-1").Return(dao.Preferences{Data: `{"system": "metric"}`}, nil)
+
+	rr := httptest.NewRecorder()
+	NewRecipes(mockRecipesDAO, WithRecipeUnits(prefs)).ServeHTTP(rr, httptest.NewRequest("GET", "/r1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// The stored grocery list is returned as it is, next to the converted one.
+	assert.Contains(t, rr.Body.String(), `"grocery_list":"[\"1 1/2 cups rolled oats\"`)
+	assert.Contains(t, rr.Body.String(), `"units":"metric"`)
+	assert.Contains(t, rr.Body.String(), `"ingredients":["354.88 ml rolled oats","100 g butter","29.57 ml golden syrup","3 eggs","salt"]`)
+
+	shown := withUnits(withPhotoURLs(recipe), measurement.Imperial)
+	assert.Equal(t, []string{"1 1/2 cups rolled oats", "3.53 oz butter", "2 tbsp golden syrup", "3 eggs", "salt"}, shown.Ingredients)
+	assert.Nil(t, withUnits(withPhotoURLs(recipe), "").Ingredients)
+}
+
+func TestMCPHandlers_ShoppingListInHouseholdUnits(t *testing.T) {
+	recipes := &MockRecipesDAO{}
+	recipes.On("GetRecipes", mock.Anything, "r1").Return(dao.Recipes{ID: "r1", Title: "Pancakes", GroceryList: strPtr(`["250 ml milk", "250 ml milk", "2 eggs"]`)}, nil)
+	prefs := &MockPreferencesDAO{}
+	prefs.On("GetPreferences", mock.Anything, UnitsPreferenceKey, "house-1").Return(dao.Preferences{Data: `{"system": "imperial"}`}, nil)
+	h := NewMCP(&MockTodoDAO{}, &MockNotesDAO{}, prefs, recipes, &MockUserDAO{}, &MockHouseholdDAO{})
+
+	var body map[string]any
+	decodeToolResult(t, h.callTool(identityContext("user-1", "house-1"), "build_shopping_list", map[string]any{"recipe_ids": "r1"}), &body)
+	items := body["items"].([]any)
+	assert.Equal(t, "2.11 cup", items[0].(map[string]any)["quantity"])
+	assert.Equal(t, "2", items[1].(map[string]any)["quantity"])
+}
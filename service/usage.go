@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+type usageDAO interface {
+	UsageStats(ctx context.Context) (dao.UsageStats, error)
+}
+
+// UsageReport is the size and use of the database, and the optional
+// features the server runs with. It holds counts, never content.
+type UsageReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	dao.UsageStats
+	Features map[string]bool `json:"features"`
+}
+
+type UsageHandlers struct {
+	dao      usageDAO
+	features map[string]bool
+}
+
+// NewUsage lets operators see how large each table is, how often its
+// indexes are used and which features are on, to plan capacity, indexes
+// and retention policies. Callers with an API key are turned away.
+func NewUsage(dao usageDAO, features map[string]bool) http.Handler {
+	h := &UsageHandlers{dao, features}
+	r := chi.NewRouter()
+	r.Use(httpLogger(), operatorsOnly)
+	r.Get("/", h.report)
+	return r
+}
+
+func (h *UsageHandlers) report(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.dao.UsageStats(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(UsageReport{GeneratedAt: time.Now().UTC(), UsageStats: stats, Features: h.features})
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	d := mocks.NewMockusageDAO(t)
+	d.On("UsageStats", mock.Anything).Return(postgres.UsageStats{
+		Tables:  []postgres.TableUsage{{Name: "todos", Rows: 1200, TotalBytes: 524288, IndexScans: 90}},
+		Indexes: []postgres.IndexUsage{{Table: "todos", Name: "idx_todos_due_date", Bytes: 16384}},
+	}, nil).Once()
+	d.On("UsageStats", mock.Anything).Return(postgres.UsageStats{}, errors.New("connection refused"))
+	handler := NewUsage(d, map[string]bool{"email_digests": true, "llm": false})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/", nil)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var report UsageReport
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+	assert.Equal(t, int64(1200), report.Tables[0].Rows)
+	assert.Equal(t, "idx_todos_due_date", report.Indexes[0].Name)
+	assert.Equal(t, map[string]bool{"email_digests": true, "llm": false}, report.Features)
+	assert.False(t, report.GeneratedAt.IsZero())
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, asOperator(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(WithIdentity(req.Context(), Identity{UserUID: "user-1"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
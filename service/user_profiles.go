@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+)
+
+// maxAvatarBytes caps uploaded avatars.
+const maxAvatarBytes = 5 << 20
+
+// avatarThumbnailSizes are generated for every avatar.
+var avatarThumbnailSizes = []thumbnailSize{
+	{"small", 64},
+	{"medium", 256},
+}
+
+type membersDAO interface {
+	ListHouseholdMembers(ctx context.Context, householdUID string) ([]dao.Users, error)
+}
+
+type userAvatarDAO interface {
+	SetUserAvatar(ctx context.Context, userUID string, avatars []dao.UserAvatar) (dao.Users, error)
+	GetUserAvatar(ctx context.Context, userUID, size string) (dao.UserAvatar, error)
+	DeleteUserAvatar(ctx context.Context, userUID string) error
+}
+
+// userResponse is a user as returned by the API, with links to their
+// avatar renditions when they have one.
+type userResponse struct {
+	dao.Users
+	AvatarURLs map[string]string `json:"avatar_urls,omitempty"`
+}
+
+func withAvatarURLs(u dao.Users) userResponse {
+	out := userResponse{Users: u}
+	if u.AvatarUpdatedAt == nil {
+		return out
+	}
+	version := strconv.FormatInt(u.AvatarUpdatedAt.Unix(), 10)
+	out.AvatarURLs = map[string]string{
+		originalPhotoSize: fmt.Sprintf("/users/%s/avatar?v=%s", u.UID, version),
+	}
+	for _, size := range avatarThumbnailSizes {
+		out.AvatarURLs[size.Name] = fmt.Sprintf("/users/%s/avatar?size=%s&v=%s", u.UID, size.Name, version)
+	}
+	return out
+}
+
+// makeUserAvatars decodes an uploaded JPEG, PNG or GIF and returns it
+// together with a JPEG thumbnail for each of avatarThumbnailSizes.
+func makeUserAvatars(data []byte) ([]dao.UserAvatar, error) {
+	renditions, err := makeRenditions(data, avatarThumbnailSizes)
+	if err != nil {
+		return nil, err
+	}
+	avatars := make([]dao.UserAvatar, len(renditions))
+	for i, r := range renditions {
+		avatars[i] = dao.UserAvatar{Size: r.Size, ContentType: r.ContentType, Width: r.Width, Height: r.Height, Data: r.Data}
+	}
+	return avatars, nil
+}
+
+type UserAvatarHandlers struct{ dao userAvatarDAO }
+
+// NewUserAvatars serves users' avatars at /{uid}/avatar. Anyone who can
+// see a user can see their avatar, but only the user, or an operator
+// calling without an API key, can change it.
+func NewUserAvatars(dao userAvatarDAO) http.Handler {
+	h := &UserAvatarHandlers{dao}
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Get("/{uid}/avatar", h.get)
+	r.With(usersOwn).Put("/{uid}/avatar", h.put)
+	r.With(usersOwn).Delete("/{uid}/avatar", h.delete)
+	return r
+}
+
+// usersOwn rejects callers with an API key acting on a user other than
+// themselves.
+func usersOwn(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := IdentityFromContext(r.Context()); ok && id.UserUID != chi.URLParam(r, "uid") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *UserAvatarHandlers) put(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAvatarBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	avatars, err := makeUserAvatars(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	out, err := h.dao.SetUserAvatar(r.Context(), chi.URLParam(r, "uid"), avatars)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(withAvatarURLs(out))
+}
+
+func (h *UserAvatarHandlers) get(w http.ResponseWriter, r *http.Request) {
+	size := r.URL.Query().Get("size")
+	if size == "" {
+		size = originalPhotoSize
+	}
+	avatar, err := h.dao.GetUserAvatar(r.Context(), chi.URLParam(r, "uid"), size)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", avatar.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(avatar.Data)))
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	_, _ = w.Write(avatar.Data)
+}
+
+func (h *UserAvatarHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	if h.dao.DeleteUserAvatar(r.Context(), chi.URLParam(r, "uid")) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateProfileRequest is the part of a user's profile they may change
+// themselves. Omitted fields are left as they are.
+type updateProfileRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Pronouns    *string `json:"pronouns"`
+	// Birthday is a date like 1990-04-17.
+	Birthday *string `json:"birthday"`
+	Phone    *string `json:"phone"`
+}
+
+// toUpdate checks the request and turns it into a user update.
+func (p updateProfileRequest) toUpdate(now time.Time) (dao.UpdateUser, error) {
+	u := dao.UpdateUser{Name: p.Name, Description: p.Description, Pronouns: p.Pronouns}
+	if p.Name != nil && strings.TrimSpace(*p.Name) == "" {
+		return u, errors.New("name must not be empty")
+	}
+	if p.Pronouns != nil && len(*p.Pronouns) > 40 {
+		return u, errors.New("pronouns must be at most 40 characters")
+	}
+	if p.Birthday != nil {
+		birthday, err := time.Parse(time.DateOnly, *p.Birthday)
+		if err != nil {
+			return u, errors.New("birthday must be a date like 1990-04-17")
+		}
+		if birthday.After(now) {
+			return u, errors.New("birthday must not be in the future")
+		}
+		u.Birthday = &birthday
+	}
+	if p.Phone != nil {
+		phone := strings.TrimSpace(*p.Phone)
+		if phone != "" && !validPhone(phone) {
+			return u, errors.New("phone must be a phone number like +44 20 7946 0958")
+		}
+		u.Phone = &phone
+	}
+	return u, nil
+}
+
+// validPhone accepts digits with the punctuation people write phone
+// numbers with, and a leading +, as long as there are 7 to 15 digits.
+func validPhone(s string) bool {
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// birthdayWindow is how far ahead the briefing looks for birthdays.
+const birthdayWindow = 7
+
+// upcomingBirthday is a user whose birthday falls within birthdayWindow
+// days of today.
+type upcomingBirthday struct {
+	UserUID string `json:"user_uid"`
+	Name    string `json:"name"`
+	// Date is this year's, or next year's, birthday.
+	Date time.Time `json:"date"`
+	// Age is the age they turn.
+	Age int `json:"age"`
+	// InDays is 0 for today.
+	InDays int `json:"in_days"`
+}
+
+// upcomingBirthdays returns the birthdays among users in the days days
+// from now's date, soonest first. Birthdays on 29 February fall on 28
+// February in other years.
+func upcomingBirthdays(users []dao.Users, now time.Time, days int) []upcomingBirthday {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	out := []upcomingBirthday{}
+	for _, u := range users {
+		if u.Birthday == nil {
+			continue
+		}
+		on := onInYear(*u.Birthday, today.Year())
+		if on.Before(today) {
+			on = onInYear(*u.Birthday, today.Year()+1)
+		}
+		if in := daysUntil(today, on); in < days {
+			out = append(out, upcomingBirthday{UserUID: u.UID, Name: u.Name, Date: on, Age: on.Year() - u.Birthday.Year(), InDays: in})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b upcomingBirthday) int { return a.InDays - b.InDays })
+	return out
+}
+
+func (h *MeHandlers) update(w http.ResponseWriter, r *http.Request) {
+	var in updateProfileRequest
+	if json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	update, err := in.toUpdate(time.Now())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	id, _ := IdentityFromContext(r.Context())
+	user, err := h.users.UpdateUser(r.Context(), id.UserUID, update)
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(withAvatarURLs(user))
+}
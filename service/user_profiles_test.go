@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAvatarRoutes(t *testing.T) {
+	updated := time.Unix(1755600000, 0)
+	d := mocks.NewMockuserAvatarDAO(t)
+	d.On("SetUserAvatar", mock.Anything, "user-1", mock.MatchedBy(func(avatars []postgres.UserAvatar) bool {
+		return len(avatars) == 3 && avatars[1].Size == "small" && avatars[1].Width == 64
+	})).Return(postgres.Users{UID: "user-1", AvatarUpdatedAt: &updated}, nil)
+	d.On("GetUserAvatar", mock.Anything, "user-1", "medium").Return(postgres.UserAvatar{ContentType: "image/jpeg", Data: []byte("jpeg")}, nil)
+	d.On("DeleteUserAvatar", mock.Anything, "user-1").Return(nil)
+	handler := NewUserAvatars(d)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(identityContext("user-1", "house-1")))
+		return rr
+	}
+
+	rr := serve(httptest.NewRequest("PUT", "/user-1/avatar", bytes.NewReader(testPNG(t, 300, 300))))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, map[string]any{
+		"original": "/users/user-1/avatar?v=1755600000",
+		"small":    "/users/user-1/avatar?size=small&v=1755600000",
+		"medium":   "/users/user-1/avatar?size=medium&v=1755600000",
+	}, out["avatar_urls"])
+
+	rr = serve(httptest.NewRequest("GET", "/user-1/avatar?size=medium", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/jpeg", rr.Header().Get("Content-Type"))
+
+	rr = serve(httptest.NewRequest("PUT", "/user-1/avatar", strings.NewReader("not an image")))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+	// Only the user themselves may change their avatar.
+	rr = serve(httptest.NewRequest("PUT", "/user-2/avatar", bytes.NewReader(testPNG(t, 10, 10))))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serve(httptest.NewRequest("DELETE", "/user-2/avatar", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serve(httptest.NewRequest("DELETE", "/user-1/avatar", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestUpdateProfileRequest(t *testing.T) {
+	now := time.Date(2025, 9, 21, 12, 0, 0, 0, time.UTC)
+	birthday := "1990-04-17"
+	phone := " +44 (20) 7946-0958 "
+	u, err := updateProfileRequest{Pronouns: strPtr("they/them"), Birthday: &birthday, Phone: &phone}.toUpdate(now)
+	require.NoError(t, err)
+	assert.Equal(t, "they/them", *u.Pronouns)
+	assert.Equal(t, time.Date(1990, 4, 17, 0, 0, 0, 0, time.UTC), *u.Birthday)
+	assert.Equal(t, "+44 (20) 7946-0958", *u.Phone)
+	assert.Nil(t, u.Name)
+
+	for req, want := range map[*updateProfileRequest]string{
+		{Name: strPtr(" ")}:              "name must not be empty",
+		{Birthday: strPtr("17/04/1990")}: "birthday must be a date",
+		{Birthday: strPtr("2030-01-01")}: "birthday must not be in the future",
+		{Phone: strPtr("call me")}:       "phone must be a phone number",
+		{Phone: strPtr("12345")}:         "phone must be a phone number",
+		{Phone: strPtr("1+2345678")}:     "phone must be a phone number",
+	} {
+		_, err := req.toUpdate(now)
+		assert.ErrorContains(t, err, want)
+	}
+
+	// An empty phone clears it.
+	u, err = updateProfileRequest{Phone: strPtr("")}.toUpdate(now)
+	require.NoError(t, err)
+	assert.Equal(t, "", *u.Phone)
+}
+
+func TestMeUpdate(t *testing.T) {
+	users := &MockUserDAO{}
+	users.On("UpdateUser", mock.Anything, "user-1", mock.MatchedBy(func(u postgres.UpdateUser) bool {
+		return u.Pronouns != nil && *u.Pronouns == "she/her" && u.Birthday != nil && u.Name == nil
+	})).Return(postgres.Users{UID: "user-1", Name: "Sam", Pronouns: "she/her"}, nil)
+	handler := NewMe(users, &MockTodoDAO{}, &MockNotesDAO{}, &MockPreferencesDAO{})
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(identityContext("user-1", "house-1")))
+		return rr
+	}
+
+	rr := patch(`{"pronouns": "she/her", "birthday": "1990-04-17"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"pronouns":"she/her"`)
+
+	rr = patch(`{"birthday": "April"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "birthday must be a date")
+	users.AssertNumberOfCalls(t, "UpdateUser", 1)
+}
+
+func TestUpcomingBirthdays(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &d
+	}
+	users := []postgres.Users{
+		{UID: "u1", Name: "Sam", Birthday: date("1990-09-25")},
+		{UID: "u2", Name: "Mia", Birthday: date("2018-09-21")},
+		{UID: "u3", Name: "Alex", Birthday: date("1985-10-30")},
+		{UID: "u4", Name: "Jo"},
+		{UID: "u5", Name: "Lee", Birthday: date("1970-09-20")},
+	}
+	now := time.Date(2025, 9, 21, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, []upcomingBirthday{
+		{UserUID: "u2", Name: "Mia", Date: *date("2025-09-21"), Age: 7, InDays: 0},
+		{UserUID: "u1", Name: "Sam", Date: *date("2025-09-25"), Age: 35, InDays: 4},
+	}, upcomingBirthdays(users, now, birthdayWindow))
+
+	// Birthdays early next year come round after New Year, and a leap day
+	// birthday falls on 28 February.
+	users = []postgres.Users{
+		{UID: "u1", Name: "Sam", Birthday: date("2000-01-02")},
+		{UID: "u2", Name: "Mia", Birthday: date("2004-02-29")},
+	}
+	assert.Equal(t, []upcomingBirthday{
+		{UserUID: "u1", Name: "Sam", Date: *date("2026-01-02"), Age: 26, InDays: 3},
+	}, upcomingBirthdays(users, time.Date(2025, 12, 30, 9, 0, 0, 0, time.UTC), birthdayWindow))
+	assert.Equal(t, []upcomingBirthday{
+		{UserUID: "u2", Name: "Mia", Date: *date("2025-02-28"), Age: 21, InDays: 1},
+	}, upcomingBirthdays(users, time.Date(2025, 2, 27, 9, 0, 0, 0, time.UTC), birthdayWindow))
+}
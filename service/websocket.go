@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is the fixed key suffix from RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// Close codes from RFC 6455 section 7.4.1.
+const (
+	wsCloseNormal       = 1000
+	wsCloseProtocol     = 1002
+	wsCloseUnsupported  = 1003
+	wsCloseTooBig       = 1009
+	wsMaxMessage        = 64 << 10
+	wsBearerProtocol    = "bearer."
+	wsDashboardProtocol = "assistant.v1"
+)
+
+var errWebSocketClosed = errors.New("websocket closed")
+
+// wsConn is the server side of an RFC 6455 connection. Reads happen on one
+// goroutine; writes may come from several and are serialized.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	mu   sync.Mutex
+}
+
+// upgradeWebSocket completes the opening handshake and takes over the
+// connection. protocol is echoed back when the client offered it.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, protocol string) (*wsConn, error) {
+	if r.Method != http.MethodGet || !headerHasToken(r.Header, "Connection", "upgrade") ||
+		!headerHasToken(r.Header, "Upgrade", "websocket") {
+		return nil, errors.New("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be taken over")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n"
+	if headerHasToken(r.Header, "Sec-WebSocket-Protocol", protocol) {
+		response += "Sec-WebSocket-Protocol: " + protocol + "\r\n"
+	}
+	if _, err := rw.WriteString(response + "\r\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// headerHasToken reports whether a comma-separated header names token,
+// ignoring case.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage returns the next text message, answering pings and joining
+// fragments along the way. It returns errWebSocketClosed once the client
+// closes the connection.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			_ = c.writeFrame(wsClose, payload)
+			return nil, errWebSocketClosed
+		case wsBinary:
+			c.close(wsCloseUnsupported, "only text messages are supported")
+			return nil, errWebSocketClosed
+		case wsText, wsContinuation:
+			if (opcode == wsText) != (message == nil) {
+				c.close(wsCloseProtocol, "unexpected fragment")
+				return nil, errWebSocketClosed
+			}
+			if len(message)+len(payload) > wsMaxMessage {
+				c.close(wsCloseTooBig, "message too big")
+				return nil, errWebSocketClosed
+			}
+			message = append(append([]byte{}, message...), payload...)
+			if fin {
+				return message, nil
+			}
+		default:
+			c.close(wsCloseProtocol, "unknown opcode")
+			return nil, errWebSocketClosed
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.rw, head[:]); err != nil {
+		return
+	}
+	fin, opcode = head[0]&0x80 != 0, head[0]&0x0F
+	if head[1]&0x80 == 0 {
+		// Clients must mask every frame.
+		c.close(wsCloseProtocol, "frames must be masked")
+		return false, 0, nil, errWebSocketClosed
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxMessage {
+		c.close(wsCloseTooBig, "message too big")
+		return false, 0, nil, errWebSocketClosed
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(c.rw, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.rw, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// writeFrame sends a single unfragmented, unmasked frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	head := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, byte(n))
+	case n <= 0xFFFF:
+		head = append(head, 126)
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head = append(head, 127)
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.rw.Write(head); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// close sends a close frame with code and reason and drops the connection.
+func (c *wsConn) close(code uint16, reason string) {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	_ = c.writeFrame(wsClose, append(payload, reason...))
+	_ = c.conn.Close()
+}
+
+// webSocketAPIKey lets browsers, which can't set headers on a WebSocket,
+// send their API key as a "bearer.<key>" subprotocol.
+func webSocketAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bearerAPIKey(r) == "" {
+			for _, v := range r.Header.Values("Sec-WebSocket-Protocol") {
+				for _, p := range strings.Split(v, ",") {
+					if key, ok := strings.CutPrefix(strings.TrimSpace(p), wsBearerProtocol); ok {
+						r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+					}
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
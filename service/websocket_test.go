@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWSClient is just enough of an RFC 6455 client to drive the server.
+type testWSClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialTestWS(t *testing.T, url string, header http.Header) (*testWSClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	req, _ := http.NewRequest("GET", url+"/ws", nil)
+	req.Header = header
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	require.NoError(t, req.Write(conn))
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	require.NoError(t, err)
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &testWSClient{conn: conn, r: r}, resp
+}
+
+func (c *testWSClient) send(t *testing.T, opcode byte, payload []byte) {
+	t.Helper()
+	frame := []byte{0x80 | opcode}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	var mask [4]byte
+	_, _ = rand.Read(mask[:])
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := c.conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func (c *testWSClient) receive(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	_, err := io.ReadFull(c.r, head[:])
+	require.NoError(t, err)
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(c.r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.r, payload)
+	require.NoError(t, err)
+	return head[0] & 0x0F, payload
+}
+
+func (c *testWSClient) receiveFrame(t *testing.T) wsFrame {
+	t.Helper()
+	opcode, payload := c.receive(t)
+	require.Equal(t, byte(wsText), opcode)
+	var f wsFrame
+	require.NoError(t, json.Unmarshal(payload, &f))
+	return f
+}
+
+func newTestWSServer(t *testing.T, hub *EventHub, householdUID string) *httptest.Server {
+	r := chi.NewRouter()
+	// Stand in for APIKeyAuth, which passes requests that already carry an
+	// identity straight through.
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := Identity{APIKeyUID: "key-1", UserUID: "user-1", HouseholdUID: householdUID}
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+		})
+	})
+	r.Get("/todos", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := IdentityFromContext(r.Context())
+		_ = json.NewEncoder(w).Encode([]map[string]string{{"uid": "t1", "user_uid": id.UserUID, "limit": r.URL.Query().Get("limit")}})
+	})
+	r.Handle("/ws", NewWebSocket(hub, r, nil))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWebSocketDashboard(t *testing.T) {
+	hub := NewEventHub()
+	srv := newTestWSServer(t, hub, "house-1")
+
+	client, resp := dialTestWS(t, srv.URL, http.Header{"Sec-Websocket-Protocol": {"assistant.v1, bearer.ak_test"}})
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+	assert.Equal(t, "assistant.v1", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	// Queries are answered through the REST API as the socket's caller.
+	client.send(t, wsText, []byte(`{"type": "request", "id": "q1", "path": "/todos?limit=5"}`))
+	f := client.receiveFrame(t)
+	assert.Equal(t, "response", f.Type)
+	assert.Equal(t, "q1", f.ID)
+	assert.Equal(t, http.StatusOK, f.Status)
+	assert.JSONEq(t, `[{"uid": "t1", "user_uid": "user-1", "limit": "5"}]`, string(f.Body))
+
+	for _, path := range []string{"/ws", "/mcp?x=1", "todos", "/mcp#x", "/mcp?", "//mcp", "/todos/../mcp", "/%6dcp", "/todos#x", "http://example.com/todos"} {
+		client.send(t, wsText, []byte(`{"type": "request", "id": "q2", "path": "`+path+`"}`))
+		assert.Equal(t, "error", client.receiveFrame(t).Type, path)
+	}
+
+	// Change events for the household are pushed; others are not.
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "created", ID: "t2", HouseholdUID: "house-2"})
+	hub.Publish(ChangeEvent{Entity: "todos", Action: "created", ID: "t3", HouseholdUID: "house-1"})
+	f = client.receiveFrame(t)
+	assert.Equal(t, "event", f.Type)
+	assert.Equal(t, "t3", f.Event.ID)
+
+	client.send(t, wsPing, []byte("hi"))
+	opcode, payload := client.receive(t)
+	assert.Equal(t, byte(wsPong), opcode)
+	assert.Equal(t, "hi", string(payload))
+
+	client.send(t, wsClose, binary.BigEndian.AppendUint16(nil, wsCloseNormal))
+	opcode, _ = client.receive(t)
+	assert.Equal(t, byte(wsClose), opcode)
+}
+
+func TestWebSocketRequiresHousehold(t *testing.T) {
+	srv := newTestWSServer(t, NewEventHub(), "")
+	_, resp := dialTestWS(t, srv.URL, http.Header{})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestWebSocketAPIKeyFromSubprotocol(t *testing.T) {
+	var got string
+	handler := webSocketAPIKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = bearerAPIKey(r) }))
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Sec-WebSocket-Protocol", "assistant.v1, bearer.ak_secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "ak_secret", got)
+}
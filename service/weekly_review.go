@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	dao "github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/notify"
+)
+
+const (
+	// WeeklyReviewPreferenceKey is the preference key, specified by
+	// household UID, holding a household's WeeklyReviewSettings as JSON.
+	WeeklyReviewPreferenceKey = "weekly_review"
+	// WeeklyReviewNoteTag marks the notes WeeklyReviews writes.
+	WeeklyReviewNoteTag = "weekly-review"
+	// weeklyReviewSectionLimit caps the items listed in each section.
+	weeklyReviewSectionLimit = 50
+)
+
+type weeklyReviewDAO interface {
+	ListTodos(ctx context.Context, options dao.ListOptions) ([]dao.Todo, error)
+	ListNotes(ctx context.Context, options dao.ListOptions) ([]dao.Notes, error)
+	CreateNotes(ctx context.Context, n dao.Notes) (dao.Notes, error)
+	ListPreferences(ctx context.Context, options dao.ListOptions) ([]dao.Preferences, error)
+}
+
+// HouseholdNotifier tells a household's members about something, honouring
+// their notification preferences for category.
+type HouseholdNotifier interface {
+	NotifyHousehold(ctx context.Context, householdUID, category string, p notify.Push) error
+}
+
+// WeeklyReviewSettings opt a household into the scheduled weekly review.
+// With Notify set, members are told when it is ready.
+type WeeklyReviewSettings struct {
+	Enabled bool `json:"enabled"`
+	Notify  bool `json:"notify"`
+}
+
+// WeeklyReviews writes a household note looking back over the week: the
+// todos done, the ones that slipped past their due date and the notes
+// added. Households opt in through their WeeklyReviewSettings.
+type WeeklyReviews struct {
+	dao      weeklyReviewDAO
+	notifier HouseholdNotifier
+	weekday  time.Weekday
+	hour     int
+}
+
+// NewWeeklyReviews writes reviews on weekday at hour o'clock UTC. notifier
+// may be nil, in which case nobody is told.
+func NewWeeklyReviews(d weeklyReviewDAO, notifier HouseholdNotifier, weekday time.Weekday, hour int) *WeeklyReviews {
+	return &WeeklyReviews{dao: d, notifier: notifier, weekday: weekday, hour: hour}
+}
+
+// Job writes reviews weekly at the configured day and hour. A failed run
+// isn't retried, as households whose reviews were written would get
+// another.
+func (w *WeeklyReviews) Job() Job {
+	return Job{
+		Name:     "weekly_reviews",
+		Schedule: dailyAt(w.hour, w.weekday),
+		Run: func(ctx context.Context, run dao.JobRun) error {
+			return w.WriteDue(ctx, run.RunAt)
+		},
+	}
+}
+
+// WriteDue writes the review of every household that opted in. Failures
+// for one household are logged and don't stop the others; WriteDue then
+// reports how many failed.
+func (w *WeeklyReviews) WriteDue(ctx context.Context, now time.Time) error {
+	failed := 0
+	for offset := 0; ; offset += digestPageSize {
+		subs, err := w.dao.ListPreferences(ctx, dao.ListOptions{
+			Limit:       digestPageSize,
+			Offset:      offset,
+			SortBy:      "specifier",
+			SortDir:     "ASC",
+			WhereClause: "WHERE key = $1",
+			WhereArgs:   []any{WeeklyReviewPreferenceKey},
+		})
+		if err != nil {
+			return fmt.Errorf("listing weekly review subscriptions: %w", err)
+		}
+		for _, sub := range subs {
+			var settings WeeklyReviewSettings
+			if err := json.Unmarshal([]byte(sub.Data), &settings); err != nil {
+				slog.Error("Invalid weekly review settings", "household_uid", sub.Specifier, "error", err)
+				continue
+			}
+			if !settings.Enabled {
+				continue
+			}
+			if _, err := w.Write(ctx, sub.Specifier, now, settings.Notify); err != nil {
+				slog.Error("Failed to write weekly review", "household_uid", sub.Specifier, "error", err)
+				failed++
+			}
+		}
+		if len(subs) < digestPageSize {
+			break
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d weekly reviews failed to write", failed)
+	}
+	return nil
+}
+
+// Write saves the review of the week up to now as a household note and, if
+// tell is set, tells the household it is ready. A failed notification is
+// logged; the note is still returned.
+func (w *WeeklyReviews) Write(ctx context.Context, householdUID string, now time.Time, tell bool) (dao.Notes, error) {
+	note, err := w.build(ctx, householdUID, now)
+	if err != nil {
+		return dao.Notes{}, err
+	}
+	created, err := w.dao.CreateNotes(ctx, note)
+	if err != nil {
+		return dao.Notes{}, err
+	}
+	if tell && w.notifier != nil {
+		err := w.notifier.NotifyHousehold(ctx, householdUID, CategoryWeeklyReview, weeklyReviewPush(created))
+		if err != nil {
+			slog.Error("Failed to send weekly review notification", "household_uid", householdUID, "error", err)
+		}
+	}
+	return created, nil
+}
+
+func weeklyReviewPush(n dao.Notes) notify.Push {
+	return notify.Push{
+		Title: "Your weekly review is ready",
+		Body:  n.Key,
+		Data:  map[string]string{"note_id": n.ID},
+	}
+}
+
+// build writes the review note for the seven days before now.
+func (w *WeeklyReviews) build(ctx context.Context, householdUID string, now time.Time) (dao.Notes, error) {
+	from := now.Add(-7 * 24 * time.Hour)
+	listTodos := func(where, sortBy string, args ...any) ([]dao.Todo, error) {
+		return w.dao.ListTodos(ctx, dao.ListOptions{
+			Limit:       weeklyReviewSectionLimit,
+			SortBy:      sortBy,
+			SortDir:     "ASC",
+			WhereClause: where,
+			WhereArgs:   append([]any{householdUID}, args...),
+		})
+	}
+	done, err := listTodos("WHERE household_uid = $1 AND marked_complete >= $2", "marked_complete", from)
+	if err != nil {
+		return dao.Notes{}, fmt.Errorf("completed todos: %w", err)
+	}
+	slipped, err := listTodos("WHERE household_uid = $1 AND marked_complete IS NULL AND due_date >= $2 AND due_date < $3", "due_date", from, now)
+	if err != nil {
+		return dao.Notes{}, fmt.Errorf("slipped todos: %w", err)
+	}
+	added, err := w.dao.ListNotes(ctx, dao.ListOptions{
+		Limit:       weeklyReviewSectionLimit,
+		SortBy:      "created_at",
+		SortDir:     "ASC",
+		WhereClause: "WHERE household_uid = $1 AND visibility <> $2 AND created_at >= $3",
+		WhereArgs:   []any{householdUID, dao.NoteVisibilityPrivate, from},
+	})
+	if err != nil {
+		return dao.Notes{}, fmt.Errorf("added notes: %w", err)
+	}
+	// Notes the server wrote itself aren't news.
+	added = slices.DeleteFunc(added, func(n dao.Notes) bool {
+		return slices.Contains(n.Tags, WeeklyReviewNoteTag) || slices.Contains(n.Tags, DigestNoteTag)
+	})
+
+	var b strings.Builder
+	section := func(title string, n int, line func(i int) string) {
+		if n == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", title, n)
+		for i := range n {
+			fmt.Fprintf(&b, "- %s\n", line(i))
+		}
+		b.WriteString("\n")
+	}
+	section("Done", len(done), func(i int) string { return done[i].Title })
+	section("Slipped", len(slipped), func(i int) string {
+		return slipped[i].Title + " (was due " + slipped[i].DueDate.Format("Mon 2 Jan") + ")"
+	})
+	section("Notes added", len(added), func(i int) string { return added[i].Key })
+	if b.Len() == 0 {
+		b.WriteString("A quiet week: nothing was done, slipped or noted.\n")
+	}
+
+	return dao.Notes{
+		Key:          fmt.Sprintf("Weekly review: %s to %s", from.Format("2 Jan"), now.Format("2 Jan 2006")),
+		HouseholdUID: &householdUID,
+		Data:         strings.TrimSuffix(b.String(), "\n"),
+		Tags:         []string{WeeklyReviewNoteTag},
+		Visibility:   dao.NoteVisibilityHousehold,
+	}, nil
+}
+
+// NewWeeklyReviewHandler serves POST /{household_uid}, which writes a
+// household's review of the past week right away; ?notify=true also tells
+// the household it is ready.
+func NewWeeklyReviewHandler(reviews *WeeklyReviews) http.Handler {
+	r := chi.NewRouter()
+	r.Use(httpLogger())
+	r.Post("/{household_uid}", func(w http.ResponseWriter, r *http.Request) {
+		out, err := reviews.Write(r.Context(), chi.URLParam(r, "household_uid"), time.Now(), r.URL.Query().Get("notify") == "true")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	})
+	return r
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pbdeuchler/assistant-server/dao/postgres"
+	"github.com/pbdeuchler/assistant-server/mocks"
+	"github.com/pbdeuchler/assistant-server/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHouseholdNotifier struct{ sent []notify.Push }
+
+func (f *fakeHouseholdNotifier) NotifyHousehold(_ context.Context, _, category string, p notify.Push) error {
+	if category == CategoryWeeklyReview {
+		f.sent = append(f.sent, p)
+	}
+	return nil
+}
+
+func TestWeeklyReviewsWrite(t *testing.T) {
+	now := time.Date(2025, 8, 17, 18, 0, 0, 0, time.UTC)
+	from := now.AddDate(0, 0, -7)
+	due := time.Date(2025, 8, 14, 9, 0, 0, 0, time.UTC)
+	d := mocks.NewMockweeklyReviewDAO(t)
+	d.On("ListTodos", mock.Anything, mock.MatchedBy(func(o postgres.ListOptions) bool { return o.SortBy == "marked_complete" })).
+		Return([]postgres.Todo{{Title: "Fix boiler"}, {Title: "Pay nursery"}}, nil)
+	d.On("ListTodos", mock.Anything, postgres.ListOptions{
+		Limit:       weeklyReviewSectionLimit,
+		SortBy:      "due_date",
+		SortDir:     "ASC",
+		WhereClause: "WHERE household_uid = $1 AND marked_complete IS NULL AND due_date >= $2 AND due_date < $3",
+		WhereArgs:   []any{"house-1", from, now},
+	}).Return([]postgres.Todo{{Title: "Renew passport", DueDate: &due}}, nil)
+	d.On("ListNotes", mock.Anything, mock.Anything).
+		Return([]postgres.Notes{{Key: "Plumber's number"}, {Key: "Digest of 3 notes", Tags: []string{DigestNoteTag}}}, nil)
+	d.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool {
+		return n.Key == "Weekly review: 10 Aug to 17 Aug 2025" && *n.HouseholdUID == "house-1" &&
+			n.Data == "Done (2)\n- Fix boiler\n- Pay nursery\n\nSlipped (1)\n- Renew passport (was due Thu 14 Aug)\n\nNotes added (1)\n- Plumber's number\n"
+	})).Return(postgres.Notes{ID: "n1", Key: "Weekly review: 10 Aug to 17 Aug 2025"}, nil)
+	notifier := &fakeHouseholdNotifier{}
+
+	out, err := NewWeeklyReviews(d, notifier, time.Sunday, 18).Write(t.Context(), "house-1", now, true)
+	require.NoError(t, err)
+	assert.Equal(t, "n1", out.ID)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "n1", notifier.sent[0].Data["note_id"])
+}
+
+func TestWeeklyReviewsWriteDue(t *testing.T) {
+	now := time.Date(2025, 8, 17, 18, 0, 0, 0, time.UTC)
+	d := mocks.NewMockweeklyReviewDAO(t)
+	d.On("ListPreferences", mock.Anything, mock.Anything).Return([]postgres.Preferences{
+		{Key: WeeklyReviewPreferenceKey, Specifier: "house-1", Data: `{"enabled": true}`},
+		{Key: WeeklyReviewPreferenceKey, Specifier: "house-2", Data: `{"enabled": false, "notify": true}`},
+		{Key: WeeklyReviewPreferenceKey, Specifier: "house-3", Data: `not json`},
+	}, nil)
+	d.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	d.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	d.On("CreateNotes", mock.Anything, mock.MatchedBy(func(n postgres.Notes) bool {
+		return *n.HouseholdUID == "house-1" && n.Data == "A quiet week: nothing was done, slipped or noted."
+	})).Return(postgres.Notes{ID: "n1"}, nil)
+	notifier := &fakeHouseholdNotifier{}
+
+	NewWeeklyReviews(d, notifier, time.Sunday, 18).WriteDue(t.Context(), now)
+	d.AssertNumberOfCalls(t, "CreateNotes", 1)
+	assert.Empty(t, notifier.sent)
+}
+
+func TestWeeklyReviewHandler(t *testing.T) {
+	d := mocks.NewMockweeklyReviewDAO(t)
+	d.On("ListTodos", mock.Anything, mock.Anything).Return([]postgres.Todo{}, nil)
+	d.On("ListNotes", mock.Anything, mock.Anything).Return([]postgres.Notes{}, nil)
+	d.On("CreateNotes", mock.Anything, mock.Anything).Return(postgres.Notes{ID: "n1"}, nil)
+	notifier := &fakeHouseholdNotifier{}
+	handler := NewWeeklyReviewHandler(NewWeeklyReviews(d, notifier, time.Sunday, 18))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/house-1?notify=true", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"n1"`)
+	assert.Len(t, notifier.sent, 1)
+}
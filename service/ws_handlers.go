@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// wsPingInterval keeps idle dashboard connections alive through proxies;
+	// a client that misses two pings is dropped.
+	wsPingInterval = 30 * time.Second
+	wsReadTimeout  = 2*wsPingInterval + 10*time.Second
+)
+
+// wsUnqueryable are the path prefixes a socket query may not reach: the
+// socket itself, the long-lived MCP stream and the OAuth redirects.
+var wsUnqueryable = []string{"/ws", "/mcp", "/oauth"}
+
+// wsFrame is every message on a dashboard socket. The server pushes
+// {"type":"event"} frames; clients send {"type":"request","id":..,"path":..}
+// and get back a {"type":"response"} frame with the same id.
+type wsFrame struct {
+	Type   string          `json:"type"`
+	ID     string          `json:"id,omitempty"`
+	Path   string          `json:"path,omitempty"`
+	Status int             `json:"status,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Event  *ChangeEvent    `json:"event,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type WebSocketHandlers struct {
+	hub *EventHub
+	api http.Handler
+}
+
+// NewWebSocket serves a household dashboard socket. It requires an API key
+// bound to a household: the household decides which change events are
+// pushed, and request frames are answered by GETs against api made as the
+// key.
+func NewWebSocket(hub *EventHub, api http.Handler, keys apiKeyDAO) http.Handler {
+	h := &WebSocketHandlers{hub: hub, api: api}
+	return webSocketAPIKey(APIKeyAuth(keys, true)(http.HandlerFunc(h.serve)))
+}
+
+func (h *WebSocketHandlers) serve(w http.ResponseWriter, r *http.Request) {
+	id, _ := IdentityFromContext(r.Context())
+	if id.HouseholdUID == "" {
+		http.Error(w, "API key is not bound to a household", http.StatusForbidden)
+		return
+	}
+	conn, err := upgradeWebSocket(w, r, wsDashboardProtocol)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.conn.Close()
+
+	events, unsubscribe := h.hub.Subscribe(id.HouseholdUID)
+	defer unsubscribe()
+	done := make(chan struct{})
+	defer close(done)
+	go h.push(conn, events, done)
+
+	for {
+		_ = conn.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		message, err := conn.readMessage()
+		if err != nil {
+			if !errors.Is(err, errWebSocketClosed) {
+				slog.Debug("Dashboard socket read failed", slog.String("error", err.Error()))
+			}
+			return
+		}
+		if err := h.writeFrame(conn, h.answer(r, message)); err != nil {
+			return
+		}
+	}
+}
+
+// push sends change events and keepalive pings until done is closed.
+func (h *WebSocketHandlers) push(conn *wsConn, events <-chan ChangeEvent, done <-chan struct{}) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-events:
+			if h.writeFrame(conn, wsFrame{Type: "event", Event: &e}) != nil {
+				return
+			}
+		case <-ping.C:
+			if conn.writeFrame(wsPing, nil) != nil {
+				return
+			}
+		}
+	}
+}
+
+// answer runs a request frame as a GET against the REST API with the
+// socket's identity and wraps the result in a response frame.
+func (h *WebSocketHandlers) answer(r *http.Request, message []byte) wsFrame {
+	var req wsFrame
+	if err := json.Unmarshal(message, &req); err != nil || req.Type != "request" {
+		return wsFrame{Type: "error", Error: `expected {"type":"request","id":...,"path":...}`}
+	}
+	out := wsFrame{Type: "response", ID: req.ID}
+	target, ok := wsQueryTarget(req.Path)
+	if !ok {
+		out.Type, out.Error = "error", "path must be a REST API path"
+		return out
+	}
+	// Drop the socket's own routing state so api routes the query afresh.
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, nil)
+	q, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		out.Type, out.Error = "error", err.Error()
+		return out
+	}
+	rec := &wsResponse{header: http.Header{}, status: http.StatusOK}
+	h.api.ServeHTTP(rec, q)
+	out.Status = rec.status
+	body := bytes.TrimSpace(rec.body.Bytes())
+	if json.Valid(body) {
+		out.Body = body
+	} else if len(body) > 0 {
+		out.Body, _ = json.Marshal(string(body))
+	}
+	return out
+}
+
+// wsQueryTarget returns the request target a socket query for raw is routed
+// to: its cleaned path and its query. Absolute URLs, fragments, paths that
+// aren't rooted and paths that clean to one of wsUnqueryable are refused,
+// so what is checked is what gets routed.
+func wsQueryTarget(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Opaque != "" || strings.Contains(raw, "#") || !strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	clean := path.Clean(u.Path)
+	for _, prefix := range wsUnqueryable {
+		if clean == prefix || strings.HasPrefix(clean, prefix+"/") {
+			return "", false
+		}
+	}
+	target := &url.URL{Path: clean, RawQuery: u.RawQuery}
+	return target.RequestURI(), true
+}
+
+func (h *WebSocketHandlers) writeFrame(conn *wsConn, f wsFrame) error {
+	payload, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return conn.writeFrame(wsText, payload)
+}
+
+// wsResponse collects a REST response for a socket query.
+type wsResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *wsResponse) Header() http.Header         { return w.header }
+func (w *wsResponse) WriteHeader(status int)      { w.status = status }
+func (w *wsResponse) Write(p []byte) (int, error) { return w.body.Write(p) }